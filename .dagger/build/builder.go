@@ -168,7 +168,9 @@ func (build *Builder) Engine(ctx context.Context) (*dagger.Container, error) {
 				Branch: consts.AlpineVersion,
 				Packages: []string{
 					// for Buildkit
-					"git", "openssh-client", "pigz", "xz",
+					"git", "git-lfs", "openssh-client", "pigz", "xz",
+					// for git signature verification
+					"gnupg",
 					// for CNI
 					"dnsmasq", "iptables", "ip6tables", "iptables-legacy",
 				},
@@ -192,7 +194,7 @@ func (build *Builder) Engine(ctx context.Context) (*dagger.Container, error) {
 			WithExec([]string{"apt-get", "update"}).
 			WithExec([]string{
 				"apt-get", "install", "-y",
-				"iptables", "git", "dnsmasq-base", "network-manager",
+				"iptables", "git", "git-lfs", "dnsmasq-base", "network-manager",
 				"gpg", "curl",
 			}).
 			WithExec([]string{
@@ -213,7 +215,9 @@ func (build *Builder) Engine(ctx context.Context) (*dagger.Container, error) {
 	case "wolfi":
 		pkgs := []string{
 			// for Buildkit
-			"git", "openssh-client", "pigz", "xz",
+			"git", "git-lfs", "openssh-client", "pigz", "xz",
+			// for git signature verification
+			"gnupg", "openssh-keygen",
 			// for CNI
 			"iptables", "ip6tables", "dnsmasq",
 		}
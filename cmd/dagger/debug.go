@@ -1,16 +1,85 @@
 package main
 
 import (
+	"context"
 	"expvar"
+	"fmt"
 	"log/slog"
 	"net"
 	"net/http"
 	"net/http/pprof"
 	"runtime"
 
+	"github.com/spf13/cobra"
 	"golang.org/x/net/trace"
+
+	"github.com/dagger/dagger/engine/client"
 )
 
+var explainMissPreviousDigest string
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Debug the execution of pipelines",
+}
+
+var debugExplainMissCmd = &cobra.Command{
+	Use:   "explain-miss [options] <digest>",
+	Short: "Explain why a call missed the cache",
+	Long: `Explain why a call missed the cache, by comparing it to the same call in a previous run.
+
+The digest of a call is recorded in its span, as the "dagger.io/dag.digest"
+attribute. Calls are looked up in the telemetry the engine keeps of its recent
+clients. By default, the call is compared to the most recent call of the same
+fields in an earlier run.
+
+The first difference is reported, in the order the calls are evaluated: the
+objects that calls are chained from first, then arguments, then content.
+`,
+	Example: "dagger debug explain-miss xxh3:3a0b1c2d3e4f5a6b",
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		return withEngine(ctx, client.Params{}, func(ctx context.Context, engineClient *client.Client) error {
+			var res struct {
+				ExplainCacheMiss struct {
+					Message        string
+					Digest         string
+					Trace          string
+					PreviousDigest string
+					PreviousTrace  string
+				}
+			}
+			err := engineClient.Do(ctx, `query ExplainCacheMiss($digest: String!, $previousDigest: String!) {
+				explainCacheMiss(digest: $digest, previousDigest: $previousDigest) {
+					message
+					digest
+					trace
+					previousDigest
+					previousTrace
+				}
+			}`, "ExplainCacheMiss", map[string]any{
+				"digest":         args[0],
+				"previousDigest": explainMissPreviousDigest,
+			}, &res)
+			if err != nil {
+				return err
+			}
+			explanation := res.ExplainCacheMiss
+			fmt.Fprintln(cmd.OutOrStdout(), explanation.Message)
+			fmt.Fprintln(cmd.OutOrStdout())
+			fmt.Fprintf(cmd.OutOrStdout(), "current:  %s (trace %s)\n", explanation.Digest, explanation.Trace)
+			fmt.Fprintf(cmd.OutOrStdout(), "previous: %s (trace %s)\n", explanation.PreviousDigest, explanation.PreviousTrace)
+			return nil
+		})
+	},
+}
+
+func init() {
+	debugExplainMissCmd.Flags().StringVar(&explainMissPreviousDigest, "previous", "", "Digest of the call to compare to")
+	debugCmd.AddCommand(debugExplainMissCmd)
+}
+
 func setupDebugHandlers(addr string) error {
 	m := http.NewServeMux()
 	m.Handle("/debug/vars", expvar.Handler())
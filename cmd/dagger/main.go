@@ -64,6 +64,8 @@ var (
 	dotFocusField     string
 	dotShowInternal   bool
 
+	reportSpecs []string
+
 	stdoutIsTTY = isatty.IsTerminal(os.Stdout.Fd())
 	stderrIsTTY = isatty.IsTerminal(os.Stderr.Fd())
 
@@ -97,6 +99,8 @@ func init() {
 		sessionCmd(),
 		newGenCmd(),
 		shellCmd,
+		debugCmd,
+		replayCmd,
 	)
 
 	rootCmd.AddGroup(moduleGroup)
@@ -217,6 +221,25 @@ func checkForUpdates(ctx context.Context, w io.Writer) {
 	})
 }
 
+// installReportFlags installs the --report flag, for commands that write
+// reports of their results with setFrontendReports.
+func installReportFlags(flags *pflag.FlagSet) {
+	flags.StringArrayVar(&reportSpecs, "report", nil, "Write a report of the results to a file after execution, as format=path where format is junit or jsonl (can be repeated)")
+}
+
+// setFrontendReports configures the frontend to write the reports requested
+// with --report.
+func setFrontendReports() error {
+	for _, spec := range reportSpecs {
+		report, err := dagui.ParseReport(spec)
+		if err != nil {
+			return err
+		}
+		opts.Reports = append(opts.Reports, report)
+	}
+	return nil
+}
+
 func installGlobalFlags(flags *pflag.FlagSet) {
 	flags.StringVar(&workdir, "workdir", ".", "Change the working directory")
 	flags.CountVarP(&verbose, "verbose", "v", "Increase verbosity (use -vv or -vvv for more)")
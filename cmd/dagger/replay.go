@@ -0,0 +1,227 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+
+	"dagger.io/dagger/telemetry"
+	"github.com/dagger/dagger/dagql/dagui"
+	"github.com/dagger/dagger/engine/clientdb"
+	"github.com/dagger/dagger/engine/distconsts"
+	enginetel "github.com/dagger/dagger/engine/telemetry"
+)
+
+var replayDBDir string
+
+var replayCmd = &cobra.Command{
+	Use:   "replay [options] <trace-id|last|path>",
+	Short: "Replay the telemetry of a past run",
+	Long: `Replay the telemetry of a past run, without connecting to an engine.
+
+The engine records the spans, logs and metrics of each client in a database in
+its state directory, and keeps it for an hour after the client disconnects.
+The run with the given trace ID, or the most recent run with "last", is looked
+up in the databases of the --db-dir directory. A path to a database file, e.g.
+one copied out of an engine for a bug report, is replayed as is.
+
+The run is rendered like it was live, and the interactive frontend stays open
+to navigate it until it is quit.
+`,
+	Example: `dagger replay last
+dagger replay 4bf92f3577b34da6a3ce929d0e0e4736
+dagger replay --db-dir ./clientdbs last
+dagger replay ./clientdbs/4xq2v9pqrqe3fcq0jr5izjcb6.db`,
+	Args: cobra.ExactArgs(1),
+	RunE: Replay,
+}
+
+func init() {
+	replayCmd.Flags().StringVar(&replayDBDir, "db-dir",
+		filepath.Join(distconsts.EngineDefaultStateDir, "worker", "clientdbs"),
+		"Directory of the client databases recorded by the engine")
+}
+
+func Replay(cmd *cobra.Command, args []string) error {
+	ctx := cmd.Context()
+
+	db, traceID, err := openReplayDB(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	// keep the TUI open to navigate the run once it's replayed
+	opts.NoExit = true
+	return Frontend.Run(ctx, opts, func(ctx context.Context) error {
+		if err := replayTrace(ctx, db, traceID, Frontend); err != nil {
+			return err
+		}
+		// signal the end of the telemetry to the frontend
+		return Frontend.SpanExporter().Shutdown(ctx)
+	})
+}
+
+// openReplayDB opens the database that recorded a trace, and returns the ID of
+// the trace to replay.
+func openReplayDB(ctx context.Context, arg string) (*sql.DB, string, error) {
+	if _, err := trace.TraceIDFromHex(arg); err != nil && arg != "last" {
+		db, err := clientdb.OpenFile(arg)
+		if err != nil {
+			return nil, "", fmt.Errorf("open %s: %w", arg, err)
+		}
+		traceID, err := clientdb.New(db).SelectLastTraceID(ctx)
+		if err != nil {
+			db.Close()
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, "", fmt.Errorf("%s recorded no trace", arg)
+			}
+			return nil, "", fmt.Errorf("select trace: %w", err)
+		}
+		return db, traceID, nil
+	}
+
+	dbs := clientdb.NewDBs(replayDBDir)
+	traceID := arg
+	if traceID == "last" {
+		var err error
+		traceID, err = dbs.LastTrace(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+	clientID, err := dbs.FindTrace(ctx, traceID)
+	if err != nil {
+		return nil, "", err
+	}
+	db, err := dbs.Open(clientID)
+	if err != nil {
+		return nil, "", err
+	}
+	return db, traceID, nil
+}
+
+// replayTarget receives the replayed telemetry, e.g. a Frontend.
+type replayTarget interface {
+	SetPrimary(spanID dagui.SpanID)
+	SpanExporter() sdktrace.SpanExporter
+	LogExporter() sdklog.Exporter
+	MetricExporter() sdkmetric.Exporter
+}
+
+// replayTrace exports the spans, logs and metrics of a trace recorded in a
+// client database, in the order they were recorded, and sets the root span of
+// the trace as the primary span.
+func replayTrace(ctx context.Context, db *sql.DB, traceID string, target replayTarget) error {
+	q := clientdb.New(db)
+
+	var spans []sdktrace.ReadOnlySpan
+	for since := int64(0); ; {
+		batch, err := q.SelectSpansSince(ctx, clientdb.SelectSpansSinceParams{
+			ID:    since,
+			Limit: replayBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("select spans: %w", err)
+		}
+		if len(batch) == 0 {
+			break
+		}
+		for _, span := range batch {
+			if span.TraceID == traceID {
+				spans = append(spans, span.ReadOnly())
+			}
+		}
+		since = batch[len(batch)-1].ID
+	}
+
+	// set the primary span before exporting logs, so its output is kept
+	target.SetPrimary(replayRootSpan(spans))
+
+	spanExp := target.SpanExporter()
+	for i := 0; i < len(spans); i += replayBatchSize {
+		if err := spanExp.ExportSpans(ctx, spans[i:min(i+replayBatchSize, len(spans))]); err != nil {
+			return fmt.Errorf("export spans: %w", err)
+		}
+	}
+
+	logExp := target.LogExporter()
+	for since := int64(0); ; {
+		batch, err := q.SelectLogsSince(ctx, clientdb.SelectLogsSinceParams{
+			ID:    since,
+			Limit: replayBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("select logs: %w", err)
+		}
+		if len(batch) == 0 {
+			break
+		}
+		since = batch[len(batch)-1].ID
+		logs := batch[:0]
+		for _, log := range batch {
+			if log.TraceID.String == traceID {
+				logs = append(logs, log)
+			}
+		}
+		if err := telemetry.ReexportLogsFromPB(ctx, logExp, &collogspb.ExportLogsServiceRequest{
+			ResourceLogs: clientdb.LogsToPB(logs),
+		}); err != nil {
+			return fmt.Errorf("export logs: %w", err)
+		}
+	}
+
+	metricExp := target.MetricExporter()
+	for since := int64(0); ; {
+		batch, err := q.SelectMetricsSince(ctx, clientdb.SelectMetricsSinceParams{
+			ID:    since,
+			Limit: replayBatchSize,
+		})
+		if err != nil {
+			return fmt.Errorf("select metrics: %w", err)
+		}
+		if len(batch) == 0 {
+			break
+		}
+		since = batch[len(batch)-1].ID
+		if err := enginetel.ReexportMetricsFromPB(ctx, []sdkmetric.Exporter{metricExp}, &colmetricspb.ExportMetricsServiceRequest{
+			ResourceMetrics: clientdb.MetricsToPB(batch),
+		}); err != nil {
+			return fmt.Errorf("export metrics: %w", err)
+		}
+	}
+
+	return nil
+}
+
+const replayBatchSize = 1000
+
+// replayRootSpan returns the root span of a trace, or the span it was started
+// from if it wasn't recorded, e.g. the span of the CLI command that started
+// the run.
+func replayRootSpan(spans []sdktrace.ReadOnlySpan) dagui.SpanID {
+	recorded := make(map[trace.SpanID]bool, len(spans))
+	for _, span := range spans {
+		recorded[span.SpanContext().SpanID()] = true
+	}
+	for _, span := range spans {
+		parent := span.Parent().SpanID()
+		if !parent.IsValid() {
+			return dagui.SpanID{SpanID: span.SpanContext().SpanID()}
+		}
+		if !recorded[parent] {
+			return dagui.SpanID{SpanID: parent}
+		}
+	}
+	return dagui.SpanID{}
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	otlpcommonv1 "go.opentelemetry.io/proto/otlp/common/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/dagger/dagger/dagql/dagui"
+	"github.com/dagger/dagger/engine/clientdb"
+)
+
+const (
+	replayTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	replayCLISpan = "00f067aa0ba902b7"
+)
+
+func TestReplay(t *testing.T) {
+	ctx := context.Background()
+
+	dir := t.TempDir()
+	dbs := clientdb.NewDBs(dir)
+	now := time.Now()
+
+	// the main client records the spans of its nested client too
+	recordReplayClient(t, dbs, "main", replayTraceID, now.Add(-time.Minute),
+		replaySpan{"0000000000000001", replayCLISpan, "build"},
+		replaySpan{"0000000000000002", "0000000000000001", "withExec"},
+		replaySpan{"0000000000000003", replayCLISpan, "test"})
+	recordReplayClient(t, dbs, "nested", replayTraceID, now.Add(-2*time.Minute),
+		replaySpan{"0000000000000002", "0000000000000001", "withExec"})
+	recordReplayClient(t, dbs, "older", "0af7651916cd43dd8448eb211c80319c", now.Add(-time.Hour),
+		replaySpan{"0000000000000009", "", "old"})
+
+	replayDBDir = dir
+	t.Cleanup(func() { replayDBDir = "" })
+
+	for _, arg := range []string{"last", replayTraceID, filepath.Join(dir, "main.db")} {
+		t.Run(arg, func(t *testing.T) {
+			db, traceID, err := openReplayDB(ctx, arg)
+			require.NoError(t, err)
+			defer db.Close()
+			require.Equal(t, replayTraceID, traceID)
+
+			target := replayDB{dagui.NewDB()}
+			target.CaptureLogs()
+			require.NoError(t, replayTrace(ctx, db, traceID, target))
+
+			require.Equal(t, replayCLISpan, target.PrimarySpan.String())
+			var names, logs []string
+			for _, c := range target.ReportCases() {
+				names = append(names, c.Name)
+				logs = append(logs, c.Logs)
+			}
+			require.Equal(t, []string{"build", "test"}, names)
+			require.Equal(t, []string{"build output\nwithExec output\n", "test output\n"}, logs)
+		})
+	}
+
+	t.Run("unknown trace", func(t *testing.T) {
+		_, _, err := openReplayDB(ctx, "00000000000000000000000000000001")
+		require.ErrorContains(t, err, "no client recorded trace 00000000000000000000000000000001")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, _, err := openReplayDB(ctx, filepath.Join(dir, "missing.db"))
+		require.ErrorIs(t, err, os.ErrNotExist)
+	})
+}
+
+// replayDB receives replayed telemetry in a DB.
+type replayDB struct {
+	*dagui.DB
+}
+
+func (db replayDB) SetPrimary(spanID dagui.SpanID)      { db.SetPrimarySpan(spanID) }
+func (db replayDB) SpanExporter() sdktrace.SpanExporter { return db.DB }
+
+type replaySpan struct {
+	id, parent, name string
+}
+
+// recordReplayClient records spans of a trace in the database of a client,
+// with a log line for each span, like the engine does.
+func recordReplayClient(t *testing.T, dbs *clientdb.DBs, clientID, traceID string, modTime time.Time, spans ...replaySpan) {
+	ctx := context.Background()
+	db, err := dbs.Create(clientID)
+	require.NoError(t, err)
+	q := clientdb.New(db)
+	start := modTime.Add(-time.Minute).UnixNano()
+	for i, span := range spans {
+		start := start + int64(i)*int64(time.Second)
+		_, err := q.InsertSpan(ctx, clientdb.InsertSpanParams{
+			TraceID: traceID,
+			SpanID:  span.id,
+			ParentSpanID: sql.NullString{
+				String: span.parent,
+				Valid:  span.parent != "",
+			},
+			Name:                 span.name,
+			Kind:                 "internal",
+			StartTime:            start,
+			EndTime:              sql.NullInt64{Int64: start + int64(time.Second), Valid: true},
+			Attributes:           []byte("[]"),
+			Events:               []byte("[]"),
+			Links:                []byte("[]"),
+			InstrumentationScope: []byte(`{"name":"dagger.io/engine"}`),
+			Resource:             []byte("{}"),
+		})
+		require.NoError(t, err)
+
+		body, err := proto.Marshal(&otlpcommonv1.AnyValue{
+			Value: &otlpcommonv1.AnyValue_StringValue{StringValue: span.name + " output\n"},
+		})
+		require.NoError(t, err)
+		_, err = q.InsertLog(ctx, clientdb.InsertLogParams{
+			TraceID:              sql.NullString{String: traceID, Valid: true},
+			SpanID:               sql.NullString{String: span.id, Valid: true},
+			Timestamp:            start,
+			Body:                 body,
+			Attributes:           []byte("[]"),
+			InstrumentationScope: []byte(`{"name":"dagger.io/engine"}`),
+			Resource:             []byte("{}"),
+			ResourceSchemaUrl:    "https://opentelemetry.io/schemas/1.26.0",
+		})
+		require.NoError(t, err)
+	}
+	require.NoError(t, db.Close())
+	require.NoError(t, os.Chtimes(filepath.Join(dbs.Root, clientID+".db"), modTime, modTime))
+}
@@ -80,6 +80,8 @@ func init() {
 	)
 
 	runCmd.Flags().BoolVar(&runFocus, "focus", false, "Only show output for focused commands.")
+
+	installReportFlags(runCmd.Flags())
 }
 
 func Run(cmd *cobra.Command, args []string) error {
@@ -106,6 +108,10 @@ func Run(cmd *cobra.Command, args []string) error {
 func run(cmd *cobra.Command, args []string) error {
 	ctx := cmd.Context()
 
+	if err := setFrontendReports(); err != nil {
+		return err
+	}
+
 	u, err := uuid.NewRandom()
 	if err != nil {
 		return fmt.Errorf("generate uuid: %w", err)
@@ -185,7 +185,7 @@ func serveSession() {
 
 	attachables := []bksession.Attachable{
 		// secrets
-		secretprovider.NewSecretProvider(),
+		secretprovider.NewSecretProvider(nil, secretprovider.CacheOpts{}),
 		// sockets
 		client.SocketProvider{EnableHostNetworkAccess: true},
 		// host=>container networking
@@ -0,0 +1,231 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/call"
+	"github.com/dagger/dagger/dagql/call/callpbv1"
+)
+
+// RecordedCall is a call recorded in the telemetry of a client.
+type RecordedCall struct {
+	ClientID string
+	TraceID  string
+	Time     time.Time
+	Call     *callpbv1.Call
+}
+
+type CacheMissExplanation struct {
+	Digest         string `field:"true" doc:"The digest of the call that missed the cache."`
+	Trace          string `field:"true" doc:"The trace of the run the call is from."`
+	PreviousDigest string `field:"true" doc:"The digest of the call it was compared to."`
+	PreviousTrace  string `field:"true" doc:"The trace of the previous run the call it was compared to is from."`
+
+	Kind          CacheMissKind `field:"true" doc:"What differs between the calls."`
+	Call          string        `field:"true" doc:"The first diverging call, e.g. host.directory(path: \"./src\"), empty if the calls are the same."`
+	Argument      string        `field:"true" doc:"The name of the diverging argument, if the kind is ARGUMENT."`
+	PreviousValue string        `field:"true" doc:"The previous value, e.g. of the argument or content digest, empty if missing."`
+	CurrentValue  string        `field:"true" doc:"The current value, e.g. of the argument or content digest, empty if missing."`
+	Message       string        `field:"true" doc:"A description of the first difference between the calls."`
+}
+
+func (*CacheMissExplanation) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "CacheMissExplanation",
+		NonNull:   true,
+	}
+}
+
+func (*CacheMissExplanation) TypeDescription() string {
+	return "The first difference between a call and the same call in a previous run, which explains why it missed the cache."
+}
+
+type CacheMissKind string
+
+var CacheMissKinds = dagql.NewEnum[CacheMissKind]()
+
+var (
+	CacheMissNone = CacheMissKinds.Register("NONE",
+		`The calls are the same, so the previous result was pruned from the cache or was never cached.`)
+	CacheMissReceiver = CacheMissKinds.Register("RECEIVER",
+		`A call is chained from an object in only one of the runs.`)
+	CacheMissCall = CacheMissKinds.Register("CALL",
+		`A call selects a different field, view or module.`)
+	CacheMissArgument = CacheMissKinds.Register("ARGUMENT",
+		`An argument was added, removed or changed.`)
+	CacheMissContent = CacheMissKinds.Register("CONTENT",
+		`A call with the same arguments has different content, e.g. the files of a host directory.`)
+)
+
+func (kind CacheMissKind) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "CacheMissKind",
+		NonNull:   true,
+	}
+}
+
+func (kind CacheMissKind) TypeDescription() string {
+	return "What differs between a call and the same call in a previous run."
+}
+
+func (kind CacheMissKind) Decoder() dagql.InputDecoder {
+	return CacheMissKinds
+}
+
+func (kind CacheMissKind) ToLiteral() call.Literal {
+	return CacheMissKinds.Literal(kind)
+}
+
+var cacheMissKindsByDivergence = map[call.DivergenceKind]CacheMissKind{
+	call.DivergenceReceiver: CacheMissReceiver,
+	call.DivergenceCall:     CacheMissCall,
+	call.DivergenceArgument: CacheMissArgument,
+	call.DivergenceContent:  CacheMissContent,
+}
+
+// ExplainCacheMiss compares the most recent call with the given digest to the
+// call with the previous digest, or by default to the most recent call of the
+// same fields from an earlier run, as recorded in the telemetry of the
+// engine's recent clients.
+func ExplainCacheMiss(ctx context.Context, q *Query, dgst, prevDgst digest.Digest) (*CacheMissExplanation, error) {
+	calls, err := q.RecordedCalls(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recorded calls: %w", err)
+	}
+	return explainCacheMiss(calls, dgst, prevDgst)
+}
+
+func explainCacheMiss(calls []*RecordedCall, dgst, prevDgst digest.Digest) (*CacheMissExplanation, error) {
+	calls = append([]*RecordedCall(nil), calls...)
+	sort.SliceStable(calls, func(i, j int) bool {
+		return calls[i].Time.After(calls[j].Time)
+	})
+	callsByDigest := map[string]*callpbv1.Call{}
+	for _, rc := range calls {
+		callsByDigest[rc.Call.Digest] = rc.Call
+	}
+	decode := func(rc *RecordedCall) (*call.ID, error) {
+		id := new(call.ID)
+		if err := id.FromProto(&callpbv1.DAG{
+			RootDigest:    rc.Call.Digest,
+			CallsByDigest: callsByDigest,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to decode call %s: %w", rc.Call.Digest, err)
+		}
+		return id, nil
+	}
+
+	cur := findRecordedCall(calls, dgst)
+	if cur == nil {
+		return nil, fmt.Errorf("no recent run recorded a call with digest %s", dgst)
+	}
+	curID, err := decode(cur)
+	if err != nil {
+		return nil, err
+	}
+
+	var prev *RecordedCall
+	var prevID *call.ID
+	if prevDgst != "" {
+		prev = findRecordedCall(calls, prevDgst)
+		if prev == nil {
+			return nil, fmt.Errorf("no recent run recorded a call with digest %s", prevDgst)
+		}
+		prevID, err = decode(prev)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// of the calls of the same fields in the most recent earlier run,
+		// prefer the one that shares the most receivers with the current call
+		shape := callShape(curID)
+		bestDepth := -1
+		for _, rc := range calls {
+			if rc.TraceID == cur.TraceID || !rc.Time.Before(cur.Time) || rc.Call.Field != cur.Call.Field {
+				continue
+			}
+			if prev != nil && rc.TraceID != prev.TraceID {
+				break
+			}
+			id, err := decode(rc)
+			if err != nil || callShape(id) != shape {
+				continue
+			}
+			if depth := commonDepth(id, curID); depth > bestDepth {
+				prev, prevID, bestDepth = rc, id, depth
+			}
+		}
+		if prev == nil {
+			return nil, fmt.Errorf("no earlier run recorded a call like %s", curID.Path())
+		}
+	}
+
+	explanation := &CacheMissExplanation{
+		Digest:         curID.Digest().String(),
+		Trace:          cur.TraceID,
+		PreviousDigest: prevID.Digest().String(),
+		PreviousTrace:  prev.TraceID,
+		Kind:           CacheMissNone,
+		Message:        fmt.Sprintf("%s: same call as in trace %s", curID.Field(), prev.TraceID),
+	}
+	if d := call.Diverge(prevID, curID); d != nil {
+		explanation.Kind = cacheMissKindsByDivergence[d.Kind]
+		explanation.Call = d.CallDisplay()
+		explanation.Argument = d.Argument
+		explanation.PreviousValue = d.PreviousValue
+		explanation.CurrentValue = d.CurrentValue
+		explanation.Message = d.String()
+	}
+	return explanation, nil
+}
+
+// findRecordedCall returns the most recent call with the digest.
+func findRecordedCall(calls []*RecordedCall, dgst digest.Digest) *RecordedCall {
+	for _, rc := range calls {
+		if rc.Call.Digest == dgst.String() {
+			return rc
+		}
+	}
+	return nil
+}
+
+// callShape returns the fields of an ID and its receivers, e.g.
+// "container.from.withExec".
+func callShape(id *call.ID) string {
+	var fields []string
+	for ; id != nil; id = id.Receiver() {
+		fields = append(fields, id.Field())
+	}
+	for i, j := 0, len(fields)-1; i < j; i, j = i+1, j-1 {
+		fields[i], fields[j] = fields[j], fields[i]
+	}
+	return strings.Join(fields, ".")
+}
+
+// commonDepth returns the number of receivers, from the root, that two IDs of
+// the same shape have in common.
+func commonDepth(a, b *call.ID) int {
+	var as, bs []digest.Digest
+	for ; a != nil; a = a.Receiver() {
+		as = append(as, a.Digest())
+	}
+	for ; b != nil; b = b.Receiver() {
+		bs = append(bs, b.Digest())
+	}
+	depth := 0
+	for i := 1; i <= len(as) && i <= len(bs); i++ {
+		if as[len(as)-i] != bs[len(bs)-i] {
+			break
+		}
+		depth++
+	}
+	return depth
+}
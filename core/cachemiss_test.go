@@ -0,0 +1,130 @@
+package core
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/dagger/dagger/dagql/call"
+)
+
+func TestExplainCacheMiss(t *testing.T) {
+	t.Parallel()
+
+	src := func(content digest.Digest) *call.ID {
+		host := testCallID(nil, "Host", "host", "")
+		return testCallID(host, "Directory", "directory", content,
+			call.NewArgument("path", call.NewLiteralString("./src"), false))
+	}
+	build := func(image string, src *call.ID, cmd string) *call.ID {
+		ctr := testCallID(nil, "Container", "container", "")
+		ctr = testCallID(ctr, "Container", "from", "",
+			call.NewArgument("address", call.NewLiteralString(image), false))
+		ctr = testCallID(ctr, "Container", "withDirectory", "",
+			call.NewArgument("directory", call.NewLiteralID(src), false),
+			call.NewArgument("path", call.NewLiteralString("/src"), false))
+		return testCallID(ctr, "Container", "withExec", "",
+			call.NewArgument("args", call.NewLiteralList(call.NewLiteralString(cmd)), false))
+	}
+
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	prevBuild := build("golang", src("sha256:a"), "build")
+	prevCalls := recordedCalls("prev", start,
+		build("alpine", src("sha256:a"), "build"),
+		prevBuild)
+	calls := slices.Concat(prevCalls, recordedCalls("cur", start.Add(time.Minute),
+		build("golang", src("sha256:b"), "build"),
+		build("golang", src("sha256:a"), "test"),
+		prevBuild))
+
+	t.Run("content", func(t *testing.T) {
+		cur := build("golang", src("sha256:b"), "build")
+		explanation, err := explainCacheMiss(calls, cur.Digest(), "")
+		require.NoError(t, err)
+		require.Equal(t, &CacheMissExplanation{
+			Digest:         cur.Digest().String(),
+			Trace:          "cur",
+			PreviousDigest: prevBuild.Digest().String(),
+			PreviousTrace:  "prev",
+			Kind:           CacheMissContent,
+			Call:           `host.directory(path: "./src")`,
+			PreviousValue:  "sha256:a",
+			CurrentValue:   "sha256:b",
+			Message:        `host.directory(path: "./src"): content changed (digest sha256:a -> sha256:b)`,
+		}, explanation)
+	})
+
+	t.Run("argument", func(t *testing.T) {
+		// the call from the same image is compared, since it shares more
+		// receivers
+		cur := build("golang", src("sha256:a"), "test")
+		explanation, err := explainCacheMiss(calls, cur.Digest(), "")
+		require.NoError(t, err)
+		require.Equal(t, CacheMissArgument, explanation.Kind)
+		require.Equal(t, prevBuild.Digest().String(), explanation.PreviousDigest)
+		require.Equal(t, "args", explanation.Argument)
+		require.Equal(t, `["build"]`, explanation.PreviousValue)
+		require.Equal(t, `["test"]`, explanation.CurrentValue)
+	})
+
+	t.Run("previous digest", func(t *testing.T) {
+		cur := build("golang", src("sha256:a"), "test")
+		prev := build("alpine", src("sha256:a"), "build")
+		explanation, err := explainCacheMiss(calls, cur.Digest(), prev.Digest())
+		require.NoError(t, err)
+		require.Equal(t, CacheMissArgument, explanation.Kind)
+		require.Equal(t, `container.from(address: "golang")`, explanation.Call)
+		require.Equal(t, "address", explanation.Argument)
+		require.Equal(t, `"alpine"`, explanation.PreviousValue)
+		require.Equal(t, `"golang"`, explanation.CurrentValue)
+	})
+
+	t.Run("same call", func(t *testing.T) {
+		// the most recent call with the digest is from the current run
+		explanation, err := explainCacheMiss(calls, prevBuild.Digest(), "")
+		require.NoError(t, err)
+		require.Equal(t, CacheMissNone, explanation.Kind)
+		require.Equal(t, "cur", explanation.Trace)
+		require.Equal(t, "prev", explanation.PreviousTrace)
+		require.Empty(t, explanation.Call)
+	})
+
+	t.Run("unknown call", func(t *testing.T) {
+		_, err := explainCacheMiss(calls, "xxh3:0000", "")
+		require.ErrorContains(t, err, "no recent run recorded a call with digest xxh3:0000")
+	})
+
+	t.Run("no earlier run", func(t *testing.T) {
+		prev := build("alpine", src("sha256:a"), "build")
+		_, err := explainCacheMiss(prevCalls, prev.Digest(), "")
+		require.ErrorContains(t, err, "no earlier run recorded a call like")
+	})
+}
+
+func testCallID(recv *call.ID, typ, field string, customDigest digest.Digest, args ...*call.Argument) *call.ID {
+	return recv.Append(ast.NonNullNamedType(typ, nil), field, "", nil, false, 0, customDigest, args...)
+}
+
+// recordedCalls records the calls of IDs in a trace, like their spans do.
+func recordedCalls(traceID string, at time.Time, ids ...*call.ID) []*RecordedCall {
+	var calls []*RecordedCall
+	for _, id := range ids {
+		dag, err := id.ToProto()
+		if err != nil {
+			panic(err)
+		}
+		for _, pb := range dag.CallsByDigest {
+			calls = append(calls, &RecordedCall{
+				ClientID: traceID,
+				TraceID:  traceID,
+				Time:     at,
+				Call:     pb,
+			})
+		}
+	}
+	return calls
+}
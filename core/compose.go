@@ -0,0 +1,753 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/shlex"
+	"github.com/vektah/gqlparser/v2/ast"
+	"gopkg.in/yaml.v3"
+
+	"github.com/dagger/dagger/dagql"
+)
+
+// ComposeDefaultFiles are the file names looked for when loading a Compose
+// project from a directory, in order of preference.
+var ComposeDefaultFiles = []string{
+	"compose.yaml",
+	"compose.yml",
+	"docker-compose.yaml",
+	"docker-compose.yml",
+}
+
+// ComposeProject is the set of services described by a Compose file.
+type ComposeProject struct {
+	Query *Query
+
+	// Name of the project, which named volumes are scoped to.
+	Name string `json:"name"`
+
+	// Context is the directory that relative paths in the Compose file are
+	// resolved against. It is nil for a Compose file loaded on its own.
+	Context *dagql.Instance[*Directory] `json:"context,omitempty"`
+
+	// Dir is the path of the directory containing the Compose file, relative
+	// to Context.
+	Dir string `json:"dir,omitempty"`
+
+	Services map[string]*ComposeService `json:"services"`
+
+	// Volumes maps the named volumes declared by the project to the name they
+	// are stored under.
+	Volumes map[string]string `json:"volumes"`
+}
+
+func (*ComposeProject) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "ComposeProject",
+		NonNull:   true,
+	}
+}
+
+func (*ComposeProject) TypeDescription() string {
+	return "A set of services described by a Compose file."
+}
+
+// ServiceNames returns the names of the project's services, sorted.
+func (proj *ComposeProject) ServiceNames() []string {
+	names := make([]string, 0, len(proj.Services))
+	for name := range proj.Services {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// VolumeKey returns the key of the cache volume backing the named volume.
+func (proj *ComposeProject) VolumeKey(name string) string {
+	if key, ok := proj.Volumes[name]; ok && key != "" {
+		return key
+	}
+	return proj.Name + "_" + name
+}
+
+// ComposeService is a service of a Compose project, normalized from the
+// various forms the Compose file format allows.
+type ComposeService struct {
+	Name string `json:"name"`
+
+	Image string        `json:"image,omitempty"`
+	Build *ComposeBuild `json:"build,omitempty"`
+
+	// Entrypoint and Command override the image's, if set.
+	Entrypoint []string `json:"entrypoint,omitempty"`
+	Command    []string `json:"command,omitempty"`
+
+	Environment []ComposeEnv `json:"environment,omitempty"`
+	WorkingDir  string       `json:"workingDir,omitempty"`
+	User        string       `json:"user,omitempty"`
+
+	Ports   []Port          `json:"ports,omitempty"`
+	Volumes []ComposeVolume `json:"volumes,omitempty"`
+
+	// Healthcheck is nil if the service doesn't configure one, in which case
+	// its ports are checked as usual.
+	Healthcheck *ServiceHealthcheckOpts `json:"healthcheck,omitempty"`
+
+	// DependsOn lists the services this service is bound to, sorted.
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	RestartPolicy     ServiceRestartPolicy `json:"restartPolicy,omitempty"`
+	RestartMaxRetries int                  `json:"restartMaxRetries,omitempty"`
+}
+
+type ComposeBuild struct {
+	// Context is the path of the build context, relative to the directory
+	// containing the Compose file.
+	Context    string     `json:"context"`
+	Dockerfile string     `json:"dockerfile,omitempty"`
+	Target     string     `json:"target,omitempty"`
+	Args       []BuildArg `json:"args,omitempty"`
+}
+
+type ComposeEnv struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type ComposeVolumeType string
+
+const (
+	ComposeVolumeNamed ComposeVolumeType = "volume"
+	ComposeVolumeBind  ComposeVolumeType = "bind"
+	ComposeVolumeTmpfs ComposeVolumeType = "tmpfs"
+)
+
+type ComposeVolume struct {
+	Type ComposeVolumeType `json:"type"`
+	// Source is the name of a named volume, or the path of a bind mount
+	// relative to the directory containing the Compose file. It is empty for
+	// anonymous volumes and tmpfs mounts.
+	Source string `json:"source,omitempty"`
+	Target string `json:"target"`
+}
+
+// LoadCompose parses a Compose file, interpolating variables from the given
+// environment (e.g. from a .env file). If projectName is empty, the name set
+// in the file is used, or "default".
+func LoadCompose(data []byte, env map[string]string, projectName string) (*ComposeProject, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse compose file: %w", err)
+	}
+	if err := interpolateNode(&doc, env); err != nil {
+		return nil, err
+	}
+
+	var raw composeFile
+	if err := doc.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("parse compose file: %w", err)
+	}
+	if len(raw.Services) == 0 {
+		return nil, fmt.Errorf("compose file has no services")
+	}
+
+	if projectName == "" {
+		projectName = raw.Name
+	}
+	if projectName == "" {
+		projectName = "default"
+	}
+	proj := &ComposeProject{
+		Name:     projectName,
+		Services: map[string]*ComposeService{},
+		Volumes:  map[string]string{},
+	}
+	for name, vol := range raw.Volumes {
+		key := ""
+		if vol != nil {
+			key = vol.Name
+		}
+		proj.Volumes[name] = key
+	}
+	for name, rawSvc := range raw.Services {
+		svc, err := rawSvc.normalize(name)
+		if err != nil {
+			return nil, fmt.Errorf("service %q: %w", name, err)
+		}
+		for _, vol := range svc.Volumes {
+			if vol.Type != ComposeVolumeNamed || vol.Source == "" {
+				continue
+			}
+			if _, ok := proj.Volumes[vol.Source]; !ok {
+				return nil, fmt.Errorf("service %q: volume %q is not declared in the top-level volumes", name, vol.Source)
+			}
+		}
+		proj.Services[name] = svc
+	}
+	for name, svc := range proj.Services {
+		for _, dep := range svc.DependsOn {
+			if _, ok := proj.Services[dep]; !ok {
+				return nil, fmt.Errorf("service %q depends on undefined service %q", name, dep)
+			}
+		}
+	}
+	return proj, nil
+}
+
+// ParseDotEnv parses the KEY=VALUE lines of a .env file.
+func ParseDotEnv(data []byte) map[string]string {
+	env := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		env[strings.TrimSpace(name)] = value
+	}
+	return env
+}
+
+type composeFile struct {
+	Name     string                        `yaml:"name"`
+	Services map[string]*composeService    `yaml:"services"`
+	Volumes  map[string]*composeVolumeDecl `yaml:"volumes"`
+}
+
+type composeVolumeDecl struct {
+	Name string `yaml:"name"`
+}
+
+type composeService struct {
+	Image       string              `yaml:"image"`
+	Build       *composeBuild       `yaml:"build"`
+	Entrypoint  composeCommand      `yaml:"entrypoint"`
+	Command     composeCommand      `yaml:"command"`
+	Environment composeMapping      `yaml:"environment"`
+	WorkingDir  string              `yaml:"working_dir"`
+	User        string              `yaml:"user"`
+	Ports       []composePort       `yaml:"ports"`
+	Expose      []string            `yaml:"expose"`
+	Volumes     []composeVolume     `yaml:"volumes"`
+	Healthcheck *composeHealthcheck `yaml:"healthcheck"`
+	DependsOn   composeDependsOn    `yaml:"depends_on"`
+	Restart     string              `yaml:"restart"`
+}
+
+func (raw *composeService) normalize(name string) (*ComposeService, error) {
+	if raw == nil {
+		return nil, fmt.Errorf("empty service definition")
+	}
+	svc := &ComposeService{
+		Name:       name,
+		Image:      raw.Image,
+		Entrypoint: raw.Entrypoint,
+		Command:    raw.Command,
+		WorkingDir: raw.WorkingDir,
+		User:       raw.User,
+		DependsOn:  raw.DependsOn,
+	}
+	if raw.Image == "" && raw.Build == nil {
+		return nil, fmt.Errorf("either image or build must be set")
+	}
+	if raw.Build != nil {
+		build := ComposeBuild(*raw.Build)
+		svc.Build = &build
+	}
+
+	for _, kv := range raw.Environment {
+		if kv.unset {
+			// taken from the environment compose runs in, which we don't have
+			continue
+		}
+		svc.Environment = append(svc.Environment, ComposeEnv{Name: kv.name, Value: kv.value})
+	}
+
+	seenPorts := map[Port]bool{}
+	addPort := func(port Port) {
+		if !seenPorts[port] {
+			seenPorts[port] = true
+			svc.Ports = append(svc.Ports, port)
+		}
+	}
+	for _, p := range raw.Ports {
+		for _, port := range p {
+			addPort(port)
+		}
+	}
+	for _, expose := range raw.Expose {
+		ports, err := parseComposePorts(expose)
+		if err != nil {
+			return nil, fmt.Errorf("expose: %w", err)
+		}
+		for _, port := range ports {
+			addPort(port)
+		}
+	}
+
+	for _, vol := range raw.Volumes {
+		svc.Volumes = append(svc.Volumes, ComposeVolume(vol))
+	}
+
+	if hc := raw.Healthcheck; hc != nil && !hc.Disable {
+		opts, err := hc.normalize()
+		if err != nil {
+			return nil, fmt.Errorf("healthcheck: %w", err)
+		}
+		svc.Healthcheck = opts
+	}
+
+	switch {
+	case raw.Restart == "" || raw.Restart == "no":
+	case raw.Restart == "always" || raw.Restart == "unless-stopped":
+		svc.RestartPolicy = ServiceRestartAlways
+	case raw.Restart == "on-failure":
+		svc.RestartPolicy = ServiceRestartOnFailure
+	case strings.HasPrefix(raw.Restart, "on-failure:"):
+		retries, err := strconv.Atoi(strings.TrimPrefix(raw.Restart, "on-failure:"))
+		if err != nil || retries < 0 {
+			return nil, fmt.Errorf("invalid restart policy %q", raw.Restart)
+		}
+		svc.RestartPolicy = ServiceRestartOnFailure
+		svc.RestartMaxRetries = retries
+	default:
+		return nil, fmt.Errorf("invalid restart policy %q", raw.Restart)
+	}
+
+	return svc, nil
+}
+
+// composeBuild is either a context path, or a build configuration.
+type composeBuild ComposeBuild
+
+func (build *composeBuild) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		build.Context = node.Value
+		return nil
+	}
+	var raw struct {
+		Context    string         `yaml:"context"`
+		Dockerfile string         `yaml:"dockerfile"`
+		Target     string         `yaml:"target"`
+		Args       composeMapping `yaml:"args"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	build.Context = raw.Context
+	if build.Context == "" {
+		build.Context = "."
+	}
+	build.Dockerfile = raw.Dockerfile
+	build.Target = raw.Target
+	for _, kv := range raw.Args {
+		if kv.unset {
+			continue
+		}
+		build.Args = append(build.Args, BuildArg{Name: kv.name, Value: kv.value})
+	}
+	return nil
+}
+
+// composeCommand is either a list of args, or a string split like a shell
+// would.
+type composeCommand []string
+
+func (cmd *composeCommand) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		args, err := shlex.Split(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		*cmd = args
+		return nil
+	}
+	var args []string
+	if err := node.Decode(&args); err != nil {
+		return err
+	}
+	*cmd = args
+	return nil
+}
+
+type composeKeyValue struct {
+	name  string
+	value string
+	// set for names listed without a value
+	unset bool
+}
+
+// composeMapping is either a map, or a list of NAME=VALUE strings. It's kept
+// in order so that the resulting services are stable.
+type composeMapping []composeKeyValue
+
+func (m *composeMapping) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, val := node.Content[i], node.Content[i+1]
+			kv := composeKeyValue{name: key.Value}
+			if val.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: value of %s must be a string", val.Line, key.Value)
+			}
+			if val.Tag == "!!null" {
+				kv.unset = true
+			} else {
+				kv.value = val.Value
+			}
+			*m = append(*m, kv)
+		}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			name, value, ok := strings.Cut(item.Value, "=")
+			*m = append(*m, composeKeyValue{name: name, value: value, unset: !ok})
+		}
+	default:
+		return fmt.Errorf("line %d: must be a map or a list", node.Line)
+	}
+	return nil
+}
+
+// composeDependsOn is either a list of service names, or a map of service
+// names to conditions. Conditions aren't needed, since bound services are
+// always started and healthy before the services they're bound to.
+type composeDependsOn []string
+
+func (deps *composeDependsOn) UnmarshalYAML(node *yaml.Node) error {
+	var names []string
+	switch node.Kind {
+	case yaml.MappingNode:
+		for i := 0; i < len(node.Content); i += 2 {
+			names = append(names, node.Content[i].Value)
+		}
+	case yaml.SequenceNode:
+		if err := node.Decode(&names); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("line %d: must be a map or a list", node.Line)
+	}
+	sort.Strings(names)
+	*deps = names
+	return nil
+}
+
+// composePort is a port in either its short or long syntax, which may specify
+// a range of ports.
+type composePort []Port
+
+func (p *composePort) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		ports, err := parseComposePorts(node.Value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		*p = ports
+		return nil
+	}
+	var raw struct {
+		Target   string `yaml:"target"`
+		Protocol string `yaml:"protocol"`
+	}
+	if err := node.Decode(&raw); err != nil {
+		return err
+	}
+	spec := raw.Target
+	if raw.Protocol != "" {
+		spec += "/" + raw.Protocol
+	}
+	ports, err := parseComposePorts(spec)
+	if err != nil {
+		return fmt.Errorf("line %d: %w", node.Line, err)
+	}
+	*p = ports
+	return nil
+}
+
+// parseComposePorts parses the container ports of a port spec like
+// "127.0.0.1:8080-8081:80-81/tcp".
+func parseComposePorts(spec string) ([]Port, error) {
+	protocol := NetworkProtocolTCP
+	if rest, proto, ok := strings.Cut(spec, "/"); ok {
+		switch strings.ToLower(proto) {
+		case "tcp":
+		case "udp":
+			protocol = NetworkProtocolUDP
+		default:
+			return nil, fmt.Errorf("invalid protocol in port %q", spec)
+		}
+		spec = rest
+	}
+	// the container port(s) come last, after the host IP and port(s)
+	if i := strings.LastIndex(spec, ":"); i >= 0 {
+		spec = spec[i+1:]
+	}
+	first, last, isRange := strings.Cut(spec, "-")
+	start, err := strconv.Atoi(first)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", spec)
+	}
+	end := start
+	if isRange {
+		end, err = strconv.Atoi(last)
+		if err != nil || end < start {
+			return nil, fmt.Errorf("invalid port range %q", spec)
+		}
+	}
+	var ports []Port
+	for port := start; port <= end; port++ {
+		ports = append(ports, Port{Port: port, Protocol: protocol})
+	}
+	return ports, nil
+}
+
+// composeVolume is a volume in either its short or long syntax.
+type composeVolume ComposeVolume
+
+func (vol *composeVolume) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		parts := strings.Split(node.Value, ":")
+		switch len(parts) {
+		case 1:
+			// anonymous volume
+			vol.Type = ComposeVolumeNamed
+			vol.Target = parts[0]
+			return nil
+		case 2, 3:
+			// the third part is the access mode, which doesn't matter here
+			vol.Source = parts[0]
+			vol.Target = parts[1]
+		default:
+			return fmt.Errorf("line %d: invalid volume %q", node.Line, node.Value)
+		}
+		if strings.HasPrefix(vol.Source, ".") || strings.HasPrefix(vol.Source, "/") || strings.HasPrefix(vol.Source, "~") {
+			vol.Type = ComposeVolumeBind
+		} else {
+			vol.Type = ComposeVolumeNamed
+		}
+	} else {
+		var raw struct {
+			Type   string `yaml:"type"`
+			Source string `yaml:"source"`
+			Target string `yaml:"target"`
+		}
+		if err := node.Decode(&raw); err != nil {
+			return err
+		}
+		switch ComposeVolumeType(raw.Type) {
+		case ComposeVolumeNamed, ComposeVolumeBind, ComposeVolumeTmpfs:
+		default:
+			return fmt.Errorf("line %d: unsupported volume type %q", node.Line, raw.Type)
+		}
+		vol.Type = ComposeVolumeType(raw.Type)
+		vol.Source = raw.Source
+		vol.Target = raw.Target
+	}
+	if vol.Type == ComposeVolumeBind {
+		if strings.HasPrefix(vol.Source, "/") || strings.HasPrefix(vol.Source, "~") {
+			return fmt.Errorf("line %d: bind mount of host path %q is not supported; use a path relative to the compose file", node.Line, vol.Source)
+		}
+	}
+	if vol.Target == "" {
+		return fmt.Errorf("line %d: volume has no target", node.Line)
+	}
+	return nil
+}
+
+type composeHealthcheck struct {
+	Test     composeHealthcheckTest `yaml:"test"`
+	Interval string                 `yaml:"interval"`
+	Timeout  string                 `yaml:"timeout"`
+	Retries  int                    `yaml:"retries"`
+	Disable  bool                   `yaml:"disable"`
+}
+
+func (hc *composeHealthcheck) normalize() (*ServiceHealthcheckOpts, error) {
+	if len(hc.Test) == 0 {
+		return nil, nil
+	}
+	opts := &ServiceHealthcheckOpts{
+		HealthcheckRetries: hc.Retries,
+	}
+	switch hc.Test[0] {
+	case "NONE":
+		return nil, nil
+	case "CMD":
+		opts.HealthcheckExec = hc.Test[1:]
+	case "CMD-SHELL":
+		opts.HealthcheckExec = []string{"/bin/sh", "-c", strings.Join(hc.Test[1:], " ")}
+	default:
+		return nil, fmt.Errorf("test must start with NONE, CMD or CMD-SHELL, got %q", hc.Test[0])
+	}
+	var err error
+	if opts.HealthcheckInterval, err = composeSeconds(hc.Interval); err != nil {
+		return nil, fmt.Errorf("interval: %w", err)
+	}
+	if opts.HealthcheckTimeout, err = composeSeconds(hc.Timeout); err != nil {
+		return nil, fmt.Errorf("timeout: %w", err)
+	}
+	return opts, nil
+}
+
+// composeHealthcheckTest is either a list starting with NONE, CMD or
+// CMD-SHELL, or a string to run with a shell.
+type composeHealthcheckTest []string
+
+func (test *composeHealthcheckTest) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*test = []string{"CMD-SHELL", node.Value}
+		return nil
+	}
+	var args []string
+	if err := node.Decode(&args); err != nil {
+		return err
+	}
+	*test = args
+	return nil
+}
+
+// composeSeconds converts a Compose duration (e.g. "1m30s") to whole seconds,
+// rounding up.
+func composeSeconds(duration string) (int, error) {
+	if duration == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return 0, err
+	}
+	return int(math.Ceil(d.Seconds())), nil
+}
+
+// interpolateNode replaces variables in the scalar values of a YAML document.
+func interpolateNode(node *yaml.Node, env map[string]string) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if !strings.Contains(node.Value, "$") {
+			return nil
+		}
+		value, err := interpolate(node.Value, env)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", node.Line, err)
+		}
+		node.Value = value
+	case yaml.MappingNode:
+		// only values are interpolated, not keys
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := interpolateNode(node.Content[i], env); err != nil {
+				return err
+			}
+		}
+	default:
+		for _, child := range node.Content {
+			if err := interpolateNode(child, env); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// interpolate replaces $VAR and ${VAR} references in s, supporting the
+// ${VAR:-default}, ${VAR-default}, ${VAR:?error}, ${VAR?error}, ${VAR:+alt}
+// and ${VAR+alt} forms. $$ is a literal $.
+func interpolate(s string, env map[string]string) (string, error) {
+	var out strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i == len(s)-1 {
+			out.WriteByte(s[i])
+			continue
+		}
+		next := s[i+1]
+		switch {
+		case next == '$':
+			out.WriteByte('$')
+			i++
+		case next == '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated variable in %q", s)
+			}
+			expr := s[i+2 : i+2+end]
+			value, err := expandComposeVar(expr, env)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(value)
+			i += 2 + end
+		case next == '_' || isASCIILetter(next):
+			j := i + 1
+			for j < len(s) && (s[j] == '_' || isASCIILetter(s[j]) || (s[j] >= '0' && s[j] <= '9')) {
+				j++
+			}
+			out.WriteString(env[s[i+1:j]])
+			i = j - 1
+		default:
+			out.WriteByte(s[i])
+		}
+	}
+	return out.String(), nil
+}
+
+func expandComposeVar(expr string, env map[string]string) (string, error) {
+	n := 0
+	for n < len(expr) && (expr[n] == '_' || isASCIILetter(expr[n]) || (n > 0 && expr[n] >= '0' && expr[n] <= '9')) {
+		n++
+	}
+	name, rest := expr[:n], expr[n:]
+	if name == "" {
+		return "", fmt.Errorf("invalid variable ${%s}", expr)
+	}
+	value, set := env[name]
+	if rest == "" {
+		return value, nil
+	}
+	if rest[0] == ':' {
+		// the colon forms treat empty values as unset
+		set = set && value != ""
+		rest = rest[1:]
+	}
+	if rest == "" {
+		return "", fmt.Errorf("invalid variable ${%s}", expr)
+	}
+	op, arg := rest[0], rest[1:]
+	switch op {
+	case '-':
+		if !set {
+			return arg, nil
+		}
+		return value, nil
+	case '?':
+		if !set {
+			return "", fmt.Errorf("required variable %s is missing a value: %s", name, arg)
+		}
+		return value, nil
+	case '+':
+		if set {
+			return arg, nil
+		}
+		return "", nil
+	default:
+		return "", fmt.Errorf("invalid variable ${%s}", expr)
+	}
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
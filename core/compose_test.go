@@ -0,0 +1,212 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dagger/dagger/core"
+)
+
+func TestLoadCompose(t *testing.T) {
+	t.Parallel()
+
+	proj, err := core.LoadCompose([]byte(`
+name: shop
+services:
+  web:
+    build:
+      context: ./web
+      dockerfile: Dockerfile.dev
+      args:
+        - GO_VERSION=1.23
+    command: ./server --port "8080"
+    environment:
+      DATABASE_URL: postgres://db:5432/${DB_NAME:-shop}
+      DEBUG: "true"
+      FROM_SHELL:
+    ports:
+      - "127.0.0.1:8080-8081:80-81"
+      - target: 9090
+        published: 9090
+        protocol: udp
+    volumes:
+      - ./static:/srv/static:ro
+      - type: tmpfs
+        target: /tmp
+    depends_on:
+      db:
+        condition: service_healthy
+      cache:
+        condition: service_started
+    restart: on-failure:3
+  db:
+    image: postgres:16
+    environment:
+      - POSTGRES_PASSWORD=$${literal}
+      - POSTGRES_DB=${DB_NAME}
+    expose:
+      - 5432
+    volumes:
+      - data:/var/lib/postgresql/data
+      - /var/lib/anonymous
+    healthcheck:
+      test: ["CMD-SHELL", "pg_isready -U postgres"]
+      interval: 2s
+      timeout: 1500ms
+      retries: 5
+  cache:
+    image: redis
+    entrypoint: ["redis-server"]
+    healthcheck:
+      test: redis-cli ping
+    restart: unless-stopped
+volumes:
+  data:
+`), map[string]string{"DB_NAME": "orders"}, "")
+	require.NoError(t, err)
+
+	require.Equal(t, "shop", proj.Name)
+	require.Equal(t, []string{"cache", "db", "web"}, proj.ServiceNames())
+	require.Equal(t, "shop_data", proj.VolumeKey("data"))
+
+	web := proj.Services["web"]
+	require.Equal(t, &core.ComposeBuild{
+		Context:    "./web",
+		Dockerfile: "Dockerfile.dev",
+		Args:       []core.BuildArg{{Name: "GO_VERSION", Value: "1.23"}},
+	}, web.Build)
+	require.Equal(t, []string{"./server", "--port", "8080"}, web.Command)
+	require.Equal(t, []core.ComposeEnv{
+		{Name: "DATABASE_URL", Value: "postgres://db:5432/orders"},
+		{Name: "DEBUG", Value: "true"},
+	}, web.Environment)
+	require.Equal(t, []core.Port{
+		{Port: 80, Protocol: core.NetworkProtocolTCP},
+		{Port: 81, Protocol: core.NetworkProtocolTCP},
+		{Port: 9090, Protocol: core.NetworkProtocolUDP},
+	}, web.Ports)
+	require.Equal(t, []core.ComposeVolume{
+		{Type: core.ComposeVolumeBind, Source: "./static", Target: "/srv/static"},
+		{Type: core.ComposeVolumeTmpfs, Target: "/tmp"},
+	}, web.Volumes)
+	require.Equal(t, []string{"cache", "db"}, web.DependsOn)
+	require.Equal(t, core.ServiceRestartOnFailure, web.RestartPolicy)
+	require.Equal(t, 3, web.RestartMaxRetries)
+	require.Nil(t, web.Healthcheck)
+
+	db := proj.Services["db"]
+	require.Equal(t, "postgres:16", db.Image)
+	require.Equal(t, []core.ComposeEnv{
+		{Name: "POSTGRES_PASSWORD", Value: "${literal}"},
+		{Name: "POSTGRES_DB", Value: "orders"},
+	}, db.Environment)
+	require.Equal(t, []core.Port{{Port: 5432, Protocol: core.NetworkProtocolTCP}}, db.Ports)
+	require.Equal(t, []core.ComposeVolume{
+		{Type: core.ComposeVolumeNamed, Source: "data", Target: "/var/lib/postgresql/data"},
+		{Type: core.ComposeVolumeNamed, Target: "/var/lib/anonymous"},
+	}, db.Volumes)
+	require.Equal(t, &core.ServiceHealthcheckOpts{
+		HealthcheckExec:     []string{"/bin/sh", "-c", "pg_isready -U postgres"},
+		HealthcheckInterval: 2,
+		HealthcheckTimeout:  2,
+		HealthcheckRetries:  5,
+	}, db.Healthcheck)
+
+	cache := proj.Services["cache"]
+	require.Equal(t, []string{"redis-server"}, cache.Entrypoint)
+	require.Equal(t, []string{"/bin/sh", "-c", "redis-cli ping"}, cache.Healthcheck.HealthcheckExec)
+	require.Equal(t, core.ServiceRestartAlways, cache.RestartPolicy)
+}
+
+func TestLoadComposeErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		compose string
+		err     string
+	}{
+		{
+			name:    "no services",
+			compose: `name: empty`,
+			err:     "compose file has no services",
+		},
+		{
+			name: "no image or build",
+			compose: `
+services:
+  app:
+    command: ["true"]`,
+			err: `service "app": either image or build must be set`,
+		},
+		{
+			name: "undefined dependency",
+			compose: `
+services:
+  app:
+    image: alpine
+    depends_on: [db]`,
+			err: `service "app" depends on undefined service "db"`,
+		},
+		{
+			name: "undeclared volume",
+			compose: `
+services:
+  app:
+    image: alpine
+    volumes: ["data:/data"]`,
+			err: `volume "data" is not declared`,
+		},
+		{
+			name: "host path",
+			compose: `
+services:
+  app:
+    image: alpine
+    volumes: ["/etc:/host-etc"]`,
+			err: `bind mount of host path "/etc" is not supported`,
+		},
+		{
+			name: "required variable",
+			compose: `
+services:
+  app:
+    image: alpine:${TAG:?must set TAG}`,
+			err: "required variable TAG is missing a value: must set TAG",
+		},
+		{
+			name: "invalid restart",
+			compose: `
+services:
+  app:
+    image: alpine
+    restart: sometimes`,
+			err: `invalid restart policy "sometimes"`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := core.LoadCompose([]byte(tc.compose), nil, "")
+			require.ErrorContains(t, err, tc.err)
+		})
+	}
+}
+
+func TestParseDotEnv(t *testing.T) {
+	t.Parallel()
+
+	env := core.ParseDotEnv([]byte(`
+# comment
+A=1
+export B = two
+C="quoted value"
+D='single'
+INVALID
+`))
+	require.Equal(t, map[string]string{
+		"A": "1",
+		"B": "two",
+		"C": "quoted value",
+		"D": "single",
+	}, env)
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -23,6 +24,7 @@ import (
 	"github.com/moby/buildkit/exporter/containerimage/exptypes"
 	"github.com/moby/buildkit/frontend/dockerui"
 	bkgw "github.com/moby/buildkit/frontend/gateway/client"
+	"github.com/moby/buildkit/session/sshforward"
 	"github.com/moby/buildkit/solver/pb"
 	"github.com/moby/buildkit/util/leaseutil"
 	"github.com/opencontainers/go-digest"
@@ -31,6 +33,7 @@ import (
 	"github.com/vektah/gqlparser/v2/ast"
 	"go.opentelemetry.io/otel/propagation"
 
+	"github.com/dagger/dagger/core/reffs"
 	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/dagql/call"
 	"github.com/dagger/dagger/engine/buildkit"
@@ -102,6 +105,24 @@ type Container struct {
 
 	// DefaultArgs have been explicitly set by the user
 	DefaultArgs bool `json:"defaultArgs,omitempty"`
+
+	// The rootfs of the image the container was created from, whose layers
+	// are kept when squashing the layers above it on export.
+	BaseFS *pb.Definition `json:"baseFS,omitempty"`
+
+	// The steps that produced the container's rootfs, in order, used to
+	// describe the image's layers on export.
+	History []ContainerHistory `json:"history,omitempty"`
+}
+
+// ContainerHistory is a step that produced a container's rootfs.
+type ContainerHistory struct {
+	// The API field of the step, e.g. "withExec".
+	Field string `json:"field"`
+	// The digest of the step's call ID.
+	Digest digest.Digest `json:"digest,omitempty"`
+	// The container's rootfs after the step.
+	FS *pb.Definition `json:"fs"`
 }
 
 func (*Container) Type() *ast.Type {
@@ -167,9 +188,26 @@ func (container *Container) Clone() *Container {
 	cp.Ports = cloneSlice(cp.Ports)
 	cp.Services = cloneSlice(cp.Services)
 	cp.SystemEnvNames = cloneSlice(cp.SystemEnvNames)
+	cp.History = cloneSlice(cp.History)
 	return &cp
 }
 
+// recordHistory records the current call as the step that produced the
+// container's current rootfs. If base is true, the rootfs is the one of an
+// image, and the previous steps are dropped.
+func (container *Container) recordHistory(ctx context.Context, base bool) {
+	if base {
+		container.BaseFS = container.FS
+		container.History = nil
+	}
+	step := ContainerHistory{FS: container.FS}
+	if id := dagql.CurrentID(ctx); id != nil {
+		step.Field = id.Field()
+		step.Digest = id.Digest()
+	}
+	container.History = append(container.History, step)
+}
+
 // Ownership contains a UID/GID pair resolved from a user/group name or ID pair
 // provided via the API. It primarily exists to distinguish an unspecified
 // ownership from UID/GID 0 (root) ownership.
@@ -374,12 +412,36 @@ func (container *Container) FromCanonicalRef(
 	container.Config = mergeImageConfig(container.Config, imgSpec.Config)
 	container.ImageRef = refStr
 	container.Platform = Platform(platforms.Normalize(imgSpec.Platform))
+	container.recordHistory(ctx, true)
 
 	return container, nil
 }
 
 const defaultDockerfileName = "Dockerfile"
 
+// DockerBuildOpts are the less common options of a Dockerfile build.
+type DockerBuildOpts struct {
+	// Named build contexts, which the Dockerfile refers to by name.
+	Contexts []NamedBuildContext
+
+	// The socket forwarded to "RUN --mount=type=ssh" instructions.
+	SSH *Socket
+
+	// The stages to build without using the cache.
+	NoCacheFilter []string
+
+	// The registry refs to import the build cache from.
+	CacheFrom []string
+}
+
+// NamedBuildContext is a build context that is either a directory or a
+// container.
+type NamedBuildContext struct {
+	Name      string
+	Directory *Directory
+	Container *Container
+}
+
 func (container *Container) Build(
 	ctx context.Context,
 	contextDir *Directory,
@@ -388,10 +450,19 @@ func (container *Container) Build(
 	target string,
 	secrets []*Secret,
 	secretStore *SecretStore,
+	buildOpts DockerBuildOpts,
 ) (*Container, error) {
 	container = container.Clone()
 
 	container.Services.Merge(contextDir.Services)
+	for _, namedCtx := range buildOpts.Contexts {
+		switch {
+		case namedCtx.Directory != nil:
+			container.Services.Merge(namedCtx.Directory.Services)
+		case namedCtx.Container != nil:
+			container.Services.Merge(namedCtx.Container.Services)
+		}
+	}
 
 	secretNameToLLBID := make(map[string]string)
 	for _, secret := range secrets {
@@ -445,11 +516,78 @@ func (container *Container) Build(
 		opts["build-arg:"+buildArg.Name] = buildArg.Value
 	}
 
+	if len(buildOpts.NoCacheFilter) > 0 {
+		opts["no-cache"] = strings.Join(buildOpts.NoCacheFilter, ",")
+	}
+
 	inputs := map[string]*pb.Definition{
 		dockerui.DefaultLocalNameContext:    contextDir.LLB,
 		dockerui.DefaultLocalNameDockerfile: contextDir.LLB,
 	}
 
+	for i, namedCtx := range buildOpts.Contexts {
+		// context names may be image refs, which aren't valid input names
+		inputName := fmt.Sprintf("dagger-context-%d", i)
+		opts["context:"+namedCtx.Name] = "input:" + inputName
+
+		switch {
+		case namedCtx.Directory != nil:
+			st, err := namedCtx.Directory.StateWithSourcePath()
+			if err != nil {
+				return nil, err
+			}
+			def, err := st.Marshal(ctx, llb.Platform(namedCtx.Directory.Platform.Spec()))
+			if err != nil {
+				return nil, err
+			}
+			inputs[inputName] = def.ToPB()
+		case namedCtx.Container != nil:
+			st, err := namedCtx.Container.FSState()
+			if err != nil {
+				return nil, err
+			}
+			def, err := st.Marshal(ctx, llb.Platform(namedCtx.Container.Platform.Spec()))
+			if err != nil {
+				return nil, err
+			}
+			inputs[inputName] = def.ToPB()
+
+			// pass the image config along, so that "FROM <name>" inherits it
+			cfgBytes, err := json.Marshal(specs.Image{
+				Platform: namedCtx.Container.Platform.Spec(),
+				Config:   namedCtx.Container.Config,
+			})
+			if err != nil {
+				return nil, err
+			}
+			mdBytes, err := json.Marshal(map[string][]byte{
+				exptypes.ExporterImageConfigKey: cfgBytes,
+			})
+			if err != nil {
+				return nil, err
+			}
+			opts["input-metadata:"+inputName] = string(mdBytes)
+		default:
+			return nil, fmt.Errorf("build context %q has neither a directory nor a container", namedCtx.Name)
+		}
+	}
+
+	var cacheImports []bkgw.CacheOptionsEntry
+	for _, ref := range buildOpts.CacheFrom {
+		cacheImports = append(cacheImports, bkgw.CacheOptionsEntry{
+			Type:  "registry",
+			Attrs: map[string]string{"ref": ref},
+		})
+	}
+	if len(cacheImports) > 0 {
+		// the frontend uses these to resolve inline cache of the base images
+		cacheImportsBytes, err := json.Marshal(cacheImports)
+		if err != nil {
+			return nil, err
+		}
+		opts["cache-imports"] = string(cacheImportsBytes)
+	}
+
 	// FIXME: ew, this is a terrible way to pass this around
 	//nolint:staticcheck
 	solveCtx := context.WithValue(ctx, "secret-translator", func(name string) (string, error) {
@@ -459,11 +597,21 @@ func (container *Container) Build(
 		}
 		return llbID, nil
 	})
+	if buildOpts.SSH != nil {
+		//nolint:staticcheck
+		solveCtx = context.WithValue(solveCtx, "ssh-translator", func(id string) (string, error) {
+			if id != sshforward.DefaultID {
+				return "", fmt.Errorf("ssh socket not found: %s", id)
+			}
+			return buildOpts.SSH.LLBID(), nil
+		})
+	}
 
 	res, err := bk.Solve(solveCtx, bkgw.SolveRequest{
 		Frontend:       "dockerfile.v0",
 		FrontendOpt:    opts,
 		FrontendInputs: inputs,
+		CacheImports:   cacheImports,
 	})
 	if err != nil {
 		return nil, err
@@ -516,6 +664,12 @@ func (container *Container) Build(
 	container.FS = newDef
 	container.FS.Source = nil
 
+	// the base image of the Dockerfile isn't known, so the built rootfs has
+	// no base to keep when squashing
+	container.BaseFS = nil
+	container.History = nil
+	container.recordHistory(ctx, false)
+
 	cfgBytes, found := res.Metadata[exptypes.ExporterImageConfigKey]
 	if found {
 		var imgSpec specs.Image
@@ -553,6 +707,7 @@ func (container *Container) WithRootFS(ctx context.Context, dir *Directory) (*Co
 	}
 
 	container.FS = def.ToPB()
+	container.recordHistory(ctx, false)
 
 	container.Services.Merge(dir.Services)
 
@@ -1182,6 +1337,7 @@ func (container *Container) Publish(
 	platformVariants []*Container,
 	forcedCompression ImageLayerCompression,
 	mediaTypes ImageMediaTypes,
+	exportOpts ImageExportOpts,
 ) (string, error) {
 	if mediaTypes == "" {
 		// Modern registry implementations support oci types and docker daemons
@@ -1205,28 +1361,25 @@ func (container *Container) Publish(
 	services := ServiceBindings{}
 
 	variants := append([]*Container{container}, platformVariants...)
-	for _, variant := range variants {
+	for i, variant := range variants {
 		if variant.FS == nil {
 			continue
 		}
-		st, err := variant.FSState()
-		if err != nil {
-			return "", err
-		}
 		platformSpec := variant.Platform.Spec()
-		def, err := st.Marshal(ctx, llb.Platform(platformSpec))
-		if err != nil {
-			return "", err
-		}
-
 		platformString := variant.Platform.Format()
 		if _, ok := inputByPlatform[platformString]; ok {
 			return "", fmt.Errorf("duplicate platform %q", platformString)
 		}
-		inputByPlatform[platformString] = buildkit.ContainerExport{
-			Definition: def.ToPB(),
-			Config:     variant.Config,
+		export := buildkit.ContainerExport{
+			Config: variant.Config,
+		}
+		if err := exportOpts.SetLayers(ctx, &export, variant); err != nil {
+			return "", err
 		}
+		if err := exportOpts.AddAttestations(&export, i, variant.Platform); err != nil {
+			return "", err
+		}
+		inputByPlatform[platformString] = export
 
 		if len(variants) == 1 {
 			// single platform case
@@ -1258,13 +1411,18 @@ func (container *Container) Publish(
 		return "", fmt.Errorf("failed to get buildkit client: %w", err)
 	}
 
+	signer, err := exportOpts.Signer(ctx, container.Query)
+	if err != nil {
+		return "", err
+	}
+
 	detach, _, err := svcs.StartBindings(ctx, services)
 	if err != nil {
 		return "", err
 	}
 	defer detach()
 
-	resp, err := bk.PublishContainerImage(ctx, inputByPlatform, opts)
+	resp, err := bk.PublishContainerImage(ctx, inputByPlatform, opts, signer)
 	if err != nil {
 		return "", err
 	}
@@ -1298,6 +1456,7 @@ func (container *Container) Export(
 	platformVariants []*Container,
 	forcedCompression ImageLayerCompression,
 	mediaTypes ImageMediaTypes,
+	exportOpts ImageExportOpts,
 ) error {
 	svcs, err := container.Query.Services(ctx)
 	if err != nil {
@@ -1333,25 +1492,18 @@ func (container *Container) Export(
 		if variant.FS == nil {
 			continue
 		}
-		st, err := variant.FSState()
-		if err != nil {
-			return err
-		}
-
 		platformSpec := variant.Platform.Spec()
-		def, err := st.Marshal(ctx, llb.Platform(platformSpec))
-		if err != nil {
-			return err
-		}
-
 		platformString := variant.Platform.Format()
 		if _, ok := inputByPlatform[platformString]; ok {
 			return fmt.Errorf("duplicate platform %q", platformString)
 		}
-		inputByPlatform[platformString] = buildkit.ContainerExport{
-			Definition: def.ToPB(),
-			Config:     variant.Config,
+		export := buildkit.ContainerExport{
+			Config: variant.Config,
 		}
+		if err := exportOpts.SetLayers(ctx, &export, variant); err != nil {
+			return err
+		}
+		inputByPlatform[platformString] = export
 
 		if len(variants) == 1 {
 			// single platform case
@@ -1384,50 +1536,258 @@ func (container *Container) Export(
 	return err
 }
 
+// ImageSource is an image archive or an OCI layout directory to import
+// containers from. Exactly one of its fields is set.
+type ImageSource struct {
+	// An OCI or Docker image archive, like the ones produced by "docker save".
+	File *File
+	// A directory in OCI image layout.
+	Directory *Directory
+}
+
+// ImageSelector selects an image in an image archive or OCI layout.
+type ImageSelector struct {
+	// The tag of the image, as annotated in the index of the archive.
+	Tag string
+	// The digest of the image manifest or index.
+	Digest digest.Digest
+}
+
 func (container *Container) Import(
 	ctx context.Context,
-	source *File,
-	tag string,
+	source ImageSource,
+	sel ImageSelector,
 ) (*Container, error) {
-	bk, err := container.Query.Buildkit(ctx)
+	var imported *Container
+	err := container.importImages(ctx, source, func(ctx context.Context, store content.Store, desc specs.Descriptor) error {
+		desc, err := selectImage(ctx, store, desc, sel)
+		if err != nil {
+			return err
+		}
+
+		platform := container.Platform
+		if isImageManifest(desc) {
+			// the image was explicitly selected, so use its own platform
+			platform, err = imagePlatform(ctx, store, desc)
+			if err != nil {
+				return err
+			}
+			if platform.OS == "" {
+				return fmt.Errorf("image %s has no platform", desc.Digest)
+			}
+		} else {
+			desc, err = resolveIndex(ctx, store, desc, platform.Spec())
+			if err != nil {
+				return err
+			}
+		}
+
+		imported, err = container.importManifest(ctx, store, desc, platform)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get buildkit client: %w", err)
+		return nil, err
 	}
+	return imported, nil
+}
+
+// ImportVariants imports every platform variant of the selected image, in the
+// order of its index, skipping attestations and signatures.
+func (container *Container) ImportVariants(
+	ctx context.Context,
+	source ImageSource,
+	sel ImageSelector,
+) ([]*Container, error) {
+	var variants []*Container
+	err := container.importImages(ctx, source, func(ctx context.Context, store content.Store, desc specs.Descriptor) error {
+		desc, err := selectImage(ctx, store, desc, sel)
+		if err != nil {
+			return err
+		}
+
+		manifests, err := imageVariants(ctx, store, desc, map[digest.Digest]bool{})
+		if err != nil {
+			return err
+		}
+		for _, m := range manifests {
+			variant, err := container.importManifest(ctx, store, m, Platform(*m.Platform))
+			if err != nil {
+				return err
+			}
+			variants = append(variants, variant)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+// importImages loads the images of the source into the OCI store and calls fn
+// with the descriptor of their index. The images are only leased until fn
+// returns, so it must evaluate the imported containers.
+func (container *Container) importImages(
+	ctx context.Context,
+	source ImageSource,
+	fn func(context.Context, content.Store, specs.Descriptor) error,
+) error {
 	store := container.Query.OCIStore()
 	lm := container.Query.LeaseManager()
 
-	container = container.Clone()
+	leaseCtx, release, err := leaseutil.WithLease(ctx, lm, leaseutil.MakeTemporary)
+	if err != nil {
+		return err
+	}
+	defer release(context.WithoutCancel(ctx))
 
-	var release func(context.Context) error
-	loadManifest := func(ctx context.Context) (*specs.Descriptor, error) {
-		src, err := source.Open(ctx)
+	var desc specs.Descriptor
+	switch {
+	case source.File != nil:
+		src, err := source.File.Open(ctx)
 		if err != nil {
-			return nil, err
+			return err
 		}
-
 		defer src.Close()
 
-		// override outer ctx with release ctx and set release
-		ctx, release, err = leaseutil.WithLease(ctx, lm, leaseutil.MakeTemporary)
+		stream := archive.NewImageImportStream(src, "")
+		desc, err = stream.Import(leaseCtx, store)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("image archive import: %w", err)
+		}
+	case source.Directory != nil:
+		desc, err = importOCILayout(leaseCtx, store, source.Directory)
+		if err != nil {
+			return fmt.Errorf("OCI layout import: %w", err)
 		}
+	default:
+		return errors.New("no image source")
+	}
 
-		stream := archive.NewImageImportStream(src, "")
+	if err := fn(ctx, store, desc); err != nil {
+		return fmt.Errorf("recover: %w", err)
+	}
+	return nil
+}
+
+// importOCILayout loads the images of an OCI layout directory into the
+// store, returning the descriptor of its index. Only the blobs referenced by
+// the index are loaded; missing blobs are tolerated, since tools often only
+// write the blobs of some platforms.
+func importOCILayout(ctx context.Context, store content.Store, dir *Directory) (specs.Descriptor, error) {
+	bk, err := dir.Query.Buildkit(ctx)
+	if err != nil {
+		return specs.Descriptor{}, fmt.Errorf("failed to get buildkit client: %w", err)
+	}
+	svcs, err := dir.Query.Services(ctx)
+	if err != nil {
+		return specs.Descriptor{}, fmt.Errorf("failed to get services: %w", err)
+	}
+	detach, _, err := svcs.StartBindings(ctx, dir.Services)
+	if err != nil {
+		return specs.Descriptor{}, err
+	}
+	defer detach()
 
-		desc, err := stream.Import(ctx, store)
+	rootFS, err := reffs.OpenDef(ctx, bk, dir.LLB)
+	if err != nil {
+		return specs.Descriptor{}, err
+	}
+	layoutFS := rootFS
+	if dirPath := strings.TrimPrefix(path.Clean(dir.Dir), "/"); dirPath != "" && dirPath != "." {
+		layoutFS, err = fs.Sub(rootFS, dirPath)
 		if err != nil {
-			return nil, fmt.Errorf("image archive import: %w", err)
+			return specs.Descriptor{}, err
 		}
+	}
 
-		return resolveIndex(ctx, store, desc, container.Platform.Spec(), tag)
+	layoutBlob, err := fs.ReadFile(layoutFS, specs.ImageLayoutFile)
+	if err != nil {
+		return specs.Descriptor{}, fmt.Errorf("read %s: %w", specs.ImageLayoutFile, err)
+	}
+	var layout specs.ImageLayout
+	if err := json.Unmarshal(layoutBlob, &layout); err != nil {
+		return specs.Descriptor{}, fmt.Errorf("unmarshal %s: %w", specs.ImageLayoutFile, err)
+	}
+	if layout.Version != specs.ImageLayoutVersion {
+		return specs.Descriptor{}, fmt.Errorf("unsupported OCI layout version %q", layout.Version)
 	}
 
-	manifestDesc, err := loadManifest(ctx)
+	indexBlob, err := fs.ReadFile(layoutFS, specs.ImageIndexFile)
 	if err != nil {
-		return nil, fmt.Errorf("recover: %w", err)
+		return specs.Descriptor{}, fmt.Errorf("read %s: %w", specs.ImageIndexFile, err)
+	}
+	desc := specs.Descriptor{
+		MediaType: specs.MediaTypeImageIndex,
+		Digest:    digest.FromBytes(indexBlob),
+		Size:      int64(len(indexBlob)),
+	}
+	if err := content.WriteBlob(ctx, store, "oci-layout-"+desc.Digest.String(), bytes.NewReader(indexBlob), desc); err != nil {
+		return specs.Descriptor{}, fmt.Errorf("write index: %w", err)
 	}
 
+	var idx specs.Index
+	if err := json.Unmarshal(indexBlob, &idx); err != nil {
+		return specs.Descriptor{}, fmt.Errorf("unmarshal index: %w", err)
+	}
+	for _, m := range idx.Manifests {
+		if err := importOCILayoutBlob(ctx, store, layoutFS, m); err != nil {
+			return specs.Descriptor{}, err
+		}
+	}
+	return desc, nil
+}
+
+// importOCILayoutBlob loads the blob of the descriptor and, for manifests and
+// indexes, the blobs it references into the store.
+func importOCILayoutBlob(ctx context.Context, store content.Store, layoutFS fs.FS, desc specs.Descriptor) error {
+	if err := desc.Digest.Validate(); err != nil {
+		return fmt.Errorf("invalid digest %q: %w", desc.Digest, err)
+	}
+	if _, err := store.Info(ctx, desc.Digest); err != nil {
+		blobPath := path.Join(specs.ImageBlobsDir, desc.Digest.Algorithm().String(), desc.Digest.Encoded())
+		f, err := layoutFS.Open(blobPath)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("open blob %s: %w", desc.Digest, err)
+		}
+		err = content.WriteBlob(ctx, store, "oci-layout-"+desc.Digest.String(), f, desc)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("write blob %s: %w", desc.Digest, err)
+		}
+	}
+
+	children, err := images.Children(ctx, store, desc)
+	if err != nil {
+		return fmt.Errorf("read children of %s: %w", desc.Digest, err)
+	}
+	for _, child := range children {
+		if err := importOCILayoutBlob(ctx, store, layoutFS, child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// importManifest returns the container of the image manifest, which must be
+// leased in the store until this returns.
+func (container *Container) importManifest(
+	ctx context.Context,
+	store content.Store,
+	manifestDesc specs.Descriptor,
+	platform Platform,
+) (*Container, error) {
+	bk, err := container.Query.Buildkit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get buildkit client: %w", err)
+	}
+
+	container = container.Clone()
+	container.Platform = platform
+
 	// NB: the repository portion of this ref doesn't actually matter, but it's
 	// pleasant to see something recognizable.
 	dummyRepo := "dagger/import"
@@ -1435,33 +1795,28 @@ func (container *Container) Import(
 	st := llb.OCILayout(
 		fmt.Sprintf("%s@%s", dummyRepo, manifestDesc.Digest),
 		llb.OCIStore("", buildkit.OCIStoreName),
-		llb.Platform(container.Platform.Spec()),
+		llb.Platform(platform.Spec()),
 		buildkit.WithTracePropagation(ctx),
 	)
 
-	execDef, err := st.Marshal(ctx, llb.Platform(container.Platform.Spec()))
+	execDef, err := st.Marshal(ctx, llb.Platform(platform.Spec()))
 	if err != nil {
 		return nil, fmt.Errorf("marshal root: %w", err)
 	}
 
 	container.FS = execDef.ToPB()
+	container.recordHistory(ctx, true)
 
-	if release != nil {
-		// eagerly evaluate the OCI reference so Buildkit sets up a long-term lease
-		_, err = bk.Solve(ctx, bkgw.SolveRequest{
-			Definition: container.FS,
-			Evaluate:   true,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("solve: %w", err)
-		}
-
-		if err := release(ctx); err != nil {
-			return nil, fmt.Errorf("release: %w", err)
-		}
+	// eagerly evaluate the OCI reference so Buildkit sets up a long-term lease
+	_, err = bk.Solve(ctx, bkgw.SolveRequest{
+		Definition: container.FS,
+		Evaluate:   true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("solve: %w", err)
 	}
 
-	manifestBlob, err := content.ReadBlob(ctx, store, *manifestDesc)
+	manifestBlob, err := content.ReadBlob(ctx, store, manifestDesc)
 	if err != nil {
 		return nil, fmt.Errorf("image archive read manifest blob: %w", err)
 	}
@@ -1489,6 +1844,14 @@ func (container *Container) Import(
 }
 
 func (container *Container) WithExposedPort(port Port) (*Container, error) {
+	if port.HealthcheckPath == "" {
+		if port.HealthcheckScheme != "" || port.HealthcheckStatus != 0 {
+			return nil, fmt.Errorf("health check scheme and status require a health check path")
+		}
+	} else if port.Protocol != NetworkProtocolTCP {
+		return nil, fmt.Errorf("HTTP health checks require a TCP port")
+	}
+
 	container = container.Clone()
 
 	// replace existing port to avoid duplicates
@@ -1588,6 +1951,10 @@ type ContainerAsServiceArgs struct {
 	// Skip the init process injected into containers by default so that the
 	// user's process is PID 1
 	NoInit bool `default:"false"`
+
+	ContainerExecLimits
+
+	ServiceHealthcheckOpts
 }
 
 func (container *Container) AsServiceLegacy(ctx context.Context) (*Service, error) {
@@ -1630,12 +1997,15 @@ func (container *Container) AsService(ctx context.Context, args ContainerAsServi
 		InsecureRootCapabilities:      args.InsecureRootCapabilities,
 		Expand:                        args.Expand,
 		NoInit:                        args.NoInit,
+		ContainerExecLimits:           args.ContainerExecLimits,
 	})
 	if err != nil {
 		return nil, err
 	}
 
-	return container.Query.NewContainerService(ctx, container), nil
+	svc := container.Query.NewContainerService(ctx, container)
+	svc.Healthcheck = args.ServiceHealthcheckOpts
+	return svc, nil
 }
 
 func (container *Container) ownership(ctx context.Context, owner string) (*Ownership, error) {
@@ -1689,14 +2059,48 @@ func (BuildArg) TypeDescription() string {
 	return "Key value object that represents a build argument."
 }
 
+type BuildContext struct {
+	Name      string                      `field:"true" doc:"The name of the build context, as referenced by the Dockerfile (e.g., in \"COPY --from=<name>\" or \"FROM <name>\")."`
+	Directory dagql.Optional[DirectoryID] `field:"true" doc:"The directory to use as the build context."`
+	Container dagql.Optional[ContainerID] `field:"true" doc:"The container to use as the build context, including its image config."`
+}
+
+func (BuildContext) TypeName() string {
+	return "BuildContext"
+}
+
+func (BuildContext) TypeDescription() string {
+	return "A named build context for a Dockerfile build."
+}
+
 // OCI manifest annotation that specifies an image's tag
 const ociTagAnnotation = "org.opencontainers.image.ref.name"
 
-func resolveIndex(ctx context.Context, store content.Store, desc specs.Descriptor, platform specs.Platform, tag string) (*specs.Descriptor, error) {
-	if desc.MediaType != specs.MediaTypeImageIndex {
-		return nil, fmt.Errorf("expected index, got %s", desc.MediaType)
+// Docker manifest annotations of the attestation manifests of an index
+const (
+	dockerReferenceTypeAnnotation = "vnd.docker.reference.type"
+	dockerAttestationManifestType = "attestation-manifest"
+)
+
+func isImageManifest(desc specs.Descriptor) bool {
+	switch desc.MediaType {
+	case specs.MediaTypeImageManifest, // OCI
+		images.MediaTypeDockerSchema2Manifest: // Docker
+		return true
 	}
+	return false
+}
 
+func isImageIndex(desc specs.Descriptor) bool {
+	switch desc.MediaType {
+	case specs.MediaTypeImageIndex, // OCI
+		images.MediaTypeDockerSchema2ManifestList: // Docker
+		return true
+	}
+	return false
+}
+
+func readIndex(ctx context.Context, store content.Provider, desc specs.Descriptor) (*specs.Index, error) {
 	indexBlob, err := content.ReadBlob(ctx, store, desc)
 	if err != nil {
 		return nil, fmt.Errorf("read index blob: %w", err)
@@ -1707,6 +2111,100 @@ func resolveIndex(ctx context.Context, store content.Store, desc specs.Descripto
 	if err != nil {
 		return nil, fmt.Errorf("unmarshal index: %w", err)
 	}
+	return &idx, nil
+}
+
+// selectImage returns the descriptor of the image selected in the index of
+// an archive, or the index itself if no image is selected.
+func selectImage(ctx context.Context, store content.Provider, desc specs.Descriptor, sel ImageSelector) (specs.Descriptor, error) {
+	if sel.Tag != "" {
+		idx, err := readIndex(ctx, store, desc)
+		if err != nil {
+			return specs.Descriptor{}, err
+		}
+		found := false
+		for _, m := range idx.Manifests {
+			if imageHasTag(m, sel.Tag) {
+				desc = m
+				found = true
+				break
+			}
+		}
+		if !found {
+			return specs.Descriptor{}, fmt.Errorf("no image with tag %q", sel.Tag)
+		}
+	}
+
+	if sel.Digest != "" {
+		m, err := findImage(ctx, store, desc, sel.Digest)
+		if err != nil {
+			return specs.Descriptor{}, err
+		}
+		if m == nil {
+			return specs.Descriptor{}, fmt.Errorf("no image with digest %q", sel.Digest)
+		}
+		desc = *m
+	}
+
+	if !isImageManifest(desc) && !isImageIndex(desc) {
+		return specs.Descriptor{}, fmt.Errorf("expected manifest or index, got %s", desc.MediaType)
+	}
+	return desc, nil
+}
+
+// imageHasTag returns whether the annotations of an image descriptor name it
+// with the tag, which is either the tag only (e.g. "latest") or a full image
+// reference (e.g. "alpine:latest" or "docker.io/library/alpine:latest").
+func imageHasTag(desc specs.Descriptor, tag string) bool {
+	names := []string{desc.Annotations[ociTagAnnotation], desc.Annotations[images.AnnotationImageName]}
+	tags := []string{tag}
+	if named, err := reference.ParseNormalizedNamed(tag); err == nil {
+		tags = append(tags, reference.TagNameOnly(named).String())
+	}
+	for _, name := range names {
+		for _, tag := range tags {
+			if name != "" && name == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// findImage returns the descriptor with the digest in the index tree of desc,
+// or nil if there is none.
+func findImage(ctx context.Context, store content.Provider, desc specs.Descriptor, dgst digest.Digest) (*specs.Descriptor, error) {
+	if desc.Digest == dgst {
+		return &desc, nil
+	}
+	if !isImageIndex(desc) {
+		return nil, nil
+	}
+	idx, err := readIndex(ctx, store, desc)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range idx.Manifests {
+		found, err := findImage(ctx, store, m, dgst)
+		if err != nil {
+			return nil, err
+		}
+		if found != nil {
+			return found, nil
+		}
+	}
+	return nil, nil
+}
+
+func resolveIndex(ctx context.Context, store content.Provider, desc specs.Descriptor, platform specs.Platform) (specs.Descriptor, error) {
+	if !isImageIndex(desc) {
+		return specs.Descriptor{}, fmt.Errorf("expected index, got %s", desc.MediaType)
+	}
+
+	idx, err := readIndex(ctx, store, desc)
+	if err != nil {
+		return specs.Descriptor{}, err
+	}
 
 	matcher := platforms.Only(platform)
 
@@ -1718,32 +2216,103 @@ func resolveIndex(ctx context.Context, store content.Store, desc specs.Descripto
 			}
 		}
 
-		if tag != "" {
-			if m.Annotations == nil {
-				continue
-			}
+		switch {
+		case isImageManifest(m):
+			return m, nil
 
-			manifestTag, found := m.Annotations[ociTagAnnotation]
-			if !found || manifestTag != tag {
-				continue
-			}
+		case isImageIndex(m):
+			return resolveIndex(ctx, store, m, platform)
+
+		default:
+			return specs.Descriptor{}, fmt.Errorf("expected manifest or index, got %s", m.MediaType)
 		}
+	}
 
-		switch m.MediaType {
-		case specs.MediaTypeImageManifest, // OCI
-			images.MediaTypeDockerSchema2Manifest: // Docker
-			return &m, nil
+	return specs.Descriptor{}, fmt.Errorf("no manifest for platform %s", platforms.Format(platform))
+}
 
-		case specs.MediaTypeImageIndex, // OCI
-			images.MediaTypeDockerSchema2ManifestList: // Docker
-			return resolveIndex(ctx, store, m, platform, tag)
+// imagePlatform returns the platform of an image manifest, from its
+// descriptor or else its config. It returns an empty platform if the manifest
+// has none, like signature manifests.
+func imagePlatform(ctx context.Context, store content.Provider, desc specs.Descriptor) (Platform, error) {
+	if desc.Platform != nil {
+		return Platform(platforms.Normalize(*desc.Platform)), nil
+	}
 
-		default:
-			return nil, fmt.Errorf("expected manifest or index, got %s", m.MediaType)
-		}
+	manifestBlob, err := content.ReadBlob(ctx, store, desc)
+	if err != nil {
+		return Platform{}, fmt.Errorf("read manifest blob: %w", err)
+	}
+	var man specs.Manifest
+	if err := json.Unmarshal(manifestBlob, &man); err != nil {
+		return Platform{}, fmt.Errorf("unmarshal manifest: %w", err)
+	}
+	switch man.Config.MediaType {
+	case specs.MediaTypeImageConfig, images.MediaTypeDockerSchema2Config:
+	default:
+		return Platform{}, nil
+	}
+	configBlob, err := content.ReadBlob(ctx, store, man.Config)
+	if err != nil {
+		return Platform{}, fmt.Errorf("read image config blob %s: %w", man.Config.Digest, err)
+	}
+	var img specs.Image
+	if err := json.Unmarshal(configBlob, &img); err != nil {
+		return Platform{}, fmt.Errorf("unmarshal image config: %w", err)
+	}
+	if img.OS == "" || img.Architecture == "" {
+		return Platform{}, nil
+	}
+	return Platform(platforms.Normalize(img.Platform)), nil
+}
+
+// imageVariants returns the descriptors of the image manifests of the index
+// tree of desc, with their platform set, skipping the manifests that aren't
+// runnable images like attestations and signatures, and the manifests missing
+// from a content store.
+func imageVariants(ctx context.Context, store content.Provider, desc specs.Descriptor, seen map[digest.Digest]bool) ([]specs.Descriptor, error) {
+	if seen[desc.Digest] || desc.Annotations[dockerReferenceTypeAnnotation] == dockerAttestationManifestType {
+		return nil, nil
 	}
+	seen[desc.Digest] = true
 
-	return nil, fmt.Errorf("no manifest for platform %s and tag %s", platforms.Format(platform), tag)
+	switch {
+	case isImageManifest(desc):
+		if infoProvider, ok := store.(content.InfoProvider); ok {
+			if _, err := infoProvider.Info(ctx, desc.Digest); err != nil {
+				// not included in the archive
+				return nil, nil
+			}
+		}
+		platform, err := imagePlatform(ctx, store, desc)
+		if err != nil {
+			return nil, err
+		}
+		if platform.OS == "" || platform.OS == "unknown" {
+			return nil, nil
+		}
+		spec := platform.Spec()
+		desc.Platform = &spec
+		return []specs.Descriptor{desc}, nil
+
+	case isImageIndex(desc):
+		idx, err := readIndex(ctx, store, desc)
+		if err != nil {
+			return nil, err
+		}
+		var variants []specs.Descriptor
+		for _, m := range idx.Manifests {
+			mVariants, err := imageVariants(ctx, store, m, seen)
+			if err != nil {
+				return nil, err
+			}
+			variants = append(variants, mVariants...)
+		}
+		return variants, nil
+
+	default:
+		return nil, nil
+	}
 }
 
 type ImageLayerCompression string
@@ -8,6 +8,7 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/engine"
@@ -55,6 +56,51 @@ type ContainerExecOpts struct {
 	// Skip the init process injected into containers by default so that the
 	// user's process is PID 1
 	NoInit bool `default:"false"`
+
+	// Network access granted to the command
+	NetworkMode NetworkMode `default:"DEFAULT"`
+
+	ContainerExecLimits
+}
+
+// ContainerExecLimits bounds the time and resources a command may consume.
+type ContainerExecLimits struct {
+	// Number of seconds the command may run for before it's killed
+	Timeout int `default:"0"`
+
+	// Number of CPUs the command may use (e.g. 0.5)
+	CPULimit float64 `name:"cpuLimit" default:"0"`
+
+	// Memory in bytes the command may use before it's killed
+	MemoryLimit int `default:"0"`
+
+	// Number of processes the command may have running at once
+	PidsLimit int `default:"0"`
+}
+
+func (limits ContainerExecLimits) execLimits() (*buildkit.ExecLimits, error) {
+	if limits.Timeout < 0 {
+		return nil, fmt.Errorf("invalid timeout %d: must not be negative", limits.Timeout)
+	}
+	if limits.CPULimit < 0 {
+		return nil, fmt.Errorf("invalid CPU limit %g: must not be negative", limits.CPULimit)
+	}
+	if limits.MemoryLimit < 0 {
+		return nil, fmt.Errorf("invalid memory limit %d: must not be negative", limits.MemoryLimit)
+	}
+	if limits.PidsLimit < 0 {
+		return nil, fmt.Errorf("invalid pids limit %d: must not be negative", limits.PidsLimit)
+	}
+	execLimits := &buildkit.ExecLimits{
+		Timeout:     time.Duration(limits.Timeout) * time.Second,
+		CPUs:        limits.CPULimit,
+		MemoryBytes: int64(limits.MemoryLimit),
+		Pids:        int64(limits.PidsLimit),
+	}
+	if execLimits.IsZero() {
+		return nil, nil
+	}
+	return execLimits, nil
 }
 
 func (container *Container) WithExec(ctx context.Context, opts ContainerExecOpts) (*Container, error) { //nolint:gocyclo
@@ -106,6 +152,31 @@ func (container *Container) WithExec(ctx context.Context, opts ContainerExecOpts
 		runOpts = append(runOpts, llb.AddEnv(buildkit.DaggerNoInitEnv, "true"))
 	}
 
+	execMD.Limits, err = opts.execLimits()
+	if err != nil {
+		return nil, err
+	}
+	if execMD.Limits != nil {
+		// ensure the limits are in the cache key
+		runOpts = append(runOpts, llb.AddEnv(buildkit.DaggerExecLimitsEnv, execMD.Limits.String()))
+	}
+
+	switch opts.NetworkMode {
+	case NetworkModeNone:
+		if len(container.Services) > 0 {
+			return nil, fmt.Errorf("network mode %s does not allow service bindings", opts.NetworkMode)
+		}
+		if opts.ExperimentalPrivilegedNesting {
+			return nil, fmt.Errorf("network mode %s does not allow privileged nesting", opts.NetworkMode)
+		}
+		// the exec op's network mode is part of its cache key already
+		runOpts = append(runOpts, llb.Network(llb.NetModeNone))
+	case NetworkModeServicesOnly:
+		execMD.ServicesOnlyNetwork = true
+		// ensure the network mode is in the cache key
+		runOpts = append(runOpts, llb.AddEnv(buildkit.DaggerNetworkModeEnv, string(opts.NetworkMode)))
+	}
+
 	mod, err := container.Query.CurrentModule(ctx)
 	if err == nil {
 		// allow the exec to reach services scoped to the module that
@@ -310,6 +381,7 @@ func (container *Container) WithExec(ctx context.Context, opts ContainerExecOpts
 	}
 
 	container.FS = execDef.ToPB()
+	container.recordHistory(ctx, false)
 
 	metaDef, err := execSt.GetMount(buildkit.MetaMountDestPath).Marshal(ctx, marshalOpts...)
 	if err != nil {
@@ -0,0 +1,254 @@
+package core
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/moby/buildkit/client/llb"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/call"
+	"github.com/dagger/dagger/engine"
+	"github.com/dagger/dagger/engine/buildkit"
+)
+
+// ImageExportOpts are the layer, signing and attestation options of an image
+// that is published or exported.
+type ImageExportOpts struct {
+	// How to squash the layers of each platform's image.
+	Squash ImageLayerSquash
+	// How to describe the layers of each platform's image in its history.
+	History ImageHistory
+
+	// The private key to sign the image with, in PEM format.
+	SigningKey *Secret
+	// The password of the signing key, if it is encrypted.
+	SigningKeyPassword *Secret
+
+	// Attach an SPDX SBOM of the filesystem of each platform's image.
+	SBOM bool
+	// Attach a SLSA provenance of each platform's image.
+	Provenance bool
+
+	// The call IDs of the container and of its platform variants, in order,
+	// recorded in the provenance.
+	IDs []*call.ID
+}
+
+// Signer returns the signer of the image, or nil if it isn't signed.
+func (opts ImageExportOpts) Signer(ctx context.Context, query *Query) (crypto.Signer, error) {
+	if opts.SigningKey == nil {
+		return nil, nil
+	}
+	secretStore, err := query.Secrets(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret store: %w", err)
+	}
+	key, err := secretStore.GetSecretPlaintext(ctx, opts.SigningKey.IDDigest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signing key: %w", err)
+	}
+	var password []byte
+	if opts.SigningKeyPassword != nil {
+		password, err = secretStore.GetSecretPlaintext(ctx, opts.SigningKeyPassword.IDDigest)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get signing key password: %w", err)
+		}
+	}
+	return buildkit.ParseSigningKey(key, password)
+}
+
+// AddAttestations sets the attestations of the export of the i-th container
+// of an image, the first being the container and the others its platform
+// variants.
+func (opts ImageExportOpts) AddAttestations(export *buildkit.ContainerExport, i int, platform Platform) error {
+	export.SBOM = opts.SBOM
+	if !opts.Provenance {
+		return nil
+	}
+	if i >= len(opts.IDs) {
+		return fmt.Errorf("no call ID for platform %s", platform.Format())
+	}
+	provenance, err := imageProvenance(opts.IDs[i], platform)
+	if err != nil {
+		return fmt.Errorf("failed to generate provenance: %w", err)
+	}
+	export.Provenance = provenance
+	return nil
+}
+
+// SetLayers sets the rootfs definition of the export of a container, with its
+// layers squashed and described as requested.
+func (opts ImageExportOpts) SetLayers(ctx context.Context, export *buildkit.ContainerExport, ctr *Container) error {
+	st, err := ctr.FSState()
+	if err != nil {
+		return err
+	}
+
+	steps := make([]buildkit.ImageHistoryStep, 0, len(ctr.History))
+	for _, step := range ctr.History {
+		steps = append(steps, buildkit.ImageHistoryStep{
+			CreatedBy:  step.Field,
+			Comment:    step.Digest.String(),
+			Definition: step.FS,
+		})
+	}
+
+	// the steps whose layers are squashed into one
+	var squashed []buildkit.ImageHistoryStep
+	switch opts.Squash {
+	case SquashAll, SquashAboveBase:
+		// copying the rootfs to scratch yields a single layer, without the
+		// files that were deleted by later steps
+		st = llb.Scratch().File(
+			llb.Copy(st, "/", "/", &llb.CopyInfo{CopyDirContentsOnly: true}),
+			llb.WithCustomName("squash layers"),
+		)
+		squashed = steps
+		if opts.Squash == SquashAboveBase && ctr.BaseFS != nil {
+			baseSt, err := defToState(ctr.BaseFS)
+			if err != nil {
+				return err
+			}
+			// the diff of the squashed rootfs against the base is a single
+			// layer, with whiteouts for the files deleted from the base
+			st = llb.Merge(
+				[]llb.State{baseSt, llb.Diff(baseSt, st)},
+				llb.WithCustomName("squash layers above base"),
+			)
+			// the first step is the one that created the base
+			if len(steps) > 0 {
+				squashed = steps[1:]
+			}
+		}
+	}
+
+	def, err := st.Marshal(ctx, llb.Platform(ctr.Platform.Spec()))
+	if err != nil {
+		return err
+	}
+	export.Definition = def.ToPB()
+
+	if opts.History != HistoryFields {
+		return nil
+	}
+	if squashed != nil {
+		fields := make([]string, 0, len(squashed))
+		for _, step := range squashed {
+			fields = append(fields, step.CreatedBy)
+		}
+		steps = append(steps[:len(steps)-len(squashed)], buildkit.ImageHistoryStep{
+			CreatedBy:  strings.Join(fields, ", "),
+			Definition: export.Definition,
+		})
+	}
+	export.History = steps
+	return nil
+}
+
+// imageProvenanceBuildType is the SLSA build type of images built by Dagger,
+// whose external parameter is the call ID of the container.
+const imageProvenanceBuildType = "https://dagger.io/provenance/container@v1"
+
+// imageProvenance returns the SLSA provenance predicate of the image of the
+// container with the given call ID.
+func imageProvenance(id *call.ID, platform Platform) ([]byte, error) {
+	encID, err := id.Encode()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]any{
+		"buildDefinition": map[string]any{
+			"buildType": imageProvenanceBuildType,
+			"externalParameters": map[string]any{
+				"call": map[string]string{
+					"digest": id.Digest().String(),
+					"id":     encID,
+				},
+			},
+			"internalParameters": map[string]any{
+				"platform": platform.Format(),
+			},
+		},
+		"runDetails": map[string]any{
+			"builder": map[string]any{
+				"id": "https://dagger.io/engine",
+				"version": map[string]string{
+					"dagger": engine.Version,
+				},
+			},
+			"metadata": map[string]any{
+				"invocationId": id.Digest().String(),
+			},
+		},
+	})
+}
+
+// ImageLayerSquash is how the layers of an image are squashed on export.
+type ImageLayerSquash string
+
+var ImageLayerSquashes = dagql.NewEnum[ImageLayerSquash]()
+
+var (
+	SquashNone = ImageLayerSquashes.Register("NONE",
+		`Keep one layer per operation.`)
+	SquashAll = ImageLayerSquashes.Register("ALL",
+		`Squash all layers into one.`)
+	SquashAboveBase = ImageLayerSquashes.Register("ABOVE_BASE",
+		`Keep the layers of the image the container was created from, and squash the layers above them into one.`)
+)
+
+func (squash ImageLayerSquash) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "ImageLayerSquash",
+		NonNull:   true,
+	}
+}
+
+func (squash ImageLayerSquash) TypeDescription() string {
+	return "How to squash the layers of an exported image."
+}
+
+func (squash ImageLayerSquash) Decoder() dagql.InputDecoder {
+	return ImageLayerSquashes
+}
+
+func (squash ImageLayerSquash) ToLiteral() call.Literal {
+	return ImageLayerSquashes.Literal(squash)
+}
+
+// ImageHistory is how the layers of an image are described in its history on
+// export.
+type ImageHistory string
+
+var ImageHistories = dagql.NewEnum[ImageHistory]()
+
+var (
+	HistoryOperations = ImageHistories.Register("OPERATIONS",
+		`Describe each layer by the operation that created it.`)
+	HistoryFields = ImageHistories.Register("FIELDS",
+		`Describe each layer by the API field that created it, e.g. "withExec", with the digest of its call as comment.`)
+)
+
+func (history ImageHistory) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "ImageHistory",
+		NonNull:   true,
+	}
+}
+
+func (history ImageHistory) TypeDescription() string {
+	return "How to describe the layers of an exported image in its history."
+}
+
+func (history ImageHistory) Decoder() dagql.InputDecoder {
+	return ImageHistories
+}
+
+func (history ImageHistory) ToLiteral() call.Literal {
+	return ImageHistories.Literal(history)
+}
@@ -0,0 +1,140 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/moby/buildkit/client/llb"
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dagger/dagger/engine/buildkit"
+)
+
+func TestContainerRecordHistory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	base := marshalState(t, llb.Image("alpine"))
+	built := marshalState(t, llb.Image("alpine").Run(llb.Shlex("touch /foo")).Root())
+
+	ctr := &Container{FS: built}
+	ctr.recordHistory(ctx, false)
+	require.Nil(t, ctr.BaseFS)
+	require.Len(t, ctr.History, 1)
+
+	// an image drops the previous steps and becomes the base
+	ctr.FS = base
+	ctr.recordHistory(ctx, true)
+	require.Equal(t, base, ctr.BaseFS)
+	require.Equal(t, []ContainerHistory{{FS: base}}, ctr.History)
+
+	ctr.FS = built
+	ctr.recordHistory(ctx, false)
+	require.Equal(t, base, ctr.BaseFS)
+	require.Equal(t, []ContainerHistory{{FS: base}, {FS: built}}, ctr.History)
+
+	// clones don't share their history
+	cp := ctr.Clone()
+	cp.recordHistory(ctx, false)
+	require.Len(t, ctr.History, 2)
+	require.Len(t, cp.History, 3)
+}
+
+func TestImageExportOptsSetLayers(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	baseSt := llb.Image("alpine")
+	base := marshalState(t, baseSt)
+	execed := marshalState(t, baseSt.Run(llb.Shlex("rm /etc/motd")).Root())
+	ctr := &Container{
+		FS:     execed,
+		BaseFS: base,
+		History: []ContainerHistory{
+			{Field: "from", Digest: "sha256:aaa", FS: base},
+			{Field: "withExec", Digest: "sha256:bbb", FS: execed},
+		},
+	}
+
+	t.Run("default", func(t *testing.T) {
+		var export buildkit.ContainerExport
+		require.NoError(t, ImageExportOpts{}.SetLayers(ctx, &export, ctr))
+		require.Equal(t, opCounts(t, execed), opCounts(t, export.Definition))
+		require.Nil(t, export.History)
+	})
+
+	t.Run("fields", func(t *testing.T) {
+		var export buildkit.ContainerExport
+		require.NoError(t, ImageExportOpts{History: HistoryFields}.SetLayers(ctx, &export, ctr))
+		require.Equal(t, []buildkit.ImageHistoryStep{
+			{CreatedBy: "from", Comment: "sha256:aaa", Definition: base},
+			{CreatedBy: "withExec", Comment: "sha256:bbb", Definition: execed},
+		}, export.History)
+	})
+
+	t.Run("squash all", func(t *testing.T) {
+		var export buildkit.ContainerExport
+		require.NoError(t, ImageExportOpts{Squash: SquashAll, History: HistoryFields}.SetLayers(ctx, &export, ctr))
+		ops := opCounts(t, export.Definition)
+		require.Equal(t, 1, ops["file"])
+		require.Zero(t, ops["merge"])
+		require.Equal(t, []buildkit.ImageHistoryStep{
+			{CreatedBy: "from, withExec", Definition: export.Definition},
+		}, export.History)
+	})
+
+	t.Run("squash above base", func(t *testing.T) {
+		var export buildkit.ContainerExport
+		require.NoError(t, ImageExportOpts{Squash: SquashAboveBase, History: HistoryFields}.SetLayers(ctx, &export, ctr))
+		ops := opCounts(t, export.Definition)
+		require.Equal(t, 1, ops["file"])
+		require.Equal(t, 1, ops["diff"])
+		require.Equal(t, 1, ops["merge"])
+		require.Equal(t, []buildkit.ImageHistoryStep{
+			{CreatedBy: "from", Comment: "sha256:aaa", Definition: base},
+			{CreatedBy: "withExec", Definition: export.Definition},
+		}, export.History)
+	})
+
+	t.Run("squash above no base", func(t *testing.T) {
+		noBase := ctr.Clone()
+		noBase.BaseFS = nil
+		var export buildkit.ContainerExport
+		require.NoError(t, ImageExportOpts{Squash: SquashAboveBase}.SetLayers(ctx, &export, noBase))
+		ops := opCounts(t, export.Definition)
+		require.Equal(t, 1, ops["file"])
+		require.Zero(t, ops["merge"])
+		require.Nil(t, export.History)
+	})
+}
+
+func marshalState(t *testing.T, st llb.State) *pb.Definition {
+	t.Helper()
+	def, err := st.Marshal(context.Background())
+	require.NoError(t, err)
+	return def.ToPB()
+}
+
+// opCounts counts the ops of a definition by type.
+func opCounts(t *testing.T, def *pb.Definition) map[string]int {
+	t.Helper()
+	counts := map[string]int{}
+	for _, dt := range def.Def {
+		var op pb.Op
+		require.NoError(t, op.Unmarshal(dt))
+		switch op.Op.(type) {
+		case *pb.Op_Source:
+			counts["source"]++
+		case *pb.Op_Exec:
+			counts["exec"]++
+		case *pb.Op_File:
+			counts["file"]++
+		case *pb.Op_Merge:
+			counts["merge"]++
+		case *pb.Op_Diff:
+			counts["diff"]++
+		}
+	}
+	return counts
+}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/images"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageHasTag(t *testing.T) {
+	t.Parallel()
+
+	// as annotated by the containerd importer for "docker save" archives
+	dockerDesc := specs.Descriptor{Annotations: map[string]string{
+		images.AnnotationImageName: "docker.io/library/alpine:3.20",
+		ociTagAnnotation:           "3.20",
+	}}
+	// as annotated by tools writing the full reference
+	ociDesc := specs.Descriptor{Annotations: map[string]string{
+		ociTagAnnotation: "example.com/foo:v1",
+	}}
+
+	for _, tc := range []struct {
+		desc specs.Descriptor
+		tag  string
+		want bool
+	}{
+		{dockerDesc, "3.20", true},
+		{dockerDesc, "alpine:3.20", true},
+		{dockerDesc, "docker.io/library/alpine:3.20", true},
+		{dockerDesc, "alpine", false},
+		{dockerDesc, "debian:3.20", false},
+		{ociDesc, "example.com/foo:v1", true},
+		{ociDesc, "v1", false},
+		{specs.Descriptor{}, "latest", false},
+	} {
+		require.Equal(t, tc.want, imageHasTag(tc.desc, tc.tag), "%v %s", tc.desc.Annotations, tc.tag)
+	}
+}
+
+func TestSelectImage(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	store, err := local.NewStore(t.TempDir())
+	require.NoError(t, err)
+
+	writeJSON := func(mediaType string, v any) specs.Descriptor {
+		dt, err := json.Marshal(v)
+		require.NoError(t, err)
+		desc := specs.Descriptor{
+			MediaType: mediaType,
+			Digest:    digest.FromBytes(dt),
+			Size:      int64(len(dt)),
+		}
+		require.NoError(t, content.WriteBlob(ctx, store, desc.Digest.String(), bytes.NewReader(dt), desc))
+		return desc
+	}
+
+	manifest := func(os, arch string) specs.Descriptor {
+		config := writeJSON(specs.MediaTypeImageConfig, specs.Image{Platform: specs.Platform{OS: os, Architecture: arch}})
+		desc := writeJSON(specs.MediaTypeImageManifest, specs.Manifest{Config: config})
+		desc.Platform = &specs.Platform{OS: os, Architecture: arch}
+		return desc
+	}
+	amd64 := manifest("linux", "amd64")
+	arm64 := manifest("linux", "arm64")
+
+	attestation := writeJSON(specs.MediaTypeImageManifest, specs.Manifest{
+		Config: writeJSON("application/vnd.in-toto+json", map[string]string{}),
+	})
+	attestation.Platform = &specs.Platform{OS: "unknown", Architecture: "unknown"}
+	attestation.Annotations = map[string]string{
+		dockerReferenceTypeAnnotation: dockerAttestationManifestType,
+		"vnd.docker.reference.digest": amd64.Digest.String(),
+	}
+
+	image := writeJSON(specs.MediaTypeImageIndex, specs.Index{
+		Manifests: []specs.Descriptor{amd64, arm64, attestation},
+	})
+	image.Annotations = map[string]string{ociTagAnnotation: "v1"}
+
+	other := manifest("linux", "s390x")
+	other.Annotations = map[string]string{ociTagAnnotation: "v2"}
+
+	root := writeJSON(specs.MediaTypeImageIndex, specs.Index{
+		Manifests: []specs.Descriptor{image, other},
+	})
+
+	t.Run("no selection", func(t *testing.T) {
+		desc, err := selectImage(ctx, store, root, ImageSelector{})
+		require.NoError(t, err)
+		require.Equal(t, root.Digest, desc.Digest)
+
+		desc, err = resolveIndex(ctx, store, desc, specs.Platform{OS: "linux", Architecture: "arm64"})
+		require.NoError(t, err)
+		require.Equal(t, arm64.Digest, desc.Digest)
+	})
+
+	t.Run("tag", func(t *testing.T) {
+		desc, err := selectImage(ctx, store, root, ImageSelector{Tag: "v1"})
+		require.NoError(t, err)
+		require.Equal(t, image.Digest, desc.Digest)
+
+		desc, err = selectImage(ctx, store, root, ImageSelector{Tag: "v2"})
+		require.NoError(t, err)
+		require.Equal(t, other.Digest, desc.Digest)
+
+		_, err = selectImage(ctx, store, root, ImageSelector{Tag: "v3"})
+		require.EqualError(t, err, `no image with tag "v3"`)
+	})
+
+	t.Run("digest", func(t *testing.T) {
+		desc, err := selectImage(ctx, store, root, ImageSelector{Digest: arm64.Digest})
+		require.NoError(t, err)
+		require.Equal(t, arm64.Digest, desc.Digest)
+
+		platform, err := imagePlatform(ctx, store, desc)
+		require.NoError(t, err)
+		require.Equal(t, "linux/arm64", platform.Format())
+
+		desc, err = selectImage(ctx, store, root, ImageSelector{Tag: "v1", Digest: amd64.Digest})
+		require.NoError(t, err)
+		require.Equal(t, amd64.Digest, desc.Digest)
+
+		_, err = selectImage(ctx, store, root, ImageSelector{Tag: "v2", Digest: arm64.Digest})
+		require.ErrorContains(t, err, "no image with digest")
+	})
+
+	t.Run("variants", func(t *testing.T) {
+		variants, err := imageVariants(ctx, store, root, map[digest.Digest]bool{})
+		require.NoError(t, err)
+		require.Len(t, variants, 3)
+		require.Equal(t, amd64.Digest, variants[0].Digest)
+		require.Equal(t, arm64.Digest, variants[1].Digest)
+		require.Equal(t, other.Digest, variants[2].Digest)
+
+		variants, err = imageVariants(ctx, store, image, map[digest.Digest]bool{})
+		require.NoError(t, err)
+		require.Len(t, variants, 2)
+	})
+
+	t.Run("platform from config", func(t *testing.T) {
+		desc := arm64
+		desc.Platform = nil
+		platform, err := imagePlatform(ctx, store, desc)
+		require.NoError(t, err)
+		require.Equal(t, "linux/arm64", platform.Format())
+
+		platform, err = imagePlatform(ctx, store, specs.Descriptor{
+			MediaType: attestation.MediaType,
+			Digest:    attestation.Digest,
+			Size:      attestation.Size,
+		})
+		require.NoError(t, err)
+		require.Empty(t, platform.OS)
+	})
+}
@@ -1,11 +1,14 @@
 package core
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io/fs"
+	"maps"
 	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -708,6 +711,308 @@ func (dir *Directory) Diff(ctx context.Context, other *Directory) (*Directory, e
 	return dir, nil
 }
 
+// WithPatch applies a unified diff, as produced by diff -u or git diff, to the
+// directory. It fails if any hunk does not apply cleanly.
+func (dir *Directory) WithPatch(ctx context.Context, patch *File) (*Directory, error) {
+	contents, err := patch.Contents(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read patch: %w", err)
+	}
+	filePatches, err := parsePatch(contents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse patch: %w", err)
+	}
+	if len(filePatches) == 0 {
+		return nil, fmt.Errorf("patch does not contain any file changes")
+	}
+
+	dir = dir.Clone()
+
+	type patchedFile struct {
+		contents []byte
+		mode     fs.FileMode
+		owner    *Ownership
+	}
+	// the resulting files by path, nil for deleted files; a file changed more
+	// than once is patched on top of its earlier changes
+	patched := map[string]*patchedFile{}
+	for _, fp := range filePatches {
+		var orig []byte
+		result := &patchedFile{mode: 0o644}
+		if fp.OldPath != "" {
+			if prev, ok := patched[fp.OldPath]; ok {
+				if prev == nil {
+					return nil, fmt.Errorf("failed to apply patch to %s: file was deleted earlier in the patch", fp.OldPath)
+				}
+				orig = prev.contents
+				result.mode, result.owner = prev.mode, prev.owner
+			} else {
+				file := &File{
+					Query:    dir.Query,
+					LLB:      dir.LLB,
+					File:     path.Join(dir.Dir, fp.OldPath),
+					Platform: dir.Platform,
+					Services: dir.Services,
+				}
+				stat, err := file.Stat(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("failed to apply patch to %s: %w", fp.OldPath, err)
+				}
+				orig, err = file.Contents(ctx)
+				if err != nil {
+					return nil, fmt.Errorf("failed to apply patch to %s: %w", fp.OldPath, err)
+				}
+				result.mode = fs.FileMode(stat.Mode).Perm()
+				result.owner = &Ownership{UID: int(stat.Uid), GID: int(stat.Gid)}
+			}
+		}
+		if fp.Mode != 0 {
+			result.mode = fp.Mode
+		}
+
+		name := fp.NewPath
+		if name == "" {
+			name = fp.OldPath
+		}
+		result.contents, err = fp.apply(orig)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply patch to %s: %w", name, err)
+		}
+
+		if fp.OldPath != "" && fp.OldPath != fp.NewPath {
+			patched[fp.OldPath] = nil
+		}
+		if fp.NewPath != "" {
+			patched[fp.NewPath] = result
+		}
+	}
+
+	st, err := dir.State()
+	if err != nil {
+		return nil, err
+	}
+
+	var action *llb.FileAction
+	for _, name := range slices.Sorted(maps.Keys(patched)) {
+		dest := path.Join(dir.Dir, name)
+		// remove the original first so that mode changes are applied
+		rm := llb.Rm(dest, llb.WithAllowNotFound(true))
+		if action == nil {
+			action = rm
+		} else {
+			action = action.Rm(dest, llb.WithAllowNotFound(true))
+		}
+		file := patched[name]
+		if file == nil {
+			continue
+		}
+		opts := []llb.MkfileOption{}
+		if file.owner != nil {
+			opts = append(opts, file.owner.Opt())
+		}
+		action = action.
+			Mkdir(path.Dir(dest), 0755, llb.WithParents(true)).
+			Mkfile(dest, file.mode, file.contents, opts...)
+	}
+
+	err = dir.SetState(ctx, st.File(action))
+	if err != nil {
+		return nil, err
+	}
+
+	return dir, nil
+}
+
+// AsPatch returns a unified diff of the regular files in this directory and
+// the other one, in the format of git diff.
+func (dir *Directory) AsPatch(ctx context.Context, other *Directory) (*File, error) {
+	oldFiles, err := dir.walkFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	newFiles, err := other.walkFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := map[string]struct{}{}
+	for name, stat := range oldFiles {
+		if fs.FileMode(stat.Mode).IsRegular() {
+			names[name] = struct{}{}
+		}
+	}
+	for name, stat := range newFiles {
+		if fs.FileMode(stat.Mode).IsRegular() {
+			names[name] = struct{}{}
+		}
+	}
+
+	var buf bytes.Buffer
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		oldContents, oldMode, err := dir.regularFileContents(ctx, name, oldFiles[name])
+		if err != nil {
+			return nil, err
+		}
+		newContents, newMode, err := other.regularFileContents(ctx, name, newFiles[name])
+		if err != nil {
+			return nil, err
+		}
+		writeFilePatch(&buf, name, oldContents, newContents, oldMode, newMode)
+	}
+
+	return NewFileWithContents(ctx, dir.Query, "changes.patch", buf.Bytes(), 0o644, nil, dir.Platform)
+}
+
+// regularFileContents reads the file at the given path if stat describes a
+// regular file, returning nil contents otherwise.
+func (dir *Directory) regularFileContents(ctx context.Context, name string, stat *fstypes.Stat) ([]byte, fs.FileMode, error) {
+	if stat == nil || !fs.FileMode(stat.Mode).IsRegular() {
+		return nil, 0, nil
+	}
+	file := &File{
+		Query:    dir.Query,
+		LLB:      dir.LLB,
+		File:     path.Join(dir.Dir, name),
+		Platform: dir.Platform,
+		Services: dir.Services,
+	}
+	contents, err := file.Contents(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return contents, fs.FileMode(stat.Mode).Perm(), nil
+}
+
+// Merge returns this directory with the contents of the others layered on
+// top, failing if the same path holds different contents in more than one
+// of them.
+func (dir *Directory) Merge(ctx context.Context, others []*Directory) (*Directory, error) {
+	sources := append([]*Directory{dir}, others...)
+	sourceName := func(i int) string {
+		if i == 0 {
+			return "this directory"
+		}
+		return fmt.Sprintf("directories[%d]", i-1)
+	}
+
+	type owner struct {
+		source int
+		stat   *fstypes.Stat
+	}
+	owners := map[string]owner{}
+	var conflicts []string
+	for i, src := range sources {
+		files, err := src.walkFiles(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range slices.Sorted(maps.Keys(files)) {
+			stat := files[name]
+			prev, ok := owners[name]
+			if !ok {
+				owners[name] = owner{source: i, stat: stat}
+				continue
+			}
+			same, err := sameMergeEntry(ctx, sources[prev.source], prev.stat, src, stat, name)
+			if err != nil {
+				return nil, err
+			}
+			if !same {
+				conflicts = append(conflicts, fmt.Sprintf("%s (%s and %s)", name, sourceName(prev.source), sourceName(i)))
+			}
+		}
+	}
+	if len(conflicts) > 0 {
+		return nil, fmt.Errorf("merge conflict: paths differ between directories: %s", strings.Join(conflicts, ", "))
+	}
+
+	var err error
+	for _, other := range others {
+		dir, err = dir.WithDirectory(ctx, "/", other, CopyFilter{}, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return dir, nil
+}
+
+// sameMergeEntry reports whether a path can be merged from two directories
+// without either side overwriting the other.
+func sameMergeEntry(ctx context.Context, a *Directory, aStat *fstypes.Stat, b *Directory, bStat *fstypes.Stat, name string) (bool, error) {
+	aMode, bMode := fs.FileMode(aStat.Mode), fs.FileMode(bStat.Mode)
+	if aMode.Type() != bMode.Type() {
+		return false, nil
+	}
+	switch {
+	case aMode.IsDir():
+		return true, nil
+	case aMode&fs.ModeSymlink != 0:
+		return aStat.Linkname == bStat.Linkname, nil
+	case aMode.IsRegular():
+		if aMode.Perm() != bMode.Perm() || aStat.Size_ != bStat.Size_ {
+			return false, nil
+		}
+		aContents, _, err := a.regularFileContents(ctx, name, aStat)
+		if err != nil {
+			return false, err
+		}
+		bContents, _, err := b.regularFileContents(ctx, name, bStat)
+		if err != nil {
+			return false, err
+		}
+		return bytes.Equal(aContents, bContents), nil
+	default:
+		return false, nil
+	}
+}
+
+// walkFiles returns the stat of every path in the directory, relative to it.
+func (dir *Directory) walkFiles(ctx context.Context) (map[string]*fstypes.Stat, error) {
+	svcs, err := dir.Query.Services(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get services: %w", err)
+	}
+	bk, err := dir.Query.Buildkit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get buildkit client: %w", err)
+	}
+
+	detach, _, err := svcs.StartBindings(ctx, dir.Services)
+	if err != nil {
+		return nil, err
+	}
+	defer detach()
+
+	res, err := bk.Solve(ctx, bkgw.SolveRequest{
+		Definition: dir.LLB,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ref, err := res.SingleRef()
+	if err != nil {
+		return nil, err
+	}
+	files := map[string]*fstypes.Stat{}
+	// empty directory, i.e. llb.Scratch()
+	if ref == nil {
+		return files, nil
+	}
+
+	err = ref.WalkDir(ctx, buildkit.WalkDirRequest{
+		Path: dir.Dir,
+		Callback: func(path string, info *fstypes.Stat) error {
+			files[filepath.ToSlash(path)] = info
+			return nil
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
 func (dir *Directory) Without(ctx context.Context, paths ...string) (*Directory, error) {
 	dir = dir.Clone()
 
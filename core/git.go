@@ -2,11 +2,15 @@ package core
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/moby/buildkit/client/llb"
 	"github.com/pkg/errors"
 	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/dagger/dagger/engine"
 	"github.com/dagger/dagger/engine/sources/gitdns"
@@ -45,6 +49,9 @@ type GitRef struct {
 
 	Ref  string         `json:"ref"`
 	Repo *GitRepository `json:"repository"`
+
+	// Signature is the signature of the ref verified by Verify.
+	Signature *GitSignature `json:"signature"`
 }
 
 func (*GitRef) Type() *ast.Type {
@@ -58,8 +65,16 @@ func (*GitRef) TypeDescription() string {
 	return "A git ref (tag, branch, or commit)."
 }
 
-func (ref *GitRef) Tree(ctx context.Context, discardGitDir bool) (*Directory, error) {
-	st, err := ref.getState(ctx, ref.Repo.DiscardGitDir || discardGitDir)
+func (ref *GitRef) Tree(ctx context.Context, discardGitDir bool, checkout gitdns.CheckoutOpts) (*Directory, error) {
+	for _, pattern := range checkout.SparseCheckout {
+		if strings.TrimSpace(pattern) == "" || strings.Contains(pattern, "\n") {
+			return nil, fmt.Errorf("invalid sparse checkout pattern %q", pattern)
+		}
+	}
+	if checkout.Depth < 1 && !checkout.FullHistory {
+		return nil, fmt.Errorf("depth must be at least 1, got %d", checkout.Depth)
+	}
+	st, err := ref.getState(ctx, ref.Repo.DiscardGitDir || discardGitDir, checkout)
 	if err != nil {
 		return nil, err
 	}
@@ -71,7 +86,7 @@ func (ref *GitRef) Commit(ctx context.Context) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("failed to get buildkit client: %w", err)
 	}
-	st, err := ref.getState(ctx, true)
+	st, err := ref.getState(ctx, true, gitdns.CheckoutOpts{})
 	if err != nil {
 		return "", err
 	}
@@ -85,7 +100,145 @@ func (ref *GitRef) Commit(ctx context.Context) (string, error) {
 	return p.Sources.Git[0].Commit, nil
 }
 
-func (ref *GitRef) getState(ctx context.Context, discardGitDir bool) (llb.State, error) {
+// CommitInfo returns the metadata of the commit the ref resolves to.
+func (ref *GitRef) CommitInfo(ctx context.Context) (*GitCommitInfo, error) {
+	md, err := ref.metadata(ctx, gitdns.MetadataRequest{Kind: gitdns.MetadataCommit})
+	if err != nil {
+		return nil, err
+	}
+	if len(md.Commits) != 1 {
+		return nil, errors.Errorf("expected 1 commit, got %d", len(md.Commits))
+	}
+	return newGitCommitInfo(md.Commits[0]), nil
+}
+
+// Log returns the commits reachable from the ref, newest first, excluding
+// those reachable from the from ref if set, and limited to the commits that
+// touch the given paths if any.
+func (ref *GitRef) Log(ctx context.Context, from *GitRef, paths []string) ([]*GitCommitInfo, error) {
+	req := gitdns.MetadataRequest{
+		Kind:  gitdns.MetadataLog,
+		Paths: paths,
+	}
+	if from != nil {
+		fromCommit, err := from.Commit(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %q: %w", from.Ref, err)
+		}
+		req.From = fromCommit
+	}
+	md, err := ref.metadata(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	commits := make([]*GitCommitInfo, 0, len(md.Commits))
+	for _, commit := range md.Commits {
+		commits = append(commits, newGitCommitInfo(commit))
+	}
+	return commits, nil
+}
+
+// Describe returns the nearest tag reachable from the ref, in the format of
+// git describe --tags.
+func (ref *GitRef) Describe(ctx context.Context) (string, error) {
+	md, err := ref.metadata(ctx, gitdns.MetadataRequest{Kind: gitdns.MetadataDescribe})
+	if err != nil {
+		return "", err
+	}
+	return md.Describe, nil
+}
+
+// Verify verifies the signature of the annotated tag or commit the ref
+// resolves to against the GPG keys and the SSH allowed signers, and returns
+// the ref pinned to the verified commit.
+func (ref *GitRef) Verify(ctx context.Context, keys [][]byte, allowedSigners string) (*GitRef, error) {
+	if len(keys) == 0 && allowedSigners == "" {
+		return nil, errors.New("at least one key or an allowed signers file is required")
+	}
+	commit, err := ref.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	md, err := ref.metadataAt(ctx, commit, gitdns.MetadataRequest{
+		Kind:           gitdns.MetadataVerify,
+		Ref:            ref.Ref,
+		Keys:           keys,
+		AllowedSigners: allowedSigners,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if md.Verification == nil {
+		return nil, errors.Errorf("no signature was verified")
+	}
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("dagger.io/git.signer", md.Verification.Signer),
+		attribute.String("dagger.io/git.signer.key", md.Verification.Key),
+	)
+
+	verified := *ref
+	verified.Ref = commit
+	verified.Signature = &GitSignature{
+		Format: md.Verification.Format,
+		Signer: md.Verification.Signer,
+		Key:    md.Verification.Key,
+	}
+	return &verified, nil
+}
+
+// metadata reads metadata of the resolved commit from the git source, so
+// that it's cached by commit.
+func (ref *GitRef) metadata(ctx context.Context, req gitdns.MetadataRequest) (*gitdns.Metadata, error) {
+	commit, err := ref.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ref.metadataAt(ctx, commit, req)
+}
+
+func (ref *GitRef) metadataAt(ctx context.Context, commit string, req gitdns.MetadataRequest) (*gitdns.Metadata, error) {
+	opts, err := ref.gitOpts(true)
+	if err != nil {
+		return nil, err
+	}
+	clientMetadata, err := engine.ClientMetadataFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	st, err := gitdns.GitMetadata(ref.Repo.URL, commit, clientMetadata.SessionID, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	file, err := NewFileSt(ctx, ref.Query, st, gitdns.MetadataFilename, ref.Repo.Platform, ref.Repo.Services)
+	if err != nil {
+		return nil, err
+	}
+	dt, err := file.Contents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var md gitdns.Metadata
+	if err := json.Unmarshal(dt, &md); err != nil {
+		return nil, fmt.Errorf("failed to decode git metadata: %w", err)
+	}
+	return &md, nil
+}
+
+func (ref *GitRef) getState(ctx context.Context, discardGitDir bool, checkout gitdns.CheckoutOpts) (llb.State, error) {
+	opts, err := ref.gitOpts(discardGitDir)
+	if err != nil {
+		return llb.State{}, err
+	}
+
+	clientMetadata, err := engine.ClientMetadataFromContext(ctx)
+	if err != nil {
+		return llb.State{}, err
+	}
+
+	return gitdns.Git(ref.Repo.URL, ref.Ref, clientMetadata.SessionID, checkout, opts...), nil
+}
+
+func (ref *GitRef) gitOpts(discardGitDir bool) ([]llb.GitOption, error) {
 	opts := []llb.GitOption{}
 
 	if !discardGitDir {
@@ -104,10 +257,78 @@ func (ref *GitRef) getState(ctx context.Context, discardGitDir bool) (llb.State,
 		opts = append(opts, llb.AuthHeaderSecret(ref.Repo.AuthHeader.LLBID()))
 	}
 
-	clientMetadata, err := engine.ClientMetadataFromContext(ctx)
-	if err != nil {
-		return llb.State{}, err
+	return opts, nil
+}
+
+type GitCommitInfo struct {
+	SHA       string    `field:"true" name:"sha" doc:"The SHA of the commit."`
+	Parents   []string  `field:"true" doc:"The SHAs of the parents of the commit."`
+	Author    *GitActor `field:"true" doc:"The author of the commit."`
+	Committer *GitActor `field:"true" doc:"The committer of the commit."`
+	Message   string    `field:"true" doc:"The full message of the commit."`
+	Date      string    `field:"true" doc:"The date the commit was authored, in RFC 3339 format."`
+}
+
+func newGitCommitInfo(commit gitdns.Commit) *GitCommitInfo {
+	return &GitCommitInfo{
+		SHA:       commit.SHA,
+		Parents:   commit.Parents,
+		Author:    newGitActor(commit.Author),
+		Committer: newGitActor(commit.Committer),
+		Message:   commit.Message,
+		Date:      commit.Author.Date,
 	}
+}
+
+func (*GitCommitInfo) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "GitCommitInfo",
+		NonNull:   true,
+	}
+}
+
+func (*GitCommitInfo) TypeDescription() string {
+	return "The metadata of a git commit."
+}
+
+type GitActor struct {
+	Name  string `field:"true" doc:"The name of the person."`
+	Email string `field:"true" doc:"The email address of the person."`
+	Date  string `field:"true" doc:"The date of the action, in RFC 3339 format."`
+}
+
+func newGitActor(sig gitdns.Signature) *GitActor {
+	return &GitActor{
+		Name:  sig.Name,
+		Email: sig.Email,
+		Date:  sig.Date,
+	}
+}
+
+func (*GitActor) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "GitActor",
+		NonNull:   true,
+	}
+}
+
+func (*GitActor) TypeDescription() string {
+	return "The author or committer of a git commit."
+}
+
+type GitSignature struct {
+	Format string `field:"true" doc:"The format of the signature, \"gpg\" or \"ssh\"."`
+	Signer string `field:"true" doc:"The identity of the signer: the user ID of the GPG key, or the principal of the SSH key in the allowed signers."`
+	Key    string `field:"true" doc:"The fingerprint of the signing key."`
+}
+
+func (*GitSignature) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "GitSignature",
+		NonNull:   true,
+	}
+}
 
-	return gitdns.Git(ref.Repo.URL, ref.Ref, clientMetadata.SessionID, opts...), nil
+func (*GitSignature) TypeDescription() string {
+	return "A verified signature of a git tag or commit."
 }
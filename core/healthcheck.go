@@ -1,28 +1,73 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"io"
 	"net"
+	"net/http"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
+	bkgw "github.com/moby/buildkit/frontend/gateway/client"
 
 	"dagger.io/dagger/telemetry"
 	"github.com/dagger/dagger/engine/buildkit"
 	"github.com/dagger/dagger/engine/slog"
 )
 
-type portHealthChecker struct {
+const (
+	// timeout for each attempt at dialing a port, if not configured
+	defaultPortCheckTimeout = time.Second
+	// timeout for each HTTP or exec probe, if not configured
+	defaultProbeTimeout = 10 * time.Second
+	// how much of a probe's output to include in its error
+	probeOutputLimit = 4096
+)
+
+// ServiceHealthcheckOpts configures how a service's health is checked when it
+// starts.
+type ServiceHealthcheckOpts struct {
+	// Command to run in the service container to check that it's healthy
+	HealthcheckExec []string `default:"[]"`
+
+	// Number of seconds to wait between health check attempts
+	HealthcheckInterval int `default:"0"`
+
+	// Number of seconds each health check attempt may take
+	HealthcheckTimeout int `default:"0"`
+
+	// Number of times to retry a failing health check before giving up
+	HealthcheckRetries int `default:"0"`
+}
+
+func (opts ServiceHealthcheckOpts) Clone() ServiceHealthcheckOpts {
+	cp := opts
+	cp.HealthcheckExec = cloneSlice(cp.HealthcheckExec)
+	return cp
+}
+
+type healthChecker struct {
 	bk    *buildkit.Client
 	ns    buildkit.Namespaced
 	host  string
 	ports []Port
+
+	opts ServiceHealthcheckOpts
+
+	// container and process settings to run HealthcheckExec with, if set
+	ctr      bkgw.Container
+	probeReq bkgw.StartRequest
 }
 
-func newHealth(bk *buildkit.Client, ns buildkit.Namespaced, host string, ports []Port) *portHealthChecker {
-	return &portHealthChecker{
+func newHealth(bk *buildkit.Client, ns buildkit.Namespaced, host string, ports []Port) *healthChecker {
+	return &healthChecker{
 		bk:    bk,
 		ns:    ns,
 		host:  host,
@@ -30,53 +75,48 @@ func newHealth(bk *buildkit.Client, ns buildkit.Namespaced, host string, ports [
 	}
 }
 
-func (d *portHealthChecker) Check(ctx context.Context) (rerr error) {
+// withExec configures the checker to also run the exec probe from opts in the
+// given container, once its ports are healthy.
+func (d *healthChecker) withExec(opts ServiceHealthcheckOpts, ctr bkgw.Container, req bkgw.StartRequest) *healthChecker {
+	d.opts = opts
+	d.ctr = ctr
+	d.probeReq = req
+	return d
+}
+
+func (d *healthChecker) Check(ctx context.Context) (rerr error) {
 	ports := make([]Port, 0, len(d.ports))
-	portStrs := make([]string, 0, len(d.ports))
+	checkStrs := make([]string, 0, len(d.ports)+1)
 	for _, port := range d.ports {
 		if !port.ExperimentalSkipHealthcheck {
 			ports = append(ports, port)
-			portStrs = append(portStrs, fmt.Sprintf("%d/%s", port.Port, port.Protocol.Network()))
+			checkStrs = append(checkStrs, fmt.Sprintf("%d/%s", port.Port, port.Protocol.Network()))
 		}
 	}
-	if len(ports) == 0 {
+	probeExec := len(d.opts.HealthcheckExec) > 0 && d.ctr != nil
+	if probeExec {
+		checkStrs = append(checkStrs, strings.Join(d.opts.HealthcheckExec, " "))
+	}
+	if len(checkStrs) == 0 {
 		return nil
 	}
 
 	// always show health checks
-	ctx, span := Tracer(ctx).Start(ctx, strings.Join(portStrs, " "))
+	ctx, span := Tracer(ctx).Start(ctx, strings.Join(checkStrs, " "))
 	defer telemetry.End(span, func() error { return rerr })
 
 	slog := slog.SpanLogger(ctx, InstrumentationLibrary).With("host", d.host)
 
-	dialer := net.Dialer{
-		Timeout: time.Second,
-	}
-
 	for _, port := range ports {
-		retry := backoff.NewExponentialBackOff(
-			backoff.WithInitialInterval(100*time.Millisecond),
-			backoff.WithMaxInterval(10*time.Second),
-		)
+		start := time.Now()
 		endpoint, err := backoff.RetryWithData(func() (string, error) {
-			return buildkit.RunInNetNS(ctx, d.bk, d.ns, func() (string, error) {
-				// NB(vito): it's a _little_ silly to dial a UDP network to see that it's
-				// up, since it'll be a false positive even if they're not listening yet,
-				// but it at least checks that we're able to resolve the container address.
-				conn, err := dialer.Dial(
-					port.Protocol.Network(),
-					net.JoinHostPort(d.host, fmt.Sprintf("%d", port.Port)),
-				)
-				if err != nil {
-					slog.Warn("port not ready", "error", err, "elapsed", retry.GetElapsedTime())
-					return "", err
-				}
-
-				endpoint := conn.RemoteAddr().String()
-				_ = conn.Close()
-				return endpoint, nil
-			})
-		}, backoff.WithContext(retry, ctx))
+			endpoint, err := d.checkEndpoint(ctx, port)
+			if err != nil {
+				slog.Warn("port not ready", "error", err, "elapsed", time.Since(start))
+				return "", err
+			}
+			return endpoint, nil
+		}, d.backoff(ctx))
 		if err != nil {
 			return fmt.Errorf("checking for port %d/%s: %w", port.Port, port.Protocol.Network(), err)
 		}
@@ -84,5 +124,225 @@ func (d *portHealthChecker) Check(ctx context.Context) (rerr error) {
 		slog.Info("port is healthy", "endpoint", endpoint)
 	}
 
+	if probeExec {
+		start := time.Now()
+		err := backoff.Retry(func() error {
+			err := d.checkExec(ctx)
+			if err != nil {
+				slog.Warn("health check command failed", "error", err, "elapsed", time.Since(start))
+			}
+			return err
+		}, d.backoff(ctx))
+		if err != nil {
+			return fmt.Errorf("checking with %q: %w", strings.Join(d.opts.HealthcheckExec, " "), err)
+		}
+
+		slog.Info("health check command succeeded")
+	}
+
+	return nil
+}
+
+// Probe runs each check once, without retrying, to see whether a service
+// that has already started is still healthy.
+func (d *healthChecker) Probe(ctx context.Context) error {
+	for _, port := range d.ports {
+		if port.ExperimentalSkipHealthcheck {
+			continue
+		}
+		if _, err := d.checkEndpoint(ctx, port); err != nil {
+			return fmt.Errorf("checking for port %d/%s: %w", port.Port, port.Protocol.Network(), err)
+		}
+	}
+	if len(d.opts.HealthcheckExec) > 0 && d.ctr != nil {
+		if err := d.checkExec(ctx); err != nil {
+			return fmt.Errorf("checking with %q: %w", strings.Join(d.opts.HealthcheckExec, " "), err)
+		}
+	}
+	return nil
+}
+
+func (d *healthChecker) backoff(ctx context.Context) backoff.BackOffContext {
+	opts := []backoff.ExponentialBackOffOpts{
+		backoff.WithInitialInterval(100 * time.Millisecond),
+		backoff.WithMaxInterval(10 * time.Second),
+	}
+	if d.opts.HealthcheckInterval > 0 {
+		interval := time.Duration(d.opts.HealthcheckInterval) * time.Second
+		opts = []backoff.ExponentialBackOffOpts{
+			backoff.WithInitialInterval(interval),
+			backoff.WithMaxInterval(interval),
+			backoff.WithMultiplier(1),
+			backoff.WithRandomizationFactor(0),
+		}
+	}
+	var b backoff.BackOff = backoff.NewExponentialBackOff(opts...)
+	if d.opts.HealthcheckRetries > 0 {
+		b = backoff.WithMaxRetries(b, uint64(d.opts.HealthcheckRetries))
+	}
+	return backoff.WithContext(b, ctx)
+}
+
+func (d *healthChecker) timeout(def time.Duration) time.Duration {
+	if d.opts.HealthcheckTimeout > 0 {
+		return time.Duration(d.opts.HealthcheckTimeout) * time.Second
+	}
+	return def
+}
+
+func (d *healthChecker) checkEndpoint(ctx context.Context, port Port) (string, error) {
+	if port.HealthcheckPath != "" {
+		return d.checkHTTP(ctx, port)
+	}
+	return d.checkPort(ctx, port)
+}
+
+func (d *healthChecker) checkPort(ctx context.Context, port Port) (string, error) {
+	dialer := net.Dialer{
+		Timeout: d.timeout(defaultPortCheckTimeout),
+	}
+	return buildkit.RunInNetNS(ctx, d.bk, d.ns, func() (string, error) {
+		// NB(vito): it's a _little_ silly to dial a UDP network to see that it's
+		// up, since it'll be a false positive even if they're not listening yet,
+		// but it at least checks that we're able to resolve the container address.
+		conn, err := dialer.Dial(
+			port.Protocol.Network(),
+			net.JoinHostPort(d.host, fmt.Sprintf("%d", port.Port)),
+		)
+		if err != nil {
+			return "", err
+		}
+
+		endpoint := conn.RemoteAddr().String()
+		_ = conn.Close()
+		return endpoint, nil
+	})
+}
+
+func (d *healthChecker) checkHTTP(ctx context.Context, port Port) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.timeout(defaultProbeTimeout))
+	defer cancel()
+
+	addr := net.JoinHostPort(d.host, fmt.Sprintf("%d", port.Port))
+
+	// the connection has to be dialed from within the network namespace, but
+	// the HTTP client dials from its own goroutine, so dial it up front
+	var dialer net.Dialer
+	conn, err := buildkit.RunInNetNS(ctx, d.bk, d.ns, func() (net.Conn, error) {
+		return dialer.DialContext(ctx, "tcp", addr)
+	})
+	if err != nil {
+		return "", err
+	}
+	var dialed sync.Once
+	// close the connection if the client never got to use it
+	defer dialed.Do(func() { conn.Close() })
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(context.Context, string, string) (net.Conn, error) {
+				var c net.Conn
+				dialed.Do(func() { c = conn })
+				if c == nil {
+					return nil, errors.New("health check connection already used")
+				}
+				return c, nil
+			},
+			DisableKeepAlives: true,
+			// services commonly use self-signed certificates
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	defer client.CloseIdleConnections()
+
+	scheme := port.HealthcheckScheme.URLScheme()
+	path := port.HealthcheckPath
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	url := scheme + "://" + addr + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if port.HealthcheckStatus != 0 {
+		if resp.StatusCode == port.HealthcheckStatus {
+			return url, nil
+		}
+	} else if resp.StatusCode >= 200 && resp.StatusCode < 400 {
+		return url, nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, probeOutputLimit))
+	return "", fmt.Errorf("GET %s: unexpected status %s: %s", url, resp.Status, bytes.TrimSpace(body))
+}
+
+func (d *healthChecker) checkExec(ctx context.Context) error {
+	timeout := d.timeout(defaultProbeTimeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	output := &probeOutput{}
+	req := d.probeReq
+	req.Args = d.opts.HealthcheckExec
+	req.Tty = false
+	req.Stdin = nil
+	req.Stdout = output
+	req.Stderr = output
+	proc, err := d.ctr.Start(ctx, req)
+	if err != nil {
+		return fmt.Errorf("start: %w", err)
+	}
+
+	waited := make(chan error, 1)
+	go func() {
+		waited <- proc.Wait()
+	}()
+	select {
+	case err = <-waited:
+	case <-ctx.Done():
+		_ = proc.Signal(context.WithoutCancel(ctx), syscall.SIGKILL)
+		<-waited
+		err = fmt.Errorf("timed out after %s", timeout)
+	}
+	if err != nil {
+		if out := output.String(); out != "" {
+			return fmt.Errorf("%w: %s", err, out)
+		}
+		return err
+	}
+	return nil
+}
+
+// probeOutput collects the tail of a probe's stdout and stderr.
+type probeOutput struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (o *probeOutput) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.buf = append(o.buf, p...)
+	if over := len(o.buf) - probeOutputLimit; over > 0 {
+		o.buf = o.buf[over:]
+	}
+	return len(p), nil
+}
+
+func (o *probeOutput) Close() error {
 	return nil
 }
+
+func (o *probeOutput) String() string {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return string(bytes.TrimSpace(o.buf))
+}
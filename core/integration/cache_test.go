@@ -6,11 +6,14 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/moby/buildkit/identity"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"dagger.io/dagger"
+	"github.com/dagger/dagger/dagql/call"
 	"github.com/dagger/dagger/testctx"
 )
 
@@ -330,3 +333,44 @@ func (CacheSuite) TestCacheNotImpactedByChangeInModuleSource(ctx context.Context
 
 	require.Equal(t, fooID, fooID2)
 }
+
+func (CacheSuite) TestExplainCacheMiss(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	digestOf := func(ctr *dagger.Container) string {
+		ctr, err := ctr.Sync(ctx)
+		require.NoError(t, err)
+		idEnc, err := ctr.ID(ctx)
+		require.NoError(t, err)
+		var id call.ID
+		require.NoError(t, id.Decode(string(idEnc)))
+		return id.Digest().String()
+	}
+
+	base := c.Container().From(alpineImage)
+	prev := digestOf(base.WithEnvVariable("FOO", "a").WithExec([]string{"true"}))
+	cur := digestOf(base.WithEnvVariable("FOO", "b").WithExec([]string{"true"}))
+
+	// calls are recorded as their telemetry is flushed
+	require.EventuallyWithT(t, func(collect *assert.CollectT) {
+		explanation := c.ExplainCacheMiss(cur, dagger.ExplainCacheMissOpts{
+			PreviousDigest: prev,
+		})
+		kind, err := explanation.Kind(ctx)
+		if !assert.NoError(collect, err) {
+			return
+		}
+		assert.Equal(collect, dagger.CacheMissKindArgument, kind)
+
+		argument, err := explanation.Argument(ctx)
+		assert.NoError(collect, err)
+		assert.Equal(collect, "value", argument)
+
+		message, err := explanation.Message(ctx)
+		assert.NoError(collect, err)
+		assert.Contains(collect, message, `argument value changed from "a" to "b"`)
+	}, time.Minute, time.Second)
+
+	_, err := c.ExplainCacheMiss("xxh3:0000").Message(ctx)
+	requireErrOut(t, err, "no recent run recorded a call with digest xxh3:0000")
+}
@@ -1,15 +1,23 @@
 package core
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/md5"
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
 	_ "embed"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"os"
@@ -18,11 +26,13 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/containerd/platforms"
 	"github.com/google/go-containerregistry/pkg/name"
 	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/moby/buildkit/identity"
+	"github.com/opencontainers/go-digest"
 	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
@@ -2827,6 +2837,377 @@ func (ContainerSuite) TestPublish(ctx context.Context, t *testctx.T) {
 	require.Equal(t, "im-a-default-arg\n", output)
 }
 
+func (ContainerSuite) TestPublishSignedWithAttestations(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	key, keyPEM := testSigningKey(t)
+
+	testRef := registryRef("container-publish-signed")
+	pushedRef, err := c.Container().From(alpineImage).Publish(ctx, testRef, dagger.ContainerPublishOpts{
+		SigningKey: c.SetSecret("signing-key", keyPEM),
+		Sbom:       true,
+		Provenance: true,
+	})
+	require.NoError(t, err)
+
+	parsedRef, err := name.ParseReference(pushedRef, name.Insecure)
+	require.NoError(t, err)
+	dgst := parsedRef.(name.Digest).DigestStr()
+
+	t.Run("attestations", func(ctx context.Context, t *testctx.T) {
+		imgDesc, err := remote.Get(parsedRef, remote.WithTransport(http.DefaultTransport))
+		require.NoError(t, err)
+		idx, err := imgDesc.ImageIndex()
+		require.NoError(t, err)
+		idxManifest, err := idx.IndexManifest()
+		require.NoError(t, err)
+		require.Len(t, idxManifest.Manifests, 2)
+		imageDesc, attDesc := idxManifest.Manifests[0], idxManifest.Manifests[1]
+		require.Equal(t, "attestation-manifest", attDesc.Annotations["vnd.docker.reference.type"])
+		require.Equal(t, imageDesc.Digest.String(), attDesc.Annotations["vnd.docker.reference.digest"])
+
+		attImg, err := idx.Image(attDesc.Digest)
+		require.NoError(t, err)
+		attManifest, err := attImg.Manifest()
+		require.NoError(t, err)
+		statements := map[string][]byte{}
+		for _, layerDesc := range attManifest.Layers {
+			layer, err := attImg.LayerByDigest(layerDesc.Digest)
+			require.NoError(t, err)
+			rc, err := layer.Compressed()
+			require.NoError(t, err)
+			var buf bytes.Buffer
+			_, err = buf.ReadFrom(rc)
+			require.NoError(t, err)
+			rc.Close()
+			statements[layerDesc.Annotations["in-toto.io/predicate-type"]] = buf.Bytes()
+		}
+		requireImageAttestations(t, statements)
+	})
+
+	t.Run("signature", func(ctx context.Context, t *testctx.T) {
+		sigRef := parsedRef.Context().Tag(strings.Replace(dgst, ":", "-", 1) + ".sig")
+		sigImg, err := remote.Image(sigRef, remote.WithTransport(http.DefaultTransport))
+		require.NoError(t, err)
+		sigManifest, err := sigImg.Manifest()
+		require.NoError(t, err)
+		require.Len(t, sigManifest.Layers, 1)
+
+		layer, err := sigImg.LayerByDigest(sigManifest.Layers[0].Digest)
+		require.NoError(t, err)
+		rc, err := layer.Compressed()
+		require.NoError(t, err)
+		defer rc.Close()
+		var payload bytes.Buffer
+		_, err = payload.ReadFrom(rc)
+		require.NoError(t, err)
+
+		requireCosignSignature(t, &key.PublicKey, dgst,
+			payload.Bytes(),
+			sigManifest.Layers[0].Annotations["dev.cosignproject.cosign/signature"])
+	})
+
+	t.Run("image is still usable", func(ctx context.Context, t *testctx.T) {
+		contents, err := c.Container().From(pushedRef).File("/etc/alpine-release").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, distconsts.AlpineVersion, strings.TrimSpace(contents))
+	})
+}
+
+func (ContainerSuite) TestAsTarballSignedWithAttestations(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	key, keyPEM := testSigningKey(t)
+
+	imageTar := filepath.Join(t.TempDir(), "image.tar")
+	_, err := c.Container().From(alpineImage).
+		AsTarball(dagger.ContainerAsTarballOpts{
+			SigningKey: c.SetSecret("signing-key", keyPEM),
+			Sbom:       true,
+			Provenance: true,
+		}).
+		Export(ctx, imageTar)
+	require.NoError(t, err)
+
+	entries := readTarEntries(t, imageTar)
+	blob := func(dgst string) []byte {
+		t.Helper()
+		dt, ok := entries["blobs/"+strings.Replace(dgst, ":", "/", 1)]
+		require.True(t, ok, "missing blob %s", dgst)
+		return dt
+	}
+
+	var index ocispecs.Index
+	require.NoError(t, json.Unmarshal(entries["index.json"], &index))
+	require.Len(t, index.Manifests, 2)
+	imageDesc, sigDesc := index.Manifests[0], index.Manifests[1]
+	require.Equal(t, "dev.cosignproject.cosign/sigs", sigDesc.Annotations["kind"])
+
+	var sigManifest ocispecs.Manifest
+	require.NoError(t, json.Unmarshal(blob(sigDesc.Digest.String()), &sigManifest))
+	require.Len(t, sigManifest.Layers, 1)
+	requireCosignSignature(t, &key.PublicKey, imageDesc.Digest.String(),
+		blob(sigManifest.Layers[0].Digest.String()),
+		sigManifest.Layers[0].Annotations["dev.cosignproject.cosign/signature"])
+
+	var imageIndex ocispecs.Index
+	require.NoError(t, json.Unmarshal(blob(imageDesc.Digest.String()), &imageIndex))
+	require.Len(t, imageIndex.Manifests, 2)
+	attDesc := imageIndex.Manifests[1]
+	require.Equal(t, "attestation-manifest", attDesc.Annotations["vnd.docker.reference.type"])
+
+	var attManifest ocispecs.Manifest
+	require.NoError(t, json.Unmarshal(blob(attDesc.Digest.String()), &attManifest))
+	statements := map[string][]byte{}
+	for _, layerDesc := range attManifest.Layers {
+		statements[layerDesc.Annotations["in-toto.io/predicate-type"]] = blob(layerDesc.Digest.String())
+	}
+	requireImageAttestations(t, statements)
+}
+
+func (ContainerSuite) TestPublishSigningKeyErrors(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	_, err := c.Container().From(alpineImage).Publish(ctx, registryRef("container-publish-bad-key"), dagger.ContainerPublishOpts{
+		SigningKey: c.SetSecret("bad-signing-key", "not a key"),
+	})
+	requireErrOut(t, err, "invalid signing key: no PEM data found")
+
+	_, err = c.Container().From(alpineImage).Publish(ctx, registryRef("container-publish-bad-key"), dagger.ContainerPublishOpts{
+		SigningKeyPassword: c.SetSecret("signing-key-password", "hunter2"),
+	})
+	requireErrOut(t, err, "signingKeyPassword requires a signingKey")
+}
+
+func (ContainerSuite) TestPublishSquash(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	base := c.Container().From(alpineImage)
+	ctr := base.
+		WithExec([]string{"sh", "-c", "head -c 1048576 /dev/urandom > /tmp/big"}).
+		WithExec([]string{"rm", "/tmp/big", "/etc/motd"}).
+		WithNewFile("/hello", "world")
+
+	baseRef, err := base.Publish(ctx, registryRef("container-squash-base"))
+	require.NoError(t, err)
+	baseLayers := len(remoteLayerFiles(t, baseRef))
+
+	for _, tc := range []struct {
+		squash  dagger.ImageLayerSquash
+		layers  int
+		history []string
+	}{
+		{dagger.ImageLayerSquashNone, baseLayers + 3, []string{"withExec", "withExec", "withNewFile"}},
+		{dagger.ImageLayerSquashAll, 1, []string{"from, withExec, withExec, withNewFile"}},
+		{dagger.ImageLayerSquashAboveBase, baseLayers + 1, []string{"withExec, withExec, withNewFile"}},
+	} {
+		t.Run(string(tc.squash), func(ctx context.Context, t *testctx.T) {
+			pushedRef, err := ctr.Publish(ctx, registryRef("container-squash"), dagger.ContainerPublishOpts{
+				Squash:  tc.squash,
+				History: dagger.ImageHistoryFields,
+			})
+			require.NoError(t, err)
+
+			layerFiles := remoteLayerFiles(t, pushedRef)
+			require.Len(t, layerFiles, tc.layers)
+			if tc.squash != dagger.ImageLayerSquashNone {
+				// the files deleted by later operations are dropped
+				for _, files := range layerFiles {
+					require.NotContains(t, files, "tmp/big")
+				}
+			}
+
+			parsedRef, err := name.ParseReference(pushedRef, name.Insecure)
+			require.NoError(t, err)
+			img, err := remote.Image(parsedRef, remote.WithTransport(http.DefaultTransport))
+			require.NoError(t, err)
+			cfg, err := img.ConfigFile()
+			require.NoError(t, err)
+			require.Len(t, cfg.History, tc.layers)
+			var createdBy []string
+			for _, h := range cfg.History[len(cfg.History)-len(tc.history):] {
+				createdBy = append(createdBy, h.CreatedBy)
+			}
+			require.Equal(t, tc.history, createdBy)
+
+			pulled := c.Container().From(pushedRef)
+			hello, err := pulled.File("/hello").Contents(ctx)
+			require.NoError(t, err)
+			require.Equal(t, "world", hello)
+			_, err = pulled.File("/etc/motd").Contents(ctx)
+			requireErrOut(t, err, "no such file or directory")
+			_, err = pulled.File("/tmp/big").Contents(ctx)
+			requireErrOut(t, err, "no such file or directory")
+		})
+	}
+}
+
+func (ContainerSuite) TestAsTarballSquash(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	ctr := c.Container().From(alpineImage).
+		WithExec([]string{"sh", "-c", "head -c 1048576 /dev/urandom > /tmp/big"}).
+		WithExec([]string{"rm", "/tmp/big"})
+
+	tarPath := filepath.Join(t.TempDir(), "squashed.tar")
+	_, err := ctr.AsTarball(dagger.ContainerAsTarballOpts{
+		Squash: dagger.ImageLayerSquashAll,
+	}).Export(ctx, tarPath)
+	require.NoError(t, err)
+
+	entries := readTarEntries(t, tarPath)
+	var manifest []struct {
+		Layers []string
+	}
+	require.NoError(t, json.Unmarshal(entries["manifest.json"], &manifest))
+	require.Len(t, manifest, 1)
+	require.Len(t, manifest[0].Layers, 1)
+
+	imported := c.Container().Import(c.Host().File(tarPath))
+	_, err = imported.File("/tmp/big").Contents(ctx)
+	requireErrOut(t, err, "no such file or directory")
+	out, err := imported.WithExec([]string{"cat", "/etc/alpine-release"}).Stdout(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, out)
+}
+
+// remoteLayerFiles returns the paths of the files of each layer of a pushed
+// image.
+func remoteLayerFiles(t testing.TB, ref string) [][]string {
+	t.Helper()
+	parsedRef, err := name.ParseReference(ref, name.Insecure)
+	require.NoError(t, err)
+	img, err := remote.Image(parsedRef, remote.WithTransport(http.DefaultTransport))
+	require.NoError(t, err)
+	layers, err := img.Layers()
+	require.NoError(t, err)
+
+	layerFiles := make([][]string, 0, len(layers))
+	for _, layer := range layers {
+		rc, err := layer.Uncompressed()
+		require.NoError(t, err)
+		var files []string
+		tr := tar.NewReader(rc)
+		for {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			require.NoError(t, err)
+			files = append(files, strings.TrimPrefix(hdr.Name, "./"))
+		}
+		rc.Close()
+		layerFiles = append(layerFiles, files)
+	}
+	return layerFiles
+}
+
+// testSigningKey generates an ECDSA P-256 key, like "cosign generate-key-pair"
+// does, returning it along with its unencrypted PEM encoding.
+func testSigningKey(t testing.TB) (*ecdsa.PrivateKey, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return key, string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+// requireCosignSignature checks a cosign signature payload and its base64
+// encoded signature against the signed image digest.
+func requireCosignSignature(t testing.TB, pub *ecdsa.PublicKey, dgst string, payload []byte, sig string) {
+	t.Helper()
+
+	var simpleSigning struct {
+		Critical struct {
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+			Type string `json:"type"`
+		} `json:"critical"`
+	}
+	require.NoError(t, json.Unmarshal(payload, &simpleSigning))
+	require.Equal(t, dgst, simpleSigning.Critical.Image.DockerManifestDigest)
+	require.Equal(t, "cosign container image signature", simpleSigning.Critical.Type)
+
+	sigBytes, err := base64.StdEncoding.DecodeString(sig)
+	require.NoError(t, err)
+	sum := sha256.Sum256(payload)
+	require.True(t, ecdsa.VerifyASN1(pub, sum[:], sigBytes), "invalid signature")
+}
+
+// requireImageAttestations checks the in-toto statements, keyed by predicate
+// type, of an alpine image published with an SBOM and a provenance.
+func requireImageAttestations(t testing.TB, statements map[string][]byte) {
+	t.Helper()
+
+	var sbom struct {
+		Predicate struct {
+			Packages []struct {
+				Name string `json:"name"`
+			} `json:"packages"`
+			Files []struct {
+				FileName string `json:"fileName"`
+				Comment  string `json:"comment"`
+			} `json:"files"`
+		} `json:"predicate"`
+	}
+	require.Contains(t, statements, "https://spdx.dev/Document")
+	require.NoError(t, json.Unmarshal(statements["https://spdx.dev/Document"], &sbom))
+	var pkgNames []string
+	for _, pkg := range sbom.Predicate.Packages {
+		pkgNames = append(pkgNames, pkg.Name)
+	}
+	require.Contains(t, pkgNames, "musl")
+	var releaseFileComment string
+	for _, file := range sbom.Predicate.Files {
+		if file.FileName == "/etc/alpine-release" {
+			releaseFileComment = file.Comment
+		}
+	}
+	require.Contains(t, releaseFileComment, "layerID: sha256:")
+
+	var provenance struct {
+		Predicate struct {
+			BuildDefinition struct {
+				BuildType          string `json:"buildType"`
+				ExternalParameters struct {
+					Call struct {
+						Digest string `json:"digest"`
+						ID     string `json:"id"`
+					} `json:"call"`
+				} `json:"externalParameters"`
+			} `json:"buildDefinition"`
+		} `json:"predicate"`
+	}
+	require.Contains(t, statements, "https://slsa.dev/provenance/v1")
+	require.NoError(t, json.Unmarshal(statements["https://slsa.dev/provenance/v1"], &provenance))
+	require.Equal(t, "https://dagger.io/provenance/container@v1", provenance.Predicate.BuildDefinition.BuildType)
+	require.Contains(t, provenance.Predicate.BuildDefinition.ExternalParameters.Call.Digest, "sha256:")
+	require.NotEmpty(t, provenance.Predicate.BuildDefinition.ExternalParameters.Call.ID)
+}
+
+func readTarEntries(t testing.TB, tarPath string) map[string][]byte {
+	t.Helper()
+	f, err := os.Open(tarPath)
+	require.NoError(t, err)
+	defer f.Close()
+	entries := map[string][]byte{}
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(tr)
+		require.NoError(t, err)
+		entries[path.Clean(hdr.Name)] = buf.Bytes()
+	}
+	return entries
+}
+
 func (ContainerSuite) TestAnnotations(ctx context.Context, t *testctx.T) {
 	build := func(c *dagger.Client, platform dagger.Platform) *dagger.Container {
 		return c.Container(dagger.ContainerOpts{Platform: platform}).
@@ -3367,6 +3748,279 @@ func (ContainerSuite) TestMultiPlatformImport(ctx context.Context, t *testctx.T)
 	}
 }
 
+func (ContainerSuite) TestImportDirectory(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	variants := make([]*dagger.Container, 0, len(platformToUname))
+	for platform := range platformToUname {
+		variants = append(variants, c.Container(dagger.ContainerOpts{Platform: platform}).From(alpineImage))
+	}
+
+	imagePath := filepath.Join(t.TempDir(), "image.tar")
+	_, err := c.Container().AsTarball(dagger.ContainerAsTarballOpts{
+		PlatformVariants: variants,
+	}).Export(ctx, imagePath)
+	require.NoError(t, err)
+
+	layout := c.Container().From(alpineImage).
+		WithMountedFile("/image.tar", c.Host().File(imagePath)).
+		WithExec([]string{"sh", "-c", "mkdir /layout && tar -xf /image.tar -C /layout"}).
+		Directory("/layout")
+
+	t.Run("by platform", func(ctx context.Context, t *testctx.T) {
+		for platform, uname := range platformToUname {
+			imported := c.Container(dagger.ContainerOpts{Platform: platform}).ImportDirectory(layout)
+
+			out, err := imported.WithExec([]string{"uname", "-m"}).Stdout(ctx)
+			require.NoError(t, err)
+			require.Equal(t, uname+"\n", out)
+		}
+	})
+
+	t.Run("by digest", func(ctx context.Context, t *testctx.T) {
+		manifests := ociPlatformManifests(t, readTarEntries(t, imagePath))
+		for platform, uname := range platformToUname {
+			// import with the default platform, the digest picks the variant
+			imported := c.Container().ImportDirectory(layout, dagger.ContainerImportDirectoryOpts{
+				Digest: manifests[platform],
+			})
+
+			out, err := imported.WithExec([]string{"uname", "-m"}).Stdout(ctx)
+			require.NoError(t, err)
+			require.Equal(t, uname+"\n", out)
+
+			actualPlatform, err := imported.Platform(ctx)
+			require.NoError(t, err)
+			require.Equal(t, platform, actualPlatform)
+		}
+	})
+
+	t.Run("unknown digest", func(ctx context.Context, t *testctx.T) {
+		_, err := c.Container().ImportDirectory(layout, dagger.ContainerImportDirectoryOpts{
+			Digest: digest.FromString("nope").String(),
+		}).Sync(ctx)
+		requireErrOut(t, err, "no image with digest")
+	})
+
+	t.Run("not a layout", func(ctx context.Context, t *testctx.T) {
+		_, err := c.Container().ImportDirectory(c.Directory().WithNewFile("foo", "bar")).Sync(ctx)
+		requireErrOut(t, err, "read oci-layout")
+	})
+}
+
+func (ContainerSuite) TestImportVariants(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	variants := make([]*dagger.Container, 0, len(platformToUname))
+	for platform := range platformToUname {
+		variants = append(variants, c.Container(dagger.ContainerOpts{Platform: platform}).
+			From(alpineImage).
+			WithEnvVariable("PLATFORM", string(platform)))
+	}
+
+	tarball := c.Container().AsTarball(dagger.ContainerAsTarballOpts{
+		PlatformVariants: variants,
+	})
+
+	requireVariants := func(ctx context.Context, t *testctx.T, imported []dagger.Container) {
+		t.Helper()
+		require.Len(t, imported, len(platformToUname))
+		for _, variant := range imported {
+			platform, err := variant.Platform(ctx)
+			require.NoError(t, err)
+			env, err := variant.EnvVariable(ctx, "PLATFORM")
+			require.NoError(t, err)
+			require.Equal(t, string(platform), env)
+
+			out, err := variant.WithExec([]string{"uname", "-m"}).Stdout(ctx)
+			require.NoError(t, err)
+			require.Equal(t, platformToUname[platform]+"\n", out)
+		}
+	}
+
+	t.Run("tarball", func(ctx context.Context, t *testctx.T) {
+		imported, err := c.Container().ImportVariants(ctx, dagger.ContainerImportVariantsOpts{
+			Source: tarball,
+		})
+		require.NoError(t, err)
+		requireVariants(ctx, t, imported)
+	})
+
+	t.Run("directory", func(ctx context.Context, t *testctx.T) {
+		layout := c.Container().From(alpineImage).
+			WithMountedFile("/image.tar", tarball).
+			WithExec([]string{"sh", "-c", "mkdir /layout && tar -xf /image.tar -C /layout"}).
+			Directory("/layout")
+
+		imported, err := c.Container().ImportVariants(ctx, dagger.ContainerImportVariantsOpts{
+			Directory: layout,
+		})
+		require.NoError(t, err)
+		requireVariants(ctx, t, imported)
+	})
+
+	t.Run("round trip", func(ctx context.Context, t *testctx.T) {
+		imported, err := c.Container().ImportVariants(ctx, dagger.ContainerImportVariantsOpts{
+			Source: tarball,
+		})
+		require.NoError(t, err)
+		require.NotEmpty(t, imported)
+
+		platformVariants := make([]*dagger.Container, 0, len(imported)-1)
+		for i := range imported[1:] {
+			platformVariants = append(platformVariants, &imported[i+1])
+		}
+		reexported := imported[0].AsTarball(dagger.ContainerAsTarballOpts{
+			PlatformVariants: platformVariants,
+		})
+
+		reimported, err := c.Container().ImportVariants(ctx, dagger.ContainerImportVariantsOpts{
+			Source: reexported,
+		})
+		require.NoError(t, err)
+		requireVariants(ctx, t, reimported)
+	})
+
+	t.Run("exactly one source", func(ctx context.Context, t *testctx.T) {
+		_, err := c.Container().ImportVariants(ctx)
+		requireErrOut(t, err, "exactly one of source or directory must be set")
+	})
+}
+
+func (ContainerSuite) TestImportMultiImageArchive(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	tmp := t.TempDir()
+	tarballs := map[string]string{}
+	for _, name := range []string{"foo", "bar"} {
+		tarballPath := filepath.Join(tmp, name+".tar")
+		_, err := c.Container().From(alpineImage).
+			WithEnvVariable("NAME", name).
+			AsTarball().
+			Export(ctx, tarballPath)
+		require.NoError(t, err)
+		tarballs["example.com/"+name+":v1"] = tarballPath
+	}
+
+	archivePath := filepath.Join(tmp, "archive.tar")
+	writeDockerArchive(t, archivePath, tarballs)
+	archive := c.Host().File(archivePath)
+
+	for _, tc := range []struct {
+		tag  string
+		name string
+	}{
+		{tag: "example.com/foo:v1", name: "foo"},
+		{tag: "example.com/bar:v1", name: "bar"},
+	} {
+		t.Run(tc.tag, func(ctx context.Context, t *testctx.T) {
+			env, err := c.Container().Import(archive, dagger.ContainerImportOpts{
+				Tag: tc.tag,
+			}).EnvVariable(ctx, "NAME")
+			require.NoError(t, err)
+			require.Equal(t, tc.name, env)
+		})
+	}
+
+	t.Run("unknown tag", func(ctx context.Context, t *testctx.T) {
+		_, err := c.Container().Import(archive, dagger.ContainerImportOpts{
+			Tag: "example.com/baz:v1",
+		}).Sync(ctx)
+		requireErrOut(t, err, `no image with tag "example.com/baz:v1"`)
+	})
+}
+
+// ociPlatformManifests returns the digests of the platform manifests of the
+// OCI tarball with the given entries.
+func ociPlatformManifests(t testing.TB, entries map[string][]byte) map[dagger.Platform]string {
+	t.Helper()
+
+	manifests := map[dagger.Platform]string{}
+	var walk func(dgst string)
+	walk = func(dgst string) {
+		var idx ocispecs.Index
+		require.NoError(t, json.Unmarshal(entries["blobs/"+strings.Replace(dgst, ":", "/", 1)], &idx))
+		for _, m := range idx.Manifests {
+			switch {
+			case m.MediaType == ocispecs.MediaTypeImageIndex:
+				walk(m.Digest.String())
+			case m.Platform != nil && m.Platform.OS != "unknown":
+				manifests[dagger.Platform(platforms.Format(*m.Platform))] = m.Digest.String()
+			}
+		}
+	}
+	var idx ocispecs.Index
+	require.NoError(t, json.Unmarshal(entries["index.json"], &idx))
+	for _, m := range idx.Manifests {
+		walk(m.Digest.String())
+	}
+	return manifests
+}
+
+// writeDockerArchive writes a multi-image archive in the format of
+// "docker save" from single-platform OCI tarballs, keyed by their tag.
+func writeDockerArchive(t testing.TB, dest string, tarballs map[string]string) {
+	t.Helper()
+
+	type dockerManifest struct {
+		Config   string
+		RepoTags []string
+		Layers   []string
+	}
+	blobPath := func(dgst digest.Digest) string {
+		return path.Join("blobs", dgst.Algorithm().String(), dgst.Encoded())
+	}
+
+	blobs := map[string][]byte{}
+	var manifests []dockerManifest
+	for tag, tarballPath := range tarballs {
+		entries := readTarEntries(t, tarballPath)
+		var idx ocispecs.Index
+		require.NoError(t, json.Unmarshal(entries["index.json"], &idx))
+		require.NotEmpty(t, idx.Manifests)
+		desc := idx.Manifests[0]
+		if desc.MediaType == ocispecs.MediaTypeImageIndex {
+			require.NoError(t, json.Unmarshal(entries[blobPath(desc.Digest)], &idx))
+			desc = idx.Manifests[0]
+		}
+
+		var manifest ocispecs.Manifest
+		require.NoError(t, json.Unmarshal(entries[blobPath(desc.Digest)], &manifest))
+		dm := dockerManifest{
+			Config:   blobPath(manifest.Config.Digest),
+			RepoTags: []string{tag},
+		}
+		blobs[dm.Config] = entries[dm.Config]
+		for _, layer := range manifest.Layers {
+			dm.Layers = append(dm.Layers, blobPath(layer.Digest))
+			blobs[blobPath(layer.Digest)] = entries[blobPath(layer.Digest)]
+		}
+		manifests = append(manifests, dm)
+	}
+
+	f, err := os.Create(dest)
+	require.NoError(t, err)
+	defer f.Close()
+	tw := tar.NewWriter(f)
+	writeFile := func(name string, data []byte) {
+		require.NoError(t, tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(data)),
+		}))
+		_, err := tw.Write(data)
+		require.NoError(t, err)
+	}
+	for name, data := range blobs {
+		writeFile(name, data)
+	}
+	manifestJSON, err := json.Marshal(manifests)
+	require.NoError(t, err)
+	writeFile("manifest.json", manifestJSON)
+	require.NoError(t, tw.Close())
+}
+
 func (ContainerSuite) TestWithDirectoryToMount(ctx context.Context, t *testctx.T) {
 	c := connect(ctx, t)
 
@@ -4809,6 +5463,219 @@ func (ContainerSuite) TestExecInit(ctx context.Context, t *testctx.T) {
 	})
 }
 
+func (ContainerSuite) TestExecLimits(ctx context.Context, t *testctx.T) {
+	t.Run("timeout", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+		_, err := c.Container().From(alpineImage).
+			WithEnvVariable("CACHEBUST", identity.NewID()).
+			WithExec([]string{"sleep", "60"}, dagger.ContainerWithExecOpts{
+				Timeout: 1,
+			}).
+			Sync(ctx)
+		var execErr *dagger.ExecError
+		require.ErrorAs(t, err, &execErr)
+		require.Equal(t, dagger.ExecErrorReasonTimeout, execErr.Reason)
+		require.ErrorContains(t, err, "exec timed out after 1s")
+	})
+
+	t.Run("no timeout", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+		out, err := c.Container().From(alpineImage).
+			WithExec([]string{"echo", "hi"}, dagger.ContainerWithExecOpts{
+				Timeout: 60,
+			}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "hi\n", out)
+	})
+
+	t.Run("memory limit", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+		out, err := c.Container().From(alpineImage).
+			WithExec([]string{"cat", "/sys/fs/cgroup/memory.max"}, dagger.ContainerWithExecOpts{
+				MemoryLimit: 64 * 1024 * 1024,
+			}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "67108864", strings.TrimSpace(out))
+	})
+
+	t.Run("oom killed", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+		_, err := c.Container().From(alpineImage).
+			WithEnvVariable("CACHEBUST", identity.NewID()).
+			WithExec([]string{"sh", "-c", "head -c 100m /dev/zero | tail"}, dagger.ContainerWithExecOpts{
+				MemoryLimit: 16 * 1024 * 1024,
+			}).
+			Sync(ctx)
+		var execErr *dagger.ExecError
+		require.ErrorAs(t, err, &execErr)
+		require.Equal(t, dagger.ExecErrorReasonOOMKilled, execErr.Reason)
+	})
+
+	t.Run("cpu limit", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+		out, err := c.Container().From(alpineImage).
+			WithExec([]string{"cat", "/sys/fs/cgroup/cpu.max"}, dagger.ContainerWithExecOpts{
+				CPULimit: 0.5,
+			}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "50000 100000", strings.TrimSpace(out))
+	})
+
+	t.Run("pids limit", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+		out, err := c.Container().From(alpineImage).
+			WithExec([]string{"cat", "/sys/fs/cgroup/pids.max"}, dagger.ContainerWithExecOpts{
+				PidsLimit: 32,
+			}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "32", strings.TrimSpace(out))
+	})
+
+	t.Run("limits are part of the cache key", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+		base := c.Container().From(alpineImage).
+			WithEnvVariable("CACHEBUST", identity.NewID())
+
+		out1, err := base.
+			WithExec([]string{"sh", "-c", "cat /sys/fs/cgroup/pids.max; head -c 16 /dev/urandom | base64"}, dagger.ContainerWithExecOpts{
+				PidsLimit: 32,
+			}).
+			Stdout(ctx)
+		require.NoError(t, err)
+
+		out2, err := base.
+			WithExec([]string{"sh", "-c", "cat /sys/fs/cgroup/pids.max; head -c 16 /dev/urandom | base64"}, dagger.ContainerWithExecOpts{
+				PidsLimit: 64,
+			}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.NotEqual(t, out1, out2)
+	})
+
+	t.Run("negative limits are rejected", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+		_, err := c.Container().From(alpineImage).
+			WithExec([]string{"true"}, dagger.ContainerWithExecOpts{
+				Timeout: -1,
+			}).
+			Sync(ctx)
+		require.ErrorContains(t, err, "invalid timeout -1")
+	})
+}
+
+func (ContainerSuite) TestExecNetworkMode(ctx context.Context, t *testctx.T) {
+	t.Run("none", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+		out, err := c.Container().From(alpineImage).
+			WithEnvVariable("CACHEBUST", identity.NewID()).
+			WithExec([]string{"sh", "-c", "ip -o link | awk '{print $2}'"}, dagger.ContainerWithExecOpts{
+				NetworkMode: dagger.NetworkModeNone,
+			}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "lo:\n", out)
+	})
+
+	t.Run("none rejects service bindings", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+		srv, _ := httpService(ctx, t, c, "Hello, world!")
+		_, err := c.Container().From(alpineImage).
+			WithServiceBinding("www", srv).
+			WithExec([]string{"true"}, dagger.ContainerWithExecOpts{
+				NetworkMode: dagger.NetworkModeNone,
+			}).
+			Sync(ctx)
+		require.ErrorContains(t, err, "network mode NONE does not allow service bindings")
+	})
+
+	t.Run("services only", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+		srv, url := httpService(ctx, t, c, "Hello, world!")
+
+		ctr := c.Container().From(alpineImage).
+			WithServiceBinding("www", srv).
+			WithEnvVariable("CACHEBUST", identity.NewID())
+
+		out, err := ctr.
+			WithExec([]string{"wget", "-q", "-O-", url}, dagger.ContainerWithExecOpts{
+				NetworkMode: dagger.NetworkModeServicesOnly,
+			}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "Hello, world!", out)
+
+		// name resolution still works, but external hosts are unreachable
+		_, err = ctr.
+			WithExec([]string{"nslookup", "www"}, dagger.ContainerWithExecOpts{
+				NetworkMode: dagger.NetworkModeServicesOnly,
+			}).
+			Sync(ctx)
+		require.NoError(t, err)
+
+		start := time.Now()
+		_, err = ctr.
+			WithExec([]string{"wget", "-q", "-T", "30", "-O-", "http://1.1.1.1"}, dagger.ContainerWithExecOpts{
+				NetworkMode: dagger.NetworkModeServicesOnly,
+			}).
+			Sync(ctx)
+		var execErr *dagger.ExecError
+		require.ErrorAs(t, err, &execErr)
+		// the connection is rejected right away instead of timing out
+		require.Less(t, time.Since(start), 15*time.Second)
+		require.Regexp(t, `(?i)unreachable|no route to host`, execErr.Stderr)
+		require.NotContains(t, execErr.Stderr, "timed out")
+	})
+
+	t.Run("default after services only", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+		ctr := c.Container().From(alpineImage)
+
+		// run a few restricted execs first, so that a following exec would
+		// likely land in one of their network namespaces if they were reused
+		for i := 0; i < 3; i++ {
+			_, err := ctr.
+				WithEnvVariable("CACHEBUST", identity.NewID()).
+				WithExec([]string{"true"}, dagger.ContainerWithExecOpts{
+					NetworkMode: dagger.NetworkModeServicesOnly,
+				}).
+				Sync(ctx)
+			require.NoError(t, err)
+		}
+
+		_, err := ctr.
+			WithEnvVariable("CACHEBUST", identity.NewID()).
+			WithExec([]string{"wget", "-q", "-T", "30", "-O", "/dev/null", "http://dl-cdn.alpinelinux.org/alpine/"}).
+			Sync(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("network mode is part of the cache key", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+		base := c.Container().From(alpineImage).
+			WithEnvVariable("CACHEBUST", identity.NewID())
+
+		outs := map[string]bool{}
+		for _, mode := range []dagger.NetworkMode{
+			dagger.NetworkModeDefault,
+			dagger.NetworkModeServicesOnly,
+			dagger.NetworkModeNone,
+		} {
+			out, err := base.
+				WithExec([]string{"sh", "-c", "head -c 16 /dev/urandom | base64"}, dagger.ContainerWithExecOpts{
+					NetworkMode: mode,
+				}).
+				Stdout(ctx)
+			require.NoError(t, err)
+			outs[out] = true
+		}
+		require.Len(t, outs, 3)
+	})
+}
+
 func (ContainerSuite) TestContainerAsService(ctx context.Context, t *testctx.T) {
 	c := connect(ctx, t)
 	maingo := `package main
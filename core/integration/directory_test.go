@@ -2,6 +2,11 @@ package core
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"io"
+	"net"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -11,6 +16,7 @@ import (
 
 	"github.com/moby/buildkit/identity"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh/agent"
 
 	"dagger.io/dagger"
 	"github.com/dagger/dagger/core"
@@ -663,6 +669,151 @@ func (DirectorySuite) TestDiff(ctx context.Context, t *testctx.T) {
 	*/
 }
 
+func (DirectorySuite) TestPatch(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	base := c.Directory().
+		WithNewFile("README.md", "hello\nworld\n").
+		WithNewFile("src/main.go", "package main\n\nfunc main() {}\n").
+		WithNewFile("old.txt", "remove me\n")
+	changed := base.
+		WithNewFile("README.md", "hello\nthere\nworld\n").
+		WithNewFile("src/gen.go", "package main\n").
+		WithoutFile("old.txt")
+
+	t.Run("as patch", func(ctx context.Context, t *testctx.T) {
+		patch, err := base.AsPatch(changed).Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, `diff --git a/README.md b/README.md
+--- a/README.md
++++ b/README.md
+@@ -1,2 +1,3 @@
+ hello
++there
+ world
+diff --git a/old.txt b/old.txt
+deleted file mode 100644
+--- a/old.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-remove me
+diff --git a/src/gen.go b/src/gen.go
+new file mode 100644
+--- /dev/null
++++ b/src/gen.go
+@@ -0,0 +1 @@
++package main
+`, patch)
+
+		empty, err := base.AsPatch(base).Contents(ctx)
+		require.NoError(t, err)
+		require.Empty(t, empty)
+	})
+
+	t.Run("round trip", func(ctx context.Context, t *testctx.T) {
+		patched := base.WithPatch(base.AsPatch(changed))
+
+		ents, err := patched.Glob(ctx, "**/*")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"README.md", "src", "src/gen.go", "src/main.go"}, ents)
+
+		readme, err := patched.File("README.md").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "hello\nthere\nworld\n", readme)
+
+		gen, err := patched.File("src/gen.go").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "package main\n", gen)
+	})
+
+	t.Run("git diff", func(ctx context.Context, t *testctx.T) {
+		out, err := c.Container().From(alpineImage).
+			WithExec([]string{"apk", "add", "git"}).
+			WithMountedDirectory("/src", base).
+			WithWorkdir("/src").
+			WithExec([]string{"sh", "-c", `
+				git init -q && git add -A &&
+				printf 'hello\nworld\nfrom git\n' > README.md &&
+				chmod +x src/main.go &&
+				git diff > /patch.diff`}).
+			File("/patch.diff").
+			Contents(ctx)
+		require.NoError(t, err)
+
+		patched := base.WithPatch(c.Directory().WithNewFile("patch.diff", out).File("patch.diff"))
+		readme, err := patched.File("README.md").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "hello\nworld\nfrom git\n", readme)
+
+		out, err = c.Container().From(alpineImage).
+			WithMountedDirectory("/src", patched).
+			WithExec([]string{"stat", "-c", "%a", "/src/src/main.go"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "755\n", out)
+	})
+
+	t.Run("rejected hunk", func(ctx context.Context, t *testctx.T) {
+		patch := base.AsPatch(changed)
+		_, err := base.
+			WithNewFile("README.md", "goodbye\nworld\n").
+			WithPatch(patch).
+			Sync(ctx)
+		requireErrOut(t, err, "failed to apply patch to README.md: hunk #1 @@ -1,2 +1,3 @@ does not apply")
+	})
+
+	t.Run("missing file", func(ctx context.Context, t *testctx.T) {
+		patch := base.AsPatch(changed)
+		_, err := c.Directory().WithPatch(patch).Sync(ctx)
+		requireErrOut(t, err, "failed to apply patch to README.md")
+	})
+
+	t.Run("not a patch", func(ctx context.Context, t *testctx.T) {
+		_, err := base.
+			WithPatch(base.File("README.md")).
+			Sync(ctx)
+		requireErrOut(t, err, "patch does not contain any file changes")
+	})
+}
+
+func (DirectorySuite) TestMerge(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	t.Run("disjoint", func(ctx context.Context, t *testctx.T) {
+		merged := c.Directory().WithNewFile("a/one", "1").Merge([]*dagger.Directory{
+			c.Directory().WithNewFile("a/two", "2"),
+			c.Directory().WithNewFile("b/three", "3"),
+		})
+		ents, err := merged.Glob(ctx, "**/*")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"a", "a/one", "a/two", "b", "b/three"}, ents)
+	})
+
+	t.Run("identical files", func(ctx context.Context, t *testctx.T) {
+		merged := c.Directory().WithNewFile("same", "content").Merge([]*dagger.Directory{
+			c.Directory().WithNewFile("same", "content"),
+		})
+		contents, err := merged.File("same").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "content", contents)
+	})
+
+	t.Run("conflict", func(ctx context.Context, t *testctx.T) {
+		_, err := c.Directory().WithNewFile("file", "one").Merge([]*dagger.Directory{
+			c.Directory().WithNewFile("other", "two"),
+			c.Directory().WithNewFile("file", "three"),
+		}).Sync(ctx)
+		requireErrOut(t, err, "merge conflict: paths differ between directories: file (this directory and directories[1])")
+	})
+
+	t.Run("file and directory conflict", func(ctx context.Context, t *testctx.T) {
+		_, err := c.Directory().WithNewFile("path", "file").Merge([]*dagger.Directory{
+			c.Directory().WithNewFile("path/file", "nested"),
+		}).Sync(ctx)
+		requireErrOut(t, err, "path (this directory and directories[0])")
+	})
+}
+
 func (DirectorySuite) TestExport(ctx context.Context, t *testctx.T) {
 	wd := t.TempDir()
 	dest := t.TempDir()
@@ -883,6 +1034,116 @@ CMD cat /secret
 		require.NoError(t, err)
 		require.Contains(t, stdout, "***")
 	})
+
+	t.Run("with named build contexts", func(ctx context.Context, t *testctx.T) {
+		base := c.Container().From(alpineImage).WithEnvVariable("FROM_CONTEXT", "yes")
+		extra := c.Directory().WithNewFile("hello.txt", "hello from extra")
+
+		src := c.Directory().
+			WithNewFile("Dockerfile",
+				`FROM base
+COPY --from=extra hello.txt /hello.txt
+CMD cat /hello.txt && echo " $FROM_CONTEXT"
+`)
+
+		out, err := src.DockerBuild(dagger.DirectoryDockerBuildOpts{
+			BuildContexts: []dagger.BuildContext{
+				{Name: "base", Container: base},
+				{Name: "extra", Directory: extra},
+			},
+		}).
+			WithExec(nil).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "hello from extra yes\n", out)
+	})
+
+	t.Run("with invalid named build contexts", func(ctx context.Context, t *testctx.T) {
+		src := c.Directory().WithNewFile("Dockerfile", "FROM scratch\n")
+		extra := c.Directory().WithNewFile("hello.txt", "hello from extra")
+
+		_, err := src.DockerBuild(dagger.DirectoryDockerBuildOpts{
+			BuildContexts: []dagger.BuildContext{
+				{Name: "extra", Directory: extra, Container: c.Container()},
+			},
+		}).Sync(ctx)
+		requireErrOut(t, err, `build context "extra" must have exactly one of directory or container`)
+
+		_, err = src.DockerBuild(dagger.DirectoryDockerBuildOpts{
+			BuildContexts: []dagger.BuildContext{
+				{Name: "extra", Directory: extra},
+				{Name: "extra", Directory: extra},
+			},
+		}).Sync(ctx)
+		requireErrOut(t, err, `duplicate build context "extra"`)
+	})
+
+	t.Run("with ssh socket", func(ctx context.Context, t *testctx.T) {
+		_, key, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		keyring := agent.NewKeyring()
+		require.NoError(t, keyring.Add(agent.AddedKey{
+			PrivateKey: key,
+			Comment:    "dockerbuild-test",
+		}))
+
+		sock := filepath.Join(t.TempDir(), "agent.sock")
+		l, err := net.Listen("unix", sock)
+		require.NoError(t, err)
+		defer l.Close()
+		go func() {
+			for {
+				conn, err := l.Accept()
+				if err != nil {
+					return
+				}
+				go func() {
+					defer conn.Close()
+					if err := agent.ServeAgent(keyring, conn); err != nil && !errors.Is(err, io.EOF) {
+						t.Logf("serve agent: %s", err)
+					}
+				}()
+			}
+		}()
+
+		src := c.Directory().
+			WithNewFile("Dockerfile",
+				`FROM `+alpineImage+`
+RUN apk add --no-cache openssh-client
+RUN --mount=type=ssh ssh-add -l > /keys
+CMD cat /keys
+`)
+
+		out, err := src.DockerBuild(dagger.DirectoryDockerBuildOpts{
+			SSH: c.Host().UnixSocket(sock),
+		}).
+			WithExec(nil).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Contains(t, out, "dockerbuild-test")
+	})
+
+	t.Run("with no-cache filter", func(ctx context.Context, t *testctx.T) {
+		src := c.Directory().
+			WithNewFile("Dockerfile",
+				`FROM `+alpineImage+` AS rand
+RUN head -c 16 /dev/urandom | base64 > /rand
+
+FROM rand
+CMD cat /rand
+`)
+
+		cached, err := src.DockerBuild().WithExec(nil).Stdout(ctx)
+		require.NoError(t, err)
+
+		uncached, err := src.DockerBuild(dagger.DirectoryDockerBuildOpts{
+			NoCacheFilter: []string{"rand"},
+		}).
+			WithExec(nil).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.NotEqual(t, cached, uncached)
+	})
 }
 
 func (DirectorySuite) TestWithNewFileExceedingLength(ctx context.Context, t *testctx.T) {
@@ -494,3 +494,420 @@ func (GitSuite) TestGitTags(ctx context.Context, t *testctx.T) {
 		require.Contains(t, tags, "sdk/go/v0.9.3")
 	})
 }
+
+func (GitSuite) TestCommitMetadata(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	gitDaemon, repoURL := gitHistoryService(ctx, t, c)
+	repo := c.Git(repoURL, dagger.GitOpts{ExperimentalServiceHost: gitDaemon})
+
+	t.Run("commit info", func(ctx context.Context, t *testctx.T) {
+		commit, err := repo.Branch("main").Commit(ctx)
+		require.NoError(t, err)
+
+		info := repo.Branch("main").CommitInfo()
+		sha, err := info.Sha(ctx)
+		require.NoError(t, err)
+		require.Equal(t, commit, sha)
+
+		msg, err := info.Message(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "add source\n\nwith a body\n", msg)
+
+		date, err := info.Date(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "2024-01-03T00:00:00+00:00", date)
+
+		name, err := info.Author().Name(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "Author", name)
+		email, err := info.Author().Email(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "author@localhost", email)
+
+		name, err = info.Committer().Name(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "Committer", name)
+
+		parents, err := info.Parents(ctx)
+		require.NoError(t, err)
+		require.Len(t, parents, 1)
+		parent, err := repo.Tag("v0.2.0").Commit(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{parent}, parents)
+	})
+
+	t.Run("log", func(ctx context.Context, t *testctx.T) {
+		commits, err := repo.Log(ctx)
+		require.NoError(t, err)
+		messages := make([]string, 0, len(commits))
+		for _, commit := range commits {
+			msg, err := commit.Message(ctx)
+			require.NoError(t, err)
+			messages = append(messages, msg)
+		}
+		require.Equal(t, []string{"add source\n\nwith a body\n", "add docs\n", "init\n"}, messages)
+
+		commits, err = repo.Log(ctx, dagger.GitRepositoryLogOpts{From: "v0.1.0"})
+		require.NoError(t, err)
+		require.Len(t, commits, 2)
+
+		commits, err = repo.Log(ctx, dagger.GitRepositoryLogOpts{From: "v0.1.0", To: "v0.2.0"})
+		require.NoError(t, err)
+		require.Len(t, commits, 1)
+		msg, err := commits[0].Message(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "add docs\n", msg)
+
+		commits, err = repo.Log(ctx, dagger.GitRepositoryLogOpts{Paths: []string{"src"}})
+		require.NoError(t, err)
+		require.Len(t, commits, 1)
+	})
+
+	t.Run("describe", func(ctx context.Context, t *testctx.T) {
+		desc, err := repo.Tag("v0.2.0").Describe(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "v0.2.0", desc)
+
+		commit, err := repo.Branch("main").Commit(ctx)
+		require.NoError(t, err)
+		desc, err = repo.Branch("main").Describe(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "v0.2.0-1-g"+commit[:7], desc)
+	})
+}
+
+// gitHistoryService serves a repository with a few commits and tags with
+// fixed metadata, and a submodule in its last commit.
+func gitHistoryService(ctx context.Context, t *testctx.T, c *dagger.Client) (*dagger.Service, string) {
+	t.Helper()
+
+	srv := c.Container().
+		From(alpineImage).
+		WithExec([]string{"apk", "add", "git"}).
+		WithEnvVariable("GIT_AUTHOR_NAME", "Author").
+		WithEnvVariable("GIT_AUTHOR_EMAIL", "author@localhost").
+		WithEnvVariable("GIT_COMMITTER_NAME", "Committer").
+		WithEnvVariable("GIT_COMMITTER_EMAIL", "committer@localhost").
+		WithWorkdir("/root/repo").
+		WithExec([]string{"sh", "-e", "-x", "-c", `
+			mkdir /root/srv
+			mkdir /tmp/sub && cd /tmp/sub
+			git init -b main
+			echo sub > sub.txt
+			git add sub.txt
+			git commit -m sub
+			git clone --bare . /root/srv/sub.git
+			cd /root/repo
+			git init -b main
+			echo hello > README.md
+			git add README.md
+			GIT_AUTHOR_DATE=2024-01-01T00:00:00Z GIT_COMMITTER_DATE=2024-01-01T00:00:00Z git commit -m init
+			git tag -a v0.1.0 -m v0.1.0
+			mkdir docs && echo docs > docs/index.md
+			git add docs
+			GIT_AUTHOR_DATE=2024-01-02T00:00:00Z GIT_COMMITTER_DATE=2024-01-02T00:00:00Z git commit -m "add docs"
+			git tag v0.2.0
+			mkdir src && echo code > src/main.go
+			git -c protocol.file.allow=always submodule add /root/srv/sub.git sub
+			git config -f .gitmodules submodule.sub.url ../sub.git
+			git add src sub .gitmodules
+			GIT_AUTHOR_DATE=2024-01-03T00:00:00Z GIT_COMMITTER_DATE=2024-01-04T00:00:00Z git commit -m "add source" -m "with a body"
+			git clone --bare . /root/srv/repo.git
+		`}).
+		Directory("/root/srv")
+
+	return gitDaemonService(ctx, t, c, srv)
+}
+
+func (GitSuite) TestTreeCheckoutOptions(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	gitDaemon, repoURL := gitHistoryService(ctx, t, c)
+	ref := c.Git(repoURL, dagger.GitOpts{ExperimentalServiceHost: gitDaemon}).Branch("main")
+
+	gitOutput := func(ctx context.Context, tree *dagger.Directory, args ...string) (string, error) {
+		return c.Container().From(alpineImage).
+			WithExec([]string{"apk", "add", "git"}).
+			WithMountedDirectory("/src", tree).
+			WithWorkdir("/src").
+			WithExec(append([]string{"git"}, args...)).
+			Stdout(ctx)
+	}
+
+	t.Run("default", func(ctx context.Context, t *testctx.T) {
+		tree := ref.Tree()
+		contents, err := tree.File("sub/sub.txt").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "sub\n", contents)
+
+		out, err := gitOutput(ctx, tree, "rev-list", "--count", "HEAD")
+		require.NoError(t, err)
+		require.Equal(t, "1\n", out)
+	})
+
+	t.Run("sparse checkout", func(ctx context.Context, t *testctx.T) {
+		tree := ref.Tree(dagger.GitRefTreeOpts{
+			SparseCheckout: []string{"/docs/"},
+			DiscardGitDir:  true,
+		})
+		ents, err := tree.Glob(ctx, "**/*")
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"docs", "docs/index.md"}, ents)
+
+		ents, err = ref.Tree(dagger.GitRefTreeOpts{
+			SparseCheckout: []string{"/docs/", "*.md"},
+		}).Entries(ctx)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{".git", "README.md", "docs"}, ents)
+	})
+
+	t.Run("depth", func(ctx context.Context, t *testctx.T) {
+		out, err := gitOutput(ctx, ref.Tree(dagger.GitRefTreeOpts{Depth: 2}), "rev-list", "--count", "HEAD")
+		require.NoError(t, err)
+		require.Equal(t, "2\n", out)
+
+		_, err = ref.Tree(dagger.GitRefTreeOpts{Depth: -1}).Sync(ctx)
+		requireErrOut(t, err, "depth must be at least 1")
+	})
+
+	t.Run("full history", func(ctx context.Context, t *testctx.T) {
+		tree := ref.Tree(dagger.GitRefTreeOpts{FullHistory: true})
+		out, err := gitOutput(ctx, tree, "rev-list", "--count", "HEAD")
+		require.NoError(t, err)
+		require.Equal(t, "3\n", out)
+
+		out, err = gitOutput(ctx, tree, "describe", "--tags", "--abbrev=0")
+		require.NoError(t, err)
+		require.Equal(t, "v0.2.0\n", out)
+	})
+
+	t.Run("skip submodules", func(ctx context.Context, t *testctx.T) {
+		ents, err := ref.Tree(dagger.GitRefTreeOpts{SkipSubmodules: true}).Entries(ctx, dagger.DirectoryEntriesOpts{Path: "sub"})
+		require.NoError(t, err)
+		require.Empty(t, ents)
+	})
+}
+
+// gitLFSService serves a repo with a Git LFS object over dumb HTTP, along with
+// a minimal LFS server counting the objects downloaded from it at /downloads.
+// Its "main" branch is one commit ahead of the "v1" tag, with the same LFS
+// object.
+func gitLFSService(ctx context.Context, t *testctx.T, c *dagger.Client, asset string) (*dagger.Service, string) {
+	t.Helper()
+
+	srv := c.Container().
+		From(alpineImage).
+		WithExec([]string{"apk", "add", "git", "git-lfs"}).
+		WithEnvVariable("GIT_AUTHOR_NAME", "Author").
+		WithEnvVariable("GIT_AUTHOR_EMAIL", "author@localhost").
+		WithEnvVariable("GIT_COMMITTER_NAME", "Committer").
+		WithEnvVariable("GIT_COMMITTER_EMAIL", "committer@localhost").
+		WithNewFile("/root/repo/asset.bin", asset).
+		WithWorkdir("/root/repo").
+		WithExec([]string{"sh", "-e", "-x", "-c", `
+			git init -b main
+			git lfs install --local
+			git lfs track "*.bin"
+			git add .gitattributes asset.bin
+			git commit -m "add asset"
+			git tag v1
+			echo hello > README.md
+			git add README.md
+			git commit -m "add readme"
+			mkdir -p /root/srv/lfs
+			git clone --bare . /root/srv/repo.git
+			git -C /root/srv/repo.git update-server-info
+			find .git/lfs/objects -type f -exec cp {} /root/srv/lfs/ \;
+		`}).
+		Directory("/root/srv")
+
+	lfsServer := c.Container().
+		From("python").
+		WithDirectory("/srv", srv).
+		WithWorkdir("/srv").
+		WithNewFile("/server.py", `
+import http.server
+import json
+
+downloads = 0
+
+class Handler(http.server.SimpleHTTPRequestHandler):
+    def do_GET(self):
+        global downloads
+        if self.path == "/downloads":
+            return self.reply("text/plain", str(downloads).encode())
+        if self.path.startswith("/lfs/"):
+            downloads += 1
+        return super().do_GET()
+
+    def do_POST(self):
+        if self.path != "/repo.git/info/lfs/objects/batch":
+            return self.send_error(404)
+        req = json.loads(self.rfile.read(int(self.headers["Content-Length"])))
+        objects = [{
+            "oid": obj["oid"],
+            "size": obj["size"],
+            "actions": {"download": {"href": "http://%s/lfs/%s" % (self.headers["Host"], obj["oid"])}},
+        } for obj in req["objects"]]
+        body = json.dumps({"transfer": "basic", "objects": objects}).encode()
+        return self.reply("application/vnd.git-lfs+json", body)
+
+    def reply(self, contentType, body):
+        self.send_response(200)
+        self.send_header("Content-Type", contentType)
+        self.send_header("Content-Length", str(len(body)))
+        self.end_headers()
+        self.wfile.write(body)
+
+http.server.ThreadingHTTPServer(("", 8000), Handler).serve_forever()
+`).
+		WithExposedPort(8000).
+		WithDefaultArgs([]string{"python", "/server.py"}).
+		AsService()
+
+	lfsHost, err := lfsServer.Hostname(ctx)
+	require.NoError(t, err)
+
+	return lfsServer, fmt.Sprintf("http://%s:8000/repo.git", lfsHost)
+}
+
+func (GitSuite) TestTreeLFS(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	asset := "asset " + identity.NewID() + "\n"
+	lfsServer, repoURL := gitLFSService(ctx, t, c, asset)
+	repo := c.Git(repoURL, dagger.GitOpts{ExperimentalServiceHost: lfsServer})
+
+	downloads := func(ctx context.Context) string {
+		out, err := c.Container().From(alpineImage).
+			WithServiceBinding("lfs", lfsServer).
+			WithEnvVariable("CACHEBUSTER", identity.NewID()).
+			WithExec([]string{"wget", "-qO-", "http://lfs:8000/downloads"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		return out
+	}
+
+	pointer, err := repo.Branch("main").Tree().File("asset.bin").Contents(ctx)
+	require.NoError(t, err)
+	require.Contains(t, pointer, "version https://git-lfs.github.com/spec/v1")
+	require.Equal(t, "0", downloads(ctx))
+
+	contents, err := repo.Branch("main").Tree(dagger.GitRefTreeOpts{Lfs: true}).File("asset.bin").Contents(ctx)
+	require.NoError(t, err)
+	require.Equal(t, asset, contents)
+	require.Equal(t, "1", downloads(ctx))
+
+	// the object is already in the engine for another commit
+	contents, err = repo.Tag("v1").Tree(dagger.GitRefTreeOpts{Lfs: true, DiscardGitDir: true}).File("asset.bin").Contents(ctx)
+	require.NoError(t, err)
+	require.Equal(t, asset, contents)
+	require.Equal(t, "1", downloads(ctx))
+}
+
+func (GitSuite) TestVerify(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	// main is signed with Alice's GPG key and tagged v1, signed with Bob's
+	// SSH key; the unsigned tag points at its unsigned parent
+	signed := c.Container().
+		From(alpineImage).
+		WithExec([]string{"apk", "add", "git", "gnupg", "openssh-keygen"}).
+		WithEnvVariable("GIT_AUTHOR_NAME", "Author").
+		WithEnvVariable("GIT_AUTHOR_EMAIL", "author@localhost").
+		WithEnvVariable("GIT_COMMITTER_NAME", "Committer").
+		WithEnvVariable("GIT_COMMITTER_EMAIL", "committer@localhost").
+		WithEnvVariable("GNUPGHOME", "/root/gnupg").
+		WithWorkdir("/root/repo").
+		WithExec([]string{"sh", "-e", "-x", "-c", `
+			mkdir -m 700 /root/gnupg
+			mkdir /root/keys /root/srv
+			gpg --batch --passphrase '' --quick-gen-key "Alice <alice@example.com>" ed25519 sign never
+			gpg --armor --export alice@example.com > /root/keys/alice.asc
+			gpg --batch --passphrase '' --quick-gen-key "Mallory <mallory@example.com>" ed25519 sign never
+			gpg --armor --export mallory@example.com > /root/keys/mallory.asc
+			ssh-keygen -q -t ed25519 -N "" -f /root/keys/bob
+			echo "bob@example.com $(cat /root/keys/bob.pub)" > /root/keys/allowed_signers
+			ssh-keygen -q -t ed25519 -N "" -f /root/keys/eve
+			echo "eve@example.com $(cat /root/keys/eve.pub)" > /root/keys/untrusted_signers
+			git init -b main
+			echo hello > README.md
+			git add README.md
+			git commit -m unsigned
+			git tag unsigned
+			git -c user.signingkey=alice@example.com commit -S --allow-empty -m signed
+			git -c gpg.format=ssh -c user.signingkey=/root/keys/bob tag -s v1 -m v1
+			git clone --bare . /root/srv/repo.git
+		`})
+	keys := signed.Directory("/root/keys")
+
+	gitDaemon, repoURL := gitDaemonService(ctx, t, c, signed.Directory("/root/srv"))
+	repo := c.Git(repoURL, dagger.GitOpts{ExperimentalServiceHost: gitDaemon})
+
+	t.Run("gpg signed commit", func(ctx context.Context, t *testctx.T) {
+		verified := repo.Branch("main").Verify(dagger.GitRefVerifyOpts{
+			Keys: []*dagger.File{keys.File("mallory.asc"), keys.File("alice.asc")},
+		})
+
+		format, err := verified.Signature().Format(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "gpg", format)
+		signer, err := verified.Signature().Signer(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "Alice <alice@example.com>", signer)
+		key, err := verified.Signature().Key(ctx)
+		require.NoError(t, err)
+		require.Regexp(t, `^[0-9A-F]{40}$`, key)
+
+		commit, err := repo.Branch("main").Commit(ctx)
+		require.NoError(t, err)
+		verifiedCommit, err := verified.Commit(ctx)
+		require.NoError(t, err)
+		require.Equal(t, commit, verifiedCommit)
+
+		contents, err := verified.Tree().File("README.md").Contents(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "hello\n", contents)
+	})
+
+	t.Run("ssh signed tag", func(ctx context.Context, t *testctx.T) {
+		sig := repo.Tag("v1").Verify(dagger.GitRefVerifyOpts{
+			AllowedSigners: keys.File("allowed_signers"),
+		}).Signature()
+
+		format, err := sig.Format(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "ssh", format)
+		signer, err := sig.Signer(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "bob@example.com", signer)
+		key, err := sig.Key(ctx)
+		require.NoError(t, err)
+		require.Regexp(t, `^SHA256:`, key)
+	})
+
+	t.Run("unsigned", func(ctx context.Context, t *testctx.T) {
+		_, err := repo.Tag("unsigned").Verify(dagger.GitRefVerifyOpts{
+			Keys: []*dagger.File{keys.File("alice.asc")},
+		}).Commit(ctx)
+		requireErrOut(t, err, "commit unsigned is not signed")
+	})
+
+	t.Run("untrusted", func(ctx context.Context, t *testctx.T) {
+		_, err := repo.Branch("main").Verify(dagger.GitRefVerifyOpts{
+			Keys: []*dagger.File{keys.File("mallory.asc")},
+		}).Commit(ctx)
+		requireErrOut(t, err, "commit main does not have a trusted signature")
+
+		_, err = repo.Tag("v1").Verify(dagger.GitRefVerifyOpts{
+			AllowedSigners: keys.File("untrusted_signers"),
+		}).Commit(ctx)
+		requireErrOut(t, err, "tag v1 does not have a trusted signature")
+	})
+
+	t.Run("no keys", func(ctx context.Context, t *testctx.T) {
+		_, err := repo.Branch("main").Verify().Commit(ctx)
+		requireErrOut(t, err, "at least one key or an allowed signers file is required")
+	})
+}
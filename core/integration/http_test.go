@@ -2,10 +2,12 @@ package core
 
 import (
 	"context"
+	"fmt"
 	"testing"
 
 	"github.com/dagger/dagger/testctx"
 	"github.com/moby/buildkit/identity"
+	"github.com/opencontainers/go-digest"
 	"github.com/stretchr/testify/require"
 
 	"dagger.io/dagger"
@@ -65,3 +67,133 @@ func (HTTPSuite) TestHTTPServiceStableDigest(ctx context.Context, t *testctx.T)
 	c2 := connect(ctx, t)
 	require.Equal(t, hostname(c1), hostname(c2))
 }
+
+func (HTTPSuite) TestHTTPChecksum(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	content := identity.NewID()
+	svc, url := httpService(ctx, t, c, content)
+	checksum := digest.FromString(content).String()
+
+	contents, err := c.HTTP(url, dagger.HTTPOpts{
+		ExperimentalServiceHost: svc,
+		Checksum:                checksum,
+		Name:                    "index.html",
+	}).Contents(ctx)
+	require.NoError(t, err)
+	require.Equal(t, content, contents)
+
+	// the content is cached by its checksum, so the server isn't contacted
+	contents, err = c.HTTP("http://unreachable.invalid/index.html", dagger.HTTPOpts{
+		Checksum: checksum,
+		Name:     "index.html",
+	}).Contents(ctx)
+	require.NoError(t, err)
+	require.Equal(t, content, contents)
+
+	_, err = c.HTTP(url, dagger.HTTPOpts{
+		ExperimentalServiceHost: svc,
+		Checksum:                digest.FromString("something else").String(),
+	}).Contents(ctx)
+	requireErrOut(t, err, "digest mismatch")
+
+	_, err = c.HTTP(url, dagger.HTTPOpts{
+		ExperimentalServiceHost: svc,
+		Checksum:                "not-a-digest",
+	}).Contents(ctx)
+	requireErrOut(t, err, "invalid checksum")
+}
+
+func (HTTPSuite) TestHTTPHeadersAndAuth(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	// echoes the X-Custom and Authorization headers, requiring the latter
+	// for /private
+	svc := c.Container().
+		From("python").
+		WithNewFile("/server.py", `
+import http.server
+
+class Handler(http.server.BaseHTTPRequestHandler):
+    def do_GET(self):
+        auth = self.headers.get("Authorization")
+        if self.path.startswith("/private") and auth not in ("Bearer s3cr3t", "Basic dXNlcjpwYXNz"):
+            self.send_response(401)
+            self.end_headers()
+            return
+        body = ("%s %s" % (self.headers.get("X-Custom"), auth)).encode()
+        self.send_response(200)
+        self.send_header("Content-Length", str(len(body)))
+        self.end_headers()
+        self.wfile.write(body)
+
+http.server.ThreadingHTTPServer(("", 8000), Handler).serve_forever()
+`).
+		WithExposedPort(8000).
+		WithDefaultArgs([]string{"python", "/server.py"}).
+		AsService()
+	url, err := svc.Endpoint(ctx, dagger.ServiceEndpointOpts{Scheme: "http"})
+	require.NoError(t, err)
+	buster := identity.NewID()
+
+	contents, err := c.HTTP(url+"/public?"+buster, dagger.HTTPOpts{
+		ExperimentalServiceHost: svc,
+		Headers:                 []dagger.HTTPHeader{{Name: "X-Custom", Value: "custom"}},
+	}).Contents(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "custom None", contents)
+
+	contents, err = c.HTTP(url+"/private?"+buster, dagger.HTTPOpts{
+		ExperimentalServiceHost: svc,
+		AuthToken:               c.SetSecret("token", "s3cr3t"),
+	}).Contents(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "None Bearer s3cr3t", contents)
+
+	contents, err = c.HTTP(url+"/private?"+buster, dagger.HTTPOpts{
+		ExperimentalServiceHost: svc,
+		AuthHeader:              c.SetSecret("header", "Basic dXNlcjpwYXNz"),
+	}).Contents(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "None Basic dXNlcjpwYXNz", contents)
+
+	_, err = c.HTTP(url+"/private?"+buster, dagger.HTTPOpts{
+		ExperimentalServiceHost: svc,
+	}).Contents(ctx)
+	requireErrOut(t, err, "invalid response status 401")
+
+	_, err = c.HTTP(url+"/public?"+buster, dagger.HTTPOpts{
+		ExperimentalServiceHost: svc,
+		Headers:                 []dagger.HTTPHeader{{Name: "Bad Name", Value: "value"}},
+	}).Contents(ctx)
+	requireErrOut(t, err, `invalid header "Bad Name"`)
+}
+
+func (HTTPSuite) TestHTTPNameAndPermissions(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	svc, url := httpService(ctx, t, c, "#!/bin/sh\necho hello\n")
+
+	file := c.HTTP(url, dagger.HTTPOpts{
+		ExperimentalServiceHost: svc,
+		Name:                    "hello.sh",
+		Permissions:             0o755,
+	})
+	name, err := file.Name(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "hello.sh", name)
+
+	out, err := c.Container().
+		From(alpineImage).
+		WithMountedFile("/bin/hello.sh", file).
+		WithExec([]string{"sh", "-c", "stat -c %a /bin/hello.sh && hello.sh"}).
+		Stdout(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "755\nhello\n", out)
+
+	_, err = c.HTTP(url, dagger.HTTPOpts{
+		ExperimentalServiceHost: svc,
+		Name:                    "../escape",
+	}).Contents(ctx)
+	requireErrOut(t, err, fmt.Sprintf("invalid file name %q", "../escape"))
+}
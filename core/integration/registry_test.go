@@ -0,0 +1,193 @@
+package core
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/require"
+
+	"dagger.io/dagger"
+	"github.com/dagger/dagger/testctx"
+)
+
+type RegistrySuite struct{}
+
+func TestRegistry(t *testing.T) {
+	testctx.Run(testCtx, t, RegistrySuite{}, Middleware()...)
+}
+
+func (RegistrySuite) TestInspect(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	variants := make([]*dagger.Container, 0, len(platformToUname))
+	for platform := range platformToUname {
+		variants = append(variants, c.Container(dagger.ContainerOpts{Platform: platform}).
+			From(alpineImage).
+			WithEnvVariable("PLATFORM", string(platform)).
+			WithLabel("org.example.b", "two").
+			WithLabel("org.example.a", "one").
+			WithEntrypoint([]string{"/bin/sh", "-c"}).
+			WithDefaultArgs([]string{"echo hello"}).
+			WithWorkdir("/work").
+			WithUser("nobody"))
+	}
+
+	testRef := registryRef("registry-inspect")
+	pushedRef, err := c.Container().Publish(ctx, testRef, dagger.ContainerPublishOpts{
+		PlatformVariants: variants,
+	})
+	require.NoError(t, err)
+	_, pushedDigest, ok := strings.Cut(pushedRef, "@")
+	require.True(t, ok)
+
+	repo, tag := testRef[:strings.LastIndex(testRef, ":")], testRef[strings.LastIndex(testRef, ":")+1:]
+
+	reg := c.Registry(testRef)
+
+	t.Run("address", func(ctx context.Context, t *testctx.T) {
+		address, err := reg.Address(ctx)
+		require.NoError(t, err)
+		require.Equal(t, testRef, address)
+
+		address, err = c.Registry(repo).Address(ctx)
+		require.NoError(t, err)
+		require.Equal(t, repo+":latest", address)
+	})
+
+	t.Run("tags", func(ctx context.Context, t *testctx.T) {
+		_, err := c.Container().From(alpineImage).Publish(ctx, repo+":other")
+		require.NoError(t, err)
+
+		tags, err := c.Registry(repo).Tags(ctx)
+		require.NoError(t, err)
+		require.Contains(t, tags, tag)
+		require.Contains(t, tags, "other")
+	})
+
+	t.Run("digest", func(ctx context.Context, t *testctx.T) {
+		dgst, err := reg.Digest(ctx)
+		require.NoError(t, err)
+		require.Equal(t, pushedDigest, dgst)
+
+		// also resolvable by digest
+		dgst, err = c.Registry(pushedRef).Digest(ctx)
+		require.NoError(t, err)
+		require.Equal(t, pushedDigest, dgst)
+	})
+
+	t.Run("platforms", func(ctx context.Context, t *testctx.T) {
+		platforms, err := reg.Platforms(ctx)
+		require.NoError(t, err)
+		expected := make([]dagger.Platform, 0, len(platformToUname))
+		for platform := range platformToUname {
+			expected = append(expected, platform)
+		}
+		require.ElementsMatch(t, expected, platforms)
+	})
+
+	t.Run("manifest digest", func(ctx context.Context, t *testctx.T) {
+		parsedRef, err := name.ParseReference(pushedRef, name.Insecure)
+		require.NoError(t, err)
+		index, err := remote.Index(parsedRef, remote.WithTransport(http.DefaultTransport))
+		require.NoError(t, err)
+		indexManifest, err := index.IndexManifest()
+		require.NoError(t, err)
+		expected := map[dagger.Platform]string{}
+		for _, m := range indexManifest.Manifests {
+			expected[dagger.Platform(m.Platform.OS+"/"+m.Platform.Architecture)] = m.Digest.String()
+		}
+
+		for platform := range platformToUname {
+			dgst, err := reg.ManifestDigest(ctx, dagger.RegistryManifestDigestOpts{
+				Platform: platform,
+			})
+			require.NoError(t, err)
+			require.Equal(t, expected[platform], dgst)
+		}
+	})
+
+	t.Run("config", func(ctx context.Context, t *testctx.T) {
+		for platform := range platformToUname {
+			cfg := reg.Config(dagger.RegistryConfigOpts{Platform: platform})
+
+			actualPlatform, err := cfg.Platform(ctx)
+			require.NoError(t, err)
+			require.Equal(t, platform, actualPlatform)
+
+			entrypoint, err := cfg.Entrypoint(ctx)
+			require.NoError(t, err)
+			require.Equal(t, []string{"/bin/sh", "-c"}, entrypoint)
+
+			args, err := cfg.DefaultArgs(ctx)
+			require.NoError(t, err)
+			require.Equal(t, []string{"echo hello"}, args)
+
+			workdir, err := cfg.Workdir(ctx)
+			require.NoError(t, err)
+			require.Equal(t, "/work", workdir)
+
+			user, err := cfg.User(ctx)
+			require.NoError(t, err)
+			require.Equal(t, "nobody", user)
+
+			env, err := cfg.EnvVariables(ctx)
+			require.NoError(t, err)
+			envMap := map[string]string{}
+			for _, v := range env {
+				name, err := v.Name(ctx)
+				require.NoError(t, err)
+				value, err := v.Value(ctx)
+				require.NoError(t, err)
+				envMap[name] = value
+			}
+			require.Equal(t, string(platform), envMap["PLATFORM"])
+			require.Contains(t, envMap, "PATH")
+
+			labels, err := cfg.Labels(ctx)
+			require.NoError(t, err)
+			var labelNames []string
+			for _, l := range labels {
+				name, err := l.Name(ctx)
+				require.NoError(t, err)
+				labelNames = append(labelNames, name)
+			}
+			require.Equal(t, []string{"org.example.a", "org.example.b"}, labelNames)
+		}
+	})
+
+	t.Run("not found", func(ctx context.Context, t *testctx.T) {
+		_, err := c.Registry(registryRef("registry-inspect-missing")).Digest(ctx)
+		requireErrOut(t, err, "not found")
+	})
+
+	t.Run("invalid address", func(ctx context.Context, t *testctx.T) {
+		_, err := c.Registry("Not A Valid Ref").Address(ctx)
+		requireErrOut(t, err, "invalid image address")
+	})
+}
+
+func (RegistrySuite) TestRegistryAuth(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	testRef := privateRegistryRef("registry-inspect-auth")
+	pushedRef, err := c.Container().From(alpineImage).
+		WithRegistryAuth(privateRegistryHost, "john", c.SetSecret("this-secret", "xFlejaPdjrt25Dvr")).
+		Publish(ctx, testRef)
+	require.NoError(t, err)
+	_, pushedDigest, _ := strings.Cut(pushedRef, "@")
+
+	// the credentials are registered in the session by withRegistryAuth, and
+	// used by the registry API
+	dgst, err := c.Registry(testRef).Digest(ctx)
+	require.NoError(t, err)
+	require.Equal(t, pushedDigest, dgst)
+
+	repo, tag := testRef[:strings.LastIndex(testRef, ":")], testRef[strings.LastIndex(testRef, ":")+1:]
+	tags, err := c.Registry(repo).Tags(ctx)
+	require.NoError(t, err)
+	require.Contains(t, tags, tag)
+}
@@ -5,8 +5,12 @@ import (
 	"context"
 	_ "embed"
 	"io"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/moby/buildkit/identity"
 	"github.com/stretchr/testify/require"
 
 	"github.com/dagger/dagger/dagql/call"
@@ -186,3 +190,35 @@ func (SecretSuite) TestBigScrubbed(ctx context.Context, t *testctx.T) {
 
 //go:embed testdata/secretkey.txt
 var secretKeyBytes []byte
+
+func (SecretSuite) TestCmdCachedPerSession(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	// the command logs every time the client resolves it
+	resolvedLog := filepath.Join(t.TempDir(), "resolved")
+	secret := c.Secret("cmd://echo resolved >> " + resolvedLog + "; echo hunter2")
+	resolved := func() int {
+		content, err := os.ReadFile(resolvedLog)
+		require.NoError(t, err)
+		return strings.Count(string(content), "resolved\n")
+	}
+
+	useSecret := func() {
+		_, err := c.Container().From(alpineImage).
+			WithEnvVariable("CACHEBUST", identity.NewID()).
+			WithSecretVariable("SECRET", secret).
+			WithExec([]string{"sh", "-c", `test "$SECRET" = hunter2`}).
+			Sync(ctx)
+		require.NoError(t, err)
+	}
+
+	useSecret()
+	useSecret()
+	require.Equal(t, 1, resolved())
+
+	require.NoError(t, secret.Refresh(ctx))
+	require.Equal(t, 2, resolved())
+
+	useSecret()
+	require.Equal(t, 2, resolved())
+}
@@ -723,6 +723,501 @@ func (ServiceSuite) TestPortsSkipHealthCheck(ctx context.Context, t *testctx.T)
 	})
 }
 
+func (ServiceSuite) TestHealthcheck(ctx context.Context, t *testctx.T) {
+	t.Run("http path", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+
+		// the server only starts serving the path after a few seconds
+		srv := c.Container().
+			From(alpineImage).
+			WithWorkdir("/srv").
+			WithExposedPort(8000, dagger.ContainerWithExposedPortOpts{
+				HealthcheckPath:   "/ready",
+				HealthcheckStatus: 200,
+			}).
+			WithDefaultArgs([]string{"sh", "-c", "(sleep 3 && echo ok > ready) & httpd -v -f -p 8000"}).
+			AsService()
+
+		_, err := srv.Start(ctx)
+		require.NoError(t, err)
+
+		out, err := c.Container().
+			From(alpineImage).
+			WithServiceBinding("www", srv).
+			WithExec([]string{"wget", "-qO-", "http://www:8000/ready"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "ok\n", out)
+	})
+
+	t.Run("http unexpected status", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+
+		srv := c.Container().
+			From(alpineImage).
+			WithWorkdir("/srv").
+			WithExposedPort(8000, dagger.ContainerWithExposedPortOpts{
+				HealthcheckPath: "/missing",
+			}).
+			WithDefaultArgs([]string{"httpd", "-v", "-f", "-p", "8000"}).
+			AsService(dagger.ContainerAsServiceOpts{
+				HealthcheckInterval: 1,
+				HealthcheckRetries:  2,
+			})
+
+		_, err := srv.Start(ctx)
+		requireErrOut(t, err, "unexpected status 404")
+	})
+
+	t.Run("exec", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+
+		srv := c.Container().
+			From(alpineImage).
+			WithDefaultArgs([]string{"sh", "-c", "sleep 3 && touch /tmp/ready && sleep infinity"}).
+			AsService(dagger.ContainerAsServiceOpts{
+				HealthcheckExec:     []string{"test", "-f", "/tmp/ready"},
+				HealthcheckInterval: 1,
+			})
+
+		_, err := srv.Start(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("exec never healthy", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+
+		msg := identity.NewID()
+		srv := c.Container().
+			From(alpineImage).
+			WithDefaultArgs([]string{"sleep", "infinity"}).
+			AsService(dagger.ContainerAsServiceOpts{
+				HealthcheckExec:     []string{"sh", "-c", "echo " + msg + " >&2; exit 1"},
+				HealthcheckInterval: 1,
+				HealthcheckRetries:  2,
+			})
+
+		_, err := srv.Start(ctx)
+		requireErrOut(t, err, msg)
+	})
+
+	t.Run("exec timeout", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+
+		srv := c.Container().
+			From(alpineImage).
+			WithDefaultArgs([]string{"sleep", "infinity"}).
+			AsService(dagger.ContainerAsServiceOpts{
+				HealthcheckExec:     []string{"sleep", "60"},
+				HealthcheckTimeout:  1,
+				HealthcheckRetries:  1,
+				HealthcheckInterval: 1,
+			})
+
+		_, err := srv.Start(ctx)
+		requireErrOut(t, err, "timed out after 1s")
+	})
+
+	t.Run("exec after service starts", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+
+		// the probe only passes if the service's process got to run first
+		srv := c.Container().
+			From(alpineImage).
+			WithDefaultArgs([]string{"sh", "-c", "touch /tmp/started && sleep infinity"}).
+			AsService(dagger.ContainerAsServiceOpts{
+				HealthcheckExec:     []string{"test", "-f", "/tmp/started"},
+				HealthcheckInterval: 1,
+				HealthcheckRetries:  5,
+			})
+
+		_, err := srv.Start(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("https", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+
+		// the port speaks plain HTTP, so an HTTPS check never succeeds
+		srv := c.Container().
+			From(alpineImage).
+			WithWorkdir("/srv").
+			WithNewFile("/srv/ready", "ok").
+			WithExposedPort(8000, dagger.ContainerWithExposedPortOpts{
+				HealthcheckPath:   "/ready",
+				HealthcheckScheme: dagger.HealthcheckSchemeHttps,
+			}).
+			WithDefaultArgs([]string{"httpd", "-v", "-f", "-p", "8000"}).
+			AsService(dagger.ContainerAsServiceOpts{
+				HealthcheckInterval: 1,
+				HealthcheckRetries:  2,
+			})
+
+		_, err := srv.Start(ctx)
+		requireErrOut(t, err, "https://")
+	})
+
+	t.Run("scheme or status without path", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+
+		_, err := c.Container().
+			From(alpineImage).
+			WithExposedPort(8000, dagger.ContainerWithExposedPortOpts{
+				HealthcheckScheme: dagger.HealthcheckSchemeHttps,
+			}).
+			Sync(ctx)
+		requireErrOut(t, err, "require a health check path")
+
+		_, err = c.Container().
+			From(alpineImage).
+			WithExposedPort(8000, dagger.ContainerWithExposedPortOpts{
+				HealthcheckStatus: 204,
+			}).
+			Sync(ctx)
+		requireErrOut(t, err, "require a health check path")
+	})
+}
+
+func (ServiceSuite) TestRestartPolicy(ctx context.Context, t *testctx.T) {
+	// counts its runs in a cache volume and serves the count; the first run
+	// crashes after a few seconds
+	const script = `n=$(( $(cat /runs/n 2>/dev/null || echo 0) + 1 ))
+echo $n > /runs/n
+mkdir -p /srv && echo $n > /srv/index.html
+httpd -f -p 8000 -h /srv &
+if [ $n -lt 2 ]; then sleep 3; exit 1; fi
+wait`
+
+	// waits until the service serves the given run count
+	const waitForRun = `for i in $(seq $TRIES); do
+  if [ "$(wget -qO- http://www:8000/ 2>/dev/null)" = "$RUN" ]; then exit 0; fi
+  sleep 1
+done
+exit 1`
+
+	t.Run("on failure", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+
+		srv := c.Container().
+			From(alpineImage).
+			WithMountedCache("/runs", c.CacheVolume(identity.NewID())).
+			WithExposedPort(8000).
+			WithDefaultArgs([]string{"sh", "-c", script}).
+			AsService().
+			WithRestartPolicy(dagger.ServiceRestartPolicyOnFailure)
+
+		// the restarted service is reachable at the same hostname
+		_, err := c.Container().
+			From(alpineImage).
+			WithServiceBinding("www", srv).
+			WithEnvVariable("RUN", "2").
+			WithEnvVariable("TRIES", "60").
+			WithExec([]string{"sh", "-c", waitForRun}).
+			Sync(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("on failure with services only network", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+
+		srv := c.Container().
+			From(alpineImage).
+			WithMountedCache("/runs", c.CacheVolume(identity.NewID())).
+			WithExposedPort(8000).
+			WithDefaultArgs([]string{"sh", "-c", script}).
+			AsService().
+			WithRestartPolicy(dagger.ServiceRestartPolicyOnFailure)
+
+		// the restarted service comes back with a new IP, which the running
+		// client's hosts file and network rules are updated with
+		_, err := c.Container().
+			From(alpineImage).
+			WithServiceBinding("www", srv).
+			WithEnvVariable("RUN", "2").
+			WithEnvVariable("TRIES", "60").
+			WithExec([]string{"sh", "-c", waitForRun}, dagger.ContainerWithExecOpts{
+				NetworkMode: dagger.NetworkModeServicesOnly,
+			}).
+			Sync(ctx)
+		require.NoError(t, err)
+	})
+
+	t.Run("never", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+
+		srv := c.Container().
+			From(alpineImage).
+			WithMountedCache("/runs", c.CacheVolume(identity.NewID())).
+			WithExposedPort(8000).
+			WithDefaultArgs([]string{"sh", "-c", script}).
+			AsService().
+			WithRestartPolicy(dagger.ServiceRestartPolicyNever)
+
+		_, err := c.Container().
+			From(alpineImage).
+			WithServiceBinding("www", srv).
+			WithEnvVariable("RUN", "2").
+			WithEnvVariable("TRIES", "10").
+			WithExec([]string{"sh", "-c", waitForRun}).
+			Sync(ctx)
+		require.Error(t, err)
+	})
+
+	t.Run("liveness", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+
+		// the first run stops being healthy after a few seconds, without exiting
+		srv := c.Container().
+			From(alpineImage).
+			WithMountedCache("/runs", c.CacheVolume(identity.NewID())).
+			WithExposedPort(8000).
+			WithDefaultArgs([]string{"sh", "-c", `n=$(( $(cat /runs/n 2>/dev/null || echo 0) + 1 ))
+echo $n > /runs/n
+mkdir -p /srv && echo $n > /srv/index.html
+touch /tmp/healthy
+if [ $n -lt 2 ]; then (sleep 3 && rm /tmp/healthy) & fi
+exec httpd -f -p 8000 -h /srv`}).
+			AsService(dagger.ContainerAsServiceOpts{
+				HealthcheckExec: []string{"test", "-f", "/tmp/healthy"},
+			}).
+			WithLivenessProbe(dagger.ServiceWithLivenessProbeOpts{
+				Interval:         1,
+				FailureThreshold: 2,
+			}).
+			WithRestartPolicy(dagger.ServiceRestartPolicyOnFailure)
+
+		_, err := c.Container().
+			From(alpineImage).
+			WithServiceBinding("www", srv).
+			WithEnvVariable("RUN", "2").
+			WithEnvVariable("TRIES", "60").
+			WithExec([]string{"sh", "-c", waitForRun}).
+			Sync(ctx)
+		require.NoError(t, err)
+	})
+}
+
+func (ServiceSuite) TestLogs(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	srv := c.Container().
+		From(alpineImage).
+		WithExposedPort(8000).
+		WithEnvVariable("CACHEBUST", identity.NewID()).
+		WithDefaultArgs([]string{"sh", "-c", "echo hello; echo oops >&2; echo bye; exec httpd -f -p 8000"}).
+		AsService()
+
+	_, err := srv.Stdout(ctx)
+	require.Error(t, err, "service has not been started")
+
+	srv, err = srv.Start(ctx)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		out, err := srv.Stdout(ctx)
+		return err == nil && out == "hello\nbye\n"
+	}, time.Minute, 100*time.Millisecond)
+
+	out, err := srv.Stdout(ctx, dagger.ServiceStdoutOpts{Tail: 1})
+	require.NoError(t, err)
+	require.Equal(t, "bye\n", out)
+
+	out, err = srv.Stdout(ctx, dagger.ServiceStdoutOpts{Since: time.Now().Add(time.Hour).Format(time.RFC3339)})
+	require.NoError(t, err)
+	require.Empty(t, out)
+
+	out, err = srv.Stderr(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "oops\n", out)
+
+	code, err := srv.ExitCode(ctx)
+	require.NoError(t, err)
+	require.Zero(t, code, "still running")
+
+	_, err = srv.Stop(ctx, dagger.ServiceStopOpts{Kill: true})
+	require.NoError(t, err)
+
+	// still available once stopped
+	out, err = srv.Stdout(ctx)
+	require.NoError(t, err)
+	require.Equal(t, "hello\nbye\n", out)
+
+	code, err = srv.ExitCode(ctx)
+	require.NoError(t, err)
+	require.NotZero(t, code)
+
+	_, err = srv.Stdout(ctx, dagger.ServiceStdoutOpts{Tail: -1})
+	requireErrOut(t, err, "tail must not be negative")
+}
+
+func (ServiceSuite) TestLogsAcrossRestarts(ctx context.Context, t *testctx.T) {
+	c := connect(ctx, t)
+
+	// the first run crashes, the second keeps running
+	srv := c.Container().
+		From(alpineImage).
+		WithMountedCache("/runs", c.CacheVolume(identity.NewID())).
+		WithExposedPort(8000).
+		WithDefaultArgs([]string{"sh", "-c", `n=$(( $(cat /runs/n 2>/dev/null || echo 0) + 1 ))
+echo $n > /runs/n
+echo "run $n"
+httpd -f -p 8000 &
+if [ $n -lt 2 ]; then sleep 1; exit 3; fi
+wait`}).
+		AsService().
+		WithRestartPolicy(dagger.ServiceRestartPolicyOnFailure)
+
+	srv, err := srv.Start(ctx)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		out, err := srv.Stdout(ctx)
+		return err == nil && out == "run 1\nrun 2\n"
+	}, time.Minute, 100*time.Millisecond)
+
+	// the crash is still reported while the restarted run is going
+	code, err := srv.ExitCode(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, code)
+}
+
+func (ServiceSuite) TestCompose(ctx context.Context, t *testctx.T) {
+	t.Run("directory", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+
+		proj := c.Directory().
+			WithNewFile("compose.yaml", `
+services:
+  web:
+    build:
+      context: ./web
+      args:
+        GREETING: ${GREETING}
+    environment:
+      BACKEND: http://api:8000
+    ports:
+      - "8080:80"
+    depends_on:
+      api:
+        condition: service_healthy
+  api:
+    image: `+alpineImage+`
+    working_dir: /srv
+    command: sh -c 'echo "$$MESSAGE" > /srv/index.html && sleep 2 && touch /tmp/ready && httpd -v -f -p 8000'
+    environment:
+      - MESSAGE=hello from api
+      - CACHEBUST=`+identity.NewID()+`
+    expose:
+      - 8000
+    volumes:
+      - data:/data
+      - ./static:/srv/static
+    healthcheck:
+      test: ["CMD", "test", "-f", "/tmp/ready"]
+      interval: 1s
+volumes:
+  data:
+`).
+			WithNewFile(".env", "GREETING=hi\n").
+			WithNewFile("static/style.css", "body {}\n").
+			WithNewFile("web/Dockerfile", `FROM `+alpineImage+`
+ARG GREETING
+RUN echo "$GREETING" > /greeting
+CMD ["sh", "-c", "wget -qO- $BACKEND > /index.html && cat /greeting /index.html && httpd -v -f -p 80 -h /"]
+`).
+			AsCompose()
+
+		name, err := proj.Name(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "default", name)
+
+		names, err := proj.ServiceNames(ctx)
+		require.NoError(t, err)
+		require.Equal(t, []string{"api", "web"}, names)
+
+		out, err := c.Container().
+			From(alpineImage).
+			WithServiceBinding("web", proj.Service("web")).
+			WithExec([]string{"wget", "-qO-", "http://web:80/index.html"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "hello from api\n", out)
+
+		out, err = c.Container().
+			From(alpineImage).
+			WithServiceBinding("api", proj.Service("api")).
+			WithExec([]string{"wget", "-qO-", "http://api:8000/static/style.css"}).
+			Stdout(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "body {}\n", out)
+
+		ports, err := proj.Service("web").Ports(ctx)
+		require.NoError(t, err)
+		require.Len(t, ports, 1)
+		port, err := ports[0].Port(ctx)
+		require.NoError(t, err)
+		require.Equal(t, 80, port)
+	})
+
+	t.Run("file", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+
+		proj := c.Directory().
+			WithNewFile("docker-compose.yml", `
+name: files
+services:
+  www:
+    image: `+alpineImage+`
+    command: ["httpd", "-v", "-f", "-p", "8000"]
+    expose: [8000]
+`).
+			File("docker-compose.yml").
+			AsCompose(dagger.FileAsComposeOpts{ProjectName: "renamed"})
+
+		name, err := proj.Name(ctx)
+		require.NoError(t, err)
+		require.Equal(t, "renamed", name)
+
+		hn, err := proj.Service("www").Hostname(ctx)
+		require.NoError(t, err)
+		require.NotEmpty(t, hn)
+	})
+
+	t.Run("errors", func(ctx context.Context, t *testctx.T) {
+		c := connect(ctx, t)
+
+		_, err := c.Directory().
+			WithNewFile("compose.yaml", `
+services:
+  a:
+    image: `+alpineImage+`
+    depends_on: [b]
+  b:
+    image: `+alpineImage+`
+    depends_on: [a]
+`).
+			AsCompose().
+			Service("a").
+			ID(ctx)
+		requireErrOut(t, err, "dependency cycle: a -> b -> a")
+
+		_, err = c.Directory().
+			WithNewFile("compose.yaml", `
+services:
+  app:
+    build: .
+`).
+			File("compose.yaml").
+			AsCompose().
+			Service("app").
+			ID(ctx)
+		requireErrOut(t, err, "build requires loading the project from a directory")
+
+		_, err = c.Directory().AsCompose().Name(ctx)
+		requireErrOut(t, err, "no compose file found")
+	})
+}
+
 func (ContainerSuite) TestPortLifecycle(ctx context.Context, t *testctx.T) {
 	c := connect(ctx, t)
 
@@ -2153,12 +2648,19 @@ func gitService(ctx context.Context, t *testctx.T, c *dagger.Client, content *da
 
 func gitServiceWithBranch(ctx context.Context, t *testctx.T, c *dagger.Client, content *dagger.Directory, branchName string) (*dagger.Service, string) {
 	t.Helper()
+	return gitDaemonService(ctx, t, c, makeGitDir(c, content, branchName))
+}
+
+// gitDaemonService serves the bare repo.git in the given directory over the
+// git protocol.
+func gitDaemonService(ctx context.Context, t *testctx.T, c *dagger.Client, srv *dagger.Directory) (*dagger.Service, string) {
+	t.Helper()
 
 	const gitPort = 9418
 	gitDaemon := c.Container().
 		From(alpineImage).
 		WithExec([]string{"apk", "add", "git", "git-daemon"}).
-		WithDirectory("/root/srv", makeGitDir(c, content, branchName)).
+		WithDirectory("/root/srv", srv).
 		WithExposedPort(gitPort).
 		WithDefaultArgs([]string{"sh", "-c", "git daemon --verbose --export-all --base-path=/root/srv"}).
 		AsService()
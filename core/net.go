@@ -15,6 +15,16 @@ type Port struct {
 	Protocol                    NetworkProtocol `field:"true" doc:"The transport layer protocol."`
 	Description                 *string         `field:"true" doc:"The port description."`
 	ExperimentalSkipHealthcheck bool            `field:"true" doc:"Skip the health check when run as a service."`
+
+	// HealthcheckPath, if set, makes the health check send an HTTP GET request
+	// for the path rather than just connecting to the port.
+	HealthcheckPath string
+	// HealthcheckScheme is the scheme of the health check request. If unset,
+	// HTTP is used.
+	HealthcheckScheme HealthcheckScheme
+	// HealthcheckStatus is the status the health check request must respond
+	// with. If zero, any 2xx or 3xx status is accepted.
+	HealthcheckStatus int
 }
 
 func (Port) Type() *ast.Type {
@@ -64,6 +74,76 @@ func (proto NetworkProtocol) Network() string {
 	return strings.ToLower(string(proto))
 }
 
+// HealthcheckScheme is a GraphQL enum type.
+type HealthcheckScheme string
+
+var HealthcheckSchemes = dagql.NewEnum[HealthcheckScheme]()
+
+var (
+	HealthcheckSchemeHTTP  = HealthcheckSchemes.Register("HTTP")
+	HealthcheckSchemeHTTPS = HealthcheckSchemes.Register("HTTPS")
+)
+
+func (scheme HealthcheckScheme) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "HealthcheckScheme",
+		NonNull:   true,
+	}
+}
+
+func (scheme HealthcheckScheme) TypeDescription() string {
+	return "Scheme of the HTTP request used to check a port's health."
+}
+
+func (scheme HealthcheckScheme) Decoder() dagql.InputDecoder {
+	return HealthcheckSchemes
+}
+
+func (scheme HealthcheckScheme) ToLiteral() call.Literal {
+	return HealthcheckSchemes.Literal(scheme)
+}
+
+// URLScheme returns the scheme for the health check request's URL.
+func (scheme HealthcheckScheme) URLScheme() string {
+	if scheme == "" {
+		return "http"
+	}
+	return strings.ToLower(string(scheme))
+}
+
+// NetworkMode is a GraphQL enum type.
+type NetworkMode string
+
+var NetworkModes = dagql.NewEnum[NetworkMode]()
+
+var (
+	NetworkModeDefault = NetworkModes.Register("DEFAULT",
+		`Full network access, including services and the internet.`)
+	NetworkModeServicesOnly = NetworkModes.Register("SERVICES_ONLY",
+		`Only bound services and the engine's DNS server are reachable.`)
+	NetworkModeNone = NetworkModes.Register("NONE",
+		`No network access beyond the loopback interface.`)
+)
+
+func (mode NetworkMode) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "NetworkMode",
+		NonNull:   true,
+	}
+}
+
+func (mode NetworkMode) TypeDescription() string {
+	return "Network access granted to an executed command."
+}
+
+func (mode NetworkMode) Decoder() dagql.InputDecoder {
+	return NetworkModes
+}
+
+func (mode NetworkMode) ToLiteral() call.Literal {
+	return NetworkModes.Literal(mode)
+}
+
 type PortForward struct {
 	Frontend *int            `doc:"Port to expose to clients. If unspecified, a default will be chosen." json:"frontend,omitempty"`
 	Backend  int             `doc:"Destination port for traffic." json:"backend"`
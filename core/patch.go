@@ -0,0 +1,424 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// patchContextLines is the number of unchanged lines surrounding each hunk
+// in generated patches, matching the default of diff -u and git diff.
+const patchContextLines = 3
+
+const noNewlineMarker = `\ No newline at end of file`
+
+var hunkHeaderRegexp = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// filePatch is the set of changes a unified diff makes to a single file.
+type filePatch struct {
+	// OldPath and NewPath are relative to the patched directory. OldPath is
+	// empty for created files and NewPath is empty for deleted files.
+	OldPath string
+	NewPath string
+
+	// Mode is the mode of the resulting file, if the patch specifies one.
+	Mode fs.FileMode
+
+	Binary bool
+	Hunks  []*patchHunk
+}
+
+type patchHunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+
+	// Lines holds the body of the hunk, each prefixed by ' ', '-' or '+' and
+	// including its trailing newline unless the line ends the file without one.
+	Lines []string
+}
+
+func (h *patchHunk) header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+}
+
+// sides returns the lines the hunk expects to find and the lines it replaces
+// them with.
+func (h *patchHunk) sides() (old, new []string) {
+	for _, line := range h.Lines {
+		switch line[0] {
+		case ' ':
+			old = append(old, line[1:])
+			new = append(new, line[1:])
+		case '-':
+			old = append(old, line[1:])
+		case '+':
+			new = append(new, line[1:])
+		}
+	}
+	return old, new
+}
+
+// parsePatch parses a unified diff, as produced by diff -u or git diff, into
+// per-file changes.
+func parsePatch(data []byte) ([]*filePatch, error) {
+	var patches []*filePatch
+	var cur *filePatch
+	var gitHeader bool
+	var oldName, newName string
+	var haveOld bool
+
+	finish := func() error {
+		if cur == nil {
+			return nil
+		}
+		if gitHeader || (strings.HasPrefix(oldName, "a/") && strings.HasPrefix(newName, "b/")) {
+			oldName = strings.TrimPrefix(oldName, "a/")
+			newName = strings.TrimPrefix(newName, "b/")
+		}
+		if oldName != "/dev/null" {
+			p, err := patchPath(oldName)
+			if err != nil {
+				return err
+			}
+			cur.OldPath = p
+		}
+		if newName != "/dev/null" {
+			p, err := patchPath(newName)
+			if err != nil {
+				return err
+			}
+			cur.NewPath = p
+		}
+		if cur.OldPath == "" && cur.NewPath == "" {
+			return fmt.Errorf("patch is missing file names")
+		}
+		patches = append(patches, cur)
+		cur = nil
+		gitHeader = false
+		haveOld = false
+		oldName, newName = "", ""
+		return nil
+	}
+
+	lines := splitLines(string(data))
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSuffix(lines[i], "\n")
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			if err := finish(); err != nil {
+				return nil, err
+			}
+			cur = &filePatch{}
+			gitHeader = true
+			// fallback names for mode-only and binary changes, which have no
+			// ---/+++ lines
+			if names := strings.SplitN(strings.TrimPrefix(line, "diff --git "), " b/", 2); len(names) == 2 {
+				oldName, newName = names[0], "b/"+names[1]
+			}
+		case strings.HasPrefix(line, "--- "):
+			if cur == nil || len(cur.Hunks) > 0 || haveOld {
+				if err := finish(); err != nil {
+					return nil, err
+				}
+				cur = &filePatch{}
+			}
+			oldName = patchFileName(strings.TrimPrefix(line, "--- "))
+			haveOld = true
+		case strings.HasPrefix(line, "+++ ") && cur != nil && haveOld:
+			newName = patchFileName(strings.TrimPrefix(line, "+++ "))
+		case cur != nil && gitHeader && len(cur.Hunks) == 0 && strings.HasPrefix(line, "new file mode "):
+			mode, err := parseGitMode(strings.TrimPrefix(line, "new file mode "))
+			if err != nil {
+				return nil, err
+			}
+			cur.Mode = mode
+			oldName = "/dev/null"
+		case cur != nil && gitHeader && len(cur.Hunks) == 0 && strings.HasPrefix(line, "deleted file mode "):
+			newName = "/dev/null"
+		case cur != nil && gitHeader && len(cur.Hunks) == 0 && strings.HasPrefix(line, "new mode "):
+			mode, err := parseGitMode(strings.TrimPrefix(line, "new mode "))
+			if err != nil {
+				return nil, err
+			}
+			cur.Mode = mode
+		case cur != nil && gitHeader && len(cur.Hunks) == 0 && strings.HasPrefix(line, "rename from "):
+			oldName = "a/" + strings.TrimPrefix(line, "rename from ")
+		case cur != nil && gitHeader && len(cur.Hunks) == 0 && strings.HasPrefix(line, "rename to "):
+			newName = "b/" + strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "Binary files ") || strings.HasPrefix(line, "GIT binary patch"):
+			if cur == nil {
+				return nil, fmt.Errorf("line %d: binary change outside of a file diff", i+1)
+			}
+			cur.Binary = true
+		case strings.HasPrefix(line, "@@ "):
+			if cur == nil {
+				return nil, fmt.Errorf("line %d: hunk outside of a file diff", i+1)
+			}
+			hunk, next, err := parseHunk(lines, i)
+			if err != nil {
+				return nil, err
+			}
+			cur.Hunks = append(cur.Hunks, hunk)
+			i = next - 1
+		}
+	}
+	if err := finish(); err != nil {
+		return nil, err
+	}
+	return patches, nil
+}
+
+// parseHunk parses the hunk whose header is at lines[start], returning the
+// index of the first line after it.
+func parseHunk(lines []string, start int) (*patchHunk, int, error) {
+	header := strings.TrimSuffix(lines[start], "\n")
+	m := hunkHeaderRegexp.FindStringSubmatch(header)
+	if m == nil {
+		return nil, 0, fmt.Errorf("line %d: malformed hunk header %q", start+1, header)
+	}
+	hunk := &patchHunk{}
+	hunk.OldStart, _ = strconv.Atoi(m[1])
+	hunk.OldLines = 1
+	if m[2] != "" {
+		hunk.OldLines, _ = strconv.Atoi(m[2])
+	}
+	hunk.NewStart, _ = strconv.Atoi(m[3])
+	hunk.NewLines = 1
+	if m[4] != "" {
+		hunk.NewLines, _ = strconv.Atoi(m[4])
+	}
+
+	oldLeft, newLeft := hunk.OldLines, hunk.NewLines
+	i := start + 1
+	for ; i < len(lines) && (oldLeft > 0 || newLeft > 0); i++ {
+		line := lines[i]
+		if line == "\n" {
+			// some editors strip the trailing space of empty context lines
+			line = " \n"
+		}
+		switch line[0] {
+		case ' ':
+			oldLeft--
+			newLeft--
+		case '-':
+			oldLeft--
+		case '+':
+			newLeft--
+		case '\\':
+			hunk.trimLastNewline()
+			continue
+		default:
+			return nil, 0, fmt.Errorf("line %d: unexpected line in hunk %s: %q", i+1, hunk.header(), strings.TrimSuffix(line, "\n"))
+		}
+		if oldLeft < 0 || newLeft < 0 {
+			return nil, 0, fmt.Errorf("line %d: hunk %s has more lines than its header declares", i+1, hunk.header())
+		}
+		hunk.Lines = append(hunk.Lines, line)
+	}
+	if oldLeft > 0 || newLeft > 0 {
+		return nil, 0, fmt.Errorf("line %d: hunk %s is truncated", i+1, hunk.header())
+	}
+	if i < len(lines) && strings.HasPrefix(lines[i], `\`) {
+		hunk.trimLastNewline()
+		i++
+	}
+	return hunk, i, nil
+}
+
+func (h *patchHunk) trimLastNewline() {
+	if len(h.Lines) == 0 {
+		return
+	}
+	h.Lines[len(h.Lines)-1] = strings.TrimSuffix(h.Lines[len(h.Lines)-1], "\n")
+}
+
+// patchFileName extracts the file name from a ---/+++ line, dropping any
+// trailing timestamp.
+func patchFileName(s string) string {
+	if idx := strings.IndexByte(s, '\t'); idx >= 0 {
+		s = s[:idx]
+	}
+	return strings.TrimSpace(s)
+}
+
+func patchPath(name string) (string, error) {
+	clean := path.Clean(strings.TrimPrefix(name, "/"))
+	if clean == "." || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("invalid path in patch: %q", name)
+	}
+	return clean, nil
+}
+
+func parseGitMode(s string) (fs.FileMode, error) {
+	mode, err := strconv.ParseUint(strings.TrimSpace(s), 8, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid file mode %q: %w", s, err)
+	}
+	return fs.FileMode(mode).Perm(), nil
+}
+
+// apply applies the hunks to the original contents of the file. Each hunk
+// must match exactly, though it may be found at a different offset than its
+// header states, like patch(1) with no fuzz.
+func (fp *filePatch) apply(orig []byte) ([]byte, error) {
+	if fp.Binary {
+		return nil, fmt.Errorf("binary patches are not supported")
+	}
+
+	src := splitLines(string(orig))
+	var out []string
+	// pos is the index in src up to which lines have been consumed
+	pos := 0
+	// offset is the drift between hunk headers and where hunks actually matched
+	offset := 0
+	for i, hunk := range fp.Hunks {
+		old, new := hunk.sides()
+
+		want := hunk.OldStart - 1 + offset
+		if hunk.OldLines == 0 {
+			// pure insertions name the line they follow
+			want = hunk.OldStart + offset
+		}
+		at := findHunk(src, old, want, pos)
+		if at < 0 {
+			return nil, fmt.Errorf("hunk #%d %s does not apply", i+1, hunk.header())
+		}
+		offset = at - (want - offset)
+		out = append(out, src[pos:at]...)
+		out = append(out, new...)
+		pos = at + len(old)
+	}
+	out = append(out, src[pos:]...)
+
+	if fp.NewPath == "" && len(out) > 0 {
+		return nil, fmt.Errorf("file to delete does not match the patch")
+	}
+	return []byte(strings.Join(out, "")), nil
+}
+
+// findHunk returns the index at which the lines match src, searching outward
+// from want and never before min, or -1 if they match nowhere.
+func findHunk(src, lines []string, want, min int) int {
+	matches := func(at int) bool {
+		if at < min || at+len(lines) > len(src) {
+			return false
+		}
+		for i, line := range lines {
+			if src[at+i] != line {
+				return false
+			}
+		}
+		return true
+	}
+	for delta := 0; want-delta >= min || want+delta <= len(src); delta++ {
+		if matches(want - delta) {
+			return want - delta
+		}
+		if delta > 0 && matches(want+delta) {
+			return want + delta
+		}
+	}
+	return -1
+}
+
+// writeFilePatch writes a git-style unified diff between two versions of a
+// file to buf. A nil content means the file does not exist on that side.
+func writeFilePatch(buf *bytes.Buffer, name string, oldContent, newContent []byte, oldMode, newMode fs.FileMode) {
+	oldName, newName := "a/"+name, "b/"+name
+	modeChanged := oldContent != nil && newContent != nil && oldMode != newMode
+	if bytes.Equal(oldContent, newContent) && (oldContent == nil) == (newContent == nil) && !modeChanged {
+		return
+	}
+
+	fmt.Fprintf(buf, "diff --git %s %s\n", oldName, newName)
+	switch {
+	case oldContent == nil:
+		fmt.Fprintf(buf, "new file mode 100%o\n", newMode.Perm())
+		oldName = "/dev/null"
+	case newContent == nil:
+		fmt.Fprintf(buf, "deleted file mode 100%o\n", oldMode.Perm())
+		newName = "/dev/null"
+	case modeChanged:
+		fmt.Fprintf(buf, "old mode 100%o\nnew mode 100%o\n", oldMode.Perm(), newMode.Perm())
+	}
+	if bytes.Equal(oldContent, newContent) {
+		return
+	}
+	if isBinary(oldContent) || isBinary(newContent) {
+		fmt.Fprintf(buf, "Binary files %s and %s differ\n", oldName, newName)
+		return
+	}
+
+	a := splitLines(string(oldContent))
+	b := splitLines(string(newContent))
+	fmt.Fprintf(buf, "--- %s\n+++ %s\n", oldName, newName)
+	matcher := difflib.NewMatcherWithJunk(a, b, false, nil)
+	for _, group := range matcher.GetGroupedOpCodes(patchContextLines) {
+		first, last := group[0], group[len(group)-1]
+		fmt.Fprintf(buf, "@@ -%s +%s @@\n",
+			unifiedRange(first.I1, last.I2),
+			unifiedRange(first.J1, last.J2))
+		for _, op := range group {
+			if op.Tag == 'e' {
+				writePatchLines(buf, ' ', a[op.I1:op.I2])
+				continue
+			}
+			if op.Tag == 'r' || op.Tag == 'd' {
+				writePatchLines(buf, '-', a[op.I1:op.I2])
+			}
+			if op.Tag == 'r' || op.Tag == 'i' {
+				writePatchLines(buf, '+', b[op.J1:op.J2])
+			}
+		}
+	}
+}
+
+func writePatchLines(buf *bytes.Buffer, prefix byte, lines []string) {
+	for _, line := range lines {
+		buf.WriteByte(prefix)
+		buf.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			buf.WriteString("\n" + noNewlineMarker + "\n")
+		}
+	}
+}
+
+// unifiedRange formats a line range the way diff -u does.
+func unifiedRange(start, stop int) string {
+	length := stop - start
+	if length == 0 {
+		// empty ranges name the line before them
+		return fmt.Sprintf("%d,0", start)
+	}
+	if length == 1 {
+		return strconv.Itoa(start + 1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, length)
+}
+
+// isBinary uses the same heuristic as git: content is binary if it contains a
+// NUL byte in its first 8000 bytes.
+func isBinary(content []byte) bool {
+	if len(content) > 8000 {
+		content = content[:8000]
+	}
+	return bytes.IndexByte(content, 0) >= 0
+}
+
+// splitLines splits s into lines, keeping their trailing newlines.
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		old, new string
+	}{
+		{
+			name: "change",
+			old:  "one\ntwo\nthree\nfour\nfive\nsix\nseven\neight\nnine\nten\n",
+			new:  "one\ntwo\nTHREE\nfour\nfive\nsix\nseven\neight\nnine\nten\neleven\n",
+		},
+		{
+			name: "missing trailing newline",
+			old:  "one\ntwo",
+			new:  "one\ntwo\nthree",
+		},
+		{
+			name: "add trailing newline",
+			old:  "one\ntwo",
+			new:  "one\ntwo\n",
+		},
+		{
+			name: "empty",
+			old:  "one\ntwo\n",
+			new:  "",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeFilePatch(&buf, "file.txt", []byte(tc.old), []byte(tc.new), 0o644, 0o644)
+
+			patches, err := parsePatch(buf.Bytes())
+			require.NoError(t, err)
+			require.Len(t, patches, 1)
+			require.Equal(t, "file.txt", patches[0].OldPath)
+			require.Equal(t, "file.txt", patches[0].NewPath)
+
+			out, err := patches[0].apply([]byte(tc.old))
+			require.NoError(t, err)
+			require.Equal(t, tc.new, string(out))
+		})
+	}
+}
+
+func TestPatchNewAndDeletedFiles(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	writeFilePatch(&buf, "added.sh", nil, []byte("#!/bin/sh\necho hi\n"), 0, 0o755)
+	writeFilePatch(&buf, "removed.txt", []byte("bye\n"), nil, 0o644, 0)
+	require.Equal(t, `diff --git a/added.sh b/added.sh
+new file mode 100755
+--- /dev/null
++++ b/added.sh
+@@ -0,0 +1,2 @@
++#!/bin/sh
++echo hi
+diff --git a/removed.txt b/removed.txt
+deleted file mode 100644
+--- a/removed.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+`, buf.String())
+
+	patches, err := parsePatch(buf.Bytes())
+	require.NoError(t, err)
+	require.Len(t, patches, 2)
+
+	require.Equal(t, "", patches[0].OldPath)
+	require.Equal(t, "added.sh", patches[0].NewPath)
+	require.Equal(t, 0o755, int(patches[0].Mode))
+	out, err := patches[0].apply(nil)
+	require.NoError(t, err)
+	require.Equal(t, "#!/bin/sh\necho hi\n", string(out))
+
+	require.Equal(t, "removed.txt", patches[1].OldPath)
+	require.Equal(t, "", patches[1].NewPath)
+	out, err = patches[1].apply([]byte("bye\n"))
+	require.NoError(t, err)
+	require.Empty(t, out)
+
+	_, err = patches[1].apply([]byte("bye\nagain\n"))
+	require.ErrorContains(t, err, "does not match")
+}
+
+func TestPatchApplyOffset(t *testing.T) {
+	t.Parallel()
+
+	patch := `--- a/file.txt	2024-01-01 00:00:00
++++ b/file.txt	2024-01-01 00:00:00
+@@ -1,3 +1,3 @@
+ a
+-b
++B
+ c
+`
+	patches, err := parsePatch([]byte(patch))
+	require.NoError(t, err)
+	require.Len(t, patches, 1)
+	require.Equal(t, "file.txt", patches[0].OldPath)
+
+	// lines were added above the hunk since the patch was made
+	out, err := patches[0].apply([]byte("x\ny\na\nb\nc\n"))
+	require.NoError(t, err)
+	require.Equal(t, "x\ny\na\nB\nc\n", string(out))
+
+	_, err = patches[0].apply([]byte("a\nchanged\nc\n"))
+	require.ErrorContains(t, err, "hunk #1 @@ -1,3 +1,3 @@ does not apply")
+}
+
+func TestParsePatchErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := parsePatch([]byte("--- a/../../etc/passwd\n+++ b/../../etc/passwd\n@@ -1 +1 @@\n-a\n+b\n"))
+	require.ErrorContains(t, err, "invalid path")
+
+	_, err = parsePatch([]byte("--- a/file\n+++ b/file\n@@ -1,2 +1,2 @@\n-a\n+b\n"))
+	require.ErrorContains(t, err, "truncated")
+
+	_, err = parsePatch([]byte("@@ -1 +1 @@\n-a\n+b\n"))
+	require.ErrorContains(t, err, "outside of a file diff")
+}
@@ -92,6 +92,10 @@ type Server interface {
 	// The default local cache policy to use for automatic local cache GC.
 	EngineLocalCachePolicy() bkclient.PruneInfo
 
+	// The calls recorded in the telemetry of the engine's recent clients,
+	// including clients that have disconnected.
+	RecordedCalls(context.Context) ([]*RecordedCall, error)
+
 	// The nearest ancestor client that is not a module (either a caller from the host like the CLI
 	// or a nested exec). Useful for figuring out where local sources should be resolved from through
 	// chains of dependency modules.
@@ -0,0 +1,174 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/containerd/containerd/content"
+	"github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Registry is an image in a registry, at a tag or digest, which is inspected
+// without pulling its layers.
+type Registry struct {
+	Query *Query
+
+	// Address is the normalized reference of the image, e.g.
+	// "docker.io/library/alpine:latest".
+	Address string `field:"true" doc:"The normalized reference of the image (e.g., \"docker.io/library/alpine:latest\")."`
+}
+
+func (*Registry) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "Registry",
+		NonNull:   true,
+	}
+}
+
+func (*Registry) TypeDescription() string {
+	return "An image in a registry, inspected without pulling its layers."
+}
+
+// NewRegistry returns the registry image at the address, which defaults to
+// the "latest" tag, like Container.from.
+func NewRegistry(query *Query, address string) (*Registry, error) {
+	named, err := reference.ParseNormalizedNamed(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid image address %q: %w", address, err)
+	}
+	return &Registry{
+		Query:   query,
+		Address: reference.TagNameOnly(named).String(),
+	}, nil
+}
+
+// Tags lists the tags of the repository of the image.
+func (r *Registry) Tags(ctx context.Context) ([]string, error) {
+	bk, err := r.Query.Buildkit(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get buildkit client: %w", err)
+	}
+	return bk.RegistryTags(ctx, r.Address)
+}
+
+// Resolve returns the descriptor of the manifest or index of the image.
+func (r *Registry) Resolve(ctx context.Context) (specs.Descriptor, error) {
+	bk, err := r.Query.Buildkit(ctx)
+	if err != nil {
+		return specs.Descriptor{}, fmt.Errorf("failed to get buildkit client: %w", err)
+	}
+	return bk.RegistryResolve(ctx, r.Address)
+}
+
+func (r *Registry) provider(ctx context.Context) (content.Provider, specs.Descriptor, error) {
+	bk, err := r.Query.Buildkit(ctx)
+	if err != nil {
+		return nil, specs.Descriptor{}, fmt.Errorf("failed to get buildkit client: %w", err)
+	}
+	desc, err := bk.RegistryResolve(ctx, r.Address)
+	if err != nil {
+		return nil, specs.Descriptor{}, err
+	}
+	provider, err := bk.RegistryProvider(ctx, r.Address)
+	if err != nil {
+		return nil, specs.Descriptor{}, err
+	}
+	return provider, desc, nil
+}
+
+// Platforms returns the platforms of the image, in the order of its index.
+func (r *Registry) Platforms(ctx context.Context) ([]Platform, error) {
+	provider, desc, err := r.provider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	manifests, err := imageVariants(ctx, provider, desc, map[digest.Digest]bool{})
+	if err != nil {
+		return nil, err
+	}
+	platforms := make([]Platform, 0, len(manifests))
+	for _, m := range manifests {
+		platforms = append(platforms, Platform(*m.Platform))
+	}
+	return platforms, nil
+}
+
+// Manifest returns the descriptor of the manifest of the image for the
+// platform. An image that isn't an index is returned as is.
+func (r *Registry) Manifest(ctx context.Context, platform Platform) (specs.Descriptor, error) {
+	provider, desc, err := r.provider(ctx)
+	if err != nil {
+		return specs.Descriptor{}, err
+	}
+	return r.manifest(ctx, provider, desc, platform)
+}
+
+func (r *Registry) manifest(ctx context.Context, provider content.Provider, desc specs.Descriptor, platform Platform) (specs.Descriptor, error) {
+	switch {
+	case isImageManifest(desc):
+		return desc, nil
+	case isImageIndex(desc):
+		desc, err := resolveIndex(ctx, provider, desc, platform.Spec())
+		if err != nil {
+			return specs.Descriptor{}, fmt.Errorf("%s: %w", r.Address, err)
+		}
+		return desc, nil
+	default:
+		return specs.Descriptor{}, fmt.Errorf("%s: expected manifest or index, got %s", r.Address, desc.MediaType)
+	}
+}
+
+// Config returns the config of the image for the platform.
+func (r *Registry) Config(ctx context.Context, platform Platform) (*ImageConfig, error) {
+	provider, desc, err := r.provider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	desc, err = r.manifest(ctx, provider, desc, platform)
+	if err != nil {
+		return nil, err
+	}
+
+	manifestBlob, err := content.ReadBlob(ctx, provider, desc)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest blob: %w", err)
+	}
+	var man specs.Manifest
+	if err := json.Unmarshal(manifestBlob, &man); err != nil {
+		return nil, fmt.Errorf("unmarshal manifest: %w", err)
+	}
+	configBlob, err := content.ReadBlob(ctx, provider, man.Config)
+	if err != nil {
+		return nil, fmt.Errorf("read image config blob %s: %w", man.Config.Digest, err)
+	}
+	var img specs.Image
+	if err := json.Unmarshal(configBlob, &img); err != nil {
+		return nil, fmt.Errorf("unmarshal image config: %w", err)
+	}
+
+	return &ImageConfig{
+		Platform: Platform(img.Platform),
+		Config:   img.Config,
+	}, nil
+}
+
+// ImageConfig is the config of an image in a registry.
+type ImageConfig struct {
+	Platform Platform
+	Config   specs.ImageConfig
+}
+
+func (*ImageConfig) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "ImageConfig",
+		NonNull:   true,
+	}
+}
+
+func (*ImageConfig) TypeDescription() string {
+	return "The config of an image in a registry."
+}
@@ -0,0 +1,45 @@
+package schema
+
+import (
+	"context"
+
+	"github.com/opencontainers/go-digest"
+
+	"github.com/dagger/dagger/core"
+	"github.com/dagger/dagger/dagql"
+)
+
+type cacheMissSchema struct {
+	srv *dagql.Server
+}
+
+var _ SchemaResolvers = &cacheMissSchema{}
+
+func (s *cacheMissSchema) Install() {
+	dagql.Fields[*core.Query]{
+		dagql.Func("explainCacheMiss", s.explainCacheMiss).
+			Impure("Reads the telemetry of recent runs, which changes as they complete.").
+			Doc(`Explains why a call missed the cache, by comparing it to the same call in a previous run.`,
+				`Calls are looked up in the telemetry the engine keeps of its recent clients.
+				The first difference is reported, in the order the calls are evaluated:
+				the objects that calls are chained from first, then arguments, then content.`).
+			ArgDoc("digest", `Digest of the call that missed the cache, as recorded in its span (e.g., "xxh3:...").`).
+			ArgDoc("previousDigest",
+				`Digest of the call to compare to.`,
+				`Defaults to the most recent call of the same fields in an earlier run.`),
+	}.Install(s.srv)
+
+	dagql.Fields[*core.CacheMissExplanation]{}.Install(s.srv)
+}
+
+type explainCacheMissArgs struct {
+	Digest         string
+	PreviousDigest string `default:""`
+}
+
+func (s *cacheMissSchema) explainCacheMiss(ctx context.Context, parent *core.Query, args explainCacheMissArgs) (*core.CacheMissExplanation, error) {
+	if err := parent.RequireMainClient(ctx); err != nil {
+		return nil, err
+	}
+	return core.ExplainCacheMiss(ctx, parent, digest.Digest(args.Digest), digest.Digest(args.PreviousDigest))
+}
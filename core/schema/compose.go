@@ -0,0 +1,401 @@
+package schema
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/dagger/dagger/core"
+	"github.com/dagger/dagger/dagql"
+)
+
+type composeSchema struct {
+	srv *dagql.Server
+}
+
+var _ SchemaResolvers = &composeSchema{}
+
+func (s *composeSchema) Install() {
+	dagql.Fields[*core.Directory]{
+		dagql.NodeFunc("asCompose", s.directoryAsCompose).
+			Doc(`Load a Compose project from a Compose file in this directory.`,
+				`Paths in the Compose file, such as build contexts and bind mounts,
+				are resolved relative to the Compose file's directory. Variables are
+				interpolated from a .env file next to the Compose file, if any.`).
+			ArgDoc("path",
+				`Path of the Compose file. If unset, the first of "compose.yaml",
+				"compose.yml", "docker-compose.yaml" and "docker-compose.yml" that
+				exists is used.`).
+			ArgDoc("projectName",
+				`Name of the project, which named volumes are scoped to. If unset,
+				the name set in the Compose file is used, or "default".`),
+	}.Install(s.srv)
+
+	dagql.Fields[*core.File]{
+		dagql.Func("asCompose", s.fileAsCompose).
+			Doc(`Load a Compose project from this Compose file.`,
+				`Services that are built or that bind mount paths can only be
+				loaded from a directory with Directory.asCompose.`).
+			ArgDoc("projectName",
+				`Name of the project, which named volumes are scoped to. If unset,
+				the name set in the Compose file is used, or "default".`),
+	}.Install(s.srv)
+
+	dagql.Fields[*core.ComposeProject]{
+		dagql.Func("name", s.name).
+			Doc(`The name of the project.`),
+
+		dagql.Func("serviceNames", s.serviceNames).
+			Doc(`The names of the services in the project.`),
+
+		dagql.Func("service", s.service).
+			Doc(`Retrieves a service of the project.`,
+				`The services it depends on are bound to it, reachable at their names
+				in the project. Its named volumes are cache volumes, shared with the
+				project's other services.`).
+			ArgDoc("name", `The name of the service.`),
+	}.Install(s.srv)
+}
+
+type directoryAsComposeArgs struct {
+	Path        string `default:""`
+	ProjectName string `default:""`
+}
+
+func (s *composeSchema) directoryAsCompose(ctx context.Context, parent dagql.Instance[*core.Directory], args directoryAsComposeArgs) (*core.ComposeProject, error) {
+	composePath := args.Path
+	if composePath == "" {
+		entries, err := parent.Self.Entries(ctx, ".")
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range core.ComposeDefaultFiles {
+			if slices.Contains(entries, name) {
+				composePath = name
+				break
+			}
+		}
+		if composePath == "" {
+			return nil, fmt.Errorf("no compose file found; looked for %s", strings.Join(core.ComposeDefaultFiles, ", "))
+		}
+	}
+	dir := path.Dir(composePath)
+
+	data, err := s.readFile(ctx, parent.Self, composePath)
+	if err != nil {
+		return nil, err
+	}
+
+	env := map[string]string{}
+	entries, err := parent.Self.Entries(ctx, dir)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(entries, ".env") {
+		dotEnv, err := s.readFile(ctx, parent.Self, path.Join(dir, ".env"))
+		if err != nil {
+			return nil, err
+		}
+		env = core.ParseDotEnv(dotEnv)
+	}
+
+	proj, err := core.LoadCompose(data, env, args.ProjectName)
+	if err != nil {
+		return nil, fmt.Errorf("load %s: %w", composePath, err)
+	}
+	proj.Query = parent.Self.Query
+	proj.Context = &parent
+	proj.Dir = dir
+	return proj, nil
+}
+
+func (s *composeSchema) readFile(ctx context.Context, dir *core.Directory, filePath string) ([]byte, error) {
+	file, err := dir.File(ctx, filePath)
+	if err != nil {
+		return nil, err
+	}
+	return file.Contents(ctx)
+}
+
+type fileAsComposeArgs struct {
+	ProjectName string `default:""`
+}
+
+func (s *composeSchema) fileAsCompose(ctx context.Context, parent *core.File, args fileAsComposeArgs) (*core.ComposeProject, error) {
+	data, err := parent.Contents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	proj, err := core.LoadCompose(data, nil, args.ProjectName)
+	if err != nil {
+		return nil, err
+	}
+	proj.Query = parent.Query
+	return proj, nil
+}
+
+func (s *composeSchema) name(ctx context.Context, parent *core.ComposeProject, args struct{}) (dagql.String, error) {
+	return dagql.NewString(parent.Name), nil
+}
+
+func (s *composeSchema) serviceNames(ctx context.Context, parent *core.ComposeProject, args struct{}) (dagql.Array[dagql.String], error) {
+	return dagql.NewStringArray(parent.ServiceNames()...), nil
+}
+
+func (s *composeSchema) service(ctx context.Context, parent *core.ComposeProject, args struct {
+	Name string
+}) (dagql.Instance[*core.Service], error) {
+	return s.loadService(ctx, parent, args.Name, nil)
+}
+
+// loadService builds the container of a Compose service and turns it into a
+// Service, loading the services it depends on first to bind them to it.
+// dependents lists the services that led to loading this one, to detect
+// cycles.
+//
+//nolint:gocyclo
+func (s *composeSchema) loadService(ctx context.Context, proj *core.ComposeProject, name string, dependents []string) (inst dagql.Instance[*core.Service], _ error) {
+	if slices.Contains(dependents, name) {
+		return inst, fmt.Errorf("dependency cycle: %s", strings.Join(append(dependents, name), " -> "))
+	}
+	svc, ok := proj.Services[name]
+	if !ok {
+		return inst, fmt.Errorf("no service %q in project %q; services: %s", name, proj.Name, strings.Join(proj.ServiceNames(), ", "))
+	}
+	dependents = append(dependents, name)
+
+	var ctr dagql.Instance[*core.Container]
+	if svc.Build != nil {
+		if proj.Context == nil {
+			return inst, fmt.Errorf("service %q: build requires loading the project from a directory", name)
+		}
+		buildArgs := make([]dagql.InputObject[core.BuildArg], len(svc.Build.Args))
+		for i, arg := range svc.Build.Args {
+			buildArgs[i] = dagql.InputObject[core.BuildArg]{Value: arg}
+		}
+		build := dagql.Selector{
+			Field: "dockerBuild",
+			Args: []dagql.NamedInput{
+				{Name: "buildArgs", Value: dagql.ArrayInput[dagql.InputObject[core.BuildArg]](buildArgs)},
+			},
+		}
+		if svc.Build.Dockerfile != "" {
+			build.Args = append(build.Args, dagql.NamedInput{Name: "dockerfile", Value: dagql.NewString(svc.Build.Dockerfile)})
+		}
+		if svc.Build.Target != "" {
+			build.Args = append(build.Args, dagql.NamedInput{Name: "target", Value: dagql.NewString(svc.Build.Target)})
+		}
+		err := s.srv.Select(ctx, *proj.Context, &ctr,
+			dagql.Selector{
+				Field: "directory",
+				Args: []dagql.NamedInput{
+					{Name: "path", Value: dagql.NewString(path.Join(proj.Dir, svc.Build.Context))},
+				},
+			},
+			build,
+		)
+		if err != nil {
+			return inst, fmt.Errorf("service %q: build: %w", name, err)
+		}
+	} else {
+		err := s.srv.Select(ctx, s.srv.Root(), &ctr,
+			dagql.Selector{Field: "container"},
+			dagql.Selector{
+				Field: "from",
+				Args: []dagql.NamedInput{
+					{Name: "address", Value: dagql.NewString(svc.Image)},
+				},
+			},
+		)
+		if err != nil {
+			return inst, fmt.Errorf("service %q: %w", name, err)
+		}
+	}
+
+	var sels []dagql.Selector
+	for _, env := range svc.Environment {
+		sels = append(sels, dagql.Selector{
+			Field: "withEnvVariable",
+			Args: []dagql.NamedInput{
+				{Name: "name", Value: dagql.NewString(env.Name)},
+				{Name: "value", Value: dagql.NewString(env.Value)},
+			},
+		})
+	}
+	if svc.WorkingDir != "" {
+		sels = append(sels, dagql.Selector{
+			Field: "withWorkdir",
+			Args: []dagql.NamedInput{
+				{Name: "path", Value: dagql.NewString(svc.WorkingDir)},
+			},
+		})
+	}
+	if svc.User != "" {
+		sels = append(sels, dagql.Selector{
+			Field: "withUser",
+			Args: []dagql.NamedInput{
+				{Name: "name", Value: dagql.NewString(svc.User)},
+			},
+		})
+	}
+
+	for _, vol := range svc.Volumes {
+		sel, err := s.volumeSelector(ctx, proj, vol)
+		if err != nil {
+			return inst, fmt.Errorf("service %q: volume %s: %w", name, vol.Target, err)
+		}
+		sels = append(sels, sel)
+	}
+
+	for _, port := range svc.Ports {
+		sels = append(sels, dagql.Selector{
+			Field: "withExposedPort",
+			Args: []dagql.NamedInput{
+				{Name: "port", Value: dagql.NewInt(port.Port)},
+				{Name: "protocol", Value: port.Protocol},
+			},
+		})
+	}
+
+	for _, dep := range svc.DependsOn {
+		depSvc, err := s.loadService(ctx, proj, dep, dependents)
+		if err != nil {
+			return inst, err
+		}
+		sels = append(sels, dagql.Selector{
+			Field: "withServiceBinding",
+			Args: []dagql.NamedInput{
+				{Name: "alias", Value: dagql.NewString(dep)},
+				{Name: "service", Value: dagql.NewID[*core.Service](depSvc.ID())},
+			},
+		})
+	}
+
+	if svc.Entrypoint != nil {
+		sels = append(sels, dagql.Selector{
+			Field: "withEntrypoint",
+			Args: []dagql.NamedInput{
+				{Name: "args", Value: dagql.ArrayInput[dagql.String](dagql.NewStringArray(svc.Entrypoint...))},
+			},
+		})
+	}
+	if svc.Command != nil {
+		sels = append(sels, dagql.Selector{
+			Field: "withDefaultArgs",
+			Args: []dagql.NamedInput{
+				{Name: "args", Value: dagql.ArrayInput[dagql.String](dagql.NewStringArray(svc.Command...))},
+			},
+		})
+	}
+
+	asService := dagql.Selector{
+		Field: "asService",
+		Args: []dagql.NamedInput{
+			{Name: "useEntrypoint", Value: dagql.NewBoolean(true)},
+		},
+	}
+	if hc := svc.Healthcheck; hc != nil {
+		asService.Args = append(asService.Args, dagql.NamedInput{
+			Name:  "healthcheckExec",
+			Value: dagql.ArrayInput[dagql.String](dagql.NewStringArray(hc.HealthcheckExec...)),
+		})
+		if hc.HealthcheckInterval != 0 {
+			asService.Args = append(asService.Args, dagql.NamedInput{Name: "healthcheckInterval", Value: dagql.NewInt(hc.HealthcheckInterval)})
+		}
+		if hc.HealthcheckTimeout != 0 {
+			asService.Args = append(asService.Args, dagql.NamedInput{Name: "healthcheckTimeout", Value: dagql.NewInt(hc.HealthcheckTimeout)})
+		}
+		if hc.HealthcheckRetries != 0 {
+			asService.Args = append(asService.Args, dagql.NamedInput{Name: "healthcheckRetries", Value: dagql.NewInt(hc.HealthcheckRetries)})
+		}
+	}
+	sels = append(sels, asService)
+
+	if svc.RestartPolicy != "" {
+		sels = append(sels, dagql.Selector{
+			Field: "withRestartPolicy",
+			Args: []dagql.NamedInput{
+				{Name: "policy", Value: svc.RestartPolicy},
+				{Name: "maxRetries", Value: dagql.NewInt(svc.RestartMaxRetries)},
+			},
+		})
+	}
+
+	if err := s.srv.Select(ctx, ctr, &inst, sels...); err != nil {
+		return inst, fmt.Errorf("service %q: %w", name, err)
+	}
+	return inst, nil
+}
+
+// volumeSelector returns the selector that mounts a Compose volume.
+func (s *composeSchema) volumeSelector(ctx context.Context, proj *core.ComposeProject, vol core.ComposeVolume) (dagql.Selector, error) {
+	target := dagql.NamedInput{Name: "path", Value: dagql.NewString(vol.Target)}
+
+	switch {
+	case vol.Type == core.ComposeVolumeTmpfs,
+		vol.Type == core.ComposeVolumeNamed && vol.Source == "":
+		// anonymous volumes don't outlive the container, so a tmpfs will do
+		return dagql.Selector{
+			Field: "withMountedTemp",
+			Args:  []dagql.NamedInput{target},
+		}, nil
+
+	case vol.Type == core.ComposeVolumeNamed:
+		var cache dagql.Instance[*core.CacheVolume]
+		err := s.srv.Select(ctx, s.srv.Root(), &cache, dagql.Selector{
+			Field: "cacheVolume",
+			Args: []dagql.NamedInput{
+				{Name: "key", Value: dagql.NewString(proj.VolumeKey(vol.Source))},
+			},
+		})
+		if err != nil {
+			return dagql.Selector{}, err
+		}
+		return dagql.Selector{
+			Field: "withMountedCache",
+			Args: []dagql.NamedInput{
+				target,
+				{Name: "cache", Value: dagql.NewID[*core.CacheVolume](cache.ID())},
+			},
+		}, nil
+
+	default: // bind
+		if proj.Context == nil {
+			return dagql.Selector{}, fmt.Errorf("bind mounts require loading the project from a directory")
+		}
+		source := dagql.NamedInput{Name: "path", Value: dagql.NewString(path.Join(proj.Dir, vol.Source))}
+
+		var dir dagql.Instance[*core.Directory]
+		dirErr := s.srv.Select(ctx, *proj.Context, &dir, dagql.Selector{
+			Field: "directory",
+			Args:  []dagql.NamedInput{source},
+		})
+		if dirErr == nil {
+			return dagql.Selector{
+				Field: "withMountedDirectory",
+				Args: []dagql.NamedInput{
+					target,
+					{Name: "source", Value: dagql.NewID[*core.Directory](dir.ID())},
+				},
+			}, nil
+		}
+
+		// not a directory; try a file
+		var file dagql.Instance[*core.File]
+		if err := s.srv.Select(ctx, *proj.Context, &file, dagql.Selector{
+			Field: "file",
+			Args:  []dagql.NamedInput{source},
+		}); err != nil {
+			return dagql.Selector{}, dirErr
+		}
+		return dagql.Selector{
+			Field: "withMountedFile",
+			Args: []dagql.NamedInput{
+				target,
+				{Name: "source", Value: dagql.NewID[*core.File](file.ID())},
+			},
+		}, nil
+	}
+}
@@ -19,11 +19,13 @@ import (
 	"github.com/moby/buildkit/exporter/containerimage/exptypes"
 	"github.com/moby/buildkit/frontend/dockerfile/shell"
 	"github.com/moby/buildkit/identity"
+	"github.com/opencontainers/go-digest"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/vektah/gqlparser/v2/ast"
 
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
+	"github.com/dagger/dagger/dagql/call"
 	"github.com/dagger/dagger/engine/buildkit"
 	"github.com/dagger/dagger/engine/slog"
 )
@@ -412,7 +414,21 @@ func (s *containerSchema) Install() {
 				`If set, skip the automatic init process injected into containers by default.`,
 				`This should only be used if the user requires that their exec process be the
 				pid 1 process in the container. Otherwise it may result in unexpected behavior.`,
-			),
+			).
+			ArgDoc("timeout",
+				`Number of seconds the command may run for before it's killed.`,
+				`A command that times out fails with an ExecError whose reason is TIMEOUT.`).
+			ArgDoc("cpuLimit",
+				`Number of CPUs the command may use (e.g., 0.5).`).
+			ArgDoc("memoryLimit",
+				`Memory in bytes the command may use (e.g., 536870912 for 512 MiB).`,
+				`A command that exceeds it is killed and fails with an ExecError whose reason is OOM_KILLED.`).
+			ArgDoc("pidsLimit",
+				`Number of processes the command may have running at once.`).
+			ArgDoc("networkMode",
+				`Network access granted to the command.`,
+				`With SERVICES_ONLY, connections to anything but bound services and the
+				engine's DNS server fail immediately.`),
 
 		dagql.Func("withExec", s.withExec).
 			View(BeforeVersion("v0.13.0")).
@@ -508,7 +524,7 @@ func (s *containerSchema) Install() {
 			Doc(`Retrieves this container minus the given OCI annotation.`).
 			ArgDoc("name", `The name of the annotation.`),
 
-		dagql.Func("publish", s.publish).
+		dagql.NodeFunc("publish", s.publish).
 			Impure("Writes to the specified Docker registry.").
 			Doc(`Publishes this container as a new image to the specified address.`,
 				`Publish returns a fully qualified ref.`,
@@ -531,7 +547,18 @@ func (s *containerSchema) Install() {
 				`Use the specified media types for the published image's layers.`,
 				`Defaults to OCI, which is largely compatible with most recent
 				registries, but Docker may be needed for older registries without OCI
-				support.`),
+				support.`).
+			ArgDoc("squash",
+				`Squash the layers of the image into one, or the layers above the image the container was created from with "from" or "import".`,
+				`Squashing drops the files that were deleted by later operations.`).
+			ArgDoc("history",
+				`How to describe the layers of the image in its history.`).
+			ArgDoc("signingKey",
+				`A private key to sign the image with, in PEM format (e.g., generated by "cosign generate-key-pair").`,
+				`The signature is compatible with "cosign verify".`).
+			ArgDoc("signingKeyPassword", `The password of the signing key, if it is encrypted.`).
+			ArgDoc("sbom", `Attach an SPDX SBOM of the image's filesystem as an attestation.`).
+			ArgDoc("provenance", `Attach a SLSA provenance, recording the container's call ID, as an attestation.`),
 
 		dagql.Func("platform", s.platform).
 			Doc(`The platform this container executes and publishes as.`),
@@ -561,7 +588,12 @@ func (s *containerSchema) Install() {
 				OCI support.`).
 			ArgDoc("expand",
 				`Replace "${VAR}" or "$VAR" in the value of path according to the current `+
-					`environment variables defined in the container (e.g. "/$VAR/foo").`),
+					`environment variables defined in the container (e.g. "/$VAR/foo").`).
+			ArgDoc("squash",
+				`Squash the layers of the image into one, or the layers above the image the container was created from with "from" or "import".`,
+				`Squashing drops the files that were deleted by later operations.`).
+			ArgDoc("history",
+				`How to describe the layers of the image in its history.`),
 		dagql.Func("export", s.exportLegacy).
 			View(BeforeVersion("v0.12.0")).
 			Extend(),
@@ -581,12 +613,45 @@ func (s *containerSchema) Install() {
 			ArgDoc("mediaTypes", `Use the specified media types for the image's layers.`,
 				`Defaults to OCI, which is largely compatible with most recent
 				container runtimes, but Docker may be needed for older runtimes without
-				OCI support.`),
+				OCI support.`).
+			ArgDoc("squash",
+				`Squash the layers of the image into one, or the layers above the image the container was created from with "from" or "import".`,
+				`Squashing drops the files that were deleted by later operations.`).
+			ArgDoc("history",
+				`How to describe the layers of the image in its history.`).
+			ArgDoc("signingKey",
+				`A private key to sign the image with, in PEM format (e.g., generated by "cosign generate-key-pair").`,
+				`The signature is compatible with "cosign verify".`).
+			ArgDoc("signingKeyPassword", `The password of the signing key, if it is encrypted.`).
+			ArgDoc("sbom", `Attach an SPDX SBOM of the image's filesystem as an attestation.`).
+			ArgDoc("provenance", `Attach a SLSA provenance, recording the container's call ID, as an attestation.`),
 
 		dagql.Func("import", s.import_).
-			Doc(`Reads the container from an OCI tarball.`).
+			Doc(`Reads the container from an OCI tarball.`,
+				`Docker archives, like the ones produced by "docker save", are also supported.
+				The image is picked for the container's platform, unless a single image
+				manifest is selected.`).
 			ArgDoc("source", `File to read the container from.`).
-			ArgDoc("tag", `Identifies the tag to import from the archive, if the archive bundles multiple tags.`),
+			ArgDoc("tag", `Identifies the tag to import from the archive, if the archive bundles multiple tags.`,
+				`Either the tag only (e.g., "latest") or the image reference (e.g., "alpine:latest").`).
+			ArgDoc("digest", `Identifies the digest of the image manifest or index to import from the archive.`),
+
+		dagql.Func("importDirectory", s.importDirectory).
+			Doc(`Reads the container from a directory in OCI image layout.`,
+				`The image is picked for the container's platform, unless a single image manifest is selected.`).
+			ArgDoc("source", `Directory in OCI image layout to read the container from.`).
+			ArgDoc("tag", `Identifies the tag to import from the layout, if the layout bundles multiple tags.`,
+				`Either the tag only (e.g., "latest") or the image reference (e.g., "alpine:latest").`).
+			ArgDoc("digest", `Identifies the digest of the image manifest or index to import from the layout.`),
+
+		dagql.Func("importVariants", s.importVariants).
+			Doc(`Reads every platform variant of an image from an OCI tarball or a directory in OCI image layout.`,
+				`The variants can be exported back as a multi-platform image with platformVariants.`).
+			ArgDoc("source", `File to read the variants from. Exactly one of source or directory must be set.`).
+			ArgDoc("directory", `Directory in OCI image layout to read the variants from.`).
+			ArgDoc("tag", `Identifies the tag to import from the archive, if the archive bundles multiple tags.`,
+				`Either the tag only (e.g., "latest") or the image reference (e.g., "alpine:latest").`).
+			ArgDoc("digest", `Identifies the digest of the image index to import from the archive.`),
 
 		dagql.Func("withRegistryAuth", s.withRegistryAuth).
 			Doc(`Retrieves this container with a registry authentication for a given address.`).
@@ -612,7 +677,15 @@ func (s *containerSchema) Install() {
 			ArgDoc("port", `Port number to expose`).
 			ArgDoc("protocol", `Transport layer network protocol`).
 			ArgDoc("description", `Optional port description`).
-			ArgDoc("experimentalSkipHealthcheck", `Skip the health check when run as a service.`),
+			ArgDoc("experimentalSkipHealthcheck", `Skip the health check when run as a service.`).
+			ArgDoc("healthcheckPath",
+				`Check the port's health with an HTTP GET request for this path, rather than just connecting to it (e.g., "/healthz").`).
+			ArgDoc("healthcheckScheme",
+				`Scheme of the health check request. If unset, HTTP is used.`,
+				`Requires healthcheckPath.`).
+			ArgDoc("healthcheckStatus",
+				`Status code the health check request must respond with.`,
+				`If unset, any 2xx or 3xx status is accepted. Requires healthcheckPath.`),
 
 		dagql.Func("withoutExposedPort", s.withoutExposedPort).
 			Doc(`Unexpose a previously exposed port.`).
@@ -815,6 +888,7 @@ func (s *containerSchema) build(ctx context.Context, parent *core.Container, arg
 		args.Target,
 		secrets,
 		secretStore,
+		core.DockerBuildOpts{},
 	)
 }
 
@@ -1311,19 +1385,78 @@ type containerPublishArgs struct {
 	PlatformVariants  []core.ContainerID `default:"[]"`
 	ForcedCompression dagql.Optional[core.ImageLayerCompression]
 	MediaTypes        core.ImageMediaTypes `default:"OCIMediaTypes"`
+	imageLayerArgs
+	imageExportArgs
 }
 
-func (s *containerSchema) publish(ctx context.Context, parent *core.Container, args containerPublishArgs) (dagql.String, error) {
+// imageLayerArgs are the layer args of publish, export and asTarball.
+type imageLayerArgs struct {
+	Squash  core.ImageLayerSquash `default:"NONE"`
+	History core.ImageHistory     `default:"OPERATIONS"`
+}
+
+// imageExportArgs are the signing and attestation args of publish and
+// asTarball.
+type imageExportArgs struct {
+	SigningKey         dagql.Optional[core.SecretID]
+	SigningKeyPassword dagql.Optional[core.SecretID]
+	SBOM               bool `name:"sbom" default:"false"`
+	Provenance         bool `default:"false"`
+}
+
+func (s *containerSchema) imageExportOpts(
+	ctx context.Context,
+	parent dagql.Instance[*core.Container],
+	platformVariants []core.ContainerID,
+	layerArgs imageLayerArgs,
+	args imageExportArgs,
+) (core.ImageExportOpts, error) {
+	opts := core.ImageExportOpts{
+		Squash:     layerArgs.Squash,
+		History:    layerArgs.History,
+		SBOM:       args.SBOM,
+		Provenance: args.Provenance,
+		IDs:        []*call.ID{parent.ID()},
+	}
+	for _, variant := range platformVariants {
+		opts.IDs = append(opts.IDs, variant.ID())
+	}
+	if args.SigningKey.Valid {
+		key, err := args.SigningKey.Value.Load(ctx, s.srv)
+		if err != nil {
+			return opts, err
+		}
+		opts.SigningKey = key.Self
+	}
+	if args.SigningKeyPassword.Valid {
+		if !args.SigningKey.Valid {
+			return opts, errors.New("signingKeyPassword requires a signingKey")
+		}
+		password, err := args.SigningKeyPassword.Value.Load(ctx, s.srv)
+		if err != nil {
+			return opts, err
+		}
+		opts.SigningKeyPassword = password.Self
+	}
+	return opts, nil
+}
+
+func (s *containerSchema) publish(ctx context.Context, parent dagql.Instance[*core.Container], args containerPublishArgs) (dagql.String, error) {
 	variants, err := dagql.LoadIDs(ctx, s.srv, args.PlatformVariants)
 	if err != nil {
 		return "", err
 	}
-	ref, err := parent.Publish(
+	exportOpts, err := s.imageExportOpts(ctx, parent, args.PlatformVariants, args.imageLayerArgs, args.imageExportArgs)
+	if err != nil {
+		return "", err
+	}
+	ref, err := parent.Self.Publish(
 		ctx,
 		args.Address.String(),
 		variants,
 		args.ForcedCompression.Value,
 		args.MediaTypes,
+		exportOpts,
 	)
 	if err != nil {
 		return "", err
@@ -1754,6 +1887,7 @@ type containerExportArgs struct {
 	ForcedCompression dagql.Optional[core.ImageLayerCompression]
 	MediaTypes        core.ImageMediaTypes `default:"OCIMediaTypes"`
 	Expand            bool                 `default:"false"`
+	imageLayerArgs
 }
 
 func (s *containerSchema) export(ctx context.Context, parent *core.Container, args containerExportArgs) (dagql.String, error) {
@@ -1773,6 +1907,10 @@ func (s *containerSchema) export(ctx context.Context, parent *core.Container, ar
 		variants,
 		args.ForcedCompression.Value,
 		args.MediaTypes,
+		core.ImageExportOpts{
+			Squash:  args.Squash,
+			History: args.History,
+		},
 	)
 	if err != nil {
 		return "", err
@@ -1800,6 +1938,8 @@ type containerAsTarballArgs struct {
 	PlatformVariants  []core.ContainerID `default:"[]"`
 	ForcedCompression dagql.Optional[core.ImageLayerCompression]
 	MediaTypes        core.ImageMediaTypes `default:"OCIMediaTypes"`
+	imageLayerArgs
+	imageExportArgs
 }
 
 func (s *containerSchema) asTarball(
@@ -1811,6 +1951,14 @@ func (s *containerSchema) asTarball(
 	if err != nil {
 		return inst, err
 	}
+	exportOpts, err := s.imageExportOpts(ctx, parent, args.PlatformVariants, args.imageLayerArgs, args.imageExportArgs)
+	if err != nil {
+		return inst, err
+	}
+	signer, err := exportOpts.Signer(ctx, parent.Self.Query)
+	if err != nil {
+		return inst, err
+	}
 
 	bk, err := parent.Self.Query.Buildkit(ctx)
 	if err != nil {
@@ -1839,29 +1987,25 @@ func (s *containerSchema) asTarball(
 	services := core.ServiceBindings{}
 
 	variants := append([]*core.Container{parent.Self}, platformVariants...)
-	for _, variant := range variants {
+	for i, variant := range variants {
 		if variant.FS == nil {
 			continue
 		}
-		st, err := variant.FSState()
-		if err != nil {
-			return inst, err
-		}
-
 		platformSpec := variant.Platform.Spec()
-		def, err := st.Marshal(ctx, llb.Platform(platformSpec))
-		if err != nil {
-			return inst, err
-		}
-
 		platformString := platforms.Format(variant.Platform.Spec())
 		if _, ok := inputByPlatform[platformString]; ok {
 			return inst, fmt.Errorf("duplicate platform %q", platformString)
 		}
-		inputByPlatform[platformString] = buildkit.ContainerExport{
-			Definition: def.ToPB(),
-			Config:     variant.Config,
+		export := buildkit.ContainerExport{
+			Config: variant.Config,
+		}
+		if err := exportOpts.SetLayers(ctx, &export, variant); err != nil {
+			return inst, err
+		}
+		if err := exportOpts.AddAttestations(&export, i, variant.Platform); err != nil {
+			return inst, err
 		}
+		inputByPlatform[platformString] = export
 
 		if len(variants) == 1 {
 			// single platform case
@@ -1896,7 +2040,7 @@ func (s *containerSchema) asTarball(
 	defer os.RemoveAll(tmpDir)
 	fileName := identity.NewID() + ".tar"
 
-	def, err := bk.ContainerImageToTarball(ctx, engineHostPlatform.Spec(), tmpDir, fileName, inputByPlatform, opts)
+	def, err := bk.ContainerImageToTarball(ctx, engineHostPlatform.Spec(), tmpDir, fileName, inputByPlatform, opts, signer)
 	if err != nil {
 		return inst, fmt.Errorf("container image to tarball file conversion failed: %w", err)
 	}
@@ -1914,28 +2058,102 @@ func (s *containerSchema) asTarball(
 	return fileInst.WithMetadata(dgst, true), nil
 }
 
+// imageSelectorArgs select the image to import from an archive or OCI layout.
+type imageSelectorArgs struct {
+	Tag    string `default:""`
+	Digest string `default:""`
+}
+
+func (args imageSelectorArgs) selector() (core.ImageSelector, error) {
+	sel := core.ImageSelector{Tag: args.Tag}
+	if args.Digest != "" {
+		dgst, err := digest.Parse(args.Digest)
+		if err != nil {
+			return sel, fmt.Errorf("invalid digest %q: %w", args.Digest, err)
+		}
+		sel.Digest = dgst
+	}
+	return sel, nil
+}
+
 type containerImportArgs struct {
 	Source core.FileID
-	Tag    string `default:""`
+	imageSelectorArgs
 }
 
 func (s *containerSchema) import_(ctx context.Context, parent *core.Container, args containerImportArgs) (*core.Container, error) {
 	start := time.Now()
-	slog.ExtraDebug("importing container", "source", args.Source.Display(), "tag", args.Tag)
+	slog.ExtraDebug("importing container", "source", args.Source.Display(), "tag", args.Tag, "digest", args.Digest)
 	defer func() {
-		slog.ExtraDebug("done importing container", "source", args.Source.Display(), "tag", args.Tag, "took", start)
+		slog.ExtraDebug("done importing container", "source", args.Source.Display(), "tag", args.Tag, "digest", args.Digest, "took", start)
 	}()
+	sel, err := args.selector()
+	if err != nil {
+		return nil, err
+	}
+	source, err := args.Source.Load(ctx, s.srv)
+	if err != nil {
+		return nil, err
+	}
+	return parent.Import(
+		ctx,
+		core.ImageSource{File: source.Self},
+		sel,
+	)
+}
+
+type containerImportDirectoryArgs struct {
+	Source core.DirectoryID
+	imageSelectorArgs
+}
+
+func (s *containerSchema) importDirectory(ctx context.Context, parent *core.Container, args containerImportDirectoryArgs) (*core.Container, error) {
+	sel, err := args.selector()
+	if err != nil {
+		return nil, err
+	}
 	source, err := args.Source.Load(ctx, s.srv)
 	if err != nil {
 		return nil, err
 	}
 	return parent.Import(
 		ctx,
-		source.Self,
-		args.Tag,
+		core.ImageSource{Directory: source.Self},
+		sel,
 	)
 }
 
+type containerImportVariantsArgs struct {
+	Source    dagql.Optional[core.FileID]
+	Directory dagql.Optional[core.DirectoryID]
+	imageSelectorArgs
+}
+
+func (s *containerSchema) importVariants(ctx context.Context, parent *core.Container, args containerImportVariantsArgs) (dagql.Array[*core.Container], error) {
+	if args.Source.Valid == args.Directory.Valid {
+		return nil, errors.New("exactly one of source or directory must be set")
+	}
+	sel, err := args.selector()
+	if err != nil {
+		return nil, err
+	}
+	var source core.ImageSource
+	if args.Source.Valid {
+		file, err := args.Source.Value.Load(ctx, s.srv)
+		if err != nil {
+			return nil, err
+		}
+		source.File = file.Self
+	} else {
+		dir, err := args.Directory.Value.Load(ctx, s.srv)
+		if err != nil {
+			return nil, err
+		}
+		source.Directory = dir.Self
+	}
+	return parent.ImportVariants(ctx, source, sel)
+}
+
 type containerWithRegistryAuthArgs struct {
 	Address  string
 	Username string
@@ -2006,7 +2224,10 @@ type containerWithExposedPortArgs struct {
 	Port                        int
 	Protocol                    core.NetworkProtocol `default:"TCP"`
 	Description                 *string
-	ExperimentalSkipHealthcheck bool `default:"false"`
+	ExperimentalSkipHealthcheck bool   `default:"false"`
+	HealthcheckPath             string `default:""`
+	HealthcheckScheme           dagql.Optional[core.HealthcheckScheme]
+	HealthcheckStatus           int `default:"0"`
 }
 
 func (s *containerSchema) withExposedPort(ctx context.Context, parent *core.Container, args containerWithExposedPortArgs) (*core.Container, error) {
@@ -2015,6 +2236,9 @@ func (s *containerSchema) withExposedPort(ctx context.Context, parent *core.Cont
 		Port:                        args.Port,
 		Description:                 args.Description,
 		ExperimentalSkipHealthcheck: args.ExperimentalSkipHealthcheck,
+		HealthcheckPath:             args.HealthcheckPath,
+		HealthcheckScheme:           args.HealthcheckScheme.Value,
+		HealthcheckStatus:           args.HealthcheckStatus,
 	})
 }
 
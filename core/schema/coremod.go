@@ -42,6 +42,7 @@ func (m *CoreMod) Install(ctx context.Context, dag *dagql.Server) error {
 		&directorySchema{dag},
 		&fileSchema{dag},
 		&gitSchema{dag},
+		&composeSchema{dag},
 		&containerSchema{dag},
 		&cacheSchema{dag},
 		&secretSchema{dag},
@@ -50,9 +51,11 @@ func (m *CoreMod) Install(ctx context.Context, dag *dagql.Server) error {
 		&httpSchema{dag},
 		&platformSchema{dag},
 		&socketSchema{dag},
+		&registrySchema{dag},
 		&moduleSchema{dag},
 		&errorSchema{dag},
 		&engineSchema{dag},
+		&cacheMissSchema{dag},
 	} {
 		schema.Install()
 	}
@@ -18,6 +18,7 @@ var _ SchemaResolvers = &directorySchema{}
 func (s *directorySchema) Install() {
 	dagql.Fields[*core.Query]{
 		dagql.Func("directory", s.directory).
+			Persistent().
 			Doc(`Creates an empty directory.`),
 	}.Install(s.srv)
 
@@ -32,60 +33,88 @@ func (s *directorySchema) Install() {
 			ArgDoc("description", "Description of the sub-pipeline.").
 			ArgDoc("labels", "Labels to apply to the sub-pipeline."),
 		dagql.Func("entries", s.entries).
+			Persistent().
 			Doc(`Returns a list of files and directories at the given path.`).
 			ArgDoc("path", `Location of the directory to look at (e.g., "/src").`),
 		dagql.Func("glob", s.glob).
+			Persistent().
 			Doc(`Returns a list of files and directories that matche the given pattern.`).
 			ArgDoc("pattern", `Pattern to match (e.g., "*.md").`),
 		dagql.Func("digest", s.digest).
+			Persistent().
 			Doc(
 				`Return the directory's digest.
 				The format of the digest is not guaranteed to be stable between releases of Dagger.
 				It is guaranteed to be stable between invocations of the same Dagger engine.`,
 			),
 		dagql.Func("file", s.file).
+			Persistent().
 			Doc(`Retrieves a file at the given path.`).
 			ArgDoc("path", `Location of the file to retrieve (e.g., "README.md").`),
 		dagql.Func("withFile", s.withFile).
+			Persistent().
 			Doc(`Retrieves this directory plus the contents of the given file copied to the given path.`).
 			ArgDoc("path", `Location of the copied file (e.g., "/file.txt").`).
 			ArgDoc("source", `Identifier of the file to copy.`).
 			ArgDoc("permissions", `Permission given to the copied file (e.g., 0600).`),
 		dagql.Func("withFiles", s.withFiles).
+			Persistent().
 			Doc(`Retrieves this directory plus the contents of the given files copied to the given path.`).
 			ArgDoc("path", `Location where copied files should be placed (e.g., "/src").`).
 			ArgDoc("sources", `Identifiers of the files to copy.`).
 			ArgDoc("permissions", `Permission given to the copied files (e.g., 0600).`),
 		dagql.Func("withNewFile", s.withNewFile).
+			Persistent().
 			Doc(`Retrieves this directory plus a new file written at the given path.`).
 			ArgDoc("path", `Location of the written file (e.g., "/file.txt").`).
 			ArgDoc("contents", `Content of the written file (e.g., "Hello world!").`).
 			ArgDoc("permissions", `Permission given to the copied file (e.g., 0600).`),
 		dagql.Func("withoutFile", s.withoutFile).
+			Persistent().
 			Doc(`Retrieves this directory with the file at the given path removed.`).
 			ArgDoc("path", `Location of the file to remove (e.g., "/file.txt").`),
 		dagql.Func("withoutFiles", s.withoutFiles).
+			Persistent().
 			Doc(`Retrieves this directory with the files at the given paths removed.`).
 			ArgDoc("paths", `Location of the file to remove (e.g., ["/file.txt"]).`),
 		dagql.Func("directory", s.subdirectory).
+			Persistent().
 			Doc(`Retrieves a directory at the given path.`).
 			ArgDoc("path", `Location of the directory to retrieve (e.g., "/src").`),
 		dagql.Func("withDirectory", s.withDirectory).
+			Persistent().
 			Doc(`Retrieves this directory plus a directory written at the given path.`).
 			ArgDoc("path", `Location of the written directory (e.g., "/src/").`).
 			ArgDoc("directory", `Identifier of the directory to copy.`).
 			ArgDoc("exclude", `Exclude artifacts that match the given pattern (e.g., ["node_modules/", ".git*"]).`).
 			ArgDoc("include", `Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).`),
 		dagql.Func("withNewDirectory", s.withNewDirectory).
+			Persistent().
 			Doc(`Retrieves this directory plus a new directory created at the given path.`).
 			ArgDoc("path", `Location of the directory created (e.g., "/logs").`).
 			ArgDoc("permissions", `Permission granted to the created directory (e.g., 0777).`),
 		dagql.Func("withoutDirectory", s.withoutDirectory).
+			Persistent().
 			Doc(`Retrieves this directory with the directory at the given path removed.`).
 			ArgDoc("path", `Location of the directory to remove (e.g., ".github/").`),
 		dagql.Func("diff", s.diff).
+			Persistent().
 			Doc(`Gets the difference between this directory and an another directory.`).
 			ArgDoc("other", `Identifier of the directory to compare.`),
+		dagql.Func("withPatch", s.withPatch).
+			Persistent().
+			Doc(`Retrieves this directory with the given unified diff applied.`,
+				`Fails if any hunk of the patch does not apply cleanly.`).
+			ArgDoc("patch", `File containing the patch to apply, as produced by "diff -u" or "git diff".`),
+		dagql.Func("asPatch", s.asPatch).
+			Persistent().
+			Doc(`Returns a unified diff, in the format of "git diff", of the files changed between this directory and another directory.`).
+			ArgDoc("other", `Identifier of the directory to compare.`),
+		dagql.Func("merge", s.merge).
+			Persistent().
+			Doc(`Retrieves this directory with the contents of the given directories merged into it.`,
+				`Fails if the same path holds different contents in more than one of the directories, instead of overwriting it.`).
+			ArgDoc("directories", `Identifiers of the directories to merge, in order.`),
 		dagql.Func("export", s.export).
 			View(AllVersion).
 			Impure("Writes to the local host.").
@@ -102,8 +131,15 @@ func (s *directorySchema) Install() {
 			ArgDoc("buildArgs", `Build arguments to use in the build.`).
 			ArgDoc("target", `Target build stage to build.`).
 			ArgDoc("secrets", `Secrets to pass to the build.`,
-				`They will be mounted at /run/secrets/[secret-name].`),
+				`They will be mounted at /run/secrets/[secret-name].`).
+			ArgDoc("buildContexts", `Named build contexts to pass to the build.`,
+				`They can be referenced by name in the Dockerfile (e.g., "COPY --from=<name>" or "FROM <name>").`).
+			ArgDoc("ssh", `A socket to forward to the build as the default SSH agent.`,
+				`It is available to "RUN --mount=type=ssh" instructions.`).
+			ArgDoc("noCacheFilter", `Build stages to build without using the cache.`).
+			ArgDoc("cacheFrom", `Registry references to import the build cache from (e.g., "registry.example.com/app:cache").`),
 		dagql.Func("withTimestamps", s.withTimestamps).
+			Persistent().
 			Doc(`Retrieves this directory with all file/dir timestamps set to the given time.`).
 			ArgDoc("timestamp", `Timestamp to set dir/files in.`,
 				`Formatted in seconds following Unix epoch (e.g., 1672531199).`),
@@ -297,6 +333,42 @@ func (s *directorySchema) diff(ctx context.Context, parent *core.Directory, args
 	return parent.Diff(ctx, dir.Self)
 }
 
+type withPatchArgs struct {
+	Patch core.FileID
+}
+
+func (s *directorySchema) withPatch(ctx context.Context, parent *core.Directory, args withPatchArgs) (*core.Directory, error) {
+	patch, err := args.Patch.Load(ctx, s.srv)
+	if err != nil {
+		return nil, err
+	}
+	return parent.WithPatch(ctx, patch.Self)
+}
+
+func (s *directorySchema) asPatch(ctx context.Context, parent *core.Directory, args diffArgs) (*core.File, error) {
+	dir, err := args.Other.Load(ctx, s.srv)
+	if err != nil {
+		return nil, err
+	}
+	return parent.AsPatch(ctx, dir.Self)
+}
+
+type mergeArgs struct {
+	Directories []core.DirectoryID
+}
+
+func (s *directorySchema) merge(ctx context.Context, parent *core.Directory, args mergeArgs) (*core.Directory, error) {
+	dirs := make([]*core.Directory, 0, len(args.Directories))
+	for _, id := range args.Directories {
+		dir, err := id.Load(ctx, s.srv)
+		if err != nil {
+			return nil, err
+		}
+		dirs = append(dirs, dir.Self)
+	}
+	return parent.Merge(ctx, dirs)
+}
+
 type dirExportArgs struct {
 	Path string
 	Wipe bool `default:"false"`
@@ -327,11 +399,15 @@ func (s *directorySchema) exportLegacy(ctx context.Context, parent *core.Directo
 }
 
 type dirDockerBuildArgs struct {
-	Platform   dagql.Optional[core.Platform]
-	Dockerfile string                             `default:"Dockerfile"`
-	Target     string                             `default:""`
-	BuildArgs  []dagql.InputObject[core.BuildArg] `default:"[]"`
-	Secrets    []core.SecretID                    `default:"[]"`
+	Platform      dagql.Optional[core.Platform]
+	Dockerfile    string                                 `default:"Dockerfile"`
+	Target        string                                 `default:""`
+	BuildArgs     []dagql.InputObject[core.BuildArg]     `default:"[]"`
+	Secrets       []core.SecretID                        `default:"[]"`
+	BuildContexts []dagql.InputObject[core.BuildContext] `default:"[]"`
+	SSH           dagql.Optional[core.SocketID]          `name:"ssh"`
+	NoCacheFilter []string                               `default:"[]"`
+	CacheFrom     []string                               `default:"[]"`
 }
 
 func (s *directorySchema) dockerBuild(ctx context.Context, parent *core.Directory, args dirDockerBuildArgs) (*core.Container, error) {
@@ -351,6 +427,45 @@ func (s *directorySchema) dockerBuild(ctx context.Context, parent *core.Director
 	if err != nil {
 		return nil, fmt.Errorf("failed to get secret store: %w", err)
 	}
+	buildOpts := core.DockerBuildOpts{
+		NoCacheFilter: args.NoCacheFilter,
+		CacheFrom:     args.CacheFrom,
+	}
+	seen := map[string]bool{}
+	for _, buildCtx := range collectInputsSlice(args.BuildContexts) {
+		if buildCtx.Name == "" {
+			return nil, fmt.Errorf("build context name must not be empty")
+		}
+		if seen[buildCtx.Name] {
+			return nil, fmt.Errorf("duplicate build context %q", buildCtx.Name)
+		}
+		seen[buildCtx.Name] = true
+		if buildCtx.Directory.Valid == buildCtx.Container.Valid {
+			return nil, fmt.Errorf("build context %q must have exactly one of directory or container", buildCtx.Name)
+		}
+		namedCtx := core.NamedBuildContext{Name: buildCtx.Name}
+		if buildCtx.Directory.Valid {
+			dir, err := buildCtx.Directory.Value.Load(ctx, s.srv)
+			if err != nil {
+				return nil, err
+			}
+			namedCtx.Directory = dir.Self
+		} else {
+			ctr, err := buildCtx.Container.Value.Load(ctx, s.srv)
+			if err != nil {
+				return nil, err
+			}
+			namedCtx.Container = ctr.Self
+		}
+		buildOpts.Contexts = append(buildOpts.Contexts, namedCtx)
+	}
+	if args.SSH.Valid {
+		sock, err := args.SSH.Value.Load(ctx, s.srv)
+		if err != nil {
+			return nil, err
+		}
+		buildOpts.SSH = sock.Self
+	}
 	return ctr.Build(
 		ctx,
 		parent,
@@ -359,6 +474,7 @@ func (s *directorySchema) dockerBuild(ctx context.Context, parent *core.Director
 		args.Target,
 		secrets,
 		secretStore,
+		buildOpts,
 	)
 }
 
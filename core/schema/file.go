@@ -20,12 +20,16 @@ func (s *fileSchema) Install() {
 		Syncer[*core.File]().
 			Doc(`Force evaluation in the engine.`),
 		dagql.Func("contents", s.contents).
+			Persistent().
 			Doc(`Retrieves the contents of the file.`),
 		dagql.Func("size", s.size).
+			Persistent().
 			Doc(`Retrieves the size of the file, in bytes.`),
 		dagql.Func("name", s.name).
+			Persistent().
 			Doc(`Retrieves the name of the file.`),
 		dagql.Func("digest", s.digest).
+			Persistent().
 			Doc(
 				`Return the file's digest.
 				The format of the digest is not guaranteed to be stable between releases of Dagger.
@@ -33,6 +37,7 @@ func (s *fileSchema) Install() {
 			).
 			ArgDoc("excludeMetadata", `If true, exclude metadata from the digest.`),
 		dagql.Func("withName", s.withName).
+			Persistent().
 			Doc(`Retrieves this file with its name set to the given name.`).
 			ArgDoc("name", `Name to set file to.`),
 		dagql.Func("export", s.export).
@@ -47,6 +52,7 @@ func (s *fileSchema) Install() {
 			View(BeforeVersion("v0.12.0")).
 			Extend(),
 		dagql.Func("withTimestamps", s.withTimestamps).
+			Persistent().
 			Doc(`Retrieves this file with its created/modified timestamps set to the given time.`).
 			ArgDoc("timestamp", `Timestamp to set dir/files in.`,
 				`Formatted in seconds following Unix epoch (e.g., 1672531199).`),
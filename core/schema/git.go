@@ -76,6 +76,11 @@ func (s *gitSchema) Install() {
 			Doc(`Returns details of a commit.`).
 			// TODO: id is normally a reserved word; we should probably rename this
 			ArgDoc("id", `Identifier of the commit (e.g., "b6315d8f2810962c601af73f86831f6866ea798b").`),
+		dagql.Func("log", s.log).
+			Doc(`Returns the commits reachable from a ref, newest first.`).
+			ArgDoc("from", `Exclude the commits reachable from this ref (e.g., "v0.3.8"). If empty, the whole history is returned.`).
+			ArgDoc("to", `Ref to list the history of (e.g., "main"). If empty, HEAD is used.`).
+			ArgDoc("paths", `Only return commits that change one of these paths.`),
 		dagql.Func("withAuthToken", s.withAuthToken).
 			Doc(`Token to authenticate the remote with.`).
 			ArgDoc("token", `Secret used to populate the password during basic HTTP Authorization`),
@@ -88,7 +93,12 @@ func (s *gitSchema) Install() {
 		dagql.Func("tree", s.tree).
 			View(AllVersion).
 			Doc(`The filesystem tree at this ref.`).
-			ArgDoc("discardGitDir", `Set to true to discard .git directory.`),
+			ArgDoc("discardGitDir", `Set to true to discard .git directory.`).
+			ArgDoc("sparseCheckout", `Only check out the paths matching these patterns, which use the syntax of .gitignore files (e.g., ["/docs/", "*.md"]).`).
+			ArgDoc("depth", `The number of commits of history to fetch into the .git directory.`).
+			ArgDoc("fullHistory", `Set to true to fetch the full history and tags into the .git directory, e.g. for "git describe". Overrides depth.`).
+			ArgDoc("skipSubmodules", `Set to true to not check out submodules. By default, submodules are checked out recursively.`).
+			ArgDoc("lfs", `Set to true to replace Git LFS pointer files with the content they point to. LFS objects are cached by the engine, so they're only downloaded once.`),
 		dagql.Func("tree", s.treeLegacy).
 			View(BeforeVersion("v0.12.0")).
 			Doc(`The filesystem tree at this ref.`).
@@ -97,7 +107,23 @@ func (s *gitSchema) Install() {
 			ArgDeprecated("sshAuthSocket", "This option should be passed to `git` instead."),
 		dagql.Func("commit", s.fetchCommit).
 			Doc(`The resolved commit id at this ref.`),
+		dagql.Func("commitInfo", s.commitInfo).
+			Doc(`The metadata of the resolved commit at this ref.`),
+		dagql.Func("describe", s.describe).
+			Doc(`The nearest tag reachable from this ref, as returned by "git describe --tags".`,
+				`If the tag doesn't point at the ref itself, it's suffixed with the number of commits since the tag and the abbreviated commit id (e.g., "v0.3.9-4-gb6315d8").`),
+		dagql.Func("verify", s.verify).
+			Doc(`Verify the GPG or SSH signature of the annotated tag or commit at this ref, failing if it's missing or not made by one of the trusted keys.`,
+				`Returns this ref pinned to the verified commit.`).
+			ArgDoc("keys", `GPG public keys trusted to sign the ref.`).
+			ArgDoc("allowedSigners", `SSH allowed signers file trusted to sign the ref, in the format read by "ssh-keygen -Y verify".`),
+		dagql.Func("signature", s.signature).
+			Doc(`The signature of this ref, if it was verified by "verify".`),
 	}.Install(s.srv)
+
+	dagql.Fields[*core.GitCommitInfo]{}.Install(s.srv)
+	dagql.Fields[*core.GitActor]{}.Install(s.srv)
+	dagql.Fields[*core.GitSignature]{}.Install(s.srv)
 }
 
 type gitArgs struct {
@@ -505,11 +531,22 @@ func (s *gitSchema) withAuthHeader(ctx context.Context, parent *core.GitReposito
 }
 
 type treeArgs struct {
-	DiscardGitDir bool `default:"false"`
+	DiscardGitDir  bool     `default:"false"`
+	SparseCheckout []string `default:"[]"`
+	Depth          int      `default:"1"`
+	FullHistory    bool     `default:"false"`
+	SkipSubmodules bool     `default:"false"`
+	LFS            bool     `name:"lfs" default:"false"`
 }
 
 func (s *gitSchema) tree(ctx context.Context, parent *core.GitRef, args treeArgs) (*core.Directory, error) {
-	return parent.Tree(ctx, args.DiscardGitDir)
+	return parent.Tree(ctx, args.DiscardGitDir, gitdns.CheckoutOpts{
+		SparseCheckout: args.SparseCheckout,
+		Depth:          args.Depth,
+		FullHistory:    args.FullHistory,
+		SkipSubmodules: args.SkipSubmodules,
+		LFS:            args.LFS,
+	})
 }
 
 type treeArgsLegacy struct {
@@ -535,7 +572,7 @@ func (s *gitSchema) treeLegacy(ctx context.Context, parent *core.GitRef, args tr
 		cp.SSHAuthSocket = authSock
 		res.Repo = &cp
 	}
-	return res.Tree(ctx, args.DiscardGitDir)
+	return res.Tree(ctx, args.DiscardGitDir, gitdns.CheckoutOpts{Depth: 1})
 }
 
 func (s *gitSchema) fetchCommit(ctx context.Context, parent *core.GitRef, _ struct{}) (dagql.String, error) {
@@ -546,6 +583,80 @@ func (s *gitSchema) fetchCommit(ctx context.Context, parent *core.GitRef, _ stru
 	return dagql.NewString(str), nil
 }
 
+func (s *gitSchema) commitInfo(ctx context.Context, parent *core.GitRef, _ struct{}) (*core.GitCommitInfo, error) {
+	return parent.CommitInfo(ctx)
+}
+
+func (s *gitSchema) describe(ctx context.Context, parent *core.GitRef, _ struct{}) (dagql.String, error) {
+	str, err := parent.Describe(ctx)
+	if err != nil {
+		return "", err
+	}
+	return dagql.NewString(str), nil
+}
+
+type verifyArgs struct {
+	Keys           []core.FileID `default:"[]"`
+	AllowedSigners dagql.Optional[core.FileID]
+}
+
+func (s *gitSchema) verify(ctx context.Context, parent *core.GitRef, args verifyArgs) (*core.GitRef, error) {
+	keys := make([][]byte, 0, len(args.Keys))
+	for _, id := range args.Keys {
+		file, err := id.Load(ctx, s.srv)
+		if err != nil {
+			return nil, err
+		}
+		key, err := file.Self.Contents(ctx)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	var allowedSigners []byte
+	if args.AllowedSigners.Valid {
+		file, err := args.AllowedSigners.Value.Load(ctx, s.srv)
+		if err != nil {
+			return nil, err
+		}
+		allowedSigners, err = file.Self.Contents(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return parent.Verify(ctx, keys, string(allowedSigners))
+}
+
+func (s *gitSchema) signature(ctx context.Context, parent *core.GitRef, _ struct{}) (dagql.Nullable[*core.GitSignature], error) {
+	if parent.Signature == nil {
+		return dagql.Null[*core.GitSignature](), nil
+	}
+	return dagql.NonNull(parent.Signature), nil
+}
+
+type logArgs struct {
+	From  string   `default:""`
+	To    string   `default:""`
+	Paths []string `default:"[]"`
+}
+
+func (s *gitSchema) log(ctx context.Context, parent *core.GitRepository, args logArgs) (dagql.Array[*core.GitCommitInfo], error) {
+	to := &core.GitRef{
+		Query: parent.Query,
+		Ref:   args.To,
+		Repo:  parent,
+	}
+	var from *core.GitRef
+	if args.From != "" {
+		from = &core.GitRef{
+			Query: parent.Query,
+			Ref:   args.From,
+			Repo:  parent,
+		}
+	}
+	return to.Log(ctx, from, args.Paths)
+}
+
 func isSemver(ver string) bool {
 	re := regexp.MustCompile(`^v[0-9]+\.[0-9]+\.[0-9]+$`)
 	return re.MatchString(ver)
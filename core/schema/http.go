@@ -2,9 +2,13 @@ package schema
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path"
 
 	"github.com/moby/buildkit/client/llb"
 	"github.com/opencontainers/go-digest"
+	"golang.org/x/net/http/httpguts"
 
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
@@ -23,13 +27,25 @@ func (s *httpSchema) Install() {
 		dagql.Func("http", s.http).
 			Doc(`Returns a file containing an http remote url content.`).
 			ArgDoc("url", `HTTP url to get the content from (e.g., "https://docs.dagger.io").`).
-			ArgDoc("experimentalServiceHost", `A service which must be started before the URL is fetched.`),
+			ArgDoc("experimentalServiceHost", `A service which must be started before the URL is fetched.`).
+			ArgDoc("checksum", `Expected digest of the content (e.g., "sha256:..."). The fetch fails if the content doesn't match, and is cached by the digest without contacting the server.`).
+			ArgDoc("headers", `Headers to send with the request.`).
+			ArgDoc("authToken", `Secret sent as a bearer token in the Authorization header.`).
+			ArgDoc("authHeader", `Secret sent as the value of the Authorization header (e.g., "Basic <base64 of user:password>"). Takes precedence over authToken.`).
+			ArgDoc("name", `Name of the file. Defaults to a digest of the URL.`).
+			ArgDoc("permissions", `Permissions of the file (e.g., 0755). Defaults to 0600.`),
 	}.Install(s.srv)
 }
 
 type httpArgs struct {
 	URL                     string
 	ExperimentalServiceHost dagql.Optional[core.ServiceID]
+	Checksum                dagql.Optional[dagql.String]
+	Headers                 []dagql.InputObject[core.HTTPHeader] `default:"[]"`
+	AuthToken               dagql.Optional[core.SecretID]
+	AuthHeader              dagql.Optional[core.SecretID]
+	Name                    dagql.Optional[dagql.String]
+	Permissions             dagql.Optional[dagql.Int]
 }
 
 func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs) (*core.File, error) {
@@ -38,6 +54,12 @@ func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs
 	// of following more optimized cache codepaths.
 	// Do a hash encode to prevent conflicts with use of `/` in the URL while also not hitting max filename limits
 	filename := digest.FromString(args.URL).Encoded()
+	if args.Name.Valid {
+		filename = args.Name.Value.String()
+		if filename != path.Base(filename) || filename == "." || filename == ".." || filename == "/" {
+			return nil, fmt.Errorf("invalid file name %q", filename)
+		}
+	}
 
 	svcs := core.ServiceBindings{}
 	if args.ExperimentalServiceHost.Valid {
@@ -59,12 +81,54 @@ func (s *httpSchema) http(ctx context.Context, parent *core.Query, args httpArgs
 	opts := []llb.HTTPOption{
 		llb.Filename(filename),
 	}
+	if args.Checksum.Valid {
+		dgst, err := digest.Parse(args.Checksum.Value.String())
+		if err != nil {
+			return nil, fmt.Errorf("invalid checksum: %w", err)
+		}
+		opts = append(opts, llb.Checksum(dgst))
+	}
+	if args.Permissions.Valid {
+		perm := args.Permissions.Value.Int()
+		if perm <= 0 || perm > 0o7777 {
+			return nil, fmt.Errorf("invalid permissions %#o", perm)
+		}
+		opts = append(opts, llb.Chmod(os.FileMode(perm)))
+	}
+
+	var request httpdns.RequestOpts
+	for _, header := range collectInputsSlice(args.Headers) {
+		if !httpguts.ValidHeaderFieldName(header.Name) || !httpguts.ValidHeaderFieldValue(header.Value) {
+			return nil, fmt.Errorf("invalid header %q", header.Name)
+		}
+		request.Headers = append(request.Headers, httpdns.Header{
+			Name:  header.Name,
+			Value: header.Value,
+		})
+	}
+	if args.AuthToken.Valid {
+		secret, err := args.AuthToken.Value.Load(ctx, s.srv)
+		if err != nil {
+			return nil, err
+		}
+		request.AuthTokenSecret = secret.Self.LLBID()
+	}
+	if args.AuthHeader.Valid {
+		secret, err := args.AuthHeader.Value.Load(ctx, s.srv)
+		if err != nil {
+			return nil, err
+		}
+		request.AuthHeaderSecret = secret.Self.LLBID()
+	}
 
 	clientMetadata, err := engine.ClientMetadataFromContext(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	st := httpdns.HTTP(args.URL, clientMetadata.SessionID, opts...)
+	st, err := httpdns.HTTP(args.URL, clientMetadata.SessionID, request, opts...)
+	if err != nil {
+		return nil, err
+	}
 	return core.NewFileSt(ctx, parent, st, filename, parent.Platform(), svcs)
 }
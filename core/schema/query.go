@@ -49,8 +49,14 @@ func (s *querySchema) Install() {
 	s.srv.InstallScalar(core.Void{})
 
 	core.NetworkProtocols.Install(s.srv)
+	core.NetworkModes.Install(s.srv)
+	core.HealthcheckSchemes.Install(s.srv)
+	core.ServiceRestartPolicies.Install(s.srv)
 	core.ImageLayerCompressions.Install(s.srv)
 	core.ImageMediaTypesEnum.Install(s.srv)
+	core.ImageLayerSquashes.Install(s.srv)
+	core.ImageHistories.Install(s.srv)
+	core.CacheMissKinds.Install(s.srv)
 	core.CacheSharingModes.Install(s.srv)
 	core.TypeDefKinds.Install(s.srv)
 	core.ModuleSourceKindEnum.Install(s.srv)
@@ -59,6 +65,8 @@ func (s *querySchema) Install() {
 	dagql.MustInputSpec(PipelineLabel{}).Install(s.srv)
 	dagql.MustInputSpec(core.PortForward{}).Install(s.srv)
 	dagql.MustInputSpec(core.BuildArg{}).Install(s.srv)
+	dagql.MustInputSpec(core.BuildContext{}).Install(s.srv)
+	dagql.MustInputSpec(core.HTTPHeader{}).Install(s.srv)
 
 	dagql.Fields[EnvVariable]{}.Install(s.srv)
 
@@ -0,0 +1,160 @@
+package schema
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dagger/dagger/core"
+	"github.com/dagger/dagger/dagql"
+)
+
+type registrySchema struct {
+	srv *dagql.Server
+}
+
+var _ SchemaResolvers = &registrySchema{}
+
+func (s *registrySchema) Install() {
+	dagql.Fields[*core.Query]{
+		dagql.Func("registry", s.registry).
+			Doc(`Inspects an image in a registry, without pulling its layers.`,
+				`Credentials set with Container.withRegistryAuth and the client's
+				credentials are used to authenticate to the registry.`).
+			ArgDoc("address",
+				`Address of the image (e.g., "alpine", "docker.io/library/alpine:3.20" or "alpine@sha256:...").`,
+				`The tag defaults to "latest".`),
+	}.Install(s.srv)
+
+	dagql.Fields[*core.Registry]{
+		dagql.Func("tags", s.tags).
+			Doc(`The tags of the image's repository.`),
+
+		dagql.Func("digest", s.digest).
+			Doc(`The digest of the image's manifest or index.`),
+
+		dagql.Func("platforms", s.platforms).
+			Doc(`The platforms of the image, in the order of its index.`,
+				`Attestations and signatures are skipped.`),
+
+		dagql.Func("manifestDigest", s.manifestDigest).
+			Doc(`The digest of the image's manifest for a platform.`).
+			ArgDoc("platform", `Platform of the manifest. Defaults to the platform of the engine.`),
+
+		dagql.Func("config", s.config).
+			Doc(`The config of the image for a platform.`).
+			ArgDoc("platform", `Platform of the image. Defaults to the platform of the engine.`),
+	}.Install(s.srv)
+
+	dagql.Fields[*core.ImageConfig]{
+		dagql.Func("platform", s.configPlatform).
+			Doc(`The platform of the image.`),
+
+		dagql.Func("entrypoint", s.configEntrypoint).
+			Doc(`The entrypoint of the image.`),
+
+		dagql.Func("defaultArgs", s.configDefaultArgs).
+			Doc(`The default arguments of the image's entrypoint.`),
+
+		dagql.Func("workdir", s.configWorkdir).
+			Doc(`The working directory of the image.`),
+
+		dagql.Func("user", s.configUser).
+			Doc(`The user of the image.`),
+
+		dagql.Func("envVariables", s.configEnvVariables).
+			Doc(`The environment variables of the image.`),
+
+		dagql.Func("labels", s.configLabels).
+			Doc(`The labels of the image, sorted by name.`),
+	}.Install(s.srv)
+}
+
+type registryArgs struct {
+	Address string
+}
+
+func (s *registrySchema) registry(ctx context.Context, parent *core.Query, args registryArgs) (*core.Registry, error) {
+	return core.NewRegistry(parent, args.Address)
+}
+
+func (s *registrySchema) tags(ctx context.Context, parent *core.Registry, args struct{}) (dagql.Array[dagql.String], error) {
+	tags, err := parent.Tags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return dagql.NewStringArray(tags...), nil
+}
+
+func (s *registrySchema) digest(ctx context.Context, parent *core.Registry, args struct{}) (dagql.String, error) {
+	desc, err := parent.Resolve(ctx)
+	if err != nil {
+		return "", err
+	}
+	return dagql.NewString(desc.Digest.String()), nil
+}
+
+func (s *registrySchema) platforms(ctx context.Context, parent *core.Registry, args struct{}) (dagql.Array[core.Platform], error) {
+	return parent.Platforms(ctx)
+}
+
+type registryPlatformArgs struct {
+	Platform dagql.Optional[core.Platform]
+}
+
+func (args registryPlatformArgs) platform(query *core.Query) core.Platform {
+	if args.Platform.Valid {
+		return args.Platform.Value
+	}
+	return query.Platform()
+}
+
+func (s *registrySchema) manifestDigest(ctx context.Context, parent *core.Registry, args registryPlatformArgs) (dagql.String, error) {
+	desc, err := parent.Manifest(ctx, args.platform(parent.Query))
+	if err != nil {
+		return "", err
+	}
+	return dagql.NewString(desc.Digest.String()), nil
+}
+
+func (s *registrySchema) config(ctx context.Context, parent *core.Registry, args registryPlatformArgs) (*core.ImageConfig, error) {
+	return parent.Config(ctx, args.platform(parent.Query))
+}
+
+func (s *registrySchema) configPlatform(ctx context.Context, parent *core.ImageConfig, args struct{}) (core.Platform, error) {
+	return parent.Platform, nil
+}
+
+func (s *registrySchema) configEntrypoint(ctx context.Context, parent *core.ImageConfig, args struct{}) (dagql.Array[dagql.String], error) {
+	return dagql.NewStringArray(parent.Config.Entrypoint...), nil
+}
+
+func (s *registrySchema) configDefaultArgs(ctx context.Context, parent *core.ImageConfig, args struct{}) (dagql.Array[dagql.String], error) {
+	return dagql.NewStringArray(parent.Config.Cmd...), nil
+}
+
+func (s *registrySchema) configWorkdir(ctx context.Context, parent *core.ImageConfig, args struct{}) (dagql.String, error) {
+	return dagql.NewString(parent.Config.WorkingDir), nil
+}
+
+func (s *registrySchema) configUser(ctx context.Context, parent *core.ImageConfig, args struct{}) (dagql.String, error) {
+	return dagql.NewString(parent.Config.User), nil
+}
+
+func (s *registrySchema) configEnvVariables(ctx context.Context, parent *core.ImageConfig, args struct{}) ([]EnvVariable, error) {
+	vars := make([]EnvVariable, 0, len(parent.Config.Env))
+	core.WalkEnv(parent.Config.Env, func(k, v, _ string) {
+		vars = append(vars, EnvVariable{Name: k, Value: v})
+	})
+	return vars, nil
+}
+
+func (s *registrySchema) configLabels(ctx context.Context, parent *core.ImageConfig, args struct{}) ([]Label, error) {
+	labels := make([]Label, 0, len(parent.Config.Labels))
+	for name, value := range parent.Config.Labels {
+		labels = append(labels, Label{Name: name, Value: value})
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].Name < labels[j].Name
+	})
+	return labels, nil
+}
@@ -28,7 +28,13 @@ func (s *secretSchema) Install() {
 		dagql.Func("secret", s.secret).
 			Impure("`secret` mutates state in the internal secret store.").
 			Doc(`Creates a new secret.`).
-			ArgDoc("uri", `The URI of the secret store`),
+			ArgDoc("uri", `The URI of the secret store`,
+				`The scheme selects the provider that resolves the secret on the client,
+				e.g. env://, file://, cmd://, op://, vault://, sops://, keyring:// or
+				aws://. Other schemes are resolved by a dagger-secret-[scheme] executable
+				on the client's PATH.`,
+				`Only the syntax of the URI is checked here: a scheme the client has no
+				provider for fails when the secret is first used.`),
 
 		dagql.Func("loadSecretFromName", s.loadSecretFromName).
 			Doc(`Load a Secret from its Name.`),
@@ -42,6 +48,11 @@ func (s *secretSchema) Install() {
 		dagql.Func("plaintext", s.plaintext).
 			Impure("A secret's `plaintext` value in the internal secret store state can change.").
 			Doc(`The value of this secret.`),
+		dagql.Func("refresh", s.refresh).
+			Impure("Resolves the secret again, replacing its cached value.").
+			Doc(`Resolves this secret again from its provider, replacing the value the
+				client cached for the session, e.g. after it was rotated.`,
+				`Secrets set from a plaintext have nothing to refresh.`),
 	}.Install(s.srv)
 }
 
@@ -185,3 +196,15 @@ func (s *secretSchema) plaintext(ctx context.Context, secret *core.Secret, args
 
 	return dagql.NewString(string(plaintext)), nil
 }
+
+func (s *secretSchema) refresh(ctx context.Context, secret *core.Secret, args struct{}) (dagql.Nullable[core.Void], error) {
+	void := dagql.Null[core.Void]()
+	secretStore, err := secret.Query.Secrets(ctx)
+	if err != nil {
+		return void, fmt.Errorf("failed to get secret store: %w", err)
+	}
+	if err := secretStore.RefreshSecret(ctx, secret.IDDigest); err != nil {
+		return void, err
+	}
+	return void, nil
+}
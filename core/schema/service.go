@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"runtime/debug"
+	"time"
 
 	"github.com/dagger/dagger/core"
 	"github.com/dagger/dagger/dagql"
@@ -50,7 +51,26 @@ func (s *serviceSchema) Install() {
 				`If set, skip the automatic init process injected into containers by default.`,
 				`This should only be used if the user requires that their exec process be the
 				pid 1 process in the container. Otherwise it may result in unexpected behavior.`,
-			),
+			).
+			ArgDoc("timeout",
+				`Number of seconds the service may run for before it's killed.`).
+			ArgDoc("cpuLimit",
+				`Number of CPUs the service may use (e.g., 0.5).`).
+			ArgDoc("memoryLimit",
+				`Memory in bytes the service may use before it's killed (e.g., 536870912 for 512 MiB).`).
+			ArgDoc("pidsLimit",
+				`Number of processes the service may have running at once.`).
+			ArgDoc("healthcheckExec",
+				`Command to run in the service container to check that it's healthy (e.g., ["pg_isready"]).`,
+				`It runs once the exposed ports are reachable, and the service is healthy once it exits successfully.`).
+			ArgDoc("healthcheckInterval",
+				`Number of seconds to wait between health check attempts.`,
+				`If unset, attempts back off exponentially.`).
+			ArgDoc("healthcheckTimeout",
+				`Number of seconds each health check attempt may take.`).
+			ArgDoc("healthcheckRetries",
+				`Number of times to retry a failing health check before the service fails to start.`,
+				`If unset, health checks are retried for up to 15 minutes.`),
 
 		dagql.NodeFunc("up", s.containerUpLegacy).
 			View(BeforeVersion("v0.15.2")).
@@ -92,7 +112,26 @@ func (s *serviceSchema) Install() {
 				`If set, skip the automatic init process injected into containers by default.`,
 				`This should only be used if the user requires that their exec process be the
 				pid 1 process in the container. Otherwise it may result in unexpected behavior.`,
-			),
+			).
+			ArgDoc("timeout",
+				`Number of seconds the service may run for before it's killed.`).
+			ArgDoc("cpuLimit",
+				`Number of CPUs the service may use (e.g., 0.5).`).
+			ArgDoc("memoryLimit",
+				`Memory in bytes the service may use before it's killed (e.g., 536870912 for 512 MiB).`).
+			ArgDoc("pidsLimit",
+				`Number of processes the service may have running at once.`).
+			ArgDoc("healthcheckExec",
+				`Command to run in the service container to check that it's healthy (e.g., ["pg_isready"]).`,
+				`It runs once the exposed ports are reachable, and the service is healthy once it exits successfully.`).
+			ArgDoc("healthcheckInterval",
+				`Number of seconds to wait between health check attempts.`,
+				`If unset, attempts back off exponentially.`).
+			ArgDoc("healthcheckTimeout",
+				`Number of seconds each health check attempt may take.`).
+			ArgDoc("healthcheckRetries",
+				`Number of times to retry a failing health check before the service fails to start.`,
+				`If unset, health checks are retried for up to 15 minutes.`),
 	}.Install(s.srv)
 
 	dagql.Fields[*core.Service]{
@@ -103,6 +142,22 @@ func (s *serviceSchema) Install() {
 			Doc(`Configures a hostname which can be used by clients within the session to reach this container.`).
 			ArgDoc("hostname", `The hostname to use.`),
 
+		dagql.Func("withRestartPolicy", s.withRestartPolicy).
+			Doc(`Configures whether the service is restarted when it exits.`,
+				`The service keeps the same hostname across restarts, so containers
+				bound to it can keep reaching it.`).
+			ArgDoc("policy", `When to restart the service.`).
+			ArgDoc("maxRetries",
+				`Maximum number of times to restart the service. If unset, there is no limit.`),
+
+		dagql.Func("withLivenessProbe", s.withLivenessProbe).
+			Doc(`Keeps running the service's health checks once it has started.`,
+				`The service is killed once its health checks fail enough times in a
+				row, and restarted if its restart policy allows it.`).
+			ArgDoc("interval", `Number of seconds between each health check.`).
+			ArgDoc("failureThreshold",
+				`Number of consecutive failed health checks after which the service is killed.`),
+
 		dagql.NodeFunc("ports", s.ports).
 			Impure("A tunnel service's ports can change each time it is restarted.").
 			Doc(`Retrieves the list of ports provided by the service.`),
@@ -115,6 +170,35 @@ func (s *serviceSchema) Install() {
 			ArgDoc("port", `The exposed port number for the endpoint`).
 			ArgDoc("scheme", `Return a URL with the given scheme, eg. http for http://`),
 
+		dagql.NodeFunc("stdout", s.stdout).
+			Impure("Output changes as the service runs.").
+			Doc(`The output the service has written to stdout.`,
+				`The service must have been started within the session. Its output
+				remains available once it has stopped, and includes the output of
+				every run if it has restarted.`).
+			ArgDoc("tail", `Only return this many lines from the end of the output.`).
+			ArgDoc("since",
+				`Only return output written since this point in time, either as a
+				duration (e.g., "10m") or as an RFC 3339 timestamp.`),
+
+		dagql.NodeFunc("stderr", s.stderr).
+			Impure("Output changes as the service runs.").
+			Doc(`The output the service has written to stderr.`,
+				`The service must have been started within the session. Its output
+				remains available once it has stopped, and includes the output of
+				every run if it has restarted.`).
+			ArgDoc("tail", `Only return this many lines from the end of the output.`).
+			ArgDoc("since",
+				`Only return output written since this point in time, either as a
+				duration (e.g., "10m") or as an RFC 3339 timestamp.`),
+
+		dagql.NodeFunc("exitCode", s.exitCode).
+			Impure("Exit code is only known once the service has exited.").
+			Doc(`The exit code the service last exited with, or null if it hasn't exited yet.`,
+				`If the service has been restarted, this is the exit code of the run
+				before the current one until that one exits too. The service must have
+				been started within the session.`),
+
 		dagql.NodeFunc("start", s.start).
 			Impure("Imperatively mutates runtime state.").
 			Doc(`Start the service and wait for its health checks to succeed.`,
@@ -196,6 +280,54 @@ func (s *serviceSchema) containerUp(ctx context.Context, ctr dagql.Instance[*cor
 			Value: dagql.Boolean(true),
 		})
 	}
+	if args.Timeout != 0 {
+		inputs = append(inputs, dagql.NamedInput{
+			Name:  "timeout",
+			Value: dagql.NewInt(args.Timeout),
+		})
+	}
+	if args.CPULimit != 0 {
+		inputs = append(inputs, dagql.NamedInput{
+			Name:  "cpuLimit",
+			Value: dagql.NewFloat(args.CPULimit),
+		})
+	}
+	if args.MemoryLimit != 0 {
+		inputs = append(inputs, dagql.NamedInput{
+			Name:  "memoryLimit",
+			Value: dagql.NewInt(args.MemoryLimit),
+		})
+	}
+	if args.PidsLimit != 0 {
+		inputs = append(inputs, dagql.NamedInput{
+			Name:  "pidsLimit",
+			Value: dagql.NewInt(args.PidsLimit),
+		})
+	}
+	if len(args.HealthcheckExec) > 0 {
+		inputs = append(inputs, dagql.NamedInput{
+			Name:  "healthcheckExec",
+			Value: dagql.ArrayInput[dagql.String](dagql.NewStringArray(args.HealthcheckExec...)),
+		})
+	}
+	if args.HealthcheckInterval != 0 {
+		inputs = append(inputs, dagql.NamedInput{
+			Name:  "healthcheckInterval",
+			Value: dagql.NewInt(args.HealthcheckInterval),
+		})
+	}
+	if args.HealthcheckTimeout != 0 {
+		inputs = append(inputs, dagql.NamedInput{
+			Name:  "healthcheckTimeout",
+			Value: dagql.NewInt(args.HealthcheckTimeout),
+		})
+	}
+	if args.HealthcheckRetries != 0 {
+		inputs = append(inputs, dagql.NamedInput{
+			Name:  "healthcheckRetries",
+			Value: dagql.NewInt(args.HealthcheckRetries),
+		})
+	}
 
 	var svc dagql.Instance[*core.Service]
 	err := s.srv.Select(ctx, ctr, &svc,
@@ -242,6 +374,24 @@ func (s *serviceSchema) withHostname(ctx context.Context, parent dagql.Instance[
 	return parent.Self.WithHostname(args.Hostname), nil
 }
 
+type serviceWithRestartPolicyArgs struct {
+	Policy     core.ServiceRestartPolicy
+	MaxRetries int `default:"0"`
+}
+
+func (s *serviceSchema) withRestartPolicy(ctx context.Context, parent *core.Service, args serviceWithRestartPolicyArgs) (*core.Service, error) {
+	return parent.WithRestartPolicy(args.Policy, args.MaxRetries)
+}
+
+type serviceWithLivenessProbeArgs struct {
+	Interval         int `default:"10"`
+	FailureThreshold int `default:"3"`
+}
+
+func (s *serviceSchema) withLivenessProbe(ctx context.Context, parent *core.Service, args serviceWithLivenessProbeArgs) (*core.Service, error) {
+	return parent.WithLivenessProbe(args.Interval, args.FailureThreshold)
+}
+
 func (s *serviceSchema) ports(ctx context.Context, parent dagql.Instance[*core.Service], args struct{}) (dagql.Array[core.Port], error) {
 	return parent.Self.Ports(ctx, parent.ID())
 }
@@ -259,6 +409,54 @@ func (s *serviceSchema) endpoint(ctx context.Context, parent dagql.Instance[*cor
 	return dagql.NewString(str), nil
 }
 
+type serviceLogsArgs struct {
+	Tail  int    `default:"0"`
+	Since string `default:""`
+}
+
+func (args serviceLogsArgs) since() (time.Time, error) {
+	if args.Tail < 0 {
+		return time.Time{}, fmt.Errorf("tail must not be negative, got %d", args.Tail)
+	}
+	return core.ParseLogsSince(args.Since)
+}
+
+func (s *serviceSchema) stdout(ctx context.Context, parent dagql.Instance[*core.Service], args serviceLogsArgs) (dagql.String, error) {
+	logs, err := parent.Self.Logs(ctx, parent.ID())
+	if err != nil {
+		return "", err
+	}
+	since, err := args.since()
+	if err != nil {
+		return "", err
+	}
+	return dagql.NewString(logs.Stdout.Read(since, args.Tail)), nil
+}
+
+func (s *serviceSchema) stderr(ctx context.Context, parent dagql.Instance[*core.Service], args serviceLogsArgs) (dagql.String, error) {
+	logs, err := parent.Self.Logs(ctx, parent.ID())
+	if err != nil {
+		return "", err
+	}
+	since, err := args.since()
+	if err != nil {
+		return "", err
+	}
+	return dagql.NewString(logs.Stderr.Read(since, args.Tail)), nil
+}
+
+func (s *serviceSchema) exitCode(ctx context.Context, parent dagql.Instance[*core.Service], args struct{}) (dagql.Nullable[dagql.Int], error) {
+	logs, err := parent.Self.Logs(ctx, parent.ID())
+	if err != nil {
+		return dagql.Null[dagql.Int](), err
+	}
+	code, exited := logs.ExitCode()
+	if !exited {
+		return dagql.Null[dagql.Int](), nil
+	}
+	return dagql.NonNull(dagql.NewInt(code)), nil
+}
+
 func (s *serviceSchema) start(ctx context.Context, parent dagql.Instance[*core.Service], args struct{}) (core.ServiceID, error) {
 	defer func() {
 		if err := recover(); err != nil {
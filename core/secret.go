@@ -5,12 +5,16 @@ import (
 	"fmt"
 	"sync"
 
+	"dagger.io/dagger/telemetry"
 	"github.com/dagger/dagger/engine/client/secretprovider"
 	bksession "github.com/moby/buildkit/session"
 	"github.com/moby/buildkit/session/secrets"
 	"github.com/opencontainers/go-digest"
 	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -114,7 +118,9 @@ func (store *SecretStore) NewSecret(secret *Secret, buildkitSessionID, uri strin
 		return fmt.Errorf("secret must have an ID digest")
 	}
 
-	_, _, err := secretprovider.ResolverForID(uri)
+	// the client may have its own providers, so it resolves the scheme when the
+	// secret is first used
+	_, _, err := secretprovider.ParseID(uri)
 	if err != nil {
 		return err
 	}
@@ -175,6 +181,18 @@ func (store *SecretStore) GetSecretURI(idDgst digest.Digest) (string, bool) {
 }
 
 func (store *SecretStore) GetSecretPlaintext(ctx context.Context, idDgst digest.Digest) ([]byte, error) {
+	return store.getSecretPlaintext(ctx, idDgst, false)
+}
+
+// RefreshSecret makes the client that provides the secret resolve it again,
+// replacing the plaintext it cached, e.g. after the secret was rotated.
+// Secrets set from a plaintext have nothing to refresh.
+func (store *SecretStore) RefreshSecret(ctx context.Context, idDgst digest.Digest) error {
+	_, err := store.getSecretPlaintext(ctx, idDgst, true)
+	return err
+}
+
+func (store *SecretStore) getSecretPlaintext(ctx context.Context, idDgst digest.Digest, refresh bool) (_ []byte, rerr error) {
 	store.mu.RLock()
 	defer store.mu.RUnlock()
 	secret, ok := store.secrets[idDgst]
@@ -196,12 +214,32 @@ func (store *SecretStore) GetSecretPlaintext(ctx context.Context, idDgst digest.
 		return nil, status.Errorf(codes.Internal, "failed to get buildkit session: %s", err)
 	}
 
+	// the URI may itself be sensitive (e.g. a cmd:// secret), so only show
+	// which provider resolves it
+	scheme, _, err := secretprovider.ParseID(secret.URI)
+	if err != nil {
+		return nil, err
+	}
+	verb := "resolve"
+	if refresh {
+		verb = "refresh"
+		ctx = metadata.AppendToOutgoingContext(ctx, secretprovider.RefreshHeader, "true")
+	}
+	ctx, span := Tracer(ctx).Start(ctx, fmt.Sprintf("%s %s secret", verb, scheme), telemetry.Internal())
+	defer telemetry.End(span, func() error { return rerr })
+
+	var header metadata.MD
 	resp, err := secrets.NewSecretsClient(caller.Conn()).GetSecret(ctx, &secrets.GetSecretRequest{
 		ID: secret.URI,
-	})
+	}, grpc.Header(&header))
 	if err != nil {
 		return nil, err
 	}
+	// the client caches secrets for the session; never record the plaintext
+	// here, only whether it had to be resolved again
+	if cacheStatus := header.Get(secretprovider.CacheStatusHeader); len(cacheStatus) > 0 {
+		span.SetAttributes(attribute.Bool(telemetry.CachedAttr, cacheStatus[0] == "hit"))
+	}
 	return resp.Data, nil
 }
 
@@ -54,6 +54,55 @@ type Service struct {
 
 	// The sockets on the host to reverse tunnel
 	HostSockets []*Socket `json:"host_sockets,omitempty"`
+
+	// Healthcheck configures how the container's health is checked when it starts.
+	Healthcheck ServiceHealthcheckOpts `json:"healthcheck"`
+
+	// RestartPolicy configures whether the service is restarted when it exits.
+	RestartPolicy ServiceRestartPolicy `json:"restart_policy,omitempty"`
+	// RestartMaxRetries limits how many times the service is restarted. Zero
+	// means no limit.
+	RestartMaxRetries int `json:"restart_max_retries,omitempty"`
+
+	// LivenessInterval is the number of seconds between re-running the health
+	// checks once the service has started. Zero disables liveness checks.
+	LivenessInterval int `json:"liveness_interval,omitempty"`
+	// LivenessFailureThreshold is the number of consecutive liveness check
+	// failures after which the service is considered failed and killed.
+	LivenessFailureThreshold int `json:"liveness_failure_threshold,omitempty"`
+}
+
+// ServiceRestartPolicy is a GraphQL enum type.
+type ServiceRestartPolicy string
+
+var ServiceRestartPolicies = dagql.NewEnum[ServiceRestartPolicy]()
+
+var (
+	ServiceRestartNever = ServiceRestartPolicies.Register("NEVER",
+		`Never restart the service.`)
+	ServiceRestartOnFailure = ServiceRestartPolicies.Register("ON_FAILURE",
+		`Restart the service when it exits with an error or fails its liveness checks.`)
+	ServiceRestartAlways = ServiceRestartPolicies.Register("ALWAYS",
+		`Restart the service whenever it exits, unless it was stopped.`)
+)
+
+func (policy ServiceRestartPolicy) Type() *ast.Type {
+	return &ast.Type{
+		NamedType: "ServiceRestartPolicy",
+		NonNull:   true,
+	}
+}
+
+func (policy ServiceRestartPolicy) TypeDescription() string {
+	return "When to restart a service that has exited."
+}
+
+func (policy ServiceRestartPolicy) Decoder() dagql.InputDecoder {
+	return ServiceRestartPolicies
+}
+
+func (policy ServiceRestartPolicy) ToLiteral() call.Literal {
+	return ServiceRestartPolicies.Literal(policy)
 }
 
 func (*Service) Type() *ast.Type {
@@ -79,6 +128,7 @@ func (svc *Service) Clone() *Service {
 	}
 	cp.TunnelPorts = cloneSlice(cp.TunnelPorts)
 	cp.HostSockets = cloneSlice(cp.HostSockets)
+	cp.Healthcheck = cp.Healthcheck.Clone()
 	return &cp
 }
 
@@ -88,6 +138,53 @@ func (svc *Service) WithHostname(hostname string) *Service {
 	return svc
 }
 
+func (svc *Service) WithRestartPolicy(policy ServiceRestartPolicy, maxRetries int) (*Service, error) {
+	if maxRetries < 0 {
+		return nil, fmt.Errorf("max retries must not be negative, got %d", maxRetries)
+	}
+	svc = svc.Clone()
+	svc.RestartPolicy = policy
+	svc.RestartMaxRetries = maxRetries
+	return svc, nil
+}
+
+func (svc *Service) WithLivenessProbe(interval, failureThreshold int) (*Service, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("interval must be positive, got %d", interval)
+	}
+	if failureThreshold <= 0 {
+		return nil, fmt.Errorf("failure threshold must be positive, got %d", failureThreshold)
+	}
+	svc = svc.Clone()
+	svc.LivenessInterval = interval
+	svc.LivenessFailureThreshold = failureThreshold
+	return svc, nil
+}
+
+// supervised returns whether the service needs to be watched once it has
+// started, to restart it or to check that it's still healthy.
+func (svc *Service) supervised() bool {
+	return (svc.RestartPolicy != "" && svc.RestartPolicy != ServiceRestartNever) ||
+		svc.LivenessInterval > 0
+}
+
+// shouldRestart returns whether the service should be restarted after
+// exiting with the given error, having already been restarted the given
+// number of times.
+func (svc *Service) shouldRestart(exitErr error, restarts int) bool {
+	if svc.RestartMaxRetries > 0 && restarts >= svc.RestartMaxRetries {
+		return false
+	}
+	switch svc.RestartPolicy {
+	case ServiceRestartAlways:
+		return true
+	case ServiceRestartOnFailure:
+		return exitErr != nil
+	default:
+		return false
+	}
+}
+
 func (svc *Service) Hostname(ctx context.Context, id *call.ID) (string, error) {
 	if svc.CustomHostname != "" {
 		return svc.CustomHostname, nil
@@ -214,6 +311,23 @@ func (svc *Service) Stop(ctx context.Context, id *call.ID, kill bool) error {
 	return svcs.Stop(ctx, id, kill)
 }
 
+// Logs returns the output and exit status of the service, which must have
+// been started within the session. They remain available once it has stopped.
+func (svc *Service) Logs(ctx context.Context, id *call.ID) (*ServiceLogs, error) {
+	svcs, err := svc.Query.Services(ctx)
+	if err != nil {
+		return nil, err
+	}
+	running, err := svcs.Inspect(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if running.Logs == nil {
+		return nil, fmt.Errorf("service %s does not run a process", running.Host)
+	}
+	return running.Logs(), nil
+}
+
 func (svc *Service) Start(
 	ctx context.Context,
 	id *call.ID,
@@ -397,6 +511,7 @@ func (svc *Service) startContainer(
 		Mounts:            mounts,
 		Hostname:          fullHost,
 		Platform:          &pbPlatform,
+		NetMode:           execOp.Network,
 		ExecutionMetadata: *execMD,
 	})
 	if err != nil {
@@ -409,14 +524,18 @@ func (svc *Service) startContainer(
 		}
 	}()
 
-	checked := make(chan error, 1)
-	go func() {
-		checked <- newHealth(bk, gc, fullHost, ctr.Ports).Check(ctx)
-	}()
-
 	env := append([]string{}, execOp.Meta.Env...)
 	env = append(env, telemetry.PropagationEnv(ctx)...)
 
+	health := newHealth(bk, gc, fullHost, ctr.Ports).
+		withExec(svc.Healthcheck, gc, bkgw.StartRequest{
+			Env:          env,
+			Cwd:          execOp.Meta.Cwd,
+			User:         execOp.Meta.User,
+			SecretEnv:    execOp.Secretenv,
+			SecurityMode: execOp.Security,
+		})
+
 	var stdinCtr, stdoutClient, stderrClient io.ReadCloser
 	var stdinClient, stdoutCtr, stderrCtr io.WriteCloser
 	if forwardStdin != nil {
@@ -431,6 +550,9 @@ func (svc *Service) startContainer(
 		stderrClient, stderrCtr = io.Pipe()
 	}
 
+	// keep the output around for querying, on top of forwarding it
+	logs := NewServiceLogs()
+
 	svcProc, err := gc.Start(execCtx, bkgw.StartRequest{
 		Args:         execOp.Meta.Args,
 		Env:          env,
@@ -439,14 +561,21 @@ func (svc *Service) startContainer(
 		SecretEnv:    execOp.Secretenv,
 		Tty:          interactive,
 		Stdin:        stdinCtr,
-		Stdout:       stdoutCtr,
-		Stderr:       stderrCtr,
+		Stdout:       logs.Stdout.Tee(stdoutCtr),
+		Stderr:       logs.Stderr.Tee(stderrCtr),
 		SecurityMode: execOp.Security,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("start container: %w", err)
 	}
 
+	// only check once the process is running, so that exec probes don't run
+	// in the container before the service itself
+	checked := make(chan error, 1)
+	go func() {
+		checked <- health.Check(ctx)
+	}()
+
 	if forwardStdin != nil {
 		forwardStdin(stdinClient, svcProc)
 	}
@@ -476,6 +605,7 @@ func (svc *Service) startContainer(
 		}()
 
 		exitErr = svcProc.Wait()
+		logs.Exit(exitErr)
 		slog.Info("service exited", "err", exitErr)
 
 		// show the exit status; doing so won't fail anything, and is
@@ -530,6 +660,12 @@ func (svc *Service) startContainer(
 	select {
 	case err := <-checked:
 		if err != nil {
+			// don't leave an unhealthy service running
+			stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+			defer cancel()
+			if stopErr := stopSvc(stopCtx, true); stopErr != nil {
+				slog.Warn("failed to stop unhealthy service", "err", stopErr)
+			}
 			return nil, fmt.Errorf("health check errored: %w", err)
 		}
 
@@ -541,8 +677,12 @@ func (svc *Service) startContainer(
 				Digest:    dig,
 				SessionID: clientMetadata.SessionID,
 			},
-			Stop: stopSvc,
-			Wait: waitSvc,
+			Stop:  stopSvc,
+			Wait:  waitSvc,
+			Check: health.Probe,
+			Logs: func() *ServiceLogs {
+				return logs
+			},
 		}, nil
 	case <-exited:
 		if exitErr != nil {
@@ -0,0 +1,243 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	bkgwpb "github.com/moby/buildkit/frontend/gateway/pb"
+)
+
+// ServiceLogLimit is the number of bytes of each of a service's output
+// streams that are kept around for querying.
+const ServiceLogLimit = 1024 * 1024
+
+// ServiceLogs collects the output and exit status of a service's process, so
+// that they can be queried while it runs and after it has exited. A service
+// that restarts keeps one ServiceLogs across all of its runs.
+type ServiceLogs struct {
+	Stdout *LogBuffer
+	Stderr *LogBuffer
+
+	mu sync.Mutex
+	// the exit code of each run that has exited, in order
+	exitCodes []int
+	// the logs that this run's logs continue, if any
+	continued *ServiceLogs
+}
+
+func NewServiceLogs() *ServiceLogs {
+	return &ServiceLogs{
+		Stdout: NewLogBuffer(ServiceLogLimit),
+		Stderr: NewLogBuffer(ServiceLogLimit),
+	}
+}
+
+// Exit records that the process exited with the given error from waiting on
+// it.
+func (logs *ServiceLogs) Exit(waitErr error) {
+	exitCode := 0
+	if waitErr != nil {
+		exitCode = 1
+		var exitErr *bkgwpb.ExitError
+		if errors.As(waitErr, &exitErr) {
+			exitCode = int(exitErr.ExitCode)
+		}
+	}
+
+	logs.mu.Lock()
+	if continued := logs.continued; continued != nil {
+		logs.mu.Unlock()
+		continued.recordExits(exitCode)
+		return
+	}
+	logs.exitCodes = append(logs.exitCodes, exitCode)
+	logs.mu.Unlock()
+}
+
+// ExitCode returns the exit code of the process's latest exit, and whether it
+// has ever exited.
+func (logs *ServiceLogs) ExitCode() (int, bool) {
+	logs.mu.Lock()
+	defer logs.mu.Unlock()
+	if len(logs.exitCodes) == 0 {
+		return 0, false
+	}
+	return logs.exitCodes[len(logs.exitCodes)-1], true
+}
+
+// Continue appends the logs of the next run of the process to these ones,
+// including anything it has written or exited with already.
+func (logs *ServiceLogs) Continue(next *ServiceLogs) {
+	next.Stdout.redirect(logs.Stdout)
+	next.Stderr.redirect(logs.Stderr)
+
+	next.mu.Lock()
+	exitCodes := next.exitCodes
+	next.exitCodes = nil
+	next.continued = logs
+	next.mu.Unlock()
+
+	logs.recordExits(exitCodes...)
+}
+
+func (logs *ServiceLogs) recordExits(exitCodes ...int) {
+	logs.mu.Lock()
+	defer logs.mu.Unlock()
+	logs.exitCodes = append(logs.exitCodes, exitCodes...)
+}
+
+// ParseLogsSince parses a point in time to read logs since, given either as a
+// duration before now (e.g., "10m") or as an RFC 3339 timestamp. An empty
+// string returns the zero time.
+func ParseLogsSince(since string) (time.Time, error) {
+	if since == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(since); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, since)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid since %q: must be a duration or an RFC 3339 timestamp", since)
+	}
+	return t, nil
+}
+
+// LogBuffer keeps the most recent output written to it, along with when it
+// was written.
+type LogBuffer struct {
+	limit int
+
+	mu     sync.Mutex
+	chunks []logChunk
+	size   int
+	// the buffer that writes go to instead, once redirected
+	target *LogBuffer
+}
+
+type logChunk struct {
+	time time.Time
+	data []byte
+}
+
+func NewLogBuffer(limit int) *LogBuffer {
+	return &LogBuffer{limit: limit}
+}
+
+func (buf *LogBuffer) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	chunk := logChunk{
+		time: time.Now(),
+		data: bytes.Clone(p),
+	}
+
+	buf.mu.Lock()
+	defer buf.mu.Unlock()
+	if buf.target != nil {
+		buf.target.append(chunk)
+		return len(p), nil
+	}
+	buf.appendLocked(chunk)
+	return len(p), nil
+}
+
+func (buf *LogBuffer) append(chunk logChunk) {
+	buf.mu.Lock()
+	defer buf.mu.Unlock()
+	buf.appendLocked(chunk)
+}
+
+func (buf *LogBuffer) appendLocked(chunk logChunk) {
+	buf.chunks = append(buf.chunks, chunk)
+	buf.size += len(chunk.data)
+	for buf.size > buf.limit {
+		over := buf.size - buf.limit
+		oldest := &buf.chunks[0]
+		if over < len(oldest.data) {
+			oldest.data = oldest.data[over:]
+			buf.size -= over
+			break
+		}
+		buf.size -= len(oldest.data)
+		buf.chunks = buf.chunks[1:]
+	}
+}
+
+// redirect moves the buffer's output to the target buffer, and sends any
+// further writes there.
+func (buf *LogBuffer) redirect(target *LogBuffer) {
+	buf.mu.Lock()
+	defer buf.mu.Unlock()
+	for _, chunk := range buf.chunks {
+		target.append(chunk)
+	}
+	buf.chunks = nil
+	buf.size = 0
+	buf.target = target
+}
+
+func (buf *LogBuffer) Close() error {
+	return nil
+}
+
+// Tee returns a writer that writes to both w and the buffer, or just the
+// buffer if w is nil. Closing it only closes w.
+func (buf *LogBuffer) Tee(w io.WriteCloser) io.WriteCloser {
+	if w == nil {
+		return buf
+	}
+	return logTee{WriteCloser: w, buf: buf}
+}
+
+type logTee struct {
+	io.WriteCloser
+	buf *LogBuffer
+}
+
+func (tee logTee) Write(p []byte) (int, error) {
+	_, _ = tee.buf.Write(p)
+	return tee.WriteCloser.Write(p)
+}
+
+// Read returns the output written since the given time, limited to its last
+// tail lines. A zero since or tail means no limit.
+func (buf *LogBuffer) Read(since time.Time, tail int) string {
+	buf.mu.Lock()
+	var out []byte
+	for _, chunk := range buf.chunks {
+		if chunk.time.Before(since) {
+			continue
+		}
+		out = append(out, chunk.data...)
+	}
+	buf.mu.Unlock()
+
+	if tail > 0 {
+		out = tailLines(out, tail)
+	}
+	return string(out)
+}
+
+// tailLines returns the last n lines of out, not counting a trailing newline.
+func tailLines(out []byte, n int) []byte {
+	end := len(out)
+	if end > 0 && out[end-1] == '\n' {
+		end--
+	}
+	for i := end - 1; i >= 0; i-- {
+		if out[i] != '\n' {
+			continue
+		}
+		n--
+		if n == 0 {
+			return out[i+1:]
+		}
+	}
+	return out
+}
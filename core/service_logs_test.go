@@ -0,0 +1,108 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	bkgwpb "github.com/moby/buildkit/frontend/gateway/pb"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dagger/dagger/core"
+)
+
+func TestLogBufferTail(t *testing.T) {
+	t.Parallel()
+
+	buf := core.NewLogBuffer(1024)
+	_, err := buf.Write([]byte("one\ntwo\n"))
+	require.NoError(t, err)
+	_, err = buf.Write([]byte("three\nfour\n"))
+	require.NoError(t, err)
+
+	require.Equal(t, "one\ntwo\nthree\nfour\n", buf.Read(time.Time{}, 0))
+	require.Equal(t, "four\n", buf.Read(time.Time{}, 1))
+	require.Equal(t, "two\nthree\nfour\n", buf.Read(time.Time{}, 3))
+	require.Equal(t, "one\ntwo\nthree\nfour\n", buf.Read(time.Time{}, 10))
+}
+
+func TestLogBufferSince(t *testing.T) {
+	t.Parallel()
+
+	buf := core.NewLogBuffer(1024)
+	_, err := buf.Write([]byte("before\n"))
+	require.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	since := time.Now()
+	_, err = buf.Write([]byte("after\n"))
+	require.NoError(t, err)
+
+	require.Equal(t, "after\n", buf.Read(since, 0))
+}
+
+func TestLogBufferLimit(t *testing.T) {
+	t.Parallel()
+
+	buf := core.NewLogBuffer(8)
+	_, err := buf.Write([]byte("0123"))
+	require.NoError(t, err)
+	_, err = buf.Write([]byte("456789"))
+	require.NoError(t, err)
+	require.Equal(t, "23456789", buf.Read(time.Time{}, 0))
+
+	_, err = buf.Write([]byte(strings.Repeat("x", 20)))
+	require.NoError(t, err)
+	require.Equal(t, strings.Repeat("x", 8), buf.Read(time.Time{}, 0))
+}
+
+func TestParseLogsSince(t *testing.T) {
+	t.Parallel()
+
+	since, err := core.ParseLogsSince("")
+	require.NoError(t, err)
+	require.True(t, since.IsZero())
+
+	since, err = core.ParseLogsSince("10m")
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now().Add(-10*time.Minute), since, time.Minute)
+
+	since, err = core.ParseLogsSince("2024-01-02T15:04:05Z")
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), since)
+
+	_, err = core.ParseLogsSince("yesterday")
+	require.ErrorContains(t, err, "invalid since")
+}
+
+func TestServiceLogsContinue(t *testing.T) {
+	t.Parallel()
+
+	logs := core.NewServiceLogs()
+	_, err := logs.Stdout.Write([]byte("run 1\n"))
+	require.NoError(t, err)
+	logs.Exit(&bkgwpb.ExitError{ExitCode: 3})
+
+	code, exited := logs.ExitCode()
+	require.True(t, exited)
+	require.Equal(t, 3, code)
+
+	// the restarted run has already written some output
+	next := core.NewServiceLogs()
+	_, err = next.Stdout.Write([]byte("run 2\n"))
+	require.NoError(t, err)
+	logs.Continue(next)
+
+	_, err = next.Stdout.Write([]byte("still run 2\n"))
+	require.NoError(t, err)
+	require.Equal(t, "run 1\nrun 2\nstill run 2\n", logs.Stdout.Read(time.Time{}, 0))
+
+	// the previous exit is reported until the new run exits
+	code, exited = logs.ExitCode()
+	require.True(t, exited)
+	require.Equal(t, 3, code)
+
+	next.Exit(nil)
+	code, exited = logs.ExitCode()
+	require.True(t, exited)
+	require.Equal(t, 0, code)
+}
@@ -0,0 +1,265 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"dagger.io/dagger/telemetry"
+	"github.com/dagger/dagger/dagql/call"
+	"github.com/dagger/dagger/engine/slog"
+)
+
+const (
+	// how long to wait before restarting a service, doubled with each restart
+	restartInitialDelay = 100 * time.Millisecond
+	restartMaxDelay     = 10 * time.Second
+)
+
+// serviceSupervisor watches a running service, restarting it according to
+// its restart policy when it exits or fails its liveness checks.
+type serviceSupervisor struct {
+	id  *call.ID
+	svc Startable
+
+	// the service's configuration, which doesn't change across restarts
+	cfg  *Service
+	host string
+
+	// the logs of every run of the service, if it has any
+	serviceLogs *ServiceLogs
+
+	mu         sync.Mutex
+	current    *RunningService
+	restarting bool
+	stopped    bool
+	stopping   chan struct{}
+
+	done    chan struct{}
+	exitErr error
+}
+
+// supervise starts supervising the given running service, returning a
+// RunningService that stays the same across restarts. Its host is derived
+// from the service's ID, so dependents keep reaching it at the same hostname
+// once it has restarted, and the engine updates their hosts files with its new
+// IP.
+//
+// Restarted services don't forward their stdio anywhere. Services that are
+// supervised are only ever started by Services.Start, which doesn't forward it
+// either; their output stays available through Logs.
+func supervise(ctx context.Context, id *call.ID, svc Startable, running *RunningService) *RunningService {
+	sup := &serviceSupervisor{
+		id:       id,
+		svc:      svc,
+		cfg:      running.Service,
+		host:     running.Host,
+		current:  running,
+		stopping: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	supervised := &RunningService{
+		Service: running.Service,
+		Key:     running.Key,
+		Host:    running.Host,
+		Ports:   running.Ports,
+		Stop:    sup.stop,
+		Wait:    sup.wait,
+		Check:   sup.check,
+	}
+	if running.Logs != nil {
+		sup.serviceLogs = running.Logs()
+		supervised.Logs = sup.logs
+	}
+	go sup.run(ctx)
+	return supervised
+}
+
+func (sup *serviceSupervisor) run(ctx context.Context) {
+	defer close(sup.done)
+
+	for restarts := 0; ; restarts++ {
+		sup.mu.Lock()
+		current := sup.current
+		sup.mu.Unlock()
+
+		exitErr := sup.watch(ctx, current)
+		if sup.isStopped() || !sup.cfg.shouldRestart(exitErr, restarts) {
+			sup.exitErr = exitErr
+			return
+		}
+
+		sup.mu.Lock()
+		sup.restarting = true
+		sup.mu.Unlock()
+
+		delay := restartMaxDelay
+		if restarts < 7 {
+			delay = min(restartInitialDelay<<restarts, restartMaxDelay)
+		}
+		select {
+		case <-sup.stopping:
+			sup.exitErr = exitErr
+			return
+		case <-time.After(delay):
+		}
+
+		next, err := sup.restart(ctx, restarts+1, exitErr)
+		if err != nil {
+			sup.exitErr = err
+			return
+		}
+		if sup.serviceLogs != nil && next.Logs != nil {
+			sup.serviceLogs.Continue(next.Logs())
+		}
+
+		sup.mu.Lock()
+		if sup.stopped {
+			// stopped while restarting; don't leave the new one running
+			sup.mu.Unlock()
+			_ = next.Stop(ctx, true)
+			sup.exitErr = exitErr
+			return
+		}
+		sup.current = next
+		sup.restarting = false
+		sup.mu.Unlock()
+	}
+}
+
+// watch blocks until the service exits, or until it fails enough liveness
+// checks in a row, in which case it's killed.
+func (sup *serviceSupervisor) watch(ctx context.Context, running *RunningService) error {
+	if sup.cfg.LivenessInterval <= 0 || running.Check == nil {
+		return running.Wait(ctx)
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- running.Wait(ctx)
+	}()
+
+	slog := slog.With("service", running.Host)
+
+	ticker := time.NewTicker(time.Duration(sup.cfg.LivenessInterval) * time.Second)
+	defer ticker.Stop()
+
+	var failures int
+	for {
+		select {
+		case err := <-exited:
+			return err
+		case <-ticker.C:
+			err := running.Check(ctx)
+			if err == nil {
+				failures = 0
+				continue
+			}
+			failures++
+			slog.Warn("liveness check failed", "err", err, "failures", failures)
+			if failures < sup.cfg.LivenessFailureThreshold {
+				continue
+			}
+			sup.mu.Lock()
+			if sup.stopped {
+				// being stopped anyway; don't count it as a failure, and leave
+				// stopping it to stop
+				sup.mu.Unlock()
+				return <-exited
+			}
+			// mark it as restarting so that a concurrent stop doesn't stop the
+			// same process again
+			sup.restarting = true
+			sup.mu.Unlock()
+			if stopErr := running.Stop(ctx, true); stopErr != nil {
+				slog.Warn("failed to stop unhealthy service", "err", stopErr)
+			}
+			<-exited
+			return fmt.Errorf("liveness check failed %d times: %w", failures, err)
+		}
+	}
+}
+
+func (sup *serviceSupervisor) restart(ctx context.Context, attempt int, cause error) (_ *RunningService, rerr error) {
+	ctx, span := Tracer(ctx).Start(ctx, fmt.Sprintf("restart %s", sup.host))
+	span.SetAttributes(attribute.Int("dagger.io/service.restart", attempt))
+	defer telemetry.End(span, func() error { return rerr })
+
+	slog := slog.SpanLogger(ctx, InstrumentationLibrary)
+	if cause != nil {
+		slog.Warn("service failed; restarting", "error", cause, "attempt", attempt)
+	} else {
+		slog.Info("service exited; restarting", "attempt", attempt)
+	}
+
+	// nothing to forward stdio to; see supervise
+	running, err := sup.svc.Start(ctx, sup.id, false, nil, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("restart: %w", err)
+	}
+	return running, nil
+}
+
+func (sup *serviceSupervisor) isStopped() bool {
+	sup.mu.Lock()
+	defer sup.mu.Unlock()
+	return sup.stopped
+}
+
+func (sup *serviceSupervisor) stop(ctx context.Context, force bool) error {
+	select {
+	case <-sup.done:
+		// already exited for good
+		return nil
+	default:
+	}
+
+	sup.mu.Lock()
+	if !sup.stopped {
+		sup.stopped = true
+		close(sup.stopping)
+	}
+	current := sup.current
+	restarting := sup.restarting
+	sup.mu.Unlock()
+
+	// if it's restarting, the old process has already exited, and the new one
+	// will be stopped once it has started
+	if !restarting {
+		if err := current.Stop(ctx, force); err != nil {
+			return err
+		}
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-sup.done:
+		return nil
+	}
+}
+
+func (sup *serviceSupervisor) wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-sup.done:
+		return sup.exitErr
+	}
+}
+
+func (sup *serviceSupervisor) check(ctx context.Context) error {
+	sup.mu.Lock()
+	current := sup.current
+	sup.mu.Unlock()
+	if current.Check == nil {
+		return nil
+	}
+	return current.Check(ctx)
+}
+
+func (sup *serviceSupervisor) logs() *ServiceLogs {
+	return sup.serviceLogs
+}
@@ -36,7 +36,10 @@ type Services struct {
 	starting map[ServiceKey]*sync.WaitGroup
 	running  map[ServiceKey]*RunningService
 	bindings map[ServiceKey]int
-	l        sync.Mutex
+	// stopped keeps services that have been stopped around until their
+	// session ends, so that their output can still be queried
+	stopped map[ServiceKey]*RunningService
+	l       sync.Mutex
 }
 
 // RunningService represents a service that is actively running.
@@ -67,6 +70,15 @@ type RunningService struct {
 
 	// Block until the service has exited or the provided context is canceled.
 	Wait func(ctx context.Context) error
+
+	// Check runs the service's health checks once, to see whether it's still
+	// healthy. It is nil for services that have no health checks to run.
+	Check func(ctx context.Context) error
+
+	// Logs returns the output and exit status of the service, across all of
+	// its runs if it has restarted. It is nil for services that don't run a
+	// process of their own.
+	Logs func() *ServiceLogs
 }
 
 // ServiceKey is a unique identifier for a service.
@@ -81,6 +93,7 @@ func NewServices() *Services {
 		starting: map[ServiceKey]*sync.WaitGroup{},
 		running:  map[ServiceKey]*RunningService{},
 		bindings: map[ServiceKey]int{},
+		stopped:  map[ServiceKey]*RunningService{},
 	}
 }
 
@@ -120,6 +133,33 @@ func (ss *Services) Get(ctx context.Context, id *call.ID) (*RunningService, erro
 	}
 }
 
+// Inspect returns the running service for the given service like Get, but
+// falls back to the last instance of it to run in the session if it has since
+// been stopped.
+func (ss *Services) Inspect(ctx context.Context, id *call.ID) (*RunningService, error) {
+	running, err := ss.Get(ctx, id)
+	if err == nil {
+		return running, nil
+	}
+
+	clientMetadata, cmErr := engine.ClientMetadataFromContext(ctx)
+	if cmErr != nil {
+		return nil, cmErr
+	}
+	key := ServiceKey{
+		Digest:    id.Digest(),
+		SessionID: clientMetadata.SessionID,
+	}
+
+	ss.l.Lock()
+	stopped, isStopped := ss.stopped[key]
+	ss.l.Unlock()
+	if isStopped {
+		return stopped, nil
+	}
+	return nil, err
+}
+
 type Startable interface {
 	Start(
 		ctx context.Context,
@@ -184,8 +224,13 @@ dance:
 		return nil, err
 	}
 
+	if running.Service != nil && running.Service.supervised() {
+		running = supervise(svcCtx, id, svc, running)
+	}
+
 	ss.l.Lock()
 	delete(ss.starting, key)
+	delete(ss.stopped, key)
 	ss.running[key] = running
 	ss.bindings[key] = 1
 	ss.l.Unlock()
@@ -290,6 +335,17 @@ func (ss *Services) StopSessionServices(ctx context.Context, sessionID string) e
 	}
 	ss.l.Unlock()
 
+	defer func() {
+		// the session is over, so nothing can inspect its services anymore
+		ss.l.Lock()
+		for key := range ss.stopped {
+			if key.SessionID == sessionID {
+				delete(ss.stopped, key)
+			}
+		}
+		ss.l.Unlock()
+	}()
+
 	eg := new(errgroup.Group)
 	for _, svc := range svcs {
 		eg.Go(func() error {
@@ -345,10 +401,7 @@ func (ss *Services) stop(ctx context.Context, running *RunningService, force boo
 		return fmt.Errorf("stop: %w", err)
 	}
 
-	ss.l.Lock()
-	delete(ss.bindings, running.Key)
-	delete(ss.running, running.Key)
-	ss.l.Unlock()
+	ss.untrack(running)
 
 	return nil
 }
@@ -366,9 +419,18 @@ func (ss *Services) stopGraceful(ctx context.Context, running *RunningService, t
 		return err
 	}
 
+	ss.untrack(running)
+	return nil
+}
+
+// untrack forgets a service that has been stopped, keeping it around for
+// Inspect if it runs a process.
+func (ss *Services) untrack(running *RunningService) {
 	ss.l.Lock()
 	delete(ss.bindings, running.Key)
 	delete(ss.running, running.Key)
+	if running.Logs != nil {
+		ss.stopped[running.Key] = running
+	}
 	ss.l.Unlock()
-	return nil
 }
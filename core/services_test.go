@@ -274,6 +274,64 @@ func TestServicesStartConcurrentSadThenHappy(t *testing.T) {
 	require.Equal(t, 2, stub.Starts())
 }
 
+func TestServicesInspectStopped(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	ctx = engine.ContextWithClientMetadata(ctx, &engine.ClientMetadata{
+		ClientID:  "fake-client",
+		SessionID: "fake-session",
+	})
+
+	services := core.NewServices()
+
+	stub := newStartable("fake")
+
+	logs := core.NewServiceLogs()
+	expected := &core.RunningService{
+		Key: core.ServiceKey{
+			Digest:    stub.ID().Digest(),
+			SessionID: "fake-session",
+		},
+		Host: "fake-host",
+		Stop: func(context.Context, bool) error {
+			logs.Exit(nil)
+			return nil
+		},
+		Logs: func() *core.ServiceLogs {
+			return logs
+		},
+	}
+	stub.startResults <- startResult{
+		Started: expected,
+	}
+
+	_, err := services.Start(ctx, stub.ID(), stub)
+	require.NoError(t, err)
+
+	running, err := services.Inspect(ctx, stub.ID())
+	require.NoError(t, err)
+	require.Equal(t, expected, running)
+
+	require.NoError(t, services.Stop(ctx, stub.ID(), false))
+
+	_, err = services.Get(ctx, stub.ID())
+	require.Error(t, err)
+
+	// still inspectable once stopped
+	running, err = services.Inspect(ctx, stub.ID())
+	require.NoError(t, err)
+	require.Equal(t, expected, running)
+	code, exited := running.Logs().ExitCode()
+	require.True(t, exited)
+	require.Equal(t, 0, code)
+
+	// until the session ends
+	require.NoError(t, services.StopSessionServices(ctx, "fake-session"))
+	_, err = services.Inspect(ctx, stub.ID())
+	require.Error(t, err)
+}
+
 type fakeStartable struct {
 	name   string
 	digest digest.Digest
@@ -1,9 +1,12 @@
 package dagql
 
 import (
+	"container/list"
 	"context"
 	"fmt"
+	"reflect"
 	"sync"
+	"time"
 
 	"github.com/opencontainers/go-digest"
 )
@@ -12,23 +15,67 @@ type CacheMap[K comparable, T any] interface {
 	GetOrInitialize(context.Context, K, func(context.Context) (T, error)) (T, bool, error)
 	Get(context.Context, K) (T, error)
 	Keys() []K
+	Stats() CacheStats
+}
+
+// CacheLimits bounds the memory used by a cache, by evicting results that can
+// be recomputed from their ID, least recently used first.
+type CacheLimits struct {
+	// MaxBytes is the approximate size of the results to keep. Zero means
+	// unlimited.
+	MaxBytes int64
+	// KeepDuration is how long to keep results that aren't used. Zero means
+	// forever.
+	KeepDuration time.Duration
+}
+
+// CacheStats reports the usage of a cache.
+type CacheStats struct {
+	// Hits is the number of results returned from the cache.
+	Hits int64
+	// Misses is the number of results initialized by the cache.
+	Misses int64
+	// Evictions is the number of results evicted to respect the limits.
+	Evictions int64
+	// Entries is the number of results in the cache.
+	Entries int
+	// Bytes is the approximate size of the results in the cache.
+	Bytes int64
 }
 
 type cacheMap[K comparable, T any] struct {
 	l     sync.Mutex
 	calls map[K]*cache[T]
+
+	limits CacheLimits
+	// sizeOf approximates the size of a result, if set
+	sizeOf func(T) int64
+	// lru holds the keys of evictable results, least recently used first
+	lru   list.List
+	stats CacheStats
 }
 
 type cache[T any] struct {
 	wg  sync.WaitGroup
 	val T
 	err error
+
+	size     int64
+	lastUsed time.Time
+	// elem is the element of the result in the LRU list, if it's evictable
+	elem *list.Element
 }
 
 // NewCache creates a new cache map suitable for assigning on a Server or
 // multiple Servers.
 func NewCache() Cache {
-	return newCacheMap[digest.Digest, Typed]()
+	return NewCacheWithLimits(CacheLimits{})
+}
+
+// NewCacheWithLimits creates a cache map like NewCache, which evicts results
+// to respect the given limits.
+func NewCacheWithLimits(limits CacheLimits) Cache {
+	return newTypedCacheMap(limits)
 }
 
 func NewCacheMap[K comparable, T any]() CacheMap[K, T] {
@@ -41,6 +88,13 @@ func newCacheMap[K comparable, T any]() *cacheMap[K, T] {
 	}
 }
 
+func newTypedCacheMap(limits CacheLimits) *cacheMap[digest.Digest, Typed] {
+	m := newCacheMap[digest.Digest, Typed]()
+	m.limits = limits
+	m.sizeOf = approxResultSize
+	return m
+}
+
 type cacheMapContextKey[K comparable, T any] struct {
 	key K
 	m   *cacheMap[K, T]
@@ -49,10 +103,16 @@ type cacheMapContextKey[K comparable, T any] struct {
 var ErrCacheMapRecursiveCall = fmt.Errorf("recursive call detected")
 
 func (m *cacheMap[K, T]) Set(key K, val T) {
-	m.l.Lock()
-	m.calls[key] = &cache[T]{
+	c := &cache[T]{
 		val: val,
 	}
+	m.l.Lock()
+	if prev, ok := m.calls[key]; ok {
+		m.removeLocked(key, prev)
+	}
+	m.calls[key] = c
+	m.trackLocked(key, c, false)
+	m.evictLocked()
 	m.l.Unlock()
 }
 
@@ -61,6 +121,18 @@ func (m *cacheMap[K, T]) GetOrInitialize(ctx context.Context, key K, fn func(ctx
 }
 
 func (m *cacheMap[K, T]) GetOrInitializeOnHit(ctx context.Context, key K, fn func(ctx context.Context) (T, error), onHit func(T, error)) (T, bool, error) {
+	return m.getOrInitialize(ctx, key, fn, onHit, false)
+}
+
+// GetOrInitializeEvictable is like GetOrInitialize, but allows the result to
+// be evicted to respect the limits of the cache, if it was initialized by
+// this call. Results must only be evictable if calling fn again yields an
+// equivalent result.
+func (m *cacheMap[K, T]) GetOrInitializeEvictable(ctx context.Context, key K, fn func(ctx context.Context) (T, error)) (T, bool, error) {
+	return m.getOrInitialize(ctx, key, fn, nil, true)
+}
+
+func (m *cacheMap[K, T]) getOrInitialize(ctx context.Context, key K, fn func(ctx context.Context) (T, error), onHit func(T, error), evictable bool) (T, bool, error) {
 	if v := ctx.Value(cacheMapContextKey[K, T]{key: key, m: m}); v != nil {
 		var zero T
 		return zero, false, ErrCacheMapRecursiveCall
@@ -68,6 +140,8 @@ func (m *cacheMap[K, T]) GetOrInitializeOnHit(ctx context.Context, key K, fn fun
 
 	m.l.Lock()
 	if c, ok := m.calls[key]; ok {
+		m.stats.Hits++
+		m.touchLocked(c)
 		m.l.Unlock()
 		c.wg.Wait()
 		if onHit != nil {
@@ -76,6 +150,7 @@ func (m *cacheMap[K, T]) GetOrInitializeOnHit(ctx context.Context, key K, fn fun
 		return c.val, true, c.err
 	}
 
+	m.stats.Misses++
 	c := &cache[T]{}
 	c.wg.Add(1)
 	m.calls[key] = c
@@ -85,11 +160,16 @@ func (m *cacheMap[K, T]) GetOrInitializeOnHit(ctx context.Context, key K, fn fun
 	c.val, c.err = fn(ctx)
 	c.wg.Done()
 
-	if c.err != nil {
-		m.l.Lock()
-		delete(m.calls, key)
-		m.l.Unlock()
+	m.l.Lock()
+	if m.calls[key] == c {
+		if c.err != nil {
+			delete(m.calls, key)
+		} else {
+			m.trackLocked(key, c, evictable)
+			m.evictLocked()
+		}
 	}
+	m.l.Unlock()
 
 	return c.val, false, c.err
 }
@@ -102,6 +182,7 @@ func (m *cacheMap[K, T]) Get(ctx context.Context, key K) (T, error) {
 
 	m.l.Lock()
 	if c, ok := m.calls[key]; ok {
+		m.touchLocked(c)
 		m.l.Unlock()
 		c.wg.Wait()
 		return c.val, c.err
@@ -121,3 +202,136 @@ func (m *cacheMap[K, T]) Keys() []K {
 	m.l.Unlock()
 	return keys
 }
+
+// Stats returns the usage of the cache, evicting results that expired since
+// it was last used.
+func (m *cacheMap[K, T]) Stats() CacheStats {
+	m.l.Lock()
+	defer m.l.Unlock()
+	m.evictLocked()
+	stats := m.stats
+	stats.Entries = len(m.calls)
+	return stats
+}
+
+// trackLocked accounts for an initialized result.
+func (m *cacheMap[K, T]) trackLocked(key K, c *cache[T], evictable bool) {
+	if m.sizeOf != nil {
+		c.size = m.sizeOf(c.val)
+		m.stats.Bytes += c.size
+	}
+	c.lastUsed = time.Now()
+	if evictable {
+		c.elem = m.lru.PushBack(key)
+	}
+}
+
+// touchLocked marks a result as recently used.
+func (m *cacheMap[K, T]) touchLocked(c *cache[T]) {
+	c.lastUsed = time.Now()
+	if c.elem != nil {
+		m.lru.MoveToBack(c.elem)
+	}
+}
+
+func (m *cacheMap[K, T]) removeLocked(key K, c *cache[T]) {
+	delete(m.calls, key)
+	m.stats.Bytes -= c.size
+	if c.elem != nil {
+		m.lru.Remove(c.elem)
+		c.elem = nil
+	}
+}
+
+// evictLocked evicts the least recently used evictable results, while the
+// cache exceeds its size or they exceed their keep duration.
+func (m *cacheMap[K, T]) evictLocked() {
+	now := time.Now()
+	for elem := m.lru.Front(); elem != nil; elem = m.lru.Front() {
+		key := elem.Value.(K)
+		c := m.calls[key]
+		oversized := m.limits.MaxBytes > 0 && m.stats.Bytes > m.limits.MaxBytes
+		expired := m.limits.KeepDuration > 0 && now.Sub(c.lastUsed) > m.limits.KeepDuration
+		if !oversized && !expired {
+			return
+		}
+		m.removeLocked(key, c)
+		m.stats.Evictions++
+	}
+}
+
+// cacheEntryOverhead approximates the memory used by a cache entry besides its
+// result, i.e. its key, map slot and bookkeeping.
+const cacheEntryOverhead = 256
+
+// maxSizeDepth is how many pointers approxSize follows. Results mostly point
+// to objects shared with other results, e.g. their receivers, which shouldn't
+// be counted again.
+const maxSizeDepth = 3
+
+func approxResultSize(val Typed) int64 {
+	if val == nil {
+		return cacheEntryOverhead
+	}
+	return cacheEntryOverhead + approxSize(reflect.ValueOf(val), maxSizeDepth, map[uintptr]struct{}{})
+}
+
+// approxSize approximates the memory used by a value, counting the contents of
+// its strings and slices, the entries of its maps, and the values it points to
+// up to a depth.
+func approxSize(val reflect.Value, depth int, seen map[uintptr]struct{}) int64 {
+	size := int64(val.Type().Size())
+	return size + approxIndirectSize(val, depth, seen)
+}
+
+// approxIndirectSize approximates the memory used by a value outside of its
+// own memory.
+func approxIndirectSize(val reflect.Value, depth int, seen map[uintptr]struct{}) int64 {
+	switch val.Kind() {
+	case reflect.String:
+		return int64(val.Len())
+	case reflect.Slice:
+		if val.IsNil() {
+			return 0
+		}
+		size := int64(val.Cap()) * int64(val.Type().Elem().Size())
+		for i := 0; i < val.Len(); i++ {
+			size += approxIndirectSize(val.Index(i), depth, seen)
+		}
+		return size
+	case reflect.Array:
+		var size int64
+		for i := 0; i < val.Len(); i++ {
+			size += approxIndirectSize(val.Index(i), depth, seen)
+		}
+		return size
+	case reflect.Struct:
+		var size int64
+		for i := 0; i < val.NumField(); i++ {
+			size += approxIndirectSize(val.Field(i), depth, seen)
+		}
+		return size
+	case reflect.Map:
+		// maps aren't iterated, since they may be written concurrently
+		if val.IsNil() {
+			return 0
+		}
+		return int64(val.Len()) * int64(val.Type().Key().Size()+val.Type().Elem().Size())
+	case reflect.Pointer:
+		if val.IsNil() || depth == 0 {
+			return 0
+		}
+		if _, ok := seen[val.Pointer()]; ok {
+			return 0
+		}
+		seen[val.Pointer()] = struct{}{}
+		return approxSize(val.Elem(), depth-1, seen)
+	case reflect.Interface:
+		if val.IsNil() || depth == 0 {
+			return 0
+		}
+		return approxSize(val.Elem(), depth-1, seen)
+	default:
+		return 0
+	}
+}
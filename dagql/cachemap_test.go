@@ -2,8 +2,10 @@ package dagql
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"gotest.tools/v3/assert"
@@ -109,3 +111,101 @@ func TestCacheMapRecursiveCall(t *testing.T) {
 	assert.Equal(t, 101, v)
 	assert.Assert(t, !cached)
 }
+
+func TestCacheMapEvictsLeastRecentlyUsed(t *testing.T) {
+	t.Parallel()
+	c := newCacheMap[int, int]()
+	c.limits = CacheLimits{MaxBytes: 30}
+	c.sizeOf = func(int) int64 { return 10 }
+	ctx := context.Background()
+
+	initialized := 0
+	get := func(key int, evictable bool) bool {
+		fn := func(context.Context) (int, error) {
+			initialized++
+			return key, nil
+		}
+		var cached bool
+		var err error
+		if evictable {
+			_, cached, err = c.GetOrInitializeEvictable(ctx, key, fn)
+		} else {
+			_, cached, err = c.GetOrInitialize(ctx, key, fn)
+		}
+		assert.NilError(t, err)
+		return cached
+	}
+
+	// pinned results are never evicted, but count towards the size
+	assert.Assert(t, !get(0, false))
+	assert.Assert(t, !get(1, true))
+	assert.Assert(t, !get(2, true))
+	assert.Assert(t, get(1, true))
+
+	// 2 is the least recently used
+	assert.Assert(t, !get(3, true))
+	assert.Assert(t, get(0, true))
+	assert.Assert(t, get(1, true))
+	assert.Assert(t, get(3, true))
+	assert.Assert(t, !get(2, true))
+	assert.Equal(t, initialized, 5)
+
+	assert.DeepEqual(t, c.Stats(), CacheStats{
+		Hits:      4,
+		Misses:    5,
+		Evictions: 2,
+		Entries:   3,
+		Bytes:     30,
+	})
+}
+
+func TestCacheMapEvictsExpired(t *testing.T) {
+	t.Parallel()
+	c := newCacheMap[int, int]()
+	c.limits = CacheLimits{KeepDuration: time.Millisecond}
+	ctx := context.Background()
+
+	_, _, err := c.GetOrInitialize(ctx, 1, func(context.Context) (int, error) {
+		return 1, nil
+	})
+	assert.NilError(t, err)
+	_, _, err = c.GetOrInitializeEvictable(ctx, 2, func(context.Context) (int, error) {
+		return 2, nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, c.Stats().Entries, 2)
+
+	time.Sleep(10 * time.Millisecond)
+	stats := c.Stats()
+	assert.Equal(t, stats.Entries, 1)
+	assert.Equal(t, stats.Evictions, int64(1))
+	assert.DeepEqual(t, c.Keys(), []int{1})
+}
+
+func TestCacheMapSizes(t *testing.T) {
+	t.Parallel()
+	c := newTypedCacheMap(CacheLimits{})
+	ctx := context.Background()
+
+	_, _, err := c.GetOrInitialize(ctx, "small", func(context.Context) (Typed, error) {
+		return NewString("hi"), nil
+	})
+	assert.NilError(t, err)
+	small := c.Stats().Bytes
+
+	_, _, err = c.GetOrInitialize(ctx, "big", func(context.Context) (Typed, error) {
+		return NewString(strings.Repeat("x", 1<<20)), nil
+	})
+	assert.NilError(t, err)
+	big := c.Stats().Bytes - small
+	assert.Assert(t, big > 1<<20, "size: %d", big)
+	assert.Assert(t, big < 1<<20+1024, "size: %d", big)
+
+	// failed results aren't accounted for
+	_, _, err = c.GetOrInitialize(ctx, "err", func(context.Context) (Typed, error) {
+		return nil, errors.New("nope")
+	})
+	assert.ErrorContains(t, err, "nope")
+	assert.Equal(t, c.Stats().Bytes, small+big)
+	assert.Equal(t, c.Stats().Entries, 2)
+}
@@ -0,0 +1,246 @@
+package call
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/opencontainers/go-digest"
+	"google.golang.org/protobuf/proto"
+)
+
+// DivergenceKind is what differs between two calls.
+type DivergenceKind string
+
+const (
+	// DivergenceReceiver is a call that has a receiver in only one of the IDs.
+	DivergenceReceiver DivergenceKind = "receiver"
+	// DivergenceCall is a call with a different field, view, module or
+	// selected element.
+	DivergenceCall DivergenceKind = "call"
+	// DivergenceArgument is a call with an argument that was added, removed
+	// or changed.
+	DivergenceArgument DivergenceKind = "argument"
+	// DivergenceContent is a call whose field and arguments are the same,
+	// but whose digest differs, which is set from the content of its result,
+	// e.g. the files of a host directory.
+	DivergenceContent DivergenceKind = "content"
+)
+
+// Divergence is the first difference between two IDs, in the order their
+// calls are evaluated: receivers first, then arguments.
+type Divergence struct {
+	Kind DivergenceKind
+
+	// The diverging call in the previous and current IDs, which are both set
+	// unless the call was added or removed.
+	Previous *ID
+	Current  *ID
+
+	// The name of the diverging argument, for DivergenceArgument.
+	Argument string
+
+	// The previous and current displayed values that differ, empty if
+	// missing.
+	PreviousValue string
+	CurrentValue  string
+}
+
+// Diverge returns the first difference between a previous and a current ID,
+// or nil if they are the same.
+func Diverge(prev, cur *ID) *Divergence {
+	if prev.Digest() == cur.Digest() {
+		return nil
+	}
+
+	if prev.Receiver().Digest() != cur.Receiver().Digest() {
+		if prev.Receiver() != nil && cur.Receiver() != nil {
+			return Diverge(prev.Receiver(), cur.Receiver())
+		}
+		return &Divergence{
+			Kind:          DivergenceReceiver,
+			Previous:      prev,
+			Current:       cur,
+			PreviousValue: receiverDisplay(prev),
+			CurrentValue:  receiverDisplay(cur),
+		}
+	}
+
+	if prev.Field() != cur.Field() ||
+		prev.View() != cur.View() ||
+		prev.Nth() != cur.Nth() ||
+		moduleDigest(prev) != moduleDigest(cur) {
+		return &Divergence{
+			Kind:          DivergenceCall,
+			Previous:      prev,
+			Current:       cur,
+			PreviousValue: prev.DisplaySelf(),
+			CurrentValue:  cur.DisplaySelf(),
+		}
+	}
+
+	if d := divergeArgs(prev, cur); d != nil {
+		return d
+	}
+
+	return &Divergence{
+		Kind:          DivergenceContent,
+		Previous:      prev,
+		Current:       cur,
+		PreviousValue: prev.Digest().String(),
+		CurrentValue:  cur.Digest().String(),
+	}
+}
+
+// CallDisplay displays the diverging call with the field of its receiver,
+// e.g. `host.directory(path: "./src")`.
+func (d *Divergence) CallDisplay() string {
+	if d.Current == nil {
+		return callDisplay(d.Previous)
+	}
+	return callDisplay(d.Current)
+}
+
+// String describes the divergence, e.g. `host.directory(path: "./src"):
+// content changed`.
+func (d *Divergence) String() string {
+	call := d.CallDisplay()
+	switch d.Kind {
+	case DivergenceReceiver:
+		return fmt.Sprintf("%s: receiver changed from %s to %s", call, orNone(d.PreviousValue), orNone(d.CurrentValue))
+	case DivergenceCall:
+		return fmt.Sprintf("%s: call changed from %s", call, callDisplay(d.Previous))
+	case DivergenceArgument:
+		switch {
+		case d.PreviousValue == "":
+			return fmt.Sprintf("%s: argument %s added: %s", call, d.Argument, d.CurrentValue)
+		case d.CurrentValue == "":
+			return fmt.Sprintf("%s: argument %s removed: %s", call, d.Argument, d.PreviousValue)
+		default:
+			return fmt.Sprintf("%s: argument %s changed from %s to %s", call, d.Argument, d.PreviousValue, d.CurrentValue)
+		}
+	case DivergenceContent:
+		return fmt.Sprintf("%s: content changed (digest %s -> %s)", call, d.PreviousValue, d.CurrentValue)
+	default:
+		return fmt.Sprintf("%s: %s changed", call, d.Kind)
+	}
+}
+
+// divergeArgs returns the first difference between the arguments of two
+// calls, which are both sorted by name.
+func divergeArgs(prev, cur *ID) *Divergence {
+	prevArgs := map[string]*Argument{}
+	curArgs := map[string]*Argument{}
+	var names []string
+	for _, arg := range prev.Args() {
+		if arg.isSensitive {
+			continue
+		}
+		prevArgs[arg.Name()] = arg
+		names = append(names, arg.Name())
+	}
+	for _, arg := range cur.Args() {
+		if arg.isSensitive {
+			continue
+		}
+		if _, ok := prevArgs[arg.Name()]; !ok {
+			names = append(names, arg.Name())
+		}
+		curArgs[arg.Name()] = arg
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prevArg, curArg := prevArgs[name], curArgs[name]
+		d := &Divergence{
+			Kind:     DivergenceArgument,
+			Previous: prev,
+			Current:  cur,
+			Argument: name,
+		}
+		switch {
+		case prevArg == nil:
+			d.CurrentValue = curArg.Value().Display()
+			return d
+		case curArg == nil:
+			d.PreviousValue = prevArg.Value().Display()
+			return d
+		case proto.Equal(prevArg.pb, curArg.pb):
+			continue
+		}
+		if d := divergeLiterals(prevArg.Value(), curArg.Value()); d != nil {
+			return d
+		}
+		d.PreviousValue = prevArg.Value().Display()
+		d.CurrentValue = curArg.Value().Display()
+		return d
+	}
+	return nil
+}
+
+// divergeLiterals returns the first difference between the IDs of two
+// literals of the same shape, or nil if their difference isn't in IDs.
+func divergeLiterals(prev, cur Literal) *Divergence {
+	switch prev := prev.(type) {
+	case *LiteralID:
+		if cur, ok := cur.(*LiteralID); ok {
+			return Diverge(prev.Value(), cur.Value())
+		}
+	case *LiteralList:
+		cur, ok := cur.(*LiteralList)
+		if !ok || prev.Len() != cur.Len() {
+			return nil
+		}
+		for i, prevVal := range prev.values {
+			if proto.Equal(prevVal.pb(), cur.values[i].pb()) {
+				continue
+			}
+			return divergeLiterals(prevVal, cur.values[i])
+		}
+	case *LiteralObject:
+		cur, ok := cur.(*LiteralObject)
+		if !ok || prev.Len() != cur.Len() {
+			return nil
+		}
+		for i, prevField := range prev.values {
+			curField := cur.values[i]
+			if prevField.Name() != curField.Name() {
+				return nil
+			}
+			if proto.Equal(prevField.pb, curField.pb) {
+				continue
+			}
+			return divergeLiterals(prevField.Value(), curField.Value())
+		}
+	}
+	return nil
+}
+
+// callDisplay displays a call with the field of its receiver, e.g.
+// `host.directory(path: "./src")`.
+func callDisplay(id *ID) string {
+	if id.Receiver() == nil {
+		return id.DisplaySelf()
+	}
+	return id.Receiver().Field() + "." + id.DisplaySelf()
+}
+
+func moduleDigest(id *ID) digest.Digest {
+	if id.Module() == nil {
+		return ""
+	}
+	return id.Module().ID().Digest()
+}
+
+func receiverDisplay(id *ID) string {
+	if id.Receiver() == nil {
+		return ""
+	}
+	return id.Receiver().DisplaySelf()
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
@@ -0,0 +1,111 @@
+package call
+
+import (
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func testCall(recv *ID, typ, field string, customDigest digest.Digest, args ...*Argument) *ID {
+	return recv.Append(ast.NonNullNamedType(typ, nil), field, "", nil, false, 0, customDigest, args...)
+}
+
+func TestDiverge(t *testing.T) {
+	t.Parallel()
+
+	src := func(content digest.Digest) *ID {
+		host := testCall(nil, "Host", "host", "")
+		return testCall(host, "Directory", "directory", content,
+			NewArgument("path", NewLiteralString("./src"), false))
+	}
+	build := func(src *ID, args ...string) *ID {
+		ctr := testCall(nil, "Container", "container", "")
+		ctr = testCall(ctr, "Container", "from", "",
+			NewArgument("address", NewLiteralString("golang"), false))
+		ctr = testCall(ctr, "Container", "withDirectory", "",
+			NewArgument("directory", NewLiteralID(src), false),
+			NewArgument("path", NewLiteralString("/src"), false))
+		lits := make([]Literal, 0, len(args))
+		for _, arg := range args {
+			lits = append(lits, NewLiteralString(arg))
+		}
+		return testCall(ctr, "Container", "withExec",
+			"", NewArgument("args", NewLiteralList(lits...), false))
+	}
+
+	t.Run("same", func(t *testing.T) {
+		require.Nil(t, Diverge(build(src("sha256:a"), "go", "build"), build(src("sha256:a"), "go", "build")))
+	})
+
+	t.Run("content", func(t *testing.T) {
+		d := Diverge(build(src("sha256:a"), "go", "build"), build(src("sha256:b"), "go", "build"))
+		require.NotNil(t, d)
+		require.Equal(t, DivergenceContent, d.Kind)
+		require.Equal(t, "directory", d.Current.Field())
+		require.Equal(t, "sha256:a", d.PreviousValue)
+		require.Equal(t, "sha256:b", d.CurrentValue)
+		require.Equal(t, `host.directory(path: "./src"): content changed (digest sha256:a -> sha256:b)`, d.String())
+	})
+
+	t.Run("argument", func(t *testing.T) {
+		d := Diverge(build(src("sha256:a"), "go", "build"), build(src("sha256:a"), "go", "test"))
+		require.NotNil(t, d)
+		require.Equal(t, DivergenceArgument, d.Kind)
+		require.Equal(t, "withExec", d.Current.Field())
+		require.Equal(t, "args", d.Argument)
+		require.Equal(t, `["go","build"]`, d.PreviousValue)
+		require.Equal(t, `["go","test"]`, d.CurrentValue)
+		require.Contains(t, d.String(), `: argument args changed from ["go","build"] to ["go","test"]`)
+	})
+
+	t.Run("receivers first", func(t *testing.T) {
+		// both the source and the args changed, but the source is evaluated
+		// first
+		d := Diverge(build(src("sha256:a"), "go", "build"), build(src("sha256:b"), "go", "test"))
+		require.NotNil(t, d)
+		require.Equal(t, DivergenceContent, d.Kind)
+	})
+
+	t.Run("argument added", func(t *testing.T) {
+		prev := testCall(nil, "Container", "container", "")
+		cur := testCall(nil, "Container", "container", "",
+			NewArgument("platform", NewLiteralString("linux/arm64"), false))
+		d := Diverge(prev, cur)
+		require.NotNil(t, d)
+		require.Equal(t, DivergenceArgument, d.Kind)
+		require.Equal(t, "platform", d.Argument)
+		require.Empty(t, d.PreviousValue)
+		require.Equal(t, `container(platform: "linux/arm64"): argument platform added: "linux/arm64"`, d.String())
+	})
+
+	t.Run("sensitive arguments", func(t *testing.T) {
+		prev := testCall(nil, "Secret", "setSecret", "",
+			NewArgument("plaintext", NewLiteralString("hunter2"), true))
+		cur := testCall(nil, "Secret", "setSecret", "",
+			NewArgument("plaintext", NewLiteralString("hunter3"), true))
+		require.Nil(t, Diverge(prev, cur))
+	})
+
+	t.Run("call", func(t *testing.T) {
+		ctr := testCall(nil, "Container", "container", "")
+		prev := testCall(ctr, "Container", "withWorkdir", "",
+			NewArgument("path", NewLiteralString("/src"), false))
+		cur := testCall(ctr, "Container", "withUser", "",
+			NewArgument("name", NewLiteralString("nobody"), false))
+		d := Diverge(prev, cur)
+		require.NotNil(t, d)
+		require.Equal(t, DivergenceCall, d.Kind)
+		require.Equal(t, `container.withUser(name: "nobody"): call changed from container.withWorkdir(path: "/src")`, d.String())
+	})
+
+	t.Run("receiver", func(t *testing.T) {
+		prev := testCall(nil, "Directory", "directory", "")
+		cur := testCall(testCall(nil, "Host", "host", ""), "Directory", "directory", "")
+		d := Diverge(prev, cur)
+		require.NotNil(t, d)
+		require.Equal(t, DivergenceReceiver, d.Kind)
+		require.Equal(t, "host.directory: receiver changed from none to host", d.String())
+	})
+}
@@ -365,6 +365,12 @@ func (id *ID) gatherCalls(callsByDigest map[string]*callpbv1.Call) {
 	}
 }
 
+// FromProto decodes the ID from the calls of a DAG, e.g. calls gathered from
+// telemetry. It fails if a call the root refers to is missing.
+func (id *ID) FromProto(dagPB *callpbv1.DAG) error {
+	return id.decode(dagPB.RootDigest, dagPB.CallsByDigest, map[string]*ID{})
+}
+
 func (id *ID) FromAnyPB(data *anypb.Any) error {
 	var dagPB callpbv1.DAG
 	if err := data.UnmarshalTo(&dagPB); err != nil {
@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 
@@ -2257,3 +2258,177 @@ func TestCustomDigest(t *testing.T) {
 		assert.Equal(t, s1ID, res.ReturnTheArg.ID)
 	}
 }
+
+type memResultStore struct {
+	mu      sync.Mutex
+	results map[digest.Digest][]byte
+}
+
+func (store *memResultStore) Load(_ context.Context, key digest.Digest) ([]byte, bool, error) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	val, ok := store.results[key]
+	return val, ok, nil
+}
+
+func (store *memResultStore) Store(_ context.Context, key digest.Digest, value []byte) error {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.results[key] = value
+	return nil
+}
+
+func TestPersistentCache(t *testing.T) {
+	store := &memResultStore{results: map[digest.Digest][]byte{}}
+
+	var mu sync.Mutex
+	called := map[string]int{}
+	call := func(field string) {
+		mu.Lock()
+		called[field]++
+		mu.Unlock()
+	}
+	newServer := func() *client.Client {
+		srv := dagql.NewServer(Query{})
+		srv.Cache = dagql.NewPersistentCache(store, dagql.CacheLimits{})
+		points.Install[Query](srv)
+		dagql.Fields[Query]{
+			dagql.Func("greet", func(ctx context.Context, self Query, args struct {
+				Name string
+			}) (dagql.String, error) {
+				call("greet")
+				return dagql.NewString("hello, " + args.Name), nil
+			}).Persistent(),
+			dagql.Func("greetings", func(ctx context.Context, self Query, args struct {
+				Names []string
+			}) (dagql.Array[dagql.String], error) {
+				call("greetings")
+				var res dagql.Array[dagql.String]
+				for _, name := range args.Names {
+					res = append(res, dagql.NewString("hello, "+name))
+				}
+				return res, nil
+			}).Persistent(),
+			dagql.Func("echo", func(ctx context.Context, self Query, args struct {
+				Val string
+			}) (dagql.String, error) {
+				call("echo")
+				return dagql.NewString(args.Val), nil
+			}),
+			dagql.Func("now", func(ctx context.Context, self Query, _ struct{}) (dagql.String, error) {
+				call("now")
+				return dagql.NewString(time.Now().String()), nil
+			}).Persistent().Impure("Returns the current time."),
+			dagql.Func("origin", func(ctx context.Context, self Query, _ struct{}) (*points.Point, error) {
+				call("origin")
+				return &points.Point{}, nil
+			}).Persistent(),
+		}.Install(srv)
+		dagql.Fields[*points.Point]{
+			dagql.Func("describe", func(ctx context.Context, self *points.Point, _ struct{}) (dagql.String, error) {
+				call("describe")
+				return dagql.NewString(fmt.Sprintf("(%d, %d)", self.X, self.Y)), nil
+			}).Persistent(),
+		}.Install(srv)
+		return client.New(dagql.NewDefaultHandler(srv))
+	}
+
+	var res struct {
+		Greet     string
+		Greetings []string
+		Echo      string
+		Now       string
+		Origin    struct{ Describe string }
+		Point     struct{ Describe string }
+	}
+	query := `query {
+		greet(name: "world")
+		greetings(names: ["a", "b"])
+		echo(val: "hi")
+		now
+		origin { describe }
+		point(x: 1, y: 2) { describe }
+	}`
+
+	req(t, newServer(), query, &res)
+	assert.Equal(t, res.Greet, "hello, world")
+	assert.DeepEqual(t, res.Greetings, []string{"hello, a", "hello, b"})
+	assert.Equal(t, res.Origin.Describe, "(0, 0)")
+	assert.Equal(t, res.Point.Describe, "(1, 2)")
+	assert.DeepEqual(t, called, map[string]int{
+		"greet":     1,
+		"greetings": 1,
+		"echo":      1,
+		"now":       1,
+		"origin":    1,
+		"describe":  2,
+	})
+
+	// only the scalar results of persistent chains are stored: greet,
+	// greetings and origin.describe, but not the origin object itself, nor
+	// point.describe since point is not persistent
+	assert.Equal(t, len(store.results), 3)
+
+	req(t, newServer(), query, &res)
+	assert.Equal(t, res.Greet, "hello, world")
+	assert.DeepEqual(t, res.Greetings, []string{"hello, a", "hello, b"})
+	assert.Equal(t, res.Origin.Describe, "(0, 0)")
+	assert.Equal(t, res.Point.Describe, "(1, 2)")
+	assert.DeepEqual(t, called, map[string]int{
+		"greet":     1,
+		"greetings": 1,
+		"echo":      2,
+		"now":       2,
+		"origin":    2,
+		"describe":  3,
+	})
+}
+
+func TestCacheEviction(t *testing.T) {
+	srv := dagql.NewServer(Query{})
+	// every result exceeds the limit, so only pinned results are kept
+	srv.Cache = dagql.NewCacheWithLimits(dagql.CacheLimits{MaxBytes: 1})
+
+	gql := client.New(dagql.NewDefaultHandler(srv))
+
+	var mu sync.Mutex
+	called := map[string]int{}
+	call := func(field string) {
+		mu.Lock()
+		called[field]++
+		mu.Unlock()
+	}
+	dagql.Fields[Query]{
+		dagql.Func("pure", func(ctx context.Context, self Query, _ struct{}) (dagql.String, error) {
+			call("pure")
+			return "pure", nil
+		}),
+		dagql.FuncWithCacheKey("keyed", func(ctx context.Context, self Query, _ struct{}) (dagql.String, error) {
+			call("keyed")
+			return "keyed", nil
+		}, func(ctx context.Context, _ dagql.Instance[Query], _ struct{}, origDgst digest.Digest) (digest.Digest, error) {
+			return digest.FromString("keyed"), nil
+		}),
+	}.Install(srv)
+
+	var res struct {
+		Pure  string
+		Keyed string
+	}
+	for range 2 {
+		req(t, gql, `query { pure, keyed }`, &res)
+		assert.Equal(t, res.Pure, "pure")
+		assert.Equal(t, res.Keyed, "keyed")
+	}
+
+	// results with a custom cache key can't be recomputed from their ID, so
+	// they're never evicted
+	assert.DeepEqual(t, called, map[string]int{
+		"pure":  2,
+		"keyed": 1,
+	})
+
+	stats := srv.Cache.(dagql.CacheMap[digest.Digest, dagql.Typed]).Stats()
+	assert.Equal(t, stats.Evictions, int64(2))
+	assert.Equal(t, stats.Entries, 1)
+}
@@ -56,6 +56,10 @@ type DB struct {
 	// UpdatedSnapshots so that we can know whether we need to send them when we
 	// finally see them
 	seenSpans map[SpanID]struct{}
+
+	// spanLogs holds the logs of each span for reports, once enabled by
+	// CaptureLogs
+	spanLogs map[SpanID][]byte
 }
 
 func NewDB() *DB {
@@ -226,6 +230,9 @@ func (db DBLogExporter) Export(ctx context.Context, logs []sdklog.Record) error
 			// buffer raw logs so we can replay them later
 			db.PrimaryLogs[spanID] = append(db.PrimaryLogs[spanID], log)
 		}
+		if db.spanLogs != nil {
+			db.captureLog(spanID, log.Body().AsString())
+		}
 	}
 	return nil
 }
@@ -39,6 +39,9 @@ type FrontendOpts struct {
 	// DotShowInternal indicates whether to include internal steps in the DOT output
 	DotShowInternal bool
 
+	// Reports are written after execution, e.g. as JUnit XML for CI systems.
+	Reports []Report
+
 	// ZoomedSpan configures a span to be zoomed in on, revealing
 	// its child spans.
 	ZoomedSpan SpanID
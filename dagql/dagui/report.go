@@ -0,0 +1,321 @@
+package dagui
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ReportFormat is a machine-readable format for the results of a run, e.g.
+// for CI systems to render them.
+type ReportFormat string
+
+const (
+	// ReportJUnit reports the results as JUnit XML test cases.
+	ReportJUnit ReportFormat = "junit"
+	// ReportJSONL reports the results as JSON lines, one per case.
+	ReportJSONL ReportFormat = "jsonl"
+)
+
+var ReportFormats = []ReportFormat{ReportJUnit, ReportJSONL}
+
+// Report is a report of the results of a run to write after it completes.
+type Report struct {
+	Format ReportFormat
+	Path   string
+}
+
+// ParseReport parses a report in the form "format=path", e.g.
+// "junit=report.xml".
+func ParseReport(spec string) (Report, error) {
+	format, path, ok := strings.Cut(spec, "=")
+	if !ok || path == "" {
+		return Report{}, fmt.Errorf("invalid report %q: must be in the form format=path", spec)
+	}
+	for _, known := range ReportFormats {
+		if ReportFormat(format) == known {
+			return Report{Format: known, Path: path}, nil
+		}
+	}
+	return Report{}, fmt.Errorf("invalid report %q: unknown format %q", spec, format)
+}
+
+// ReportStatus is the outcome of a reported case.
+type ReportStatus string
+
+const (
+	ReportPassed   ReportStatus = "passed"
+	ReportFailed   ReportStatus = "failed"
+	ReportCanceled ReportStatus = "canceled"
+)
+
+// ReportCase is the result of a top-level span of a run, e.g. a function
+// called by "dagger call".
+type ReportCase struct {
+	Name       string       `json:"name"`
+	SpanID     string       `json:"spanId"`
+	CallDigest string       `json:"callDigest,omitempty"`
+	StartTime  time.Time    `json:"startTime"`
+	Duration   float64      `json:"durationSeconds"`
+	Status     ReportStatus `json:"status"`
+	Cached     bool         `json:"cached"`
+	Failure    string       `json:"failure,omitempty"`
+	Logs       string       `json:"logs,omitempty"`
+}
+
+// maxSpanLogBytes is how much of the end of each span's logs is kept for
+// reports.
+const maxSpanLogBytes = 256 * 1024
+
+// CaptureLogs records the logs of every span from now on, to include them in
+// reports.
+func (db *DB) CaptureLogs() {
+	if db.spanLogs == nil {
+		db.spanLogs = make(map[SpanID][]byte)
+	}
+}
+
+func (db *DB) captureLog(spanID SpanID, body string) {
+	logs := append(db.spanLogs[spanID], body...)
+	if len(logs) > maxSpanLogBytes {
+		logs = logs[len(logs)-maxSpanLogBytes:]
+	}
+	db.spanLogs[spanID] = logs
+}
+
+// ReportCases returns a case for each top-level span beneath the primary
+// span, as shown by the frontends.
+func (db *DB) ReportCases() []*ReportCase {
+	rows := db.RowsView(FrontendOpts{
+		ZoomedSpan: db.PrimarySpan,
+		Verbosity:  ShowCompletedVerbosity,
+	})
+	var cases []*ReportCase
+	for _, tree := range rows.Body {
+		cases = append(cases, db.reportCase(tree.Span))
+	}
+	return cases
+}
+
+func (db *DB) reportCase(span *Span) *ReportCase {
+	c := &ReportCase{
+		Name:       span.Name,
+		SpanID:     span.ID.String(),
+		CallDigest: span.CallDigest,
+		StartTime:  span.StartTime,
+		Duration:   span.EndTimeOrNow().Sub(span.StartTime).Seconds(),
+		Status:     ReportPassed,
+		Cached:     span.IsCached(),
+		Logs:       db.reportLogs(span),
+	}
+	switch {
+	case span.IsFailedOrCausedFailure():
+		c.Status = ReportFailed
+		c.Failure = reportFailure(span)
+	case span.IsCanceled() || span.IsRunningOrEffectsRunning():
+		c.Status = ReportCanceled
+	}
+	return c
+}
+
+// reportFailure describes the errors that failed the span.
+func reportFailure(span *Span) string {
+	var msgs []string
+	for _, failed := range span.Errors().Order {
+		msg := failed.Status.Description
+		if msg == "" {
+			msg = "failed"
+		}
+		if failed != span {
+			msg = failed.Name + ": " + msg
+		}
+		msgs = append(msgs, msg)
+	}
+	if len(msgs) == 0 {
+		return "failed"
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// reportLogs returns the logs of the span and its children.
+func (db *DB) reportLogs(span *Span) string {
+	var logs strings.Builder
+	var collect func(*Span)
+	collect = func(span *Span) {
+		logs.Write(db.spanLogs[span.ID])
+		for _, child := range span.ChildSpans.Order {
+			collect(child)
+		}
+	}
+	collect(span)
+	return logs.String()
+}
+
+// WriteReports writes the reports of the run.
+func (db *DB) WriteReports(reports []Report) error {
+	if len(reports) == 0 {
+		return nil
+	}
+	cases := db.ReportCases()
+	var errs error
+	for _, report := range reports {
+		if err := db.writeReport(report, cases); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("write %s report to %s: %w", report.Format, report.Path, err))
+		}
+	}
+	return errs
+}
+
+func (db *DB) writeReport(report Report, cases []*ReportCase) (rerr error) {
+	f, err := os.Create(report.Path)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		rerr = errors.Join(rerr, f.Close())
+	}()
+	w := bufio.NewWriter(f)
+	switch report.Format {
+	case ReportJUnit:
+		err = writeJUnitReport(w, db.reportSuiteName(), cases)
+	case ReportJSONL:
+		err = writeJSONLReport(w, cases)
+	default:
+		err = fmt.Errorf("unknown format %q", report.Format)
+	}
+	if err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
+func (db *DB) reportSuiteName() string {
+	if primary, ok := db.Spans.Map[db.PrimarySpan]; ok && primary.Name != "" {
+		return primary.Name
+	}
+	return "dagger"
+}
+
+func writeJSONLReport(w io.Writer, cases []*ReportCase) error {
+	enc := json.NewEncoder(w)
+	for _, c := range cases {
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string           `xml:"name,attr"`
+	Classname  string           `xml:"classname,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	Failure    *junitMessage    `xml:"failure,omitempty"`
+	Skipped    *junitMessage    `xml:"skipped,omitempty"`
+	SystemOut  string           `xml:"system-out,omitempty"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+func writeJUnitReport(w io.Writer, suiteName string, cases []*ReportCase) error {
+	suite := junitTestSuite{
+		Name:  suiteName,
+		Tests: len(cases),
+	}
+	var start, end time.Time
+	for _, c := range cases {
+		tc := junitTestCase{
+			Name:      c.Name,
+			Classname: suiteName,
+			Time:      junitTime(c.Duration),
+			SystemOut: c.Logs,
+		}
+		if c.Cached {
+			tc.Properties = &junitProperties{
+				Properties: []junitProperty{{Name: "cached", Value: "true"}},
+			}
+		}
+		switch c.Status {
+		case ReportFailed:
+			msg, _, _ := strings.Cut(c.Failure, "\n")
+			tc.Failure = &junitMessage{Message: msg, Body: c.Failure}
+			suite.Failures++
+		case ReportCanceled:
+			tc.Skipped = &junitMessage{Message: "canceled"}
+			suite.Skipped++
+		}
+		suite.Cases = append(suite.Cases, tc)
+
+		if start.IsZero() || c.StartTime.Before(start) {
+			start = c.StartTime
+		}
+		if caseEnd := c.StartTime.Add(time.Duration(c.Duration * float64(time.Second))); caseEnd.After(end) {
+			end = caseEnd
+		}
+	}
+	suite.Time = junitTime(end.Sub(start).Seconds())
+	if !start.IsZero() {
+		suite.Timestamp = start.UTC().Format(time.RFC3339)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitTestSuites{
+		Name:     suiteName,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func junitTime(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
@@ -0,0 +1,108 @@
+package dagui
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+
+	"dagger.io/dagger/telemetry"
+)
+
+func TestParseReport(t *testing.T) {
+	report, err := ParseReport("junit=out/report.xml")
+	require.NoError(t, err)
+	require.Equal(t, Report{Format: ReportJUnit, Path: "out/report.xml"}, report)
+
+	report, err = ParseReport("jsonl=a=b.jsonl")
+	require.NoError(t, err)
+	require.Equal(t, Report{Format: ReportJSONL, Path: "a=b.jsonl"}, report)
+
+	_, err = ParseReport("junit")
+	require.ErrorContains(t, err, "must be in the form format=path")
+	_, err = ParseReport("tap=report.tap")
+	require.ErrorContains(t, err, `unknown format "tap"`)
+}
+
+func TestWriteReports(t *testing.T) {
+	ctx := context.Background()
+
+	db := NewDB()
+	db.CaptureLogs()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(db))
+	tracer := tp.Tracer("test")
+
+	logRecord := func(span sdktrace.ReadOnlySpan, body string) sdklog.Record {
+		var rec sdklog.Record
+		rec.SetTraceID(span.SpanContext().TraceID())
+		rec.SetSpanID(span.SpanContext().SpanID())
+		rec.SetBody(otellog.StringValue(body))
+		return rec
+	}
+
+	ctx, primary := tracer.Start(ctx, "dagger call")
+	db.SetPrimarySpan(SpanID{primary.SpanContext().SpanID()})
+
+	_, build := tracer.Start(ctx, "build", trace.WithAttributes(
+		attribute.Bool(telemetry.CachedAttr, true)))
+	build.End()
+
+	testCtx, test := tracer.Start(ctx, "test")
+	_, exec := tracer.Start(testCtx, "withExec")
+	exec.SetStatus(codes.Error, "exit code 1")
+	exec.End()
+	test.SetStatus(codes.Error, "test failed")
+	test.End()
+	primary.End()
+
+	require.NoError(t, db.LogExporter().Export(ctx, []sdklog.Record{
+		logRecord(build.(sdktrace.ReadOnlySpan), "building\n"),
+		logRecord(exec.(sdktrace.ReadOnlySpan), "FAIL: TestFoo\n"),
+	}))
+
+	dir := t.TempDir()
+	junitPath := filepath.Join(dir, "report.xml")
+	jsonlPath := filepath.Join(dir, "report.jsonl")
+	require.NoError(t, db.WriteReports([]Report{
+		{Format: ReportJUnit, Path: junitPath},
+		{Format: ReportJSONL, Path: jsonlPath},
+	}))
+
+	jsonl, err := os.ReadFile(jsonlPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(jsonl)), "\n")
+	require.Len(t, lines, 2)
+
+	var cases []ReportCase
+	for _, line := range lines {
+		var c ReportCase
+		require.NoError(t, json.Unmarshal([]byte(line), &c))
+		cases = append(cases, c)
+	}
+	require.Equal(t, "build", cases[0].Name)
+	require.Equal(t, ReportPassed, cases[0].Status)
+	require.True(t, cases[0].Cached)
+	require.Equal(t, "building\n", cases[0].Logs)
+
+	require.Equal(t, "test", cases[1].Name)
+	require.Equal(t, ReportFailed, cases[1].Status)
+	require.False(t, cases[1].Cached)
+	require.Equal(t, "test failed", cases[1].Failure)
+	require.Equal(t, "FAIL: TestFoo\n", cases[1].Logs)
+
+	junit, err := os.ReadFile(junitPath)
+	require.NoError(t, err)
+	require.Contains(t, string(junit), `<testsuite name="dagger call" tests="2" failures="1" skipped="0"`)
+	require.Contains(t, string(junit), `<property name="cached" value="true"></property>`)
+	require.Contains(t, string(junit), `<system-out>FAIL: TestFoo&#xA;</system-out>`)
+}
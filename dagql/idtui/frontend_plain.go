@@ -2,6 +2,7 @@ package idtui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"slices"
@@ -173,6 +174,11 @@ func (fe *frontendPlain) Run(ctx context.Context, opts dagui.FrontendOpts, run f
 		opts.TooFastThreshold = 100 * time.Millisecond
 	}
 	fe.FrontendOpts = opts
+	if len(opts.Reports) > 0 {
+		fe.mu.Lock()
+		fe.db.CaptureLogs()
+		fe.mu.Unlock()
+	}
 
 	if !fe.Silent {
 		go func() {
@@ -197,6 +203,10 @@ func (fe *frontendPlain) Run(ctx context.Context, opts dagui.FrontendOpts, run f
 
 	fe.db.WriteDot(opts.DotOutputFilePath, opts.DotFocusField, opts.DotShowInternal)
 
+	if err := fe.db.WriteReports(opts.Reports); err != nil {
+		return errors.Join(runErr, err)
+	}
+
 	return runErr
 }
 
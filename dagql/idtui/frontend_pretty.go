@@ -156,6 +156,11 @@ func (fe *frontendPretty) Run(ctx context.Context, opts dagui.FrontendOpts, run
 		opts.GCThreshold = 1 * time.Second
 	}
 	fe.FrontendOpts = opts
+	if len(opts.Reports) > 0 {
+		fe.mu.Lock()
+		fe.db.CaptureLogs()
+		fe.mu.Unlock()
+	}
 
 	if fe.reportOnly {
 		fe.err = run(ctx)
@@ -171,6 +176,10 @@ func (fe *frontendPretty) Run(ctx context.Context, opts dagui.FrontendOpts, run
 
 	fe.db.WriteDot(opts.DotOutputFilePath, opts.DotFocusField, opts.DotShowInternal)
 
+	if err := fe.db.WriteReports(opts.Reports); err != nil {
+		return errors.Join(fe.err, err)
+	}
+
 	// return original err
 	return fe.err
 }
@@ -87,6 +87,14 @@ func (class Class[T]) Field(name string, views ...string) (Field[T], bool) {
 	return class.fieldLocked(name, views...)
 }
 
+func (class Class[T]) FieldSpec(name string, views ...string) (FieldSpec, bool) {
+	field, ok := class.Field(name, views...)
+	if !ok {
+		return FieldSpec{}, false
+	}
+	return field.Spec, true
+}
+
 func (class Class[T]) fieldLocked(name string, views ...string) (Field[T], bool) {
 	fields, ok := class.fields[name]
 	if !ok {
@@ -390,7 +398,7 @@ func (r Instance[T]) Select(ctx context.Context, s *Server, sel Selector) (Typed
 		newID = newID.WithMetadata(customDgst, tainted)
 	}
 
-	return r.call(ctx, s, newID, inputArgs)
+	return r.call(ctx, s, newID, field, inputArgs)
 }
 
 // Call calls the field on the instance specified by the ID.
@@ -432,13 +440,14 @@ func (r Instance[T]) Call(ctx context.Context, s *Server, newID *call.ID) (Typed
 		}
 	}
 
-	return r.call(ctx, s, newID, inputArgs)
+	return r.call(ctx, s, newID, field, inputArgs)
 }
 
 func (r Instance[T]) call(
 	ctx context.Context,
 	s *Server,
 	newID *call.ID,
+	field Field[T],
 	inputArgs map[string]Input,
 ) (Typed, *call.ID, error) {
 	doCall := func(ctx context.Context) (innerVal Typed, innerErr error) {
@@ -487,7 +496,16 @@ func (r Instance[T]) call(
 	if newID.IsTainted() {
 		val, err = doCall(ctx)
 	} else {
-		val, _, err = s.Cache.GetOrInitialize(ctx, dig, doCall)
+		if cache, ok := s.Cache.(*persistentCache); ok && s.persistable(newID) {
+			doCall = cache.persisted(dig, field.Spec.Type, doCall)
+		}
+		// results with a custom cache key may not be recomputed from their ID
+		// alone, so only the others may be evicted
+		if cache, ok := s.Cache.(evictableCache); ok && field.CacheKeyFunc == nil {
+			val, _, err = cache.GetOrInitializeEvictable(ctx, dig, doCall)
+		} else {
+			val, _, err = s.Cache.GetOrInitialize(ctx, dig, doCall)
+		}
 	}
 	if err != nil {
 		return nil, nil, err
@@ -670,6 +688,9 @@ type FieldSpec struct {
 	Meta bool
 	// ImpurityReason indicates that the field's result may change over time.
 	ImpurityReason string
+	// Persistent indicates that the field's result is determined by its
+	// receiver and arguments across sessions, so it may be persisted.
+	Persistent bool
 	// DeprecatedReason deprecates the field and provides a reason.
 	DeprecatedReason string
 	// Module is the module that provides the field's implementation.
@@ -964,6 +985,22 @@ func (field Field[T]) Impure(reason string, paras ...string) Field[T] {
 	return field
 }
 
+// Persistent marks the field as safe to persist, meaning its result is
+// determined by its receiver and arguments, even across sessions.
+//
+// Results are only persisted when every field of their ID is persistent, and
+// only if they are scalars, enums or lists of them.
+func (field Field[T]) Persistent() Field[T] {
+	if field.Spec.extend {
+		panic("cannot call on extended field")
+	}
+	if field.CacheKeyFunc != nil {
+		panic("cannot persist field with a custom cache key")
+	}
+	field.Spec.Persistent = true
+	return field
+}
+
 // Meta indicates that the field has no impact on the field's result.
 func (field Field[T]) Meta() Field[T] {
 	if field.Spec.extend {
@@ -0,0 +1,147 @@
+package dagql
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	"github.com/opencontainers/go-digest"
+
+	"github.com/dagger/dagger/dagql/call"
+	"github.com/dagger/dagger/engine/slog"
+)
+
+// ResultStore stores the encoded results of selections across sessions,
+// keyed by the digest of their ID.
+type ResultStore interface {
+	// Load returns the result stored for the digest, if any.
+	Load(ctx context.Context, key digest.Digest) ([]byte, bool, error)
+	// Store stores the result for the digest.
+	Store(ctx context.Context, key digest.Digest, value []byte) error
+}
+
+// NewPersistentCache creates a cache map like NewCacheWithLimits, which also
+// loads the results of persistent fields from the store, or stores them.
+func NewPersistentCache(store ResultStore, limits CacheLimits) Cache {
+	return &persistentCache{
+		cacheMap: newTypedCacheMap(limits),
+		store:    store,
+	}
+}
+
+type persistentCache struct {
+	*cacheMap[digest.Digest, Typed]
+
+	store ResultStore
+}
+
+// persisted wraps the call of a field, to load its result from the store if
+// it was stored by a previous session, or to store it.
+func (c *persistentCache) persisted(
+	key digest.Digest,
+	typ Typed,
+	fn func(context.Context) (Typed, error),
+) func(context.Context) (Typed, error) {
+	rt := reflect.TypeOf(typ)
+	if !persistableType(rt) {
+		return fn
+	}
+	return func(ctx context.Context) (Typed, error) {
+		data, ok, err := c.store.Load(ctx, key)
+		if err != nil {
+			slog.Warn("failed to load persisted result", "digest", key, "error", err)
+		} else if ok {
+			val := reflect.New(rt)
+			if err := json.Unmarshal(data, val.Interface()); err == nil {
+				return val.Elem().Interface().(Typed), nil
+			}
+			slog.Warn("failed to decode persisted result", "digest", key, "error", err)
+		}
+
+		res, err := fn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if res == nil || reflect.TypeOf(res) != rt {
+			// e.g. a null or an element of the result
+			return res, nil
+		}
+		data, err = json.Marshal(res)
+		if err != nil {
+			slog.Warn("failed to encode persisted result", "digest", key, "error", err)
+			return res, nil
+		}
+		if err := c.store.Store(ctx, key, data); err != nil {
+			slog.Warn("failed to persist result", "digest", key, "error", err)
+		}
+		return res, nil
+	}
+}
+
+// persistable returns whether every call of the ID, its receivers and the IDs
+// in their arguments selects a persistent, pure field.
+func (s *Server) persistable(id *call.ID) bool {
+	for ; id != nil; id = id.Receiver() {
+		if id.IsTainted() {
+			return false
+		}
+		typeName := s.root.Type().Name()
+		if id.Receiver() != nil {
+			typeName = id.Receiver().Type().NamedType()
+		}
+		objType, ok := s.ObjectType(typeName)
+		if !ok {
+			return false
+		}
+		spec, ok := objType.FieldSpec(id.Field(), id.View())
+		if !ok || !spec.Persistent || spec.ImpurityReason != "" {
+			return false
+		}
+		for _, arg := range id.Args() {
+			if !s.persistableLiteral(arg.Value()) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func (s *Server) persistableLiteral(lit call.Literal) bool {
+	switch lit := lit.(type) {
+	case *call.LiteralID:
+		return s.persistable(lit.Value())
+	case *call.LiteralList:
+		persistable := true
+		lit.Range(func(_ int, elem call.Literal) error {
+			persistable = persistable && s.persistableLiteral(elem)
+			return nil
+		})
+		return persistable
+	case *call.LiteralObject:
+		persistable := true
+		lit.Range(func(_ int, _ string, field call.Literal) error {
+			persistable = persistable && s.persistableLiteral(field)
+			return nil
+		})
+		return persistable
+	default:
+		return true
+	}
+}
+
+// persistableType returns whether values of the type are fully encoded as
+// JSON, i.e. scalars, enums and lists of them, rather than objects with
+// state.
+func persistableType(rt reflect.Type) bool {
+	switch rt.Kind() {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return persistableType(rt.Elem())
+	default:
+		return false
+	}
+}
@@ -79,6 +79,17 @@ type Cache interface {
 	) (Typed, bool, error)
 }
 
+// evictableCache is a Cache that may evict results which can be recomputed,
+// to respect its limits.
+type evictableCache interface {
+	Cache
+	GetOrInitializeEvictable(
+		context.Context,
+		digest.Digest,
+		func(context.Context) (Typed, error),
+	) (Typed, bool, error)
+}
+
 // TypeDef is a type whose sole practical purpose is to define a GraphQL type,
 // so it explicitly includes the Definitive interface.
 type TypeDef interface {
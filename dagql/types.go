@@ -44,6 +44,8 @@ type ObjectType interface {
 	// ParseField parses the given field and returns a Selector and an expected
 	// return type.
 	ParseField(ctx context.Context, view string, astField *ast.Field, vars map[string]any) (Selector, *ast.Type, error)
+	// FieldSpec returns the spec of the field visible in the given views.
+	FieldSpec(name string, views ...string) (FieldSpec, bool)
 	// Extend registers an additional field onto the type.
 	//
 	// Unlike natively added fields, the extended func is limited to the external
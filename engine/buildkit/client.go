@@ -162,13 +162,16 @@ func (c *Client) Solve(ctx context.Context, req bkgw.SolveRequest) (_ *Result, r
 	}
 
 	// include upstream cache imports, if any
-	req.CacheImports = c.UpstreamCacheImports
+	req.CacheImports = append(req.CacheImports, c.UpstreamCacheImports...)
 
-	// handle secret translation
+	// handle secret and ssh translation
 	gw := newFilterGateway(c, req)
 	if v := ctx.Value("secret-translator"); v != nil {
 		gw.secretTranslator = v.(func(string) (string, error))
 	}
+	if v := ctx.Value("ssh-translator"); v != nil {
+		gw.sshTranslator = v.(func(string) (string, error))
+	}
 	llbRes, err := gw.Solve(ctx, req, c.ID())
 	if err != nil {
 		return nil, WrapError(ctx, err, c)
@@ -234,6 +237,7 @@ type NewContainerRequest struct {
 	Mounts   []ContainerMount
 	Platform *bksolverpb.Platform
 	Hostname string
+	NetMode  bksolverpb.NetMode
 	ExecutionMetadata
 }
 
@@ -259,6 +263,7 @@ func (c *Client) NewContainer(ctx context.Context, req NewContainerRequest) (*Co
 	ctrReq := bkcontainer.NewContainerRequest{
 		ContainerID: containerID,
 		Hostname:    req.Hostname,
+		NetMode:     req.NetMode,
 		Mounts:      make([]bkcontainer.Mount, len(req.Mounts)),
 	}
 
@@ -813,6 +818,11 @@ type filteringGateway struct {
 	// in the secret store.
 	secretTranslator func(string) (string, error)
 
+	// sshTranslator is a function to convert ssh socket ids. Frontends
+	// request ssh sockets by name (e.g. "default"), but they are keyed by
+	// socket ID in the socket store.
+	sshTranslator func(string) (string, error)
+
 	// client is the top-most client that is owning the filtering process
 	client *Client
 
@@ -840,7 +850,7 @@ func newFilterGateway(client *Client, req bkgw.SolveRequest) *filteringGateway {
 func (gw *filteringGateway) Solve(ctx context.Context, req bkfrontend.SolveRequest, sid string) (*bkfrontend.Result, error) {
 	switch {
 	case req.Definition != nil && req.Definition.Def != nil:
-		if gw.secretTranslator != nil {
+		if gw.secretTranslator != nil || gw.sshTranslator != nil {
 			dag, err := DefToDAG(req.Definition)
 			if err != nil {
 				return nil, err
@@ -856,20 +866,28 @@ func (gw *filteringGateway) Solve(ctx context.Context, req bkfrontend.SolveReque
 					return nil
 				}
 
-				for _, secret := range execOp.ExecOp.GetSecretenv() {
-					secret.ID, err = gw.secretTranslator(secret.ID)
-					if err != nil {
-						return err
+				if gw.secretTranslator != nil {
+					for _, secret := range execOp.ExecOp.GetSecretenv() {
+						secret.ID, err = gw.secretTranslator(secret.ID)
+						if err != nil {
+							return err
+						}
 					}
 				}
 				for _, mount := range execOp.ExecOp.GetMounts() {
-					if mount.MountType != bksolverpb.MountType_SECRET {
-						continue
-					}
-					secret := mount.SecretOpt
-					secret.ID, err = gw.secretTranslator(secret.ID)
-					if err != nil {
-						return err
+					switch {
+					case mount.MountType == bksolverpb.MountType_SECRET && gw.secretTranslator != nil:
+						secret := mount.SecretOpt
+						secret.ID, err = gw.secretTranslator(secret.ID)
+						if err != nil {
+							return err
+						}
+					case mount.MountType == bksolverpb.MountType_SSH && gw.sshTranslator != nil:
+						ssh := mount.SSHOpt
+						ssh.ID, err = gw.sshTranslator(ssh.ID)
+						if err != nil {
+							return err
+						}
 					}
 				}
 				return nil
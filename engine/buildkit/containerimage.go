@@ -2,6 +2,7 @@ package buildkit
 
 import (
 	"context"
+	"crypto"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -9,27 +10,45 @@ import (
 	"strings"
 
 	"github.com/containerd/platforms"
+	intoto "github.com/in-toto/in-toto-golang/in_toto"
 	bkcache "github.com/moby/buildkit/cache"
 	bkclient "github.com/moby/buildkit/client"
 	"github.com/moby/buildkit/client/llb"
 	"github.com/moby/buildkit/exporter/containerimage/exptypes"
-	bkgw "github.com/moby/buildkit/frontend/gateway/client"
+	gatewaypb "github.com/moby/buildkit/frontend/gateway/pb"
+	bksession "github.com/moby/buildkit/session"
 	bksolverpb "github.com/moby/buildkit/solver/pb"
 	solverresult "github.com/moby/buildkit/solver/result"
+	"github.com/opencontainers/go-digest"
 	specs "github.com/opencontainers/image-spec/specs-go/v1"
 
 	"github.com/dagger/dagger/engine"
 )
 
+// SLSAProvenancePredicateType is the in-toto predicate type of the SLSA
+// provenance attestations of exported images.
+const SLSAProvenancePredicateType = "https://slsa.dev/provenance/v1"
+
 type ContainerExport struct {
 	Definition *bksolverpb.Definition
 	Config     specs.ImageConfig
+
+	// SBOM attaches an SPDX SBOM of the container's filesystem as an
+	// attestation.
+	SBOM bool
+	// Provenance is a SLSA provenance predicate to attach as an attestation.
+	Provenance []byte
+
+	// History describes the image's layers by the steps that produced them.
+	// If empty, each layer is described by the operation that created it.
+	History []ImageHistoryStep
 }
 
 func (c *Client) PublishContainerImage(
 	ctx context.Context,
 	inputByPlatform map[string]ContainerExport,
 	opts map[string]string, // TODO: make this an actual type, this leaks too much untyped buildkit api
+	signer crypto.Signer, // optional, signs the published image
 ) (map[string]string, error) {
 	ctx = buildkitTelemetryProvider(ctx)
 	ctx, cancel, err := c.withClientCloseCancel(ctx)
@@ -60,6 +79,16 @@ func (c *Client) PublishContainerImage(
 	if descRef != nil {
 		descRef.Release()
 	}
+
+	if signer != nil {
+		dgst, err := digest.Parse(resp[exptypes.ExporterImageDigestKey])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get digest of published image: %w", err)
+		}
+		if err := c.pushImageSignature(ctx, opts[string(exptypes.OptKeyName)], dgst, signer); err != nil {
+			return nil, err
+		}
+	}
 	return resp, nil
 }
 
@@ -128,6 +157,7 @@ func (c *Client) ContainerImageToTarball(
 	fileName string,
 	inputByPlatform map[string]ContainerExport,
 	opts map[string]string,
+	signer crypto.Signer, // optional, signs the image in the tarball
 ) (*bksolverpb.Definition, error) {
 	ctx = buildkitTelemetryProvider(ctx)
 	ctx, cancel, err := c.withClientCloseCancel(ctx)
@@ -163,7 +193,7 @@ func (c *Client) ContainerImageToTarball(
 		IsFileStream: true,
 	}.AppendToOutgoingContext(ctx)
 
-	_, descRef, err := expInstance.Export(ctx, combinedResult, nil, c.ID())
+	resp, descRef, err := expInstance.Export(ctx, combinedResult, nil, c.ID())
 	if err != nil {
 		return nil, fmt.Errorf("failed to export: %w", err)
 	}
@@ -171,6 +201,16 @@ func (c *Client) ContainerImageToTarball(
 		defer descRef.Release()
 	}
 
+	if signer != nil {
+		dgst, err := digest.Parse(resp[exptypes.ExporterImageDigestKey])
+		if err != nil {
+			return nil, fmt.Errorf("failed to get digest of exported image: %w", err)
+		}
+		if err := signTarball(destPath, dgst, signer); err != nil {
+			return nil, fmt.Errorf("failed to sign image tarball: %w", err)
+		}
+	}
+
 	localDef, err := llb.Local(tmpDir,
 		llb.SessionID(c.ID()), // see engine/server/bk_session.go, we have a special session that points to our engine host
 		llb.SharedKeyHint(c.ID()),
@@ -195,27 +235,16 @@ func (c *Client) getContainerResult(
 	}
 	// TODO: probably faster to do this in parallel for each platform
 	for platformString, input := range inputByPlatform {
-		res, err := c.Solve(ctx, bkgw.SolveRequest{
-			Definition: input.Definition,
-			Evaluate:   true,
-		})
+		ref, err := c.solveRef(ctx, input.Definition)
 		if err != nil {
 			return nil, fmt.Errorf("failed to solve for container publish: %w", err)
 		}
-		cacheRes, err := ConvertToWorkerCacheResult(ctx, res)
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert result: %w", err)
-		}
-		ref, err := cacheRes.SingleRef()
-		if err != nil {
-			return nil, err
-		}
 
 		platform, err := platforms.Parse(platformString)
 		if err != nil {
 			return nil, err
 		}
-		cfgBytes, err := json.Marshal(specs.Image{
+		img := specs.Image{
 			Platform: specs.Platform{
 				Architecture: platform.Architecture,
 				OS:           platform.OS,
@@ -223,11 +252,59 @@ func (c *Client) getContainerResult(
 				OSFeatures:   platform.OSFeatures,
 			},
 			Config: input.Config,
-		})
+		}
+		if len(input.History) > 0 {
+			// the exporter uses the history of the config for the layers it
+			// covers, in order
+			img.History, err = c.imageHistory(ctx, ref, input.History)
+			if err != nil {
+				return nil, err
+			}
+		}
+		cfgBytes, err := json.Marshal(img)
 		if err != nil {
 			return nil, err
 		}
 		combinedResult.AddMeta(fmt.Sprintf("%s/%s", exptypes.ExporterImageConfigKey, platformString), cfgBytes)
+
+		// the exporter keys attestations by platform ID, which for a single
+		// platform is the normalized platform of its image config
+		attestationKey := platformString
+		if len(inputByPlatform) == 1 {
+			attestationKey = platforms.Format(platforms.Normalize(platform))
+		}
+		if input.SBOM {
+			sbom, err := imageSBOM(ctx, ref, bksession.NewGroup(c.ID()), "container "+platformString)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate SBOM: %w", err)
+			}
+			combinedResult.AddAttestation(attestationKey, solverresult.Attestation[bkcache.ImmutableRef]{
+				Kind: gatewaypb.AttestationKindInToto,
+				Metadata: map[string][]byte{
+					solverresult.AttestationReasonKey: []byte(solverresult.AttestationReasonSBOM),
+					solverresult.AttestationSBOMCore:  []byte(sbomCoreName),
+				},
+				Path:        sbomCoreName + ".spdx.json",
+				ContentFunc: func() ([]byte, error) { return sbom, nil },
+				InToto: solverresult.InTotoAttestation{
+					PredicateType: intoto.PredicateSPDX,
+				},
+			})
+		}
+		if input.Provenance != nil {
+			provenance := input.Provenance
+			combinedResult.AddAttestation(attestationKey, solverresult.Attestation[bkcache.ImmutableRef]{
+				Kind: gatewaypb.AttestationKindInToto,
+				Metadata: map[string][]byte{
+					solverresult.AttestationReasonKey: []byte(solverresult.AttestationReasonProvenance),
+				},
+				ContentFunc: func() ([]byte, error) { return provenance, nil },
+				InToto: solverresult.InTotoAttestation{
+					PredicateType: SLSAProvenancePredicateType,
+				},
+			})
+		}
+
 		if len(inputByPlatform) == 1 {
 			combinedResult.AddMeta(exptypes.ExporterImageConfigKey, cfgBytes)
 			combinedResult.SetRef(ref)
@@ -1,5 +1,34 @@
 package buildkit
 
+import "fmt"
+
+// ExecExitReason describes why the engine terminated an exec, as opposed to
+// the exec exiting on its own.
+type ExecExitReason string
+
+const (
+	// ExecExitReasonTimeout is set when an exec ran past its configured timeout.
+	ExecExitReasonTimeout ExecExitReason = "TIMEOUT"
+	// ExecExitReasonOOMKilled is set when an exec was killed for exceeding its
+	// memory limit.
+	ExecExitReasonOOMKilled ExecExitReason = "OOM_KILLED"
+)
+
+// ExitReasonError wraps the error of an exec that the engine terminated, for
+// execs that have no meta mount to record the reason in, e.g. services.
+type ExitReasonError struct {
+	Reason ExecExitReason
+	Err    error
+}
+
+func (e *ExitReasonError) Error() string {
+	return fmt.Sprintf("%s (%s)", e.Err, e.Reason)
+}
+
+func (e *ExitReasonError) Unwrap() error {
+	return e.Err
+}
+
 // ExecError is an error that occurred while executing an `Op_Exec`.
 type ExecError struct {
 	original error
@@ -7,6 +36,8 @@ type ExecError struct {
 	ExitCode int
 	Stdout   string
 	Stderr   string
+	// Reason is set if the engine terminated the exec, e.g. due to a timeout.
+	Reason ExecExitReason
 }
 
 func (e *ExecError) Error() string {
@@ -18,11 +49,15 @@ func (e *ExecError) Unwrap() error {
 }
 
 func (e *ExecError) Extensions() map[string]interface{} {
-	return map[string]interface{}{
+	ext := map[string]interface{}{
 		"_type":    "EXEC_ERROR",
 		"cmd":      e.Cmd,
 		"exitCode": e.ExitCode,
 		"stdout":   e.Stdout,
 		"stderr":   e.Stderr,
 	}
+	if e.Reason != "" {
+		ext["reason"] = string(e.Reason)
+	}
+	return ext
 }
@@ -102,6 +102,13 @@ type ExecutionMetadata struct {
 
 	// If true, skip injecting dagger-init into the container.
 	NoInit bool
+
+	// Time and resource limits to enforce on the exec, if any.
+	Limits *ExecLimits
+
+	// If true, only allow the exec to reach other containers on the engine's
+	// network (i.e. services) and the engine's DNS server.
+	ServicesOnlyNetwork bool
 }
 
 const executionMetadataKey = "dagger.executionMetadata"
@@ -159,9 +166,12 @@ func (w *Worker) Run(
 	state := newExecState(id, &procInfo, rootMount, mounts, started)
 	return nil, w.run(ctx, state,
 		w.setupNetwork,
+		w.setupNetworkPolicy,
+		w.refreshServiceHosts,
 		w.injectInit,
 		w.generateBaseSpec,
 		w.filterEnvs,
+		w.setupLimits,
 		w.setupRootfs,
 		w.setUserGroup,
 		w.setExitCodePath,
@@ -0,0 +1,136 @@
+package buildkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/moby/buildkit/util/bklog"
+	"github.com/opencontainers/runtime-spec/specs-go"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/dagger/dagger/engine/buildkit/resources"
+)
+
+// the CFS period used when converting a CPU limit into a quota; matches the
+// kernel default
+const cpuQuotaPeriod = 100000
+
+// ExecLimits bounds the time and resources an exec may consume. Zero values
+// mean no limit.
+type ExecLimits struct {
+	// Wall-clock time the exec may run for before it's killed.
+	Timeout time.Duration
+	// Number of CPUs the exec may use, enforced via the CFS quota.
+	CPUs float64
+	// Memory in bytes the exec may use before it's OOM-killed.
+	MemoryBytes int64
+	// Number of processes the exec may have running at once.
+	Pids int64
+}
+
+// IsZero returns true if no limits are set.
+func (limits ExecLimits) IsZero() bool {
+	return limits == ExecLimits{}
+}
+
+// String returns a stable representation of the limits, suitable for
+// including in an exec's cache key.
+func (limits ExecLimits) String() string {
+	return fmt.Sprintf("timeout=%s,cpus=%g,memory=%d,pids=%d",
+		limits.Timeout, limits.CPUs, limits.MemoryBytes, limits.Pids)
+}
+
+func (w *Worker) setupLimits(_ context.Context, state *execState) error {
+	if w.execMD == nil || w.execMD.Limits == nil {
+		return nil
+	}
+	limits := w.execMD.Limits
+
+	if state.spec.Linux == nil {
+		state.spec.Linux = &specs.Linux{}
+	}
+	if state.spec.Linux.Resources == nil {
+		state.spec.Linux.Resources = &specs.LinuxResources{}
+	}
+	res := state.spec.Linux.Resources
+
+	if limits.CPUs > 0 {
+		if res.CPU == nil {
+			res.CPU = &specs.LinuxCPU{}
+		}
+		period := uint64(cpuQuotaPeriod)
+		quota := int64(limits.CPUs * cpuQuotaPeriod)
+		res.CPU.Period = &period
+		res.CPU.Quota = &quota
+	}
+
+	if limits.MemoryBytes > 0 {
+		if res.Memory == nil {
+			res.Memory = &specs.LinuxMemory{}
+		}
+		memory := limits.MemoryBytes
+		res.Memory.Limit = &memory
+		// set swap to the same value so the limit can't be dodged by swapping
+		res.Memory.Swap = &memory
+	}
+
+	if limits.Pids > 0 {
+		res.Pids = &specs.LinuxPids{Limit: limits.Pids}
+	}
+
+	return nil
+}
+
+// errExecTimeout is the cause set on an exec's context when it exceeds its
+// timeout.
+var errExecTimeout = errors.New("exec timed out")
+
+// withExecTimeout returns a context that's canceled once the exec's timeout,
+// if any, elapses.
+func (w *Worker) withExecTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if w.execMD == nil || w.execMD.Limits == nil || w.execMD.Limits.Timeout <= 0 {
+		return ctx, func() {}
+	}
+	timeout := w.execMD.Limits.Timeout
+	return context.WithTimeoutCause(ctx, timeout,
+		fmt.Errorf("%w after %s", errExecTimeout, timeout))
+}
+
+// recordExitReason writes the reason the engine terminated the exec, if it
+// did, to the meta mount so that it can be surfaced in an ExecError, and
+// returns it.
+func (w *Worker) recordExitReason(ctx context.Context, state *execState, cgroupPath string) ExecExitReason {
+	var reason ExecExitReason
+	switch {
+	case errors.Is(context.Cause(ctx), errExecTimeout):
+		reason = ExecExitReasonTimeout
+	case cgroupPath != "":
+		oomKilled, err := resources.OOMKilled(cgroupPath)
+		if err != nil {
+			bklog.G(ctx).Debugf("failed to check for OOM kill: %v", err)
+		}
+		if oomKilled {
+			reason = ExecExitReasonOOMKilled
+		}
+	}
+	if reason == "" {
+		return ""
+	}
+
+	trace.SpanFromContext(ctx).AddEvent(
+		"Container terminated",
+		trace.WithAttributes(attribute.String("exit.reason", string(reason))),
+	)
+
+	if state.exitReasonPath == "" {
+		return reason
+	}
+	if err := os.WriteFile(state.exitReasonPath, []byte(reason), 0o600); err != nil {
+		bklog.G(ctx).Errorf("failed to write exit reason %s to %s: %v", reason, state.exitReasonPath, err)
+	}
+	return reason
+}
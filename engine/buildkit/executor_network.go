@@ -0,0 +1,173 @@
+package buildkit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/moby/buildkit/solver/pb"
+	"github.com/moby/buildkit/util/bklog"
+)
+
+// how often to check whether bound services have moved to a new IP
+const serviceHostsRefreshInterval = time.Second
+
+func (w *Worker) setupNetworkPolicy(ctx context.Context, state *execState) error {
+	if w.execMD == nil || !w.execMD.ServicesOnlyNetwork {
+		return nil
+	}
+	if state.procInfo.Meta.NetMode != pb.NetMode_UNSET {
+		// only the default mode joins the engine's network, so there's nothing
+		// to restrict otherwise
+		return nil
+	}
+
+	var nameservers []string
+	if w.dns != nil {
+		nameservers = w.dns.Nameservers
+	}
+
+	// the bound services' IPs were resolved when writing the hosts file
+	if err := applyNetworkRules(ctx, state, servicesOnlyRules(state.serviceIPs, nameservers)); err != nil {
+		return fmt.Errorf("restrict network to services: %w", err)
+	}
+	return nil
+}
+
+func applyNetworkRules(ctx context.Context, state *execState, rules string) error {
+	_, err := runInNetNS(ctx, state, func() (struct{}, error) {
+		cmd := exec.CommandContext(ctx, "iptables-restore")
+		cmd.Stdin = strings.NewReader(rules)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return struct{}{}, fmt.Errorf("iptables-restore: %w: %s", err, out)
+		}
+		return struct{}{}, nil
+	})
+	return err
+}
+
+// refreshServiceHosts keeps the hosts file entries of bound services up to
+// date for as long as the container runs. A service that restarts keeps its
+// hostname but comes back with a new IP, which the engine's DNS picks up but
+// the hosts file wouldn't otherwise.
+func (w *Worker) refreshServiceHosts(ctx context.Context, state *execState) error {
+	if state.hostIPs == nil {
+		return nil
+	}
+
+	servicesOnly := w.execMD.ServicesOnlyNetwork && state.procInfo.Meta.NetMode == pb.NetMode_UNSET
+	var nameservers []string
+	if w.dns != nil {
+		nameservers = w.dns.Nameservers
+	}
+
+	stop := make(chan struct{})
+	stopped := make(chan struct{})
+	state.cleanups.Add("stop refreshing hosts file", Infallible(func() {
+		close(stop)
+		<-stopped
+	}))
+
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(serviceHostsRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+			}
+
+			hostIPs, err := resolveHostAliases(w.execMD.HostAliases, state.searchDomains)
+			if err != nil {
+				// the service may be between restarts; try again later
+				continue
+			}
+			if hostIPsEqual(hostIPs, state.hostIPs) {
+				continue
+			}
+
+			// truncate and rewrite the file rather than replacing it, since it's
+			// bind mounted into the container
+			f, err := os.OpenFile(state.hostsFilePath, os.O_WRONLY|os.O_TRUNC, 0)
+			if err != nil {
+				bklog.G(ctx).WithError(err).Warn("failed to open hosts file for refresh")
+				continue
+			}
+			_, err = f.Write(hostsFileContent(state.baseHosts, w.execMD.HostAliases, hostIPs))
+			f.Close()
+			if err != nil {
+				bklog.G(ctx).WithError(err).Warn("failed to refresh hosts file")
+				continue
+			}
+
+			if servicesOnly {
+				var serviceIPs []net.IP
+				for _, ips := range hostIPs {
+					serviceIPs = append(serviceIPs, ips...)
+				}
+				if err := applyNetworkRules(ctx, state, servicesOnlyRules(serviceIPs, nameservers)); err != nil {
+					bklog.G(ctx).WithError(err).Warn("failed to refresh services network rules")
+					continue
+				}
+			}
+			state.hostIPs = hostIPs
+		}
+	}()
+	return nil
+}
+
+func hostIPsEqual(a, b map[string][]net.IP) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for host, ipsA := range a {
+		ipsB, ok := b[host]
+		if !ok || !slices.EqualFunc(ipsA, ipsB, net.IP.Equal) {
+			return false
+		}
+	}
+	return true
+}
+
+// servicesOnlyRules returns an iptables-restore ruleset that only allows
+// outbound traffic to the given service IPs and DNS traffic to the given
+// nameservers.
+//
+// Disallowed packets are rejected in the OUTPUT chain as administratively
+// prohibited, which makes connect(2) fail immediately with EHOSTUNREACH
+// rather than hanging until a timeout.
+func servicesOnlyRules(serviceIPs []net.IP, nameservers []string) string {
+	var rules strings.Builder
+	rules.WriteString("*filter\n")
+	rules.WriteString(":INPUT ACCEPT [0:0]\n")
+	rules.WriteString(":FORWARD ACCEPT [0:0]\n")
+	rules.WriteString(":OUTPUT ACCEPT [0:0]\n")
+	rules.WriteString("-A OUTPUT -o lo -j ACCEPT\n")
+	rules.WriteString("-A OUTPUT -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT\n")
+	for _, ns := range nameservers {
+		ip := net.ParseIP(ns)
+		if ip == nil || ip.To4() == nil {
+			continue
+		}
+		fmt.Fprintf(&rules, "-A OUTPUT -d %s/32 -p udp --dport 53 -j ACCEPT\n", ip)
+		fmt.Fprintf(&rules, "-A OUTPUT -d %s/32 -p tcp --dport 53 -j ACCEPT\n", ip)
+	}
+	seen := map[string]bool{}
+	for _, ip := range serviceIPs {
+		if ip.To4() == nil || seen[ip.String()] {
+			continue
+		}
+		seen[ip.String()] = true
+		fmt.Fprintf(&rules, "-A OUTPUT -d %s/32 -j ACCEPT\n", ip)
+	}
+	rules.WriteString("-A OUTPUT -j REJECT --reject-with icmp-admin-prohibited\n")
+	rules.WriteString("COMMIT\n")
+	return rules.String()
+}
@@ -0,0 +1,70 @@
+package buildkit
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestServicesOnlyRules(t *testing.T) {
+	serviceIPs := []net.IP{
+		net.ParseIP("10.87.0.5"),
+		net.ParseIP("10.87.0.9"),
+		net.ParseIP("10.87.0.5"),
+		net.ParseIP("fd00::5"),
+	}
+	rules := servicesOnlyRules(serviceIPs, []string{"10.87.0.1", "fd00::1", "bogus"})
+	require.Equal(t, `*filter
+:INPUT ACCEPT [0:0]
+:FORWARD ACCEPT [0:0]
+:OUTPUT ACCEPT [0:0]
+-A OUTPUT -o lo -j ACCEPT
+-A OUTPUT -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT
+-A OUTPUT -d 10.87.0.1/32 -p udp --dport 53 -j ACCEPT
+-A OUTPUT -d 10.87.0.1/32 -p tcp --dport 53 -j ACCEPT
+-A OUTPUT -d 10.87.0.5/32 -j ACCEPT
+-A OUTPUT -d 10.87.0.9/32 -j ACCEPT
+-A OUTPUT -j REJECT --reject-with icmp-admin-prohibited
+COMMIT
+`, rules)
+}
+
+func TestServicesOnlyRulesNoServices(t *testing.T) {
+	rules := servicesOnlyRules(nil, nil)
+	require.Equal(t, `*filter
+:INPUT ACCEPT [0:0]
+:FORWARD ACCEPT [0:0]
+:OUTPUT ACCEPT [0:0]
+-A OUTPUT -o lo -j ACCEPT
+-A OUTPUT -m conntrack --ctstate ESTABLISHED,RELATED -j ACCEPT
+-A OUTPUT -j REJECT --reject-with icmp-admin-prohibited
+COMMIT
+`, rules)
+}
+
+func TestHostsFileContent(t *testing.T) {
+	aliases := map[string][]string{
+		"db-host":  {"db"},
+		"www-host": {"www", "web"},
+	}
+	hostIPs := map[string][]net.IP{
+		"db-host":  {net.ParseIP("10.87.0.7")},
+		"www-host": {net.ParseIP("10.87.0.5")},
+	}
+	content := hostsFileContent([]byte("127.0.0.1\tlocalhost\n"), aliases, hostIPs)
+	require.Equal(t, "127.0.0.1\tlocalhost\n"+
+		"\n10.87.0.7\tdb\n"+
+		"\n10.87.0.5\twww\n"+
+		"\n10.87.0.5\tweb\n", string(content))
+
+	require.True(t, hostIPsEqual(hostIPs, map[string][]net.IP{
+		"db-host":  {net.ParseIP("10.87.0.7")},
+		"www-host": {net.ParseIP("10.87.0.5")},
+	}))
+	// a restarted service comes back with a new IP
+	require.False(t, hostIPsEqual(hostIPs, map[string][]net.IP{
+		"db-host":  {net.ParseIP("10.87.0.7")},
+		"www-host": {net.ParseIP("10.87.0.8")},
+	}))
+}
@@ -8,11 +8,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net"
 	"net/http"
 	"os"
 	"path"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -58,6 +60,8 @@ const (
 	DaggerRedirectStderrEnv  = "_DAGGER_REDIRECT_STDERR"
 	DaggerHostnameAliasesEnv = "_DAGGER_HOSTNAME_ALIASES"
 	DaggerNoInitEnv          = "_DAGGER_NOINIT"
+	DaggerExecLimitsEnv      = "_DAGGER_EXEC_LIMITS"
+	DaggerNetworkModeEnv     = "_DAGGER_NETWORK_MODE"
 
 	DaggerSessionPortEnv  = "DAGGER_SESSION_PORT"
 	DaggerSessionTokenEnv = "DAGGER_SESSION_TOKEN"
@@ -79,6 +83,8 @@ var removeEnvs = map[string]struct{}{
 	DaggerRedirectStderrEnv:  {},
 	DaggerHostnameAliasesEnv: {},
 	DaggerNoInitEnv:          {},
+	DaggerExecLimitsEnv:      {},
+	DaggerNetworkModeEnv:     {},
 }
 
 type execState struct {
@@ -98,6 +104,11 @@ type execState struct {
 	resolvConfPath     string
 	hostsFilePath      string
 	exitCodePath       string
+	exitReasonPath     string
+	serviceIPs         []net.IP
+	baseHosts          []byte
+	searchDomains      []string
+	hostIPs            map[string][]net.IP
 	metaMount          *specs.Mount
 	origEnvMap         map[string]string
 	sessionClientConnF *os.File
@@ -144,6 +155,11 @@ func (w *Worker) setupNetwork(ctx context.Context, state *execState) error {
 	if state.procInfo.Meta.SecurityMode == pb.SecurityMode_INSECURE && state.procInfo.Meta.Hostname == "" {
 		state.procInfo.Meta.Hostname = uuid.NewString()
 	}
+	// likewise, the SERVICES_ONLY network policy installs rules in the netns, so
+	// make sure it's never one that's reused by a later exec
+	if w.execMD != nil && w.execMD.ServicesOnlyNetwork && state.procInfo.Meta.Hostname == "" {
+		state.procInfo.Meta.Hostname = uuid.NewString()
+	}
 	networkNamespace, err := provider.New(ctx, state.procInfo.Meta.Hostname)
 	if err != nil {
 		return fmt.Errorf("create network namespace: %w", err)
@@ -237,13 +253,12 @@ func (w *Worker) setupNetwork(ctx context.Context, state *execState) error {
 		return nil
 	}
 
-	baseHostsFile, err := os.Open(state.hostsFilePath)
+	baseHosts, err := os.ReadFile(state.hostsFilePath)
 	if err != nil {
-		return fmt.Errorf("open base hosts file: %w", err)
+		return fmt.Errorf("read base hosts file: %w", err)
 	}
-	defer baseHostsFile.Close()
 
-	baseHostsStat, err := baseHostsFile.Stat()
+	baseHostsStat, err := os.Stat(state.hostsFilePath)
 	if err != nil {
 		return fmt.Errorf("stat base hosts file: %w", err)
 	}
@@ -262,14 +277,32 @@ func (w *Worker) setupNetwork(ctx context.Context, state *execState) error {
 		return fmt.Errorf("chmod hosts file: %w", err)
 	}
 
-	if _, err := io.Copy(ctrHostsFile, baseHostsFile); err != nil {
-		return fmt.Errorf("copy base hosts file: %w", err)
+	hostIPs, err := resolveHostAliases(w.execMD.HostAliases, extraSearchDomains)
+	if err != nil {
+		return err
+	}
+	if _, err := ctrHostsFile.Write(hostsFileContent(baseHosts, w.execMD.HostAliases, hostIPs)); err != nil {
+		return fmt.Errorf("write hosts file: %w", err)
+	}
+
+	state.baseHosts = baseHosts
+	state.searchDomains = extraSearchDomains
+	state.hostIPs = hostIPs
+	for _, ips := range hostIPs {
+		state.serviceIPs = append(state.serviceIPs, ips...)
 	}
 
-	for target, aliases := range w.execMD.HostAliases {
+	return nil
+}
+
+// resolveHostAliases looks up the IPs of each bound service's hostname,
+// trying each of the search domains in turn.
+func resolveHostAliases(hostAliases map[string][]string, searchDomains []string) (map[string][]net.IP, error) {
+	hostIPs := make(map[string][]net.IP, len(hostAliases))
+	for target := range hostAliases {
 		var ips []net.IP
 		var errs error
-		for _, domain := range append([]string{""}, extraSearchDomains...) {
+		for _, domain := range append([]string{""}, searchDomains...) {
 			qualified := target
 			if domain != "" {
 				qualified += "." + domain
@@ -285,19 +318,26 @@ func (w *Worker) setupNetwork(ctx context.Context, state *execState) error {
 			errs = errors.Join(errs, err)
 		}
 		if errs != nil {
-			return fmt.Errorf("lookup %s for hosts file: %w", target, errs)
+			return nil, fmt.Errorf("lookup %s for hosts file: %w", target, errs)
 		}
+		hostIPs[target] = ips
+	}
+	return hostIPs, nil
+}
 
-		for _, ip := range ips {
-			for _, alias := range aliases {
-				if _, err := fmt.Fprintf(ctrHostsFile, "\n%s\t%s\n", ip, alias); err != nil {
-					return fmt.Errorf("write hosts file: %w", err)
-				}
+// hostsFileContent appends an entry for each alias of each bound service to
+// the base hosts file.
+func hostsFileContent(baseHosts []byte, hostAliases map[string][]string, hostIPs map[string][]net.IP) []byte {
+	content := bytes.NewBuffer(slices.Clone(baseHosts))
+	targets := slices.Sorted(maps.Keys(hostAliases))
+	for _, target := range targets {
+		for _, ip := range hostIPs[target] {
+			for _, alias := range hostAliases[target] {
+				fmt.Fprintf(content, "\n%s\t%s\n", ip, alias)
 			}
 		}
 	}
-
-	return nil
+	return content.Bytes()
 }
 
 type hostBindMount struct {
@@ -544,6 +584,7 @@ func (w *Worker) setUserGroup(_ context.Context, state *execState) error {
 func (w *Worker) setExitCodePath(_ context.Context, state *execState) error {
 	if state.metaMount != nil {
 		state.exitCodePath = filepath.Join(state.metaMount.Source, MetaMountExitCodePath)
+		state.exitReasonPath = filepath.Join(state.metaMount.Source, MetaMountExitReasonPath)
 	}
 	return nil
 }
@@ -1241,5 +1282,18 @@ func (w *Worker) runContainer(ctx context.Context, state *execState) (rerr error
 		return err
 	}
 
-	return exitError(ctx, state.exitCodePath, w.callWithIO(ctx, state.procInfo, startedCallback, killer, runcCall), state.procInfo.Meta.ValidExitCodes)
+	runCtx, cancelRun := w.withExecTimeout(ctx)
+	defer cancelRun()
+
+	err = w.callWithIO(runCtx, state.procInfo, startedCallback, killer, runcCall)
+	var reason ExecExitReason
+	if err != nil {
+		reason = w.recordExitReason(runCtx, state, cgroupPath)
+	}
+	err = exitError(runCtx, state.exitCodePath, err, state.procInfo.Meta.ValidExitCodes)
+	if err != nil && reason != "" && state.exitReasonPath == "" {
+		// nowhere to record the reason (e.g. a service), so put it on the error
+		err = &ExitReasonError{Reason: reason, Err: err}
+	}
+	return err
 }
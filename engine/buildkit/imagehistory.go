@@ -0,0 +1,95 @@
+package buildkit
+
+import (
+	"context"
+	"fmt"
+
+	bkcache "github.com/moby/buildkit/cache"
+	bkgw "github.com/moby/buildkit/frontend/gateway/client"
+	bksolverpb "github.com/moby/buildkit/solver/pb"
+	specs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// ImageHistoryStep describes the layers of an exported image that were first
+// produced by a step of the container's build.
+type ImageHistoryStep struct {
+	// CreatedBy is the history entry's description of the step, e.g. the
+	// API field that produced the layers.
+	CreatedBy string
+	// Comment is the history entry's comment.
+	Comment string
+	// Definition is the container's rootfs after the step.
+	Definition *bksolverpb.Definition
+}
+
+// imageHistory returns a history entry for each layer of the ref, described
+// by the first step whose rootfs includes the layer. Layers that no step
+// includes are described by the operation that created them, like the
+// exporter does by default.
+func (c *Client) imageHistory(
+	ctx context.Context,
+	ref bkcache.ImmutableRef,
+	steps []ImageHistoryStep,
+) ([]specs.History, error) {
+	layerSteps := map[string]ImageHistoryStep{}
+	for _, step := range steps {
+		stepRef, err := c.solveRef(ctx, step.Definition)
+		if err != nil {
+			return nil, fmt.Errorf("failed to solve history step %q: %w", step.CreatedBy, err)
+		}
+		if stepRef == nil {
+			continue
+		}
+		chain := stepRef.LayerChain()
+		for _, layer := range chain {
+			if _, ok := layerSteps[layer.ID()]; !ok {
+				layerSteps[layer.ID()] = step
+			}
+		}
+		chain.Release(context.WithoutCancel(ctx))
+	}
+
+	return layerHistory(ref, layerSteps), nil
+}
+
+// layerHistory returns a history entry for each layer of the ref, from the
+// step of its ID if any.
+func layerHistory(ref bkcache.ImmutableRef, layerSteps map[string]ImageHistoryStep) []specs.History {
+	if ref == nil {
+		return nil
+	}
+	chain := ref.LayerChain()
+	defer chain.Release(context.TODO())
+
+	history := make([]specs.History, 0, len(chain))
+	for _, layer := range chain {
+		createdAt := layer.GetCreatedAt()
+		entry := specs.History{
+			Created:   &createdAt,
+			CreatedBy: layer.GetDescription(),
+		}
+		if step, ok := layerSteps[layer.ID()]; ok {
+			entry.CreatedBy = step.CreatedBy
+			entry.Comment = step.Comment
+		}
+		history = append(history, entry)
+	}
+	return history
+}
+
+// solveRef solves the definition and returns its immutable ref, which may be
+// nil for an empty filesystem.
+func (c *Client) solveRef(ctx context.Context, def *bksolverpb.Definition) (bkcache.ImmutableRef, error) {
+	res, err := c.Solve(ctx, bkgw.SolveRequest{
+		Definition: def,
+		Evaluate:   true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cacheRes, err := ConvertToWorkerCacheResult(ctx, res)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert result: %w", err)
+	}
+	return cacheRes.SingleRef()
+}
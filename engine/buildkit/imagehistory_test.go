@@ -0,0 +1,64 @@
+package buildkit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	bkcache "github.com/moby/buildkit/cache"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeLayer is a layer of a ref, whose chain are the layers below it and
+// itself.
+type fakeLayer struct {
+	bkcache.ImmutableRef
+
+	id          string
+	description string
+	createdAt   time.Time
+	parent      *fakeLayer
+}
+
+func (l *fakeLayer) ID() string                        { return l.id }
+func (l *fakeLayer) GetDescription() string            { return l.description }
+func (l *fakeLayer) GetCreatedAt() time.Time           { return l.createdAt }
+func (l *fakeLayer) Release(ctx context.Context) error { return nil }
+
+func (l *fakeLayer) LayerChain() bkcache.RefList {
+	var chain bkcache.RefList
+	if l.parent != nil {
+		chain = l.parent.LayerChain()
+	}
+	return append(chain, l)
+}
+
+func TestLayerHistory(t *testing.T) {
+	t.Parallel()
+
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	base := &fakeLayer{id: "base", description: "pulled from alpine", createdAt: created}
+	exec := &fakeLayer{id: "exec", description: "mount / from exec sh", createdAt: created.Add(time.Minute), parent: base}
+	copied := &fakeLayer{id: "copy", description: "copy /src /app", createdAt: created.Add(2 * time.Minute), parent: exec}
+
+	history := layerHistory(copied, map[string]ImageHistoryStep{
+		"base": {CreatedBy: "from", Comment: "sha256:aaa"},
+		"exec": {CreatedBy: "withExec", Comment: "sha256:bbb"},
+	})
+	require.Len(t, history, 3)
+
+	require.Equal(t, "from", history[0].CreatedBy)
+	require.Equal(t, "sha256:aaa", history[0].Comment)
+	require.Equal(t, created, *history[0].Created)
+
+	require.Equal(t, "withExec", history[1].CreatedBy)
+	require.Equal(t, "sha256:bbb", history[1].Comment)
+	require.Equal(t, created.Add(time.Minute), *history[1].Created)
+
+	// layers no step includes are described by their operation
+	require.Equal(t, "copy /src /app", history[2].CreatedBy)
+	require.Empty(t, history[2].Comment)
+	require.Equal(t, created.Add(2*time.Minute), *history[2].Created)
+
+	require.Nil(t, layerHistory(nil, nil))
+}
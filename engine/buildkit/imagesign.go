@@ -0,0 +1,339 @@
+package buildkit
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/containerd/containerd/content"
+	"github.com/distribution/reference"
+	"github.com/moby/buildkit/util/leaseutil"
+	"github.com/moby/buildkit/util/push"
+	"github.com/opencontainers/go-digest"
+	specs "github.com/opencontainers/image-spec/specs-go"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// cosignPayloadMediaType is the media type of the layers of a cosign
+	// signature image, holding the signed payloads.
+	cosignPayloadMediaType = "application/vnd.dev.cosign.simplesigning.v1+json"
+
+	// cosignSignatureAnnotation is the annotation of a cosign payload layer
+	// holding the base64 encoded signature of the payload.
+	cosignSignatureAnnotation = "dev.cosignproject.cosign/signature"
+
+	// cosignKindAnnotation marks the signature images in an OCI layout, the
+	// way "cosign save" does.
+	cosignKindAnnotation = "kind"
+	cosignSigsKind       = "dev.cosignproject.cosign/sigs"
+)
+
+// ParseSigningKey parses a PEM encoded private key to sign images with. It
+// accepts PKCS #8, PKCS #1 and SEC 1 keys, as well as the encrypted keys
+// generated by "cosign generate-key-pair", which are decrypted with password.
+func ParseSigningKey(pemBytes, password []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("invalid signing key: no PEM data found")
+	}
+
+	var key any
+	var err error
+	switch block.Type {
+	case "ENCRYPTED SIGSTORE PRIVATE KEY", "ENCRYPTED COSIGN PRIVATE KEY":
+		der, derr := decryptCosignKey(block.Bytes, password)
+		if derr != nil {
+			return nil, derr
+		}
+		key, err = x509.ParsePKCS8PrivateKey(der)
+	case "PRIVATE KEY":
+		key, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "EC PRIVATE KEY":
+		key, err = x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		key, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("invalid signing key: unsupported PEM type %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key: %w", err)
+	}
+
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, fmt.Errorf("invalid signing key: unsupported key type %T", key)
+	}
+	return signer, nil
+}
+
+// decryptCosignKey decrypts the DER bytes of a private key encrypted by
+// cosign, with scrypt and NaCl secretbox.
+func decryptCosignKey(data, password []byte) ([]byte, error) {
+	var enc struct {
+		KDF struct {
+			Name   string `json:"name"`
+			Params struct {
+				N int `json:"N"`
+				R int `json:"r"`
+				P int `json:"p"`
+			} `json:"params"`
+			Salt []byte `json:"salt"`
+		} `json:"kdf"`
+		Cipher struct {
+			Name  string `json:"name"`
+			Nonce []byte `json:"nonce"`
+		} `json:"cipher"`
+		Ciphertext []byte `json:"ciphertext"`
+	}
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return nil, fmt.Errorf("invalid signing key: %w", err)
+	}
+	if enc.KDF.Name != "scrypt" || enc.Cipher.Name != "nacl/secretbox" {
+		return nil, fmt.Errorf("invalid signing key: unsupported encryption %s/%s", enc.KDF.Name, enc.Cipher.Name)
+	}
+	if len(enc.Cipher.Nonce) != 24 {
+		return nil, errors.New("invalid signing key: invalid nonce")
+	}
+
+	secret, err := scrypt.Key(password, enc.KDF.Salt, enc.KDF.Params.N, enc.KDF.Params.R, enc.KDF.Params.P, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key: %w", err)
+	}
+	var nonce [24]byte
+	copy(nonce[:], enc.Cipher.Nonce)
+	var boxKey [32]byte
+	copy(boxKey[:], secret)
+
+	der, ok := secretbox.Open(nil, enc.Ciphertext, &nonce, &boxKey)
+	if !ok {
+		return nil, errors.New("failed to decrypt signing key: wrong password")
+	}
+	return der, nil
+}
+
+// SignatureTag is the tag of the cosign signature image of the image with the
+// given digest.
+func SignatureTag(dgst digest.Digest) string {
+	return fmt.Sprintf("%s-%s.sig", dgst.Algorithm(), dgst.Encoded())
+}
+
+// signPayload signs the payload the way cosign does: ed25519 keys sign the
+// payload itself, other keys sign its SHA-256 digest.
+func signPayload(signer crypto.Signer, payload []byte) ([]byte, error) {
+	if _, ok := signer.Public().(ed25519.PublicKey); ok {
+		return signer.Sign(rand.Reader, payload, crypto.Hash(0))
+	}
+	sum := sha256.Sum256(payload)
+	return signer.Sign(rand.Reader, sum[:], crypto.SHA256)
+}
+
+// signatureImage returns the blobs of the cosign signature image of the image
+// with the given digest, along with the descriptor of its manifest, which is
+// the last blob.
+func signatureImage(signer crypto.Signer, repo string, dgst digest.Digest) ([][]byte, ocispecs.Descriptor, error) {
+	// the "simple signing" payload that cosign signs
+	payload, err := json.Marshal(map[string]any{
+		"critical": map[string]any{
+			"identity": map[string]string{"docker-reference": repo},
+			"image":    map[string]string{"docker-manifest-digest": dgst.String()},
+			"type":     "cosign container image signature",
+		},
+		"optional": nil,
+	})
+	if err != nil {
+		return nil, ocispecs.Descriptor{}, err
+	}
+	sig, err := signPayload(signer, payload)
+	if err != nil {
+		return nil, ocispecs.Descriptor{}, fmt.Errorf("failed to sign image: %w", err)
+	}
+	payloadDesc := blobDescriptor(cosignPayloadMediaType, payload)
+	payloadDesc.Annotations = map[string]string{
+		cosignSignatureAnnotation: base64.StdEncoding.EncodeToString(sig),
+	}
+
+	config, err := json.Marshal(ocispecs.Image{
+		RootFS: ocispecs.RootFS{
+			Type:    "layers",
+			DiffIDs: []digest.Digest{payloadDesc.Digest},
+		},
+	})
+	if err != nil {
+		return nil, ocispecs.Descriptor{}, err
+	}
+
+	manifest, err := json.Marshal(ocispecs.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispecs.MediaTypeImageManifest,
+		Config:    blobDescriptor(ocispecs.MediaTypeImageConfig, config),
+		Layers:    []ocispecs.Descriptor{payloadDesc},
+	})
+	if err != nil {
+		return nil, ocispecs.Descriptor{}, err
+	}
+
+	return [][]byte{payload, config, manifest}, blobDescriptor(ocispecs.MediaTypeImageManifest, manifest), nil
+}
+
+func blobDescriptor(mediaType string, blob []byte) ocispecs.Descriptor {
+	return ocispecs.Descriptor{
+		MediaType: mediaType,
+		Digest:    digest.FromBytes(blob),
+		Size:      int64(len(blob)),
+	}
+}
+
+// pushImageSignature pushes the cosign signature of the image with the given
+// digest next to each of the comma separated image names.
+func (c *Client) pushImageSignature(ctx context.Context, names string, dgst digest.Digest, signer crypto.Signer) error {
+	ctx, done, err := leaseutil.WithLease(ctx, c.Worker.LeaseManager(), leaseutil.MakeTemporary)
+	if err != nil {
+		return err
+	}
+	defer done(context.WithoutCancel(ctx))
+
+	store := c.Worker.ContentStore()
+	for _, name := range strings.Split(names, ",") {
+		if name == "" {
+			continue
+		}
+		named, err := reference.ParseNormalizedNamed(name)
+		if err != nil {
+			return fmt.Errorf("failed to parse image name %q: %w", name, err)
+		}
+		repo := named.Name()
+
+		blobs, manifestDesc, err := signatureImage(signer, repo, dgst)
+		if err != nil {
+			return err
+		}
+		for _, blob := range blobs {
+			desc := blobDescriptor("", blob)
+			if err := content.WriteBlob(ctx, store, "dagger-signature-"+desc.Digest.String(), bytes.NewReader(blob), desc); err != nil {
+				return fmt.Errorf("failed to write signature blob: %w", err)
+			}
+		}
+
+		sigRef := repo + ":" + SignatureTag(dgst)
+		if err := push.Push(ctx, c.SessionManager, c.ID(), store, store, manifestDesc.Digest, sigRef, false, c.Worker.RegistryHosts, false, nil); err != nil {
+			return fmt.Errorf("failed to push signature %s: %w", sigRef, err)
+		}
+	}
+	return nil
+}
+
+// signTarball adds the cosign signature of the image with the given digest
+// to the OCI layout in the tarball at tarPath, the way "cosign save" does.
+func signTarball(tarPath string, dgst digest.Digest, signer crypto.Signer) error {
+	blobs, manifestDesc, err := signatureImage(signer, "", dgst)
+	if err != nil {
+		return err
+	}
+	manifestDesc.Annotations = map[string]string{
+		cosignKindAnnotation: cosignSigsKind,
+	}
+
+	src, err := os.Open(tarPath)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(tarPath + ".signed")
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	tr := tar.NewReader(src)
+	tw := tar.NewWriter(dst)
+	existing := map[string]bool{}
+	var index *ocispecs.Index
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read image tarball: %w", err)
+		}
+		existing[path.Clean(hdr.Name)] = true
+
+		if path.Clean(hdr.Name) == ocispecs.ImageIndexFile {
+			// written last, with the signature added
+			index = &ocispecs.Index{}
+			if err := json.NewDecoder(tr).Decode(index); err != nil {
+				return fmt.Errorf("failed to read image index: %w", err)
+			}
+			continue
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+	if index == nil {
+		return fmt.Errorf("image tarball has no %s", ocispecs.ImageIndexFile)
+	}
+
+	for _, blob := range blobs {
+		dgst := digest.FromBytes(blob)
+		name := path.Join(ocispecs.ImageBlobsDir, dgst.Algorithm().String(), dgst.Encoded())
+		if existing[name] {
+			continue
+		}
+		if err := writeTarFile(tw, name, blob); err != nil {
+			return err
+		}
+	}
+
+	index.Manifests = append(index.Manifests, manifestDesc)
+	indexBytes, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if err := writeTarFile(tw, ocispecs.ImageIndexFile, indexBytes); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tarPath+".signed", tarPath)
+}
+
+func writeTarFile(tw *tar.Writer, name string, data []byte) error {
+	if err := tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0o644,
+		Size:     int64(len(data)),
+	}); err != nil {
+		return err
+	}
+	_, err := tw.Write(data)
+	return err
+}
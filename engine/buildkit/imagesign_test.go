@@ -0,0 +1,264 @@
+package buildkit
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+func TestParseSigningKey(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	pkcs8, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	sec1, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	t.Run("pkcs8", func(t *testing.T) {
+		signer, err := ParseSigningKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}), nil)
+		require.NoError(t, err)
+		require.True(t, key.PublicKey.Equal(signer.Public()))
+	})
+
+	t.Run("sec1", func(t *testing.T) {
+		signer, err := ParseSigningKey(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: sec1}), nil)
+		require.NoError(t, err)
+		require.True(t, key.PublicKey.Equal(signer.Public()))
+	})
+
+	t.Run("encrypted cosign key", func(t *testing.T) {
+		encrypted := encryptCosignKey(t, pkcs8, []byte("hunter2"))
+
+		signer, err := ParseSigningKey(encrypted, []byte("hunter2"))
+		require.NoError(t, err)
+		require.True(t, key.PublicKey.Equal(signer.Public()))
+
+		_, err = ParseSigningKey(encrypted, []byte("wrong"))
+		require.EqualError(t, err, "failed to decrypt signing key: wrong password")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := ParseSigningKey([]byte("not a key"), nil)
+		require.EqualError(t, err, "invalid signing key: no PEM data found")
+
+		_, err = ParseSigningKey(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkcs8}), nil)
+		require.EqualError(t, err, `invalid signing key: unsupported PEM type "PUBLIC KEY"`)
+	})
+}
+
+// encryptCosignKey encrypts a PKCS #8 key the way "cosign generate-key-pair"
+// does, with cheaper scrypt parameters.
+func encryptCosignKey(t *testing.T, der, password []byte) []byte {
+	t.Helper()
+
+	salt := make([]byte, 32)
+	_, err := rand.Read(salt)
+	require.NoError(t, err)
+	var nonce [24]byte
+	_, err = rand.Read(nonce[:])
+	require.NoError(t, err)
+
+	secret, err := scrypt.Key(password, salt, 1024, 8, 1, 32)
+	require.NoError(t, err)
+	var boxKey [32]byte
+	copy(boxKey[:], secret)
+
+	data, err := json.Marshal(map[string]any{
+		"kdf": map[string]any{
+			"name":   "scrypt",
+			"params": map[string]int{"N": 1024, "r": 8, "p": 1},
+			"salt":   salt,
+		},
+		"cipher": map[string]any{
+			"name":  "nacl/secretbox",
+			"nonce": nonce[:],
+		},
+		"ciphertext": secretbox.Seal(nil, der, &nonce, &boxKey),
+	})
+	require.NoError(t, err)
+	return pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED SIGSTORE PRIVATE KEY", Bytes: data})
+}
+
+func TestSignatureTag(t *testing.T) {
+	t.Parallel()
+	dgst := digest.FromString("image")
+	require.Equal(t, "sha256-"+dgst.Encoded()+".sig", SignatureTag(dgst))
+}
+
+func TestSignatureImage(t *testing.T) {
+	t.Parallel()
+
+	dgst := digest.FromString("image")
+
+	t.Run("ecdsa", func(t *testing.T) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+
+		payload, sig := signatureImagePayload(t, key, dgst)
+		sum := sha256.Sum256(payload)
+		require.True(t, ecdsa.VerifyASN1(&key.PublicKey, sum[:], sig))
+	})
+
+	t.Run("ed25519", func(t *testing.T) {
+		pub, key, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+
+		payload, sig := signatureImagePayload(t, key, dgst)
+		require.True(t, ed25519.Verify(pub, payload, sig))
+	})
+}
+
+// signatureImagePayload returns the payload of the signature image of the
+// image with the given digest, checking its blobs, and its signature.
+func signatureImagePayload(t *testing.T, signer crypto.Signer, dgst digest.Digest) ([]byte, []byte) {
+	t.Helper()
+
+	blobs, manifestDesc, err := signatureImage(signer, "registry:5000/image", dgst)
+	require.NoError(t, err)
+	require.Len(t, blobs, 3)
+	require.Equal(t, digest.FromBytes(blobs[2]), manifestDesc.Digest)
+	require.Equal(t, ocispecs.MediaTypeImageManifest, manifestDesc.MediaType)
+
+	var manifest ocispecs.Manifest
+	require.NoError(t, json.Unmarshal(blobs[2], &manifest))
+	require.Equal(t, digest.FromBytes(blobs[1]), manifest.Config.Digest)
+	require.Len(t, manifest.Layers, 1)
+	require.Equal(t, cosignPayloadMediaType, manifest.Layers[0].MediaType)
+	require.Equal(t, digest.FromBytes(blobs[0]), manifest.Layers[0].Digest)
+
+	var payload struct {
+		Critical struct {
+			Identity struct {
+				DockerReference string `json:"docker-reference"`
+			} `json:"identity"`
+			Image struct {
+				DockerManifestDigest string `json:"docker-manifest-digest"`
+			} `json:"image"`
+		} `json:"critical"`
+	}
+	require.NoError(t, json.Unmarshal(blobs[0], &payload))
+	require.Equal(t, "registry:5000/image", payload.Critical.Identity.DockerReference)
+	require.Equal(t, dgst.String(), payload.Critical.Image.DockerManifestDigest)
+
+	sig, err := base64.StdEncoding.DecodeString(manifest.Layers[0].Annotations[cosignSignatureAnnotation])
+	require.NoError(t, err)
+	return blobs[0], sig
+}
+
+func TestSignTarball(t *testing.T) {
+	t.Parallel()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	imageManifest := []byte(`{"schemaVersion":2}`)
+	imageDesc := blobDescriptor(ocispecs.MediaTypeImageManifest, imageManifest)
+	index, err := json.Marshal(ocispecs.Index{Manifests: []ocispecs.Descriptor{imageDesc}})
+	require.NoError(t, err)
+
+	tarPath := filepath.Join(t.TempDir(), "image.tar")
+	f, err := os.Create(tarPath)
+	require.NoError(t, err)
+	tw := tar.NewWriter(f)
+	require.NoError(t, writeTarFile(tw, ocispecs.ImageLayoutFile, []byte(`{"imageLayoutVersion":"1.0.0"}`)))
+	require.NoError(t, writeTarFile(tw, ocispecs.ImageIndexFile, index))
+	require.NoError(t, writeTarFile(tw, path.Join("blobs/sha256", imageDesc.Digest.Encoded()), imageManifest))
+	require.NoError(t, tw.Close())
+	require.NoError(t, f.Close())
+
+	require.NoError(t, signTarball(tarPath, imageDesc.Digest, key))
+
+	entries := map[string][]byte{}
+	f, err = os.Open(tarPath)
+	require.NoError(t, err)
+	defer f.Close()
+	tr := tar.NewReader(f)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(tr)
+		require.NoError(t, err)
+		entries[hdr.Name] = buf.Bytes()
+	}
+	require.Contains(t, entries, ocispecs.ImageLayoutFile)
+	require.Equal(t, imageManifest, entries[path.Join("blobs/sha256", imageDesc.Digest.Encoded())])
+
+	var signedIndex ocispecs.Index
+	require.NoError(t, json.Unmarshal(entries[ocispecs.ImageIndexFile], &signedIndex))
+	require.Len(t, signedIndex.Manifests, 2)
+	require.Equal(t, imageDesc.Digest, signedIndex.Manifests[0].Digest)
+	sigDesc := signedIndex.Manifests[1]
+	require.Equal(t, cosignSigsKind, sigDesc.Annotations[cosignKindAnnotation])
+
+	var sigManifest ocispecs.Manifest
+	require.NoError(t, json.Unmarshal(entries[path.Join("blobs/sha256", sigDesc.Digest.Encoded())], &sigManifest))
+	require.Len(t, sigManifest.Layers, 1)
+	payload := entries[path.Join("blobs/sha256", sigManifest.Layers[0].Digest.Encoded())]
+	require.NotEmpty(t, payload)
+	sig, err := base64.StdEncoding.DecodeString(sigManifest.Layers[0].Annotations[cosignSignatureAnnotation])
+	require.NoError(t, err)
+	sum := sha256.Sum256(payload)
+	require.True(t, ecdsa.VerifyASN1(&key.PublicKey, sum[:], sig))
+}
+
+func TestParsePackageDB(t *testing.T) {
+	t.Parallel()
+
+	apkDB := []byte(`C:Q1abc=
+P:musl
+V:1.2.5-r0
+A:x86_64
+L:MIT
+
+P:busybox
+V:1.36.1-r29
+A:x86_64
+L:GPL-2.0-only
+`)
+	pkgs := parsePackageDB(apkDB, "apk", func(key, value string, pkg *sbomPackage) {
+		switch key {
+		case "P":
+			pkg.Name = value
+		case "V":
+			pkg.Version = value
+		case "A":
+			pkg.Arch = value
+		case "L":
+			pkg.License = value
+		}
+	})
+	require.Equal(t, []sbomPackage{
+		{Type: "apk", Name: "musl", Version: "1.2.5-r0", Arch: "x86_64", License: "MIT"},
+		{Type: "apk", Name: "busybox", Version: "1.36.1-r29", Arch: "x86_64", License: "GPL-2.0-only"},
+	}, pkgs)
+
+	require.Equal(t, "pkg:apk/alpine/musl@1.2.5-r0?arch=x86_64", packageURL(pkgs[0], "alpine"))
+	require.Equal(t, "pkg:deb/curl", packageURL(sbomPackage{Type: "deb", Name: "curl"}, ""))
+}
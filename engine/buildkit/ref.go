@@ -53,12 +53,13 @@ const (
 	MaxFileContentsSize = 128 << 20
 
 	// MetaMountDestPath is the special path that the shim writes metadata to.
-	MetaMountDestPath     = "/.dagger_meta_mount"
-	MetaMountExitCodePath = "exitCode"
-	MetaMountStdinPath    = "stdin"
-	MetaMountStdoutPath   = "stdout"
-	MetaMountStderrPath   = "stderr"
-	MetaMountClientIDPath = "clientID"
+	MetaMountDestPath       = "/.dagger_meta_mount"
+	MetaMountExitCodePath   = "exitCode"
+	MetaMountStdinPath      = "stdin"
+	MetaMountStdoutPath     = "stdout"
+	MetaMountStderrPath     = "stderr"
+	MetaMountClientIDPath   = "clientID"
+	MetaMountExitReasonPath = "exitReason"
 )
 
 type Result = solverresult.Result[*ref]
@@ -369,6 +370,10 @@ func WrapError(ctx context.Context, baseErr error, client *Client) error {
 			return errors.Join(err, baseErr)
 		}
 	}
+	reasonBytes, err := getExecMetaFile(ctx, client, mntable, MetaMountExitReasonPath)
+	if err != nil {
+		return errors.Join(err, baseErr)
+	}
 
 	// Start a debug container if the exec failed
 	if err := debugContainer(ctx, execOp.Exec, execErr, opErr, client); err != nil {
@@ -381,6 +386,7 @@ func WrapError(ctx context.Context, baseErr error, client *Client) error {
 		ExitCode: exitCode,
 		Stdout:   strings.TrimSpace(string(stdoutBytes)),
 		Stderr:   strings.TrimSpace(string(stderrBytes)),
+		Reason:   ExecExitReason(strings.TrimSpace(string(reasonBytes))),
 	}
 }
 
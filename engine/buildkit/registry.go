@@ -0,0 +1,179 @@
+package buildkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/containerd/containerd/content"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/distribution/reference"
+	bksession "github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/util/contentutil"
+	"github.com/moby/buildkit/util/resolver"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+// registryResolver returns a resolver pulling from the repository of the
+// image reference with the registry config and credentials of the client.
+func (c *Client) registryResolver(ref string) *resolver.Resolver {
+	return resolver.DefaultPool.GetResolver(c.Worker.RegistryHosts, ref, "pull", c.SessionManager, bksession.NewGroup(c.ID()))
+}
+
+// RegistryResolve returns the descriptor of the manifest or index the image
+// reference points to, without pulling it.
+func (c *Client) RegistryResolve(ctx context.Context, ref string) (ocispecs.Descriptor, error) {
+	_, desc, err := c.registryResolver(ref).Resolve(ctx, ref)
+	if err != nil {
+		return ocispecs.Descriptor{}, fmt.Errorf("failed to resolve %s: %w", ref, err)
+	}
+	return desc, nil
+}
+
+// RegistryProvider returns a content provider fetching the blobs of the
+// repository of the image reference from its registry.
+func (c *Client) RegistryProvider(ctx context.Context, ref string) (content.Provider, error) {
+	fetcher, err := c.registryResolver(ref).Fetcher(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get fetcher for %s: %w", ref, err)
+	}
+	return contentutil.FromFetcher(fetcher), nil
+}
+
+// RegistryTags lists the tags of the repository of the image reference.
+func (c *Client) RegistryTags(ctx context.Context, ref string) ([]string, error) {
+	named, err := reference.ParseNormalizedNamed(ref)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse image name %q: %w", ref, err)
+	}
+	domain, repo := reference.Domain(named), reference.Path(named)
+
+	hosts, err := c.registryResolver(named.Name()).HostsFunc(domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registry hosts for %s: %w", domain, err)
+	}
+
+	ctx = docker.WithScope(ctx, "repository:"+repo+":pull")
+
+	// try the hosts in order, like the resolver does, so that mirrors are
+	// preferred over the upstream registry
+	var lastErr error
+	for _, host := range hosts {
+		if !host.Capabilities.Has(docker.HostCapabilityResolve) {
+			continue
+		}
+		tags, err := listRegistryTags(ctx, host, repo)
+		if err == nil {
+			return tags, nil
+		}
+		lastErr = err
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no registry host for %s", domain)
+	}
+	return nil, fmt.Errorf("failed to list tags of %s: %w", named.Name(), lastErr)
+}
+
+// maxTagListSize is the maximum size of a page of a tag list response.
+const maxTagListSize = 16 << 20
+
+// listRegistryTags lists the tags of the repository on the host, following
+// the pagination links of the distribution API.
+func listRegistryTags(ctx context.Context, host docker.RegistryHost, repo string) ([]string, error) {
+	base := &url.URL{
+		Scheme: host.Scheme,
+		Host:   host.Host,
+		Path:   path.Join(host.Path, repo, "tags", "list"),
+	}
+
+	var tags []string
+	next := base
+	for next != nil {
+		resp, err := registryGet(ctx, host, next.String())
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Tags []string `json:"tags"`
+		}
+		err = json.NewDecoder(io.LimitReader(resp.Body, maxTagListSize)).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode tag list: %w", err)
+		}
+		tags = append(tags, page.Tags...)
+
+		next, err = nextLink(base, resp.Header.Get("Link"))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tags, nil
+}
+
+// registryGet sends a GET request to the registry host, authorizing it with
+// the host's authorizer, which may require a first unauthorized response.
+func registryGet(ctx context.Context, host docker.RegistryHost, u string) (*http.Response, error) {
+	client := host.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range host.Header {
+			req.Header[k] = v
+		}
+		req.Header.Set("Accept", "application/json")
+		if host.Authorizer != nil {
+			if err := host.Authorizer.Authorize(ctx, req); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 && host.Authorizer != nil {
+			err := host.Authorizer.AddResponses(ctx, []*http.Response{resp})
+			resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			resp.Body.Close()
+			return nil, fmt.Errorf("unexpected status from GET %s: %s: %s", u, resp.Status, strings.TrimSpace(string(body)))
+		}
+		return resp, nil
+	}
+}
+
+// nextLink returns the URL of the next page of a paginated response from its
+// Link header, e.g. `</v2/foo/tags/list?last=bar&n=100>; rel="next"`, or nil
+// if it's the last page.
+func nextLink(base *url.URL, header string) (*url.URL, error) {
+	for _, link := range strings.Split(header, ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+		if !ok || !strings.Contains(strings.ReplaceAll(params, " ", ""), `rel="next"`) {
+			continue
+		}
+		target = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(target), "<"), ">")
+		u, err := base.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Link header %q: %w", header, err)
+		}
+		return u, nil
+	}
+	return nil, nil
+}
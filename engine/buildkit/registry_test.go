@@ -0,0 +1,124 @@
+package buildkit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/stretchr/testify/require"
+)
+
+// tokenAuthorizer authorizes requests with a token once it got an
+// unauthorized response.
+type tokenAuthorizer struct {
+	challenged bool
+}
+
+func (a *tokenAuthorizer) Authorize(ctx context.Context, req *http.Request) error {
+	if a.challenged {
+		req.Header.Set("Authorization", "Bearer token")
+	}
+	return nil
+}
+
+func (a *tokenAuthorizer) AddResponses(ctx context.Context, responses []*http.Response) error {
+	a.challenged = true
+	return nil
+}
+
+func TestListRegistryTags(t *testing.T) {
+	t.Parallel()
+
+	pages := map[string]struct {
+		tags []string
+		next string
+	}{
+		"":    {tags: []string{"1.0", "1.1"}, next: "/v2/library/alpine/tags/list?last=1.1&n=2"},
+		"1.1": {tags: []string{"2.0"}},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v2/library/alpine/tags/list" {
+			http.NotFound(w, r)
+			return
+		}
+		page := pages[r.URL.Query().Get("last")]
+		if page.next != "" {
+			w.Header().Set("Link", "<"+page.next+`>; rel="next"`)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"name": "library/alpine",
+			"tags": page.tags,
+		})
+	}))
+	defer srv.Close()
+
+	srvURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	tags, err := listRegistryTags(context.Background(), docker.RegistryHost{
+		Client:       srv.Client(),
+		Authorizer:   &tokenAuthorizer{},
+		Host:         srvURL.Host,
+		Scheme:       srvURL.Scheme,
+		Path:         "/v2",
+		Capabilities: docker.HostCapabilityPull | docker.HostCapabilityResolve,
+	}, "library/alpine")
+	require.NoError(t, err)
+	require.Equal(t, []string{"1.0", "1.1", "2.0"}, tags)
+}
+
+func TestListRegistryTagsError(t *testing.T) {
+	t.Parallel()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"errors":[{"code":"NAME_UNKNOWN"}]}`, http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	srvURL, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+
+	_, err = listRegistryTags(context.Background(), docker.RegistryHost{
+		Client: srv.Client(),
+		Host:   srvURL.Host,
+		Scheme: srvURL.Scheme,
+		Path:   "/v2",
+	}, "nope")
+	require.ErrorContains(t, err, "404 Not Found")
+	require.ErrorContains(t, err, "NAME_UNKNOWN")
+}
+
+func TestNextLink(t *testing.T) {
+	t.Parallel()
+
+	base, err := url.Parse("https://registry.example.com/v2/foo/tags/list")
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		header string
+		want   string
+	}{
+		{"", ""},
+		{`</v2/foo/tags/list?last=b&n=2>; rel="next"`, "https://registry.example.com/v2/foo/tags/list?last=b&n=2"},
+		{`<https://other.example.com/v2/foo/tags/list?last=b>; rel="next"`, "https://other.example.com/v2/foo/tags/list?last=b"},
+		{`</v2/foo/tags/list?last=a>; rel="prev", </v2/foo/tags/list?last=c>; rel="next"`, "https://registry.example.com/v2/foo/tags/list?last=c"},
+		{`</v2/foo/tags/list?last=a>; rel="prev"`, ""},
+	} {
+		next, err := nextLink(base, tc.header)
+		require.NoError(t, err)
+		if tc.want == "" {
+			require.Nil(t, next, tc.header)
+		} else {
+			require.Equal(t, tc.want, next.String(), tc.header)
+		}
+	}
+}
@@ -15,6 +15,7 @@ import (
 const (
 	memoryCurrentFile = "memory.current"
 	memoryPeakFile    = "memory.peak"
+	memoryEventsFile  = "memory.events"
 )
 
 type memoryCurrentSampler struct {
@@ -106,3 +107,22 @@ func (s *memoryPeakSampler) sample(ctx context.Context) error {
 
 	return nil
 }
+
+// OOMKilled returns true if any process in the given cgroup was killed by the
+// OOM killer.
+func OOMKilled(cgroupNSSubpath string) (bool, error) {
+	eventsPath := filepath.Join(defaultMountpoint, cgroupNSSubpath, memoryEventsFile)
+	bs, err := os.ReadFile(eventsPath)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to read %s: %w", eventsPath, err)
+	}
+	for key, value := range flatKeyValuesInt64(bs) {
+		if key == "oom_kill" && value > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
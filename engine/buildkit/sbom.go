@@ -0,0 +1,307 @@
+package buildkit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1" //nolint:gosec // SPDX requires SHA-1 file checksums
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	continuityfs "github.com/containerd/continuity/fs"
+	bkcache "github.com/moby/buildkit/cache"
+	"github.com/moby/buildkit/identity"
+	bksession "github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/snapshot"
+	spdxjson "github.com/spdx/tools-golang/json"
+	"github.com/spdx/tools-golang/spdx"
+	"github.com/spdx/tools-golang/spdx/v2/common"
+
+	"github.com/dagger/dagger/engine"
+)
+
+// sbomCoreName is the name of the SBOM attestation describing the image
+// itself; buildkit annotates the files of this SBOM with their layers.
+const sbomCoreName = "sbom"
+
+const spdxNoAssertion = "NOASSERTION"
+
+// sbomPackage is a package found in the database of an OS package manager.
+type sbomPackage struct {
+	Type    string
+	Name    string
+	Version string
+	Arch    string
+	License string
+}
+
+// imageSBOM generates an SPDX SBOM of the filesystem of an image, listing the
+// packages installed by its OS package manager and its files.
+func imageSBOM(ctx context.Context, ref bkcache.ImmutableRef, s bksession.Group, name string) ([]byte, error) {
+	doc := &spdx.Document{
+		SPDXVersion:       spdx.Version,
+		DataLicense:       spdx.DataLicense,
+		SPDXIdentifier:    "DOCUMENT",
+		DocumentName:      name,
+		DocumentNamespace: "https://dagger.io/spdx/" + identity.NewID(),
+		CreationInfo: &spdx.CreationInfo{
+			Creators: []common.Creator{{
+				CreatorType: "Tool",
+				Creator:     "dagger-" + engine.Version,
+			}},
+			Created: time.Now().UTC().Format(time.RFC3339),
+		},
+	}
+
+	root := &spdx.Package{
+		PackageName:               name,
+		PackageSPDXIdentifier:     "Package-image",
+		PackageDownloadLocation:   spdxNoAssertion,
+		IsFilesAnalyzedTagPresent: true,
+		PrimaryPackagePurpose:     "CONTAINER",
+	}
+	doc.Packages = append(doc.Packages, root)
+	doc.Relationships = append(doc.Relationships, &spdx.Relationship{
+		RefA:         common.MakeDocElementID("", "DOCUMENT"),
+		RefB:         common.MakeDocElementID("", string(root.PackageSPDXIdentifier)),
+		Relationship: common.TypeRelationshipDescribe,
+	})
+
+	if ref == nil {
+		// an empty image
+		return encodeSBOM(doc)
+	}
+
+	mount, err := ref.Mount(ctx, true, s)
+	if err != nil {
+		return nil, err
+	}
+	lm := snapshot.LocalMounter(mount)
+	dir, err := lm.Mount()
+	if err != nil {
+		return nil, err
+	}
+	defer lm.Unmount()
+
+	pkgs, err := osPackages(dir)
+	if err != nil {
+		return nil, err
+	}
+	distro := osReleaseID(dir)
+	for i, pkg := range pkgs {
+		license := pkg.License
+		if license == "" {
+			license = spdxNoAssertion
+		}
+		p := &spdx.Package{
+			PackageName:               pkg.Name,
+			PackageSPDXIdentifier:     common.ElementID(fmt.Sprintf("Package-%s-%d", pkg.Type, i)),
+			PackageVersion:            pkg.Version,
+			PackageDownloadLocation:   spdxNoAssertion,
+			IsFilesAnalyzedTagPresent: true,
+			PackageLicenseDeclared:    license,
+			PackageExternalReferences: []*spdx.PackageExternalReference{{
+				Category: common.CategoryPackageManager,
+				RefType:  common.TypePackageManagerPURL,
+				Locator:  packageURL(pkg, distro),
+			}},
+		}
+		doc.Packages = append(doc.Packages, p)
+		doc.Relationships = append(doc.Relationships, &spdx.Relationship{
+			RefA:         common.MakeDocElementID("", string(root.PackageSPDXIdentifier)),
+			RefB:         common.MakeDocElementID("", string(p.PackageSPDXIdentifier)),
+			Relationship: common.TypeRelationshipContains,
+		})
+	}
+
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		sha1Sum, sha256Sum, err := fileChecksums(p)
+		if err != nil {
+			return err
+		}
+		doc.Files = append(doc.Files, &spdx.File{
+			FileName:           "/" + filepath.ToSlash(rel),
+			FileSPDXIdentifier: common.ElementID(fmt.Sprintf("File-%d", len(doc.Files))),
+			Checksums: []common.Checksum{
+				{Algorithm: common.SHA1, Value: sha1Sum},
+				{Algorithm: common.SHA256, Value: sha256Sum},
+			},
+			LicenseConcluded:  spdxNoAssertion,
+			FileCopyrightText: spdxNoAssertion,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	return encodeSBOM(doc)
+}
+
+func encodeSBOM(doc *spdx.Document) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := spdxjson.Write(doc, &buf); err != nil {
+		return nil, fmt.Errorf("failed to encode SBOM: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func fileChecksums(p string) (string, string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+	h1 := sha1.New() //nolint:gosec // SPDX requires SHA-1 file checksums
+	h256 := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(h1, h256), f); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(h1.Sum(nil)), hex.EncodeToString(h256.Sum(nil)), nil
+}
+
+// osPackages lists the packages installed by apk or dpkg in the filesystem
+// at dir.
+func osPackages(dir string) ([]sbomPackage, error) {
+	var pkgs []sbomPackage
+	apkDB, err := readPackageDB(dir, "lib/apk/db/installed")
+	if err != nil {
+		return nil, err
+	}
+	pkgs = append(pkgs, parsePackageDB(apkDB, "apk", func(key, value string, pkg *sbomPackage) {
+		switch key {
+		case "P":
+			pkg.Name = value
+		case "V":
+			pkg.Version = value
+		case "A":
+			pkg.Arch = value
+		case "L":
+			pkg.License = value
+		}
+	})...)
+
+	dpkgDB, err := readPackageDB(dir, "var/lib/dpkg/status")
+	if err != nil {
+		return nil, err
+	}
+	pkgs = append(pkgs, parsePackageDB(dpkgDB, "deb", func(key, value string, pkg *sbomPackage) {
+		switch key {
+		case "Package":
+			pkg.Name = value
+		case "Version":
+			pkg.Version = value
+		case "Architecture":
+			pkg.Arch = value
+		case "Status":
+			if !strings.HasSuffix(value, " installed") {
+				// not (or no longer) installed
+				pkg.Name = ""
+			}
+		}
+	})...)
+	return pkgs, nil
+}
+
+func readPackageDB(dir, dbPath string) ([]byte, error) {
+	p, err := continuityfs.RootPath(dir, dbPath)
+	if err != nil {
+		return nil, err
+	}
+	dt, err := os.ReadFile(p)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return dt, err
+}
+
+// parsePackageDB parses the "key: value" paragraphs of an apk or dpkg
+// database, one paragraph per package. Continuation lines are ignored.
+func parsePackageDB(db []byte, typ string, set func(key, value string, pkg *sbomPackage)) []sbomPackage {
+	var pkgs []sbomPackage
+	pkg := sbomPackage{Type: typ}
+	flush := func() {
+		if pkg.Name != "" {
+			pkgs = append(pkgs, pkg)
+		}
+		pkg = sbomPackage{Type: typ}
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(db))
+	scanner.Buffer(nil, 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			flush()
+			continue
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		set(key, strings.TrimSpace(value), &pkg)
+	}
+	flush()
+	return pkgs
+}
+
+// osReleaseID returns the ID of the distribution of the filesystem at dir.
+func osReleaseID(dir string) string {
+	for _, p := range []string{"etc/os-release", "usr/lib/os-release"} {
+		p, err := continuityfs.RootPath(dir, p)
+		if err != nil {
+			continue
+		}
+		dt, err := os.ReadFile(p)
+		if err != nil {
+			continue
+		}
+		for _, line := range strings.Split(string(dt), "\n") {
+			if id, ok := strings.CutPrefix(line, "ID="); ok {
+				return strings.Trim(id, `"'`)
+			}
+		}
+	}
+	return ""
+}
+
+// packageURL returns the package URL of a package, e.g.
+// "pkg:apk/alpine/musl@1.2.5-r0?arch=x86_64".
+func packageURL(pkg sbomPackage, distro string) string {
+	purl := "pkg:" + pkg.Type + "/"
+	if distro != "" {
+		purl += url.PathEscape(distro) + "/"
+	}
+	purl += url.PathEscape(pkg.Name)
+	if pkg.Version != "" {
+		purl += "@" + url.PathEscape(pkg.Version)
+	}
+	if pkg.Arch != "" {
+		purl += "?arch=" + url.QueryEscape(pkg.Arch)
+	}
+	return purl
+}
@@ -484,83 +484,3 @@ func BenchmarkScrubSecret(b *testing.B) {
 
 	wg.Wait()
 }
-
-func TestTrie(t *testing.T) {
-	trie := Trie{}
-
-	trie.Insert([]byte("foo"), []byte("bar"))
-	fmt.Println(trie)
-	require.Equal(t, []byte("bar"), trie.Step('f').Step('o').Step('o').Value())
-	require.Nil(t, trie.Step('f').Step('o').Value())
-
-	trie.Insert([]byte("fox"), []byte("bax"))
-	fmt.Println(trie)
-	require.Equal(t, []byte("bar"), trie.Step('f').Step('o').Step('o').Value())
-	require.Equal(t, []byte("bax"), trie.Step('f').Step('o').Step('x').Value())
-
-	trie.Insert([]byte("fax"), []byte("brx"))
-	fmt.Println(trie)
-	require.Equal(t, []byte("bar"), trie.Step('f').Step('o').Step('o').Value())
-	require.Equal(t, []byte("bax"), trie.Step('f').Step('o').Step('x').Value())
-	require.Equal(t, []byte("brx"), trie.Step('f').Step('a').Step('x').Value())
-}
-
-func TestTrieExtend(t *testing.T) {
-	trie := Trie{}
-	trie.Insert([]byte("foo"), []byte("bar"))
-	trie.Insert([]byte("foob"), []byte("bax"))
-	fmt.Println(trie)
-	require.Equal(t, []byte("bar"), trie.Step('f').Step('o').Step('o').Value())
-	require.Equal(t, []byte("bax"), trie.Step('f').Step('o').Step('o').Step('b').Value())
-
-	trie = Trie{}
-	trie.Insert([]byte("foob"), []byte("bax"))
-	trie.Insert([]byte("foo"), []byte("bar"))
-	fmt.Println(trie)
-	require.Equal(t, []byte("bar"), trie.Step('f').Step('o').Step('o').Value())
-	require.Equal(t, []byte("bax"), trie.Step('f').Step('o').Step('o').Step('b').Value())
-
-	trie = Trie{}
-	trie.Insert([]byte("foo"), []byte("bar"))
-	trie.Insert([]byte("foobar"), []byte("qux"))
-	trie.Insert([]byte("foob"), []byte("bax"))
-	fmt.Println(trie)
-	require.Equal(t, []byte("bar"), trie.Step('f').Step('o').Step('o').Value())
-	require.Equal(t, []byte("bax"), trie.Step('f').Step('o').Step('o').Step('b').Value())
-	require.Equal(t, []byte("qux"), trie.Step('f').Step('o').Step('o').Step('b').Step('a').Step('r').Value())
-
-	trie = Trie{}
-	trie.Insert([]byte("foo"), []byte("bar"))
-	trie.Insert([]byte("foob"), []byte("bax"))
-	trie.Insert([]byte("foobar"), []byte("qux"))
-	fmt.Println(trie)
-	require.Equal(t, []byte("bar"), trie.Step('f').Step('o').Step('o').Value())
-	require.Equal(t, []byte("bax"), trie.Step('f').Step('o').Step('o').Step('b').Value())
-	require.Equal(t, []byte("qux"), trie.Step('f').Step('o').Step('o').Step('b').Step('a').Step('r').Value())
-
-	trie = Trie{}
-	trie.Insert([]byte("foo1"), []byte("bar"))
-	trie.Insert([]byte("foo2"), []byte("bax"))
-	trie.Insert([]byte("fax"), []byte("qux"))
-	fmt.Println(trie)
-	require.Equal(t, []byte("bar"), trie.Step('f').Step('o').Step('o').Step('1').Value())
-	require.Equal(t, []byte("bax"), trie.Step('f').Step('o').Step('o').Step('2').Value())
-	require.Equal(t, []byte("qux"), trie.Step('f').Step('a').Step('x').Value())
-}
-
-func TestTrieReinsert(t *testing.T) {
-	trie := Trie{}
-
-	trie.Insert([]byte("foo"), []byte("bar"))
-	require.Equal(t, []byte("bar"), trie.Step('f').Step('o').Step('o').Value())
-	before := trie.String()
-
-	trie.Insert([]byte("foo"), []byte("bar"))
-	require.Equal(t, []byte("bar"), trie.Step('f').Step('o').Step('o').Value())
-	after := trie.String()
-
-	require.Equal(t, before, after)
-
-	trie.Insert([]byte("foo"), []byte("baz"))
-	require.Equal(t, []byte("baz"), trie.Step('f').Step('o').Step('o').Value())
-}
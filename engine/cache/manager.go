@@ -47,6 +47,10 @@ type ManagerConfig struct {
 	ServiceURL   string
 	Token        string
 	EngineID     string
+
+	// Storage, if set, configures a remote cache that's stored directly in a
+	// registry or bucket rather than going through the cache service.
+	Storage *StorageConfig
 }
 
 const (
@@ -75,16 +79,25 @@ func NewManager(ctx context.Context, managerConfig ManagerConfig) (Manager, erro
 		httpClient:    &http.Client{},
 	}
 
-	if managerConfig.Token == "" {
+	switch {
+	case managerConfig.Storage != nil:
+		bklog.G(ctx).Debugf("using %s cache storage", managerConfig.Storage.Type)
+		storageService, err := newStorageService(ctx, *managerConfig.Storage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to init %s cache storage: %w", managerConfig.Storage.Type, err)
+		}
+		m.cacheClient = storageService
+		m.httpClient = storageService.HTTPClient()
+	case managerConfig.Token != "":
+		bklog.G(ctx).Debugf("using cache service at %s", managerConfig.ServiceURL)
+		serviceClient, err := newClient(managerConfig.ServiceURL, managerConfig.Token)
+		if err != nil {
+			return nil, err
+		}
+		m.cacheClient = serviceClient
+	default:
 		return defaultCacheManager{m.localCache}, nil
 	}
-	bklog.G(ctx).Debugf("using cache service at %s", managerConfig.ServiceURL)
-
-	serviceClient, err := newClient(managerConfig.ServiceURL, managerConfig.Token)
-	if err != nil {
-		return nil, err
-	}
-	m.cacheClient = serviceClient
 	m.layerProvider = &layerProvider{
 		httpClient:  m.httpClient,
 		cacheClient: m.cacheClient,
@@ -495,27 +508,9 @@ func (m *manager) ReleaseUnreferenced(ctx context.Context) error {
 }
 
 func (m *manager) descriptorProviderPair(layerMetadata remotecache.CacheLayer) (*remotecache.DescriptorProviderPair, error) {
-	if layerMetadata.Annotations == nil {
-		return nil, fmt.Errorf("missing annotations for layer %s", layerMetadata.Blob)
-	}
-
-	annotations := map[string]string{}
-	if layerMetadata.Annotations.DiffID == "" {
-		return nil, fmt.Errorf("missing diffID for layer %s", layerMetadata.Blob)
-	}
-	annotations["containerd.io/uncompressed"] = layerMetadata.Annotations.DiffID.String()
-	if !layerMetadata.Annotations.CreatedAt.IsZero() {
-		createdAt, err := layerMetadata.Annotations.CreatedAt.MarshalText()
-		if err != nil {
-			return nil, err
-		}
-		annotations["buildkit/createdat"] = string(createdAt)
-	}
-	desc := ocispecs.Descriptor{
-		MediaType:   layerMetadata.Annotations.MediaType,
-		Digest:      layerMetadata.Blob,
-		Size:        layerMetadata.Annotations.Size,
-		Annotations: annotations,
+	desc, err := layerDescriptor(layerMetadata)
+	if err != nil {
+		return nil, err
 	}
 	return &remotecache.DescriptorProviderPair{
 		Provider:   m.layerProvider,
@@ -153,18 +153,19 @@ func (m *manager) StartCacheMountSynchronization(ctx context.Context) error {
 					}
 					defer contentReaderAt.Close()
 					contentLength := contentReaderAt.Size()
-					getURLResp, err := m.cacheClient.GetCacheMountUploadURL(ctx, GetCacheMountUploadURLRequest{
+					uploadReq := GetCacheMountUploadURLRequest{
 						CacheName: cacheMountName,
 						Digest:    contentDigest,
 						Size:      contentLength,
-					})
+					}
+					getURLResp, err := m.cacheClient.GetCacheMountUploadURL(ctx, uploadReq)
 					if err != nil {
 						return fmt.Errorf("failed to get cache mount upload url: %w", err)
 					}
 
 					if getURLResp.Skip {
 						bklog.G(ctx).Debugf("skipped pushing cache mount %s", cacheMountName)
-						return nil
+						return m.recordCacheMount(ctx, uploadReq)
 					}
 
 					contentReader := io.NewSectionReader(contentReaderAt, 0, contentLength)
@@ -181,12 +182,12 @@ func (m *manager) StartCacheMountSynchronization(ctx context.Context) error {
 						return fmt.Errorf("failed to upload cache mount: %w", err)
 					}
 					defer resp.Body.Close()
-					if resp.StatusCode != http.StatusOK {
-						return fmt.Errorf("failed to upload cache mount: %s", resp.Status)
+					if err := checkResponse(resp); err != nil {
+						return fmt.Errorf("failed to upload cache mount: %w", err)
 					}
 
 					bklog.G(ctx).Debugf("synced cache mount remotely %s", cacheMountName)
-					return nil
+					return m.recordCacheMount(ctx, uploadReq)
 				})
 			})
 		}
@@ -196,6 +197,19 @@ func (m *manager) StartCacheMountSynchronization(ctx context.Context) error {
 	return nil
 }
 
+// recordCacheMount tells the cache service about a cache mount that's been
+// uploaded, if it needs to be told.
+func (m *manager) recordCacheMount(ctx context.Context, req GetCacheMountUploadURLRequest) error {
+	recorder, ok := m.cacheClient.(cacheMountRecorder)
+	if !ok {
+		return nil
+	}
+	if err := recorder.RecordCacheMount(ctx, req); err != nil {
+		return fmt.Errorf("failed to record cache mount: %w", err)
+	}
+	return nil
+}
+
 func cacheKeyFromMountName(name string) string {
 	// Turn the human-readable name into the key we use internally
 	// NOTE: this will be problematic if backwards incompatible changes are made
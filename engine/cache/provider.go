@@ -100,6 +100,10 @@ func (r *urlReaderAt) ReadAt(p []byte, off int64) (int, error) {
 		if err != nil {
 			return 0, err
 		}
+		if err := checkResponse(resp); err != nil {
+			resp.Body.Close()
+			return 0, err
+		}
 
 		if r.body != nil {
 			// close previous body if we had to reset due to non-sequential read
@@ -0,0 +1,593 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	remotecache "github.com/moby/buildkit/cache/remotecache/v1"
+	"github.com/moby/buildkit/solver"
+	"github.com/moby/buildkit/util/bklog"
+	"github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+/*
+A storage-backed cache implements the same Service the engine uses to talk to the cloud cache
+service, but runs the service side of it inside the engine and keeps all of its state in a blob
+store the engine talks to directly (an OCI registry or an S3-compatible bucket).
+
+The store holds:
+  - the layer blobs of exported cache refs and cache mounts, addressed by digest
+  - a single index, which contains the buildkit cache config that the engine imports and the
+    list of synced cache mounts
+
+On export, the engine's cache records are merged into the cache config already in the index, so
+that multiple engines can share one store. Concurrent writers are last-writer-wins, which at worst
+drops records that the losing engine will write again on its next export.
+
+So that the index doesn't grow forever, results and cache mounts that are older than MaxAge are
+pruned from it whenever it's written. The exporting engine's own records are always written back,
+so only state that no running engine holds anymore ages out.
+*/
+
+const (
+	StorageTypeRegistry = "registry"
+	StorageTypeS3       = "s3"
+
+	defaultStorageImportPeriod  = 5 * time.Minute
+	defaultStorageExportPeriod  = 5 * time.Minute
+	defaultStorageExportTimeout = 10 * time.Minute
+	defaultStorageMaxAge        = 7 * 24 * time.Hour
+)
+
+// StorageConfig configures a remote cache that the engine stores directly in
+// an OCI registry or an S3-compatible bucket, without a cache service.
+type StorageConfig struct {
+	// Type is either StorageTypeRegistry or StorageTypeS3.
+	Type string
+
+	// Ref is the registry reference to store the cache under, e.g.
+	// "registry.example.com/dagger/cache:main". Only used by the registry type.
+	Ref string
+	// Insecure allows connecting to the registry over plain HTTP.
+	Insecure bool
+
+	// Endpoint is the URL of an S3-compatible server, e.g. a MinIO instance.
+	// If unset, AWS S3 is used.
+	Endpoint string
+	// Bucket is the name of the S3 bucket.
+	Bucket string
+	// Prefix is prepended to the key of every object in the bucket.
+	Prefix string
+	// Region is the S3 region, defaulting to the AWS SDK's configuration.
+	Region string
+
+	ImportPeriod  time.Duration
+	ExportPeriod  time.Duration
+	ExportTimeout time.Duration
+	// MaxAge is how long results and cache mounts are kept in the index after
+	// they were last exported.
+	MaxAge time.Duration
+}
+
+// blobStore is the storage a storageService keeps its blobs and index in.
+type blobStore interface {
+	// HTTPClient returns the client to use for the URLs returned by the store.
+	HTTPClient() *http.Client
+
+	BlobExists(ctx context.Context, dgst digest.Digest) (bool, error)
+	BlobDownloadURL(ctx context.Context, dgst digest.Digest) (string, error)
+	BlobUploadURL(ctx context.Context, dgst digest.Digest) (string, map[string]string, error)
+
+	// ReadIndex returns the index last written with WriteIndex, or nil if
+	// there is none.
+	ReadIndex(ctx context.Context) ([]byte, error)
+	// WriteIndex replaces the index. The given blobs are the ones referenced by
+	// the index, for stores that track references to blobs.
+	WriteIndex(ctx context.Context, data []byte, blobs []ocispecs.Descriptor) error
+}
+
+func newBlobStore(ctx context.Context, config StorageConfig) (blobStore, error) {
+	switch config.Type {
+	case StorageTypeRegistry:
+		return newRegistryStore(ctx, config)
+	case StorageTypeS3:
+		return newS3Store(ctx, config)
+	default:
+		return nil, fmt.Errorf("unknown cache storage type %q", config.Type)
+	}
+}
+
+type storageIndex struct {
+	Cache       remotecache.CacheConfig `json:"cache"`
+	CacheMounts []storageCacheMount     `json:"cacheMounts,omitempty"`
+}
+
+type storageCacheMount struct {
+	Name      string        `json:"name"`
+	Digest    digest.Digest `json:"digest"`
+	Size      int64         `json:"size"`
+	MediaType string        `json:"mediaType"`
+	UpdatedAt time.Time     `json:"updatedAt,omitempty"`
+}
+
+type storageService struct {
+	config StorageConfig
+	store  blobStore
+
+	mu sync.Mutex
+	// the engine's cache records as of the last call to UpdateCacheRecords
+	keys  []CacheKey
+	links []Link
+	// layers of the results that have been pushed, keyed by the digest of the
+	// result ID
+	results map[digest.Digest][]ocispecs.Descriptor
+}
+
+var _ Service = &storageService{}
+
+func newStorageService(ctx context.Context, config StorageConfig) (*storageService, error) {
+	store, err := newBlobStore(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return &storageService{
+		config:  config,
+		store:   store,
+		results: map[digest.Digest][]ocispecs.Descriptor{},
+	}, nil
+}
+
+func (s *storageService) HTTPClient() *http.Client {
+	return s.store.HTTPClient()
+}
+
+func (s *storageService) GetConfig(context.Context, GetConfigRequest) (*Config, error) {
+	config := &Config{
+		ImportPeriod:  s.config.ImportPeriod,
+		ExportPeriod:  s.config.ExportPeriod,
+		ExportTimeout: s.config.ExportTimeout,
+	}
+	if config.ImportPeriod == 0 {
+		config.ImportPeriod = defaultStorageImportPeriod
+	}
+	if config.ExportPeriod == 0 {
+		config.ExportPeriod = defaultStorageExportPeriod
+	}
+	if config.ExportTimeout == 0 {
+		config.ExportTimeout = defaultStorageExportTimeout
+	}
+	return config, nil
+}
+
+func (s *storageService) UpdateCacheRecords(ctx context.Context, req UpdateCacheRecordsRequest) (*UpdateCacheRecordsResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = req.CacheKeys
+	s.links = req.Links
+
+	resp := &UpdateCacheRecordsResponse{}
+	seen := map[digest.Digest]struct{}{}
+	for _, key := range req.CacheKeys {
+		for _, res := range key.Results {
+			dgst := resultDigest(res.ID)
+			if _, ok := s.results[dgst]; ok {
+				continue
+			}
+			if _, ok := seen[dgst]; ok {
+				continue
+			}
+			seen[dgst] = struct{}{}
+			resp.ExportRecords = append(resp.ExportRecords, ExportRecord{
+				Digest:     dgst,
+				CacheRefID: res.ID,
+			})
+		}
+	}
+
+	if len(resp.ExportRecords) == 0 {
+		// the engine won't call UpdateCacheLayers, but the records may still
+		// have new links to results we've already pushed
+		if err := s.writeCacheConfig(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+func (s *storageService) UpdateCacheLayers(ctx context.Context, req UpdateCacheLayersRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, record := range req.UpdatedRecords {
+		s.results[record.RecordDigest] = record.Layers
+	}
+	return s.writeCacheConfig(ctx)
+}
+
+func (s *storageService) ImportCache(ctx context.Context) (*remotecache.CacheConfig, error) {
+	index, err := s.readIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &index.Cache, nil
+}
+
+func (s *storageService) GetLayerDownloadURL(ctx context.Context, req GetLayerDownloadURLRequest) (*GetLayerDownloadURLResponse, error) {
+	url, err := s.store.BlobDownloadURL(ctx, req.Digest)
+	if err != nil {
+		return nil, err
+	}
+	return &GetLayerDownloadURLResponse{URL: url}, nil
+}
+
+func (s *storageService) GetLayerUploadURL(ctx context.Context, req GetLayerUploadURLRequest) (*GetLayerUploadURLResponse, error) {
+	exists, err := s.store.BlobExists(ctx, req.Digest)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return &GetLayerUploadURLResponse{Skip: true}, nil
+	}
+	url, headers, err := s.store.BlobUploadURL(ctx, req.Digest)
+	if err != nil {
+		return nil, err
+	}
+	return &GetLayerUploadURLResponse{URL: url, Headers: headers}, nil
+}
+
+func (s *storageService) GetCacheMountConfig(ctx context.Context, _ GetCacheMountConfigRequest) (*GetCacheMountConfigResponse, error) {
+	index, err := s.readIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp := &GetCacheMountConfigResponse{}
+	for _, mount := range index.CacheMounts {
+		url, err := s.store.BlobDownloadURL(ctx, mount.Digest)
+		if err != nil {
+			return nil, err
+		}
+		resp.SyncedCacheMounts = append(resp.SyncedCacheMounts, SyncedCacheMountConfig{
+			Name:      mount.Name,
+			Digest:    mount.Digest,
+			Size:      mount.Size,
+			MediaType: mount.MediaType,
+			URL:       url,
+		})
+	}
+	return resp, nil
+}
+
+func (s *storageService) GetCacheMountUploadURL(ctx context.Context, req GetCacheMountUploadURLRequest) (*GetCacheMountUploadURLResponse, error) {
+	exists, err := s.store.BlobExists(ctx, req.Digest)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return &GetCacheMountUploadURLResponse{Skip: true}, nil
+	}
+	url, headers, err := s.store.BlobUploadURL(ctx, req.Digest)
+	if err != nil {
+		return nil, err
+	}
+	return &GetCacheMountUploadURLResponse{URL: url, Headers: headers}, nil
+}
+
+// cacheMountRecorder is implemented by services that need to be told once a
+// cache mount's blob has been uploaded, as opposed to when the upload URL is
+// requested.
+type cacheMountRecorder interface {
+	RecordCacheMount(context.Context, GetCacheMountUploadURLRequest) error
+}
+
+var _ cacheMountRecorder = &storageService{}
+
+// RecordCacheMount adds the uploaded cache mount to the index, replacing any
+// previous version of it.
+func (s *storageService) RecordCacheMount(ctx context.Context, req GetCacheMountUploadURLRequest) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	index, err := s.readIndex(ctx)
+	if err != nil {
+		return err
+	}
+	mount := storageCacheMount{
+		Name:      req.CacheName,
+		Digest:    req.Digest,
+		Size:      req.Size,
+		MediaType: ocispecs.MediaTypeImageLayerZstd,
+		UpdatedAt: time.Now().UTC(),
+	}
+	var found bool
+	for i, existing := range index.CacheMounts {
+		if existing.Name == req.CacheName {
+			index.CacheMounts[i] = mount
+			found = true
+			break
+		}
+	}
+	if !found {
+		index.CacheMounts = append(index.CacheMounts, mount)
+	}
+	s.prune(index)
+	return s.writeIndex(ctx, index)
+}
+
+// writeCacheConfig merges the engine's records into the cache config in the
+// index. Must be called with s.mu held.
+func (s *storageService) writeCacheConfig(ctx context.Context) error {
+	index, err := s.readIndex(ctx)
+	if err != nil {
+		return err
+	}
+	oldIndex, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	s.prune(index)
+
+	chains := remotecache.NewCacheChains()
+	if err := remotecache.ParseConfig(index.Cache, layerDescriptors(ctx, index.Cache), chains); err != nil {
+		return fmt.Errorf("failed to parse stored cache config: %w", err)
+	}
+	s.addRecords(chains)
+	config, descs, err := chains.Marshal(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cache config: %w", err)
+	}
+	for i, layer := range config.Layers {
+		desc, ok := descs[layer.Blob]
+		if !ok {
+			return fmt.Errorf("missing descriptor for layer %s", layer.Blob)
+		}
+		config.Layers[i].Annotations = layerAnnotations(desc.Descriptor)
+	}
+	index.Cache = *config
+
+	newIndex, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(oldIndex, newIndex) {
+		bklog.G(ctx).Debug("stored cache config is up to date")
+		return nil
+	}
+	return s.writeIndex(ctx, index)
+}
+
+// prune drops the results and cache mounts in the index that are older than
+// the configured max age.
+func (s *storageService) prune(index *storageIndex) {
+	maxAge := s.config.MaxAge
+	if maxAge == 0 {
+		maxAge = defaultStorageMaxAge
+	}
+	cutoff := time.Now().Add(-maxAge)
+
+	pruneCacheConfig(&index.Cache, cutoff)
+
+	mounts := index.CacheMounts[:0]
+	for _, mount := range index.CacheMounts {
+		if mount.UpdatedAt.Before(cutoff) {
+			continue
+		}
+		mounts = append(mounts, mount)
+	}
+	index.CacheMounts = mounts
+}
+
+// pruneCacheConfig removes the results created before the cutoff from the
+// config, along with the records that no longer lead to any result. Layers are
+// left as they are, since marshaling the config again drops the unused ones.
+func pruneCacheConfig(config *remotecache.CacheConfig, cutoff time.Time) {
+	keep := make([]bool, len(config.Records))
+	var mark func(int)
+	mark = func(idx int) {
+		if idx < 0 || idx >= len(keep) || keep[idx] {
+			return
+		}
+		keep[idx] = true
+		for _, inputs := range config.Records[idx].Inputs {
+			for _, input := range inputs {
+				mark(input.LinkIndex)
+			}
+		}
+	}
+	for i := range config.Records {
+		rec := &config.Records[i]
+		results := rec.Results[:0]
+		for _, res := range rec.Results {
+			if !res.CreatedAt.Before(cutoff) {
+				results = append(results, res)
+			}
+		}
+		rec.Results = results
+		chained := rec.ChainedResults[:0]
+		for _, res := range rec.ChainedResults {
+			if !res.CreatedAt.Before(cutoff) {
+				chained = append(chained, res)
+			}
+		}
+		rec.ChainedResults = chained
+		if len(rec.Results) > 0 || len(rec.ChainedResults) > 0 {
+			mark(i)
+		}
+	}
+
+	newIndexes := make([]int, len(config.Records))
+	records := make([]remotecache.CacheRecord, 0, len(config.Records))
+	for i, rec := range config.Records {
+		if !keep[i] {
+			continue
+		}
+		newIndexes[i] = len(records)
+		records = append(records, rec)
+	}
+	for _, rec := range records {
+		for _, inputs := range rec.Inputs {
+			for j, input := range inputs {
+				if input.LinkIndex >= 0 && input.LinkIndex < len(newIndexes) {
+					inputs[j].LinkIndex = newIndexes[input.LinkIndex]
+				}
+			}
+		}
+	}
+	config.Records = records
+}
+
+// addRecords adds the engine's cache keys, and the results that have been
+// pushed, to the cache chains.
+func (s *storageService) addRecords(chains *remotecache.CacheChains) {
+	incoming := map[string][]Link{}
+	for _, link := range s.links {
+		incoming[link.ID] = append(incoming[link.ID], link)
+	}
+
+	records := make(map[string]solver.CacheExporterRecord, len(s.keys))
+	for _, key := range s.keys {
+		// a key with inputs is identified by the digest of the op that
+		// produced it, while a root key's ID is already a digest
+		dgst := digest.Digest(key.ID)
+		if links := incoming[key.ID]; len(links) > 0 {
+			dgst = links[0].Digest
+		}
+		if dgst.Validate() != nil {
+			continue
+		}
+		rec := chains.Add(dgst)
+		records[key.ID] = rec
+		for _, res := range key.Results {
+			layers, ok := s.results[resultDigest(res.ID)]
+			if !ok || len(layers) == 0 {
+				continue
+			}
+			rec.AddResult("", 0, res.CreatedAt, &solver.Remote{Descriptors: layers})
+		}
+	}
+	for _, link := range s.links {
+		child, ok := records[link.ID]
+		if !ok {
+			continue
+		}
+		parent, ok := records[link.LinkedID]
+		if !ok {
+			continue
+		}
+		child.LinkFrom(parent, link.Input, link.Selector.String())
+	}
+}
+
+func (s *storageService) readIndex(ctx context.Context) (*storageIndex, error) {
+	data, err := s.store.ReadIndex(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read cache index: %w", err)
+	}
+	index := &storageIndex{}
+	if data == nil {
+		return index, nil
+	}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, fmt.Errorf("failed to decode cache index: %w", err)
+	}
+	return index, nil
+}
+
+func (s *storageService) writeIndex(ctx context.Context, index *storageIndex) error {
+	data, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	var blobs []ocispecs.Descriptor
+	for _, layer := range index.Cache.Layers {
+		desc, err := layerDescriptor(layer)
+		if err != nil {
+			continue
+		}
+		blobs = append(blobs, desc)
+	}
+	for _, mount := range index.CacheMounts {
+		blobs = append(blobs, ocispecs.Descriptor{
+			MediaType: mount.MediaType,
+			Digest:    mount.Digest,
+			Size:      mount.Size,
+		})
+	}
+	if err := s.store.WriteIndex(ctx, data, blobs); err != nil {
+		return fmt.Errorf("failed to write cache index: %w", err)
+	}
+	return nil
+}
+
+// resultDigest is the digest used to refer to a cache result in the records
+// exchanged with the manager.
+func resultDigest(id string) digest.Digest {
+	return digest.FromString(id)
+}
+
+// layerDescriptors returns descriptors for the layers of the cache config,
+// skipping any that are invalid.
+func layerDescriptors(ctx context.Context, config remotecache.CacheConfig) remotecache.DescriptorProvider {
+	descs := remotecache.DescriptorProvider{}
+	for _, layer := range config.Layers {
+		desc, err := layerDescriptor(layer)
+		if err != nil {
+			bklog.G(ctx).WithError(err).Debug("skipping stored cache layer")
+			continue
+		}
+		descs[layer.Blob] = remotecache.DescriptorProviderPair{Descriptor: desc}
+	}
+	return descs
+}
+
+// the descriptor annotations buildkit uses for a layer's diffID and creation time
+const (
+	labelUncompressed = "containerd.io/uncompressed"
+	labelCreatedAt    = "buildkit/createdat"
+)
+
+func layerDescriptor(layer remotecache.CacheLayer) (ocispecs.Descriptor, error) {
+	if layer.Annotations == nil {
+		return ocispecs.Descriptor{}, fmt.Errorf("missing annotations for layer %s", layer.Blob)
+	}
+
+	annotations := map[string]string{}
+	if layer.Annotations.DiffID == "" {
+		return ocispecs.Descriptor{}, fmt.Errorf("missing diffID for layer %s", layer.Blob)
+	}
+	annotations[labelUncompressed] = layer.Annotations.DiffID.String()
+	if !layer.Annotations.CreatedAt.IsZero() {
+		createdAt, err := layer.Annotations.CreatedAt.MarshalText()
+		if err != nil {
+			return ocispecs.Descriptor{}, err
+		}
+		annotations[labelCreatedAt] = string(createdAt)
+	}
+	return ocispecs.Descriptor{
+		MediaType:   layer.Annotations.MediaType,
+		Digest:      layer.Blob,
+		Size:        layer.Annotations.Size,
+		Annotations: annotations,
+	}, nil
+}
+
+func layerAnnotations(desc ocispecs.Descriptor) *remotecache.LayerAnnotations {
+	annotations := &remotecache.LayerAnnotations{
+		MediaType: desc.MediaType,
+		Size:      desc.Size,
+	}
+	if diffID, ok := desc.Annotations[labelUncompressed]; ok {
+		annotations.DiffID = digest.Digest(diffID)
+	}
+	if createdAt, ok := desc.Annotations[labelCreatedAt]; ok {
+		var t time.Time
+		if err := t.UnmarshalText([]byte(createdAt)); err == nil {
+			annotations.CreatedAt = t
+		}
+	}
+	return annotations
+}
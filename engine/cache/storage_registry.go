@@ -0,0 +1,285 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote/transport"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// the tag the cache is stored under if the ref doesn't specify one
+	defaultRegistryCacheTag = "cache"
+
+	// the media type of the config blob of the manifest the index is stored in
+	registryIndexMediaType = "application/vnd.dagger.cache.index.v1+json"
+)
+
+// registryStore stores blobs in a repository of an OCI registry, and the index
+// as the config of a manifest tagged in that repository. The manifest lists
+// the blobs as layers so that the registry doesn't garbage collect them.
+type registryStore struct {
+	httpClient *http.Client
+	// base URL of the repository, i.e. <scheme>://<registry>/v2/<repository>
+	baseURL string
+	tag     string
+
+	mu sync.Mutex
+	// blobs known to be in the repository, so that writing the index doesn't
+	// need to check each of them again
+	knownBlobs map[digest.Digest]struct{}
+}
+
+var _ blobStore = &registryStore{}
+
+func newRegistryStore(ctx context.Context, config StorageConfig) (*registryStore, error) {
+	opts := []name.Option{name.WithDefaultTag(defaultRegistryCacheTag)}
+	if config.Insecure {
+		opts = append(opts, name.Insecure)
+	}
+	ref, err := name.NewTag(config.Ref, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cache ref %q: %w", config.Ref, err)
+	}
+	repo := ref.Context()
+
+	auth, err := authn.DefaultKeychain.Resolve(repo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve credentials for %s: %w", repo, err)
+	}
+	rt, err := transport.NewWithContext(ctx, repo.Registry, auth, http.DefaultTransport, []string{
+		repo.Scope(transport.PushScope),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", repo.Registry, err)
+	}
+
+	return &registryStore{
+		httpClient: &http.Client{Transport: rt},
+		baseURL:    fmt.Sprintf("%s://%s/v2/%s", repo.Registry.Scheme(), repo.RegistryStr(), repo.RepositoryStr()),
+		tag:        ref.TagStr(),
+		knownBlobs: map[digest.Digest]struct{}{},
+	}, nil
+}
+
+func (s *registryStore) HTTPClient() *http.Client {
+	return s.httpClient
+}
+
+func (s *registryStore) BlobExists(ctx context.Context, dgst digest.Digest) (bool, error) {
+	if s.isKnownBlob(dgst) {
+		return true, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.blobURL(dgst), nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err := checkResponse(resp); err != nil {
+		return false, err
+	}
+	s.addKnownBlobs(ocispecs.Descriptor{Digest: dgst})
+	return true, nil
+}
+
+func (s *registryStore) BlobDownloadURL(_ context.Context, dgst digest.Digest) (string, error) {
+	return s.blobURL(dgst), nil
+}
+
+func (s *registryStore) BlobUploadURL(ctx context.Context, dgst digest.Digest) (string, map[string]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/blobs/uploads/", nil)
+	if err != nil {
+		return "", nil, err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return "", nil, err
+	}
+
+	// the location may be relative to the request URL
+	location, err := resp.Location()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get upload location: %w", err)
+	}
+	query := location.Query()
+	query.Set("digest", dgst.String())
+	location.RawQuery = query.Encode()
+	return location.String(), map[string]string{
+		"Content-Type": "application/octet-stream",
+	}, nil
+}
+
+func (s *registryStore) ReadIndex(ctx context.Context) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.manifestURL(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", ocispecs.MediaTypeImageManifest)
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err := checkResponse(resp); err != nil {
+		return nil, err
+	}
+
+	var manifest ocispecs.Manifest
+	if err := json.NewDecoder(resp.Body).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	if manifest.Config.MediaType != registryIndexMediaType {
+		return nil, fmt.Errorf("%s:%s is not a dagger cache (config media type %q)", s.baseURL, s.tag, manifest.Config.MediaType)
+	}
+	// the manifest keeps its blobs from being garbage collected
+	s.addKnownBlobs(manifest.Layers...)
+
+	req, err = http.NewRequestWithContext(ctx, http.MethodGet, s.blobURL(manifest.Config.Digest), nil)
+	if err != nil {
+		return nil, err
+	}
+	blobResp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer blobResp.Body.Close()
+	if err := checkResponse(blobResp); err != nil {
+		return nil, err
+	}
+	return io.ReadAll(blobResp.Body)
+}
+
+func (s *registryStore) WriteIndex(ctx context.Context, data []byte, blobs []ocispecs.Descriptor) error {
+	config := ocispecs.Descriptor{
+		MediaType: registryIndexMediaType,
+		Digest:    digest.FromBytes(data),
+		Size:      int64(len(data)),
+	}
+	if err := s.putBlob(ctx, config.Digest, data); err != nil {
+		return fmt.Errorf("failed to upload index: %w", err)
+	}
+
+	manifest := ocispecs.Manifest{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispecs.MediaTypeImageManifest,
+		Config:    config,
+		Layers:    []ocispecs.Descriptor{},
+	}
+	seen := map[digest.Digest]struct{}{}
+	for _, blob := range blobs {
+		if _, ok := seen[blob.Digest]; ok {
+			continue
+		}
+		seen[blob.Digest] = struct{}{}
+		// registries reject manifests that reference missing blobs, which is
+		// the case for layers that were never pushed (e.g. ones built in to the
+		// engine)
+		exists, err := s.BlobExists(ctx, blob.Digest)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		manifest.Layers = append(manifest.Layers, ocispecs.Descriptor{
+			MediaType: blob.MediaType,
+			Digest:    blob.Digest,
+			Size:      blob.Size,
+		})
+	}
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.manifestURL(), bytes.NewReader(manifestData))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ocispecs.MediaTypeImageManifest)
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp)
+}
+
+func (s *registryStore) putBlob(ctx context.Context, dgst digest.Digest, data []byte) error {
+	exists, err := s.BlobExists(ctx, dgst)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return nil
+	}
+	uploadURL, headers, err := s.BlobUploadURL(ctx, dgst)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return err
+	}
+	s.addKnownBlobs(ocispecs.Descriptor{Digest: dgst})
+	return nil
+}
+
+func (s *registryStore) isKnownBlob(dgst digest.Digest) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.knownBlobs[dgst]
+	return ok
+}
+
+func (s *registryStore) addKnownBlobs(descs ...ocispecs.Descriptor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, desc := range descs {
+		s.knownBlobs[desc.Digest] = struct{}{}
+	}
+}
+
+func (s *registryStore) blobURL(dgst digest.Digest) string {
+	return s.baseURL + "/blobs/" + dgst.String()
+}
+
+func (s *registryStore) manifestURL() string {
+	return s.baseURL + "/manifests/" + url.PathEscape(s.tag)
+}
@@ -0,0 +1,157 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+)
+
+const (
+	// how long presigned URLs are valid for; they're requested right before
+	// they're used, so this only needs to cover slow transfers
+	s3PresignExpiry = time.Hour
+
+	s3DefaultRegion = "us-east-1"
+)
+
+// s3Store stores blobs and the index as objects in an S3-compatible bucket,
+// and hands out presigned URLs to transfer blobs.
+type s3Store struct {
+	client     *s3.Client
+	presign    *s3.PresignClient
+	httpClient *http.Client
+	bucket     string
+	prefix     string
+}
+
+var _ blobStore = &s3Store{}
+
+func newS3Store(ctx context.Context, config StorageConfig) (*s3Store, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("s3 cache storage requires a bucket")
+	}
+	var opts []func(*awsconfig.LoadOptions) error
+	if config.Region != "" {
+		opts = append(opts, awsconfig.WithRegion(config.Region))
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load aws config: %w", err)
+	}
+	if awsCfg.Region == "" {
+		awsCfg.Region = s3DefaultRegion
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if config.Endpoint != "" {
+			// S3-compatible servers generally don't support virtual-hosted
+			// buckets
+			o.BaseEndpoint = aws.String(config.Endpoint)
+			o.UsePathStyle = true
+		}
+	})
+	return &s3Store{
+		client:     client,
+		presign:    s3.NewPresignClient(client),
+		httpClient: &http.Client{},
+		bucket:     config.Bucket,
+		prefix:     config.Prefix,
+	}, nil
+}
+
+func (s *s3Store) HTTPClient() *http.Client {
+	return s.httpClient
+}
+
+func (s *s3Store) BlobExists(ctx context.Context, dgst digest.Digest) (bool, error) {
+	_, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.blobKey(dgst)),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *s3Store) BlobDownloadURL(ctx context.Context, dgst digest.Digest) (string, error) {
+	req, err := s.presign.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.blobKey(dgst)),
+	}, s3.WithPresignExpires(s3PresignExpiry))
+	if err != nil {
+		return "", err
+	}
+	return req.URL, nil
+}
+
+func (s *s3Store) BlobUploadURL(ctx context.Context, dgst digest.Digest) (string, map[string]string, error) {
+	req, err := s.presign.PresignPutObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.blobKey(dgst)),
+	}, s3.WithPresignExpires(s3PresignExpiry))
+	if err != nil {
+		return "", nil, err
+	}
+	headers := map[string]string{}
+	for k, v := range req.SignedHeader {
+		if http.CanonicalHeaderKey(k) == "Host" || len(v) == 0 {
+			// set from the URL by the http client
+			continue
+		}
+		headers[k] = v[0]
+	}
+	return req.URL, headers, nil
+}
+
+func (s *s3Store) ReadIndex(ctx context.Context) ([]byte, error) {
+	resp, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.indexKey()),
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+func (s *s3Store) WriteIndex(ctx context.Context, data []byte, _ []ocispecs.Descriptor) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(s.indexKey()),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String("application/json"),
+	})
+	return err
+}
+
+func (s *s3Store) blobKey(dgst digest.Digest) string {
+	return s.prefix + "blobs/" + dgst.Algorithm().String() + "/" + dgst.Encoded()
+}
+
+func (s *s3Store) indexKey() string {
+	return s.prefix + "index.json"
+}
+
+func isS3NotFound(err error) bool {
+	var respErr *awshttp.ResponseError
+	return errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound
+}
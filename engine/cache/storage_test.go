@@ -0,0 +1,478 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-containerregistry/pkg/registry"
+	remotecache "github.com/moby/buildkit/cache/remotecache/v1"
+	"github.com/opencontainers/go-digest"
+	ocispecs "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStorageServiceExport(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+
+	rootID := digest.FromString("root").String()
+	childDgst := digest.FromString("child op")
+	layer := testLayer("layer1")
+
+	svc := &storageService{store: store, results: map[digest.Digest][]ocispecs.Descriptor{}}
+	records, err := svc.UpdateCacheRecords(ctx, UpdateCacheRecordsRequest{
+		CacheKeys: []CacheKey{
+			{ID: rootID},
+			{ID: "child", Results: []Result{{ID: "ref1", CreatedAt: time.Unix(1000, 0).UTC()}}},
+		},
+		Links: []Link{
+			{ID: "child", LinkedID: rootID, Input: 0, Digest: childDgst, Selector: digest.FromString("sel")},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []ExportRecord{{Digest: resultDigest("ref1"), CacheRefID: "ref1"}}, records.ExportRecords)
+
+	err = svc.UpdateCacheLayers(ctx, UpdateCacheLayersRequest{
+		UpdatedRecords: []RecordLayers{{RecordDigest: resultDigest("ref1"), Layers: []ocispecs.Descriptor{layer}}},
+	})
+	require.NoError(t, err)
+
+	config, err := svc.ImportCache(ctx)
+	require.NoError(t, err)
+	require.Len(t, config.Layers, 1)
+	require.Equal(t, layer.Digest, config.Layers[0].Blob)
+	require.Equal(t, &remotecache.LayerAnnotations{
+		MediaType: layer.MediaType,
+		DiffID:    digest.Digest(layer.Annotations[labelUncompressed]),
+		Size:      layer.Size,
+	}, config.Layers[0].Annotations)
+
+	require.Len(t, config.Records, 2)
+	byDigest := map[digest.Digest]remotecache.CacheRecord{}
+	for _, rec := range config.Records {
+		byDigest[rec.Digest] = rec
+	}
+	child, ok := byDigest[childDgst]
+	require.True(t, ok)
+	require.Len(t, child.Inputs, 1)
+	require.Len(t, child.Inputs[0], 1)
+	require.Equal(t, digest.FromString("sel").String(), child.Inputs[0][0].Selector)
+	require.Equal(t, digest.Digest(rootID), config.Records[child.Inputs[0][0].LinkIndex].Digest)
+	require.Equal(t, []remotecache.CacheResult{{LayerIndex: 0, CreatedAt: time.Unix(1000, 0).UTC()}}, child.Results)
+
+	// the layer's blob needs to be referenced for registries to keep it
+	require.Equal(t, []digest.Digest{layer.Digest}, store.indexBlobs())
+
+	// results that were already pushed aren't exported again
+	records, err = svc.UpdateCacheRecords(ctx, UpdateCacheRecordsRequest{
+		CacheKeys: []CacheKey{
+			{ID: rootID},
+			{ID: "child", Results: []Result{{ID: "ref1", CreatedAt: time.Unix(1000, 0).UTC()}}},
+		},
+		Links: []Link{
+			{ID: "child", LinkedID: rootID, Input: 0, Digest: childDgst, Selector: digest.FromString("sel")},
+		},
+	})
+	require.NoError(t, err)
+	require.Empty(t, records.ExportRecords)
+}
+
+func TestStorageServiceMergesEngines(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+
+	export := func(rootName, refID string, layer ocispecs.Descriptor) {
+		svc := &storageService{store: store, results: map[digest.Digest][]ocispecs.Descriptor{}}
+		_, err := svc.UpdateCacheRecords(ctx, UpdateCacheRecordsRequest{
+			CacheKeys: []CacheKey{
+				{ID: digest.FromString(rootName).String(), Results: []Result{{ID: refID, CreatedAt: time.Now()}}},
+			},
+		})
+		require.NoError(t, err)
+		err = svc.UpdateCacheLayers(ctx, UpdateCacheLayersRequest{
+			UpdatedRecords: []RecordLayers{{RecordDigest: resultDigest(refID), Layers: []ocispecs.Descriptor{layer}}},
+		})
+		require.NoError(t, err)
+	}
+	export("a", "ref-a", testLayer("a"))
+	export("b", "ref-b", testLayer("b"))
+
+	svc := &storageService{store: store}
+	config, err := svc.ImportCache(ctx)
+	require.NoError(t, err)
+	var dgsts []digest.Digest
+	for _, rec := range config.Records {
+		dgsts = append(dgsts, rec.Digest)
+	}
+	require.ElementsMatch(t, []digest.Digest{digest.FromString("a"), digest.FromString("b")}, dgsts)
+	require.Len(t, config.Layers, 2)
+}
+
+func TestStorageServiceCacheMounts(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	svc := &storageService{store: store, results: map[digest.Digest][]ocispecs.Descriptor{}}
+
+	dgst := digest.FromString("mount contents")
+	uploadReq := GetCacheMountUploadURLRequest{
+		CacheName: "go-mod",
+		Digest:    dgst,
+		Size:      14,
+	}
+	resp, err := svc.GetCacheMountUploadURL(ctx, uploadReq)
+	require.NoError(t, err)
+	require.False(t, resp.Skip)
+	require.NotEmpty(t, resp.URL)
+
+	// not uploaded yet, so not synced
+	config, err := svc.GetCacheMountConfig(ctx, GetCacheMountConfigRequest{})
+	require.NoError(t, err)
+	require.Empty(t, config.SyncedCacheMounts)
+
+	store.putBlob(dgst, []byte("mount contents"))
+	require.NoError(t, svc.RecordCacheMount(ctx, uploadReq))
+
+	config, err = svc.GetCacheMountConfig(ctx, GetCacheMountConfigRequest{})
+	require.NoError(t, err)
+	require.Equal(t, []SyncedCacheMountConfig{{
+		Name:      "go-mod",
+		Digest:    dgst,
+		Size:      14,
+		MediaType: ocispecs.MediaTypeImageLayerZstd,
+		URL:       "mem://" + dgst.String(),
+	}}, config.SyncedCacheMounts)
+
+	resp, err = svc.GetCacheMountUploadURL(ctx, uploadReq)
+	require.NoError(t, err)
+	require.True(t, resp.Skip)
+}
+
+func TestStorageServicePrune(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+
+	export := func(rootName, refID string, createdAt time.Time) {
+		svc := &storageService{
+			config:  StorageConfig{MaxAge: time.Hour},
+			store:   store,
+			results: map[digest.Digest][]ocispecs.Descriptor{},
+		}
+		childID := "child " + rootName
+		_, err := svc.UpdateCacheRecords(ctx, UpdateCacheRecordsRequest{
+			CacheKeys: []CacheKey{
+				{ID: digest.FromString(rootName).String()},
+				{ID: childID, Results: []Result{{ID: refID, CreatedAt: createdAt}}},
+			},
+			Links: []Link{{
+				ID:       childID,
+				LinkedID: digest.FromString(rootName).String(),
+				Digest:   digest.FromString(childID),
+			}},
+		})
+		require.NoError(t, err)
+		err = svc.UpdateCacheLayers(ctx, UpdateCacheLayersRequest{
+			UpdatedRecords: []RecordLayers{{RecordDigest: resultDigest(refID), Layers: []ocispecs.Descriptor{testLayer(refID)}}},
+		})
+		require.NoError(t, err)
+	}
+	export("old", "ref-old", time.Now().Add(-2*time.Hour))
+	export("new", "ref-new", time.Now())
+
+	// the old engine's records are pruned by the new engine's export, along
+	// with the root that only led to them
+	svc := &storageService{store: store}
+	config, err := svc.ImportCache(ctx)
+	require.NoError(t, err)
+	var dgsts []digest.Digest
+	for _, rec := range config.Records {
+		dgsts = append(dgsts, rec.Digest)
+	}
+	require.ElementsMatch(t, []digest.Digest{digest.FromString("new"), digest.FromString("child new")}, dgsts)
+	require.Len(t, config.Layers, 1)
+	require.Equal(t, testLayer("ref-new").Digest, config.Layers[0].Blob)
+	require.Equal(t, []digest.Digest{testLayer("ref-new").Digest}, store.indexBlobs())
+}
+
+func TestPruneCacheConfig(t *testing.T) {
+	now := time.Now()
+	config := remotecache.CacheConfig{
+		Records: []remotecache.CacheRecord{
+			{Digest: "root"},
+			{Digest: "old", Inputs: [][]remotecache.CacheInput{{{LinkIndex: 0}}}, Results: []remotecache.CacheResult{{CreatedAt: now.Add(-time.Hour)}}},
+			{Digest: "other root"},
+			{Digest: "new", Inputs: [][]remotecache.CacheInput{{{LinkIndex: 2}}}, Results: []remotecache.CacheResult{{CreatedAt: now}}},
+			{Digest: "new child", Inputs: [][]remotecache.CacheInput{{{LinkIndex: 3}}}, ChainedResults: []remotecache.ChainedResult{{CreatedAt: now}}},
+		},
+	}
+	pruneCacheConfig(&config, now.Add(-time.Minute))
+	require.Equal(t, []remotecache.CacheRecord{
+		{Digest: "other root"},
+		{Digest: "new", Inputs: [][]remotecache.CacheInput{{{LinkIndex: 0}}}, Results: []remotecache.CacheResult{{CreatedAt: now}}},
+		{Digest: "new child", Inputs: [][]remotecache.CacheInput{{{LinkIndex: 1}}}, ChainedResults: []remotecache.ChainedResult{{CreatedAt: now}}},
+	}, config.Records)
+}
+
+func TestRegistryStore(t *testing.T) {
+	ctx := context.Background()
+	var heads atomic.Int32
+	reg := registry.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			heads.Add(1)
+		}
+		reg.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	store, err := newRegistryStore(ctx, StorageConfig{
+		Type:     StorageTypeRegistry,
+		Ref:      strings.TrimPrefix(srv.URL, "http://") + "/dagger/cache",
+		Insecure: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, defaultRegistryCacheTag, store.tag)
+
+	index, err := store.ReadIndex(ctx)
+	require.NoError(t, err)
+	require.Nil(t, index)
+
+	blob := []byte("some layer")
+	dgst := digest.FromBytes(blob)
+	exists, err := store.BlobExists(ctx, dgst)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	uploadURL, headers, err := store.BlobUploadURL(ctx, dgst)
+	require.NoError(t, err)
+	require.NoError(t, putURL(store.HTTPClient(), uploadURL, headers, blob))
+
+	exists, err = store.BlobExists(ctx, dgst)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	downloadURL, err := store.BlobDownloadURL(ctx, dgst)
+	require.NoError(t, err)
+	require.Equal(t, blob, getURL(t, store.HTTPClient(), downloadURL))
+
+	err = store.WriteIndex(ctx, []byte(`{"cache":{}}`), []ocispecs.Descriptor{
+		{MediaType: ocispecs.MediaTypeImageLayerZstd, Digest: dgst, Size: int64(len(blob))},
+		// not uploaded, so must be left out of the manifest
+		testLayer("missing"),
+	})
+	require.NoError(t, err)
+
+	index, err = store.ReadIndex(ctx)
+	require.NoError(t, err)
+	require.Equal(t, `{"cache":{}}`, string(index))
+
+	// blobs that are known to exist aren't checked again, only the blob of the
+	// new index is
+	heads.Store(0)
+	err = store.WriteIndex(ctx, []byte(`{"cache":{"layers":[]}}`), []ocispecs.Descriptor{
+		{MediaType: ocispecs.MediaTypeImageLayerZstd, Digest: dgst, Size: int64(len(blob))},
+	})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, heads.Load())
+}
+
+func TestS3Store(t *testing.T) {
+	ctx := context.Background()
+	s3srv := newFakeS3(t)
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "test")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "test")
+	t.Setenv("AWS_CONFIG_FILE", t.TempDir()+"/config")
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", t.TempDir()+"/credentials")
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	store, err := newS3Store(ctx, StorageConfig{
+		Type:     StorageTypeS3,
+		Endpoint: s3srv.URL,
+		Bucket:   "dagger",
+		Prefix:   "cache/",
+	})
+	require.NoError(t, err)
+
+	index, err := store.ReadIndex(ctx)
+	require.NoError(t, err)
+	require.Nil(t, index)
+
+	blob := []byte("some layer")
+	dgst := digest.FromBytes(blob)
+	exists, err := store.BlobExists(ctx, dgst)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	uploadURL, headers, err := store.BlobUploadURL(ctx, dgst)
+	require.NoError(t, err)
+	require.NoError(t, putURL(store.HTTPClient(), uploadURL, headers, blob))
+	require.Equal(t, blob, s3srv.object("/dagger/cache/blobs/sha256/"+dgst.Encoded()))
+
+	exists, err = store.BlobExists(ctx, dgst)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	downloadURL, err := store.BlobDownloadURL(ctx, dgst)
+	require.NoError(t, err)
+	require.Equal(t, blob, getURL(t, store.HTTPClient(), downloadURL))
+
+	require.NoError(t, store.WriteIndex(ctx, []byte(`{"cache":{}}`), nil))
+	index, err = store.ReadIndex(ctx)
+	require.NoError(t, err)
+	require.Equal(t, `{"cache":{}}`, string(index))
+	require.Equal(t, index, s3srv.object("/dagger/cache/index.json"))
+}
+
+func testLayer(contents string) ocispecs.Descriptor {
+	return ocispecs.Descriptor{
+		MediaType: ocispecs.MediaTypeImageLayerZstd,
+		Digest:    digest.FromString(contents),
+		Size:      int64(len(contents)),
+		Annotations: map[string]string{
+			labelUncompressed: digest.FromString("uncompressed " + contents).String(),
+		},
+	}
+}
+
+func putURL(client *http.Client, url string, headers map[string]string, data []byte) error {
+	req, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp)
+}
+
+func getURL(t *testing.T, client *http.Client, url string) []byte {
+	t.Helper()
+	resp, err := client.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.NoError(t, checkResponse(resp))
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return data
+}
+
+type memStore struct {
+	mu     sync.Mutex
+	blobs  map[digest.Digest][]byte
+	index  []byte
+	refs   []ocispecs.Descriptor
+	client *http.Client
+}
+
+func newMemStore() *memStore {
+	return &memStore{blobs: map[digest.Digest][]byte{}, client: &http.Client{}}
+}
+
+func (s *memStore) HTTPClient() *http.Client {
+	return s.client
+}
+
+func (s *memStore) BlobExists(_ context.Context, dgst digest.Digest) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.blobs[dgst]
+	return ok, nil
+}
+
+func (s *memStore) BlobDownloadURL(_ context.Context, dgst digest.Digest) (string, error) {
+	return "mem://" + dgst.String(), nil
+}
+
+func (s *memStore) BlobUploadURL(_ context.Context, dgst digest.Digest) (string, map[string]string, error) {
+	return "mem://" + dgst.String(), nil, nil
+}
+
+func (s *memStore) ReadIndex(context.Context) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.index, nil
+}
+
+func (s *memStore) WriteIndex(_ context.Context, data []byte, blobs []ocispecs.Descriptor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.index = data
+	s.refs = blobs
+	return nil
+}
+
+func (s *memStore) putBlob(dgst digest.Digest, data []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[dgst] = data
+}
+
+func (s *memStore) indexBlobs() []digest.Digest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var dgsts []digest.Digest
+	for _, desc := range s.refs {
+		dgsts = append(dgsts, desc.Digest)
+	}
+	return dgsts
+}
+
+// fakeS3 is a minimal path-style S3 server that stores objects in memory.
+type fakeS3 struct {
+	*httptest.Server
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newFakeS3(t *testing.T) *fakeS3 {
+	s := &fakeS3{objects: map[string][]byte{}}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" && r.URL.Query().Get("X-Amz-Signature") == "" {
+			http.Error(w, "unsigned request", http.StatusForbidden)
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch r.Method {
+		case http.MethodPut:
+			data, err := io.ReadAll(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			s.objects[r.URL.Path] = data
+		case http.MethodGet, http.MethodHead:
+			data, ok := s.objects[r.URL.Path]
+			if !ok {
+				w.Header().Set("Content-Type", "application/xml")
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = io.WriteString(w, `<Error><Code>NoSuchKey</Code></Error>`)
+				return
+			}
+			_, _ = w.Write(data)
+		default:
+			http.Error(w, "unsupported", http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeS3) object(path string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.objects[path]
+}
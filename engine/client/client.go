@@ -86,6 +86,14 @@ type Params struct {
 	InteractiveCommand []string
 
 	WithTerminal session.WithTerminalFunc
+
+	// Additional secret providers, keyed by URI scheme, to resolve secrets
+	// of this client with.
+	SecretResolvers map[string]secretprovider.SecretResolver
+
+	// How long secrets resolved by this client are cached for. If the TTL
+	// isn't set, it's read from $DAGGER_SECRET_CACHE_TTL.
+	SecretCache secretprovider.CacheOpts
 }
 
 type Client struct {
@@ -113,6 +121,8 @@ type Client struct {
 	// Currently used for the dagger CLI so it can avoid making a subprocess of itself...
 	daggerClient *dagger.Client
 
+	secretProvider secretprovider.SecretProvider
+
 	upstreamCacheImportOptions []*controlapi.CacheOptionsEntry
 	upstreamCacheExportOptions []*controlapi.CacheOptionsEntry
 
@@ -337,11 +347,17 @@ func (c *Client) startSession(ctx context.Context) (rerr error) {
 	clientMetadata := c.clientMetadata()
 	c.internalCtx = engine.ContextWithClientMetadata(c.internalCtx, &clientMetadata)
 
+	secretCache, err := c.SecretCache.WithEnv()
+	if err != nil {
+		return err
+	}
+	c.secretProvider = secretprovider.NewSecretProvider(c.SecretResolvers, secretCache)
+
 	attachables := []bksession.Attachable{
 		// sockets
 		SocketProvider{EnableHostNetworkAccess: !c.DisableHostRW},
 		// secrets
-		secretprovider.NewSecretProvider(),
+		c.secretProvider,
 		// registry auth
 		authprovider.NewDockerAuthProvider(config.LoadDefaultConfigFile(os.Stderr), nil),
 		// host=>container networking
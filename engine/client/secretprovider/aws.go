@@ -0,0 +1,60 @@
+package secretprovider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// awsProvider reads a secret from AWS Secrets Manager with the `aws` CLI,
+// using the credentials and region from the environment.
+//
+// The key has the form "secret-id#field", where secret-id is the name or ARN
+// of the secret, and field optionally selects a value from a secret stored as
+// a JSON object.
+func awsProvider(ctx context.Context, key string) ([]byte, error) {
+	secretID, field, _ := strings.Cut(key, "#")
+	if secretID == "" {
+		return nil, fmt.Errorf("invalid aws secret %q: missing secret id", key)
+	}
+
+	cmd := exec.CommandContext(
+		ctx,
+		"aws",
+		"secretsmanager",
+		"get-secret-value",
+		"--secret-id", secretID,
+		"--query", "SecretString",
+		"--output", "text",
+	)
+	cmd.Env = os.Environ()
+
+	plaintext, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read aws secret %q: %w", secretID, cmdError(err))
+	}
+	// text output is terminated with a newline
+	plaintext = bytes.TrimSuffix(plaintext, []byte("\n"))
+
+	if field == "" {
+		return plaintext, nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(plaintext, &fields); err != nil {
+		// don't include the parse error, it may quote the secret
+		return nil, fmt.Errorf("aws secret %q is not a JSON object", secretID)
+	}
+	v, ok := fields[field]
+	if !ok {
+		return nil, fmt.Errorf("aws secret %q has no field %q", secretID, field)
+	}
+	if s, ok := v.(string); ok {
+		return []byte(s), nil
+	}
+	return json.Marshal(v)
+}
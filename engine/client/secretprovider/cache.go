@@ -0,0 +1,200 @@
+package secretprovider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"resenje.org/singleflight"
+
+	"github.com/dagger/dagger/engine/secretscrub"
+)
+
+const (
+	// CacheStatusHeader is the gRPC response header a SecretProvider sets to
+	// "hit" or "miss", so the engine can tell whether the secret was resolved
+	// again.
+	CacheStatusHeader = "x-dagger-secret-cache"
+
+	// RefreshHeader is the gRPC request header the engine sets to "true" to
+	// make a SecretProvider resolve a secret again instead of using its
+	// cached plaintext.
+	RefreshHeader = "x-dagger-secret-refresh"
+
+	// CacheTTLEnv is the environment variable that sets how long a client
+	// caches resolved secrets for, as a duration like "30s" or "1h". "0"
+	// disables caching.
+	CacheTTLEnv = "DAGGER_SECRET_CACHE_TTL"
+)
+
+// DefaultCacheTTL is how long secrets are cached for if no TTL is set; it's
+// short so that rotated secrets are picked up without an explicit refresh.
+const DefaultCacheTTL = 5 * time.Minute
+
+// CacheOpts configures how long a SecretProvider caches resolved secrets.
+type CacheOpts struct {
+	// How long secrets are cached for. Zero uses DefaultCacheTTL, and a
+	// negative value disables caching.
+	TTL time.Duration
+
+	// Overrides of TTL, keyed by either the full URI of a secret (e.g.
+	// "vault://path/to/secret.field") or the scheme of its provider (e.g.
+	// "cmd").
+	TTLs map[string]time.Duration
+}
+
+// WithEnv returns opts with its TTL set from CacheTTLEnv, unless it's set
+// already.
+func (opts CacheOpts) WithEnv() (CacheOpts, error) {
+	val, ok := os.LookupEnv(CacheTTLEnv)
+	if !ok || opts.TTL != 0 {
+		return opts, nil
+	}
+	ttl, err := time.ParseDuration(val)
+	if err != nil {
+		return opts, fmt.Errorf("invalid %s: %w", CacheTTLEnv, err)
+	}
+	if ttl == 0 {
+		ttl = -1
+	}
+	opts.TTL = ttl
+	return opts, nil
+}
+
+func (opts CacheOpts) ttl(uri string) time.Duration {
+	ttl, ok := opts.TTLs[uri]
+	if !ok {
+		scheme, _, _ := strings.Cut(uri, "://")
+		ttl, ok = opts.TTLs[scheme]
+	}
+	if !ok {
+		ttl = opts.TTL
+	}
+	if ttl == 0 {
+		return DefaultCacheTTL
+	}
+	return ttl
+}
+
+// secretCache holds the plaintexts of the secrets resolved during a session,
+// and makes sure concurrent requests for the same secret only resolve it once.
+type secretCache struct {
+	opts CacheOpts
+
+	entries map[string]*cachedSecret
+	mu      sync.Mutex
+
+	resolving singleflight.Group[string, []byte]
+
+	now func() time.Time
+}
+
+type cachedSecret struct {
+	plaintext []byte
+	expires   time.Time
+}
+
+func newSecretCache(opts CacheOpts) *secretCache {
+	return &secretCache{
+		opts:    opts,
+		entries: map[string]*cachedSecret{},
+		now:     time.Now,
+	}
+}
+
+// get returns the plaintext of the secret with the given URI, calling resolve
+// if it isn't cached or has expired. The returned bool is true if the
+// plaintext came from the cache.
+func (c *secretCache) get(ctx context.Context, uri string, resolve func(context.Context) ([]byte, error)) ([]byte, bool, error) {
+	ttl := c.opts.ttl(uri)
+	if ttl < 0 {
+		plaintext, err := resolve(ctx)
+		if err != nil {
+			return nil, false, c.scrub(err)
+		}
+		return plaintext, false, nil
+	}
+
+	if plaintext, ok := c.lookup(uri); ok {
+		return plaintext, true, nil
+	}
+
+	var resolved atomic.Bool
+	plaintext, _, err := c.resolving.Do(ctx, uri, func(ctx context.Context) ([]byte, error) {
+		resolved.Store(true)
+		plaintext, err := resolve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.entries[uri] = &cachedSecret{
+			plaintext: plaintext,
+			expires:   c.now().Add(ttl),
+		}
+		c.mu.Unlock()
+		return plaintext, nil
+	})
+	if err != nil {
+		return nil, false, c.scrub(err)
+	}
+	// a request that waited on another one to resolve the secret counts as a
+	// hit, since it didn't resolve it again
+	return plaintext, !resolved.Load(), nil
+}
+
+func (c *secretCache) lookup(uri string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[uri]
+	if !ok {
+		return nil, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, uri)
+		return nil, false
+	}
+	return entry.plaintext, true
+}
+
+// refresh drops the cached plaintext of the secret with the given URI.
+func (c *secretCache) refresh(uri string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, uri)
+}
+
+// scrub removes the plaintext of every cached secret from the message of err,
+// since providers may include the output of a command or API call in their
+// errors.
+func (c *secretCache) scrub(err error) error {
+	c.mu.Lock()
+	plaintexts := make([][]byte, 0, len(c.entries))
+	for _, entry := range c.entries {
+		plaintexts = append(plaintexts, entry.plaintext)
+	}
+	c.mu.Unlock()
+	if len(plaintexts) == 0 {
+		return err
+	}
+	return &scrubbedError{
+		err: err,
+		msg: secretscrub.String(err.Error(), plaintexts...),
+	}
+}
+
+type scrubbedError struct {
+	err error
+	msg string
+}
+
+func (e *scrubbedError) Error() string {
+	return e.msg
+}
+
+func (e *scrubbedError) Unwrap() error {
+	return e.err
+}
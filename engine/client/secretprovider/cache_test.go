@@ -0,0 +1,185 @@
+package secretprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/moby/buildkit/session/secrets"
+	"github.com/stretchr/testify/require"
+)
+
+// countingResolver returns a resolver that returns a new value every time
+// it's called.
+func countingResolver(calls *atomic.Int32) func(context.Context) ([]byte, error) {
+	return func(context.Context) ([]byte, error) {
+		n := calls.Add(1)
+		return []byte(fmt.Sprintf("secret-%d", n)), nil
+	}
+}
+
+func TestSecretCache(t *testing.T) {
+	ctx := context.Background()
+	cache := newSecretCache(CacheOpts{})
+
+	var calls atomic.Int32
+	plaintext, cached, err := cache.get(ctx, "cmd://vend", countingResolver(&calls))
+	require.NoError(t, err)
+	require.False(t, cached)
+	require.Equal(t, "secret-1", string(plaintext))
+
+	plaintext, cached, err = cache.get(ctx, "cmd://vend", countingResolver(&calls))
+	require.NoError(t, err)
+	require.True(t, cached)
+	require.Equal(t, "secret-1", string(plaintext))
+	require.EqualValues(t, 1, calls.Load())
+
+	cache.refresh("cmd://vend")
+	plaintext, cached, err = cache.get(ctx, "cmd://vend", countingResolver(&calls))
+	require.NoError(t, err)
+	require.False(t, cached)
+	require.Equal(t, "secret-2", string(plaintext))
+}
+
+func TestSecretCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	cache := newSecretCache(CacheOpts{
+		TTL: time.Minute,
+		TTLs: map[string]time.Duration{
+			"cmd":          -1,
+			"vault://long": time.Hour,
+		},
+	})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	var calls atomic.Int32
+	_, cached, err := cache.get(ctx, "vault://short", countingResolver(&calls))
+	require.NoError(t, err)
+	require.False(t, cached)
+	_, cached, err = cache.get(ctx, "vault://long", countingResolver(&calls))
+	require.NoError(t, err)
+	require.False(t, cached)
+
+	now = now.Add(30 * time.Second)
+	_, cached, err = cache.get(ctx, "vault://short", countingResolver(&calls))
+	require.NoError(t, err)
+	require.True(t, cached)
+
+	now = now.Add(time.Minute)
+	_, cached, err = cache.get(ctx, "vault://short", countingResolver(&calls))
+	require.NoError(t, err)
+	require.False(t, cached)
+	_, cached, err = cache.get(ctx, "vault://long", countingResolver(&calls))
+	require.NoError(t, err)
+	require.True(t, cached)
+
+	// negative TTLs disable caching
+	_, cached, err = cache.get(ctx, "cmd://vend", countingResolver(&calls))
+	require.NoError(t, err)
+	require.False(t, cached)
+	_, cached, err = cache.get(ctx, "cmd://vend", countingResolver(&calls))
+	require.NoError(t, err)
+	require.False(t, cached)
+	require.EqualValues(t, 5, calls.Load())
+}
+
+func TestSecretCacheDefaultTTL(t *testing.T) {
+	ctx := context.Background()
+	cache := newSecretCache(CacheOpts{})
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	var calls atomic.Int32
+	_, cached, err := cache.get(ctx, "cmd://vend", countingResolver(&calls))
+	require.NoError(t, err)
+	require.False(t, cached)
+
+	now = now.Add(DefaultCacheTTL - time.Second)
+	_, cached, err = cache.get(ctx, "cmd://vend", countingResolver(&calls))
+	require.NoError(t, err)
+	require.True(t, cached)
+
+	// secrets aren't cached forever by default
+	now = now.Add(time.Second)
+	_, cached, err = cache.get(ctx, "cmd://vend", countingResolver(&calls))
+	require.NoError(t, err)
+	require.False(t, cached)
+}
+
+func TestCacheOptsWithEnv(t *testing.T) {
+	t.Setenv(CacheTTLEnv, "1h")
+	opts, err := CacheOpts{}.WithEnv()
+	require.NoError(t, err)
+	require.Equal(t, time.Hour, opts.TTL)
+
+	// explicit options win
+	opts, err = CacheOpts{TTL: time.Second}.WithEnv()
+	require.NoError(t, err)
+	require.Equal(t, time.Second, opts.TTL)
+
+	t.Setenv(CacheTTLEnv, "0")
+	opts, err = CacheOpts{}.WithEnv()
+	require.NoError(t, err)
+	require.Negative(t, opts.TTL)
+
+	t.Setenv(CacheTTLEnv, "soon")
+	_, err = CacheOpts{}.WithEnv()
+	require.ErrorContains(t, err, "invalid "+CacheTTLEnv)
+}
+
+func TestSecretCacheConcurrent(t *testing.T) {
+	ctx := context.Background()
+	cache := newSecretCache(CacheOpts{})
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	resolve := func(ctx context.Context) ([]byte, error) {
+		<-release
+		return countingResolver(&calls)(ctx)
+	}
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			plaintext, _, err := cache.get(ctx, "vault://path.field", resolve)
+			require.NoError(t, err)
+			require.Equal(t, "secret-1", string(plaintext))
+		}()
+	}
+	time.Sleep(100 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	require.EqualValues(t, 1, calls.Load())
+}
+
+func TestSecretCacheScrubsErrors(t *testing.T) {
+	ctx := context.Background()
+	cache := newSecretCache(CacheOpts{})
+
+	_, _, err := cache.get(ctx, "env://TOKEN", func(context.Context) ([]byte, error) {
+		return []byte("hunter2"), nil
+	})
+	require.NoError(t, err)
+
+	_, _, err = cache.get(ctx, "cmd://leaky", func(context.Context) ([]byte, error) {
+		return nil, fmt.Errorf("command failed: token hunter2 rejected: %w", secrets.ErrNotFound)
+	})
+	require.EqualError(t, err, "command failed: token *** rejected: "+secrets.ErrNotFound.Error())
+	require.True(t, errors.Is(err, secrets.ErrNotFound))
+
+	// failures aren't cached
+	plaintext, cached, err := cache.get(ctx, "cmd://leaky", func(context.Context) ([]byte, error) {
+		return []byte("ok"), nil
+	})
+	require.NoError(t, err)
+	require.False(t, cached)
+	require.Equal(t, "ok", string(plaintext))
+}
@@ -0,0 +1,68 @@
+package secretprovider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/moby/buildkit/session/secrets"
+)
+
+// keyringProvider looks up a password in the system keyring: the Secret
+// Service (via `secret-tool`) on Linux, or the login keychain (via
+// `security`) on macOS.
+//
+// The key has the form "service/user", matching the attributes used by most
+// keyring clients, e.g. Python's keyring library.
+func keyringProvider(ctx context.Context, key string) ([]byte, error) {
+	service, user, ok := strings.Cut(key, "/")
+	if !ok || service == "" || user == "" {
+		return nil, fmt.Errorf("invalid keyring secret %q: expected service/user", key)
+	}
+
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.CommandContext(ctx, "secret-tool", "lookup", "service", service, "username", user)
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "security", "find-generic-password", "-s", service, "-a", user, "-w")
+	default:
+		return nil, fmt.Errorf("keyring secrets are not supported on %s", runtime.GOOS)
+	}
+	cmd.Env = os.Environ()
+
+	plaintext, err := cmd.Output()
+	if err != nil {
+		if keyringNotFound(err) {
+			return nil, fmt.Errorf("keyring secret %q: %w", key, secrets.ErrNotFound)
+		}
+		return nil, fmt.Errorf("failed to look up keyring secret %q: %w", key, cmdError(err))
+	}
+	if runtime.GOOS == "darwin" {
+		// `security -w` terminates the password with a newline
+		plaintext = bytes.TrimSuffix(plaintext, []byte("\n"))
+	}
+	return plaintext, nil
+}
+
+// keyringNotFound reports whether a keyring CLI failed because there's no
+// matching item.
+func keyringNotFound(err error) bool {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return false
+	}
+	switch runtime.GOOS {
+	case "darwin":
+		// errSecItemNotFound
+		return exitErr.ExitCode() == 44
+	default:
+		// secret-tool exits 1 silently if nothing matches
+		return exitErr.ExitCode() == 1 && len(bytes.TrimSpace(exitErr.Stderr)) == 0
+	}
+}
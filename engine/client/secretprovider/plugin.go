@@ -0,0 +1,34 @@
+package secretprovider
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+)
+
+// pluginPrefix is the prefix of executables that provide secrets for schemes
+// with no built-in or registered provider. For example, a
+// `dagger-secret-pass` executable on the client's $PATH resolves
+// "pass://path" by running `dagger-secret-pass path` and reading the
+// plaintext from its stdout.
+//
+// Unlike Register, this lets any client, including the CLI, use new schemes
+// without being rebuilt.
+const pluginPrefix = "dagger-secret-"
+
+// pluginProvider returns a resolver that runs the plugin for the given
+// scheme, if there's one on the $PATH.
+func pluginProvider(scheme string) (SecretResolver, bool) {
+	bin, err := exec.LookPath(pluginPrefix + scheme)
+	if err != nil {
+		return nil, false
+	}
+	return func(ctx context.Context, path string) ([]byte, error) {
+		// #nosec G204
+		plaintext, err := exec.CommandContext(ctx, bin, path).Output()
+		if err != nil {
+			return nil, fmt.Errorf("failed to run secret provider %s: %w", bin, cmdError(err))
+		}
+		return plaintext, nil
+	}, true
+}
@@ -4,55 +4,146 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"slices"
 	"strings"
+	"sync"
 
 	"github.com/moby/buildkit/session/secrets"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
 type SecretResolver func(context.Context, string) ([]byte, error)
 
-var resolvers = map[string]SecretResolver{
-	"env":   envProvider,
-	"file":  fileProvider,
-	"cmd":   cmdProvider,
-	"op":    opProvider,
-	"vault": vaultProvider,
+var (
+	resolvers = map[string]SecretResolver{
+		"env":     envProvider,
+		"file":    fileProvider,
+		"cmd":     cmdProvider,
+		"op":      opProvider,
+		"vault":   vaultProvider,
+		"sops":    sopsProvider,
+		"keyring": keyringProvider,
+		"aws":     awsProvider,
+	}
+	resolversMu sync.RWMutex
+)
+
+// schemeRegexp matches valid secret provider schemes, following the URI
+// scheme syntax from RFC 3986.
+var schemeRegexp = regexp.MustCompile(`^[a-z][a-z0-9+.-]*$`)
+
+// Register makes a secret provider available for the given scheme to every
+// client in this process, so that secrets with URIs like "scheme://path" are
+// resolved by calling resolver with "path".
+//
+// Providers registered this way only exist in the calling process; to add a
+// scheme to other clients such as the CLI, install a plugin executable
+// instead (see pluginPrefix).
+//
+// The built-in providers can't be replaced.
+func Register(scheme string, resolver SecretResolver) error {
+	if !schemeRegexp.MatchString(scheme) {
+		return fmt.Errorf("invalid secret provider scheme: %q", scheme)
+	}
+	if resolver == nil {
+		return fmt.Errorf("secret provider %q: resolver must not be nil", scheme)
+	}
+
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+	if _, ok := resolvers[scheme]; ok {
+		return fmt.Errorf("secret provider %q is already registered", scheme)
+	}
+	resolvers[scheme] = resolver
+	return nil
+}
+
+// ParseID splits a secret URI into its scheme and provider-specific path,
+// without checking whether the scheme has a provider.
+//
+// Providers may be registered by the client, so the engine can only validate
+// the syntax of the URI; ResolverForID checks the scheme on the client side.
+func ParseID(id string) (string, string, error) {
+	scheme, path, ok := strings.Cut(id, "://")
+	if !ok {
+		return "", "", fmt.Errorf("parse %q: malformed id", id)
+	}
+	if !schemeRegexp.MatchString(scheme) {
+		return "", "", fmt.Errorf("parse %q: invalid scheme %q", id, scheme)
+	}
+	return scheme, path, nil
 }
 
 func ResolverForID(id string) (SecretResolver, string, error) {
+	return resolverForID(nil, id)
+}
+
+func resolverForID(extra map[string]SecretResolver, id string) (SecretResolver, string, error) {
 	scheme, path, ok := strings.Cut(id, "://")
 	if !ok {
 		return nil, "", fmt.Errorf("parse %q: malformed id", id)
 	}
 
+	resolversMu.RLock()
 	resolver, ok := resolvers[scheme]
+	resolversMu.RUnlock()
+	if !ok {
+		resolver, ok = extra[scheme]
+	}
 	if !ok {
-		return nil, "", fmt.Errorf("unsupported secret provider: %q", scheme)
+		resolver, ok = pluginProvider(scheme)
+	}
+	if !ok {
+		return nil, "", fmt.Errorf("unsupported secret provider: %q (no %s%s executable found in $PATH)", scheme, pluginPrefix, scheme)
 	}
 	return resolver, path, nil
 }
 
 type SecretProvider struct {
+	// Providers only available to this client, in addition to the ones that
+	// are built-in or added with Register.
+	resolvers map[string]SecretResolver
+
+	cache *secretCache
 }
 
-func NewSecretProvider() SecretProvider {
-	return SecretProvider{}
+func NewSecretProvider(resolvers map[string]SecretResolver, cacheOpts CacheOpts) SecretProvider {
+	return SecretProvider{
+		resolvers: resolvers,
+		cache:     newSecretCache(cacheOpts),
+	}
 }
 
 func (sp SecretProvider) Register(server *grpc.Server) {
 	secrets.RegisterSecretsServer(server, sp)
 }
 
+// Refresh drops the cached plaintext of the secret with the given URI, so
+// that it's resolved again the next time it's used, e.g. after rotating it.
+func (sp SecretProvider) Refresh(uri string) {
+	if sp.cache == nil {
+		return
+	}
+	sp.cache.refresh(uri)
+}
+
 func (sp SecretProvider) GetSecret(ctx context.Context, req *secrets.GetSecretRequest) (*secrets.GetSecretResponse, error) {
-	resolver, u, err := ResolverForID(req.ID)
+	resolver, u, err := resolverForID(sp.resolvers, req.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	plaintext, err := resolver(ctx, u)
+	if md, ok := metadata.FromIncomingContext(ctx); ok && slices.Contains(md.Get(RefreshHeader), "true") {
+		sp.Refresh(req.ID)
+	}
+
+	plaintext, cached, err := sp.cache.get(ctx, req.ID, func(ctx context.Context) ([]byte, error) {
+		return resolver(ctx, u)
+	})
 	if err != nil {
 		if errors.Is(err, secrets.ErrNotFound) {
 			return nil, status.Error(codes.NotFound, err.Error())
@@ -60,6 +151,13 @@ func (sp SecretProvider) GetSecret(ctx context.Context, req *secrets.GetSecretRe
 		return nil, err
 	}
 
+	cacheStatus := "miss"
+	if cached {
+		cacheStatus = "hit"
+	}
+	// best effort, this fails if not called through gRPC
+	_ = grpc.SetHeader(ctx, metadata.Pairs(CacheStatusHeader, cacheStatus))
+
 	return &secrets.GetSecretResponse{
 		Data: plaintext,
 	}, nil
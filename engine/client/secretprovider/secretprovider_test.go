@@ -0,0 +1,218 @@
+package secretprovider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/moby/buildkit/session/secrets"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestRegister(t *testing.T) {
+	resolver := func(_ context.Context, path string) ([]byte, error) {
+		return []byte("registered " + path), nil
+	}
+	require.NoError(t, Register("test-registered", resolver))
+
+	r, path, err := ResolverForID("test-registered://foo/bar")
+	require.NoError(t, err)
+	require.Equal(t, "foo/bar", path)
+	plaintext, err := r(context.Background(), path)
+	require.NoError(t, err)
+	require.Equal(t, "registered foo/bar", string(plaintext))
+
+	require.ErrorContains(t, Register("test-registered", resolver), "already registered")
+	require.ErrorContains(t, Register("env", resolver), "already registered")
+	require.ErrorContains(t, Register("Not A Scheme", resolver), "invalid secret provider scheme")
+	require.ErrorContains(t, Register("test-nil", nil), "must not be nil")
+}
+
+func TestResolverForIDUnsupported(t *testing.T) {
+	_, _, err := ResolverForID("wtf://huh")
+	require.ErrorContains(t, err, `unsupported secret provider: "wtf"`)
+
+	_, _, err = ResolverForID("huh")
+	require.ErrorContains(t, err, "malformed id")
+}
+
+func TestPluginProvider(t *testing.T) {
+	fakeCommand(t, "dagger-secret-test-plugin", `
+if [ "$1" = "team/token" ]; then
+  printf hunter2
+  exit 0
+fi
+echo "no such secret: $1" >&2
+exit 1
+`)
+	ctx := context.Background()
+
+	r, path, err := ResolverForID("test-plugin://team/token")
+	require.NoError(t, err)
+	require.Equal(t, "team/token", path)
+	plaintext, err := r(ctx, path)
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", string(plaintext))
+
+	r, path, err = ResolverForID("test-plugin://team/nope")
+	require.NoError(t, err)
+	_, err = r(ctx, path)
+	require.ErrorContains(t, err, "no such secret: team/nope")
+}
+
+func TestParseID(t *testing.T) {
+	scheme, path, err := ParseID("custom+v2://a/b#c")
+	require.NoError(t, err)
+	require.Equal(t, "custom+v2", scheme)
+	require.Equal(t, "a/b#c", path)
+
+	_, _, err = ParseID("nope")
+	require.ErrorContains(t, err, "malformed id")
+
+	_, _, err = ParseID("in valid://x")
+	require.ErrorContains(t, err, "invalid scheme")
+}
+
+func TestSecretProviderClientResolvers(t *testing.T) {
+	sp := NewSecretProvider(map[string]SecretResolver{
+		"client": func(_ context.Context, path string) ([]byte, error) {
+			if path == "missing" {
+				return nil, secrets.ErrNotFound
+			}
+			return []byte("client " + path), nil
+		},
+	}, CacheOpts{})
+	ctx := context.Background()
+
+	resp, err := sp.GetSecret(ctx, &secrets.GetSecretRequest{ID: "client://token"})
+	require.NoError(t, err)
+	require.Equal(t, "client token", string(resp.Data))
+
+	_, err = sp.GetSecret(ctx, &secrets.GetSecretRequest{ID: "client://missing"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	// client resolvers aren't visible to other clients
+	_, err = NewSecretProvider(nil, CacheOpts{}).GetSecret(ctx, &secrets.GetSecretRequest{ID: "client://token"})
+	require.ErrorContains(t, err, "unsupported secret provider")
+}
+
+func TestSecretProviderRefresh(t *testing.T) {
+	var calls int
+	sp := NewSecretProvider(map[string]SecretResolver{
+		"client": func(_ context.Context, path string) ([]byte, error) {
+			calls++
+			return []byte(fmt.Sprintf("%s-%d", path, calls)), nil
+		},
+	}, CacheOpts{})
+	ctx := context.Background()
+
+	resp, err := sp.GetSecret(ctx, &secrets.GetSecretRequest{ID: "client://token"})
+	require.NoError(t, err)
+	require.Equal(t, "token-1", string(resp.Data))
+	resp, err = sp.GetSecret(ctx, &secrets.GetSecretRequest{ID: "client://token"})
+	require.NoError(t, err)
+	require.Equal(t, "token-1", string(resp.Data))
+
+	refreshCtx := metadata.NewIncomingContext(ctx, metadata.Pairs(RefreshHeader, "true"))
+	resp, err = sp.GetSecret(refreshCtx, &secrets.GetSecretRequest{ID: "client://token"})
+	require.NoError(t, err)
+	require.Equal(t, "token-2", string(resp.Data))
+	resp, err = sp.GetSecret(ctx, &secrets.GetSecretRequest{ID: "client://token"})
+	require.NoError(t, err)
+	require.Equal(t, "token-2", string(resp.Data))
+}
+
+func TestSopsProvider(t *testing.T) {
+	// stand-in that prints its arguments, one per line
+	fakeCommand(t, "sops", `printf '%s\n' "$@"`)
+	ctx := context.Background()
+
+	out, err := sopsProvider(ctx, "/secrets/app.yaml#db.hosts.0")
+	require.NoError(t, err)
+	require.Equal(t, "--decrypt\n--extract\n[\"db\"][\"hosts\"][0]\n/secrets/app.yaml\n", string(out))
+
+	out, err = sopsProvider(ctx, "/secrets/app.env")
+	require.NoError(t, err)
+	require.Equal(t, "--decrypt\n/secrets/app.env\n", string(out))
+
+	_, err = sopsProvider(ctx, "#key")
+	require.ErrorContains(t, err, "missing file path")
+
+	fakeCommand(t, "sops", `echo "Failed to get the data key" >&2; exit 128`)
+	_, err = sopsProvider(ctx, "/secrets/app.yaml#db.password")
+	require.ErrorContains(t, err, "Failed to get the data key")
+}
+
+func TestKeyringProvider(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("keyring stand-in emulates secret-tool")
+	}
+	fakeCommand(t, "secret-tool", `
+if [ "$*" = "lookup service myapp username alice" ]; then
+  printf hunter2
+  exit 0
+fi
+exit 1
+`)
+	ctx := context.Background()
+
+	out, err := keyringProvider(ctx, "myapp/alice")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", string(out))
+
+	_, err = keyringProvider(ctx, "myapp/bob")
+	require.ErrorIs(t, err, secrets.ErrNotFound)
+
+	_, err = keyringProvider(ctx, "myapp")
+	require.ErrorContains(t, err, "expected service/user")
+}
+
+func TestAWSProvider(t *testing.T) {
+	fakeCommand(t, "aws", `
+if [ "$*" = "secretsmanager get-secret-value --secret-id prod/db --query SecretString --output text" ]; then
+  echo '{"user":"admin","password":"hunter2","port":5432}'
+  exit 0
+fi
+echo "An error occurred (ResourceNotFoundException)" >&2
+exit 254
+`)
+	ctx := context.Background()
+
+	out, err := awsProvider(ctx, "prod/db")
+	require.NoError(t, err)
+	require.Equal(t, `{"user":"admin","password":"hunter2","port":5432}`, string(out))
+
+	out, err = awsProvider(ctx, "prod/db#password")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", string(out))
+
+	out, err = awsProvider(ctx, "prod/db#port")
+	require.NoError(t, err)
+	require.Equal(t, "5432", string(out))
+
+	_, err = awsProvider(ctx, "prod/db#nope")
+	require.ErrorContains(t, err, `has no field "nope"`)
+
+	_, err = awsProvider(ctx, "staging/db")
+	require.ErrorContains(t, err, "ResourceNotFoundException")
+}
+
+// fakeCommand puts a shell script with the given name and body first in
+// $PATH, as a stand-in for a secret manager CLI.
+func fakeCommand(t *testing.T, name, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("stand-in commands are shell scripts")
+	}
+
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, name), []byte("#!/bin/sh\n"+script), 0o755) // #nosec G306
+	require.NoError(t, err)
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
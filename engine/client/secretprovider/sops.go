@@ -0,0 +1,77 @@
+package secretprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/dagger/dagger/engine/client/pathutil"
+)
+
+// sopsProvider decrypts a sops-encrypted file with the `sops` CLI, using the
+// key configuration from the environment (e.g. SOPS_AGE_KEY_FILE).
+//
+// The key has the form "path/to/file#key", where key is an optional dotted
+// path into the decrypted document, e.g. "secrets.yaml#db.password". Without
+// it, the whole decrypted file is returned.
+func sopsProvider(ctx context.Context, key string) ([]byte, error) {
+	path, field, _ := strings.Cut(key, "#")
+	if path == "" {
+		return nil, fmt.Errorf("invalid sops secret %q: missing file path", key)
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	path, err = pathutil.ExpandHomeDir(homeDir, path)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"--decrypt"}
+	if field != "" {
+		args = append(args, "--extract", sopsExtractPath(field))
+	}
+	args = append(args, path)
+
+	// #nosec G204
+	cmd := exec.CommandContext(ctx, "sops", args...)
+	cmd.Env = os.Environ()
+
+	plaintext, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt sops secret %q: %w", key, cmdError(err))
+	}
+	return plaintext, nil
+}
+
+// sopsExtractPath converts a dotted path like "db.hosts.0" to the syntax
+// expected by `sops --extract`, i.e. `["db"]["hosts"][0]`.
+func sopsExtractPath(field string) string {
+	var extract strings.Builder
+	for _, part := range strings.Split(field, ".") {
+		if _, err := strconv.Atoi(part); err == nil {
+			fmt.Fprintf(&extract, "[%s]", part)
+		} else {
+			fmt.Fprintf(&extract, "[%q]", part)
+		}
+	}
+	return extract.String()
+}
+
+// cmdError includes the stderr of a failed command in its error, since
+// secret CLIs usually explain what went wrong there.
+func cmdError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		if stderr := strings.TrimSpace(string(exitErr.Stderr)); stderr != "" {
+			return fmt.Errorf("%w: %s", err, stderr)
+		}
+	}
+	return err
+}
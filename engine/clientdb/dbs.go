@@ -1,6 +1,7 @@
 package clientdb
 
 import (
+	"context"
 	"database/sql"
 	_ "embed"
 	"errors"
@@ -8,6 +9,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -49,6 +51,19 @@ func (dbs *DBs) Open(clientID string) (*sql.DB, error) {
 	if err := os.MkdirAll(filepath.Dir(dbPath), 0700); err != nil {
 		return nil, fmt.Errorf("mkdir %s: %w", filepath.Dir(dbPath), err)
 	}
+	return open(dbPath)
+}
+
+// OpenFile opens an existing database file, e.g. one copied from an engine,
+// for reading.
+func OpenFile(dbPath string) (*sql.DB, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, err
+	}
+	return open(dbPath)
+}
+
+func open(dbPath string) (*sql.DB, error) {
 	connURL := &url.URL{
 		Scheme: "file",
 		Host:   "",
@@ -114,6 +129,103 @@ func (dbs *DBs) GC(keep map[string]bool) error {
 	return errs
 }
 
+// Clients returns the IDs of the clients that have a database, most recently
+// modified first.
+func (dbs *DBs) Clients() ([]string, error) {
+	ents, err := os.ReadDir(dbs.Root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// no databases found
+			return nil, nil
+		}
+		return nil, fmt.Errorf("readdir %s: %w", dbs.Root, err)
+	}
+	type clientDB struct {
+		clientID string
+		modTime  time.Time
+	}
+	var clients []clientDB
+	for _, ent := range ents {
+		clientID, ext, ok := strings.Cut(ent.Name(), ".")
+		if !ok || ext != "db" {
+			continue
+		}
+		info, err := ent.Info()
+		if err != nil {
+			return nil, fmt.Errorf("stat %s: %w", ent.Name(), err)
+		}
+		clients = append(clients, clientDB{clientID, info.ModTime()})
+	}
+	sort.SliceStable(clients, func(i, j int) bool {
+		return clients[i].modTime.After(clients[j].modTime)
+	})
+	clientIDs := make([]string, len(clients))
+	for i, client := range clients {
+		clientIDs[i] = client.clientID
+	}
+	return clientIDs, nil
+}
+
+// LastTrace returns the trace of the most recently recorded span.
+func (dbs *DBs) LastTrace(ctx context.Context) (string, error) {
+	clientIDs, err := dbs.Clients()
+	if err != nil {
+		return "", err
+	}
+	for _, clientID := range clientIDs {
+		traceID, err := query(dbs, clientID, func(q *Queries) (string, error) {
+			return q.SelectLastTraceID(ctx)
+		})
+		if errors.Is(err, sql.ErrNoRows) {
+			// no spans yet
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return traceID, nil
+	}
+	return "", fmt.Errorf("no client recorded a trace in %s", dbs.Root)
+}
+
+// FindTrace returns the client that recorded the most spans of the trace,
+// i.e. the client that started it, since the spans of nested clients are also
+// recorded by their parents.
+func (dbs *DBs) FindTrace(ctx context.Context, traceID string) (string, error) {
+	clientIDs, err := dbs.Clients()
+	if err != nil {
+		return "", err
+	}
+	var found string
+	var most int64
+	for _, clientID := range clientIDs {
+		count, err := query(dbs, clientID, func(q *Queries) (int64, error) {
+			return q.SelectTraceSpanCount(ctx, traceID)
+		})
+		if err != nil {
+			return "", err
+		}
+		if count > most {
+			found, most = clientID, count
+		}
+	}
+	if found == "" {
+		return "", fmt.Errorf("no client recorded trace %s in %s", traceID, dbs.Root)
+	}
+	return found, nil
+}
+
+func query[T any](dbs *DBs, clientID string, fn func(*Queries) (T, error)) (res T, rerr error) {
+	db, err := dbs.Open(clientID)
+	if err != nil {
+		return res, err
+	}
+	defer func() {
+		rerr = errors.Join(rerr, db.Close())
+	}()
+	return fn(New(db))
+}
+
 func (dbs *DBs) path(clientID string) string {
 	return filepath.Join(dbs.Root, clientID+".db")
 }
@@ -55,3 +55,12 @@ SELECT * FROM logs WHERE id > ? ORDER BY id ASC LIMIT ?;
 
 -- name: SelectMetricsSince :many
 SELECT * FROM metrics WHERE id > ? ORDER BY id ASC LIMIT ?;
+
+-- name: SelectCallSpans :many
+SELECT * FROM spans WHERE attributes LIKE '%"dagger.io/dag.call"%' ORDER BY id DESC;
+
+-- name: SelectLastTraceID :one
+SELECT trace_id FROM spans ORDER BY id DESC LIMIT 1;
+
+-- name: SelectTraceSpanCount :one
+SELECT COUNT(*) FROM spans WHERE trace_id = ?;
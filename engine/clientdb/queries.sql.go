@@ -151,6 +151,66 @@ func (q *Queries) InsertSpan(ctx context.Context, arg InsertSpanParams) (int64,
 	return id, err
 }
 
+const selectCallSpans = `-- name: SelectCallSpans :many
+SELECT id, trace_id, span_id, trace_state, parent_span_id, flags, name, kind, start_time, end_time, attributes, dropped_attributes_count, events, dropped_events_count, links, dropped_links_count, status_code, status_message, instrumentation_scope, resource, resource_schema_url FROM spans WHERE attributes LIKE '%"dagger.io/dag.call"%' ORDER BY id DESC
+`
+
+func (q *Queries) SelectCallSpans(ctx context.Context) ([]Span, error) {
+	rows, err := q.db.QueryContext(ctx, selectCallSpans)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Span
+	for rows.Next() {
+		var i Span
+		if err := rows.Scan(
+			&i.ID,
+			&i.TraceID,
+			&i.SpanID,
+			&i.TraceState,
+			&i.ParentSpanID,
+			&i.Flags,
+			&i.Name,
+			&i.Kind,
+			&i.StartTime,
+			&i.EndTime,
+			&i.Attributes,
+			&i.DroppedAttributesCount,
+			&i.Events,
+			&i.DroppedEventsCount,
+			&i.Links,
+			&i.DroppedLinksCount,
+			&i.StatusCode,
+			&i.StatusMessage,
+			&i.InstrumentationScope,
+			&i.Resource,
+			&i.ResourceSchemaUrl,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const selectLastTraceID = `-- name: SelectLastTraceID :one
+SELECT trace_id FROM spans ORDER BY id DESC LIMIT 1
+`
+
+func (q *Queries) SelectLastTraceID(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, selectLastTraceID)
+	var trace_id string
+	err := row.Scan(&trace_id)
+	return trace_id, err
+}
+
 const selectLogsSince = `-- name: SelectLogsSince :many
 SELECT id, trace_id, span_id, timestamp, severity_number, severity_text, body, attributes, instrumentation_scope, resource, resource_schema_url FROM logs WHERE id > ? ORDER BY id ASC LIMIT ?
 `
@@ -280,3 +340,14 @@ func (q *Queries) SelectSpansSince(ctx context.Context, arg SelectSpansSincePara
 	}
 	return items, nil
 }
+
+const selectTraceSpanCount = `-- name: SelectTraceSpanCount :one
+SELECT COUNT(*) FROM spans WHERE trace_id = ?
+`
+
+func (q *Queries) SelectTraceSpanCount(ctx context.Context, traceID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, selectTraceSpanCount, traceID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
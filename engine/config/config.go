@@ -29,6 +29,10 @@ type Config struct {
 
 	// Security allows configuring various security settings for the engine.
 	Security Security `json:"security,omitempty"`
+
+	// RemoteCache configures storage for sharing the engine's cache between
+	// engines, without Dagger Cloud.
+	RemoteCache RemoteCache `json:"remoteCache,omitempty"`
 }
 
 type LogLevel string
@@ -92,6 +96,27 @@ type GCConfig struct {
 	// an automatic default will be generated from the top-level disk space
 	// parameters.
 	Policies []GCPolicy `json:"policies,omitempty"`
+
+	// ResultsSpace is the maximum amount of disk space for the results of API
+	// calls persisted across sessions. Defaults to 1% of the default policy's
+	// MaxUsedSpace.
+	ResultsSpace DiskSpace `json:"resultsSpace,omitempty"`
+
+	// SessionCache limits the memory used by each session to cache the
+	// results of API calls.
+	SessionCache SessionCacheConfig `json:"sessionCache,omitempty"`
+}
+
+type SessionCacheConfig struct {
+	// MaxUsedMemory is the approximate amount of memory for the results of
+	// API calls cached by a session, as a number of bytes or a string with a
+	// byte unit suffix. Results that can be recomputed are evicted above this
+	// limit, least recently used first. Unlimited by default.
+	MaxUsedMemory DiskSpace `json:"maxUsedMemory,omitempty"`
+
+	// KeepDuration is how long a session keeps the results of API calls that
+	// aren't used, if they can be recomputed. Forever by default.
+	KeepDuration Duration `json:"keepDuration,omitempty"`
 }
 
 type GCPolicy struct {
@@ -209,3 +234,47 @@ type Security struct {
 	// privileged, and is a basic form of security hardening.
 	InsecureRootCapabilities *bool `json:"insecureRootCapabilities,omitempty"`
 }
+
+type RemoteCache struct {
+	// Type is the kind of storage to keep the cache in - either "registry" for
+	// an OCI registry, or "s3" for an S3-compatible bucket. If unset, the
+	// remote cache is disabled.
+	Type string `json:"type,omitempty" jsonschema:"enum=registry,enum=s3"`
+
+	// Ref is the reference to store the cache under in a registry, e.g.
+	// "registry.example.com/dagger/cache:main". If the reference has no tag,
+	// "cache" is used.
+	Ref string `json:"ref,omitempty"`
+
+	// Insecure allows connecting to the registry over plain HTTP.
+	Insecure bool `json:"insecure,omitempty"`
+
+	// Endpoint is the URL of an S3-compatible server, e.g. a MinIO instance.
+	// If unset, AWS S3 is used. Credentials are read from the standard AWS
+	// environment variables and config files.
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Bucket is the name of the S3 bucket to store the cache in.
+	Bucket string `json:"bucket,omitempty"`
+
+	// Prefix is prepended to the key of every object in the S3 bucket.
+	Prefix string `json:"prefix,omitempty"`
+
+	// Region is the S3 region of the bucket.
+	Region string `json:"region,omitempty"`
+
+	// ImportPeriod is how often to import cache from the remote storage.
+	ImportPeriod Duration `json:"importPeriod,omitempty"`
+
+	// ExportPeriod is how often to export cache to the remote storage. The
+	// cache is also exported when the engine shuts down.
+	ExportPeriod Duration `json:"exportPeriod,omitempty"`
+
+	// ExportTimeout is the maximum amount of time a single export may take.
+	ExportTimeout Duration `json:"exportTimeout,omitempty"`
+
+	// MaxAge is how long cache entries and cache volumes are kept in the
+	// remote storage after they were last exported by any engine. Defaults to
+	// 7 days.
+	MaxAge Duration `json:"maxAge,omitempty"`
+}
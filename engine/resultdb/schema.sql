@@ -0,0 +1,18 @@
+-- Persisted results of dagql selections, keyed by the digest of their ID.
+
+CREATE TABLE IF NOT EXISTS results (
+    digest TEXT PRIMARY KEY,
+    value BLOB NOT NULL, -- JSON encoded result
+    size INTEGER NOT NULL,
+    created_at INTEGER NOT NULL, -- Nanoseconds from epoch
+    last_used_at INTEGER NOT NULL -- Nanoseconds from epoch
+) STRICT;
+
+CREATE INDEX IF NOT EXISTS results_last_used_at ON results (last_used_at);
+
+-- The version of the engine that stored the results, since digests are only
+-- stable between invocations of the same engine.
+CREATE TABLE IF NOT EXISTS meta (
+    key TEXT PRIMARY KEY,
+    value TEXT NOT NULL
+) STRICT;
@@ -0,0 +1,224 @@
+package resultdb
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	_ "modernc.org/sqlite"
+)
+
+//go:embed schema.sql
+var Schema string
+
+// Store is a dagql.ResultStore backed by a sqlite database, which persists
+// results across sessions and engine restarts.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the database at the given path, creating it if needed. Results
+// stored by a different engine version are removed.
+func Open(dbPath string, version string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0700); err != nil {
+		return nil, fmt.Errorf("mkdir %s: %w", filepath.Dir(dbPath), err)
+	}
+	connURL := &url.URL{
+		Scheme: "file",
+		Host:   "",
+		Path:   dbPath,
+		RawQuery: url.Values{
+			"_pragma": []string{
+				"journal_mode=WAL",   // readers don't block writers and vice versa
+				"synchronous=OFF",    // a lost result is just a cache miss
+				"busy_timeout=10000", // wait up to 10s when there are concurrent writers
+			},
+			"_txlock": []string{"immediate"}, // use BEGIN IMMEDIATE for transactions
+		}.Encode(),
+	}
+	db, err := sql.Open("sqlite", connURL.String())
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", connURL, err)
+	}
+	if _, err := db.Exec(Schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate: %w", err)
+	}
+	store := &Store{db: db}
+	if err := store.setVersion(version); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (store *Store) setVersion(version string) error {
+	tx, err := store.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	var prevVersion string
+	err = tx.QueryRow(`SELECT value FROM meta WHERE key = 'version'`).Scan(&prevVersion)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("select version: %w", err)
+	}
+	if prevVersion == version {
+		return nil
+	}
+	if _, err := tx.Exec(`DELETE FROM results`); err != nil {
+		return fmt.Errorf("delete results of version %q: %w", prevVersion, err)
+	}
+	if _, err := tx.Exec(
+		`INSERT INTO meta (key, value) VALUES ('version', ?) ON CONFLICT (key) DO UPDATE SET value = excluded.value`,
+		version,
+	); err != nil {
+		return fmt.Errorf("set version: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// Load returns the result stored for the digest, if any, and marks it as
+// used.
+func (store *Store) Load(ctx context.Context, key digest.Digest) ([]byte, bool, error) {
+	var value []byte
+	err := store.db.QueryRowContext(ctx,
+		`UPDATE results SET last_used_at = ? WHERE digest = ? RETURNING value`,
+		time.Now().UnixNano(), key.String(),
+	).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("load %s: %w", key, err)
+	}
+	return value, true, nil
+}
+
+// Store stores the result for the digest, replacing any previous result.
+func (store *Store) Store(ctx context.Context, key digest.Digest, value []byte) error {
+	now := time.Now().UnixNano()
+	_, err := store.db.ExecContext(ctx,
+		`INSERT INTO results (digest, value, size, created_at, last_used_at) VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (digest) DO UPDATE SET value = excluded.value, size = excluded.size, last_used_at = excluded.last_used_at`,
+		key.String(), value, len(value), now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("store %s: %w", key, err)
+	}
+	return nil
+}
+
+// Size returns the number of stored results and their total size in bytes.
+func (store *Store) Size(ctx context.Context) (count int, size int64, err error) {
+	err = store.db.QueryRowContext(ctx,
+		`SELECT COUNT(*), COALESCE(SUM(size), 0) FROM results`,
+	).Scan(&count, &size)
+	if err != nil {
+		return 0, 0, fmt.Errorf("size: %w", err)
+	}
+	return count, size, nil
+}
+
+// PruneOpts configures which results Prune removes.
+type PruneOpts struct {
+	// All removes every result, ignoring the other options.
+	All bool
+	// MaxUsedSpace is the total size in bytes to prune the results down to,
+	// removing the least recently used first. Zero means unlimited.
+	MaxUsedSpace int64
+	// KeepDuration keeps results used within the duration, even if they
+	// exceed MaxUsedSpace.
+	KeepDuration time.Duration
+}
+
+// Prune removes results according to the options, and returns the number of
+// results and bytes removed.
+func (store *Store) Prune(ctx context.Context, opts PruneOpts) (count int, size int64, err error) {
+	if !opts.All && opts.MaxUsedSpace <= 0 {
+		return 0, 0, nil
+	}
+
+	tx, err := store.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback()
+
+	var total int64
+	if err := tx.QueryRowContext(ctx,
+		`SELECT COALESCE(SUM(size), 0) FROM results`,
+	).Scan(&total); err != nil {
+		return 0, 0, fmt.Errorf("size: %w", err)
+	}
+	if opts.All {
+		res, err := tx.ExecContext(ctx, `DELETE FROM results`)
+		if err != nil {
+			return 0, 0, fmt.Errorf("delete: %w", err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return 0, 0, fmt.Errorf("delete: %w", err)
+		}
+		if err := tx.Commit(); err != nil {
+			return 0, 0, fmt.Errorf("commit: %w", err)
+		}
+		return int(n), total, nil
+	}
+	if total <= opts.MaxUsedSpace {
+		return 0, 0, nil
+	}
+
+	keepAfter := time.Now().UnixNano()
+	if opts.KeepDuration > 0 {
+		keepAfter = time.Now().Add(-opts.KeepDuration).UnixNano()
+	}
+	// remove the least recently used results until the rest fit
+	rows, err := tx.QueryContext(ctx,
+		`SELECT digest, size FROM results WHERE last_used_at < ? ORDER BY last_used_at ASC`,
+		keepAfter,
+	)
+	if err != nil {
+		return 0, 0, fmt.Errorf("select: %w", err)
+	}
+	var prune []string
+	for total-size > opts.MaxUsedSpace && rows.Next() {
+		var dgst string
+		var entSize int64
+		if err := rows.Scan(&dgst, &entSize); err != nil {
+			rows.Close()
+			return 0, 0, fmt.Errorf("scan: %w", err)
+		}
+		prune = append(prune, dgst)
+		size += entSize
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return 0, 0, fmt.Errorf("select: %w", err)
+	}
+
+	for _, dgst := range prune {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM results WHERE digest = ?`, dgst); err != nil {
+			return 0, 0, fmt.Errorf("delete %s: %w", dgst, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("commit: %w", err)
+	}
+	return len(prune), size, nil
+}
+
+// Close closes the database.
+func (store *Store) Close() error {
+	return store.db.Close()
+}
@@ -0,0 +1,100 @@
+package resultdb
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	dbPath := filepath.Join(t.TempDir(), "results.db")
+
+	store, err := Open(dbPath, "v1")
+	require.NoError(t, err)
+	require.NoError(t, store.Store(ctx, digest.FromString("a"), []byte(`"hello"`)))
+	require.NoError(t, store.Close())
+
+	// results survive reopening
+	store, err = Open(dbPath, "v1")
+	require.NoError(t, err)
+
+	val, ok, err := store.Load(ctx, digest.FromString("a"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, `"hello"`, string(val))
+
+	_, ok, err = store.Load(ctx, digest.FromString("b"))
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, store.Store(ctx, digest.FromString("a"), []byte(`"bye"`)))
+	val, ok, err = store.Load(ctx, digest.FromString("a"))
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, `"bye"`, string(val))
+
+	count, size, err := store.Size(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+	require.EqualValues(t, 5, size)
+	require.NoError(t, store.Close())
+
+	// results of other engine versions are removed
+	store, err = Open(dbPath, "v2")
+	require.NoError(t, err)
+	_, ok, err = store.Load(ctx, digest.FromString("a"))
+	require.NoError(t, err)
+	require.False(t, ok)
+	require.NoError(t, store.Close())
+}
+
+func TestStorePrune(t *testing.T) {
+	ctx := context.Background()
+
+	store, err := Open(filepath.Join(t.TempDir(), "results.db"), "v1")
+	require.NoError(t, err)
+	defer store.Close()
+
+	value := []byte(strings.Repeat("x", 10))
+	for _, key := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, store.Store(ctx, digest.FromString(key), value))
+	}
+	// a is now the most recently used
+	_, ok, err := store.Load(ctx, digest.FromString("a"))
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// nothing to prune without a limit
+	count, _, err := store.Prune(ctx, PruneOpts{})
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	// everything was used recently
+	count, _, err = store.Prune(ctx, PruneOpts{MaxUsedSpace: 20, KeepDuration: time.Hour})
+	require.NoError(t, err)
+	require.Zero(t, count)
+
+	count, size, err := store.Prune(ctx, PruneOpts{MaxUsedSpace: 20})
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.EqualValues(t, 20, size)
+	for key, exists := range map[string]bool{"a": true, "b": false, "c": false, "d": true} {
+		_, ok, err := store.Load(ctx, digest.FromString(key))
+		require.NoError(t, err)
+		require.Equal(t, exists, ok, key)
+	}
+
+	count, size, err = store.Prune(ctx, PruneOpts{All: true})
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+	require.EqualValues(t, 20, size)
+	count, _, err = store.Size(ctx)
+	require.NoError(t, err)
+	require.Zero(t, count)
+}
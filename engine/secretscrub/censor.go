@@ -0,0 +1,176 @@
+package secretscrub
+
+import (
+	"bytes"
+
+	"golang.org/x/text/transform"
+)
+
+var (
+	// scrubString will be used as replacement for found secrets:
+	scrubString = []byte("***")
+)
+
+// NewTransformer returns a Transformer that replaces all occurrences of the
+// given secrets with "***". Secrets with surrounding whitespace are also
+// scrubbed with it trimmed, and empty secrets are ignored.
+func NewTransformer(secrets [][]byte) transform.Transformer {
+	trie := &Trie{}
+	for _, s := range secrets {
+		// Skip empty secrets:
+		if len(s) == 0 {
+			continue
+		}
+		trie.Insert(s, scrubString)
+		if strimmed := bytes.TrimSpace(s); len(strimmed) != len(s) && len(strimmed) != 0 {
+			trie.Insert(strimmed, scrubString)
+		}
+	}
+	return &censor{
+		trieRoot: trie,
+		trie:     trie.Iter(),
+		// NOTE: keep these sizes the same as the default transform sizes
+		srcBuf: make([]byte, 0, 4096),
+		dstBuf: make([]byte, 0, 4096),
+	}
+}
+
+// String scrubs the given secrets from s.
+func String(s string, secrets ...[]byte) string {
+	scrubbed, _, err := transform.String(NewTransformer(secrets), s)
+	if err != nil {
+		// the censor never fails, but don't risk leaking anything if it does
+		return string(scrubString)
+	}
+	return scrubbed
+}
+
+// censor is a custom Transformer for replacing all keys in a target trie with
+// their values.
+type censor struct {
+	// trieRoot is the root of the trie
+	trieRoot *Trie
+	// trie is the current node we are at in the trie
+	trie *TrieIter
+	// match is the last trie node that we found a match from
+	match    *TrieIter
+	matchLen int
+
+	// srcBuf is the source buffer, which contains bytes read from the src that
+	// are partial matches against the trie
+	srcBuf []byte
+	// destBuf is the destination buffer, which contains bytes that have been
+	// sanitized by the censor and are ready to be copied out
+	dstBuf []byte
+}
+
+// Transform ingests src bytes, and outputs sanitized bytes to dst.
+//
+// Unlike some other secret scrubbing implementations, this aims to sanitize
+// bytes *as soon as possible*. The moment that we know a byte is not part of a
+// secret, we should output it into dst - even if this would break up a provided
+// src into multiple dsts over multiple calls to Transform.
+func (c *censor) Transform(dst, src []byte, atEOF bool) (nDst, nSrc int, err error) {
+	for {
+		// flush the destination buffer
+		k := copy(dst[nDst:], c.dstBuf)
+		nDst += k
+		if nDst == len(dst) {
+			c.dstBuf = c.dstBuf[k:]
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		c.dstBuf = c.dstBuf[:0]
+
+		if !atEOF && nSrc == len(src) {
+			// no more source bytes, we're done!
+			return nDst, nSrc, nil
+		}
+		if atEOF && nSrc == len(src) && len(c.srcBuf) == 0 {
+			// no more source bytes, or buffered source bytes, we're done!
+			// (when atEOF, we won't get called again, so we need to make sure
+			// to flush everything)
+			return nDst, nSrc, nil
+		}
+
+		// read more source bytes, until either we've read all the source
+		// bytes, or we've filled the destination buffer
+		for ; nSrc < len(src) && nDst+len(c.dstBuf) < len(dst); nSrc++ {
+			ch := src[nSrc]
+			c.trie = c.trie.Step(ch)
+
+			if c.trie == nil {
+				// we had found a match somewhere in this string previously, so
+				// flush the secret replacement and the rest of the source
+				// buffer
+				if c.match != nil {
+					c.trie = c.trieRoot.Iter()
+					c.dstBuf = append(c.dstBuf, c.match.Value()...)
+					c.dstBuf = append(c.dstBuf, c.srcBuf[c.matchLen:]...)
+					c.srcBuf = c.srcBuf[:0]
+					c.match = nil
+					c.matchLen = 0
+
+					// process the current byte again. we do this because this
+					// *might* cause us to try to flush more than len(dst) - nDst
+					// bytes into the destination buffer, so we should avoid
+					// consuming the next byte in this case.
+					nSrc--
+					continue
+				}
+
+				// no match possible, so flush the source buffer into the
+				// destination buffer
+				if len(c.srcBuf) != 0 {
+					c.trie = c.trieRoot.Iter()
+					c.dstBuf = append(c.dstBuf, c.srcBuf...)
+					c.srcBuf = c.srcBuf[:0]
+
+					// process the current byte again - same reason as above
+					nSrc--
+					continue
+				}
+
+				// put the current byte either into the destination buffer, or
+				// the source buffer, depending on whether it's a partial match
+				c.trie = c.trieRoot.Step(ch)
+				if c.trie == nil {
+					c.trie = c.trieRoot.Iter()
+					c.dstBuf = append(c.dstBuf, ch)
+				} else if replace := c.trie.Value(); replace != nil {
+					c.trie = c.trieRoot.Iter()
+					c.dstBuf = append(c.dstBuf, replace...)
+				} else {
+					c.srcBuf = append(c.srcBuf, ch)
+				}
+			} else if replace := c.trie.Value(); replace != nil {
+				// aha, we made a match, mark it, and we'll come back and flush
+				// the censored string later
+				c.srcBuf = append(c.srcBuf, ch)
+				c.match = c.trie
+				c.matchLen = len(c.srcBuf)
+			} else {
+				// we're in the middle of a match
+				c.srcBuf = append(c.srcBuf, ch)
+			}
+		}
+
+		// at this point, no more matches are possible, so flush
+		if atEOF {
+			if c.match != nil {
+				c.dstBuf = append(c.dstBuf, c.match.Value()...)
+				c.dstBuf = append(c.dstBuf, c.srcBuf[c.matchLen:]...)
+				c.match = nil
+				c.matchLen = 0
+			} else {
+				c.dstBuf = append(c.dstBuf, c.srcBuf...)
+			}
+			c.srcBuf = c.srcBuf[:0]
+		}
+	}
+}
+
+func (c *censor) Reset() {
+	c.trie = c.trieRoot.Iter()
+	c.srcBuf = c.srcBuf[:0]
+	c.dstBuf = c.dstBuf[:0]
+}
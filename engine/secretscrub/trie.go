@@ -0,0 +1,169 @@
+package secretscrub
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Trie is a simple implementation of a compressed trie (or radix tree). In
+// essence, it's a key-value store that allows easily selecting all entries
+// that have a given prefix.
+//
+// Why not an off-the-shelf implementation? Well, most of those don't allow
+// navigating character-by-character through the tree, like we do with Step.
+type Trie struct {
+	// value is the value stored in this trie node
+	value []byte
+
+	// children is a byte-indexed slice of child nodes
+	children []*Trie
+	// direct is a prefix that every child in this node has - this is the
+	// compressed part of the compressed trie, and it saves us a huge amount of
+	// memory and performance
+	direct []byte
+}
+
+func (t *Trie) Iter() *TrieIter {
+	return &TrieIter{Trie: t}
+}
+
+func (t *Trie) Insert(key []byte, value []byte) {
+	t.Iter().insert(key, value)
+}
+
+func (t *Trie) Step(ch byte) *TrieIter {
+	return t.Iter().Step(ch)
+}
+
+// String prints a debuggable representation of the trie.
+func (t Trie) String() string {
+	lines := ""
+	lines += fmt.Sprintf("%s (%s)\n", t.direct, t.value)
+
+	for ch, child := range t.children {
+		if child != nil {
+			lines += fmt.Sprintf("- %c ->\n", ch)
+			for _, line := range strings.Split(child.String(), "\n") {
+				lines += "  " + line + "\n"
+			}
+		}
+	}
+	return strings.TrimSpace(lines)
+}
+
+// TrieIter is an iterator that allows navigating through a Trie.
+//
+// This is used so that we can navigate through the compressed Trie structure
+// easily - not every node "exists", but the TrieIter handles this case. For
+// example, a node might have a direct of `foo`, so the node `fo` is virtual.
+type TrieIter struct {
+	*Trie
+
+	// idx is the current index of this node into direct
+	idx int
+}
+
+func (t *TrieIter) insert(key []byte, value []byte) {
+	if t == nil {
+		panic("cannot insert into nil tree")
+	}
+
+	if len(key) == 0 || t.direct == nil {
+		// we're done, this is where we shall store the data!
+		t = t.materialize().Iter()
+		if t.direct == nil {
+			t.direct = key
+		}
+		t.value = value
+		return
+	}
+
+	next := t.Step(key[0])
+	if next == nil {
+		t = t.materialize().Iter()
+		t.branch()
+		child := t.children[key[0]]
+		if child == nil {
+			child = &Trie{}
+			t.children[key[0]] = child
+		}
+		next = child.Iter()
+	}
+
+	next.insert(key[1:], value)
+}
+
+// materialize is the main magic of how insertion works.
+//
+// This function can take any iterable part of the trie, and if the node is
+// virtual, then it will modify the trie to make it "real". This means that
+// this node can then store data, or can be given it's own children.
+func (t *TrieIter) materialize() *Trie {
+	if t.idx == len(t.direct) {
+		// already materialized
+		return t.Trie
+	}
+
+	direct := t.direct
+	child := &Trie{
+		direct:   direct[t.idx+1:],
+		children: t.children,
+		value:    t.value,
+	}
+	t.direct = direct[:t.idx]
+	t.children = nil
+	t.value = nil
+
+	t.branch()
+	t.children[direct[t.idx]] = child
+
+	return t.Trie
+}
+
+// branch takes a node in the trie and converts it from a leaf node into a
+// branch node (if it wasn't already)
+func (t *Trie) branch() {
+	// why a slice instead of a map? surely it uses more space?
+	// well, doing a lookup on a slice like this is *super* quick, but
+	// doing so on a map is *much* slower - since this is in the
+	// hotpath, it makes sense to waste the memory here (and since the
+	// trie is compressed, it doesn't seem to be that much in practice)
+	if t.children != nil {
+		return
+	}
+	t.children = make([]*Trie, 256)
+}
+
+// Step selects a node that was previously inserted.
+func (t *TrieIter) Step(ch byte) *TrieIter {
+	if t == nil {
+		return nil
+	}
+	if t.idx < len(t.direct) {
+		if t.direct[t.idx] == ch {
+			return &TrieIter{
+				Trie: t.Trie,
+				idx:  t.idx + 1,
+			}
+		}
+		return nil
+	}
+	if t.children != nil {
+		child := t.children[ch]
+		if child != nil {
+			return &TrieIter{Trie: child}
+		}
+	}
+	return nil
+}
+
+// Value gets the value previously inserted at this node.
+func (t *TrieIter) Value() []byte {
+	if t == nil {
+		return nil
+	}
+	if t.idx == len(t.direct) {
+		return t.value
+	}
+	return nil
+}
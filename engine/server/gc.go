@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/dagger/dagger/engine/config"
+	"github.com/dagger/dagger/engine/resultdb"
 	bkclient "github.com/moby/buildkit/client"
 	bkconfig "github.com/moby/buildkit/cmd/buildkitd/config"
 	"github.com/moby/buildkit/util/bklog"
@@ -14,6 +15,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/dagger/dagger/core"
+	"github.com/dagger/dagger/dagql"
 )
 
 func (srv *Server) EngineLocalCachePolicy() bkclient.PruneInfo {
@@ -73,6 +75,12 @@ func (srv *Server) PruneEngineLocalCacheEntries(ctx context.Context) (*core.Engi
 	close(ch)
 	wg.Wait()
 
+	// persisted results may refer to pruned content, e.g. the digest of a
+	// directory's entries, so they're pruned too
+	if _, _, err := srv.resultDB.Prune(ctx, resultdb.PruneOpts{All: true}); err != nil {
+		return nil, fmt.Errorf("failed to prune results: %w", err)
+	}
+
 	if len(pruned) == 0 {
 		return &core.EngineCacheEntrySet{}, nil
 	}
@@ -132,6 +140,15 @@ func (srv *Server) gc() {
 	if err != nil {
 		bklog.G(ctx).Errorf("gc error: %+v", err)
 	}
+	if len(srv.baseWorker.GCPolicy()) > 0 {
+		_, resultsSize, err := srv.resultDB.Prune(context.TODO(), srv.resultGCPolicy)
+		if err != nil {
+			bklog.G(ctx).Errorf("gc error pruning results: %+v", err)
+		}
+		if resultsSize > 0 {
+			bklog.G(ctx).Debugf("gc cleaned up %d bytes of results", resultsSize)
+		}
+	}
 	if size > 0 {
 		bklog.G(ctx).Debugf("gc cleaned up %d bytes", size)
 		go srv.throttledReleaseUnreferenced()
@@ -176,6 +193,30 @@ func getDefaultGCPolicy(cfg config.Config, bkcfg bkconfig.GCConfig, root string)
 	return policies[len(policies)-1]
 }
 
+// getResultGCPolicy returns the policy for pruning the persisted dagql
+// results, which is limited to a fraction of the default policy's space.
+func getResultGCPolicy(cfg config.Config, defaultPolicy bkclient.PruneInfo, root string) resultdb.PruneOpts {
+	dstat, _ := disk.GetDiskStat(root)
+
+	maxUsedSpace := cfg.GC.ResultsSpace.AsBytes(dstat)
+	if maxUsedSpace == 0 {
+		maxUsedSpace = defaultPolicy.MaxUsedSpace * resultsSpacePercentage / 100
+	}
+	return resultdb.PruneOpts{
+		MaxUsedSpace: maxUsedSpace,
+		KeepDuration: defaultPolicy.KeepDuration,
+	}
+}
+
+// getDagqlCacheLimits returns the limits of each session's dagql cache.
+func getDagqlCacheLimits(cfg config.Config) dagql.CacheLimits {
+	return dagql.CacheLimits{
+		// percentages of the disk don't apply to memory
+		MaxBytes:     cfg.GC.SessionCache.MaxUsedMemory.Bytes,
+		KeepDuration: cfg.GC.SessionCache.KeepDuration.Duration,
+	}
+}
+
 func defaultGCPolicy(cfg config.Config, bkcfg bkconfig.GCConfig, dstat disk.DiskStat) []config.GCPolicy {
 	space := cfg.GC.GCSpace
 	if space.IsUnset() {
@@ -247,4 +288,6 @@ const (
 	diskSpaceReserveBytes      int64 = 10 * 1e9 // 10GB
 	diskSpaceFreePercentage    int64 = 20
 	diskSpaceMaxPercentage     int64 = 75
+
+	resultsSpacePercentage int64 = 1
 )
@@ -68,11 +68,13 @@ import (
 	"golang.org/x/sync/semaphore"
 	"google.golang.org/grpc"
 
+	"github.com/dagger/dagger/dagql"
 	"github.com/dagger/dagger/engine"
 	"github.com/dagger/dagger/engine/buildkit"
 	daggercache "github.com/dagger/dagger/engine/cache"
 	"github.com/dagger/dagger/engine/clientdb"
 	"github.com/dagger/dagger/engine/distconsts"
+	"github.com/dagger/dagger/engine/resultdb"
 	"github.com/dagger/dagger/engine/slog"
 	"github.com/dagger/dagger/engine/sources/blob"
 	"github.com/dagger/dagger/engine/sources/gitdns"
@@ -94,6 +96,7 @@ type Server struct {
 
 	rootDir           string
 	solverCacheDBPath string
+	resultDBPath      string
 
 	workerRootDir         string
 	snapshotterRootDir    string
@@ -171,6 +174,15 @@ type Server struct {
 	daggerSessions   map[string]*daggerSession // session id -> session state
 	daggerSessionsMu sync.RWMutex
 	clientDBs        *clientdb.DBs
+
+	//
+	// dagql results persisted across sessions
+	//
+	resultDB       *resultdb.Store
+	resultGCPolicy resultdb.PruneOpts
+
+	// limits of each session's dagql cache
+	dagqlCacheLimits dagql.CacheLimits
 }
 
 type NewServerOpts struct {
@@ -220,6 +232,7 @@ func NewServer(ctx context.Context, opts *NewServerOpts) (*Server, error) {
 		return nil, err
 	}
 	srv.solverCacheDBPath = filepath.Join(srv.rootDir, "cache.db")
+	srv.resultDBPath = filepath.Join(srv.rootDir, "results.db")
 
 	srv.workerRootDir = filepath.Join(srv.rootDir, "worker")
 	if err := os.MkdirAll(srv.workerRootDir, 0700); err != nil {
@@ -244,6 +257,12 @@ func NewServer(ctx context.Context, opts *NewServerOpts) (*Server, error) {
 	srv.clientDBs = clientdb.NewDBs(srv.clientDBDir)
 	srv.telemetryPubSub = NewPubSub(srv)
 
+	// set up the store of dagql results persisted across sessions
+	srv.resultDB, err = resultdb.Open(srv.resultDBPath, engine.Version)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open result DB: %w", err)
+	}
+
 	//
 	// setup config derived from engine config
 	//
@@ -413,6 +432,8 @@ func NewServer(ctx context.Context, opts *NewServerOpts) (*Server, error) {
 	srv.workerCache = srv.baseWorker.CacheMgr
 	srv.workerSourceManager = srv.baseWorker.SourceManager
 	srv.workerDefaultGCPolicy = getDefaultGCPolicy(*cfg, ociCfg.GCConfig, srv.rootDir)
+	srv.resultGCPolicy = getResultGCPolicy(*cfg, srv.workerDefaultGCPolicy, srv.rootDir)
+	srv.dagqlCacheLimits = getDagqlCacheLimits(*cfg)
 
 	logrus.Infof("found worker %q, labels=%v, platforms=%v", workerID, baseLabels, FormatPlatforms(srv.enabledPlatforms))
 	archutil.WarnIfUnsupported(srv.enabledPlatforms)
@@ -508,6 +529,22 @@ func NewServer(ctx context.Context, opts *NewServerOpts) (*Server, error) {
 	if cacheServiceURL == "" {
 		cacheServiceURL = daggerCacheServiceURL
 	}
+	var cacheStorage *daggercache.StorageConfig
+	if remoteCache := cfg.RemoteCache; remoteCache.Type != "" {
+		cacheStorage = &daggercache.StorageConfig{
+			Type:          remoteCache.Type,
+			Ref:           remoteCache.Ref,
+			Insecure:      remoteCache.Insecure,
+			Endpoint:      remoteCache.Endpoint,
+			Bucket:        remoteCache.Bucket,
+			Prefix:        remoteCache.Prefix,
+			Region:        remoteCache.Region,
+			ImportPeriod:  remoteCache.ImportPeriod.Duration,
+			ExportPeriod:  remoteCache.ExportPeriod.Duration,
+			ExportTimeout: remoteCache.ExportTimeout.Duration,
+			MaxAge:        remoteCache.MaxAge.Duration,
+		}
+	}
 	srv.SolverCache, err = daggercache.NewManager(ctx, daggercache.ManagerConfig{
 		KeyStore:     srv.solverCacheDB,
 		ResultStore:  bkworker.NewCacheResultStorage(baseWorkerController),
@@ -516,6 +553,7 @@ func NewServer(ctx context.Context, opts *NewServerOpts) (*Server, error) {
 		ServiceURL:   cacheServiceURL,
 		Token:        cacheServiceToken,
 		EngineID:     opts.Name,
+		Storage:      cacheStorage,
 	})
 	if err != nil {
 		return nil, err
@@ -574,7 +612,7 @@ func (srv *Server) Close() error {
 		err = errors.Join(err, srv.removeDaggerSession(context.Background(), s))
 		s.stateMu.Unlock()
 	}
-	return err
+	return errors.Join(err, srv.resultDB.Close())
 }
 
 func (srv *Server) Info(context.Context, *controlapi.InfoRequest) (*controlapi.InfoResponse, error) {
@@ -233,7 +233,7 @@ func (srv *Server) initializeDaggerSession(
 	sess.authProvider = auth.NewRegistryAuthProvider()
 	sess.refs = map[buildkit.Reference]struct{}{}
 	sess.containers = map[bkgw.Container]struct{}{}
-	sess.dagqlCache = dagql.NewCache()
+	sess.dagqlCache = dagql.NewPersistentCache(srv.resultDB, srv.dagqlCacheLimits)
 	sess.telemetryPubSub = srv.telemetryPubSub
 	sess.interactive = clientMetadata.Interactive
 	sess.interactiveCommand = clientMetadata.InteractiveCommand
@@ -659,6 +659,18 @@ func (srv *Server) initializeDaggerClient(
 	client.loggerProvider = sdklog.NewLoggerProvider(loggerOpts...)
 	client.meterProvider = sdkmetric.NewMeterProvider(meterOpts...)
 
+	// report the usage of the session's cache to the main client
+	if client.clientID == client.daggerSession.mainClientCallerID {
+		if cache, ok := client.daggerSession.dagqlCache.(interface {
+			Stats() dagql.CacheStats
+		}); ok {
+			meter := client.meterProvider.Meter(InstrumentationLibrary)
+			if _, err := enginetel.ObserveDagqlCache(meter, cache.Stats); err != nil {
+				return fmt.Errorf("failed to observe dagql cache: %w", err)
+			}
+		}
+	}
+
 	client.state = clientStateInitialized
 	return nil
 }
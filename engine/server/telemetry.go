@@ -23,6 +23,8 @@ import (
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
 
+	"github.com/dagger/dagger/core"
+	"github.com/dagger/dagger/dagql/call/callpbv1"
 	"github.com/dagger/dagger/engine/clientdb"
 	"github.com/dagger/dagger/engine/slog"
 	enginetel "github.com/dagger/dagger/engine/telemetry"
@@ -658,3 +660,64 @@ func (ps *PubSub) sseHandler(w http.ResponseWriter, r *http.Request, client *dag
 		flush()
 	}
 }
+
+// RecordedCalls returns the calls recorded in the telemetry of the engine's
+// recent clients, including clients that have disconnected, until their
+// databases are garbage collected.
+func (srv *Server) RecordedCalls(ctx context.Context) ([]*core.RecordedCall, error) {
+	clientIDs, err := srv.clientDBs.Clients()
+	if err != nil {
+		return nil, err
+	}
+	var calls []*core.RecordedCall
+	for _, clientID := range clientIDs {
+		clientCalls, err := srv.clientRecordedCalls(ctx, clientID)
+		if err != nil {
+			slog.Warn("failed to read recorded calls", "client", clientID, "err", err)
+			continue
+		}
+		calls = append(calls, clientCalls...)
+	}
+	return calls, nil
+}
+
+func (srv *Server) clientRecordedCalls(ctx context.Context, clientID string) ([]*core.RecordedCall, error) {
+	db, err := srv.clientDBs.Open(clientID)
+	if err != nil {
+		return nil, fmt.Errorf("open client db: %w", err)
+	}
+	defer db.Close()
+
+	spans, err := clientdb.New(db).SelectCallSpans(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("select call spans: %w", err)
+	}
+	var calls []*core.RecordedCall
+	seen := map[string]bool{}
+	for _, span := range spans {
+		// spans are recorded again as they progress, so skip the calls seen
+		// in their later updates
+		if seen[span.SpanID] {
+			continue
+		}
+		seen[span.SpanID] = true
+		for _, attr := range span.ReadOnly().Attributes() {
+			if attr.Key != telemetry.DagCallAttr {
+				continue
+			}
+			var pb callpbv1.Call
+			if err := pb.Decode(attr.Value.AsString()); err != nil {
+				slog.Warn("failed to decode recorded call", "span", span.SpanID, "err", err)
+				break
+			}
+			calls = append(calls, &core.RecordedCall{
+				ClientID: clientID,
+				TraceID:  span.TraceID,
+				Time:     time.Unix(0, span.StartTime),
+				Call:     &pb,
+			})
+			break
+		}
+	}
+	return calls, nil
+}
@@ -6,6 +6,8 @@ import (
 	"io"
 	"os"
 	"os/exec"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/dagger/dagger/engine"
@@ -25,6 +27,7 @@ type gitCLI struct {
 	sshAuthSock string   // SSH_AUTH_SOCK env value
 	knownHosts  string   // file path passed to SSH
 	auth        []string // extra auth flags passed to git
+	env         []string // extra environment variables
 
 	hostsPath  string // generated /etc/hosts from network config
 	resolvPath string // generated /etc/resolv.conf from network config
@@ -70,7 +73,20 @@ func (cli *gitCLI) withinDir(gitDir, workDir string) *gitCLI {
 	return &cp
 }
 
-func (cli *gitCLI) run(ctx context.Context, args ...string) (_ *bytes.Buffer, err error) {
+func (cli *gitCLI) withEnv(env ...string) *gitCLI {
+	cp := *cli
+	cp.env = append(slices.Clip(cli.env), env...)
+	return &cp
+}
+
+func (cli *gitCLI) run(ctx context.Context, args ...string) (*bytes.Buffer, error) {
+	stdout, _, err := cli.runOutputs(ctx, args...)
+	return stdout, err
+}
+
+// runOutputs is like run, but also returns the stderr of the command, which
+// some commands write their results to.
+func (cli *gitCLI) runOutputs(ctx context.Context, args ...string) (_ *bytes.Buffer, _ *bytes.Buffer, err error) {
 	for {
 		stdout, stderr, flush := logs.NewLogStreams(ctx, true)
 		defer stdout.Close()
@@ -123,6 +139,7 @@ func (cli *gitCLI) run(ctx context.Context, args ...string) (_ *bytes.Buffer, er
 		if cli.sshAuthSock != "" {
 			cmd.Env = append(cmd.Env, "SSH_AUTH_SOCK="+cli.sshAuthSock)
 		}
+		cmd.Env = append(cmd.Env, cli.env...)
 		// remote git commands spawn helper processes that inherit FDs and don't
 		// handle parent death signal so exec.CommandContext can't be used
 		err := runWithStandardUmaskAndNetOverride(ctx, cmd, cli.hostsPath, cli.resolvPath)
@@ -141,9 +158,9 @@ func (cli *gitCLI) run(ctx context.Context, args ...string) (_ *bytes.Buffer, er
 					continue
 				}
 			}
-			return buf, errors.Errorf("git error: %s\nstderr:\n%s", err, errbuf.String())
+			return buf, errbuf, errors.Errorf("git error: %s\nstderr:\n%s", err, errbuf.String())
 		}
-		return buf, nil
+		return buf, errbuf, nil
 	}
 }
 
@@ -160,7 +177,7 @@ func GetGitSSHCommand(knownHosts string) string {
 func argsNoDepth(args []string) []string {
 	out := make([]string, 0, len(args))
 	for _, a := range args {
-		if a != "--depth=1" {
+		if !strings.HasPrefix(a, "--depth=") {
 			out = append(out, a)
 		}
 	}
@@ -182,3 +199,92 @@ func argsNoCommitRefspec(args []string) []string {
 
 	return args
 }
+
+// commitLogFormat separates the fields of a commit with unit separators and
+// commits with record separators, so that messages can contain newlines.
+const commitLogFormat = "--format=%H%x1f%P%x1f%an%x1f%ae%x1f%aI%x1f%cn%x1f%ce%x1f%cI%x1f%B%x1e"
+
+// log returns the commits reachable from the given revisions, newest first,
+// optionally limited to those touching the given paths.
+func (cli *gitCLI) log(ctx context.Context, revs []string, paths []string, maxCount int) ([]Commit, error) {
+	args := []string{"log", commitLogFormat}
+	if maxCount > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(maxCount))
+	}
+	args = append(args, revs...)
+	args = append(args, "--")
+	args = append(args, paths...)
+	buf, err := cli.run(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitLog(buf.String())
+}
+
+func parseCommitLog(out string) ([]Commit, error) {
+	commits := []Commit{}
+	for _, record := range strings.Split(out, "\x1e") {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+		fields := strings.SplitN(record, "\x1f", 9)
+		if len(fields) != 9 {
+			return nil, errors.Errorf("unexpected git log record %q", record)
+		}
+		commit := Commit{
+			SHA:       fields[0],
+			Parents:   strings.Fields(fields[1]),
+			Author:    Signature{Name: fields[2], Email: fields[3], Date: fields[4]},
+			Committer: Signature{Name: fields[5], Email: fields[6], Date: fields[7]},
+			Message:   fields[8],
+		}
+		if commit.Parents == nil {
+			commit.Parents = []string{}
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// describe returns the nearest tag reachable from the revision, suffixed with
+// the number of commits since the tag and the abbreviated commit if they
+// differ, as git describe --tags does. The excluded tags are never used.
+func (cli *gitCLI) describe(ctx context.Context, rev string, exclude []string) (string, error) {
+	args := []string{"describe", "--tags"}
+	for _, tag := range exclude {
+		args = append(args, "--exclude="+tag)
+	}
+	buf, err := cli.run(ctx, append(args, rev)...)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// localOnlyTags returns the tags of the repository that the origin doesn't
+// have, such as the tags that fetched branches are stored as.
+func (cli *gitCLI) localOnlyTags(ctx context.Context) ([]string, error) {
+	remoteBuf, err := cli.run(ctx, "ls-remote", "--tags", "--refs", "origin")
+	if err != nil {
+		return nil, err
+	}
+	remoteTags := map[string]struct{}{}
+	for _, line := range strings.Split(remoteBuf.String(), "\n") {
+		if _, ref, ok := strings.Cut(line, "\t"); ok {
+			remoteTags[strings.TrimPrefix(ref, "refs/tags/")] = struct{}{}
+		}
+	}
+
+	localBuf, err := cli.run(ctx, "for-each-ref", "--format=%(refname:strip=2)", "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+	var tags []string
+	for _, tag := range strings.Fields(localBuf.String()) {
+		if _, ok := remoteTags[tag]; !ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
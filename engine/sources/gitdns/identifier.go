@@ -6,8 +6,29 @@ import (
 
 const AttrDNSNamespace = "dagger.dns.namespace"
 
+const (
+	// AttrSparseCheckout holds newline-separated sparse-checkout patterns.
+	AttrSparseCheckout = "dagger.git.sparsecheckout"
+	// AttrDepth holds the number of commits of history to fetch into a kept
+	// git directory, where 0 fetches the full history. Defaults to 1.
+	AttrDepth = "dagger.git.depth"
+	// AttrSkipSubmodules disables checking out submodules.
+	AttrSkipSubmodules = "dagger.git.skipsubmodules"
+	// AttrLFS enables replacing Git LFS pointer files with their content.
+	AttrLFS = "dagger.git.lfs"
+)
+
 type GitIdentifier struct {
 	bkgit.GitIdentifier
 
 	Namespace string
+
+	// Metadata is the JSON-encoded MetadataRequest, if the source should
+	// produce metadata of the ref instead of its tree.
+	Metadata string
+
+	SparseCheckout []string
+	Depth          int
+	SkipSubmodules bool
+	LFS            bool
 }
@@ -0,0 +1,35 @@
+package gitdns
+
+import (
+	"context"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// lfsStorage is where the Git LFS objects of a remote are stored, within its
+// shared repo. Objects are stored by their content hash, so they're only
+// downloaded once, whichever commits they're checked out from.
+func lfsStorage(gitDir string) string {
+	return filepath.Join(gitDir, "lfs")
+}
+
+// fetchLFS downloads the Git LFS objects of the ref (within subdir, if set)
+// that aren't in the storage of the shared repo yet, and returns the flags
+// that make git replace pointer files with their content on checkout.
+func fetchLFS(ctx context.Context, git *gitCLI, gitDir, ref, subdir string) ([]string, error) {
+	storage := lfsStorage(gitDir)
+	args := []string{"-c", "lfs.storage=" + storage, "lfs", "fetch"}
+	if subdir = strings.TrimPrefix(path.Clean("/"+subdir), "/"); subdir != "" {
+		args = append(args, "--include="+path.Join(subdir, "**"))
+	}
+	if _, err := git.run(ctx, append(args, "origin", ref)...); err != nil {
+		return nil, err
+	}
+	return []string{
+		"-c", "lfs.storage=" + storage,
+		"-c", "filter.lfs.process=git-lfs filter-process",
+		"-c", "filter.lfs.smudge=git-lfs smudge -- %f",
+		"-c", "filter.lfs.required=true",
+	}, nil
+}
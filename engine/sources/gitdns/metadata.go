@@ -0,0 +1,145 @@
+package gitdns
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/moby/buildkit/client/llb"
+	"github.com/pkg/errors"
+)
+
+// AttrGitMetadata makes the git source produce a snapshot containing
+// MetadataFilename, holding the Metadata requested by the JSON-encoded
+// MetadataRequest, instead of a checkout of the ref.
+const AttrGitMetadata = "dagger.git.metadata"
+
+// MetadataFilename is the file in a metadata snapshot holding the Metadata.
+const MetadataFilename = "metadata.json"
+
+type MetadataKind string
+
+const (
+	// MetadataCommit requests the commit the ref resolves to.
+	MetadataCommit MetadataKind = "commit"
+	// MetadataLog requests the commits reachable from the ref.
+	MetadataLog MetadataKind = "log"
+	// MetadataDescribe requests the nearest tag reachable from the ref.
+	MetadataDescribe MetadataKind = "describe"
+	// MetadataVerify requests the verification of the signature of the ref.
+	MetadataVerify MetadataKind = "verify"
+)
+
+type MetadataRequest struct {
+	Kind MetadataKind `json:"kind"`
+
+	// From excludes the commits reachable from this commit SHA from a log.
+	From string `json:"from,omitempty"`
+	// Paths limits a log to the commits touching these paths.
+	Paths []string `json:"paths,omitempty"`
+
+	// Ref is the name of the ref to verify, so that the signature of an
+	// annotated tag is verified instead of the one of its commit.
+	Ref string `json:"ref,omitempty"`
+	// Keys are the GPG public keys that are trusted to sign the ref.
+	Keys [][]byte `json:"keys,omitempty"`
+	// AllowedSigners is the SSH allowed signers file, as read by ssh-keygen,
+	// trusted to sign the ref.
+	AllowedSigners string `json:"allowedSigners,omitempty"`
+}
+
+type Metadata struct {
+	Commits  []Commit `json:"commits,omitempty"`
+	Describe string   `json:"describe,omitempty"`
+
+	Verification *Verification `json:"verification,omitempty"`
+}
+
+type Commit struct {
+	SHA       string    `json:"sha"`
+	Parents   []string  `json:"parents"`
+	Author    Signature `json:"author"`
+	Committer Signature `json:"committer"`
+	Message   string    `json:"message"`
+}
+
+type Signature struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// Date is in strict ISO 8601 format.
+	Date string `json:"date"`
+}
+
+// GitMetadata is like Git, but its state contains the requested metadata of
+// the ref instead of its tree. The ref should be a commit SHA, so that the
+// result is cached by commit.
+func GitMetadata(url, ref string, namespace string, req MetadataRequest, opts ...llb.GitOption) (llb.State, error) {
+	dt, err := json.Marshal(req)
+	if err != nil {
+		return llb.State{}, err
+	}
+	return gitState(url, ref, namespace, map[string]string{
+		AttrGitMetadata: string(dt),
+	}, opts...), nil
+}
+
+// writeMetadata writes the metadata requested by the JSON-encoded request
+// for the ref to MetadataFilename in dir.
+func writeMetadata(ctx context.Context, git *gitCLI, ref string, request string, dir string) error {
+	var req MetadataRequest
+	if err := json.Unmarshal([]byte(request), &req); err != nil {
+		return errors.Wrap(err, "invalid git metadata request")
+	}
+
+	var md Metadata
+	switch req.Kind {
+	case MetadataCommit:
+		commits, err := git.log(ctx, []string{ref}, nil, 1)
+		if err != nil {
+			return err
+		}
+		md.Commits = commits
+	case MetadataLog:
+		revs := []string{ref}
+		if req.From != "" {
+			if _, err := git.run(ctx, "cat-file", "-e", req.From+"^{commit}"); err != nil {
+				if _, err := git.run(ctx, "fetch", "--tags", "origin", req.From); err != nil {
+					return errors.Wrapf(err, "failed to fetch commit %s", req.From)
+				}
+			}
+			revs = append(revs, "^"+req.From)
+		}
+		commits, err := git.log(ctx, revs, req.Paths, 0)
+		if err != nil {
+			return err
+		}
+		md.Commits = commits
+	case MetadataDescribe:
+		// fetched branches are stored as tags, so make sure they're not
+		// mistaken for the nearest tag
+		exclude, err := git.localOnlyTags(ctx)
+		if err != nil {
+			return err
+		}
+		describe, err := git.describe(ctx, ref, exclude)
+		if err != nil {
+			return err
+		}
+		md.Describe = describe
+	case MetadataVerify:
+		verification, err := verifySignature(ctx, git, ref, req)
+		if err != nil {
+			return err
+		}
+		md.Verification = verification
+	default:
+		return errors.Errorf("unknown git metadata kind %q", req.Kind)
+	}
+
+	dt, err := json.Marshal(md)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, MetadataFilename), dt, 0o644)
+}
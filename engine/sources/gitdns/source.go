@@ -28,6 +28,7 @@ import (
 	"github.com/moby/buildkit/util/bklog"
 	"github.com/moby/buildkit/util/urlutil"
 	"github.com/moby/locker"
+	"github.com/opencontainers/go-digest"
 	"github.com/pkg/errors"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -81,6 +82,34 @@ func (gs *gitSource) Identifier(scheme, ref string, attrs map[string]string, pla
 	if v, ok := attrs[AttrDNSNamespace]; ok {
 		id.Namespace = v
 	}
+	if v, ok := attrs[AttrGitMetadata]; ok {
+		id.Metadata = v
+	}
+	if v, ok := attrs[AttrSparseCheckout]; ok && v != "" {
+		id.SparseCheckout = strings.Split(v, "\n")
+	}
+	id.Depth = 1
+	if v, ok := attrs[AttrDepth]; ok {
+		depth, err := strconv.Atoi(v)
+		if err != nil || depth < 0 {
+			return nil, errors.Errorf("invalid git depth %q", v)
+		}
+		id.Depth = depth
+	}
+	if v, ok := attrs[AttrSkipSubmodules]; ok {
+		skip, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", AttrSkipSubmodules)
+		}
+		id.SkipSubmodules = skip
+	}
+	if v, ok := attrs[AttrLFS]; ok {
+		lfs, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", AttrLFS)
+		}
+		id.LFS = lfs
+	}
 
 	return id, nil
 }
@@ -202,6 +231,21 @@ func (gs *gitSourceHandler) shaToCacheKey(sha string) string {
 	if gs.src.Subdir != "" {
 		key += ":" + gs.src.Subdir
 	}
+	if gs.src.Metadata != "" {
+		key += ".metadata:" + digest.FromString(gs.src.Metadata).Encoded()
+	}
+	if len(gs.src.SparseCheckout) > 0 {
+		key += ".sparse:" + digest.FromString(strings.Join(gs.src.SparseCheckout, "\n")).Encoded()
+	}
+	if gs.src.Depth != 1 {
+		key += ".depth:" + strconv.Itoa(gs.src.Depth)
+	}
+	if gs.src.SkipSubmodules {
+		key += ".nosubmodules"
+	}
+	if gs.src.LFS {
+		key += ".lfs"
+	}
 	return key
 }
 
@@ -522,8 +566,13 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 		}
 	}
 
+	_, err = os.Lstat(filepath.Join(gitDir, "shallow"))
+	isShallow := err == nil
+
 	doFetch := true
-	if isCommitSHA(ref) {
+	// metadata and deeper checkouts may need history and tags that an earlier
+	// shallow fetch of the commit didn't get, so always fetch those
+	if isCommitSHA(ref) && gs.src.Metadata == "" && (gs.src.Depth == 1 || !isShallow) {
 		// skip fetch if commit already exists
 		if _, err := git.run(ctx, "cat-file", "-e", ref+"^{commit}"); err == nil {
 			doFetch = false
@@ -535,11 +584,11 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 		os.RemoveAll(filepath.Join(gitDir, "shallow.lock"))
 
 		args := []string{"fetch"}
-		if !isCommitSHA(ref) { // TODO: find a branch from ls-remote?
-			args = append(args, "--depth=1", "--no-tags")
+		if !isCommitSHA(ref) && gs.src.Metadata == "" && gs.src.Depth > 0 { // TODO: find a branch from ls-remote?
+			args = append(args, "--depth="+strconv.Itoa(gs.src.Depth), "--no-tags")
 		} else {
 			args = append(args, "--tags")
-			if _, err := os.Lstat(filepath.Join(gitDir, "shallow")); err == nil {
+			if isShallow {
 				args = append(args, "--unshallow")
 			}
 		}
@@ -561,6 +610,14 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 		}
 	}
 
+	var lfsArgs []string
+	if gs.src.LFS && gs.src.Metadata == "" {
+		lfsArgs, err = fetchLFS(ctx, git, gitDir, ref, gs.src.Subdir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch LFS objects for %s", urlutil.RedactCredentials(gs.src.Remote))
+		}
+	}
+
 	checkoutRef, err := gs.cache.New(ctx, nil, g, cache.WithRecordType(client.UsageRecordTypeGitCheckout), cache.WithDescription(fmt.Sprintf("git snapshot for %s#%s", gs.src.Remote, ref)))
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to create new mutable for %s", urlutil.RedactCredentials(gs.src.Remote))
@@ -592,7 +649,20 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 		subdir = "."
 	}
 
-	if gs.src.KeepGitDir && subdir == "." {
+	if len(gs.src.SparseCheckout) > 0 && subdir != "." {
+		return nil, errors.Errorf("sparse checkout of a subdirectory is not supported")
+	}
+
+	// sparse checkouts are done in a git directory, which is removed after if
+	// it isn't kept
+	var discardCheckoutGitDir bool
+	switch {
+	case gs.src.Metadata != "":
+		if err := writeMetadata(ctx, git, ref, gs.src.Metadata, checkoutDir); err != nil {
+			return nil, errors.Wrapf(err, "failed to read metadata of remote %s", urlutil.RedactCredentials(gs.src.Remote))
+		}
+	case (gs.src.KeepGitDir || len(gs.src.SparseCheckout) > 0) && subdir == ".":
+		discardCheckoutGitDir = !gs.src.KeepGitDir
 		checkoutDirGit := filepath.Join(checkoutDir, ".git")
 		if err := os.MkdirAll(checkoutDir, 0711); err != nil {
 			return nil, err
@@ -602,6 +672,11 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 		if err != nil {
 			return nil, err
 		}
+		if len(gs.src.SparseCheckout) > 0 {
+			if err := setSparseCheckout(ctx, checkoutGit, checkoutDirGit, gs.src.SparseCheckout); err != nil {
+				return nil, errors.Wrapf(err, "failed to configure sparse checkout")
+			}
+		}
 		// Defense-in-depth: clone using the file protocol to disable local-clone
 		// optimizations which can be abused on some versions of Git to copy unintended
 		// host files into the build context.
@@ -629,11 +704,30 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 		default:
 			pullref += ":" + pullref
 		}
-		_, err = checkoutGit.run(ctx, "fetch", "-u", "--depth=1", "origin", pullref)
+		fetchArgs := []string{"fetch", "-u"}
+		if gs.src.Depth > 0 {
+			fetchArgs = append(fetchArgs, "--depth="+strconv.Itoa(gs.src.Depth))
+		} else {
+			fetchArgs = append(fetchArgs, "--tags")
+		}
+		_, err = checkoutGit.run(ctx, append(fetchArgs, "origin", pullref)...)
 		if err != nil {
 			return nil, err
 		}
-		_, err = checkoutGit.run(ctx, "checkout", "FETCH_HEAD")
+		if gs.src.Depth == 0 {
+			// fetched branches are stored as tags in the shared repo, which
+			// shouldn't show up as tags of the checkout
+			localTags, err := git.localOnlyTags(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if len(localTags) > 0 {
+				if _, err := checkoutGit.run(ctx, append([]string{"tag", "-d"}, localTags...)...); err != nil {
+					return nil, err
+				}
+			}
+		}
+		_, err = checkoutGit.run(ctx, append(lfsArgs, "checkout", "FETCH_HEAD")...)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to checkout remote %s", urlutil.RedactCredentials(gs.src.Remote))
 		}
@@ -649,7 +743,7 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 			return nil, errors.Wrapf(err, "failed to remove FETCH_HEAD for remote %s", urlutil.RedactCredentials(gs.src.Remote))
 		}
 		gitDir = checkoutDirGit
-	} else {
+	default:
 		cd := checkoutDir
 		if subdir != "." {
 			cd, err = os.MkdirTemp(cd, "checkout")
@@ -657,7 +751,7 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 				return nil, errors.Wrapf(err, "failed to create temporary checkout dir")
 			}
 		}
-		_, err = git.withinDir(gitDir, cd).run(ctx, "checkout", ref, "--", ".")
+		_, err = git.withinDir(gitDir, cd).run(ctx, append(lfsArgs, "checkout", ref, "--", ".")...)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to checkout remote %s", urlutil.RedactCredentials(gs.src.Remote))
 		}
@@ -690,14 +784,26 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 		}
 	}
 
-	_, err = git.withinDir(gitDir, checkoutDir).run(ctx, "submodule", "update", "--init", "--recursive", "--depth=1")
-	if err != nil {
-		return nil, errors.Wrapf(err, "failed to update submodules for %s", urlutil.RedactCredentials(gs.src.Remote))
+	if gs.src.Metadata == "" && !gs.src.SkipSubmodules {
+		_, err = git.withinDir(gitDir, checkoutDir).run(ctx, "submodule", "update", "--init", "--recursive", "--depth=1")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to update submodules for %s", urlutil.RedactCredentials(gs.src.Remote))
+		}
+	}
+
+	if discardCheckoutGitDir {
+		if err := os.RemoveAll(gitDir); err != nil {
+			return nil, errors.Wrapf(err, "failed to remove git dir")
+		}
 	}
 
 	if idmap := mount.IdentityMapping(); idmap != nil {
 		u := idmap.RootPair()
-		err := filepath.WalkDir(gitDir, func(p string, _ os.DirEntry, _ error) error {
+		chownDir := gitDir
+		if gs.src.Metadata != "" || discardCheckoutGitDir {
+			chownDir = checkoutDir
+		}
+		err := filepath.WalkDir(chownDir, func(p string, _ os.DirEntry, _ error) error {
 			return os.Lchown(p, u.UID, u.GID)
 		})
 		if err != nil {
@@ -727,6 +833,18 @@ func (gs *gitSourceHandler) Snapshot(ctx context.Context, g session.Group) (out
 	return snap, nil
 }
 
+// setSparseCheckout limits the checkout to the paths matching the patterns,
+// which use the syntax of .gitignore files.
+func setSparseCheckout(ctx context.Context, git *gitCLI, gitDir string, patterns []string) error {
+	if _, err := git.run(ctx, "config", "core.sparseCheckout", "true"); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Join(gitDir, "info"), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(gitDir, "info", "sparse-checkout"), []byte(strings.Join(patterns, "\n")+"\n"), 0644)
+}
+
 func isCommitSHA(str string) bool {
 	return validHex.MatchString(str)
 }
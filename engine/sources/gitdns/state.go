@@ -2,6 +2,8 @@ package gitdns
 
 import (
 	"path"
+	"strconv"
+	"strings"
 
 	"github.com/moby/buildkit/client/llb"
 	"github.com/moby/buildkit/solver/pb"
@@ -10,9 +12,50 @@ import (
 	"github.com/pkg/errors"
 )
 
+// CheckoutOpts customizes how the tree of a ref is checked out.
+type CheckoutOpts struct {
+	// SparseCheckout limits the checkout to the paths matching these
+	// patterns, which use the syntax of .gitignore files.
+	SparseCheckout []string
+	// Depth is the number of commits of history to fetch into a kept git
+	// directory. Zero uses the default of 1.
+	Depth int
+	// FullHistory fetches the full history into a kept git directory,
+	// overriding Depth.
+	FullHistory bool
+	// SkipSubmodules disables checking out submodules.
+	SkipSubmodules bool
+	// LFS replaces Git LFS pointer files with the content they point to.
+	LFS bool
+}
+
+func (opts CheckoutOpts) attrs() map[string]string {
+	attrs := map[string]string{}
+	if len(opts.SparseCheckout) > 0 {
+		attrs[AttrSparseCheckout] = strings.Join(opts.SparseCheckout, "\n")
+	}
+	switch {
+	case opts.FullHistory:
+		attrs[AttrDepth] = "0"
+	case opts.Depth > 1:
+		attrs[AttrDepth] = strconv.Itoa(opts.Depth)
+	}
+	if opts.SkipSubmodules {
+		attrs[AttrSkipSubmodules] = "true"
+	}
+	if opts.LFS {
+		attrs[AttrLFS] = "true"
+	}
+	return attrs
+}
+
 // Git is a helper mimicking the llb.Git function, but with the ability to
 // set additional attributes.
-func Git(url, ref string, namespace string, opts ...llb.GitOption) llb.State {
+func Git(url, ref string, namespace string, checkout CheckoutOpts, opts ...llb.GitOption) llb.State {
+	return gitState(url, ref, namespace, checkout.attrs(), opts...)
+}
+
+func gitState(url, ref string, namespace string, extraAttrs map[string]string, opts ...llb.GitOption) llb.State {
 	remote, err := gitutil.ParseURL(url)
 	if errors.Is(err, gitutil.ErrUnknownProtocol) {
 		url = "https://" + url
@@ -76,6 +119,9 @@ func Git(url, ref string, namespace string, opts ...llb.GitOption) llb.State {
 	}
 
 	attrs[AttrDNSNamespace] = namespace
+	for k, v := range extraAttrs {
+		attrs[k] = v
+	}
 
 	source := llb.NewSource("git://"+id, attrs, gi.Constraints)
 	return llb.NewState(source.Output())
@@ -0,0 +1,117 @@
+package gitdns
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Verification is a signature that was verified against the trusted keys.
+type Verification struct {
+	// Format is the format of the signature, "gpg" or "ssh".
+	Format string `json:"format"`
+	// Signer is the user ID of the GPG key, or the principal of the SSH key
+	// in the allowed signers.
+	Signer string `json:"signer"`
+	// Key is the fingerprint of the signing key.
+	Key string `json:"key"`
+}
+
+var sshGoodSignature = regexp.MustCompile(`Good "git" signature for (.+) with \S+ key (\S+)`)
+
+// verifySignature verifies the signature of the annotated tag named by
+// req.Ref if it points at the commit, or else of the commit itself, against
+// the GPG keys and SSH allowed signers of the request.
+func verifySignature(ctx context.Context, git *gitCLI, commit string, req MetadataRequest) (*Verification, error) {
+	if len(req.Keys) == 0 && req.AllowedSigners == "" {
+		return nil, errors.New("no keys or allowed signers to verify the signature with")
+	}
+
+	tmp, err := os.MkdirTemp("", "git-verify")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	gnupgHome := filepath.Join(tmp, "gnupg")
+	if err := os.Mkdir(gnupgHome, 0o700); err != nil {
+		return nil, err
+	}
+	for i, key := range req.Keys {
+		cmd := exec.CommandContext(ctx, "gpg", "--homedir", gnupgHome, "--batch", "--import")
+		cmd.Stdin = bytes.NewReader(key)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return nil, errors.Wrapf(err, "failed to import key #%d: %s", i+1, out)
+		}
+	}
+	allowedSigners := filepath.Join(tmp, "allowed_signers")
+	if err := os.WriteFile(allowedSigners, []byte(req.AllowedSigners), 0o600); err != nil {
+		return nil, err
+	}
+
+	kind, target := "commit", commit
+	if tag, ok := annotatedTag(ctx, git, req.Ref, commit); ok {
+		kind, target = "tag", tag
+	}
+	name := req.Ref
+	if name == "" {
+		name = commit
+	}
+
+	_, stderr, err := git.withEnv("GNUPGHOME="+gnupgHome).runOutputs(ctx,
+		"-c", "gpg.ssh.allowedSignersFile="+allowedSigners,
+		"verify-"+kind, "--raw", target)
+	if err != nil {
+		if out := strings.TrimSpace(stderr.String()); out == "" || strings.Contains(out, "no signature found") {
+			return nil, errors.Errorf("%s %s is not signed", kind, name)
+		}
+		return nil, errors.Wrapf(err, "%s %s does not have a trusted signature", kind, name)
+	}
+
+	if m := sshGoodSignature.FindStringSubmatch(stderr.String()); m != nil {
+		return &Verification{Format: "ssh", Signer: m[1], Key: m[2]}, nil
+	}
+	v := Verification{Format: "gpg"}
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[0] != "[GNUPG:]" {
+			continue
+		}
+		switch fields[1] {
+		case "GOODSIG":
+			v.Signer = strings.Join(fields[3:], " ")
+		case "VALIDSIG":
+			v.Key = fields[2]
+		}
+	}
+	if v.Signer == "" || v.Key == "" {
+		return nil, errors.Errorf("%s %s does not have a trusted signature: %s", kind, name, stderr)
+	}
+	return &v, nil
+}
+
+// annotatedTag returns the full ref of the annotated tag with the given name,
+// if it exists and points at the commit.
+func annotatedTag(ctx context.Context, git *gitCLI, name string, commit string) (string, bool) {
+	if name == "" || isCommitSHA(name) {
+		return "", false
+	}
+	tagRef := "refs/tags/" + strings.TrimPrefix(name, "refs/tags/")
+	buf, err := git.run(ctx, "cat-file", "-t", tagRef)
+	if err != nil || strings.TrimSpace(buf.String()) != "tag" {
+		return "", false
+	}
+	buf, err = git.run(ctx, "rev-parse", tagRef+"^{commit}")
+	if err != nil || strings.TrimSpace(buf.String()) != commit {
+		return "", false
+	}
+	return tagRef, true
+}
@@ -6,8 +6,29 @@ import (
 
 const AttrDNSNamespace = "dagger.dns.namespace"
 
+const (
+	// AttrHTTPHeaders holds the JSON-encoded headers sent with the request.
+	AttrHTTPHeaders = "dagger.http.headers"
+	// AttrHTTPAuthTokenSecret holds the ID of the secret sent as a bearer
+	// token.
+	AttrHTTPAuthTokenSecret = "dagger.http.authtokensecret"
+	// AttrHTTPAuthHeaderSecret holds the ID of the secret sent as the value
+	// of the Authorization header.
+	AttrHTTPAuthHeaderSecret = "dagger.http.authheadersecret"
+)
+
 type HTTPIdentifier struct {
 	bkhttp.HTTPIdentifier
 
 	Namespace string
+
+	Headers          []Header
+	AuthTokenSecret  string
+	AuthHeaderSecret string
+}
+
+// Header is an HTTP header sent with the request.
+type Header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
 }
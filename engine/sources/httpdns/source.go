@@ -19,6 +19,7 @@ import (
 	"github.com/moby/buildkit/cache"
 	"github.com/moby/buildkit/executor/oci"
 	"github.com/moby/buildkit/session"
+	"github.com/moby/buildkit/session/secrets"
 	"github.com/moby/buildkit/snapshot"
 	"github.com/moby/buildkit/solver"
 	"github.com/moby/buildkit/solver/pb"
@@ -79,6 +80,17 @@ func (hs *httpSource) Identifier(scheme, ref string, attrs map[string]string, pl
 	if v, ok := attrs[AttrDNSNamespace]; ok {
 		id.Namespace = v
 	}
+	if v, ok := attrs[AttrHTTPHeaders]; ok {
+		if err := json.Unmarshal([]byte(v), &id.Headers); err != nil {
+			return nil, errors.Wrapf(err, "invalid %s", AttrHTTPHeaders)
+		}
+	}
+	if v, ok := attrs[AttrHTTPAuthTokenSecret]; ok {
+		id.AuthTokenSecret = v
+	}
+	if v, ok := attrs[AttrHTTPAuthHeaderSecret]; ok {
+		id.AuthHeaderSecret = v
+	}
 
 	return id, nil
 }
@@ -122,11 +134,13 @@ func (hs *httpSourceHandler) urlHash() (digest.Digest, error) {
 	dt, err := json.Marshal(struct {
 		Filename       string
 		Perm, UID, GID int
+		requestKey
 	}{
-		Filename: getFileName(hs.src.URL, hs.src.Filename, nil),
-		Perm:     hs.src.Perm,
-		UID:      hs.src.UID,
-		GID:      hs.src.GID,
+		Filename:   getFileName(hs.src.URL, hs.src.Filename, nil),
+		Perm:       hs.src.Perm,
+		UID:        hs.src.UID,
+		GID:        hs.src.GID,
+		requestKey: hs.requestKey(),
 	})
 	if err != nil {
 		return "", err
@@ -140,6 +154,7 @@ func (hs *httpSourceHandler) formatCacheKey(filename string, dgst digest.Digest,
 		Perm, UID, GID int
 		Checksum       digest.Digest
 		LastModTime    string `json:",omitempty"`
+		requestKey
 	}{
 		Filename:    filename,
 		Perm:        hs.src.Perm,
@@ -147,6 +162,7 @@ func (hs *httpSourceHandler) formatCacheKey(filename string, dgst digest.Digest,
 		GID:         hs.src.GID,
 		Checksum:    dgst,
 		LastModTime: lastModTime,
+		requestKey:  hs.requestKey(),
 	})
 	if err != nil {
 		return dgst
@@ -154,6 +170,56 @@ func (hs *httpSourceHandler) formatCacheKey(filename string, dgst digest.Digest,
 	return digest.FromBytes(dt)
 }
 
+// requestKey identifies the customizations of the request in cache keys,
+// referring to secrets by ID rather than by value.
+type requestKey struct {
+	Headers          []Header `json:",omitempty"`
+	AuthTokenSecret  string   `json:",omitempty"`
+	AuthHeaderSecret string   `json:",omitempty"`
+}
+
+func (hs *httpSourceHandler) requestKey() requestKey {
+	return requestKey{
+		Headers:          hs.src.Headers,
+		AuthTokenSecret:  hs.src.AuthTokenSecret,
+		AuthHeaderSecret: hs.src.AuthHeaderSecret,
+	}
+}
+
+// newRequest creates a GET request for the URL with the configured headers
+// and authorization.
+func (hs *httpSourceHandler) newRequest(ctx context.Context, g session.Group) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", hs.src.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, h := range hs.src.Headers {
+		req.Header.Add(h.Name, h.Value)
+	}
+
+	var secretID, prefix string
+	switch {
+	case hs.src.AuthHeaderSecret != "":
+		secretID = hs.src.AuthHeaderSecret
+	case hs.src.AuthTokenSecret != "":
+		secretID, prefix = hs.src.AuthTokenSecret, "Bearer "
+	default:
+		return req, nil
+	}
+	err = hs.sm.Any(ctx, g, func(ctx context.Context, _ string, caller session.Caller) error {
+		dt, err := secrets.GetSecret(ctx, caller, secretID)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", prefix+string(dt))
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get authorization secret")
+	}
+	return req, nil
+}
+
 func (hs *httpSourceHandler) CacheKey(ctx context.Context, g session.Group, index int) (string, string, solver.CacheOpts, bool, error) {
 	if hs.src.Checksum != "" {
 		hs.cacheKey = hs.src.Checksum
@@ -171,11 +237,10 @@ func (hs *httpSourceHandler) CacheKey(ctx context.Context, g session.Group, inde
 		return "", "", nil, false, errors.Wrapf(err, "failed to search metadata for %s", uh)
 	}
 
-	req, err := http.NewRequest("GET", hs.src.URL, nil)
+	req, err := hs.newRequest(ctx, g)
 	if err != nil {
 		return "", "", nil, false, err
 	}
-	req = req.WithContext(ctx)
 	m := map[string]cacheRefMetadata{}
 
 	// If we request a single ETag in 'If-None-Match', some servers omit the
@@ -422,11 +487,10 @@ func (hs *httpSourceHandler) Snapshot(ctx context.Context, g session.Group) (cac
 		}
 	}
 
-	req, err := http.NewRequest("GET", hs.src.URL, nil)
+	req, err := hs.newRequest(ctx, g)
 	if err != nil {
 		return nil, err
 	}
-	req = req.WithContext(ctx)
 
 	client := hs.client(g)
 
@@ -437,6 +501,9 @@ func (hs *httpSourceHandler) Snapshot(ctx context.Context, g session.Group) (cac
 	defer func() {
 		_ = resp.Body.Close()
 	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return nil, errors.Errorf("invalid response status %d", resp.StatusCode)
+	}
 
 	ref, dgst, err := hs.save(ctx, resp, g)
 	if err != nil {
@@ -444,7 +511,7 @@ func (hs *httpSourceHandler) Snapshot(ctx context.Context, g session.Group) (cac
 	}
 	if dgst != hs.cacheKey {
 		ref.Release(context.TODO())
-		return nil, errors.Errorf("digest mismatch %s: %s", dgst, hs.cacheKey)
+		return nil, errors.Errorf("digest mismatch for %s: expected %s, got %s", hs.src.URL, hs.cacheKey, dgst)
 	}
 
 	return ref, nil
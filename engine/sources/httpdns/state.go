@@ -1,15 +1,27 @@
 package httpdns
 
 import (
+	"encoding/json"
 	"strconv"
 
 	"github.com/moby/buildkit/client/llb"
 	"github.com/moby/buildkit/solver/pb"
 )
 
+// RequestOpts customizes the request made to fetch the URL.
+type RequestOpts struct {
+	// Headers are sent with the request.
+	Headers []Header
+	// AuthTokenSecret is the ID of the secret sent as a bearer token.
+	AuthTokenSecret string
+	// AuthHeaderSecret is the ID of the secret sent as the value of the
+	// Authorization header, overriding AuthTokenSecret.
+	AuthHeaderSecret string
+}
+
 // HTTP is a helper mimicking the llb.HTTP function, but with the ability to
 // set additional attributes.
-func HTTP(url string, namespace string, opts ...llb.HTTPOption) llb.State {
+func HTTP(url string, namespace string, request RequestOpts, opts ...llb.HTTPOption) (llb.State, error) {
 	hi := &llb.HTTPInfo{}
 	for _, o := range opts {
 		o.SetHTTPOption(hi)
@@ -31,8 +43,22 @@ func HTTP(url string, namespace string, opts ...llb.HTTPOption) llb.State {
 		attrs[pb.AttrHTTPGID] = strconv.Itoa(hi.GID)
 	}
 
+	if len(request.Headers) > 0 {
+		dt, err := json.Marshal(request.Headers)
+		if err != nil {
+			return llb.State{}, err
+		}
+		attrs[AttrHTTPHeaders] = string(dt)
+	}
+	if request.AuthTokenSecret != "" {
+		attrs[AttrHTTPAuthTokenSecret] = request.AuthTokenSecret
+	}
+	if request.AuthHeaderSecret != "" {
+		attrs[AttrHTTPAuthHeaderSecret] = request.AuthHeaderSecret
+	}
+
 	attrs[AttrDNSNamespace] = namespace
 
 	source := llb.NewSource(url, attrs, hi.Constraints)
-	return llb.NewState(source.Output())
+	return llb.NewState(source.Output()), nil
 }
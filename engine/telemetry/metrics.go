@@ -4,11 +4,14 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	colmetricspb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
 	"golang.org/x/sync/errgroup"
 
 	"dagger.io/dagger/telemetry"
+
+	"github.com/dagger/dagger/dagql"
 )
 
 func ReexportMetricsFromPB(ctx context.Context, exps []sdkmetric.Exporter, req *colmetricspb.ExportMetricsServiceRequest) error {
@@ -34,3 +37,60 @@ func ReexportMetricsFromPB(ctx context.Context, exps []sdkmetric.Exporter, req *
 
 	return nil
 }
+
+const (
+	// OTel metric for the number of results returned from a session's dagql cache
+	DagqlCacheHits = "dagger.io/metrics.dagql.cache.hits"
+
+	// OTel metric for the number of results initialized by a session's dagql cache
+	DagqlCacheMisses = "dagger.io/metrics.dagql.cache.misses"
+
+	// OTel metric for the number of results evicted from a session's dagql cache to respect its limits
+	DagqlCacheEvictions = "dagger.io/metrics.dagql.cache.evictions"
+
+	// OTel metric for the number of results in a session's dagql cache
+	DagqlCacheEntries = "dagger.io/metrics.dagql.cache.entries"
+
+	// OTel metric for the approximate size of the results in a session's dagql cache
+	DagqlCacheBytes = "dagger.io/metrics.dagql.cache.bytes"
+)
+
+// ObserveDagqlCache reports the usage of a dagql cache to the meter whenever
+// its metrics are collected, until the returned registration is unregistered.
+func ObserveDagqlCache(meter metric.Meter, stats func() dagql.CacheStats) (metric.Registration, error) {
+	hits, err := meter.Int64ObservableCounter(DagqlCacheHits,
+		metric.WithDescription("Results returned from the dagql cache"))
+	if err != nil {
+		return nil, err
+	}
+	misses, err := meter.Int64ObservableCounter(DagqlCacheMisses,
+		metric.WithDescription("Results initialized by the dagql cache"))
+	if err != nil {
+		return nil, err
+	}
+	evictions, err := meter.Int64ObservableCounter(DagqlCacheEvictions,
+		metric.WithDescription("Results evicted from the dagql cache"))
+	if err != nil {
+		return nil, err
+	}
+	entries, err := meter.Int64ObservableGauge(DagqlCacheEntries,
+		metric.WithDescription("Results in the dagql cache"))
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := meter.Int64ObservableGauge(DagqlCacheBytes,
+		metric.WithDescription("Approximate size of the results in the dagql cache"),
+		metric.WithUnit("bytes"))
+	if err != nil {
+		return nil, err
+	}
+	return meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		s := stats()
+		o.ObserveInt64(hits, s.Hits)
+		o.ObserveInt64(misses, s.Misses)
+		o.ObserveInt64(evictions, s.Evictions)
+		o.ObserveInt64(entries, int64(s.Entries))
+		o.ObserveInt64(bytes, s.Bytes)
+		return nil
+	}, hits, misses, evictions, entries, bytes)
+}
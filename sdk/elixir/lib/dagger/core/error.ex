@@ -1,16 +1,20 @@
 defmodule Dagger.Core.ExecError do
   @moduledoc """
   API error from an exec operation.
+
+  `reason` is set when the engine terminated the command, e.g. `"TIMEOUT"` or
+  `"OOM_KILLED"`.
   """
 
-  defexception [:original_error, :cmd, :exit_code, :stdout, :stderr]
+  defexception [:original_error, :cmd, :exit_code, :stdout, :stderr, :reason]
 
   def from_map(map) do
     %__MODULE__{
       cmd: map["cmd"],
       exit_code: map["exitCode"],
       stdout: map["stdout"],
-      stderr: map["stderr"]
+      stderr: map["stderr"],
+      reason: map["reason"]
     }
   end
 
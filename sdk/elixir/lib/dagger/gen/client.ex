@@ -147,6 +147,26 @@ defmodule Dagger.Client do
     }
   end
 
+  @doc """
+  Explains why a call missed the cache, by comparing it to the same call in a previous run.
+
+  Calls are looked up in the telemetry the engine keeps of its recent clients. The first difference is reported, in the order the calls are evaluated: the objects that calls are chained from first, then arguments, then content.
+  """
+  @spec explain_cache_miss(t(), String.t(), [{:previous_digest, String.t() | nil}]) ::
+          Dagger.CacheMissExplanation.t()
+  def explain_cache_miss(%__MODULE__{} = client, digest, optional_args \\ []) do
+    query_builder =
+      client.query_builder
+      |> QB.select("explainCacheMiss")
+      |> QB.put_arg("digest", digest)
+      |> QB.maybe_put_arg("previousDigest", optional_args[:previous_digest])
+
+    %Dagger.CacheMissExplanation{
+      query_builder: query_builder,
+      client: client.client
+    }
+  end
+
   @doc "Creates a function."
   @spec function(t(), String.t(), Dagger.TypeDef.t()) :: Dagger.Function.t()
   def function(%__MODULE__{} = client, name, return_type) do
@@ -212,14 +232,27 @@ defmodule Dagger.Client do
   end
 
   @doc "Returns a file containing an http remote url content."
-  @spec http(t(), String.t(), [{:experimental_service_host, Dagger.ServiceID.t() | nil}]) ::
-          Dagger.File.t()
+  @spec http(t(), String.t(), [
+          {:experimental_service_host, Dagger.ServiceID.t() | nil},
+          {:checksum, String.t() | nil},
+          {:headers, [Dagger.HTTPHeader.t()]},
+          {:auth_token, Dagger.SecretID.t() | nil},
+          {:auth_header, Dagger.SecretID.t() | nil},
+          {:name, String.t() | nil},
+          {:permissions, integer() | nil}
+        ]) :: Dagger.File.t()
   def http(%__MODULE__{} = client, url, optional_args \\ []) do
     query_builder =
       client.query_builder
       |> QB.select("http")
       |> QB.put_arg("url", url)
       |> QB.maybe_put_arg("experimentalServiceHost", optional_args[:experimental_service_host])
+      |> QB.maybe_put_arg("checksum", optional_args[:checksum])
+      |> QB.maybe_put_arg("headers", optional_args[:headers])
+      |> QB.maybe_put_arg("authToken", optional_args[:auth_token])
+      |> QB.maybe_put_arg("authHeader", optional_args[:auth_header])
+      |> QB.maybe_put_arg("name", optional_args[:name])
+      |> QB.maybe_put_arg("permissions", optional_args[:permissions])
 
     %Dagger.File{
       query_builder: query_builder,
@@ -227,6 +260,19 @@ defmodule Dagger.Client do
     }
   end
 
+  @doc "Load a CacheMissExplanation from its ID."
+  @spec load_cache_miss_explanation_from_id(t(), Dagger.CacheMissExplanationID.t()) ::
+          Dagger.CacheMissExplanation.t()
+  def load_cache_miss_explanation_from_id(%__MODULE__{} = client, id) do
+    query_builder =
+      client.query_builder |> QB.select("loadCacheMissExplanationFromID") |> QB.put_arg("id", id)
+
+    %Dagger.CacheMissExplanation{
+      query_builder: query_builder,
+      client: client.client
+    }
+  end
+
   @doc "Load a CacheVolume from its ID."
   @spec load_cache_volume_from_id(t(), Dagger.CacheVolumeID.t()) :: Dagger.CacheVolume.t()
   def load_cache_volume_from_id(%__MODULE__{} = client, id) do
@@ -239,6 +285,19 @@ defmodule Dagger.Client do
     }
   end
 
+  @doc "Load a ComposeProject from its ID."
+  @spec load_compose_project_from_id(t(), Dagger.ComposeProjectID.t()) ::
+          Dagger.ComposeProject.t()
+  def load_compose_project_from_id(%__MODULE__{} = client, id) do
+    query_builder =
+      client.query_builder |> QB.select("loadComposeProjectFromID") |> QB.put_arg("id", id)
+
+    %Dagger.ComposeProject{
+      query_builder: query_builder,
+      client: client.client
+    }
+  end
+
   @doc "Load a Container from its ID."
   @spec load_container_from_id(t(), Dagger.ContainerID.t()) :: Dagger.Container.t()
   def load_container_from_id(%__MODULE__{} = client, id) do
@@ -459,6 +518,30 @@ defmodule Dagger.Client do
     }
   end
 
+  @doc "Load a GitActor from its ID."
+  @spec load_git_actor_from_id(t(), Dagger.GitActorID.t()) :: Dagger.GitActor.t()
+  def load_git_actor_from_id(%__MODULE__{} = client, id) do
+    query_builder =
+      client.query_builder |> QB.select("loadGitActorFromID") |> QB.put_arg("id", id)
+
+    %Dagger.GitActor{
+      query_builder: query_builder,
+      client: client.client
+    }
+  end
+
+  @doc "Load a GitCommitInfo from its ID."
+  @spec load_git_commit_info_from_id(t(), Dagger.GitCommitInfoID.t()) :: Dagger.GitCommitInfo.t()
+  def load_git_commit_info_from_id(%__MODULE__{} = client, id) do
+    query_builder =
+      client.query_builder |> QB.select("loadGitCommitInfoFromID") |> QB.put_arg("id", id)
+
+    %Dagger.GitCommitInfo{
+      query_builder: query_builder,
+      client: client.client
+    }
+  end
+
   @doc "Load a GitModuleSource from its ID."
   @spec load_git_module_source_from_id(t(), Dagger.GitModuleSourceID.t()) ::
           Dagger.GitModuleSource.t()
@@ -496,6 +579,18 @@ defmodule Dagger.Client do
     }
   end
 
+  @doc "Load a GitSignature from its ID."
+  @spec load_git_signature_from_id(t(), Dagger.GitSignatureID.t()) :: Dagger.GitSignature.t()
+  def load_git_signature_from_id(%__MODULE__{} = client, id) do
+    query_builder =
+      client.query_builder |> QB.select("loadGitSignatureFromID") |> QB.put_arg("id", id)
+
+    %Dagger.GitSignature{
+      query_builder: query_builder,
+      client: client.client
+    }
+  end
+
   @doc "Load a Host from its ID."
   @spec load_host_from_id(t(), Dagger.HostID.t()) :: Dagger.Host.t()
   def load_host_from_id(%__MODULE__{} = client, id) do
@@ -508,6 +603,18 @@ defmodule Dagger.Client do
     }
   end
 
+  @doc "Load a ImageConfig from its ID."
+  @spec load_image_config_from_id(t(), Dagger.ImageConfigID.t()) :: Dagger.ImageConfig.t()
+  def load_image_config_from_id(%__MODULE__{} = client, id) do
+    query_builder =
+      client.query_builder |> QB.select("loadImageConfigFromID") |> QB.put_arg("id", id)
+
+    %Dagger.ImageConfig{
+      query_builder: query_builder,
+      client: client.client
+    }
+  end
+
   @doc "Load a InputTypeDef from its ID."
   @spec load_input_type_def_from_id(t(), Dagger.InputTypeDefID.t()) :: Dagger.InputTypeDef.t()
   def load_input_type_def_from_id(%__MODULE__{} = client, id) do
@@ -644,6 +751,18 @@ defmodule Dagger.Client do
     }
   end
 
+  @doc "Load a Registry from its ID."
+  @spec load_registry_from_id(t(), Dagger.RegistryID.t()) :: Dagger.Registry.t()
+  def load_registry_from_id(%__MODULE__{} = client, id) do
+    query_builder =
+      client.query_builder |> QB.select("loadRegistryFromID") |> QB.put_arg("id", id)
+
+    %Dagger.Registry{
+      query_builder: query_builder,
+      client: client.client
+    }
+  end
+
   @doc "Load a SDKConfig from its ID."
   @spec load_sdk_config_from_id(t(), Dagger.SDKConfigID.t()) :: Dagger.SDKConfig.t() | nil
   def load_sdk_config_from_id(%__MODULE__{} = client, id) do
@@ -805,6 +924,22 @@ defmodule Dagger.Client do
     }
   end
 
+  @doc """
+  Inspects an image in a registry, without pulling its layers.
+
+  Credentials set with Container.withRegistryAuth and the client's credentials are used to authenticate to the registry.
+  """
+  @spec registry(t(), String.t()) :: Dagger.Registry.t()
+  def registry(%__MODULE__{} = client, address) do
+    query_builder =
+      client.query_builder |> QB.select("registry") |> QB.put_arg("address", address)
+
+    %Dagger.Registry{
+      query_builder: query_builder,
+      client: client.client
+    }
+  end
+
   @doc "Creates a new secret."
   @spec secret(t(), String.t()) :: Dagger.Secret.t()
   def secret(%__MODULE__{} = client, uri) do
@@ -22,7 +22,15 @@ defmodule Dagger.Container do
           {:experimental_privileged_nesting, boolean() | nil},
           {:insecure_root_capabilities, boolean() | nil},
           {:expand, boolean() | nil},
-          {:no_init, boolean() | nil}
+          {:no_init, boolean() | nil},
+          {:timeout, integer() | nil},
+          {:cpu_limit, float() | nil},
+          {:memory_limit, integer() | nil},
+          {:pids_limit, integer() | nil},
+          {:healthcheck_exec, [String.t()]},
+          {:healthcheck_interval, integer() | nil},
+          {:healthcheck_timeout, integer() | nil},
+          {:healthcheck_retries, integer() | nil}
         ]) :: Dagger.Service.t()
   def as_service(%__MODULE__{} = container, optional_args \\ []) do
     query_builder =
@@ -37,6 +45,14 @@ defmodule Dagger.Container do
       |> QB.maybe_put_arg("insecureRootCapabilities", optional_args[:insecure_root_capabilities])
       |> QB.maybe_put_arg("expand", optional_args[:expand])
       |> QB.maybe_put_arg("noInit", optional_args[:no_init])
+      |> QB.maybe_put_arg("timeout", optional_args[:timeout])
+      |> QB.maybe_put_arg("cpuLimit", optional_args[:cpu_limit])
+      |> QB.maybe_put_arg("memoryLimit", optional_args[:memory_limit])
+      |> QB.maybe_put_arg("pidsLimit", optional_args[:pids_limit])
+      |> QB.maybe_put_arg("healthcheckExec", optional_args[:healthcheck_exec])
+      |> QB.maybe_put_arg("healthcheckInterval", optional_args[:healthcheck_interval])
+      |> QB.maybe_put_arg("healthcheckTimeout", optional_args[:healthcheck_timeout])
+      |> QB.maybe_put_arg("healthcheckRetries", optional_args[:healthcheck_retries])
 
     %Dagger.Service{
       query_builder: query_builder,
@@ -48,7 +64,13 @@ defmodule Dagger.Container do
   @spec as_tarball(t(), [
           {:platform_variants, [Dagger.ContainerID.t()]},
           {:forced_compression, Dagger.ImageLayerCompression.t() | nil},
-          {:media_types, Dagger.ImageMediaTypes.t() | nil}
+          {:media_types, Dagger.ImageMediaTypes.t() | nil},
+          {:squash, Dagger.ImageLayerSquash.t() | nil},
+          {:history, Dagger.ImageHistory.t() | nil},
+          {:signing_key, Dagger.SecretID.t() | nil},
+          {:signing_key_password, Dagger.SecretID.t() | nil},
+          {:sbom, boolean() | nil},
+          {:provenance, boolean() | nil}
         ]) :: Dagger.File.t()
   def as_tarball(%__MODULE__{} = container, optional_args \\ []) do
     query_builder =
@@ -63,6 +85,12 @@ defmodule Dagger.Container do
       )
       |> QB.maybe_put_arg("forcedCompression", optional_args[:forced_compression])
       |> QB.maybe_put_arg("mediaTypes", optional_args[:media_types])
+      |> QB.maybe_put_arg("squash", optional_args[:squash])
+      |> QB.maybe_put_arg("history", optional_args[:history])
+      |> QB.maybe_put_arg("signingKey", optional_args[:signing_key])
+      |> QB.maybe_put_arg("signingKeyPassword", optional_args[:signing_key_password])
+      |> QB.maybe_put_arg("sbom", optional_args[:sbom])
+      |> QB.maybe_put_arg("provenance", optional_args[:provenance])
 
     %Dagger.File{
       query_builder: query_builder,
@@ -225,7 +253,9 @@ defmodule Dagger.Container do
           {:platform_variants, [Dagger.ContainerID.t()]},
           {:forced_compression, Dagger.ImageLayerCompression.t() | nil},
           {:media_types, Dagger.ImageMediaTypes.t() | nil},
-          {:expand, boolean() | nil}
+          {:expand, boolean() | nil},
+          {:squash, Dagger.ImageLayerSquash.t() | nil},
+          {:history, Dagger.ImageHistory.t() | nil}
         ]) :: {:ok, String.t()} | {:error, term()}
   def export(%__MODULE__{} = container, path, optional_args \\ []) do
     query_builder =
@@ -242,6 +272,8 @@ defmodule Dagger.Container do
       |> QB.maybe_put_arg("forcedCompression", optional_args[:forced_compression])
       |> QB.maybe_put_arg("mediaTypes", optional_args[:media_types])
       |> QB.maybe_put_arg("expand", optional_args[:expand])
+      |> QB.maybe_put_arg("squash", optional_args[:squash])
+      |> QB.maybe_put_arg("history", optional_args[:history])
 
     Client.execute(container.client, query_builder)
   end
@@ -319,14 +351,43 @@ defmodule Dagger.Container do
     Client.execute(container.client, query_builder)
   end
 
-  @doc "Reads the container from an OCI tarball."
-  @spec import(t(), Dagger.File.t(), [{:tag, String.t() | nil}]) :: Dagger.Container.t()
+  @doc """
+  Reads the container from an OCI tarball.
+
+  Docker archives, like the ones produced by "docker save", are also supported. The image is picked for the container's platform, unless a single image manifest is selected.
+  """
+  @spec import(t(), Dagger.File.t(), [{:tag, String.t() | nil}, {:digest, String.t() | nil}]) ::
+          Dagger.Container.t()
   def import(%__MODULE__{} = container, source, optional_args \\ []) do
     query_builder =
       container.query_builder
       |> QB.select("import")
       |> QB.put_arg("source", Dagger.ID.id!(source))
       |> QB.maybe_put_arg("tag", optional_args[:tag])
+      |> QB.maybe_put_arg("digest", optional_args[:digest])
+
+    %Dagger.Container{
+      query_builder: query_builder,
+      client: container.client
+    }
+  end
+
+  @doc """
+  Reads the container from a directory in OCI image layout.
+
+  The image is picked for the container's platform, unless a single image manifest is selected.
+  """
+  @spec import_directory(t(), Dagger.Directory.t(), [
+          {:tag, String.t() | nil},
+          {:digest, String.t() | nil}
+        ]) :: Dagger.Container.t()
+  def import_directory(%__MODULE__{} = container, source, optional_args \\ []) do
+    query_builder =
+      container.query_builder
+      |> QB.select("importDirectory")
+      |> QB.put_arg("source", Dagger.ID.id!(source))
+      |> QB.maybe_put_arg("tag", optional_args[:tag])
+      |> QB.maybe_put_arg("digest", optional_args[:digest])
 
     %Dagger.Container{
       query_builder: query_builder,
@@ -334,6 +395,41 @@ defmodule Dagger.Container do
     }
   end
 
+  @doc """
+  Reads every platform variant of an image from an OCI tarball or a directory in OCI image layout.
+
+  The variants can be exported back as a multi-platform image with platformVariants.
+  """
+  @spec import_variants(t(), [
+          {:source, Dagger.FileID.t() | nil},
+          {:directory, Dagger.DirectoryID.t() | nil},
+          {:tag, String.t() | nil},
+          {:digest, String.t() | nil}
+        ]) :: {:ok, [Dagger.Container.t()]} | {:error, term()}
+  def import_variants(%__MODULE__{} = container, optional_args \\ []) do
+    query_builder =
+      container.query_builder
+      |> QB.select("importVariants")
+      |> QB.maybe_put_arg("source", optional_args[:source])
+      |> QB.maybe_put_arg("directory", optional_args[:directory])
+      |> QB.maybe_put_arg("tag", optional_args[:tag])
+      |> QB.maybe_put_arg("digest", optional_args[:digest])
+      |> QB.select("id")
+
+    with {:ok, items} <- Client.execute(container.client, query_builder) do
+      {:ok,
+       for %{"id" => id} <- items do
+         %Dagger.Container{
+           query_builder:
+             QB.query()
+             |> QB.select("loadContainerFromID")
+             |> QB.put_arg("id", id),
+           client: container.client
+         }
+       end}
+    end
+  end
+
   @doc "Retrieves the value of the specified label."
   @spec label(t(), String.t()) :: {:ok, String.t() | nil} | {:error, term()}
   def label(%__MODULE__{} = container, name) do
@@ -391,7 +487,13 @@ defmodule Dagger.Container do
   @spec publish(t(), String.t(), [
           {:platform_variants, [Dagger.ContainerID.t()]},
           {:forced_compression, Dagger.ImageLayerCompression.t() | nil},
-          {:media_types, Dagger.ImageMediaTypes.t() | nil}
+          {:media_types, Dagger.ImageMediaTypes.t() | nil},
+          {:squash, Dagger.ImageLayerSquash.t() | nil},
+          {:history, Dagger.ImageHistory.t() | nil},
+          {:signing_key, Dagger.SecretID.t() | nil},
+          {:signing_key_password, Dagger.SecretID.t() | nil},
+          {:sbom, boolean() | nil},
+          {:provenance, boolean() | nil}
         ]) :: {:ok, String.t()} | {:error, term()}
   def publish(%__MODULE__{} = container, address, optional_args \\ []) do
     query_builder =
@@ -407,6 +509,12 @@ defmodule Dagger.Container do
       )
       |> QB.maybe_put_arg("forcedCompression", optional_args[:forced_compression])
       |> QB.maybe_put_arg("mediaTypes", optional_args[:media_types])
+      |> QB.maybe_put_arg("squash", optional_args[:squash])
+      |> QB.maybe_put_arg("history", optional_args[:history])
+      |> QB.maybe_put_arg("signingKey", optional_args[:signing_key])
+      |> QB.maybe_put_arg("signingKeyPassword", optional_args[:signing_key_password])
+      |> QB.maybe_put_arg("sbom", optional_args[:sbom])
+      |> QB.maybe_put_arg("provenance", optional_args[:provenance])
 
     Client.execute(container.client, query_builder)
   end
@@ -507,7 +615,15 @@ defmodule Dagger.Container do
           {:experimental_privileged_nesting, boolean() | nil},
           {:insecure_root_capabilities, boolean() | nil},
           {:expand, boolean() | nil},
-          {:no_init, boolean() | nil}
+          {:no_init, boolean() | nil},
+          {:timeout, integer() | nil},
+          {:cpu_limit, float() | nil},
+          {:memory_limit, integer() | nil},
+          {:pids_limit, integer() | nil},
+          {:healthcheck_exec, [String.t()]},
+          {:healthcheck_interval, integer() | nil},
+          {:healthcheck_timeout, integer() | nil},
+          {:healthcheck_retries, integer() | nil}
         ]) :: :ok | {:error, term()}
   def up(%__MODULE__{} = container, optional_args \\ []) do
     query_builder =
@@ -524,6 +640,14 @@ defmodule Dagger.Container do
       |> QB.maybe_put_arg("insecureRootCapabilities", optional_args[:insecure_root_capabilities])
       |> QB.maybe_put_arg("expand", optional_args[:expand])
       |> QB.maybe_put_arg("noInit", optional_args[:no_init])
+      |> QB.maybe_put_arg("timeout", optional_args[:timeout])
+      |> QB.maybe_put_arg("cpuLimit", optional_args[:cpu_limit])
+      |> QB.maybe_put_arg("memoryLimit", optional_args[:memory_limit])
+      |> QB.maybe_put_arg("pidsLimit", optional_args[:pids_limit])
+      |> QB.maybe_put_arg("healthcheckExec", optional_args[:healthcheck_exec])
+      |> QB.maybe_put_arg("healthcheckInterval", optional_args[:healthcheck_interval])
+      |> QB.maybe_put_arg("healthcheckTimeout", optional_args[:healthcheck_timeout])
+      |> QB.maybe_put_arg("healthcheckRetries", optional_args[:healthcheck_retries])
 
     case Client.execute(container.client, query_builder) do
       {:ok, _} -> :ok
@@ -656,7 +780,12 @@ defmodule Dagger.Container do
           {:experimental_privileged_nesting, boolean() | nil},
           {:insecure_root_capabilities, boolean() | nil},
           {:expand, boolean() | nil},
-          {:no_init, boolean() | nil}
+          {:no_init, boolean() | nil},
+          {:network_mode, Dagger.NetworkMode.t() | nil},
+          {:timeout, integer() | nil},
+          {:cpu_limit, float() | nil},
+          {:memory_limit, integer() | nil},
+          {:pids_limit, integer() | nil}
         ]) :: Dagger.Container.t()
   def with_exec(%__MODULE__{} = container, args, optional_args \\ []) do
     query_builder =
@@ -675,6 +804,11 @@ defmodule Dagger.Container do
       |> QB.maybe_put_arg("insecureRootCapabilities", optional_args[:insecure_root_capabilities])
       |> QB.maybe_put_arg("expand", optional_args[:expand])
       |> QB.maybe_put_arg("noInit", optional_args[:no_init])
+      |> QB.maybe_put_arg("networkMode", optional_args[:network_mode])
+      |> QB.maybe_put_arg("timeout", optional_args[:timeout])
+      |> QB.maybe_put_arg("cpuLimit", optional_args[:cpu_limit])
+      |> QB.maybe_put_arg("memoryLimit", optional_args[:memory_limit])
+      |> QB.maybe_put_arg("pidsLimit", optional_args[:pids_limit])
 
     %Dagger.Container{
       query_builder: query_builder,
@@ -694,7 +828,10 @@ defmodule Dagger.Container do
   @spec with_exposed_port(t(), integer(), [
           {:protocol, Dagger.NetworkProtocol.t() | nil},
           {:description, String.t() | nil},
-          {:experimental_skip_healthcheck, boolean() | nil}
+          {:experimental_skip_healthcheck, boolean() | nil},
+          {:healthcheck_path, String.t() | nil},
+          {:healthcheck_scheme, Dagger.HealthcheckScheme.t() | nil},
+          {:healthcheck_status, integer() | nil}
         ]) :: Dagger.Container.t()
   def with_exposed_port(%__MODULE__{} = container, port, optional_args \\ []) do
     query_builder =
@@ -707,6 +844,9 @@ defmodule Dagger.Container do
         "experimentalSkipHealthcheck",
         optional_args[:experimental_skip_healthcheck]
       )
+      |> QB.maybe_put_arg("healthcheckPath", optional_args[:healthcheck_path])
+      |> QB.maybe_put_arg("healthcheckScheme", optional_args[:healthcheck_scheme])
+      |> QB.maybe_put_arg("healthcheckStatus", optional_args[:healthcheck_status])
 
     %Dagger.Container{
       query_builder: query_builder,
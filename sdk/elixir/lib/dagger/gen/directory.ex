@@ -11,6 +11,26 @@ defmodule Dagger.Directory do
 
   @type t() :: %__MODULE__{}
 
+  @doc """
+  Load a Compose project from a Compose file in this directory.
+
+  Paths in the Compose file, such as build contexts and bind mounts, are resolved relative to the Compose file's directory. Variables are interpolated from a .env file next to the Compose file, if any.
+  """
+  @spec as_compose(t(), [{:path, String.t() | nil}, {:project_name, String.t() | nil}]) ::
+          Dagger.ComposeProject.t()
+  def as_compose(%__MODULE__{} = directory, optional_args \\ []) do
+    query_builder =
+      directory.query_builder
+      |> QB.select("asCompose")
+      |> QB.maybe_put_arg("path", optional_args[:path])
+      |> QB.maybe_put_arg("projectName", optional_args[:project_name])
+
+    %Dagger.ComposeProject{
+      query_builder: query_builder,
+      client: directory.client
+    }
+  end
+
   @doc "Load the directory as a Dagger module"
   @spec as_module(t(), [
           {:source_root_path, String.t() | nil},
@@ -29,6 +49,18 @@ defmodule Dagger.Directory do
     }
   end
 
+  @doc "Returns a unified diff, in the format of "git diff", of the files changed between this directory and another directory."
+  @spec as_patch(t(), Dagger.Directory.t()) :: Dagger.File.t()
+  def as_patch(%__MODULE__{} = directory, other) do
+    query_builder =
+      directory.query_builder |> QB.select("asPatch") |> QB.put_arg("other", Dagger.ID.id!(other))
+
+    %Dagger.File{
+      query_builder: query_builder,
+      client: directory.client
+    }
+  end
+
   @doc "Gets the difference between this directory and an another directory."
   @spec diff(t(), Dagger.Directory.t()) :: Dagger.Directory.t()
   def diff(%__MODULE__{} = directory, other) do
@@ -68,7 +100,11 @@ defmodule Dagger.Directory do
           {:dockerfile, String.t() | nil},
           {:target, String.t() | nil},
           {:build_args, [Dagger.BuildArg.t()]},
-          {:secrets, [Dagger.SecretID.t()]}
+          {:secrets, [Dagger.SecretID.t()]},
+          {:build_contexts, [Dagger.BuildContext.t()]},
+          {:ssh, Dagger.SocketID.t() | nil},
+          {:no_cache_filter, [String.t()]},
+          {:cache_from, [String.t()]}
         ]) :: Dagger.Container.t()
   def docker_build(%__MODULE__{} = directory, optional_args \\ []) do
     query_builder =
@@ -85,6 +121,10 @@ defmodule Dagger.Directory do
           else: nil
         )
       )
+      |> QB.maybe_put_arg("buildContexts", optional_args[:build_contexts])
+      |> QB.maybe_put_arg("ssh", optional_args[:ssh])
+      |> QB.maybe_put_arg("noCacheFilter", optional_args[:no_cache_filter])
+      |> QB.maybe_put_arg("cacheFrom", optional_args[:cache_from])
 
     %Dagger.Container{
       query_builder: query_builder,
@@ -146,6 +186,22 @@ defmodule Dagger.Directory do
     Client.execute(directory.client, query_builder)
   end
 
+  @doc """
+  Retrieves this directory with the contents of the given directories merged into it.
+
+  Fails if the same path holds different contents in more than one of the directories, instead of overwriting it.
+  """
+  @spec merge(t(), [Dagger.DirectoryID.t()]) :: Dagger.Directory.t()
+  def merge(%__MODULE__{} = directory, directories) do
+    query_builder =
+      directory.query_builder |> QB.select("merge") |> QB.put_arg("directories", directories)
+
+    %Dagger.Directory{
+      query_builder: query_builder,
+      client: directory.client
+    }
+  end
+
   @doc "Force evaluation in the engine."
   @spec sync(t()) :: {:ok, Dagger.Directory.t()} | {:error, term()}
   def sync(%__MODULE__{} = directory) do
@@ -276,6 +332,24 @@ defmodule Dagger.Directory do
     }
   end
 
+  @doc """
+  Retrieves this directory with the given unified diff applied.
+
+  Fails if any hunk of the patch does not apply cleanly.
+  """
+  @spec with_patch(t(), Dagger.File.t()) :: Dagger.Directory.t()
+  def with_patch(%__MODULE__{} = directory, patch) do
+    query_builder =
+      directory.query_builder
+      |> QB.select("withPatch")
+      |> QB.put_arg("patch", Dagger.ID.id!(patch))
+
+    %Dagger.Directory{
+      query_builder: query_builder,
+      client: directory.client
+    }
+  end
+
   @doc "Retrieves this directory with all file/dir timestamps set to the given time."
   @spec with_timestamps(t(), integer()) :: Dagger.Directory.t()
   def with_timestamps(%__MODULE__{} = directory, timestamp) do
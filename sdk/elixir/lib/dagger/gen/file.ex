@@ -11,6 +11,24 @@ defmodule Dagger.File do
 
   @type t() :: %__MODULE__{}
 
+  @doc """
+  Load a Compose project from this Compose file.
+
+  Services that are built or that bind mount paths can only be loaded from a directory with Directory.asCompose.
+  """
+  @spec as_compose(t(), [{:project_name, String.t() | nil}]) :: Dagger.ComposeProject.t()
+  def as_compose(%__MODULE__{} = file, optional_args \\ []) do
+    query_builder =
+      file.query_builder
+      |> QB.select("asCompose")
+      |> QB.maybe_put_arg("projectName", optional_args[:project_name])
+
+    %Dagger.ComposeProject{
+      query_builder: query_builder,
+      client: file.client
+    }
+  end
+
   @doc "Retrieves the contents of the file."
   @spec contents(t()) :: {:ok, String.t()} | {:error, term()}
   def contents(%__MODULE__{} = file) do
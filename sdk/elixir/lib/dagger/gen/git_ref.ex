@@ -20,6 +20,31 @@ defmodule Dagger.GitRef do
     Client.execute(git_ref.client, query_builder)
   end
 
+  @doc "The metadata of the resolved commit at this ref."
+  @spec commit_info(t()) :: Dagger.GitCommitInfo.t()
+  def commit_info(%__MODULE__{} = git_ref) do
+    query_builder =
+      git_ref.query_builder |> QB.select("commitInfo")
+
+    %Dagger.GitCommitInfo{
+      query_builder: query_builder,
+      client: git_ref.client
+    }
+  end
+
+  @doc """
+  The nearest tag reachable from this ref, as returned by "git describe --tags".
+
+  If the tag doesn't point at the ref itself, it's suffixed with the number of commits since the tag and the abbreviated commit id (e.g., "v0.3.9-4-gb6315d8").
+  """
+  @spec describe(t()) :: {:ok, String.t()} | {:error, term()}
+  def describe(%__MODULE__{} = git_ref) do
+    query_builder =
+      git_ref.query_builder |> QB.select("describe")
+
+    Client.execute(git_ref.client, query_builder)
+  end
+
   @doc "A unique identifier for this GitRef."
   @spec id(t()) :: {:ok, Dagger.GitRefID.t()} | {:error, term()}
   def id(%__MODULE__{} = git_ref) do
@@ -29,17 +54,64 @@ defmodule Dagger.GitRef do
     Client.execute(git_ref.client, query_builder)
   end
 
+  @doc "The signature of this ref, if it was verified by "verify"."
+  @spec signature(t()) :: Dagger.GitSignature.t() | nil
+  def signature(%__MODULE__{} = git_ref) do
+    query_builder =
+      git_ref.query_builder |> QB.select("signature")
+
+    %Dagger.GitSignature{
+      query_builder: query_builder,
+      client: git_ref.client
+    }
+  end
+
   @doc "The filesystem tree at this ref."
-  @spec tree(t(), [{:discard_git_dir, boolean() | nil}]) :: Dagger.Directory.t()
+  @spec tree(t(), [
+          {:discard_git_dir, boolean() | nil},
+          {:sparse_checkout, [String.t()]},
+          {:depth, integer() | nil},
+          {:full_history, boolean() | nil},
+          {:skip_submodules, boolean() | nil},
+          {:lfs, boolean() | nil}
+        ]) :: Dagger.Directory.t()
   def tree(%__MODULE__{} = git_ref, optional_args \\ []) do
     query_builder =
       git_ref.query_builder
       |> QB.select("tree")
       |> QB.maybe_put_arg("discardGitDir", optional_args[:discard_git_dir])
+      |> QB.maybe_put_arg("sparseCheckout", optional_args[:sparse_checkout])
+      |> QB.maybe_put_arg("depth", optional_args[:depth])
+      |> QB.maybe_put_arg("fullHistory", optional_args[:full_history])
+      |> QB.maybe_put_arg("skipSubmodules", optional_args[:skip_submodules])
+      |> QB.maybe_put_arg("lfs", optional_args[:lfs])
 
     %Dagger.Directory{
       query_builder: query_builder,
       client: git_ref.client
     }
   end
+
+  @doc """
+  Verify the GPG or SSH signature of the annotated tag or commit at this ref, failing if it's missing or not made by one of the trusted keys.
+
+  Returns this ref pinned to the verified commit.
+  """
+  @spec verify(t(), [{:keys, [Dagger.FileID.t()]}, {:allowed_signers, Dagger.FileID.t() | nil}]) ::
+          Dagger.GitRef.t()
+  def verify(%__MODULE__{} = git_ref, optional_args \\ []) do
+    query_builder =
+      git_ref.query_builder
+      |> QB.select("verify")
+      |> QB.maybe_put_arg(
+        "keys",
+        if(optional_args[:keys], do: Enum.map(optional_args[:keys], &Dagger.ID.id!/1), else: nil)
+      )
+      |> QB.maybe_put_arg("allowedSigners", optional_args[:allowed_signers])
+
+    %Dagger.GitRef{
+      query_builder: query_builder,
+      client: git_ref.client
+    }
+  end
 end
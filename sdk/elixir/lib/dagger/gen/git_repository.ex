@@ -56,6 +56,32 @@ defmodule Dagger.GitRepository do
     Client.execute(git_repository.client, query_builder)
   end
 
+  @doc "Returns the commits reachable from a ref, newest first."
+  @spec log(t(), [{:from, String.t() | nil}, {:to, String.t() | nil}, {:paths, [String.t()]}]) ::
+          {:ok, [Dagger.GitCommitInfo.t()]} | {:error, term()}
+  def log(%__MODULE__{} = git_repository, optional_args \\ []) do
+    query_builder =
+      git_repository.query_builder
+      |> QB.select("log")
+      |> QB.maybe_put_arg("from", optional_args[:from])
+      |> QB.maybe_put_arg("to", optional_args[:to])
+      |> QB.maybe_put_arg("paths", optional_args[:paths])
+      |> QB.select("id")
+
+    with {:ok, items} <- Client.execute(git_repository.client, query_builder) do
+      {:ok,
+       for %{"id" => id} <- items do
+         %Dagger.GitCommitInfo{
+           query_builder:
+             QB.query()
+             |> QB.select("loadGitCommitInfoFromID")
+             |> QB.put_arg("id", id),
+           client: git_repository.client
+         }
+       end}
+    end
+  end
+
   @doc "Returns details of a ref."
   @spec ref(t(), String.t()) :: Dagger.GitRef.t()
   def ref(%__MODULE__{} = git_repository, name) do
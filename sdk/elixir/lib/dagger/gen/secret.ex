@@ -38,6 +38,22 @@ defmodule Dagger.Secret do
     Client.execute(secret.client, query_builder)
   end
 
+  @doc """
+  Resolves this secret again from its provider, replacing the value the client cached for the session, e.g. after it was rotated.
+
+  Secrets set from a plaintext have nothing to refresh.
+  """
+  @spec refresh(t()) :: :ok | {:error, term()}
+  def refresh(%__MODULE__{} = secret) do
+    query_builder =
+      secret.query_builder |> QB.select("refresh")
+
+    case Client.execute(secret.client, query_builder) do
+      {:ok, _} -> :ok
+      error -> error
+    end
+  end
+
   @doc "The URI of this secret."
   @spec uri(t()) :: {:ok, String.t()} | {:error, term()}
   def uri(%__MODULE__{} = secret) do
@@ -30,6 +30,19 @@ defmodule Dagger.Service do
     Client.execute(service.client, query_builder)
   end
 
+  @doc """
+  The exit code the service last exited with, or null if it hasn't exited yet.
+
+  If the service has been restarted, this is the exit code of the run before the current one until that one exits too. The service must have been started within the session.
+  """
+  @spec exit_code(t()) :: {:ok, integer() | nil} | {:error, term()}
+  def exit_code(%__MODULE__{} = service) do
+    query_builder =
+      service.query_builder |> QB.select("exitCode")
+
+    Client.execute(service.client, query_builder)
+  end
+
   @doc "Retrieves a hostname which can be used by clients to reach this container."
   @spec hostname(t()) :: {:ok, String.t()} | {:error, term()}
   def hostname(%__MODULE__{} = service) do
@@ -90,6 +103,40 @@ defmodule Dagger.Service do
     end
   end
 
+  @doc """
+  The output the service has written to stderr.
+
+  The service must have been started within the session. Its output remains available once it has stopped, and includes the output of every run if it has restarted.
+  """
+  @spec stderr(t(), [{:tail, integer() | nil}, {:since, String.t() | nil}]) ::
+          {:ok, String.t()} | {:error, term()}
+  def stderr(%__MODULE__{} = service, optional_args \\ []) do
+    query_builder =
+      service.query_builder
+      |> QB.select("stderr")
+      |> QB.maybe_put_arg("tail", optional_args[:tail])
+      |> QB.maybe_put_arg("since", optional_args[:since])
+
+    Client.execute(service.client, query_builder)
+  end
+
+  @doc """
+  The output the service has written to stdout.
+
+  The service must have been started within the session. Its output remains available once it has stopped, and includes the output of every run if it has restarted.
+  """
+  @spec stdout(t(), [{:tail, integer() | nil}, {:since, String.t() | nil}]) ::
+          {:ok, String.t()} | {:error, term()}
+  def stdout(%__MODULE__{} = service, optional_args \\ []) do
+    query_builder =
+      service.query_builder
+      |> QB.select("stdout")
+      |> QB.maybe_put_arg("tail", optional_args[:tail])
+      |> QB.maybe_put_arg("since", optional_args[:since])
+
+    Client.execute(service.client, query_builder)
+  end
+
   @doc "Stop the service."
   @spec stop(t(), [{:kill, boolean() | nil}]) :: {:ok, Dagger.Service.t()} | {:error, term()}
   def stop(%__MODULE__{} = service, optional_args \\ []) do
@@ -135,4 +182,47 @@ defmodule Dagger.Service do
       client: service.client
     }
   end
+
+  @doc """
+  Keeps running the service's health checks once it has started.
+
+  The service is killed once its health checks fail enough times in a row, and restarted if its restart policy allows it.
+  """
+  @spec with_liveness_probe(t(), [
+          {:interval, integer() | nil},
+          {:failure_threshold, integer() | nil}
+        ]) :: Dagger.Service.t()
+  def with_liveness_probe(%__MODULE__{} = service, optional_args \\ []) do
+    query_builder =
+      service.query_builder
+      |> QB.select("withLivenessProbe")
+      |> QB.maybe_put_arg("interval", optional_args[:interval])
+      |> QB.maybe_put_arg("failureThreshold", optional_args[:failure_threshold])
+
+    %Dagger.Service{
+      query_builder: query_builder,
+      client: service.client
+    }
+  end
+
+  @doc """
+  Configures whether the service is restarted when it exits.
+
+  The service keeps the same hostname across restarts, so containers bound to it can keep reaching it.
+  """
+  @spec with_restart_policy(t(), Dagger.ServiceRestartPolicy.t(), [
+          {:max_retries, integer() | nil}
+        ]) :: Dagger.Service.t()
+  def with_restart_policy(%__MODULE__{} = service, policy, optional_args \\ []) do
+    query_builder =
+      service.query_builder
+      |> QB.select("withRestartPolicy")
+      |> QB.put_arg("policy", policy)
+      |> QB.maybe_put_arg("maxRetries", optional_args[:max_retries])
+
+    %Dagger.Service{
+      query_builder: query_builder,
+      client: service.client
+    }
+  end
 end
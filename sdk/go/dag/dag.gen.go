@@ -104,6 +104,14 @@ func Error(message string) *dagger.Error {
 	return client.Error(message)
 }
 
+// Explains why a call missed the cache, by comparing it to the same call in a previous run.
+//
+// Calls are looked up in the telemetry the engine keeps of its recent clients. The first difference is reported, in the order the calls are evaluated: the objects that calls are chained from first, then arguments, then content.
+func ExplainCacheMiss(digest string, opts ...dagger.ExplainCacheMissOpts) *dagger.CacheMissExplanation {
+	client := initClient()
+	return client.ExplainCacheMiss(digest, opts...)
+}
+
 // Creates a function.
 func Function(name string, returnType *dagger.TypeDef) *dagger.Function {
 	client := initClient()
@@ -134,12 +142,24 @@ func HTTP(url string, opts ...dagger.HTTPOpts) *dagger.File {
 	return client.HTTP(url, opts...)
 }
 
+// Load a CacheMissExplanation from its ID.
+func LoadCacheMissExplanationFromID(id dagger.CacheMissExplanationID) *dagger.CacheMissExplanation {
+	client := initClient()
+	return client.LoadCacheMissExplanationFromID(id)
+}
+
 // Load a CacheVolume from its ID.
 func LoadCacheVolumeFromID(id dagger.CacheVolumeID) *dagger.CacheVolume {
 	client := initClient()
 	return client.LoadCacheVolumeFromID(id)
 }
 
+// Load a ComposeProject from its ID.
+func LoadComposeProjectFromID(id dagger.ComposeProjectID) *dagger.ComposeProject {
+	client := initClient()
+	return client.LoadComposeProjectFromID(id)
+}
+
 // Load a Container from its ID.
 func LoadContainerFromID(id dagger.ContainerID) *dagger.Container {
 	client := initClient()
@@ -248,6 +268,18 @@ func LoadGeneratedCodeFromID(id dagger.GeneratedCodeID) *dagger.GeneratedCode {
 	return client.LoadGeneratedCodeFromID(id)
 }
 
+// Load a GitActor from its ID.
+func LoadGitActorFromID(id dagger.GitActorID) *dagger.GitActor {
+	client := initClient()
+	return client.LoadGitActorFromID(id)
+}
+
+// Load a GitCommitInfo from its ID.
+func LoadGitCommitInfoFromID(id dagger.GitCommitInfoID) *dagger.GitCommitInfo {
+	client := initClient()
+	return client.LoadGitCommitInfoFromID(id)
+}
+
 // Load a GitModuleSource from its ID.
 func LoadGitModuleSourceFromID(id dagger.GitModuleSourceID) *dagger.GitModuleSource {
 	client := initClient()
@@ -266,12 +298,24 @@ func LoadGitRepositoryFromID(id dagger.GitRepositoryID) *dagger.GitRepository {
 	return client.LoadGitRepositoryFromID(id)
 }
 
+// Load a GitSignature from its ID.
+func LoadGitSignatureFromID(id dagger.GitSignatureID) *dagger.GitSignature {
+	client := initClient()
+	return client.LoadGitSignatureFromID(id)
+}
+
 // Load a Host from its ID.
 func LoadHostFromID(id dagger.HostID) *dagger.Host {
 	client := initClient()
 	return client.LoadHostFromID(id)
 }
 
+// Load a ImageConfig from its ID.
+func LoadImageConfigFromID(id dagger.ImageConfigID) *dagger.ImageConfig {
+	client := initClient()
+	return client.LoadImageConfigFromID(id)
+}
+
 // Load a InputTypeDef from its ID.
 func LoadInputTypeDefFromID(id dagger.InputTypeDefID) *dagger.InputTypeDef {
 	client := initClient()
@@ -338,6 +382,12 @@ func LoadPortFromID(id dagger.PortID) *dagger.Port {
 	return client.LoadPortFromID(id)
 }
 
+// Load a Registry from its ID.
+func LoadRegistryFromID(id dagger.RegistryID) *dagger.Registry {
+	client := initClient()
+	return client.LoadRegistryFromID(id)
+}
+
 // Load a SDKConfig from its ID.
 func LoadSDKConfigFromID(id dagger.SDKConfigID) *dagger.SDKConfig {
 	client := initClient()
@@ -410,6 +460,14 @@ func ModuleSource(refString string, opts ...dagger.ModuleSourceOpts) *dagger.Mod
 	return client.ModuleSource(refString, opts...)
 }
 
+// Inspects an image in a registry, without pulling its layers.
+//
+// Credentials set with Container.withRegistryAuth and the client's credentials are used to authenticate to the registry.
+func Registry(address string) *dagger.Registry {
+	client := initClient()
+	return client.Registry(address)
+}
+
 // Creates a new secret.
 func Secret(uri string) *dagger.Secret {
 	client := initClient()
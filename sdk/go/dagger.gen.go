@@ -72,12 +72,25 @@ func getCustomError(err error) error {
 		if stderr, ok := ext["stderr"].(string); ok {
 			e.Stderr = stderr
 		}
+		if reason, ok := ext["reason"].(string); ok {
+			e.Reason = ExecErrorReason(reason)
+		}
 		return e
 	}
 
 	return nil
 }
 
+// ExecErrorReason describes why the engine terminated an exec.
+type ExecErrorReason string
+
+const (
+	// The exec ran past its timeout.
+	ExecErrorReasonTimeout ExecErrorReason = "TIMEOUT"
+	// The exec was killed for exceeding its memory limit.
+	ExecErrorReasonOOMKilled ExecErrorReason = "OOM_KILLED"
+)
+
 // ExecError is an API error from an exec operation.
 type ExecError struct {
 	original error
@@ -85,6 +98,8 @@ type ExecError struct {
 	ExitCode int
 	Stdout   string
 	Stderr   string
+	// Reason is set if the engine terminated the exec, e.g. due to a timeout.
+	Reason ExecErrorReason
 }
 
 func (e *ExecError) Error() string {
@@ -99,9 +114,15 @@ func (e *ExecError) Unwrap() error {
 	return e.original
 }
 
+// The `CacheMissExplanationID` scalar type represents an identifier for an object of type CacheMissExplanation.
+type CacheMissExplanationID string
+
 // The `CacheVolumeID` scalar type represents an identifier for an object of type CacheVolume.
 type CacheVolumeID string
 
+// The `ComposeProjectID` scalar type represents an identifier for an object of type ComposeProject.
+type ComposeProjectID string
+
 // The `ContainerID` scalar type represents an identifier for an object of type Container.
 type ContainerID string
 
@@ -156,6 +177,12 @@ type FunctionID string
 // The `GeneratedCodeID` scalar type represents an identifier for an object of type GeneratedCode.
 type GeneratedCodeID string
 
+// The `GitActorID` scalar type represents an identifier for an object of type GitActor.
+type GitActorID string
+
+// The `GitCommitInfoID` scalar type represents an identifier for an object of type GitCommitInfo.
+type GitCommitInfoID string
+
 // The `GitModuleSourceID` scalar type represents an identifier for an object of type GitModuleSource.
 type GitModuleSourceID string
 
@@ -165,9 +192,15 @@ type GitRefID string
 // The `GitRepositoryID` scalar type represents an identifier for an object of type GitRepository.
 type GitRepositoryID string
 
+// The `GitSignatureID` scalar type represents an identifier for an object of type GitSignature.
+type GitSignatureID string
+
 // The `HostID` scalar type represents an identifier for an object of type Host.
 type HostID string
 
+// The `ImageConfigID` scalar type represents an identifier for an object of type ImageConfig.
+type ImageConfigID string
+
 // The `InputTypeDefID` scalar type represents an identifier for an object of type InputTypeDef.
 type InputTypeDefID string
 
@@ -209,6 +242,9 @@ type Platform string
 // The `PortID` scalar type represents an identifier for an object of type Port.
 type PortID string
 
+// The `RegistryID` scalar type represents an identifier for an object of type Registry.
+type RegistryID string
+
 // The `SDKConfigID` scalar type represents an identifier for an object of type SDKConfig.
 type SDKConfigID string
 
@@ -247,6 +283,27 @@ type BuildArg struct {
 	Value string `json:"value"`
 }
 
+// A named build context for a Dockerfile build.
+type BuildContext struct {
+	// The container to use as the build context, including its image config.
+	Container *Container `json:"container"`
+
+	// The directory to use as the build context.
+	Directory *Directory `json:"directory"`
+
+	// The name of the build context, as referenced by the Dockerfile (e.g., in "COPY --from=<name>" or "FROM <name>").
+	Name string `json:"name"`
+}
+
+// Key value object that represents an HTTP header.
+type HTTPHeader struct {
+	// The header name.
+	Name string `json:"name"`
+
+	// The header value.
+	Value string `json:"value"`
+}
+
 // Key value object that represents a pipeline label.
 type PipelineLabel struct {
 	// Label name.
@@ -268,6 +325,199 @@ type PortForward struct {
 	Protocol NetworkProtocol `json:"protocol,omitempty"`
 }
 
+// The first difference between a call and the same call in a previous run, which explains why it missed the cache.
+type CacheMissExplanation struct {
+	query *querybuilder.Selection
+
+	argument       *string
+	call           *string
+	currentValue   *string
+	digest         *string
+	id             *CacheMissExplanationID
+	kind           *CacheMissKind
+	message        *string
+	previousDigest *string
+	previousTrace  *string
+	previousValue  *string
+	trace          *string
+}
+
+func (r *CacheMissExplanation) WithGraphQLQuery(q *querybuilder.Selection) *CacheMissExplanation {
+	return &CacheMissExplanation{
+		query: q,
+	}
+}
+
+// The name of the diverging argument, if the kind is ARGUMENT.
+func (r *CacheMissExplanation) Argument(ctx context.Context) (string, error) {
+	if r.argument != nil {
+		return *r.argument, nil
+	}
+	q := r.query.Select("argument")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The first diverging call, e.g. host.directory(path: "./src"), empty if the calls are the same.
+func (r *CacheMissExplanation) Call(ctx context.Context) (string, error) {
+	if r.call != nil {
+		return *r.call, nil
+	}
+	q := r.query.Select("call")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The current value, e.g. of the argument or content digest, empty if missing.
+func (r *CacheMissExplanation) CurrentValue(ctx context.Context) (string, error) {
+	if r.currentValue != nil {
+		return *r.currentValue, nil
+	}
+	q := r.query.Select("currentValue")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The digest of the call that missed the cache.
+func (r *CacheMissExplanation) Digest(ctx context.Context) (string, error) {
+	if r.digest != nil {
+		return *r.digest, nil
+	}
+	q := r.query.Select("digest")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this CacheMissExplanation.
+func (r *CacheMissExplanation) ID(ctx context.Context) (CacheMissExplanationID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response CacheMissExplanationID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *CacheMissExplanation) XXX_GraphQLType() string {
+	return "CacheMissExplanation"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *CacheMissExplanation) XXX_GraphQLIDType() string {
+	return "CacheMissExplanationID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *CacheMissExplanation) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *CacheMissExplanation) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// What differs between the calls.
+func (r *CacheMissExplanation) Kind(ctx context.Context) (CacheMissKind, error) {
+	if r.kind != nil {
+		return *r.kind, nil
+	}
+	q := r.query.Select("kind")
+
+	var response CacheMissKind
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A description of the first difference between the calls.
+func (r *CacheMissExplanation) Message(ctx context.Context) (string, error) {
+	if r.message != nil {
+		return *r.message, nil
+	}
+	q := r.query.Select("message")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The digest of the call it was compared to.
+func (r *CacheMissExplanation) PreviousDigest(ctx context.Context) (string, error) {
+	if r.previousDigest != nil {
+		return *r.previousDigest, nil
+	}
+	q := r.query.Select("previousDigest")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The trace of the previous run the call it was compared to is from.
+func (r *CacheMissExplanation) PreviousTrace(ctx context.Context) (string, error) {
+	if r.previousTrace != nil {
+		return *r.previousTrace, nil
+	}
+	q := r.query.Select("previousTrace")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The previous value, e.g. of the argument or content digest, empty if missing.
+func (r *CacheMissExplanation) PreviousValue(ctx context.Context) (string, error) {
+	if r.previousValue != nil {
+		return *r.previousValue, nil
+	}
+	q := r.query.Select("previousValue")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The trace of the run the call is from.
+func (r *CacheMissExplanation) Trace(ctx context.Context) (string, error) {
+	if r.trace != nil {
+		return *r.trace, nil
+	}
+	q := r.query.Select("trace")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // A directory whose contents persist across runs.
 type CacheVolume struct {
 	query *querybuilder.Selection
@@ -321,6 +571,95 @@ func (r *CacheVolume) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
+// A set of services described by a Compose file.
+type ComposeProject struct {
+	query *querybuilder.Selection
+
+	id   *ComposeProjectID
+	name *string
+}
+
+func (r *ComposeProject) WithGraphQLQuery(q *querybuilder.Selection) *ComposeProject {
+	return &ComposeProject{
+		query: q,
+	}
+}
+
+// A unique identifier for this ComposeProject.
+func (r *ComposeProject) ID(ctx context.Context) (ComposeProjectID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response ComposeProjectID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *ComposeProject) XXX_GraphQLType() string {
+	return "ComposeProject"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *ComposeProject) XXX_GraphQLIDType() string {
+	return "ComposeProjectID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *ComposeProject) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *ComposeProject) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The name of the project.
+func (r *ComposeProject) Name(ctx context.Context) (string, error) {
+	if r.name != nil {
+		return *r.name, nil
+	}
+	q := r.query.Select("name")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// Retrieves a service of the project.
+//
+// The services it depends on are bound to it, reachable at their names in the project. Its named volumes are cache volumes, shared with the project's other services.
+func (r *ComposeProject) Service(name string) *Service {
+	q := r.query.Select("service")
+	q = q.Arg("name", name)
+
+	return &Service{
+		query: q,
+	}
+}
+
+// The names of the services in the project.
+func (r *ComposeProject) ServiceNames(ctx context.Context) ([]string, error) {
+	q := r.query.Select("serviceNames")
+
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // An OCI-compatible container, also known as a Docker container.
 type Container struct {
 	query *querybuilder.Selection
@@ -375,6 +714,28 @@ type ContainerAsServiceOpts struct {
 	//
 	// This should only be used if the user requires that their exec process be the pid 1 process in the container. Otherwise it may result in unexpected behavior.
 	NoInit bool
+	// Number of seconds the service may run for before it's killed.
+	Timeout int
+	// Number of CPUs the service may use (e.g., 0.5).
+	CPULimit float64
+	// Memory in bytes the service may use before it's killed (e.g., 536870912 for 512 MiB).
+	MemoryLimit int
+	// Number of processes the service may have running at once.
+	PidsLimit int
+	// Command to run in the service container to check that it's healthy (e.g., ["pg_isready"]).
+	//
+	// It runs once the exposed ports are reachable, and the service is healthy once it exits successfully.
+	HealthcheckExec []string
+	// Number of seconds to wait between health check attempts.
+	//
+	// If unset, attempts back off exponentially.
+	HealthcheckInterval int
+	// Number of seconds each health check attempt may take.
+	HealthcheckTimeout int
+	// Number of times to retry a failing health check before the service fails to start.
+	//
+	// If unset, health checks are retried for up to 15 minutes.
+	HealthcheckRetries int
 }
 
 // Turn the container into a Service.
@@ -407,6 +768,38 @@ func (r *Container) AsService(opts ...ContainerAsServiceOpts) *Service {
 		if !querybuilder.IsZeroValue(opts[i].NoInit) {
 			q = q.Arg("noInit", opts[i].NoInit)
 		}
+		// `timeout` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Timeout) {
+			q = q.Arg("timeout", opts[i].Timeout)
+		}
+		// `cpuLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].CPULimit) {
+			q = q.Arg("cpuLimit", opts[i].CPULimit)
+		}
+		// `memoryLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].MemoryLimit) {
+			q = q.Arg("memoryLimit", opts[i].MemoryLimit)
+		}
+		// `pidsLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].PidsLimit) {
+			q = q.Arg("pidsLimit", opts[i].PidsLimit)
+		}
+		// `healthcheckExec` optional argument
+		if !querybuilder.IsZeroValue(opts[i].HealthcheckExec) {
+			q = q.Arg("healthcheckExec", opts[i].HealthcheckExec)
+		}
+		// `healthcheckInterval` optional argument
+		if !querybuilder.IsZeroValue(opts[i].HealthcheckInterval) {
+			q = q.Arg("healthcheckInterval", opts[i].HealthcheckInterval)
+		}
+		// `healthcheckTimeout` optional argument
+		if !querybuilder.IsZeroValue(opts[i].HealthcheckTimeout) {
+			q = q.Arg("healthcheckTimeout", opts[i].HealthcheckTimeout)
+		}
+		// `healthcheckRetries` optional argument
+		if !querybuilder.IsZeroValue(opts[i].HealthcheckRetries) {
+			q = q.Arg("healthcheckRetries", opts[i].HealthcheckRetries)
+		}
 	}
 
 	return &Service{
@@ -428,6 +821,22 @@ type ContainerAsTarballOpts struct {
 	//
 	// Defaults to OCI, which is largely compatible with most recent container runtimes, but Docker may be needed for older runtimes without OCI support.
 	MediaTypes ImageMediaTypes
+	// Squash the layers of the image into one, or the layers above the image the container was created from with "from" or "import".
+	//
+	// Squashing drops the files that were deleted by later operations.
+	Squash ImageLayerSquash
+	// How to describe the layers of the image in its history.
+	History ImageHistory
+	// A private key to sign the image with, in PEM format (e.g., generated by "cosign generate-key-pair").
+	//
+	// The signature is compatible with "cosign verify".
+	SigningKey *Secret
+	// The password of the signing key, if it is encrypted.
+	SigningKeyPassword *Secret
+	// Attach an SPDX SBOM of the image's filesystem as an attestation.
+	Sbom bool
+	// Attach a SLSA provenance, recording the container's call ID, as an attestation.
+	Provenance bool
 }
 
 // Returns a File representing the container serialized to a tarball.
@@ -446,6 +855,30 @@ func (r *Container) AsTarball(opts ...ContainerAsTarballOpts) *File {
 		if !querybuilder.IsZeroValue(opts[i].MediaTypes) {
 			q = q.Arg("mediaTypes", opts[i].MediaTypes)
 		}
+		// `squash` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Squash) {
+			q = q.Arg("squash", opts[i].Squash)
+		}
+		// `history` optional argument
+		if !querybuilder.IsZeroValue(opts[i].History) {
+			q = q.Arg("history", opts[i].History)
+		}
+		// `signingKey` optional argument
+		if !querybuilder.IsZeroValue(opts[i].SigningKey) {
+			q = q.Arg("signingKey", opts[i].SigningKey)
+		}
+		// `signingKeyPassword` optional argument
+		if !querybuilder.IsZeroValue(opts[i].SigningKeyPassword) {
+			q = q.Arg("signingKeyPassword", opts[i].SigningKeyPassword)
+		}
+		// `sbom` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Sbom) {
+			q = q.Arg("sbom", opts[i].Sbom)
+		}
+		// `provenance` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Provenance) {
+			q = q.Arg("provenance", opts[i].Provenance)
+		}
 	}
 
 	return &File{
@@ -647,6 +1080,12 @@ type ContainerExportOpts struct {
 	MediaTypes ImageMediaTypes
 	// Replace "${VAR}" or "$VAR" in the value of path according to the current environment variables defined in the container (e.g. "/$VAR/foo").
 	Expand bool
+	// Squash the layers of the image into one, or the layers above the image the container was created from with "from" or "import".
+	//
+	// Squashing drops the files that were deleted by later operations.
+	Squash ImageLayerSquash
+	// How to describe the layers of the image in its history.
+	History ImageHistory
 }
 
 // Writes the container as an OCI tarball to the destination file path on the host.
@@ -674,6 +1113,14 @@ func (r *Container) Export(ctx context.Context, path string, opts ...ContainerEx
 		if !querybuilder.IsZeroValue(opts[i].Expand) {
 			q = q.Arg("expand", opts[i].Expand)
 		}
+		// `squash` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Squash) {
+			q = q.Arg("squash", opts[i].Squash)
+		}
+		// `history` optional argument
+		if !querybuilder.IsZeroValue(opts[i].History) {
+			q = q.Arg("history", opts[i].History)
+		}
 	}
 	q = q.Arg("path", path)
 
@@ -808,10 +1255,16 @@ func (r *Container) ImageRef(ctx context.Context) (string, error) {
 // ContainerImportOpts contains options for Container.Import
 type ContainerImportOpts struct {
 	// Identifies the tag to import from the archive, if the archive bundles multiple tags.
+	//
+	// Either the tag only (e.g., "latest") or the image reference (e.g., "alpine:latest").
 	Tag string
+	// Identifies the digest of the image manifest or index to import from the archive.
+	Digest string
 }
 
 // Reads the container from an OCI tarball.
+//
+// Docker archives, like the ones produced by "docker save", are also supported. The image is picked for the container's platform, unless a single image manifest is selected.
 func (r *Container) Import(source *File, opts ...ContainerImportOpts) *Container {
 	assertNotNil("source", source)
 	q := r.query.Select("import")
@@ -820,6 +1273,10 @@ func (r *Container) Import(source *File, opts ...ContainerImportOpts) *Container
 		if !querybuilder.IsZeroValue(opts[i].Tag) {
 			q = q.Arg("tag", opts[i].Tag)
 		}
+		// `digest` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Digest) {
+			q = q.Arg("digest", opts[i].Digest)
+		}
 	}
 	q = q.Arg("source", source)
 
@@ -828,21 +1285,121 @@ func (r *Container) Import(source *File, opts ...ContainerImportOpts) *Container
 	}
 }
 
-// Retrieves the value of the specified label.
-func (r *Container) Label(ctx context.Context, name string) (string, error) {
-	if r.label != nil {
-		return *r.label, nil
-	}
-	q := r.query.Select("label")
-	q = q.Arg("name", name)
-
-	var response string
-
-	q = q.Bind(&response)
-	return response, q.Execute(ctx)
+// ContainerImportDirectoryOpts contains options for Container.ImportDirectory
+type ContainerImportDirectoryOpts struct {
+	// Identifies the tag to import from the layout, if the layout bundles multiple tags.
+	//
+	// Either the tag only (e.g., "latest") or the image reference (e.g., "alpine:latest").
+	Tag string
+	// Identifies the digest of the image manifest or index to import from the layout.
+	Digest string
 }
 
-// Retrieves the list of labels passed to container.
+// Reads the container from a directory in OCI image layout.
+//
+// The image is picked for the container's platform, unless a single image manifest is selected.
+func (r *Container) ImportDirectory(source *Directory, opts ...ContainerImportDirectoryOpts) *Container {
+	assertNotNil("source", source)
+	q := r.query.Select("importDirectory")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `tag` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Tag) {
+			q = q.Arg("tag", opts[i].Tag)
+		}
+		// `digest` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Digest) {
+			q = q.Arg("digest", opts[i].Digest)
+		}
+	}
+	q = q.Arg("source", source)
+
+	return &Container{
+		query: q,
+	}
+}
+
+// ContainerImportVariantsOpts contains options for Container.ImportVariants
+type ContainerImportVariantsOpts struct {
+	// File to read the variants from. Exactly one of source or directory must be set.
+	Source *File
+	// Directory in OCI image layout to read the variants from.
+	Directory *Directory
+	// Identifies the tag to import from the archive, if the archive bundles multiple tags.
+	//
+	// Either the tag only (e.g., "latest") or the image reference (e.g., "alpine:latest").
+	Tag string
+	// Identifies the digest of the image index to import from the archive.
+	Digest string
+}
+
+// Reads every platform variant of an image from an OCI tarball or a directory in OCI image layout.
+//
+// The variants can be exported back as a multi-platform image with platformVariants.
+func (r *Container) ImportVariants(ctx context.Context, opts ...ContainerImportVariantsOpts) ([]Container, error) {
+	q := r.query.Select("importVariants")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `source` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Source) {
+			q = q.Arg("source", opts[i].Source)
+		}
+		// `directory` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Directory) {
+			q = q.Arg("directory", opts[i].Directory)
+		}
+		// `tag` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Tag) {
+			q = q.Arg("tag", opts[i].Tag)
+		}
+		// `digest` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Digest) {
+			q = q.Arg("digest", opts[i].Digest)
+		}
+	}
+
+	q = q.Select("id")
+
+	type importVariants struct {
+		Id ContainerID
+	}
+
+	convert := func(fields []importVariants) []Container {
+		out := []Container{}
+
+		for i := range fields {
+			val := Container{id: &fields[i].Id}
+			val.query = q.Root().Select("loadContainerFromID").Arg("id", fields[i].Id)
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []importVariants
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
+// Retrieves the value of the specified label.
+func (r *Container) Label(ctx context.Context, name string) (string, error) {
+	if r.label != nil {
+		return *r.label, nil
+	}
+	q := r.query.Select("label")
+	q = q.Arg("name", name)
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// Retrieves the list of labels passed to container.
 func (r *Container) Labels(ctx context.Context) ([]Label, error) {
 	q := r.query.Select("labels")
 
@@ -912,6 +1469,22 @@ type ContainerPublishOpts struct {
 	//
 	// Defaults to OCI, which is largely compatible with most recent registries, but Docker may be needed for older registries without OCI support.
 	MediaTypes ImageMediaTypes
+	// Squash the layers of the image into one, or the layers above the image the container was created from with "from" or "import".
+	//
+	// Squashing drops the files that were deleted by later operations.
+	Squash ImageLayerSquash
+	// How to describe the layers of the image in its history.
+	History ImageHistory
+	// A private key to sign the image with, in PEM format (e.g., generated by "cosign generate-key-pair").
+	//
+	// The signature is compatible with "cosign verify".
+	SigningKey *Secret
+	// The password of the signing key, if it is encrypted.
+	SigningKeyPassword *Secret
+	// Attach an SPDX SBOM of the image's filesystem as an attestation.
+	Sbom bool
+	// Attach a SLSA provenance, recording the container's call ID, as an attestation.
+	Provenance bool
 }
 
 // Publishes this container as a new image to the specified address.
@@ -937,6 +1510,30 @@ func (r *Container) Publish(ctx context.Context, address string, opts ...Contain
 		if !querybuilder.IsZeroValue(opts[i].MediaTypes) {
 			q = q.Arg("mediaTypes", opts[i].MediaTypes)
 		}
+		// `squash` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Squash) {
+			q = q.Arg("squash", opts[i].Squash)
+		}
+		// `history` optional argument
+		if !querybuilder.IsZeroValue(opts[i].History) {
+			q = q.Arg("history", opts[i].History)
+		}
+		// `signingKey` optional argument
+		if !querybuilder.IsZeroValue(opts[i].SigningKey) {
+			q = q.Arg("signingKey", opts[i].SigningKey)
+		}
+		// `signingKeyPassword` optional argument
+		if !querybuilder.IsZeroValue(opts[i].SigningKeyPassword) {
+			q = q.Arg("signingKeyPassword", opts[i].SigningKeyPassword)
+		}
+		// `sbom` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Sbom) {
+			q = q.Arg("sbom", opts[i].Sbom)
+		}
+		// `provenance` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Provenance) {
+			q = q.Arg("provenance", opts[i].Provenance)
+		}
 	}
 	q = q.Arg("address", address)
 
@@ -1061,6 +1658,28 @@ type ContainerUpOpts struct {
 	//
 	// This should only be used if the user requires that their exec process be the pid 1 process in the container. Otherwise it may result in unexpected behavior.
 	NoInit bool
+	// Number of seconds the service may run for before it's killed.
+	Timeout int
+	// Number of CPUs the service may use (e.g., 0.5).
+	CPULimit float64
+	// Memory in bytes the service may use before it's killed (e.g., 536870912 for 512 MiB).
+	MemoryLimit int
+	// Number of processes the service may have running at once.
+	PidsLimit int
+	// Command to run in the service container to check that it's healthy (e.g., ["pg_isready"]).
+	//
+	// It runs once the exposed ports are reachable, and the service is healthy once it exits successfully.
+	HealthcheckExec []string
+	// Number of seconds to wait between health check attempts.
+	//
+	// If unset, attempts back off exponentially.
+	HealthcheckInterval int
+	// Number of seconds each health check attempt may take.
+	HealthcheckTimeout int
+	// Number of times to retry a failing health check before the service fails to start.
+	//
+	// If unset, health checks are retried for up to 15 minutes.
+	HealthcheckRetries int
 }
 
 // Starts a Service and creates a tunnel that forwards traffic from the caller's network to that service.
@@ -1104,6 +1723,38 @@ func (r *Container) Up(ctx context.Context, opts ...ContainerUpOpts) error {
 		if !querybuilder.IsZeroValue(opts[i].NoInit) {
 			q = q.Arg("noInit", opts[i].NoInit)
 		}
+		// `timeout` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Timeout) {
+			q = q.Arg("timeout", opts[i].Timeout)
+		}
+		// `cpuLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].CPULimit) {
+			q = q.Arg("cpuLimit", opts[i].CPULimit)
+		}
+		// `memoryLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].MemoryLimit) {
+			q = q.Arg("memoryLimit", opts[i].MemoryLimit)
+		}
+		// `pidsLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].PidsLimit) {
+			q = q.Arg("pidsLimit", opts[i].PidsLimit)
+		}
+		// `healthcheckExec` optional argument
+		if !querybuilder.IsZeroValue(opts[i].HealthcheckExec) {
+			q = q.Arg("healthcheckExec", opts[i].HealthcheckExec)
+		}
+		// `healthcheckInterval` optional argument
+		if !querybuilder.IsZeroValue(opts[i].HealthcheckInterval) {
+			q = q.Arg("healthcheckInterval", opts[i].HealthcheckInterval)
+		}
+		// `healthcheckTimeout` optional argument
+		if !querybuilder.IsZeroValue(opts[i].HealthcheckTimeout) {
+			q = q.Arg("healthcheckTimeout", opts[i].HealthcheckTimeout)
+		}
+		// `healthcheckRetries` optional argument
+		if !querybuilder.IsZeroValue(opts[i].HealthcheckRetries) {
+			q = q.Arg("healthcheckRetries", opts[i].HealthcheckRetries)
+		}
 	}
 
 	return q.Execute(ctx)
@@ -1288,6 +1939,22 @@ type ContainerWithExecOpts struct {
 	//
 	// This should only be used if the user requires that their exec process be the pid 1 process in the container. Otherwise it may result in unexpected behavior.
 	NoInit bool
+	// Network access granted to the command.
+	//
+	// With SERVICES_ONLY, connections to anything but bound services and the engine's DNS server fail immediately.
+	NetworkMode NetworkMode
+	// Number of seconds the command may run for before it's killed.
+	//
+	// A command that times out fails with an ExecError whose reason is TIMEOUT.
+	Timeout int
+	// Number of CPUs the command may use (e.g., 0.5).
+	CPULimit float64
+	// Memory in bytes the command may use (e.g., 536870912 for 512 MiB).
+	//
+	// A command that exceeds it is killed and fails with an ExecError whose reason is OOM_KILLED.
+	MemoryLimit int
+	// Number of processes the command may have running at once.
+	PidsLimit int
 }
 
 // Retrieves this container after executing the specified command inside it.
@@ -1330,6 +1997,26 @@ func (r *Container) WithExec(args []string, opts ...ContainerWithExecOpts) *Cont
 		if !querybuilder.IsZeroValue(opts[i].NoInit) {
 			q = q.Arg("noInit", opts[i].NoInit)
 		}
+		// `networkMode` optional argument
+		if !querybuilder.IsZeroValue(opts[i].NetworkMode) {
+			q = q.Arg("networkMode", opts[i].NetworkMode)
+		}
+		// `timeout` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Timeout) {
+			q = q.Arg("timeout", opts[i].Timeout)
+		}
+		// `cpuLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].CPULimit) {
+			q = q.Arg("cpuLimit", opts[i].CPULimit)
+		}
+		// `memoryLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].MemoryLimit) {
+			q = q.Arg("memoryLimit", opts[i].MemoryLimit)
+		}
+		// `pidsLimit` optional argument
+		if !querybuilder.IsZeroValue(opts[i].PidsLimit) {
+			q = q.Arg("pidsLimit", opts[i].PidsLimit)
+		}
 	}
 	q = q.Arg("args", args)
 
@@ -1346,6 +2033,16 @@ type ContainerWithExposedPortOpts struct {
 	Description string
 	// Skip the health check when run as a service.
 	ExperimentalSkipHealthcheck bool
+	// Check the port's health with an HTTP GET request for this path, rather than just connecting to it (e.g., "/healthz").
+	HealthcheckPath string
+	// Scheme of the health check request. If unset, HTTP is used.
+	//
+	// Requires healthcheckPath.
+	HealthcheckScheme HealthcheckScheme
+	// Status code the health check request must respond with.
+	//
+	// If unset, any 2xx or 3xx status is accepted. Requires healthcheckPath.
+	HealthcheckStatus int
 }
 
 // Expose a network port.
@@ -1370,6 +2067,18 @@ func (r *Container) WithExposedPort(port int, opts ...ContainerWithExposedPortOp
 		if !querybuilder.IsZeroValue(opts[i].ExperimentalSkipHealthcheck) {
 			q = q.Arg("experimentalSkipHealthcheck", opts[i].ExperimentalSkipHealthcheck)
 		}
+		// `healthcheckPath` optional argument
+		if !querybuilder.IsZeroValue(opts[i].HealthcheckPath) {
+			q = q.Arg("healthcheckPath", opts[i].HealthcheckPath)
+		}
+		// `healthcheckScheme` optional argument
+		if !querybuilder.IsZeroValue(opts[i].HealthcheckScheme) {
+			q = q.Arg("healthcheckScheme", opts[i].HealthcheckScheme)
+		}
+		// `healthcheckStatus` optional argument
+		if !querybuilder.IsZeroValue(opts[i].HealthcheckStatus) {
+			q = q.Arg("healthcheckStatus", opts[i].HealthcheckStatus)
+		}
 	}
 	q = q.Arg("port", port)
 
@@ -2198,6 +2907,35 @@ func (r *Directory) WithGraphQLQuery(q *querybuilder.Selection) *Directory {
 	}
 }
 
+// DirectoryAsComposeOpts contains options for Directory.AsCompose
+type DirectoryAsComposeOpts struct {
+	// Path of the Compose file. If unset, the first of "compose.yaml", "compose.yml", "docker-compose.yaml" and "docker-compose.yml" that exists is used.
+	Path string
+	// Name of the project, which named volumes are scoped to. If unset, the name set in the Compose file is used, or "default".
+	ProjectName string
+}
+
+// Load a Compose project from a Compose file in this directory.
+//
+// Paths in the Compose file, such as build contexts and bind mounts, are resolved relative to the Compose file's directory. Variables are interpolated from a .env file next to the Compose file, if any.
+func (r *Directory) AsCompose(opts ...DirectoryAsComposeOpts) *ComposeProject {
+	q := r.query.Select("asCompose")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `path` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Path) {
+			q = q.Arg("path", opts[i].Path)
+		}
+		// `projectName` optional argument
+		if !querybuilder.IsZeroValue(opts[i].ProjectName) {
+			q = q.Arg("projectName", opts[i].ProjectName)
+		}
+	}
+
+	return &ComposeProject{
+		query: q,
+	}
+}
+
 // DirectoryAsModuleOpts contains options for Directory.AsModule
 type DirectoryAsModuleOpts struct {
 	// An optional subpath of the directory which contains the module's configuration file.
@@ -2229,6 +2967,17 @@ func (r *Directory) AsModule(opts ...DirectoryAsModuleOpts) *Module {
 	}
 }
 
+// Returns a unified diff, in the format of "git diff", of the files changed between this directory and another directory.
+func (r *Directory) AsPatch(other *Directory) *File {
+	assertNotNil("other", other)
+	q := r.query.Select("asPatch")
+	q = q.Arg("other", other)
+
+	return &File{
+		query: q,
+	}
+}
+
 // Gets the difference between this directory and an another directory.
 func (r *Directory) Diff(other *Directory) *Directory {
 	assertNotNil("other", other)
@@ -2277,6 +3026,18 @@ type DirectoryDockerBuildOpts struct {
 	//
 	// They will be mounted at /run/secrets/[secret-name].
 	Secrets []*Secret
+	// Named build contexts to pass to the build.
+	//
+	// They can be referenced by name in the Dockerfile (e.g., "COPY --from=<name>" or "FROM <name>").
+	BuildContexts []BuildContext
+	// A socket to forward to the build as the default SSH agent.
+	//
+	// It is available to "RUN --mount=type=ssh" instructions.
+	SSH *Socket
+	// Build stages to build without using the cache.
+	NoCacheFilter []string
+	// Registry references to import the build cache from (e.g., "registry.example.com/app:cache").
+	CacheFrom []string
 }
 
 // Builds a new Docker container from this directory.
@@ -2303,6 +3064,22 @@ func (r *Directory) DockerBuild(opts ...DirectoryDockerBuildOpts) *Container {
 		if !querybuilder.IsZeroValue(opts[i].Secrets) {
 			q = q.Arg("secrets", opts[i].Secrets)
 		}
+		// `buildContexts` optional argument
+		if !querybuilder.IsZeroValue(opts[i].BuildContexts) {
+			q = q.Arg("buildContexts", opts[i].BuildContexts)
+		}
+		// `ssh` optional argument
+		if !querybuilder.IsZeroValue(opts[i].SSH) {
+			q = q.Arg("ssh", opts[i].SSH)
+		}
+		// `noCacheFilter` optional argument
+		if !querybuilder.IsZeroValue(opts[i].NoCacheFilter) {
+			q = q.Arg("noCacheFilter", opts[i].NoCacheFilter)
+		}
+		// `cacheFrom` optional argument
+		if !querybuilder.IsZeroValue(opts[i].CacheFrom) {
+			q = q.Arg("cacheFrom", opts[i].CacheFrom)
+		}
 	}
 
 	return &Container{
@@ -2419,6 +3196,18 @@ func (r *Directory) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
+// Retrieves this directory with the contents of the given directories merged into it.
+//
+// Fails if the same path holds different contents in more than one of the directories, instead of overwriting it.
+func (r *Directory) Merge(directories []*Directory) *Directory {
+	q := r.query.Select("merge")
+	q = q.Arg("directories", directories)
+
+	return &Directory{
+		query: q,
+	}
+}
+
 // Force evaluation in the engine.
 func (r *Directory) Sync(ctx context.Context) (*Directory, error) {
 	q := r.query.Select("sync")
@@ -2595,6 +3384,19 @@ func (r *Directory) WithNewFile(path string, contents string, opts ...DirectoryW
 	}
 }
 
+// Retrieves this directory with the given unified diff applied.
+//
+// Fails if any hunk of the patch does not apply cleanly.
+func (r *Directory) WithPatch(patch *File) *Directory {
+	assertNotNil("patch", patch)
+	q := r.query.Select("withPatch")
+	q = q.Arg("patch", patch)
+
+	return &Directory{
+		query: q,
+	}
+}
+
 // Retrieves this directory with all file/dir timestamps set to the given time.
 func (r *Directory) WithTimestamps(timestamp int) *Directory {
 	q := r.query.Select("withTimestamps")
@@ -3577,6 +4379,29 @@ func (r *File) WithGraphQLQuery(q *querybuilder.Selection) *File {
 	}
 }
 
+// FileAsComposeOpts contains options for File.AsCompose
+type FileAsComposeOpts struct {
+	// Name of the project, which named volumes are scoped to. If unset, the name set in the Compose file is used, or "default".
+	ProjectName string
+}
+
+// Load a Compose project from this Compose file.
+//
+// Services that are built or that bind mount paths can only be loaded from a directory with Directory.asCompose.
+func (r *File) AsCompose(opts ...FileAsComposeOpts) *ComposeProject {
+	q := r.query.Select("asCompose")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `projectName` optional argument
+		if !querybuilder.IsZeroValue(opts[i].ProjectName) {
+			q = q.Arg("projectName", opts[i].ProjectName)
+		}
+	}
+
+	return &ComposeProject{
+		query: q,
+	}
+}
+
 // Retrieves the contents of the file.
 func (r *File) Contents(ctx context.Context) (string, error) {
 	if r.contents != nil {
@@ -4434,6 +5259,224 @@ func (r *GeneratedCode) WithVCSIgnoredPaths(paths []string) *GeneratedCode {
 	}
 }
 
+// The author or committer of a git commit.
+type GitActor struct {
+	query *querybuilder.Selection
+
+	date  *string
+	email *string
+	id    *GitActorID
+	name  *string
+}
+
+func (r *GitActor) WithGraphQLQuery(q *querybuilder.Selection) *GitActor {
+	return &GitActor{
+		query: q,
+	}
+}
+
+// The date of the action, in RFC 3339 format.
+func (r *GitActor) Date(ctx context.Context) (string, error) {
+	if r.date != nil {
+		return *r.date, nil
+	}
+	q := r.query.Select("date")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The email address of the person.
+func (r *GitActor) Email(ctx context.Context) (string, error) {
+	if r.email != nil {
+		return *r.email, nil
+	}
+	q := r.query.Select("email")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this GitActor.
+func (r *GitActor) ID(ctx context.Context) (GitActorID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response GitActorID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *GitActor) XXX_GraphQLType() string {
+	return "GitActor"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *GitActor) XXX_GraphQLIDType() string {
+	return "GitActorID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *GitActor) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *GitActor) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The name of the person.
+func (r *GitActor) Name(ctx context.Context) (string, error) {
+	if r.name != nil {
+		return *r.name, nil
+	}
+	q := r.query.Select("name")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The metadata of a git commit.
+type GitCommitInfo struct {
+	query *querybuilder.Selection
+
+	date    *string
+	id      *GitCommitInfoID
+	message *string
+	sha     *string
+}
+
+func (r *GitCommitInfo) WithGraphQLQuery(q *querybuilder.Selection) *GitCommitInfo {
+	return &GitCommitInfo{
+		query: q,
+	}
+}
+
+// The author of the commit.
+func (r *GitCommitInfo) Author() *GitActor {
+	q := r.query.Select("author")
+
+	return &GitActor{
+		query: q,
+	}
+}
+
+// The committer of the commit.
+func (r *GitCommitInfo) Committer() *GitActor {
+	q := r.query.Select("committer")
+
+	return &GitActor{
+		query: q,
+	}
+}
+
+// The date the commit was authored, in RFC 3339 format.
+func (r *GitCommitInfo) Date(ctx context.Context) (string, error) {
+	if r.date != nil {
+		return *r.date, nil
+	}
+	q := r.query.Select("date")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this GitCommitInfo.
+func (r *GitCommitInfo) ID(ctx context.Context) (GitCommitInfoID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response GitCommitInfoID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *GitCommitInfo) XXX_GraphQLType() string {
+	return "GitCommitInfo"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *GitCommitInfo) XXX_GraphQLIDType() string {
+	return "GitCommitInfoID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *GitCommitInfo) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *GitCommitInfo) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The full message of the commit.
+func (r *GitCommitInfo) Message(ctx context.Context) (string, error) {
+	if r.message != nil {
+		return *r.message, nil
+	}
+	q := r.query.Select("message")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The SHAs of the parents of the commit.
+func (r *GitCommitInfo) Parents(ctx context.Context) ([]string, error) {
+	q := r.query.Select("parents")
+
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The SHA of the commit.
+func (r *GitCommitInfo) Sha(ctx context.Context) (string, error) {
+	if r.sha != nil {
+		return *r.sha, nil
+	}
+	q := r.query.Select("sha")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // Module source originating from a git repo.
 type GitModuleSource struct {
 	query *querybuilder.Selection
@@ -4598,8 +5641,17 @@ func (r *GitModuleSource) Version(ctx context.Context) (string, error) {
 type GitRef struct {
 	query *querybuilder.Selection
 
-	commit *string
-	id     *GitRefID
+	commit   *string
+	describe *string
+	id       *GitRefID
+}
+type WithGitRefFunc func(r *GitRef) *GitRef
+
+// With calls the provided function with current GitRef.
+//
+// This is useful for reusability and readability by not breaking the calling chain.
+func (r *GitRef) With(f WithGitRefFunc) *GitRef {
+	return f(r)
 }
 
 func (r *GitRef) WithGraphQLQuery(q *querybuilder.Selection) *GitRef {
@@ -4621,6 +5673,30 @@ func (r *GitRef) Commit(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx)
 }
 
+// The metadata of the resolved commit at this ref.
+func (r *GitRef) CommitInfo() *GitCommitInfo {
+	q := r.query.Select("commitInfo")
+
+	return &GitCommitInfo{
+		query: q,
+	}
+}
+
+// The nearest tag reachable from this ref, as returned by "git describe --tags".
+//
+// If the tag doesn't point at the ref itself, it's suffixed with the number of commits since the tag and the abbreviated commit id (e.g., "v0.3.9-4-gb6315d8").
+func (r *GitRef) Describe(ctx context.Context) (string, error) {
+	if r.describe != nil {
+		return *r.describe, nil
+	}
+	q := r.query.Select("describe")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // A unique identifier for this GitRef.
 func (r *GitRef) ID(ctx context.Context) (GitRefID, error) {
 	if r.id != nil {
@@ -4661,10 +5737,29 @@ func (r *GitRef) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
+// The signature of this ref, if it was verified by "verify".
+func (r *GitRef) Signature() *GitSignature {
+	q := r.query.Select("signature")
+
+	return &GitSignature{
+		query: q,
+	}
+}
+
 // GitRefTreeOpts contains options for GitRef.Tree
 type GitRefTreeOpts struct {
 	// Set to true to discard .git directory.
 	DiscardGitDir bool
+	// Only check out the paths matching these patterns, which use the syntax of .gitignore files (e.g., ["/docs/", "*.md"]).
+	SparseCheckout []string
+	// The number of commits of history to fetch into the .git directory.
+	Depth int
+	// Set to true to fetch the full history and tags into the .git directory, e.g. for "git describe". Overrides depth.
+	FullHistory bool
+	// Set to true to not check out submodules. By default, submodules are checked out recursively.
+	SkipSubmodules bool
+	// Set to true to replace Git LFS pointer files with the content they point to. LFS objects are cached by the engine, so they're only downloaded once.
+	Lfs bool
 }
 
 // The filesystem tree at this ref.
@@ -4675,6 +5770,26 @@ func (r *GitRef) Tree(opts ...GitRefTreeOpts) *Directory {
 		if !querybuilder.IsZeroValue(opts[i].DiscardGitDir) {
 			q = q.Arg("discardGitDir", opts[i].DiscardGitDir)
 		}
+		// `sparseCheckout` optional argument
+		if !querybuilder.IsZeroValue(opts[i].SparseCheckout) {
+			q = q.Arg("sparseCheckout", opts[i].SparseCheckout)
+		}
+		// `depth` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Depth) {
+			q = q.Arg("depth", opts[i].Depth)
+		}
+		// `fullHistory` optional argument
+		if !querybuilder.IsZeroValue(opts[i].FullHistory) {
+			q = q.Arg("fullHistory", opts[i].FullHistory)
+		}
+		// `skipSubmodules` optional argument
+		if !querybuilder.IsZeroValue(opts[i].SkipSubmodules) {
+			q = q.Arg("skipSubmodules", opts[i].SkipSubmodules)
+		}
+		// `lfs` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Lfs) {
+			q = q.Arg("lfs", opts[i].Lfs)
+		}
 	}
 
 	return &Directory{
@@ -4682,6 +5797,35 @@ func (r *GitRef) Tree(opts ...GitRefTreeOpts) *Directory {
 	}
 }
 
+// GitRefVerifyOpts contains options for GitRef.Verify
+type GitRefVerifyOpts struct {
+	// GPG public keys trusted to sign the ref.
+	Keys []*File
+	// SSH allowed signers file trusted to sign the ref, in the format read by "ssh-keygen -Y verify".
+	AllowedSigners *File
+}
+
+// Verify the GPG or SSH signature of the annotated tag or commit at this ref, failing if it's missing or not made by one of the trusted keys.
+//
+// Returns this ref pinned to the verified commit.
+func (r *GitRef) Verify(opts ...GitRefVerifyOpts) *GitRef {
+	q := r.query.Select("verify")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `keys` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Keys) {
+			q = q.Arg("keys", opts[i].Keys)
+		}
+		// `allowedSigners` optional argument
+		if !querybuilder.IsZeroValue(opts[i].AllowedSigners) {
+			q = q.Arg("allowedSigners", opts[i].AllowedSigners)
+		}
+	}
+
+	return &GitRef{
+		query: q,
+	}
+}
+
 // A git repository.
 type GitRepository struct {
 	query *querybuilder.Selection
@@ -4772,6 +5916,63 @@ func (r *GitRepository) MarshalJSON() ([]byte, error) {
 	return json.Marshal(id)
 }
 
+// GitRepositoryLogOpts contains options for GitRepository.Log
+type GitRepositoryLogOpts struct {
+	// Exclude the commits reachable from this ref (e.g., "v0.3.8"). If empty, the whole history is returned.
+	From string
+	// Ref to list the history of (e.g., "main"). If empty, HEAD is used.
+	To string
+	// Only return commits that change one of these paths.
+	Paths []string
+}
+
+// Returns the commits reachable from a ref, newest first.
+func (r *GitRepository) Log(ctx context.Context, opts ...GitRepositoryLogOpts) ([]GitCommitInfo, error) {
+	q := r.query.Select("log")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `from` optional argument
+		if !querybuilder.IsZeroValue(opts[i].From) {
+			q = q.Arg("from", opts[i].From)
+		}
+		// `to` optional argument
+		if !querybuilder.IsZeroValue(opts[i].To) {
+			q = q.Arg("to", opts[i].To)
+		}
+		// `paths` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Paths) {
+			q = q.Arg("paths", opts[i].Paths)
+		}
+	}
+
+	q = q.Select("id")
+
+	type log struct {
+		Id GitCommitInfoID
+	}
+
+	convert := func(fields []log) []GitCommitInfo {
+		out := []GitCommitInfo{}
+
+		for i := range fields {
+			val := GitCommitInfo{id: &fields[i].Id}
+			val.query = q.Root().Select("loadGitCommitInfoFromID").Arg("id", fields[i].Id)
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []log
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
 // Returns details of a ref.
 func (r *GitRepository) Ref(name string) *GitRef {
 	q := r.query.Select("ref")
@@ -4787,53 +5988,148 @@ func (r *GitRepository) Tag(name string) *GitRef {
 	q := r.query.Select("tag")
 	q = q.Arg("name", name)
 
-	return &GitRef{
-		query: q,
+	return &GitRef{
+		query: q,
+	}
+}
+
+// GitRepositoryTagsOpts contains options for GitRepository.Tags
+type GitRepositoryTagsOpts struct {
+	// Glob patterns (e.g., "refs/tags/v*").
+	Patterns []string
+}
+
+// tags that match any of the given glob patterns.
+func (r *GitRepository) Tags(ctx context.Context, opts ...GitRepositoryTagsOpts) ([]string, error) {
+	q := r.query.Select("tags")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `patterns` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Patterns) {
+			q = q.Arg("patterns", opts[i].Patterns)
+		}
+	}
+
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// Header to authenticate the remote with.
+func (r *GitRepository) WithAuthHeader(header *Secret) *GitRepository {
+	assertNotNil("header", header)
+	q := r.query.Select("withAuthHeader")
+	q = q.Arg("header", header)
+
+	return &GitRepository{
+		query: q,
+	}
+}
+
+// Token to authenticate the remote with.
+func (r *GitRepository) WithAuthToken(token *Secret) *GitRepository {
+	assertNotNil("token", token)
+	q := r.query.Select("withAuthToken")
+	q = q.Arg("token", token)
+
+	return &GitRepository{
+		query: q,
+	}
+}
+
+// A verified signature of a git tag or commit.
+type GitSignature struct {
+	query *querybuilder.Selection
+
+	format *string
+	id     *GitSignatureID
+	key    *string
+	signer *string
+}
+
+func (r *GitSignature) WithGraphQLQuery(q *querybuilder.Selection) *GitSignature {
+	return &GitSignature{
+		query: q,
+	}
+}
+
+// The format of the signature, "gpg" or "ssh".
+func (r *GitSignature) Format(ctx context.Context) (string, error) {
+	if r.format != nil {
+		return *r.format, nil
+	}
+	q := r.query.Select("format")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this GitSignature.
+func (r *GitSignature) ID(ctx context.Context) (GitSignatureID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response GitSignatureID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *GitSignature) XXX_GraphQLType() string {
+	return "GitSignature"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *GitSignature) XXX_GraphQLIDType() string {
+	return "GitSignatureID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *GitSignature) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
 	}
+	return string(id), nil
 }
 
-// GitRepositoryTagsOpts contains options for GitRepository.Tags
-type GitRepositoryTagsOpts struct {
-	// Glob patterns (e.g., "refs/tags/v*").
-	Patterns []string
+func (r *GitSignature) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
 }
 
-// tags that match any of the given glob patterns.
-func (r *GitRepository) Tags(ctx context.Context, opts ...GitRepositoryTagsOpts) ([]string, error) {
-	q := r.query.Select("tags")
-	for i := len(opts) - 1; i >= 0; i-- {
-		// `patterns` optional argument
-		if !querybuilder.IsZeroValue(opts[i].Patterns) {
-			q = q.Arg("patterns", opts[i].Patterns)
-		}
+// The fingerprint of the signing key.
+func (r *GitSignature) Key(ctx context.Context) (string, error) {
+	if r.key != nil {
+		return *r.key, nil
 	}
+	q := r.query.Select("key")
 
-	var response []string
+	var response string
 
 	q = q.Bind(&response)
 	return response, q.Execute(ctx)
 }
 
-// Header to authenticate the remote with.
-func (r *GitRepository) WithAuthHeader(header *Secret) *GitRepository {
-	assertNotNil("header", header)
-	q := r.query.Select("withAuthHeader")
-	q = q.Arg("header", header)
-
-	return &GitRepository{
-		query: q,
+// The identity of the signer: the user ID of the GPG key, or the principal of the SSH key in the allowed signers.
+func (r *GitSignature) Signer(ctx context.Context) (string, error) {
+	if r.signer != nil {
+		return *r.signer, nil
 	}
-}
+	q := r.query.Select("signer")
 
-// Token to authenticate the remote with.
-func (r *GitRepository) WithAuthToken(token *Secret) *GitRepository {
-	assertNotNil("token", token)
-	q := r.query.Select("withAuthToken")
-	q = q.Arg("token", token)
+	var response string
 
-	return &GitRepository{
-		query: q,
-	}
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
 }
 
 // Information about the host environment.
@@ -5009,6 +6305,187 @@ func (r *Host) UnixSocket(path string) *Socket {
 	}
 }
 
+// The config of an image in a registry.
+type ImageConfig struct {
+	query *querybuilder.Selection
+
+	id       *ImageConfigID
+	platform *Platform
+	user     *string
+	workdir  *string
+}
+
+func (r *ImageConfig) WithGraphQLQuery(q *querybuilder.Selection) *ImageConfig {
+	return &ImageConfig{
+		query: q,
+	}
+}
+
+// The default arguments of the image's entrypoint.
+func (r *ImageConfig) DefaultArgs(ctx context.Context) ([]string, error) {
+	q := r.query.Select("defaultArgs")
+
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The entrypoint of the image.
+func (r *ImageConfig) Entrypoint(ctx context.Context) ([]string, error) {
+	q := r.query.Select("entrypoint")
+
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The environment variables of the image.
+func (r *ImageConfig) EnvVariables(ctx context.Context) ([]EnvVariable, error) {
+	q := r.query.Select("envVariables")
+
+	q = q.Select("id")
+
+	type envVariables struct {
+		Id EnvVariableID
+	}
+
+	convert := func(fields []envVariables) []EnvVariable {
+		out := []EnvVariable{}
+
+		for i := range fields {
+			val := EnvVariable{id: &fields[i].Id}
+			val.query = q.Root().Select("loadEnvVariableFromID").Arg("id", fields[i].Id)
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []envVariables
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
+// A unique identifier for this ImageConfig.
+func (r *ImageConfig) ID(ctx context.Context) (ImageConfigID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response ImageConfigID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *ImageConfig) XXX_GraphQLType() string {
+	return "ImageConfig"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *ImageConfig) XXX_GraphQLIDType() string {
+	return "ImageConfigID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *ImageConfig) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *ImageConfig) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// The labels of the image, sorted by name.
+func (r *ImageConfig) Labels(ctx context.Context) ([]Label, error) {
+	q := r.query.Select("labels")
+
+	q = q.Select("id")
+
+	type labels struct {
+		Id LabelID
+	}
+
+	convert := func(fields []labels) []Label {
+		out := []Label{}
+
+		for i := range fields {
+			val := Label{id: &fields[i].Id}
+			val.query = q.Root().Select("loadLabelFromID").Arg("id", fields[i].Id)
+			out = append(out, val)
+		}
+
+		return out
+	}
+	var response []labels
+
+	q = q.Bind(&response)
+
+	err := q.Execute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return convert(response), nil
+}
+
+// The platform of the image.
+func (r *ImageConfig) Platform(ctx context.Context) (Platform, error) {
+	if r.platform != nil {
+		return *r.platform, nil
+	}
+	q := r.query.Select("platform")
+
+	var response Platform
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The user of the image.
+func (r *ImageConfig) User(ctx context.Context) (string, error) {
+	if r.user != nil {
+		return *r.user, nil
+	}
+	q := r.query.Select("user")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The working directory of the image.
+func (r *ImageConfig) Workdir(ctx context.Context) (string, error) {
+	if r.workdir != nil {
+		return *r.workdir, nil
+	}
+	q := r.query.Select("workdir")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // A graphql input type, which is essentially just a group of named args.
 // This is currently only used to represent pre-existing usage of graphql input types
 // in the core API. It is not used by user modules and shouldn't ever be as user
@@ -6948,6 +8425,32 @@ func (r *Client) Error(message string) *Error {
 	}
 }
 
+// ExplainCacheMissOpts contains options for Client.ExplainCacheMiss
+type ExplainCacheMissOpts struct {
+	// Digest of the call to compare to.
+	//
+	// Defaults to the most recent call of the same fields in an earlier run.
+	PreviousDigest string
+}
+
+// Explains why a call missed the cache, by comparing it to the same call in a previous run.
+//
+// Calls are looked up in the telemetry the engine keeps of its recent clients. The first difference is reported, in the order the calls are evaluated: the objects that calls are chained from first, then arguments, then content.
+func (r *Client) ExplainCacheMiss(digest string, opts ...ExplainCacheMissOpts) *CacheMissExplanation {
+	q := r.query.Select("explainCacheMiss")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `previousDigest` optional argument
+		if !querybuilder.IsZeroValue(opts[i].PreviousDigest) {
+			q = q.Arg("previousDigest", opts[i].PreviousDigest)
+		}
+	}
+	q = q.Arg("digest", digest)
+
+	return &CacheMissExplanation{
+		query: q,
+	}
+}
+
 // Creates a function.
 func (r *Client) Function(name string, returnType *TypeDef) *Function {
 	assertNotNil("returnType", returnType)
@@ -7024,6 +8527,18 @@ func (r *Client) Host() *Host {
 type HTTPOpts struct {
 	// A service which must be started before the URL is fetched.
 	ExperimentalServiceHost *Service
+	// Expected digest of the content (e.g., "sha256:..."). The fetch fails if the content doesn't match, and is cached by the digest without contacting the server.
+	Checksum string
+	// Headers to send with the request.
+	Headers []HTTPHeader
+	// Secret sent as a bearer token in the Authorization header.
+	AuthToken *Secret
+	// Secret sent as the value of the Authorization header (e.g., "Basic <base64 of user:password>"). Takes precedence over authToken.
+	AuthHeader *Secret
+	// Name of the file. Defaults to a digest of the URL.
+	Name string
+	// Permissions of the file (e.g., 0755). Defaults to 0600.
+	Permissions int
 }
 
 // Returns a file containing an http remote url content.
@@ -7034,6 +8549,30 @@ func (r *Client) HTTP(url string, opts ...HTTPOpts) *File {
 		if !querybuilder.IsZeroValue(opts[i].ExperimentalServiceHost) {
 			q = q.Arg("experimentalServiceHost", opts[i].ExperimentalServiceHost)
 		}
+		// `checksum` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Checksum) {
+			q = q.Arg("checksum", opts[i].Checksum)
+		}
+		// `headers` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Headers) {
+			q = q.Arg("headers", opts[i].Headers)
+		}
+		// `authToken` optional argument
+		if !querybuilder.IsZeroValue(opts[i].AuthToken) {
+			q = q.Arg("authToken", opts[i].AuthToken)
+		}
+		// `authHeader` optional argument
+		if !querybuilder.IsZeroValue(opts[i].AuthHeader) {
+			q = q.Arg("authHeader", opts[i].AuthHeader)
+		}
+		// `name` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Name) {
+			q = q.Arg("name", opts[i].Name)
+		}
+		// `permissions` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Permissions) {
+			q = q.Arg("permissions", opts[i].Permissions)
+		}
 	}
 	q = q.Arg("url", url)
 
@@ -7042,6 +8581,16 @@ func (r *Client) HTTP(url string, opts ...HTTPOpts) *File {
 	}
 }
 
+// Load a CacheMissExplanation from its ID.
+func (r *Client) LoadCacheMissExplanationFromID(id CacheMissExplanationID) *CacheMissExplanation {
+	q := r.query.Select("loadCacheMissExplanationFromID")
+	q = q.Arg("id", id)
+
+	return &CacheMissExplanation{
+		query: q,
+	}
+}
+
 // Load a CacheVolume from its ID.
 func (r *Client) LoadCacheVolumeFromID(id CacheVolumeID) *CacheVolume {
 	q := r.query.Select("loadCacheVolumeFromID")
@@ -7052,6 +8601,16 @@ func (r *Client) LoadCacheVolumeFromID(id CacheVolumeID) *CacheVolume {
 	}
 }
 
+// Load a ComposeProject from its ID.
+func (r *Client) LoadComposeProjectFromID(id ComposeProjectID) *ComposeProject {
+	q := r.query.Select("loadComposeProjectFromID")
+	q = q.Arg("id", id)
+
+	return &ComposeProject{
+		query: q,
+	}
+}
+
 // Load a Container from its ID.
 func (r *Client) LoadContainerFromID(id ContainerID) *Container {
 	q := r.query.Select("loadContainerFromID")
@@ -7217,17 +8776,37 @@ func (r *Client) LoadFunctionFromID(id FunctionID) *Function {
 	q := r.query.Select("loadFunctionFromID")
 	q = q.Arg("id", id)
 
-	return &Function{
+	return &Function{
+		query: q,
+	}
+}
+
+// Load a GeneratedCode from its ID.
+func (r *Client) LoadGeneratedCodeFromID(id GeneratedCodeID) *GeneratedCode {
+	q := r.query.Select("loadGeneratedCodeFromID")
+	q = q.Arg("id", id)
+
+	return &GeneratedCode{
+		query: q,
+	}
+}
+
+// Load a GitActor from its ID.
+func (r *Client) LoadGitActorFromID(id GitActorID) *GitActor {
+	q := r.query.Select("loadGitActorFromID")
+	q = q.Arg("id", id)
+
+	return &GitActor{
 		query: q,
 	}
 }
 
-// Load a GeneratedCode from its ID.
-func (r *Client) LoadGeneratedCodeFromID(id GeneratedCodeID) *GeneratedCode {
-	q := r.query.Select("loadGeneratedCodeFromID")
+// Load a GitCommitInfo from its ID.
+func (r *Client) LoadGitCommitInfoFromID(id GitCommitInfoID) *GitCommitInfo {
+	q := r.query.Select("loadGitCommitInfoFromID")
 	q = q.Arg("id", id)
 
-	return &GeneratedCode{
+	return &GitCommitInfo{
 		query: q,
 	}
 }
@@ -7262,6 +8841,16 @@ func (r *Client) LoadGitRepositoryFromID(id GitRepositoryID) *GitRepository {
 	}
 }
 
+// Load a GitSignature from its ID.
+func (r *Client) LoadGitSignatureFromID(id GitSignatureID) *GitSignature {
+	q := r.query.Select("loadGitSignatureFromID")
+	q = q.Arg("id", id)
+
+	return &GitSignature{
+		query: q,
+	}
+}
+
 // Load a Host from its ID.
 func (r *Client) LoadHostFromID(id HostID) *Host {
 	q := r.query.Select("loadHostFromID")
@@ -7272,6 +8861,16 @@ func (r *Client) LoadHostFromID(id HostID) *Host {
 	}
 }
 
+// Load a ImageConfig from its ID.
+func (r *Client) LoadImageConfigFromID(id ImageConfigID) *ImageConfig {
+	q := r.query.Select("loadImageConfigFromID")
+	q = q.Arg("id", id)
+
+	return &ImageConfig{
+		query: q,
+	}
+}
+
 // Load a InputTypeDef from its ID.
 func (r *Client) LoadInputTypeDefFromID(id InputTypeDefID) *InputTypeDef {
 	q := r.query.Select("loadInputTypeDefFromID")
@@ -7382,6 +8981,16 @@ func (r *Client) LoadPortFromID(id PortID) *Port {
 	}
 }
 
+// Load a Registry from its ID.
+func (r *Client) LoadRegistryFromID(id RegistryID) *Registry {
+	q := r.query.Select("loadRegistryFromID")
+	q = q.Arg("id", id)
+
+	return &Registry{
+		query: q,
+	}
+}
+
 // Load a SDKConfig from its ID.
 func (r *Client) LoadSDKConfigFromID(id SDKConfigID) *SDKConfig {
 	q := r.query.Select("loadSDKConfigFromID")
@@ -7549,6 +9158,18 @@ func (r *Client) ModuleSource(refString string, opts ...ModuleSourceOpts) *Modul
 	}
 }
 
+// Inspects an image in a registry, without pulling its layers.
+//
+// Credentials set with Container.withRegistryAuth and the client's credentials are used to authenticate to the registry.
+func (r *Client) Registry(address string) *Registry {
+	q := r.query.Select("registry")
+	q = q.Arg("address", address)
+
+	return &Registry{
+		query: q,
+	}
+}
+
 // Creates a new secret.
 func (r *Client) Secret(uri string) *Secret {
 	q := r.query.Select("secret")
@@ -7603,6 +9224,156 @@ func (r *Client) Version(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx)
 }
 
+// An image in a registry, inspected without pulling its layers.
+type Registry struct {
+	query *querybuilder.Selection
+
+	address        *string
+	digest         *string
+	id             *RegistryID
+	manifestDigest *string
+}
+
+func (r *Registry) WithGraphQLQuery(q *querybuilder.Selection) *Registry {
+	return &Registry{
+		query: q,
+	}
+}
+
+// The normalized reference of the image (e.g., "docker.io/library/alpine:latest").
+func (r *Registry) Address(ctx context.Context) (string, error) {
+	if r.address != nil {
+		return *r.address, nil
+	}
+	q := r.query.Select("address")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// RegistryConfigOpts contains options for Registry.Config
+type RegistryConfigOpts struct {
+	// Platform of the image. Defaults to the platform of the engine.
+	Platform Platform
+}
+
+// The config of the image for a platform.
+func (r *Registry) Config(opts ...RegistryConfigOpts) *ImageConfig {
+	q := r.query.Select("config")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `platform` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Platform) {
+			q = q.Arg("platform", opts[i].Platform)
+		}
+	}
+
+	return &ImageConfig{
+		query: q,
+	}
+}
+
+// The digest of the image's manifest or index.
+func (r *Registry) Digest(ctx context.Context) (string, error) {
+	if r.digest != nil {
+		return *r.digest, nil
+	}
+	q := r.query.Select("digest")
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// A unique identifier for this Registry.
+func (r *Registry) ID(ctx context.Context) (RegistryID, error) {
+	if r.id != nil {
+		return *r.id, nil
+	}
+	q := r.query.Select("id")
+
+	var response RegistryID
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// XXX_GraphQLType is an internal function. It returns the native GraphQL type name
+func (r *Registry) XXX_GraphQLType() string {
+	return "Registry"
+}
+
+// XXX_GraphQLIDType is an internal function. It returns the native GraphQL type name for the ID of this object
+func (r *Registry) XXX_GraphQLIDType() string {
+	return "RegistryID"
+}
+
+// XXX_GraphQLID is an internal function. It returns the underlying type ID
+func (r *Registry) XXX_GraphQLID(ctx context.Context) (string, error) {
+	id, err := r.ID(ctx)
+	if err != nil {
+		return "", err
+	}
+	return string(id), nil
+}
+
+func (r *Registry) MarshalJSON() ([]byte, error) {
+	id, err := r.ID(marshalCtx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(id)
+}
+
+// RegistryManifestDigestOpts contains options for Registry.ManifestDigest
+type RegistryManifestDigestOpts struct {
+	// Platform of the manifest. Defaults to the platform of the engine.
+	Platform Platform
+}
+
+// The digest of the image's manifest for a platform.
+func (r *Registry) ManifestDigest(ctx context.Context, opts ...RegistryManifestDigestOpts) (string, error) {
+	if r.manifestDigest != nil {
+		return *r.manifestDigest, nil
+	}
+	q := r.query.Select("manifestDigest")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `platform` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Platform) {
+			q = q.Arg("platform", opts[i].Platform)
+		}
+	}
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The platforms of the image, in the order of its index.
+//
+// Attestations and signatures are skipped.
+func (r *Registry) Platforms(ctx context.Context) ([]Platform, error) {
+	q := r.query.Select("platforms")
+
+	var response []Platform
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// The tags of the image's repository.
+func (r *Registry) Tags(ctx context.Context) ([]string, error) {
+	q := r.query.Select("tags")
+
+	var response []string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // The SDK config of the module.
 type SDKConfig struct {
 	query *querybuilder.Selection
@@ -7772,6 +9543,7 @@ type Secret struct {
 	id        *SecretID
 	name      *string
 	plaintext *string
+	refresh   *Void
 	uri       *string
 }
 
@@ -7847,6 +9619,18 @@ func (r *Secret) Plaintext(ctx context.Context) (string, error) {
 	return response, q.Execute(ctx)
 }
 
+// Resolves this secret again from its provider, replacing the value the client cached for the session, e.g. after it was rotated.
+//
+// Secrets set from a plaintext have nothing to refresh.
+func (r *Secret) Refresh(ctx context.Context) error {
+	if r.refresh != nil {
+		return nil
+	}
+	q := r.query.Select("refresh")
+
+	return q.Execute(ctx)
+}
+
 // The URI of this secret.
 func (r *Secret) URI(ctx context.Context) (string, error) {
 	if r.uri != nil {
@@ -7865,9 +9649,12 @@ type Service struct {
 	query *querybuilder.Selection
 
 	endpoint *string
+	exitCode *int
 	hostname *string
 	id       *ServiceID
 	start    *ServiceID
+	stderr   *string
+	stdout   *string
 	stop     *ServiceID
 	up       *Void
 }
@@ -7921,6 +9708,21 @@ func (r *Service) Endpoint(ctx context.Context, opts ...ServiceEndpointOpts) (st
 	return response, q.Execute(ctx)
 }
 
+// The exit code the service last exited with, or null if it hasn't exited yet.
+//
+// If the service has been restarted, this is the exit code of the run before the current one until that one exits too. The service must have been started within the session.
+func (r *Service) ExitCode(ctx context.Context) (int, error) {
+	if r.exitCode != nil {
+		return *r.exitCode, nil
+	}
+	q := r.query.Select("exitCode")
+
+	var response int
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // Retrieves a hostname which can be used by clients to reach this container.
 func (r *Service) Hostname(ctx context.Context) (string, error) {
 	if r.hostname != nil {
@@ -8022,6 +9824,72 @@ func (r *Service) Start(ctx context.Context) (*Service, error) {
 	}, nil
 }
 
+// ServiceStderrOpts contains options for Service.Stderr
+type ServiceStderrOpts struct {
+	// Only return this many lines from the end of the output.
+	Tail int
+	// Only return output written since this point in time, either as a duration (e.g., "10m") or as an RFC 3339 timestamp.
+	Since string
+}
+
+// The output the service has written to stderr.
+//
+// The service must have been started within the session. Its output remains available once it has stopped, and includes the output of every run if it has restarted.
+func (r *Service) Stderr(ctx context.Context, opts ...ServiceStderrOpts) (string, error) {
+	if r.stderr != nil {
+		return *r.stderr, nil
+	}
+	q := r.query.Select("stderr")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `tail` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Tail) {
+			q = q.Arg("tail", opts[i].Tail)
+		}
+		// `since` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Since) {
+			q = q.Arg("since", opts[i].Since)
+		}
+	}
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
+// ServiceStdoutOpts contains options for Service.Stdout
+type ServiceStdoutOpts struct {
+	// Only return this many lines from the end of the output.
+	Tail int
+	// Only return output written since this point in time, either as a duration (e.g., "10m") or as an RFC 3339 timestamp.
+	Since string
+}
+
+// The output the service has written to stdout.
+//
+// The service must have been started within the session. Its output remains available once it has stopped, and includes the output of every run if it has restarted.
+func (r *Service) Stdout(ctx context.Context, opts ...ServiceStdoutOpts) (string, error) {
+	if r.stdout != nil {
+		return *r.stdout, nil
+	}
+	q := r.query.Select("stdout")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `tail` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Tail) {
+			q = q.Arg("tail", opts[i].Tail)
+		}
+		// `since` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Since) {
+			q = q.Arg("since", opts[i].Since)
+		}
+	}
+
+	var response string
+
+	q = q.Bind(&response)
+	return response, q.Execute(ctx)
+}
+
 // ServiceStopOpts contains options for Service.Stop
 type ServiceStopOpts struct {
 	// Immediately kill the service without waiting for a graceful exit
@@ -8087,6 +9955,59 @@ func (r *Service) WithHostname(hostname string) *Service {
 	}
 }
 
+// ServiceWithLivenessProbeOpts contains options for Service.WithLivenessProbe
+type ServiceWithLivenessProbeOpts struct {
+	// Number of seconds between each health check.
+	Interval int
+	// Number of consecutive failed health checks after which the service is killed.
+	FailureThreshold int
+}
+
+// Keeps running the service's health checks once it has started.
+//
+// The service is killed once its health checks fail enough times in a row, and restarted if its restart policy allows it.
+func (r *Service) WithLivenessProbe(opts ...ServiceWithLivenessProbeOpts) *Service {
+	q := r.query.Select("withLivenessProbe")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `interval` optional argument
+		if !querybuilder.IsZeroValue(opts[i].Interval) {
+			q = q.Arg("interval", opts[i].Interval)
+		}
+		// `failureThreshold` optional argument
+		if !querybuilder.IsZeroValue(opts[i].FailureThreshold) {
+			q = q.Arg("failureThreshold", opts[i].FailureThreshold)
+		}
+	}
+
+	return &Service{
+		query: q,
+	}
+}
+
+// ServiceWithRestartPolicyOpts contains options for Service.WithRestartPolicy
+type ServiceWithRestartPolicyOpts struct {
+	// Maximum number of times to restart the service. If unset, there is no limit.
+	MaxRetries int
+}
+
+// Configures whether the service is restarted when it exits.
+//
+// The service keeps the same hostname across restarts, so containers bound to it can keep reaching it.
+func (r *Service) WithRestartPolicy(policy ServiceRestartPolicy, opts ...ServiceWithRestartPolicyOpts) *Service {
+	q := r.query.Select("withRestartPolicy")
+	for i := len(opts) - 1; i >= 0; i-- {
+		// `maxRetries` optional argument
+		if !querybuilder.IsZeroValue(opts[i].MaxRetries) {
+			q = q.Arg("maxRetries", opts[i].MaxRetries)
+		}
+	}
+	q = q.Arg("policy", policy)
+
+	return &Service{
+		query: q,
+	}
+}
+
 // A Unix or TCP/IP socket that can be mounted into a container.
 type Socket struct {
 	query *querybuilder.Selection
@@ -8679,6 +10600,28 @@ func (r *TypeDef) WithScalar(name string, opts ...TypeDefWithScalarOpts) *TypeDe
 	}
 }
 
+// What differs between a call and the same call in a previous run.
+type CacheMissKind string
+
+func (CacheMissKind) IsEnum() {}
+
+const (
+	// An argument was added, removed or changed.
+	CacheMissKindArgument CacheMissKind = "ARGUMENT"
+
+	// A call selects a different field, view or module.
+	CacheMissKindCall CacheMissKind = "CALL"
+
+	// A call with the same arguments has different content, e.g. the files of a host directory.
+	CacheMissKindContent CacheMissKind = "CONTENT"
+
+	// The calls are the same, so the previous result was pruned from the cache or was never cached.
+	CacheMissKindNone CacheMissKind = "NONE"
+
+	// A call is chained from an object in only one of the runs.
+	CacheMissKindReceiver CacheMissKind = "RECEIVER"
+)
+
 // Sharing mode of the cache volume.
 type CacheSharingMode string
 
@@ -8695,6 +10638,30 @@ const (
 	CacheSharingModeShared CacheSharingMode = "SHARED"
 )
 
+// Scheme of the HTTP request used to check a port's health.
+type HealthcheckScheme string
+
+func (HealthcheckScheme) IsEnum() {}
+
+const (
+	HealthcheckSchemeHttp HealthcheckScheme = "HTTP"
+
+	HealthcheckSchemeHttps HealthcheckScheme = "HTTPS"
+)
+
+// How to describe the layers of an exported image in its history.
+type ImageHistory string
+
+func (ImageHistory) IsEnum() {}
+
+const (
+	// Describe each layer by the API field that created it, e.g. "withExec", with the digest of its call as comment.
+	ImageHistoryFields ImageHistory = "FIELDS"
+
+	// Describe each layer by the operation that created it.
+	ImageHistoryOperations ImageHistory = "OPERATIONS"
+)
+
 // Compression algorithm to use for image layers.
 type ImageLayerCompression string
 
@@ -8710,6 +10677,22 @@ const (
 	ImageLayerCompressionZstd ImageLayerCompression = "Zstd"
 )
 
+// How to squash the layers of an exported image.
+type ImageLayerSquash string
+
+func (ImageLayerSquash) IsEnum() {}
+
+const (
+	// Keep the layers of the image the container was created from, and squash the layers above them into one.
+	ImageLayerSquashAboveBase ImageLayerSquash = "ABOVE_BASE"
+
+	// Squash all layers into one.
+	ImageLayerSquashAll ImageLayerSquash = "ALL"
+
+	// Keep one layer per operation.
+	ImageLayerSquashNone ImageLayerSquash = "NONE"
+)
+
 // Mediatypes to use in published or exported image metadata.
 type ImageMediaTypes string
 
@@ -8732,6 +10715,22 @@ const (
 	ModuleSourceKindLocalSource ModuleSourceKind = "LOCAL_SOURCE"
 )
 
+// Network access granted to an executed command.
+type NetworkMode string
+
+func (NetworkMode) IsEnum() {}
+
+const (
+	// Full network access, including services and the internet.
+	NetworkModeDefault NetworkMode = "DEFAULT"
+
+	// No network access beyond the loopback interface.
+	NetworkModeNone NetworkMode = "NONE"
+
+	// Only bound services and the engine's DNS server are reachable.
+	NetworkModeServicesOnly NetworkMode = "SERVICES_ONLY"
+)
+
 // Transport layer network protocol associated to a port.
 type NetworkProtocol string
 
@@ -8759,6 +10758,22 @@ const (
 	ReturnTypeSuccess ReturnType = "SUCCESS"
 )
 
+// When to restart a service that has exited.
+type ServiceRestartPolicy string
+
+func (ServiceRestartPolicy) IsEnum() {}
+
+const (
+	// Restart the service whenever it exits, unless it was stopped.
+	ServiceRestartPolicyAlways ServiceRestartPolicy = "ALWAYS"
+
+	// Never restart the service.
+	ServiceRestartPolicyNever ServiceRestartPolicy = "NEVER"
+
+	// Restart the service when it exits with an error or fails its liveness checks.
+	ServiceRestartPolicyOnFailure ServiceRestartPolicy = "ON_FAILURE"
+)
+
 // Distinguishes the different kinds of TypeDefs.
 type TypeDefKind string
 
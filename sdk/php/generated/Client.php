@@ -116,6 +116,21 @@ class Client extends Client\AbstractClient
         return new \Dagger\Error($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
+    /**
+     * Explains why a call missed the cache, by comparing it to the same call in a previous run.
+     *
+     * Calls are looked up in the telemetry the engine keeps of its recent clients. The first difference is reported, in the order the calls are evaluated: the objects that calls are chained from first, then arguments, then content.
+     */
+    public function explainCacheMiss(string $digest, ?string $previousDigest = ''): CacheMissExplanation
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('explainCacheMiss');
+        $innerQueryBuilder->setArgument('digest', $digest);
+        if (null !== $previousDigest) {
+        $innerQueryBuilder->setArgument('previousDigest', $previousDigest);
+        }
+        return new \Dagger\CacheMissExplanation($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
     /**
      * Creates a function.
      */
@@ -176,16 +191,53 @@ class Client extends Client\AbstractClient
     /**
      * Returns a file containing an http remote url content.
      */
-    public function http(string $url, ServiceId|Service|null $experimentalServiceHost = null): File
-    {
+    public function http(
+        string $url,
+        ServiceId|Service|null $experimentalServiceHost = null,
+        ?string $checksum = null,
+        ?array $headers = null,
+        SecretId|Secret|null $authToken = null,
+        SecretId|Secret|null $authHeader = null,
+        ?string $name = null,
+        ?int $permissions = null,
+    ): File {
         $innerQueryBuilder = new \Dagger\Client\QueryBuilder('http');
         $innerQueryBuilder->setArgument('url', $url);
         if (null !== $experimentalServiceHost) {
         $innerQueryBuilder->setArgument('experimentalServiceHost', $experimentalServiceHost);
         }
+        if (null !== $checksum) {
+        $innerQueryBuilder->setArgument('checksum', $checksum);
+        }
+        if (null !== $headers) {
+        $innerQueryBuilder->setArgument('headers', $headers);
+        }
+        if (null !== $authToken) {
+        $innerQueryBuilder->setArgument('authToken', $authToken);
+        }
+        if (null !== $authHeader) {
+        $innerQueryBuilder->setArgument('authHeader', $authHeader);
+        }
+        if (null !== $name) {
+        $innerQueryBuilder->setArgument('name', $name);
+        }
+        if (null !== $permissions) {
+        $innerQueryBuilder->setArgument('permissions', $permissions);
+        }
         return new \Dagger\File($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
+    /**
+     * Load a CacheMissExplanation from its ID.
+     */
+    public function loadCacheMissExplanationFromID(
+        CacheMissExplanationId|CacheMissExplanation $id,
+    ): CacheMissExplanation {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('loadCacheMissExplanationFromID');
+        $innerQueryBuilder->setArgument('id', $id);
+        return new \Dagger\CacheMissExplanation($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
     /**
      * Load a CacheVolume from its ID.
      */
@@ -196,6 +248,16 @@ class Client extends Client\AbstractClient
         return new \Dagger\CacheVolume($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
+    /**
+     * Load a ComposeProject from its ID.
+     */
+    public function loadComposeProjectFromID(ComposeProjectId|ComposeProject $id): ComposeProject
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('loadComposeProjectFromID');
+        $innerQueryBuilder->setArgument('id', $id);
+        return new \Dagger\ComposeProject($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
     /**
      * Load a Container from its ID.
      */
@@ -377,6 +439,26 @@ class Client extends Client\AbstractClient
         return new \Dagger\GeneratedCode($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
+    /**
+     * Load a GitActor from its ID.
+     */
+    public function loadGitActorFromID(GitActorId|GitActor $id): GitActor
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('loadGitActorFromID');
+        $innerQueryBuilder->setArgument('id', $id);
+        return new \Dagger\GitActor($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
+    /**
+     * Load a GitCommitInfo from its ID.
+     */
+    public function loadGitCommitInfoFromID(GitCommitInfoId|GitCommitInfo $id): GitCommitInfo
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('loadGitCommitInfoFromID');
+        $innerQueryBuilder->setArgument('id', $id);
+        return new \Dagger\GitCommitInfo($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
     /**
      * Load a GitModuleSource from its ID.
      */
@@ -407,6 +489,16 @@ class Client extends Client\AbstractClient
         return new \Dagger\GitRepository($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
+    /**
+     * Load a GitSignature from its ID.
+     */
+    public function loadGitSignatureFromID(GitSignatureId|GitSignature $id): GitSignature
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('loadGitSignatureFromID');
+        $innerQueryBuilder->setArgument('id', $id);
+        return new \Dagger\GitSignature($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
     /**
      * Load a Host from its ID.
      */
@@ -417,6 +509,16 @@ class Client extends Client\AbstractClient
         return new \Dagger\Host($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
+    /**
+     * Load a ImageConfig from its ID.
+     */
+    public function loadImageConfigFromID(ImageConfigId|ImageConfig $id): ImageConfig
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('loadImageConfigFromID');
+        $innerQueryBuilder->setArgument('id', $id);
+        return new \Dagger\ImageConfig($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
     /**
      * Load a InputTypeDef from its ID.
      */
@@ -527,6 +629,16 @@ class Client extends Client\AbstractClient
         return new \Dagger\Port($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
+    /**
+     * Load a Registry from its ID.
+     */
+    public function loadRegistryFromID(RegistryId|Registry $id): Registry
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('loadRegistryFromID');
+        $innerQueryBuilder->setArgument('id', $id);
+        return new \Dagger\Registry($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
     /**
      * Load a SDKConfig from its ID.
      */
@@ -665,6 +777,18 @@ class Client extends Client\AbstractClient
         return new \Dagger\ModuleSource($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
+    /**
+     * Inspects an image in a registry, without pulling its layers.
+     *
+     * Credentials set with Container.withRegistryAuth and the client's credentials are used to authenticate to the registry.
+     */
+    public function registry(string $address): Registry
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('registry');
+        $innerQueryBuilder->setArgument('address', $address);
+        return new \Dagger\Registry($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
     /**
      * Creates a new secret.
      */
@@ -25,6 +25,14 @@ class Container extends Client\AbstractObject implements Client\IdAble
         ?bool $insecureRootCapabilities = false,
         ?bool $expand = false,
         ?bool $noInit = false,
+        ?int $timeout = 0,
+        ?float $cpuLimit = 0,
+        ?int $memoryLimit = 0,
+        ?int $pidsLimit = 0,
+        ?array $healthcheckExec = null,
+        ?int $healthcheckInterval = 0,
+        ?int $healthcheckTimeout = 0,
+        ?int $healthcheckRetries = 0,
     ): Service {
         $innerQueryBuilder = new \Dagger\Client\QueryBuilder('asService');
         if (null !== $args) {
@@ -45,6 +53,30 @@ class Container extends Client\AbstractObject implements Client\IdAble
         if (null !== $noInit) {
         $innerQueryBuilder->setArgument('noInit', $noInit);
         }
+        if (null !== $timeout) {
+        $innerQueryBuilder->setArgument('timeout', $timeout);
+        }
+        if (null !== $cpuLimit) {
+        $innerQueryBuilder->setArgument('cpuLimit', $cpuLimit);
+        }
+        if (null !== $memoryLimit) {
+        $innerQueryBuilder->setArgument('memoryLimit', $memoryLimit);
+        }
+        if (null !== $pidsLimit) {
+        $innerQueryBuilder->setArgument('pidsLimit', $pidsLimit);
+        }
+        if (null !== $healthcheckExec) {
+        $innerQueryBuilder->setArgument('healthcheckExec', $healthcheckExec);
+        }
+        if (null !== $healthcheckInterval) {
+        $innerQueryBuilder->setArgument('healthcheckInterval', $healthcheckInterval);
+        }
+        if (null !== $healthcheckTimeout) {
+        $innerQueryBuilder->setArgument('healthcheckTimeout', $healthcheckTimeout);
+        }
+        if (null !== $healthcheckRetries) {
+        $innerQueryBuilder->setArgument('healthcheckRetries', $healthcheckRetries);
+        }
         return new \Dagger\Service($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
@@ -55,6 +87,12 @@ class Container extends Client\AbstractObject implements Client\IdAble
         ?array $platformVariants = null,
         ?ImageLayerCompression $forcedCompression = null,
         ?ImageMediaTypes $mediaTypes = null,
+        ?ImageLayerSquash $squash = null,
+        ?ImageHistory $history = null,
+        SecretId|Secret|null $signingKey = null,
+        SecretId|Secret|null $signingKeyPassword = null,
+        ?bool $sbom = false,
+        ?bool $provenance = false,
     ): File {
         $innerQueryBuilder = new \Dagger\Client\QueryBuilder('asTarball');
         if (null !== $platformVariants) {
@@ -66,6 +104,24 @@ class Container extends Client\AbstractObject implements Client\IdAble
         if (null !== $mediaTypes) {
         $innerQueryBuilder->setArgument('mediaTypes', $mediaTypes);
         }
+        if (null !== $squash) {
+        $innerQueryBuilder->setArgument('squash', $squash);
+        }
+        if (null !== $history) {
+        $innerQueryBuilder->setArgument('history', $history);
+        }
+        if (null !== $signingKey) {
+        $innerQueryBuilder->setArgument('signingKey', $signingKey);
+        }
+        if (null !== $signingKeyPassword) {
+        $innerQueryBuilder->setArgument('signingKeyPassword', $signingKeyPassword);
+        }
+        if (null !== $sbom) {
+        $innerQueryBuilder->setArgument('sbom', $sbom);
+        }
+        if (null !== $provenance) {
+        $innerQueryBuilder->setArgument('provenance', $provenance);
+        }
         return new \Dagger\File($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
@@ -197,6 +253,8 @@ class Container extends Client\AbstractObject implements Client\IdAble
         ?ImageLayerCompression $forcedCompression = null,
         ?ImageMediaTypes $mediaTypes = null,
         ?bool $expand = false,
+        ?ImageLayerSquash $squash = null,
+        ?ImageHistory $history = null,
     ): string {
         $leafQueryBuilder = new \Dagger\Client\QueryBuilder('export');
         $leafQueryBuilder->setArgument('path', $path);
@@ -212,6 +270,12 @@ class Container extends Client\AbstractObject implements Client\IdAble
         if (null !== $expand) {
         $leafQueryBuilder->setArgument('expand', $expand);
         }
+        if (null !== $squash) {
+        $leafQueryBuilder->setArgument('squash', $squash);
+        }
+        if (null !== $history) {
+        $leafQueryBuilder->setArgument('history', $history);
+        }
         return (string)$this->queryLeaf($leafQueryBuilder, 'export');
     }
 
@@ -271,17 +335,67 @@ class Container extends Client\AbstractObject implements Client\IdAble
 
     /**
      * Reads the container from an OCI tarball.
+     *
+     * Docker archives, like the ones produced by "docker save", are also supported. The image is picked for the container's platform, unless a single image manifest is selected.
      */
-    public function import(FileId|File $source, ?string $tag = ''): Container
+    public function import(FileId|File $source, ?string $tag = '', ?string $digest = ''): Container
     {
         $innerQueryBuilder = new \Dagger\Client\QueryBuilder('import');
         $innerQueryBuilder->setArgument('source', $source);
         if (null !== $tag) {
         $innerQueryBuilder->setArgument('tag', $tag);
         }
+        if (null !== $digest) {
+        $innerQueryBuilder->setArgument('digest', $digest);
+        }
         return new \Dagger\Container($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
+    /**
+     * Reads the container from a directory in OCI image layout.
+     *
+     * The image is picked for the container's platform, unless a single image manifest is selected.
+     */
+    public function importDirectory(DirectoryId|Directory $source, ?string $tag = '', ?string $digest = ''): Container
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('importDirectory');
+        $innerQueryBuilder->setArgument('source', $source);
+        if (null !== $tag) {
+        $innerQueryBuilder->setArgument('tag', $tag);
+        }
+        if (null !== $digest) {
+        $innerQueryBuilder->setArgument('digest', $digest);
+        }
+        return new \Dagger\Container($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
+    /**
+     * Reads every platform variant of an image from an OCI tarball or a directory in OCI image layout.
+     *
+     * The variants can be exported back as a multi-platform image with platformVariants.
+     */
+    public function importVariants(
+        FileId|File|null $source = null,
+        DirectoryId|Directory|null $directory = null,
+        ?string $tag = '',
+        ?string $digest = '',
+    ): array {
+        $leafQueryBuilder = new \Dagger\Client\QueryBuilder('importVariants');
+        if (null !== $source) {
+        $leafQueryBuilder->setArgument('source', $source);
+        }
+        if (null !== $directory) {
+        $leafQueryBuilder->setArgument('directory', $directory);
+        }
+        if (null !== $tag) {
+        $leafQueryBuilder->setArgument('tag', $tag);
+        }
+        if (null !== $digest) {
+        $leafQueryBuilder->setArgument('digest', $digest);
+        }
+        return (array)$this->queryLeaf($leafQueryBuilder, 'importVariants');
+    }
+
     /**
      * Retrieves the value of the specified label.
      */
@@ -331,6 +445,12 @@ class Container extends Client\AbstractObject implements Client\IdAble
         ?array $platformVariants = null,
         ?ImageLayerCompression $forcedCompression = null,
         ?ImageMediaTypes $mediaTypes = null,
+        ?ImageLayerSquash $squash = null,
+        ?ImageHistory $history = null,
+        SecretId|Secret|null $signingKey = null,
+        SecretId|Secret|null $signingKeyPassword = null,
+        ?bool $sbom = false,
+        ?bool $provenance = false,
     ): string {
         $leafQueryBuilder = new \Dagger\Client\QueryBuilder('publish');
         $leafQueryBuilder->setArgument('address', $address);
@@ -343,6 +463,24 @@ class Container extends Client\AbstractObject implements Client\IdAble
         if (null !== $mediaTypes) {
         $leafQueryBuilder->setArgument('mediaTypes', $mediaTypes);
         }
+        if (null !== $squash) {
+        $leafQueryBuilder->setArgument('squash', $squash);
+        }
+        if (null !== $history) {
+        $leafQueryBuilder->setArgument('history', $history);
+        }
+        if (null !== $signingKey) {
+        $leafQueryBuilder->setArgument('signingKey', $signingKey);
+        }
+        if (null !== $signingKeyPassword) {
+        $leafQueryBuilder->setArgument('signingKeyPassword', $signingKeyPassword);
+        }
+        if (null !== $sbom) {
+        $leafQueryBuilder->setArgument('sbom', $sbom);
+        }
+        if (null !== $provenance) {
+        $leafQueryBuilder->setArgument('provenance', $provenance);
+        }
         return (string)$this->queryLeaf($leafQueryBuilder, 'publish');
     }
 
@@ -423,6 +561,14 @@ class Container extends Client\AbstractObject implements Client\IdAble
         ?bool $insecureRootCapabilities = false,
         ?bool $expand = false,
         ?bool $noInit = false,
+        ?int $timeout = 0,
+        ?float $cpuLimit = 0,
+        ?int $memoryLimit = 0,
+        ?int $pidsLimit = 0,
+        ?array $healthcheckExec = null,
+        ?int $healthcheckInterval = 0,
+        ?int $healthcheckTimeout = 0,
+        ?int $healthcheckRetries = 0,
     ): void {
         $leafQueryBuilder = new \Dagger\Client\QueryBuilder('up');
         if (null !== $ports) {
@@ -449,6 +595,30 @@ class Container extends Client\AbstractObject implements Client\IdAble
         if (null !== $noInit) {
         $leafQueryBuilder->setArgument('noInit', $noInit);
         }
+        if (null !== $timeout) {
+        $leafQueryBuilder->setArgument('timeout', $timeout);
+        }
+        if (null !== $cpuLimit) {
+        $leafQueryBuilder->setArgument('cpuLimit', $cpuLimit);
+        }
+        if (null !== $memoryLimit) {
+        $leafQueryBuilder->setArgument('memoryLimit', $memoryLimit);
+        }
+        if (null !== $pidsLimit) {
+        $leafQueryBuilder->setArgument('pidsLimit', $pidsLimit);
+        }
+        if (null !== $healthcheckExec) {
+        $leafQueryBuilder->setArgument('healthcheckExec', $healthcheckExec);
+        }
+        if (null !== $healthcheckInterval) {
+        $leafQueryBuilder->setArgument('healthcheckInterval', $healthcheckInterval);
+        }
+        if (null !== $healthcheckTimeout) {
+        $leafQueryBuilder->setArgument('healthcheckTimeout', $healthcheckTimeout);
+        }
+        if (null !== $healthcheckRetries) {
+        $leafQueryBuilder->setArgument('healthcheckRetries', $healthcheckRetries);
+        }
         $this->queryLeaf($leafQueryBuilder, 'up');
     }
 
@@ -571,6 +741,11 @@ class Container extends Client\AbstractObject implements Client\IdAble
         ?bool $insecureRootCapabilities = false,
         ?bool $expand = false,
         ?bool $noInit = false,
+        ?NetworkMode $networkMode = null,
+        ?int $timeout = 0,
+        ?float $cpuLimit = 0,
+        ?int $memoryLimit = 0,
+        ?int $pidsLimit = 0,
     ): Container {
         $innerQueryBuilder = new \Dagger\Client\QueryBuilder('withExec');
         $innerQueryBuilder->setArgument('args', $args);
@@ -601,6 +776,21 @@ class Container extends Client\AbstractObject implements Client\IdAble
         if (null !== $noInit) {
         $innerQueryBuilder->setArgument('noInit', $noInit);
         }
+        if (null !== $networkMode) {
+        $innerQueryBuilder->setArgument('networkMode', $networkMode);
+        }
+        if (null !== $timeout) {
+        $innerQueryBuilder->setArgument('timeout', $timeout);
+        }
+        if (null !== $cpuLimit) {
+        $innerQueryBuilder->setArgument('cpuLimit', $cpuLimit);
+        }
+        if (null !== $memoryLimit) {
+        $innerQueryBuilder->setArgument('memoryLimit', $memoryLimit);
+        }
+        if (null !== $pidsLimit) {
+        $innerQueryBuilder->setArgument('pidsLimit', $pidsLimit);
+        }
         return new \Dagger\Container($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
@@ -618,6 +808,9 @@ class Container extends Client\AbstractObject implements Client\IdAble
         ?NetworkProtocol $protocol = null,
         ?string $description = null,
         ?bool $experimentalSkipHealthcheck = false,
+        ?string $healthcheckPath = '',
+        ?HealthcheckScheme $healthcheckScheme = null,
+        ?int $healthcheckStatus = 0,
     ): Container {
         $innerQueryBuilder = new \Dagger\Client\QueryBuilder('withExposedPort');
         $innerQueryBuilder->setArgument('port', $port);
@@ -630,6 +823,15 @@ class Container extends Client\AbstractObject implements Client\IdAble
         if (null !== $experimentalSkipHealthcheck) {
         $innerQueryBuilder->setArgument('experimentalSkipHealthcheck', $experimentalSkipHealthcheck);
         }
+        if (null !== $healthcheckPath) {
+        $innerQueryBuilder->setArgument('healthcheckPath', $healthcheckPath);
+        }
+        if (null !== $healthcheckScheme) {
+        $innerQueryBuilder->setArgument('healthcheckScheme', $healthcheckScheme);
+        }
+        if (null !== $healthcheckStatus) {
+        $innerQueryBuilder->setArgument('healthcheckStatus', $healthcheckStatus);
+        }
         return new \Dagger\Container($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
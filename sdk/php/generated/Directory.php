@@ -13,6 +13,23 @@ namespace Dagger;
  */
 class Directory extends Client\AbstractObject implements Client\IdAble
 {
+    /**
+     * Load a Compose project from a Compose file in this directory.
+     *
+     * Paths in the Compose file, such as build contexts and bind mounts, are resolved relative to the Compose file's directory. Variables are interpolated from a .env file next to the Compose file, if any.
+     */
+    public function asCompose(?string $path = '', ?string $projectName = ''): ComposeProject
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('asCompose');
+        if (null !== $path) {
+        $innerQueryBuilder->setArgument('path', $path);
+        }
+        if (null !== $projectName) {
+        $innerQueryBuilder->setArgument('projectName', $projectName);
+        }
+        return new \Dagger\ComposeProject($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
     /**
      * Load the directory as a Dagger module
      */
@@ -28,6 +45,16 @@ class Directory extends Client\AbstractObject implements Client\IdAble
         return new \Dagger\Module($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
+    /**
+     * Returns a unified diff, in the format of "git diff", of the files changed between this directory and another directory.
+     */
+    public function asPatch(DirectoryId|Directory $other): File
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('asPatch');
+        $innerQueryBuilder->setArgument('other', $other);
+        return new \Dagger\File($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
     /**
      * Gets the difference between this directory and an another directory.
      */
@@ -66,6 +93,10 @@ class Directory extends Client\AbstractObject implements Client\IdAble
         ?string $target = '',
         ?array $buildArgs = null,
         ?array $secrets = null,
+        ?array $buildContexts = null,
+        SocketId|Socket|null $ssh = null,
+        ?array $noCacheFilter = null,
+        ?array $cacheFrom = null,
     ): Container {
         $innerQueryBuilder = new \Dagger\Client\QueryBuilder('dockerBuild');
         if (null !== $platform) {
@@ -83,6 +114,18 @@ class Directory extends Client\AbstractObject implements Client\IdAble
         if (null !== $secrets) {
         $innerQueryBuilder->setArgument('secrets', $secrets);
         }
+        if (null !== $buildContexts) {
+        $innerQueryBuilder->setArgument('buildContexts', $buildContexts);
+        }
+        if (null !== $ssh) {
+        $innerQueryBuilder->setArgument('ssh', $ssh);
+        }
+        if (null !== $noCacheFilter) {
+        $innerQueryBuilder->setArgument('noCacheFilter', $noCacheFilter);
+        }
+        if (null !== $cacheFrom) {
+        $innerQueryBuilder->setArgument('cacheFrom', $cacheFrom);
+        }
         return new \Dagger\Container($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
@@ -140,6 +183,18 @@ class Directory extends Client\AbstractObject implements Client\IdAble
         return new \Dagger\DirectoryId((string)$this->queryLeaf($leafQueryBuilder, 'id'));
     }
 
+    /**
+     * Retrieves this directory with the contents of the given directories merged into it.
+     *
+     * Fails if the same path holds different contents in more than one of the directories, instead of overwriting it.
+     */
+    public function merge(array $directories): Directory
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('merge');
+        $innerQueryBuilder->setArgument('directories', $directories);
+        return new \Dagger\Directory($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
     /**
      * Force evaluation in the engine.
      */
@@ -250,6 +305,18 @@ class Directory extends Client\AbstractObject implements Client\IdAble
         return new \Dagger\Directory($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
 
+    /**
+     * Retrieves this directory with the given unified diff applied.
+     *
+     * Fails if any hunk of the patch does not apply cleanly.
+     */
+    public function withPatch(FileId|File $patch): Directory
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('withPatch');
+        $innerQueryBuilder->setArgument('patch', $patch);
+        return new \Dagger\Directory($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
     /**
      * Retrieves this directory with all file/dir timestamps set to the given time.
      */
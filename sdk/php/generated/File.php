@@ -13,6 +13,20 @@ namespace Dagger;
  */
 class File extends Client\AbstractObject implements Client\IdAble
 {
+    /**
+     * Load a Compose project from this Compose file.
+     *
+     * Services that are built or that bind mount paths can only be loaded from a directory with Directory.asCompose.
+     */
+    public function asCompose(?string $projectName = ''): ComposeProject
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('asCompose');
+        if (null !== $projectName) {
+        $innerQueryBuilder->setArgument('projectName', $projectName);
+        }
+        return new \Dagger\ComposeProject($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
     /**
      * Retrieves the contents of the file.
      */
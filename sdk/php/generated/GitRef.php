@@ -22,6 +22,26 @@ class GitRef extends Client\AbstractObject implements Client\IdAble
         return (string)$this->queryLeaf($leafQueryBuilder, 'commit');
     }
 
+    /**
+     * The metadata of the resolved commit at this ref.
+     */
+    public function commitInfo(): GitCommitInfo
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('commitInfo');
+        return new \Dagger\GitCommitInfo($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
+    /**
+     * The nearest tag reachable from this ref, as returned by "git describe --tags".
+     *
+     * If the tag doesn't point at the ref itself, it's suffixed with the number of commits since the tag and the abbreviated commit id (e.g., "v0.3.9-4-gb6315d8").
+     */
+    public function describe(): string
+    {
+        $leafQueryBuilder = new \Dagger\Client\QueryBuilder('describe');
+        return (string)$this->queryLeaf($leafQueryBuilder, 'describe');
+    }
+
     /**
      * A unique identifier for this GitRef.
      */
@@ -32,14 +52,61 @@ class GitRef extends Client\AbstractObject implements Client\IdAble
     }
 
     /**
-     * The filesystem tree at this ref.
+     * The signature of this ref, if it was verified by "verify".
      */
-    public function tree(?bool $discardGitDir = false): Directory
+    public function signature(): GitSignature
     {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('signature');
+        return new \Dagger\GitSignature($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
+    /**
+     * The filesystem tree at this ref.
+     */
+    public function tree(
+        ?bool $discardGitDir = false,
+        ?array $sparseCheckout = null,
+        ?int $depth = 1,
+        ?bool $fullHistory = false,
+        ?bool $skipSubmodules = false,
+        ?bool $lfs = false,
+    ): Directory {
         $innerQueryBuilder = new \Dagger\Client\QueryBuilder('tree');
         if (null !== $discardGitDir) {
         $innerQueryBuilder->setArgument('discardGitDir', $discardGitDir);
         }
+        if (null !== $sparseCheckout) {
+        $innerQueryBuilder->setArgument('sparseCheckout', $sparseCheckout);
+        }
+        if (null !== $depth) {
+        $innerQueryBuilder->setArgument('depth', $depth);
+        }
+        if (null !== $fullHistory) {
+        $innerQueryBuilder->setArgument('fullHistory', $fullHistory);
+        }
+        if (null !== $skipSubmodules) {
+        $innerQueryBuilder->setArgument('skipSubmodules', $skipSubmodules);
+        }
+        if (null !== $lfs) {
+        $innerQueryBuilder->setArgument('lfs', $lfs);
+        }
         return new \Dagger\Directory($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
+
+    /**
+     * Verify the GPG or SSH signature of the annotated tag or commit at this ref, failing if it's missing or not made by one of the trusted keys.
+     *
+     * Returns this ref pinned to the verified commit.
+     */
+    public function verify(?array $keys = null, FileId|File|null $allowedSigners = null): GitRef
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('verify');
+        if (null !== $keys) {
+        $innerQueryBuilder->setArgument('keys', $keys);
+        }
+        if (null !== $allowedSigners) {
+        $innerQueryBuilder->setArgument('allowedSigners', $allowedSigners);
+        }
+        return new \Dagger\GitRef($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
 }
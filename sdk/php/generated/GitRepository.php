@@ -51,6 +51,24 @@ class GitRepository extends Client\AbstractObject implements Client\IdAble
         return new \Dagger\GitRepositoryId((string)$this->queryLeaf($leafQueryBuilder, 'id'));
     }
 
+    /**
+     * Returns the commits reachable from a ref, newest first.
+     */
+    public function log(?string $from = '', ?string $to = '', ?array $paths = null): array
+    {
+        $leafQueryBuilder = new \Dagger\Client\QueryBuilder('log');
+        if (null !== $from) {
+        $leafQueryBuilder->setArgument('from', $from);
+        }
+        if (null !== $to) {
+        $leafQueryBuilder->setArgument('to', $to);
+        }
+        if (null !== $paths) {
+        $leafQueryBuilder->setArgument('paths', $paths);
+        }
+        return (array)$this->queryLeaf($leafQueryBuilder, 'log');
+    }
+
     /**
      * Returns details of a ref.
      */
@@ -40,6 +40,17 @@ class Secret extends Client\AbstractObject implements Client\IdAble
         return (string)$this->queryLeaf($leafQueryBuilder, 'plaintext');
     }
 
+    /**
+     * Resolves this secret again from its provider, replacing the value the client cached for the session, e.g. after it was rotated.
+     *
+     * Secrets set from a plaintext have nothing to refresh.
+     */
+    public function refresh(): void
+    {
+        $leafQueryBuilder = new \Dagger\Client\QueryBuilder('refresh');
+        $this->queryLeaf($leafQueryBuilder, 'refresh');
+    }
+
     /**
      * The URI of this secret.
      */
@@ -32,6 +32,17 @@ class Service extends Client\AbstractObject implements Client\IdAble
         return (string)$this->queryLeaf($leafQueryBuilder, 'endpoint');
     }
 
+    /**
+     * The exit code the service last exited with, or null if it hasn't exited yet.
+     *
+     * If the service has been restarted, this is the exit code of the run before the current one until that one exits too. The service must have been started within the session.
+     */
+    public function exitCode(): int
+    {
+        $leafQueryBuilder = new \Dagger\Client\QueryBuilder('exitCode');
+        return (int)$this->queryLeaf($leafQueryBuilder, 'exitCode');
+    }
+
     /**
      * Retrieves a hostname which can be used by clients to reach this container.
      */
@@ -70,6 +81,40 @@ class Service extends Client\AbstractObject implements Client\IdAble
         return new \Dagger\ServiceId((string)$this->queryLeaf($leafQueryBuilder, 'start'));
     }
 
+    /**
+     * The output the service has written to stderr.
+     *
+     * The service must have been started within the session. Its output remains available once it has stopped, and includes the output of every run if it has restarted.
+     */
+    public function stderr(?int $tail = 0, ?string $since = ''): string
+    {
+        $leafQueryBuilder = new \Dagger\Client\QueryBuilder('stderr');
+        if (null !== $tail) {
+        $leafQueryBuilder->setArgument('tail', $tail);
+        }
+        if (null !== $since) {
+        $leafQueryBuilder->setArgument('since', $since);
+        }
+        return (string)$this->queryLeaf($leafQueryBuilder, 'stderr');
+    }
+
+    /**
+     * The output the service has written to stdout.
+     *
+     * The service must have been started within the session. Its output remains available once it has stopped, and includes the output of every run if it has restarted.
+     */
+    public function stdout(?int $tail = 0, ?string $since = ''): string
+    {
+        $leafQueryBuilder = new \Dagger\Client\QueryBuilder('stdout');
+        if (null !== $tail) {
+        $leafQueryBuilder->setArgument('tail', $tail);
+        }
+        if (null !== $since) {
+        $leafQueryBuilder->setArgument('since', $since);
+        }
+        return (string)$this->queryLeaf($leafQueryBuilder, 'stdout');
+    }
+
     /**
      * Stop the service.
      */
@@ -106,4 +151,36 @@ class Service extends Client\AbstractObject implements Client\IdAble
         $innerQueryBuilder->setArgument('hostname', $hostname);
         return new \Dagger\Service($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
     }
+
+    /**
+     * Keeps running the service's health checks once it has started.
+     *
+     * The service is killed once its health checks fail enough times in a row, and restarted if its restart policy allows it.
+     */
+    public function withLivenessProbe(?int $interval = 10, ?int $failureThreshold = 3): Service
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('withLivenessProbe');
+        if (null !== $interval) {
+        $innerQueryBuilder->setArgument('interval', $interval);
+        }
+        if (null !== $failureThreshold) {
+        $innerQueryBuilder->setArgument('failureThreshold', $failureThreshold);
+        }
+        return new \Dagger\Service($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
+
+    /**
+     * Configures whether the service is restarted when it exits.
+     *
+     * The service keeps the same hostname across restarts, so containers bound to it can keep reaching it.
+     */
+    public function withRestartPolicy(ServiceRestartPolicy $policy, ?int $maxRetries = 0): Service
+    {
+        $innerQueryBuilder = new \Dagger\Client\QueryBuilder('withRestartPolicy');
+        $innerQueryBuilder->setArgument('policy', $policy);
+        if (null !== $maxRetries) {
+        $innerQueryBuilder->setArgument('maxRetries', $maxRetries);
+        }
+        return new \Dagger\Service($this->client, $this->queryBuilderChain->chain($innerQueryBuilder));
+    }
 }
@@ -4,8 +4,10 @@ namespace Dagger\Client;
 
 use Dagger\Client;
 use Dagger\Connection;
+use Dagger\Exception\ExecError;
 use Dagger\GraphQl\QueryBuilderChain;
 use GraphQL\Client as GqlClient;
+use GraphQL\Exception\QueryError;
 use GraphQL\Query;
 use GraphQL\QueryBuilder\QueryBuilder;
 use GraphQL\Results;
@@ -32,12 +34,16 @@ abstract class AbstractClient
 
     public function runQuery(QueryBuilder|Query $query): Results
     {
-        return $this->graphQlClient->runQuery($query);
+        try {
+            return $this->graphQlClient->runQuery($query);
+        } catch (QueryError $e) {
+            throw ExecError::fromQueryError($e) ?? $e;
+        }
     }
 
     public function queryLeaf(QueryBuilder|Query $query, string $leafKey): null|array|string|int|float|bool
     {
-        $response = $this->graphQlClient->runQuery($query);
+        $response = $this->runQuery($query);
         $data = $response->getData();
         foreach (
             new RecursiveIteratorIterator(
@@ -5,6 +5,7 @@ declare(strict_types=1);
 namespace Dagger\Command;
 
 use Dagger;
+use Dagger\Exception\ExecError;
 use Dagger\Service\DecodesValue;
 use Dagger\Service\FindsDaggerObjects;
 use Dagger\Service\FindsSrcDirectory;
@@ -127,6 +128,15 @@ class EntrypointCommand extends Command
             } else {
                 $result = new $parentName(...$args);
             }
+        } catch (ExecError $e) {
+            $errorOutput->writeln($e->getMessage());
+            $output->writeln($e->stdout);
+            $errorOutput->writeln($e->stderr);
+            if ($e->reason !== null) {
+                $errorOutput->writeln(sprintf('terminated by the engine: %s', $e->reason));
+            }
+
+            return $e->exitCode;
         } catch (QueryError $e) {
             if (!isset($e->getErrorDetails()['extensions'])) {
                 throw $e;
@@ -0,0 +1,51 @@
+<?php
+
+declare(strict_types=1);
+
+namespace Dagger\Exception;
+
+use GraphQL\Exception\QueryError;
+
+/**
+ * API error from an exec operation.
+ */
+final class ExecError extends \RuntimeException
+{
+    /**
+     * @param string[] $cmd The command that was executed.
+     * @param ?string $reason Why the engine terminated the command
+     * (e.g. TIMEOUT or OOM_KILLED), if it did.
+     */
+    public function __construct(
+        string $message,
+        public readonly array $cmd,
+        public readonly int $exitCode,
+        public readonly string $stdout,
+        public readonly string $stderr,
+        public readonly ?string $reason = null,
+        ?\Throwable $previous = null,
+    ) {
+        parent::__construct($message, 0, $previous);
+    }
+
+    /**
+     * Returns the ExecError described by the query error, if it is one.
+     */
+    public static function fromQueryError(QueryError $error): ?self
+    {
+        $extensions = $error->getErrorDetails()['extensions'] ?? [];
+        if (($extensions['_type'] ?? null) !== 'EXEC_ERROR') {
+            return null;
+        }
+
+        return new self(
+            $error->getMessage(),
+            $extensions['cmd'] ?? [],
+            $extensions['exitCode'] ?? -1,
+            $extensions['stdout'] ?? '',
+            $extensions['stderr'] ?? '',
+            $extensions['reason'] ?? null,
+            $error,
+        );
+    }
+}
@@ -156,6 +156,9 @@ class ExecError(QueryError):
         The stdout of the command.
     stderr:
         The stderr of the command.
+    reason:
+        Why the engine terminated the command (e.g., ``TIMEOUT`` or
+        ``OOM_KILLED``), if it did.
     """
 
     _type = "EXEC_ERROR"
@@ -165,6 +168,7 @@ class ExecError(QueryError):
     exit_code: int
     stdout: str
     stderr: str
+    reason: str | None
 
     def __init__(self, *args, **kwargs):
         super().__init__(*args, **kwargs)
@@ -176,6 +180,7 @@ class ExecError(QueryError):
         self.exit_code = ext["exitCode"]
         self.stdout = ext["stdout"]
         self.stderr = ext["stderr"]
+        self.reason = ext.get("reason")
 
     def __str__(self):
         """Prints the original error message."""
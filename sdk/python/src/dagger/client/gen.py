@@ -11,11 +11,21 @@ from dagger.client._guards import typecheck
 from dagger.client.base import Enum, Input, Scalar, Type
 
 
+class CacheMissExplanationID(Scalar):
+    """The `CacheMissExplanationID` scalar type represents an identifier
+    for an object of type CacheMissExplanation."""
+
+
 class CacheVolumeID(Scalar):
     """The `CacheVolumeID` scalar type represents an identifier for an
     object of type CacheVolume."""
 
 
+class ComposeProjectID(Scalar):
+    """The `ComposeProjectID` scalar type represents an identifier for an
+    object of type ComposeProject."""
+
+
 class ContainerID(Scalar):
     """The `ContainerID` scalar type represents an identifier for an
     object of type Container."""
@@ -106,6 +116,16 @@ class GeneratedCodeID(Scalar):
     object of type GeneratedCode."""
 
 
+class GitActorID(Scalar):
+    """The `GitActorID` scalar type represents an identifier for an object
+    of type GitActor."""
+
+
+class GitCommitInfoID(Scalar):
+    """The `GitCommitInfoID` scalar type represents an identifier for an
+    object of type GitCommitInfo."""
+
+
 class GitModuleSourceID(Scalar):
     """The `GitModuleSourceID` scalar type represents an identifier for an
     object of type GitModuleSource."""
@@ -121,11 +141,21 @@ class GitRepositoryID(Scalar):
     object of type GitRepository."""
 
 
+class GitSignatureID(Scalar):
+    """The `GitSignatureID` scalar type represents an identifier for an
+    object of type GitSignature."""
+
+
 class HostID(Scalar):
     """The `HostID` scalar type represents an identifier for an object of
     type Host."""
 
 
+class ImageConfigID(Scalar):
+    """The `ImageConfigID` scalar type represents an identifier for an
+    object of type ImageConfig."""
+
+
 class InputTypeDefID(Scalar):
     """The `InputTypeDefID` scalar type represents an identifier for an
     object of type InputTypeDef."""
@@ -191,6 +221,11 @@ class PortID(Scalar):
     type Port."""
 
 
+class RegistryID(Scalar):
+    """The `RegistryID` scalar type represents an identifier for an object
+    of type Registry."""
+
+
 class SDKConfigID(Scalar):
     """The `SDKConfigID` scalar type represents an identifier for an
     object of type SDKConfig."""
@@ -236,6 +271,25 @@ class Void(Scalar):
     resolvers that do not return anything."""
 
 
+class CacheMissKind(Enum):
+    """What differs between a call and the same call in a previous run."""
+
+    ARGUMENT = "ARGUMENT"
+    """An argument was added, removed or changed."""
+
+    CALL = "CALL"
+    """A call selects a different field, view or module."""
+
+    CONTENT = "CONTENT"
+    """A call with the same arguments has different content, e.g. the files of a host directory."""
+
+    NONE = "NONE"
+    """The calls are the same, so the previous result was pruned from the cache or was never cached."""
+
+    RECEIVER = "RECEIVER"
+    """A call is chained from an object in only one of the runs."""
+
+
 class CacheSharingMode(Enum):
     """Sharing mode of the cache volume."""
 
@@ -249,6 +303,24 @@ class CacheSharingMode(Enum):
     """Shares the cache volume amongst many build pipelines"""
 
 
+class HealthcheckScheme(Enum):
+    """Scheme of the HTTP request used to check a port's health."""
+
+    HTTP = "HTTP"
+
+    HTTPS = "HTTPS"
+
+
+class ImageHistory(Enum):
+    """How to describe the layers of an exported image in its history."""
+
+    FIELDS = "FIELDS"
+    """Describe each layer by the API field that created it, e.g. "withExec", with the digest of its call as comment."""
+
+    OPERATIONS = "OPERATIONS"
+    """Describe each layer by the operation that created it."""
+
+
 class ImageLayerCompression(Enum):
     """Compression algorithm to use for image layers."""
 
@@ -261,6 +333,19 @@ class ImageLayerCompression(Enum):
     Zstd = "Zstd"
 
 
+class ImageLayerSquash(Enum):
+    """How to squash the layers of an exported image."""
+
+    ABOVE_BASE = "ABOVE_BASE"
+    """Keep the layers of the image the container was created from, and squash the layers above them into one."""
+
+    ALL = "ALL"
+    """Squash all layers into one."""
+
+    NONE = "NONE"
+    """Keep one layer per operation."""
+
+
 class ImageMediaTypes(Enum):
     """Mediatypes to use in published or exported image metadata."""
 
@@ -277,6 +362,19 @@ class ModuleSourceKind(Enum):
     LOCAL_SOURCE = "LOCAL_SOURCE"
 
 
+class NetworkMode(Enum):
+    """Network access granted to an executed command."""
+
+    DEFAULT = "DEFAULT"
+    """Full network access, including services and the internet."""
+
+    NONE = "NONE"
+    """No network access beyond the loopback interface."""
+
+    SERVICES_ONLY = "SERVICES_ONLY"
+    """Only bound services and the engine's DNS server are reachable."""
+
+
 class NetworkProtocol(Enum):
     """Transport layer network protocol associated to a port."""
 
@@ -298,6 +396,19 @@ class ReturnType(Enum):
     """A successful execution (exit code 0)"""
 
 
+class ServiceRestartPolicy(Enum):
+    """When to restart a service that has exited."""
+
+    ALWAYS = "ALWAYS"
+    """Restart the service whenever it exits, unless it was stopped."""
+
+    NEVER = "NEVER"
+    """Never restart the service."""
+
+    ON_FAILURE = "ON_FAILURE"
+    """Restart the service when it exits with an error or fails its liveness checks."""
+
+
 class TypeDefKind(Enum):
     """Distinguishes the different kinds of TypeDefs."""
 
@@ -362,6 +473,33 @@ class BuildArg(Input):
     """The build argument value."""
 
 
+@typecheck
+@dataclass(slots=True)
+class BuildContext(Input):
+    """A named build context for a Dockerfile build."""
+
+    name: str
+    """The name of the build context, as referenced by the Dockerfile (e.g., in "COPY --from=<name>" or "FROM <name>")."""
+
+    container: "Container | None" = None
+    """The container to use as the build context, including its image config."""
+
+    directory: "Directory | None" = None
+    """The directory to use as the build context."""
+
+
+@typecheck
+@dataclass(slots=True)
+class HTTPHeader(Input):
+    """Key value object that represents an HTTP header."""
+
+    name: str
+    """The header name."""
+
+    value: str
+    """The header value."""
+
+
 @typecheck
 @dataclass(slots=True)
 class PipelineLabel(Input):
@@ -389,6 +527,247 @@ class PortForward(Input):
     """Transport layer protocol to use for traffic."""
 
 
+@typecheck
+class CacheMissExplanation(Type):
+    """The first difference between a call and the same call in a previous
+    run, which explains why it missed the cache."""
+
+    async def argument(self) -> str:
+        """The name of the diverging argument, if the kind is ARGUMENT.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("argument", _args)
+        return await _ctx.execute(str)
+
+    async def call(self) -> str:
+        """The first diverging call, e.g. host.directory(path: "./src"), empty if
+        the calls are the same.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("call", _args)
+        return await _ctx.execute(str)
+
+    async def current_value(self) -> str:
+        """The current value, e.g. of the argument or content digest, empty if
+        missing.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("currentValue", _args)
+        return await _ctx.execute(str)
+
+    async def digest(self) -> str:
+        """The digest of the call that missed the cache.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("digest", _args)
+        return await _ctx.execute(str)
+
+    async def id(self) -> CacheMissExplanationID:
+        """A unique identifier for this CacheMissExplanation.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        CacheMissExplanationID
+            The `CacheMissExplanationID` scalar type represents an identifier
+            for an object of type CacheMissExplanation.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(CacheMissExplanationID)
+
+    async def kind(self) -> CacheMissKind:
+        """What differs between the calls.
+
+        Returns
+        -------
+        CacheMissKind
+            What differs between a call and the same call in a previous run.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("kind", _args)
+        return await _ctx.execute(CacheMissKind)
+
+    async def message(self) -> str:
+        """A description of the first difference between the calls.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("message", _args)
+        return await _ctx.execute(str)
+
+    async def previous_digest(self) -> str:
+        """The digest of the call it was compared to.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("previousDigest", _args)
+        return await _ctx.execute(str)
+
+    async def previous_trace(self) -> str:
+        """The trace of the previous run the call it was compared to is from.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("previousTrace", _args)
+        return await _ctx.execute(str)
+
+    async def previous_value(self) -> str:
+        """The previous value, e.g. of the argument or content digest, empty if
+        missing.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("previousValue", _args)
+        return await _ctx.execute(str)
+
+    async def trace(self) -> str:
+        """The trace of the run the call is from.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("trace", _args)
+        return await _ctx.execute(str)
+
+
 @typecheck
 class CacheVolume(Type):
     """A directory whose contents persist across runs."""
@@ -418,6 +797,95 @@ class CacheVolume(Type):
         return await _ctx.execute(CacheVolumeID)
 
 
+@typecheck
+class ComposeProject(Type):
+    """A set of services described by a Compose file."""
+
+    async def id(self) -> ComposeProjectID:
+        """A unique identifier for this ComposeProject.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        ComposeProjectID
+            The `ComposeProjectID` scalar type represents an identifier for an
+            object of type ComposeProject.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(ComposeProjectID)
+
+    async def name(self) -> str:
+        """The name of the project.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("name", _args)
+        return await _ctx.execute(str)
+
+    def service(self, name: str) -> "Service":
+        """Retrieves a service of the project.
+
+        The services it depends on are bound to it, reachable at their names
+        in the project. Its named volumes are cache volumes, shared with the
+        project's other services.
+
+        Parameters
+        ----------
+        name:
+            The name of the service.
+        """
+        _args = [
+            Arg("name", name),
+        ]
+        _ctx = self._select("service", _args)
+        return Service(_ctx)
+
+    async def service_names(self) -> list[str]:
+        """The names of the services in the project.
+
+        Returns
+        -------
+        list[str]
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("serviceNames", _args)
+        return await _ctx.execute(list[str])
+
+
 @typecheck
 class Container(Type):
     """An OCI-compatible container, also known as a Docker container."""
@@ -431,6 +899,14 @@ class Container(Type):
         insecure_root_capabilities: bool | None = False,
         expand: bool | None = False,
         no_init: bool | None = False,
+        timeout: int | None = 0,
+        cpu_limit: float | None = 0,
+        memory_limit: int | None = 0,
+        pids_limit: int | None = 0,
+        healthcheck_exec: list[str] | None = None,
+        healthcheck_interval: int | None = 0,
+        healthcheck_timeout: int | None = 0,
+        healthcheck_retries: int | None = 0,
     ) -> "Service":
         """Turn the container into a Service.
 
@@ -464,6 +940,29 @@ class Container(Type):
             This should only be used if the user requires that their exec
             process be the pid 1 process in the container. Otherwise it may
             result in unexpected behavior.
+        timeout:
+            Number of seconds the service may run for before it's killed.
+        cpu_limit:
+            Number of CPUs the service may use (e.g., 0.5).
+        memory_limit:
+            Memory in bytes the service may use before it's killed (e.g.,
+            536870912 for 512 MiB).
+        pids_limit:
+            Number of processes the service may have running at once.
+        healthcheck_exec:
+            Command to run in the service container to check that it's healthy
+            (e.g., ["pg_isready"]).
+            It runs once the exposed ports are reachable, and the service is
+            healthy once it exits successfully.
+        healthcheck_interval:
+            Number of seconds to wait between health check attempts.
+            If unset, attempts back off exponentially.
+        healthcheck_timeout:
+            Number of seconds each health check attempt may take.
+        healthcheck_retries:
+            Number of times to retry a failing health check before the service
+            fails to start.
+            If unset, health checks are retried for up to 15 minutes.
         """
         _args = [
             Arg("args", () if args is None else args, ()),
@@ -474,6 +973,18 @@ class Container(Type):
             Arg("insecureRootCapabilities", insecure_root_capabilities, False),
             Arg("expand", expand, False),
             Arg("noInit", no_init, False),
+            Arg("timeout", timeout, 0),
+            Arg("cpuLimit", cpu_limit, 0),
+            Arg("memoryLimit", memory_limit, 0),
+            Arg("pidsLimit", pids_limit, 0),
+            Arg(
+                "healthcheckExec",
+                () if healthcheck_exec is None else healthcheck_exec,
+                (),
+            ),
+            Arg("healthcheckInterval", healthcheck_interval, 0),
+            Arg("healthcheckTimeout", healthcheck_timeout, 0),
+            Arg("healthcheckRetries", healthcheck_retries, 0),
         ]
         _ctx = self._select("asService", _args)
         return Service(_ctx)
@@ -484,6 +995,12 @@ class Container(Type):
         platform_variants: "list[Container] | None" = None,
         forced_compression: ImageLayerCompression | None = None,
         media_types: ImageMediaTypes | None = ImageMediaTypes.OCIMediaTypes,
+        squash: ImageLayerSquash | None = ImageLayerSquash.NONE,
+        history: ImageHistory | None = ImageHistory.OPERATIONS,
+        signing_key: "Secret | None" = None,
+        signing_key_password: "Secret | None" = None,
+        sbom: bool | None = False,
+        provenance: bool | None = False,
     ) -> "File":
         """Returns a File representing the container serialized to a tarball.
 
@@ -505,6 +1022,23 @@ class Container(Type):
             Defaults to OCI, which is largely compatible with most recent
             container runtimes, but Docker may be needed for older runtimes
             without OCI support.
+        squash:
+            Squash the layers of the image into one, or the layers above the
+            image the container was created from with "from" or "import".
+            Squashing drops the files that were deleted by later operations.
+        history:
+            How to describe the layers of the image in its history.
+        signing_key:
+            A private key to sign the image with, in PEM format (e.g.,
+            generated by "cosign generate-key-pair").
+            The signature is compatible with "cosign verify".
+        signing_key_password:
+            The password of the signing key, if it is encrypted.
+        sbom:
+            Attach an SPDX SBOM of the image's filesystem as an attestation.
+        provenance:
+            Attach a SLSA provenance, recording the container's call ID, as an
+            attestation.
         """
         _args = [
             Arg(
@@ -514,6 +1048,12 @@ class Container(Type):
             ),
             Arg("forcedCompression", forced_compression, None),
             Arg("mediaTypes", media_types, ImageMediaTypes.OCIMediaTypes),
+            Arg("squash", squash, ImageLayerSquash.NONE),
+            Arg("history", history, ImageHistory.OPERATIONS),
+            Arg("signingKey", signing_key, None),
+            Arg("signingKeyPassword", signing_key_password, None),
+            Arg("sbom", sbom, False),
+            Arg("provenance", provenance, False),
         ]
         _ctx = self._select("asTarball", _args)
         return File(_ctx)
@@ -738,6 +1278,8 @@ class Container(Type):
         forced_compression: ImageLayerCompression | None = None,
         media_types: ImageMediaTypes | None = ImageMediaTypes.OCIMediaTypes,
         expand: bool | None = False,
+        squash: ImageLayerSquash | None = ImageLayerSquash.NONE,
+        history: ImageHistory | None = ImageHistory.OPERATIONS,
     ) -> str:
         """Writes the container as an OCI tarball to the destination file path on
         the host.
@@ -769,6 +1311,12 @@ class Container(Type):
             Replace "${VAR}" or "$VAR" in the value of path according to the
             current environment variables defined in the container (e.g.
             "/$VAR/foo").
+        squash:
+            Squash the layers of the image into one, or the layers above the
+            image the container was created from with "from" or "import".
+            Squashing drops the files that were deleted by later operations.
+        history:
+            How to describe the layers of the image in its history.
 
         Returns
         -------
@@ -794,6 +1342,8 @@ class Container(Type):
             Arg("forcedCompression", forced_compression, None),
             Arg("mediaTypes", media_types, ImageMediaTypes.OCIMediaTypes),
             Arg("expand", expand, False),
+            Arg("squash", squash, ImageLayerSquash.NONE),
+            Arg("history", history, ImageHistory.OPERATIONS),
         ]
         _ctx = self._select("export", _args)
         return await _ctx.execute(str)
@@ -916,9 +1466,14 @@ class Container(Type):
         source: "File",
         *,
         tag: str | None = "",
+        digest: str | None = "",
     ) -> Self:
         """Reads the container from an OCI tarball.
 
+        Docker archives, like the ones produced by "docker save", are also
+        supported. The image is picked for the container's platform, unless a
+        single image manifest is selected.
+
         Parameters
         ----------
         source:
@@ -926,14 +1481,107 @@ class Container(Type):
         tag:
             Identifies the tag to import from the archive, if the archive
             bundles multiple tags.
+            Either the tag only (e.g., "latest") or the image reference (e.g.,
+            "alpine:latest").
+        digest:
+            Identifies the digest of the image manifest or index to import
+            from the archive.
         """
         _args = [
             Arg("source", source),
             Arg("tag", tag, ""),
+            Arg("digest", digest, ""),
         ]
         _ctx = self._select("import", _args)
         return Container(_ctx)
 
+    def import_directory(
+        self,
+        source: "Directory",
+        *,
+        tag: str | None = "",
+        digest: str | None = "",
+    ) -> Self:
+        """Reads the container from a directory in OCI image layout.
+
+        The image is picked for the container's platform, unless a single
+        image manifest is selected.
+
+        Parameters
+        ----------
+        source:
+            Directory in OCI image layout to read the container from.
+        tag:
+            Identifies the tag to import from the layout, if the layout
+            bundles multiple tags.
+            Either the tag only (e.g., "latest") or the image reference (e.g.,
+            "alpine:latest").
+        digest:
+            Identifies the digest of the image manifest or index to import
+            from the layout.
+        """
+        _args = [
+            Arg("source", source),
+            Arg("tag", tag, ""),
+            Arg("digest", digest, ""),
+        ]
+        _ctx = self._select("importDirectory", _args)
+        return Container(_ctx)
+
+    async def import_variants(
+        self,
+        *,
+        source: "File | None" = None,
+        directory: "Directory | None" = None,
+        tag: str | None = "",
+        digest: str | None = "",
+    ) -> list["Container"]:
+        """Reads every platform variant of an image from an OCI tarball or a
+        directory in OCI image layout.
+
+        The variants can be exported back as a multi-platform image with
+        platformVariants.
+
+        Parameters
+        ----------
+        source:
+            File to read the variants from. Exactly one of source or directory
+            must be set.
+        directory:
+            Directory in OCI image layout to read the variants from.
+        tag:
+            Identifies the tag to import from the archive, if the archive
+            bundles multiple tags.
+            Either the tag only (e.g., "latest") or the image reference (e.g.,
+            "alpine:latest").
+        digest:
+            Identifies the digest of the image index to import from the
+            archive.
+        """
+        _args = [
+            Arg("source", source, None),
+            Arg("directory", directory, None),
+            Arg("tag", tag, ""),
+            Arg("digest", digest, ""),
+        ]
+        _ctx = self._select("importVariants", _args)
+        _ctx = Container(_ctx)._select("id", [])
+
+        @dataclass
+        class Response:
+            id: ContainerID
+
+        _ids = await _ctx.execute(list[Response])
+        return [
+            Container(
+                Client.from_context(_ctx)._select(
+                    "loadContainerFromID",
+                    [Arg("id", v.id)],
+                )
+            )
+            for v in _ids
+        ]
+
     async def label(self, name: str) -> str | None:
         """Retrieves the value of the specified label.
 
@@ -1033,6 +1681,12 @@ class Container(Type):
         platform_variants: "list[Container] | None" = None,
         forced_compression: ImageLayerCompression | None = None,
         media_types: ImageMediaTypes | None = ImageMediaTypes.OCIMediaTypes,
+        squash: ImageLayerSquash | None = ImageLayerSquash.NONE,
+        history: ImageHistory | None = ImageHistory.OPERATIONS,
+        signing_key: "Secret | None" = None,
+        signing_key_password: "Secret | None" = None,
+        sbom: bool | None = False,
+        provenance: bool | None = False,
     ) -> str:
         """Publishes this container as a new image to the specified address.
 
@@ -1062,6 +1716,23 @@ class Container(Type):
             Defaults to OCI, which is largely compatible with most recent
             registries, but Docker may be needed for older registries without
             OCI support.
+        squash:
+            Squash the layers of the image into one, or the layers above the
+            image the container was created from with "from" or "import".
+            Squashing drops the files that were deleted by later operations.
+        history:
+            How to describe the layers of the image in its history.
+        signing_key:
+            A private key to sign the image with, in PEM format (e.g.,
+            generated by "cosign generate-key-pair").
+            The signature is compatible with "cosign verify".
+        signing_key_password:
+            The password of the signing key, if it is encrypted.
+        sbom:
+            Attach an SPDX SBOM of the image's filesystem as an attestation.
+        provenance:
+            Attach a SLSA provenance, recording the container's call ID, as an
+            attestation.
 
         Returns
         -------
@@ -1086,6 +1757,12 @@ class Container(Type):
             ),
             Arg("forcedCompression", forced_compression, None),
             Arg("mediaTypes", media_types, ImageMediaTypes.OCIMediaTypes),
+            Arg("squash", squash, ImageLayerSquash.NONE),
+            Arg("history", history, ImageHistory.OPERATIONS),
+            Arg("signingKey", signing_key, None),
+            Arg("signingKeyPassword", signing_key_password, None),
+            Arg("sbom", sbom, False),
+            Arg("provenance", provenance, False),
         ]
         _ctx = self._select("publish", _args)
         return await _ctx.execute(str)
@@ -1214,6 +1891,14 @@ class Container(Type):
         insecure_root_capabilities: bool | None = False,
         expand: bool | None = False,
         no_init: bool | None = False,
+        timeout: int | None = 0,
+        cpu_limit: float | None = 0,
+        memory_limit: int | None = 0,
+        pids_limit: int | None = 0,
+        healthcheck_exec: list[str] | None = None,
+        healthcheck_interval: int | None = 0,
+        healthcheck_timeout: int | None = 0,
+        healthcheck_retries: int | None = 0,
     ) -> Void | None:
         """Starts a Service and creates a tunnel that forwards traffic from the
         caller's network to that service.
@@ -1254,6 +1939,29 @@ class Container(Type):
             This should only be used if the user requires that their exec
             process be the pid 1 process in the container. Otherwise it may
             result in unexpected behavior.
+        timeout:
+            Number of seconds the service may run for before it's killed.
+        cpu_limit:
+            Number of CPUs the service may use (e.g., 0.5).
+        memory_limit:
+            Memory in bytes the service may use before it's killed (e.g.,
+            536870912 for 512 MiB).
+        pids_limit:
+            Number of processes the service may have running at once.
+        healthcheck_exec:
+            Command to run in the service container to check that it's healthy
+            (e.g., ["pg_isready"]).
+            It runs once the exposed ports are reachable, and the service is
+            healthy once it exits successfully.
+        healthcheck_interval:
+            Number of seconds to wait between health check attempts.
+            If unset, attempts back off exponentially.
+        healthcheck_timeout:
+            Number of seconds each health check attempt may take.
+        healthcheck_retries:
+            Number of times to retry a failing health check before the service
+            fails to start.
+            If unset, health checks are retried for up to 15 minutes.
 
         Returns
         -------
@@ -1279,6 +1987,18 @@ class Container(Type):
             Arg("insecureRootCapabilities", insecure_root_capabilities, False),
             Arg("expand", expand, False),
             Arg("noInit", no_init, False),
+            Arg("timeout", timeout, 0),
+            Arg("cpuLimit", cpu_limit, 0),
+            Arg("memoryLimit", memory_limit, 0),
+            Arg("pidsLimit", pids_limit, 0),
+            Arg(
+                "healthcheckExec",
+                () if healthcheck_exec is None else healthcheck_exec,
+                (),
+            ),
+            Arg("healthcheckInterval", healthcheck_interval, 0),
+            Arg("healthcheckTimeout", healthcheck_timeout, 0),
+            Arg("healthcheckRetries", healthcheck_retries, 0),
         ]
         _ctx = self._select("up", _args)
         await _ctx.execute()
@@ -1479,6 +2199,11 @@ class Container(Type):
         insecure_root_capabilities: bool | None = False,
         expand: bool | None = False,
         no_init: bool | None = False,
+        network_mode: NetworkMode | None = NetworkMode.DEFAULT,
+        timeout: int | None = 0,
+        cpu_limit: float | None = 0,
+        memory_limit: int | None = 0,
+        pids_limit: int | None = 0,
     ) -> Self:
         """Retrieves this container after executing the specified command inside
         it.
@@ -1522,6 +2247,22 @@ class Container(Type):
             This should only be used if the user requires that their exec
             process be the pid 1 process in the container. Otherwise it may
             result in unexpected behavior.
+        network_mode:
+            Network access granted to the command.
+            With SERVICES_ONLY, connections to anything but bound services and
+            the engine's DNS server fail immediately.
+        timeout:
+            Number of seconds the command may run for before it's killed.
+            A command that times out fails with an ExecError whose reason is
+            TIMEOUT.
+        cpu_limit:
+            Number of CPUs the command may use (e.g., 0.5).
+        memory_limit:
+            Memory in bytes the command may use (e.g., 536870912 for 512 MiB).
+            A command that exceeds it is killed and fails with an ExecError
+            whose reason is OOM_KILLED.
+        pids_limit:
+            Number of processes the command may have running at once.
         """
         _args = [
             Arg("args", args),
@@ -1536,6 +2277,11 @@ class Container(Type):
             Arg("insecureRootCapabilities", insecure_root_capabilities, False),
             Arg("expand", expand, False),
             Arg("noInit", no_init, False),
+            Arg("networkMode", network_mode, NetworkMode.DEFAULT),
+            Arg("timeout", timeout, 0),
+            Arg("cpuLimit", cpu_limit, 0),
+            Arg("memoryLimit", memory_limit, 0),
+            Arg("pidsLimit", pids_limit, 0),
         ]
         _ctx = self._select("withExec", _args)
         return Container(_ctx)
@@ -1547,6 +2293,9 @@ class Container(Type):
         protocol: NetworkProtocol | None = NetworkProtocol.TCP,
         description: str | None = None,
         experimental_skip_healthcheck: bool | None = False,
+        healthcheck_path: str | None = "",
+        healthcheck_scheme: HealthcheckScheme | None = None,
+        healthcheck_status: int | None = 0,
     ) -> Self:
         """Expose a network port.
 
@@ -1566,12 +2315,25 @@ class Container(Type):
             Optional port description
         experimental_skip_healthcheck:
             Skip the health check when run as a service.
+        healthcheck_path:
+            Check the port's health with an HTTP GET request for this path,
+            rather than just connecting to it (e.g., "/healthz").
+        healthcheck_scheme:
+            Scheme of the health check request. If unset, HTTP is used.
+            Requires healthcheckPath.
+        healthcheck_status:
+            Status code the health check request must respond with.
+            If unset, any 2xx or 3xx status is accepted. Requires
+            healthcheckPath.
         """
         _args = [
             Arg("port", port),
             Arg("protocol", protocol, NetworkProtocol.TCP),
             Arg("description", description, None),
             Arg("experimentalSkipHealthcheck", experimental_skip_healthcheck, False),
+            Arg("healthcheckPath", healthcheck_path, ""),
+            Arg("healthcheckScheme", healthcheck_scheme, None),
+            Arg("healthcheckStatus", healthcheck_status, 0),
         ]
         _ctx = self._select("withExposedPort", _args)
         return Container(_ctx)
@@ -2463,6 +3225,35 @@ class CurrentModule(Type):
 class Directory(Type):
     """A directory."""
 
+    def as_compose(
+        self,
+        *,
+        path: str | None = "",
+        project_name: str | None = "",
+    ) -> ComposeProject:
+        """Load a Compose project from a Compose file in this directory.
+
+        Paths in the Compose file, such as build contexts and bind mounts, are
+        resolved relative to the Compose file's directory. Variables are
+        interpolated from a .env file next to the Compose file, if any.
+
+        Parameters
+        ----------
+        path:
+            Path of the Compose file. If unset, the first of "compose.yaml",
+            "compose.yml", "docker-compose.yaml" and "docker-compose.yml" that
+            exists is used.
+        project_name:
+            Name of the project, which named volumes are scoped to. If unset,
+            the name set in the Compose file is used, or "default".
+        """
+        _args = [
+            Arg("path", path, ""),
+            Arg("projectName", project_name, ""),
+        ]
+        _ctx = self._select("asCompose", _args)
+        return ComposeProject(_ctx)
+
     def as_module(
         self,
         *,
@@ -2486,11 +3277,26 @@ class Directory(Type):
             The engine version to upgrade to.
         """
         _args = [
-            Arg("sourceRootPath", source_root_path, "."),
-            Arg("engineVersion", engine_version, None),
+            Arg("sourceRootPath", source_root_path, "."),
+            Arg("engineVersion", engine_version, None),
+        ]
+        _ctx = self._select("asModule", _args)
+        return Module(_ctx)
+
+    def as_patch(self, other: Self) -> "File":
+        """Returns a unified diff, in the format of "git diff", of the files
+        changed between this directory and another directory.
+
+        Parameters
+        ----------
+        other:
+            Identifier of the directory to compare.
+        """
+        _args = [
+            Arg("other", other),
         ]
-        _ctx = self._select("asModule", _args)
-        return Module(_ctx)
+        _ctx = self._select("asPatch", _args)
+        return File(_ctx)
 
     def diff(self, other: Self) -> Self:
         """Gets the difference between this directory and an another directory.
@@ -2551,6 +3357,10 @@ class Directory(Type):
         target: str | None = "",
         build_args: list[BuildArg] | None = None,
         secrets: "list[Secret] | None" = None,
+        build_contexts: list[BuildContext] | None = None,
+        ssh: "Socket | None" = None,
+        no_cache_filter: list[str] | None = None,
+        cache_from: list[str] | None = None,
     ) -> Container:
         """Builds a new Docker container from this directory.
 
@@ -2567,6 +3377,18 @@ class Directory(Type):
         secrets:
             Secrets to pass to the build.
             They will be mounted at /run/secrets/[secret-name].
+        build_contexts:
+            Named build contexts to pass to the build.
+            They can be referenced by name in the Dockerfile (e.g., "COPY
+            --from=<name>" or "FROM <name>").
+        ssh:
+            A socket to forward to the build as the default SSH agent.
+            It is available to "RUN --mount=type=ssh" instructions.
+        no_cache_filter:
+            Build stages to build without using the cache.
+        cache_from:
+            Registry references to import the build cache from (e.g.,
+            "registry.example.com/app:cache").
         """
         _args = [
             Arg("platform", platform, None),
@@ -2574,6 +3396,12 @@ class Directory(Type):
             Arg("target", target, ""),
             Arg("buildArgs", () if build_args is None else build_args, ()),
             Arg("secrets", () if secrets is None else secrets, ()),
+            Arg("buildContexts", () if build_contexts is None else build_contexts, ()),
+            Arg("ssh", ssh, None),
+            Arg(
+                "noCacheFilter", () if no_cache_filter is None else no_cache_filter, ()
+            ),
+            Arg("cacheFrom", () if cache_from is None else cache_from, ()),
         ]
         _ctx = self._select("dockerBuild", _args)
         return Container(_ctx)
@@ -2714,6 +3542,24 @@ class Directory(Type):
         _ctx = self._select("id", _args)
         return await _ctx.execute(DirectoryID)
 
+    def merge(self, directories: list["Directory"]) -> Self:
+        """Retrieves this directory with the contents of the given directories
+        merged into it.
+
+        Fails if the same path holds different contents in more than one of
+        the directories, instead of overwriting it.
+
+        Parameters
+        ----------
+        directories:
+            Identifiers of the directories to merge, in order.
+        """
+        _args = [
+            Arg("directories", directories),
+        ]
+        _ctx = self._select("merge", _args)
+        return Directory(_ctx)
+
     async def sync(self) -> Self:
         """Force evaluation in the engine.
 
@@ -2911,6 +3757,23 @@ class Directory(Type):
         _ctx = self._select("withNewFile", _args)
         return Directory(_ctx)
 
+    def with_patch(self, patch: "File") -> Self:
+        """Retrieves this directory with the given unified diff applied.
+
+        Fails if any hunk of the patch does not apply cleanly.
+
+        Parameters
+        ----------
+        patch:
+            File containing the patch to apply, as produced by "diff -u" or
+            "git diff".
+        """
+        _args = [
+            Arg("patch", patch),
+        ]
+        _ctx = self._select("withPatch", _args)
+        return Directory(_ctx)
+
     def with_timestamps(self, timestamp: int) -> Self:
         """Retrieves this directory with all file/dir timestamps set to the given
         time.
@@ -3791,6 +4654,24 @@ class FieldTypeDef(Type):
 class File(Type):
     """A file."""
 
+    def as_compose(self, *, project_name: str | None = "") -> ComposeProject:
+        """Load a Compose project from this Compose file.
+
+        Services that are built or that bind mount paths can only be loaded
+        from a directory with Directory.asCompose.
+
+        Parameters
+        ----------
+        project_name:
+            Name of the project, which named volumes are scoped to. If unset,
+            the name set in the Compose file is used, or "default".
+        """
+        _args = [
+            Arg("projectName", project_name, ""),
+        ]
+        _ctx = self._select("asCompose", _args)
+        return ComposeProject(_ctx)
+
     async def contents(self) -> str:
         """Retrieves the contents of the file.
 
@@ -4463,9 +5344,236 @@ class FunctionCall(Type):
 
         Returns
         -------
-        Void | None
-            The absence of a value.  A Null Void is used as a placeholder for
-            resolvers that do not return anything.
+        Void | None
+            The absence of a value.  A Null Void is used as a placeholder for
+            resolvers that do not return anything.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args = [
+            Arg("error", error),
+        ]
+        _ctx = self._select("returnError", _args)
+        await _ctx.execute()
+
+    async def return_value(self, value: JSON) -> Void | None:
+        """Set the return value of the function call to the provided value.
+
+        Parameters
+        ----------
+        value:
+            JSON serialization of the return value.
+
+        Returns
+        -------
+        Void | None
+            The absence of a value.  A Null Void is used as a placeholder for
+            resolvers that do not return anything.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args = [
+            Arg("value", value),
+        ]
+        _ctx = self._select("returnValue", _args)
+        await _ctx.execute()
+
+
+@typecheck
+class FunctionCallArgValue(Type):
+    """A value passed as a named argument to a function call."""
+
+    async def id(self) -> FunctionCallArgValueID:
+        """A unique identifier for this FunctionCallArgValue.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        FunctionCallArgValueID
+            The `FunctionCallArgValueID` scalar type represents an identifier
+            for an object of type FunctionCallArgValue.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(FunctionCallArgValueID)
+
+    async def name(self) -> str:
+        """The name of the argument.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("name", _args)
+        return await _ctx.execute(str)
+
+    async def value(self) -> JSON:
+        """The value of the argument represented as a JSON serialized string.
+
+        Returns
+        -------
+        JSON
+            An arbitrary JSON-encoded value.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("value", _args)
+        return await _ctx.execute(JSON)
+
+
+@typecheck
+class GeneratedCode(Type):
+    """The result of running an SDK's codegen."""
+
+    def code(self) -> Directory:
+        """The directory containing the generated code."""
+        _args: list[Arg] = []
+        _ctx = self._select("code", _args)
+        return Directory(_ctx)
+
+    async def id(self) -> GeneratedCodeID:
+        """A unique identifier for this GeneratedCode.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        GeneratedCodeID
+            The `GeneratedCodeID` scalar type represents an identifier for an
+            object of type GeneratedCode.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(GeneratedCodeID)
+
+    async def vcs_generated_paths(self) -> list[str]:
+        """List of paths to mark generated in version control (i.e.
+        .gitattributes).
+
+        Returns
+        -------
+        list[str]
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("vcsGeneratedPaths", _args)
+        return await _ctx.execute(list[str])
+
+    async def vcs_ignored_paths(self) -> list[str]:
+        """List of paths to ignore in version control (i.e. .gitignore).
+
+        Returns
+        -------
+        list[str]
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("vcsIgnoredPaths", _args)
+        return await _ctx.execute(list[str])
+
+    def with_vcs_generated_paths(self, paths: list[str]) -> Self:
+        """Set the list of paths to mark generated in version control."""
+        _args = [
+            Arg("paths", paths),
+        ]
+        _ctx = self._select("withVCSGeneratedPaths", _args)
+        return GeneratedCode(_ctx)
+
+    def with_vcs_ignored_paths(self, paths: list[str]) -> Self:
+        """Set the list of paths to ignore in version control."""
+        _args = [
+            Arg("paths", paths),
+        ]
+        _ctx = self._select("withVCSIgnoredPaths", _args)
+        return GeneratedCode(_ctx)
+
+    def with_(
+        self, cb: Callable[["GeneratedCode"], "GeneratedCode"]
+    ) -> "GeneratedCode":
+        """Call the provided callable with current GeneratedCode.
+
+        This is useful for reusability and readability by not breaking the calling chain.
+        """
+        return cb(self)
+
+
+@typecheck
+class GitActor(Type):
+    """The author or committer of a git commit."""
+
+    async def date(self) -> str:
+        """The date of the action, in RFC 3339 format.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
 
         Raises
         ------
@@ -4474,25 +5582,19 @@ class FunctionCall(Type):
         QueryError
             If the API returns an error.
         """
-        _args = [
-            Arg("error", error),
-        ]
-        _ctx = self._select("returnError", _args)
-        await _ctx.execute()
-
-    async def return_value(self, value: JSON) -> Void | None:
-        """Set the return value of the function call to the provided value.
+        _args: list[Arg] = []
+        _ctx = self._select("date", _args)
+        return await _ctx.execute(str)
 
-        Parameters
-        ----------
-        value:
-            JSON serialization of the return value.
+    async def email(self) -> str:
+        """The email address of the person.
 
         Returns
         -------
-        Void | None
-            The absence of a value.  A Null Void is used as a placeholder for
-            resolvers that do not return anything.
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
 
         Raises
         ------
@@ -4501,19 +5603,12 @@ class FunctionCall(Type):
         QueryError
             If the API returns an error.
         """
-        _args = [
-            Arg("value", value),
-        ]
-        _ctx = self._select("returnValue", _args)
-        await _ctx.execute()
-
-
-@typecheck
-class FunctionCallArgValue(Type):
-    """A value passed as a named argument to a function call."""
+        _args: list[Arg] = []
+        _ctx = self._select("email", _args)
+        return await _ctx.execute(str)
 
-    async def id(self) -> FunctionCallArgValueID:
-        """A unique identifier for this FunctionCallArgValue.
+    async def id(self) -> GitActorID:
+        """A unique identifier for this GitActor.
 
         Note
         ----
@@ -4521,9 +5616,9 @@ class FunctionCallArgValue(Type):
 
         Returns
         -------
-        FunctionCallArgValueID
-            The `FunctionCallArgValueID` scalar type represents an identifier
-            for an object of type FunctionCallArgValue.
+        GitActorID
+            The `GitActorID` scalar type represents an identifier for an
+            object of type GitActor.
 
         Raises
         ------
@@ -4534,10 +5629,10 @@ class FunctionCallArgValue(Type):
         """
         _args: list[Arg] = []
         _ctx = self._select("id", _args)
-        return await _ctx.execute(FunctionCallArgValueID)
+        return await _ctx.execute(GitActorID)
 
     async def name(self) -> str:
-        """The name of the argument.
+        """The name of the person.
 
         Returns
         -------
@@ -4557,13 +5652,32 @@ class FunctionCallArgValue(Type):
         _ctx = self._select("name", _args)
         return await _ctx.execute(str)
 
-    async def value(self) -> JSON:
-        """The value of the argument represented as a JSON serialized string.
+
+@typecheck
+class GitCommitInfo(Type):
+    """The metadata of a git commit."""
+
+    def author(self) -> GitActor:
+        """The author of the commit."""
+        _args: list[Arg] = []
+        _ctx = self._select("author", _args)
+        return GitActor(_ctx)
+
+    def committer(self) -> GitActor:
+        """The committer of the commit."""
+        _args: list[Arg] = []
+        _ctx = self._select("committer", _args)
+        return GitActor(_ctx)
+
+    async def date(self) -> str:
+        """The date the commit was authored, in RFC 3339 format.
 
         Returns
         -------
-        JSON
-            An arbitrary JSON-encoded value.
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
 
         Raises
         ------
@@ -4573,22 +5687,11 @@ class FunctionCallArgValue(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("value", _args)
-        return await _ctx.execute(JSON)
-
-
-@typecheck
-class GeneratedCode(Type):
-    """The result of running an SDK's codegen."""
-
-    def code(self) -> Directory:
-        """The directory containing the generated code."""
-        _args: list[Arg] = []
-        _ctx = self._select("code", _args)
-        return Directory(_ctx)
+        _ctx = self._select("date", _args)
+        return await _ctx.execute(str)
 
-    async def id(self) -> GeneratedCodeID:
-        """A unique identifier for this GeneratedCode.
+    async def id(self) -> GitCommitInfoID:
+        """A unique identifier for this GitCommitInfo.
 
         Note
         ----
@@ -4596,9 +5699,9 @@ class GeneratedCode(Type):
 
         Returns
         -------
-        GeneratedCodeID
-            The `GeneratedCodeID` scalar type represents an identifier for an
-            object of type GeneratedCode.
+        GitCommitInfoID
+            The `GitCommitInfoID` scalar type represents an identifier for an
+            object of type GitCommitInfo.
 
         Raises
         ------
@@ -4609,15 +5712,14 @@ class GeneratedCode(Type):
         """
         _args: list[Arg] = []
         _ctx = self._select("id", _args)
-        return await _ctx.execute(GeneratedCodeID)
+        return await _ctx.execute(GitCommitInfoID)
 
-    async def vcs_generated_paths(self) -> list[str]:
-        """List of paths to mark generated in version control (i.e.
-        .gitattributes).
+    async def message(self) -> str:
+        """The full message of the commit.
 
         Returns
         -------
-        list[str]
+        str
             The `String` scalar type represents textual data, represented as
             UTF-8 character sequences. The String type is most often used by
             GraphQL to represent free-form human-readable text.
@@ -4630,11 +5732,11 @@ class GeneratedCode(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("vcsGeneratedPaths", _args)
-        return await _ctx.execute(list[str])
+        _ctx = self._select("message", _args)
+        return await _ctx.execute(str)
 
-    async def vcs_ignored_paths(self) -> list[str]:
-        """List of paths to ignore in version control (i.e. .gitignore).
+    async def parents(self) -> list[str]:
+        """The SHAs of the parents of the commit.
 
         Returns
         -------
@@ -4651,33 +5753,29 @@ class GeneratedCode(Type):
             If the API returns an error.
         """
         _args: list[Arg] = []
-        _ctx = self._select("vcsIgnoredPaths", _args)
+        _ctx = self._select("parents", _args)
         return await _ctx.execute(list[str])
 
-    def with_vcs_generated_paths(self, paths: list[str]) -> Self:
-        """Set the list of paths to mark generated in version control."""
-        _args = [
-            Arg("paths", paths),
-        ]
-        _ctx = self._select("withVCSGeneratedPaths", _args)
-        return GeneratedCode(_ctx)
-
-    def with_vcs_ignored_paths(self, paths: list[str]) -> Self:
-        """Set the list of paths to ignore in version control."""
-        _args = [
-            Arg("paths", paths),
-        ]
-        _ctx = self._select("withVCSIgnoredPaths", _args)
-        return GeneratedCode(_ctx)
+    async def sha(self) -> str:
+        """The SHA of the commit.
 
-    def with_(
-        self, cb: Callable[["GeneratedCode"], "GeneratedCode"]
-    ) -> "GeneratedCode":
-        """Call the provided callable with current GeneratedCode.
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
 
-        This is useful for reusability and readability by not breaking the calling chain.
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
         """
-        return cb(self)
+        _args: list[Arg] = []
+        _ctx = self._select("sha", _args)
+        return await _ctx.execute(str)
 
 
 @typecheck
@@ -4892,6 +5990,38 @@ class GitRef(Type):
         _ctx = self._select("commit", _args)
         return await _ctx.execute(str)
 
+    def commit_info(self) -> GitCommitInfo:
+        """The metadata of the resolved commit at this ref."""
+        _args: list[Arg] = []
+        _ctx = self._select("commitInfo", _args)
+        return GitCommitInfo(_ctx)
+
+    async def describe(self) -> str:
+        """The nearest tag reachable from this ref, as returned by "git describe
+        --tags".
+
+        If the tag doesn't point at the ref itself, it's suffixed with the
+        number of commits since the tag and the abbreviated commit id (e.g.,
+        "v0.3.9-4-gb6315d8").
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("describe", _args)
+        return await _ctx.execute(str)
+
     async def id(self) -> GitRefID:
         """A unique identifier for this GitRef.
 
@@ -4916,10 +6046,21 @@ class GitRef(Type):
         _ctx = self._select("id", _args)
         return await _ctx.execute(GitRefID)
 
+    def signature(self) -> "GitSignature":
+        """The signature of this ref, if it was verified by "verify"."""
+        _args: list[Arg] = []
+        _ctx = self._select("signature", _args)
+        return GitSignature(_ctx)
+
     def tree(
         self,
         *,
         discard_git_dir: bool | None = False,
+        sparse_checkout: list[str] | None = None,
+        depth: int | None = 1,
+        full_history: bool | None = False,
+        skip_submodules: bool | None = False,
+        lfs: bool | None = False,
     ) -> Directory:
         """The filesystem tree at this ref.
 
@@ -4927,13 +6068,68 @@ class GitRef(Type):
         ----------
         discard_git_dir:
             Set to true to discard .git directory.
+        sparse_checkout:
+            Only check out the paths matching these patterns, which use the
+            syntax of .gitignore files (e.g., ["/docs/", "*.md"]).
+        depth:
+            The number of commits of history to fetch into the .git directory.
+        full_history:
+            Set to true to fetch the full history and tags into the .git
+            directory, e.g. for "git describe". Overrides depth.
+        skip_submodules:
+            Set to true to not check out submodules. By default, submodules
+            are checked out recursively.
+        lfs:
+            Set to true to replace Git LFS pointer files with the content they
+            point to. LFS objects are cached by the engine, so they're only
+            downloaded once.
         """
         _args = [
             Arg("discardGitDir", discard_git_dir, False),
+            Arg(
+                "sparseCheckout", () if sparse_checkout is None else sparse_checkout, ()
+            ),
+            Arg("depth", depth, 1),
+            Arg("fullHistory", full_history, False),
+            Arg("skipSubmodules", skip_submodules, False),
+            Arg("lfs", lfs, False),
         ]
         _ctx = self._select("tree", _args)
         return Directory(_ctx)
 
+    def verify(
+        self,
+        *,
+        keys: list[File] | None = None,
+        allowed_signers: File | None = None,
+    ) -> Self:
+        """Verify the GPG or SSH signature of the annotated tag or commit at this
+        ref, failing if it's missing or not made by one of the trusted keys.
+
+        Returns this ref pinned to the verified commit.
+
+        Parameters
+        ----------
+        keys:
+            GPG public keys trusted to sign the ref.
+        allowed_signers:
+            SSH allowed signers file trusted to sign the ref, in the format
+            read by "ssh-keygen -Y verify".
+        """
+        _args = [
+            Arg("keys", () if keys is None else keys, ()),
+            Arg("allowedSigners", allowed_signers, None),
+        ]
+        _ctx = self._select("verify", _args)
+        return GitRef(_ctx)
+
+    def with_(self, cb: Callable[["GitRef"], "GitRef"]) -> "GitRef":
+        """Call the provided callable with current GitRef.
+
+        This is useful for reusability and readability by not breaking the calling chain.
+        """
+        return cb(self)
+
 
 @typecheck
 class GitRepository(Type):
@@ -4998,6 +6194,48 @@ class GitRepository(Type):
         _ctx = self._select("id", _args)
         return await _ctx.execute(GitRepositoryID)
 
+    async def log(
+        self,
+        *,
+        from_: str | None = "",
+        to: str | None = "",
+        paths: list[str] | None = None,
+    ) -> list[GitCommitInfo]:
+        """Returns the commits reachable from a ref, newest first.
+
+        Parameters
+        ----------
+        from_:
+            Exclude the commits reachable from this ref (e.g., "v0.3.8"). If
+            empty, the whole history is returned.
+        to:
+            Ref to list the history of (e.g., "main"). If empty, HEAD is used.
+        paths:
+            Only return commits that change one of these paths.
+        """
+        _args = [
+            Arg("from", from_, ""),
+            Arg("to", to, ""),
+            Arg("paths", () if paths is None else paths, ()),
+        ]
+        _ctx = self._select("log", _args)
+        _ctx = GitCommitInfo(_ctx)._select("id", [])
+
+        @dataclass
+        class Response:
+            id: GitCommitInfoID
+
+        _ids = await _ctx.execute(list[Response])
+        return [
+            GitCommitInfo(
+                Client.from_context(_ctx)._select(
+                    "loadGitCommitInfoFromID",
+                    [Arg("id", v.id)],
+                )
+            )
+            for v in _ids
+        ]
+
     def ref(self, name: str) -> GitRef:
         """Returns details of a ref.
 
@@ -5082,20 +6320,113 @@ class GitRepository(Type):
             Secret used to populate the password during basic HTTP
             Authorization
         """
-        _args = [
-            Arg("token", token),
-        ]
-        _ctx = self._select("withAuthToken", _args)
-        return GitRepository(_ctx)
+        _args = [
+            Arg("token", token),
+        ]
+        _ctx = self._select("withAuthToken", _args)
+        return GitRepository(_ctx)
+
+    def with_(
+        self, cb: Callable[["GitRepository"], "GitRepository"]
+    ) -> "GitRepository":
+        """Call the provided callable with current GitRepository.
+
+        This is useful for reusability and readability by not breaking the calling chain.
+        """
+        return cb(self)
+
+
+@typecheck
+class GitSignature(Type):
+    """A verified signature of a git tag or commit."""
+
+    async def format(self) -> str:
+        """The format of the signature, "gpg" or "ssh".
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("format", _args)
+        return await _ctx.execute(str)
+
+    async def id(self) -> GitSignatureID:
+        """A unique identifier for this GitSignature.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        GitSignatureID
+            The `GitSignatureID` scalar type represents an identifier for an
+            object of type GitSignature.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(GitSignatureID)
+
+    async def key(self) -> str:
+        """The fingerprint of the signing key.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("key", _args)
+        return await _ctx.execute(str)
 
-    def with_(
-        self, cb: Callable[["GitRepository"], "GitRepository"]
-    ) -> "GitRepository":
-        """Call the provided callable with current GitRepository.
+    async def signer(self) -> str:
+        """The identity of the signer: the user ID of the GPG key, or the
+        principal of the SSH key in the allowed signers.
 
-        This is useful for reusability and readability by not breaking the calling chain.
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
         """
-        return cb(self)
+        _args: list[Arg] = []
+        _ctx = self._select("signer", _args)
+        return await _ctx.execute(str)
 
 
 @typecheck
@@ -5265,6 +6596,182 @@ class Host(Type):
         return Socket(_ctx)
 
 
+@typecheck
+class ImageConfig(Type):
+    """The config of an image in a registry."""
+
+    async def default_args(self) -> list[str]:
+        """The default arguments of the image's entrypoint.
+
+        Returns
+        -------
+        list[str]
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("defaultArgs", _args)
+        return await _ctx.execute(list[str])
+
+    async def entrypoint(self) -> list[str]:
+        """The entrypoint of the image.
+
+        Returns
+        -------
+        list[str]
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("entrypoint", _args)
+        return await _ctx.execute(list[str])
+
+    async def env_variables(self) -> list[EnvVariable]:
+        """The environment variables of the image."""
+        _args: list[Arg] = []
+        _ctx = self._select("envVariables", _args)
+        _ctx = EnvVariable(_ctx)._select("id", [])
+
+        @dataclass
+        class Response:
+            id: EnvVariableID
+
+        _ids = await _ctx.execute(list[Response])
+        return [
+            EnvVariable(
+                Client.from_context(_ctx)._select(
+                    "loadEnvVariableFromID",
+                    [Arg("id", v.id)],
+                )
+            )
+            for v in _ids
+        ]
+
+    async def id(self) -> ImageConfigID:
+        """A unique identifier for this ImageConfig.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        ImageConfigID
+            The `ImageConfigID` scalar type represents an identifier for an
+            object of type ImageConfig.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(ImageConfigID)
+
+    async def labels(self) -> list["Label"]:
+        """The labels of the image, sorted by name."""
+        _args: list[Arg] = []
+        _ctx = self._select("labels", _args)
+        _ctx = Label(_ctx)._select("id", [])
+
+        @dataclass
+        class Response:
+            id: LabelID
+
+        _ids = await _ctx.execute(list[Response])
+        return [
+            Label(
+                Client.from_context(_ctx)._select(
+                    "loadLabelFromID",
+                    [Arg("id", v.id)],
+                )
+            )
+            for v in _ids
+        ]
+
+    async def platform(self) -> Platform:
+        """The platform of the image.
+
+        Returns
+        -------
+        Platform
+            The platform config OS and architecture in a Container.  The
+            format is [os]/[platform]/[version] (e.g., "darwin/arm64/v7",
+            "windows/amd64", "linux/arm64").
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("platform", _args)
+        return await _ctx.execute(Platform)
+
+    async def user(self) -> str:
+        """The user of the image.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("user", _args)
+        return await _ctx.execute(str)
+
+    async def workdir(self) -> str:
+        """The working directory of the image.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("workdir", _args)
+        return await _ctx.execute(str)
+
+
 @typecheck
 class InputTypeDef(Type):
     """A graphql input type, which is essentially just a group of named
@@ -7037,6 +8544,37 @@ class Client(Root):
         _ctx = self._select("error", _args)
         return Error(_ctx)
 
+    def explain_cache_miss(
+        self,
+        digest: str,
+        *,
+        previous_digest: str | None = "",
+    ) -> CacheMissExplanation:
+        """Explains why a call missed the cache, by comparing it to the same call
+        in a previous run.
+
+        Calls are looked up in the telemetry the engine keeps of its recent
+        clients. The first difference is reported, in the order the calls are
+        evaluated: the objects that calls are chained from first, then
+        arguments, then content.
+
+        Parameters
+        ----------
+        digest:
+            Digest of the call that missed the cache, as recorded in its span
+            (e.g., "xxh3:...").
+        previous_digest:
+            Digest of the call to compare to.
+            Defaults to the most recent call of the same fields in an earlier
+            run.
+        """
+        _args = [
+            Arg("digest", digest),
+            Arg("previousDigest", previous_digest, ""),
+        ]
+        _ctx = self._select("explainCacheMiss", _args)
+        return CacheMissExplanation(_ctx)
+
     def function(self, name: str, return_type: "TypeDef") -> Function:
         """Creates a function.
 
@@ -7113,6 +8651,12 @@ class Client(Root):
         url: str,
         *,
         experimental_service_host: "Service | None" = None,
+        checksum: str | None = None,
+        headers: list[HTTPHeader] | None = None,
+        auth_token: "Secret | None" = None,
+        auth_header: "Secret | None" = None,
+        name: str | None = None,
+        permissions: int | None = None,
     ) -> File:
         """Returns a file containing an http remote url content.
 
@@ -7122,14 +8666,45 @@ class Client(Root):
             HTTP url to get the content from (e.g., "https://docs.dagger.io").
         experimental_service_host:
             A service which must be started before the URL is fetched.
+        checksum:
+            Expected digest of the content (e.g., "sha256:..."). The fetch
+            fails if the content doesn't match, and is cached by the digest
+            without contacting the server.
+        headers:
+            Headers to send with the request.
+        auth_token:
+            Secret sent as a bearer token in the Authorization header.
+        auth_header:
+            Secret sent as the value of the Authorization header (e.g., "Basic
+            <base64 of user:password>"). Takes precedence over authToken.
+        name:
+            Name of the file. Defaults to a digest of the URL.
+        permissions:
+            Permissions of the file (e.g., 0755). Defaults to 0600.
         """
         _args = [
             Arg("url", url),
             Arg("experimentalServiceHost", experimental_service_host, None),
+            Arg("checksum", checksum, None),
+            Arg("headers", () if headers is None else headers, ()),
+            Arg("authToken", auth_token, None),
+            Arg("authHeader", auth_header, None),
+            Arg("name", name, None),
+            Arg("permissions", permissions, None),
         ]
         _ctx = self._select("http", _args)
         return File(_ctx)
 
+    def load_cache_miss_explanation_from_id(
+        self, id: CacheMissExplanationID
+    ) -> CacheMissExplanation:
+        """Load a CacheMissExplanation from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadCacheMissExplanationFromID", _args)
+        return CacheMissExplanation(_ctx)
+
     def load_cache_volume_from_id(self, id: CacheVolumeID) -> CacheVolume:
         """Load a CacheVolume from its ID."""
         _args = [
@@ -7138,6 +8713,14 @@ class Client(Root):
         _ctx = self._select("loadCacheVolumeFromID", _args)
         return CacheVolume(_ctx)
 
+    def load_compose_project_from_id(self, id: ComposeProjectID) -> ComposeProject:
+        """Load a ComposeProject from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadComposeProjectFromID", _args)
+        return ComposeProject(_ctx)
+
     def load_container_from_id(self, id: ContainerID) -> Container:
         """Load a Container from its ID."""
         _args = [
@@ -7290,6 +8873,22 @@ class Client(Root):
         _ctx = self._select("loadGeneratedCodeFromID", _args)
         return GeneratedCode(_ctx)
 
+    def load_git_actor_from_id(self, id: GitActorID) -> GitActor:
+        """Load a GitActor from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadGitActorFromID", _args)
+        return GitActor(_ctx)
+
+    def load_git_commit_info_from_id(self, id: GitCommitInfoID) -> GitCommitInfo:
+        """Load a GitCommitInfo from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadGitCommitInfoFromID", _args)
+        return GitCommitInfo(_ctx)
+
     def load_git_module_source_from_id(self, id: GitModuleSourceID) -> GitModuleSource:
         """Load a GitModuleSource from its ID."""
         _args = [
@@ -7314,6 +8913,14 @@ class Client(Root):
         _ctx = self._select("loadGitRepositoryFromID", _args)
         return GitRepository(_ctx)
 
+    def load_git_signature_from_id(self, id: GitSignatureID) -> GitSignature:
+        """Load a GitSignature from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadGitSignatureFromID", _args)
+        return GitSignature(_ctx)
+
     def load_host_from_id(self, id: HostID) -> Host:
         """Load a Host from its ID."""
         _args = [
@@ -7322,6 +8929,14 @@ class Client(Root):
         _ctx = self._select("loadHostFromID", _args)
         return Host(_ctx)
 
+    def load_image_config_from_id(self, id: ImageConfigID) -> ImageConfig:
+        """Load a ImageConfig from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadImageConfigFromID", _args)
+        return ImageConfig(_ctx)
+
     def load_input_type_def_from_id(self, id: InputTypeDefID) -> InputTypeDef:
         """Load a InputTypeDef from its ID."""
         _args = [
@@ -7418,6 +9033,14 @@ class Client(Root):
         _ctx = self._select("loadPortFromID", _args)
         return Port(_ctx)
 
+    def load_registry_from_id(self, id: RegistryID) -> "Registry":
+        """Load a Registry from its ID."""
+        _args = [
+            Arg("id", id),
+        ]
+        _ctx = self._select("loadRegistryFromID", _args)
+        return Registry(_ctx)
+
     def load_sdk_config_from_id(self, id: SDKConfigID) -> "SDKConfig":
         """Load a SDKConfig from its ID."""
         _args = [
@@ -7550,13 +9173,32 @@ class Client(Root):
             The relative path to the module root from the host directory
         """
         _args = [
-            Arg("refString", ref_string),
-            Arg("refPin", ref_pin, ""),
-            Arg("stable", stable, False),
-            Arg("relHostPath", rel_host_path, ""),
+            Arg("refString", ref_string),
+            Arg("refPin", ref_pin, ""),
+            Arg("stable", stable, False),
+            Arg("relHostPath", rel_host_path, ""),
+        ]
+        _ctx = self._select("moduleSource", _args)
+        return ModuleSource(_ctx)
+
+    def registry(self, address: str) -> "Registry":
+        """Inspects an image in a registry, without pulling its layers.
+
+        Credentials set with Container.withRegistryAuth and the client's
+        credentials are used to authenticate to the registry.
+
+        Parameters
+        ----------
+        address:
+            Address of the image (e.g., "alpine",
+            "docker.io/library/alpine:3.20" or "alpine@sha256:...").
+            The tag defaults to "latest".
+        """
+        _args = [
+            Arg("address", address),
         ]
-        _ctx = self._select("moduleSource", _args)
-        return ModuleSource(_ctx)
+        _ctx = self._select("registry", _args)
+        return Registry(_ctx)
 
     def secret(self, uri: str) -> "Secret":
         """Creates a new secret.
@@ -7565,6 +9207,12 @@ class Client(Root):
         ----------
         uri:
             The URI of the secret store
+            The scheme selects the provider that resolves the secret on the
+            client, e.g. env://, file://, cmd://, op://, vault://, sops://,
+            keyring:// or aws://. Other schemes are resolved by a dagger-
+            secret-[scheme] executable on the client's PATH.
+            Only the syntax of the URI is checked here: a scheme the client
+            has no provider for fails when the secret is first used.
         """
         _args = [
             Arg("uri", uri),
@@ -7645,6 +9293,172 @@ class Client(Root):
         return await _ctx.execute(str)
 
 
+@typecheck
+class Registry(Type):
+    """An image in a registry, inspected without pulling its layers."""
+
+    async def address(self) -> str:
+        """The normalized reference of the image (e.g.,
+        "docker.io/library/alpine:latest").
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("address", _args)
+        return await _ctx.execute(str)
+
+    def config(
+        self,
+        *,
+        platform: Platform | None = None,
+    ) -> ImageConfig:
+        """The config of the image for a platform.
+
+        Parameters
+        ----------
+        platform:
+            Platform of the image. Defaults to the platform of the engine.
+        """
+        _args = [
+            Arg("platform", platform, None),
+        ]
+        _ctx = self._select("config", _args)
+        return ImageConfig(_ctx)
+
+    async def digest(self) -> str:
+        """The digest of the image's manifest or index.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("digest", _args)
+        return await _ctx.execute(str)
+
+    async def id(self) -> RegistryID:
+        """A unique identifier for this Registry.
+
+        Note
+        ----
+        This is lazily evaluated, no operation is actually run.
+
+        Returns
+        -------
+        RegistryID
+            The `RegistryID` scalar type represents an identifier for an
+            object of type Registry.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("id", _args)
+        return await _ctx.execute(RegistryID)
+
+    async def manifest_digest(
+        self,
+        *,
+        platform: Platform | None = None,
+    ) -> str:
+        """The digest of the image's manifest for a platform.
+
+        Parameters
+        ----------
+        platform:
+            Platform of the manifest. Defaults to the platform of the engine.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args = [
+            Arg("platform", platform, None),
+        ]
+        _ctx = self._select("manifestDigest", _args)
+        return await _ctx.execute(str)
+
+    async def platforms(self) -> list[Platform]:
+        """The platforms of the image, in the order of its index.
+
+        Attestations and signatures are skipped.
+
+        Returns
+        -------
+        list[Platform]
+            The platform config OS and architecture in a Container.  The
+            format is [os]/[platform]/[version] (e.g., "darwin/arm64/v7",
+            "windows/amd64", "linux/arm64").
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("platforms", _args)
+        return await _ctx.execute(list[Platform])
+
+    async def tags(self) -> list[str]:
+        """The tags of the image's repository.
+
+        Returns
+        -------
+        list[str]
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("tags", _args)
+        return await _ctx.execute(list[str])
+
+
 @typecheck
 class SDKConfig(Type):
     """The SDK config of the module."""
@@ -7860,6 +9674,29 @@ class Secret(Type):
         _ctx = self._select("plaintext", _args)
         return await _ctx.execute(str)
 
+    async def refresh(self) -> Void | None:
+        """Resolves this secret again from its provider, replacing the value the
+        client cached for the session, e.g. after it was rotated.
+
+        Secrets set from a plaintext have nothing to refresh.
+
+        Returns
+        -------
+        Void | None
+            The absence of a value.  A Null Void is used as a placeholder for
+            resolvers that do not return anything.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("refresh", _args)
+        await _ctx.execute()
+
     async def uri(self) -> str:
         """The URI of this secret.
 
@@ -7928,6 +9765,32 @@ class Service(Type):
         _ctx = self._select("endpoint", _args)
         return await _ctx.execute(str)
 
+    async def exit_code(self) -> int | None:
+        """The exit code the service last exited with, or null if it hasn't
+        exited yet.
+
+        If the service has been restarted, this is the exit code of the run
+        before the current one until that one exits too. The service must have
+        been started within the session.
+
+        Returns
+        -------
+        int | None
+            The `Int` scalar type represents non-fractional signed whole
+            numeric values. Int can represent values between -(2^31) and 2^31
+            - 1.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args: list[Arg] = []
+        _ctx = self._select("exitCode", _args)
+        return await _ctx.execute(int | None)
+
     async def hostname(self) -> str:
         """Retrieves a hostname which can be used by clients to reach this
         container.
@@ -8013,6 +9876,88 @@ class Service(Type):
         _ctx = Client.from_context(_ctx)._select("loadServiceFromID", [Arg("id", _id)])
         return Service(_ctx)
 
+    async def stderr(
+        self,
+        *,
+        tail: int | None = 0,
+        since: str | None = "",
+    ) -> str:
+        """The output the service has written to stderr.
+
+        The service must have been started within the session. Its output
+        remains available once it has stopped, and includes the output of
+        every run if it has restarted.
+
+        Parameters
+        ----------
+        tail:
+            Only return this many lines from the end of the output.
+        since:
+            Only return output written since this point in time, either as a
+            duration (e.g., "10m") or as an RFC 3339 timestamp.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args = [
+            Arg("tail", tail, 0),
+            Arg("since", since, ""),
+        ]
+        _ctx = self._select("stderr", _args)
+        return await _ctx.execute(str)
+
+    async def stdout(
+        self,
+        *,
+        tail: int | None = 0,
+        since: str | None = "",
+    ) -> str:
+        """The output the service has written to stdout.
+
+        The service must have been started within the session. Its output
+        remains available once it has stopped, and includes the output of
+        every run if it has restarted.
+
+        Parameters
+        ----------
+        tail:
+            Only return this many lines from the end of the output.
+        since:
+            Only return output written since this point in time, either as a
+            duration (e.g., "10m") or as an RFC 3339 timestamp.
+
+        Returns
+        -------
+        str
+            The `String` scalar type represents textual data, represented as
+            UTF-8 character sequences. The String type is most often used by
+            GraphQL to represent free-form human-readable text.
+
+        Raises
+        ------
+        ExecuteTimeoutError
+            If the time to execute the query exceeds the configured timeout.
+        QueryError
+            If the API returns an error.
+        """
+        _args = [
+            Arg("tail", tail, 0),
+            Arg("since", since, ""),
+        ]
+        _ctx = self._select("stdout", _args)
+        return await _ctx.execute(str)
+
     async def stop(self, *, kill: bool | None = False) -> Self:
         """Stop the service.
 
@@ -8089,6 +10034,58 @@ class Service(Type):
         _ctx = self._select("withHostname", _args)
         return Service(_ctx)
 
+    def with_liveness_probe(
+        self,
+        *,
+        interval: int | None = 10,
+        failure_threshold: int | None = 3,
+    ) -> Self:
+        """Keeps running the service's health checks once it has started.
+
+        The service is killed once its health checks fail enough times in a
+        row, and restarted if its restart policy allows it.
+
+        Parameters
+        ----------
+        interval:
+            Number of seconds between each health check.
+        failure_threshold:
+            Number of consecutive failed health checks after which the service
+            is killed.
+        """
+        _args = [
+            Arg("interval", interval, 10),
+            Arg("failureThreshold", failure_threshold, 3),
+        ]
+        _ctx = self._select("withLivenessProbe", _args)
+        return Service(_ctx)
+
+    def with_restart_policy(
+        self,
+        policy: ServiceRestartPolicy,
+        *,
+        max_retries: int | None = 0,
+    ) -> Self:
+        """Configures whether the service is restarted when it exits.
+
+        The service keeps the same hostname across restarts, so containers
+        bound to it can keep reaching it.
+
+        Parameters
+        ----------
+        policy:
+            When to restart the service.
+        max_retries:
+            Maximum number of times to restart the service. If unset, there is
+            no limit.
+        """
+        _args = [
+            Arg("policy", policy),
+            Arg("maxRetries", max_retries, 0),
+        ]
+        _ctx = self._select("withRestartPolicy", _args)
+        return Service(_ctx)
+
     def with_(self, cb: Callable[["Service"], "Service"]) -> "Service":
         """Call the provided callable with current Service.
 
@@ -8602,10 +10599,16 @@ dag = Client()
 __all__ = [
     "JSON",
     "BuildArg",
+    "BuildContext",
+    "CacheMissExplanation",
+    "CacheMissExplanationID",
+    "CacheMissKind",
     "CacheSharingMode",
     "CacheVolume",
     "CacheVolumeID",
     "Client",
+    "ComposeProject",
+    "ComposeProjectID",
     "Container",
     "ContainerID",
     "CurrentModule",
@@ -8642,15 +10645,27 @@ __all__ = [
     "FunctionID",
     "GeneratedCode",
     "GeneratedCodeID",
+    "GitActor",
+    "GitActorID",
+    "GitCommitInfo",
+    "GitCommitInfoID",
     "GitModuleSource",
     "GitModuleSourceID",
     "GitRef",
     "GitRefID",
     "GitRepository",
     "GitRepositoryID",
+    "GitSignature",
+    "GitSignatureID",
+    "HTTPHeader",
+    "HealthcheckScheme",
     "Host",
     "HostID",
+    "ImageConfig",
+    "ImageConfigID",
+    "ImageHistory",
     "ImageLayerCompression",
+    "ImageLayerSquash",
     "ImageMediaTypes",
     "InputTypeDef",
     "InputTypeDefID",
@@ -8671,6 +10686,7 @@ __all__ = [
     "ModuleSourceKind",
     "ModuleSourceView",
     "ModuleSourceViewID",
+    "NetworkMode",
     "NetworkProtocol",
     "ObjectTypeDef",
     "ObjectTypeDefID",
@@ -8679,6 +10695,8 @@ __all__ = [
     "Port",
     "PortForward",
     "PortID",
+    "Registry",
+    "RegistryID",
     "ReturnType",
     "SDKConfig",
     "SDKConfigID",
@@ -8688,6 +10706,7 @@ __all__ = [
     "SecretID",
     "Service",
     "ServiceID",
+    "ServiceRestartPolicy",
     "Socket",
     "SocketID",
     "SourceMap",
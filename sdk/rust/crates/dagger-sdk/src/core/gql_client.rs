@@ -17,10 +17,16 @@ pub struct GraphQLError {
 pub struct GraphQLErrorMessage {
     pub message: String,
     locations: Option<Vec<GraphQLErrorLocation>>,
-    extensions: Option<HashMap<String, String>>,
+    extensions: Option<HashMap<String, serde_json::Value>>,
     path: Option<Vec<GraphQLErrorPathParam>>,
 }
 
+impl GraphQLErrorMessage {
+    pub fn extensions(&self) -> Option<&HashMap<String, serde_json::Value>> {
+        self.extensions.as_ref()
+    }
+}
+
 #[derive(Deserialize, Debug, Clone)]
 #[allow(dead_code)]
 pub struct GraphQLErrorLocation {
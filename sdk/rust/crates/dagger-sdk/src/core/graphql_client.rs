@@ -54,6 +54,9 @@ fn map_graphql_error(gql_error: crate::core::gql_client::GraphQLError) -> GraphQ
     let json = gql_error.json();
 
     if let Some(json) = json {
+        if let Some(exec_error) = json.first().and_then(ExecError::from_message) {
+            return GraphQLError::ExecError(exec_error);
+        }
         if !json.is_empty() {
             return GraphQLError::DomainError {
                 message,
@@ -74,6 +77,59 @@ pub enum GraphQLError {
         message: String,
         fields: GraphqlErrorMessages,
     },
+    #[error("{0}")]
+    ExecError(ExecError),
+}
+
+/// API error from an exec operation.
+#[derive(Error, Debug, Clone)]
+#[error("{message}")]
+pub struct ExecError {
+    pub message: String,
+    /// The command that was executed.
+    pub cmd: Vec<String>,
+    /// The exit code of the command.
+    pub exit_code: i64,
+    /// The stdout of the command.
+    pub stdout: String,
+    /// The stderr of the command.
+    pub stderr: String,
+    /// Why the engine terminated the command (e.g. TIMEOUT or OOM_KILLED), if it did.
+    pub reason: Option<String>,
+}
+
+impl ExecError {
+    fn from_message(err: &crate::core::gql_client::GraphQLErrorMessage) -> Option<Self> {
+        let ext = err.extensions()?;
+        if ext.get("_type")?.as_str()? != "EXEC_ERROR" {
+            return None;
+        }
+        let string = |key: &str| {
+            ext.get(key)
+                .and_then(|v| v.as_str())
+                .unwrap_or_default()
+                .to_string()
+        };
+        Some(Self {
+            message: err.message.clone(),
+            cmd: ext
+                .get("cmd")
+                .and_then(|v| v.as_array())
+                .map(|cmd| {
+                    cmd.iter()
+                        .filter_map(|arg| arg.as_str().map(|arg| arg.to_string()))
+                        .collect()
+                })
+                .unwrap_or_default(),
+            exit_code: ext.get("exitCode").and_then(|v| v.as_i64()).unwrap_or(-1),
+            stdout: string("stdout"),
+            stderr: string("stderr"),
+            reason: ext
+                .get("reason")
+                .and_then(|v| v.as_str())
+                .map(|v| v.to_string()),
+        })
+    }
 }
 
 #[derive(Debug, Clone)]
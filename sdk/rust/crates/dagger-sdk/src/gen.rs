@@ -9,6 +9,41 @@ use derive_builder::Builder;
 use serde::{Deserialize, Serialize};
 use std::sync::Arc;
 
+#[derive(Serialize, Deserialize, PartialEq, Debug, Clone)]
+pub struct CacheMissExplanationId(pub String);
+impl From<&str> for CacheMissExplanationId {
+    fn from(value: &str) -> Self {
+        Self(value.to_string())
+    }
+}
+impl From<String> for CacheMissExplanationId {
+    fn from(value: String) -> Self {
+        Self(value)
+    }
+}
+impl IntoID<CacheMissExplanationId> for CacheMissExplanation {
+    fn into_id(
+        self,
+    ) -> std::pin::Pin<
+        Box<dyn core::future::Future<Output = Result<CacheMissExplanationId, DaggerError>> + Send>,
+    > {
+        Box::pin(async move { self.id().await })
+    }
+}
+impl IntoID<CacheMissExplanationId> for CacheMissExplanationId {
+    fn into_id(
+        self,
+    ) -> std::pin::Pin<
+        Box<dyn core::future::Future<Output = Result<CacheMissExplanationId, DaggerError>> + Send>,
+    > {
+        Box::pin(async move { Ok::<CacheMissExplanationId, DaggerError>(self) })
+    }
+}
+impl CacheMissExplanationId {
+    fn quote(&self) -> String {
+        format!("\"{}\"", self.0.clone())
+    }
+}
 #[derive(Serialize, Deserialize, PartialEq, Debug, Clone)]
 pub struct CacheVolumeId(pub String);
 impl From<&str> for CacheVolumeId {
@@ -45,6 +80,41 @@ impl CacheVolumeId {
     }
 }
 #[derive(Serialize, Deserialize, PartialEq, Debug, Clone)]
+pub struct ComposeProjectId(pub String);
+impl From<&str> for ComposeProjectId {
+    fn from(value: &str) -> Self {
+        Self(value.to_string())
+    }
+}
+impl From<String> for ComposeProjectId {
+    fn from(value: String) -> Self {
+        Self(value)
+    }
+}
+impl IntoID<ComposeProjectId> for ComposeProject {
+    fn into_id(
+        self,
+    ) -> std::pin::Pin<
+        Box<dyn core::future::Future<Output = Result<ComposeProjectId, DaggerError>> + Send>,
+    > {
+        Box::pin(async move { self.id().await })
+    }
+}
+impl IntoID<ComposeProjectId> for ComposeProjectId {
+    fn into_id(
+        self,
+    ) -> std::pin::Pin<
+        Box<dyn core::future::Future<Output = Result<ComposeProjectId, DaggerError>> + Send>,
+    > {
+        Box::pin(async move { Ok::<ComposeProjectId, DaggerError>(self) })
+    }
+}
+impl ComposeProjectId {
+    fn quote(&self) -> String {
+        format!("\"{}\"", self.0.clone())
+    }
+}
+#[derive(Serialize, Deserialize, PartialEq, Debug, Clone)]
 pub struct ContainerId(pub String);
 impl From<&str> for ContainerId {
     fn from(value: &str) -> Self {
@@ -667,6 +737,74 @@ impl GeneratedCodeId {
     }
 }
 #[derive(Serialize, Deserialize, PartialEq, Debug, Clone)]
+pub struct GitActorId(pub String);
+impl From<&str> for GitActorId {
+    fn from(value: &str) -> Self {
+        Self(value.to_string())
+    }
+}
+impl From<String> for GitActorId {
+    fn from(value: String) -> Self {
+        Self(value)
+    }
+}
+impl IntoID<GitActorId> for GitActor {
+    fn into_id(
+        self,
+    ) -> std::pin::Pin<Box<dyn core::future::Future<Output = Result<GitActorId, DaggerError>> + Send>>
+    {
+        Box::pin(async move { self.id().await })
+    }
+}
+impl IntoID<GitActorId> for GitActorId {
+    fn into_id(
+        self,
+    ) -> std::pin::Pin<Box<dyn core::future::Future<Output = Result<GitActorId, DaggerError>> + Send>>
+    {
+        Box::pin(async move { Ok::<GitActorId, DaggerError>(self) })
+    }
+}
+impl GitActorId {
+    fn quote(&self) -> String {
+        format!("\"{}\"", self.0.clone())
+    }
+}
+#[derive(Serialize, Deserialize, PartialEq, Debug, Clone)]
+pub struct GitCommitInfoId(pub String);
+impl From<&str> for GitCommitInfoId {
+    fn from(value: &str) -> Self {
+        Self(value.to_string())
+    }
+}
+impl From<String> for GitCommitInfoId {
+    fn from(value: String) -> Self {
+        Self(value)
+    }
+}
+impl IntoID<GitCommitInfoId> for GitCommitInfo {
+    fn into_id(
+        self,
+    ) -> std::pin::Pin<
+        Box<dyn core::future::Future<Output = Result<GitCommitInfoId, DaggerError>> + Send>,
+    > {
+        Box::pin(async move { self.id().await })
+    }
+}
+impl IntoID<GitCommitInfoId> for GitCommitInfoId {
+    fn into_id(
+        self,
+    ) -> std::pin::Pin<
+        Box<dyn core::future::Future<Output = Result<GitCommitInfoId, DaggerError>> + Send>,
+    > {
+        Box::pin(async move { Ok::<GitCommitInfoId, DaggerError>(self) })
+    }
+}
+impl GitCommitInfoId {
+    fn quote(&self) -> String {
+        format!("\"{}\"", self.0.clone())
+    }
+}
+#[derive(Serialize, Deserialize, PartialEq, Debug, Clone)]
 pub struct GitModuleSourceId(pub String);
 impl From<&str> for GitModuleSourceId {
     fn from(value: &str) -> Self {
@@ -770,6 +908,41 @@ impl GitRepositoryId {
     }
 }
 #[derive(Serialize, Deserialize, PartialEq, Debug, Clone)]
+pub struct GitSignatureId(pub String);
+impl From<&str> for GitSignatureId {
+    fn from(value: &str) -> Self {
+        Self(value.to_string())
+    }
+}
+impl From<String> for GitSignatureId {
+    fn from(value: String) -> Self {
+        Self(value)
+    }
+}
+impl IntoID<GitSignatureId> for GitSignature {
+    fn into_id(
+        self,
+    ) -> std::pin::Pin<
+        Box<dyn core::future::Future<Output = Result<GitSignatureId, DaggerError>> + Send>,
+    > {
+        Box::pin(async move { self.id().await })
+    }
+}
+impl IntoID<GitSignatureId> for GitSignatureId {
+    fn into_id(
+        self,
+    ) -> std::pin::Pin<
+        Box<dyn core::future::Future<Output = Result<GitSignatureId, DaggerError>> + Send>,
+    > {
+        Box::pin(async move { Ok::<GitSignatureId, DaggerError>(self) })
+    }
+}
+impl GitSignatureId {
+    fn quote(&self) -> String {
+        format!("\"{}\"", self.0.clone())
+    }
+}
+#[derive(Serialize, Deserialize, PartialEq, Debug, Clone)]
 pub struct HostId(pub String);
 impl From<&str> for HostId {
     fn from(value: &str) -> Self {
@@ -803,6 +976,41 @@ impl HostId {
     }
 }
 #[derive(Serialize, Deserialize, PartialEq, Debug, Clone)]
+pub struct ImageConfigId(pub String);
+impl From<&str> for ImageConfigId {
+    fn from(value: &str) -> Self {
+        Self(value.to_string())
+    }
+}
+impl From<String> for ImageConfigId {
+    fn from(value: String) -> Self {
+        Self(value)
+    }
+}
+impl IntoID<ImageConfigId> for ImageConfig {
+    fn into_id(
+        self,
+    ) -> std::pin::Pin<
+        Box<dyn core::future::Future<Output = Result<ImageConfigId, DaggerError>> + Send>,
+    > {
+        Box::pin(async move { self.id().await })
+    }
+}
+impl IntoID<ImageConfigId> for ImageConfigId {
+    fn into_id(
+        self,
+    ) -> std::pin::Pin<
+        Box<dyn core::future::Future<Output = Result<ImageConfigId, DaggerError>> + Send>,
+    > {
+        Box::pin(async move { Ok::<ImageConfigId, DaggerError>(self) })
+    }
+}
+impl ImageConfigId {
+    fn quote(&self) -> String {
+        format!("\"{}\"", self.0.clone())
+    }
+}
+#[derive(Serialize, Deserialize, PartialEq, Debug, Clone)]
 pub struct InputTypeDefId(pub String);
 impl From<&str> for InputTypeDefId {
     fn from(value: &str) -> Self {
@@ -1216,6 +1424,39 @@ impl PortId {
     }
 }
 #[derive(Serialize, Deserialize, PartialEq, Debug, Clone)]
+pub struct RegistryId(pub String);
+impl From<&str> for RegistryId {
+    fn from(value: &str) -> Self {
+        Self(value.to_string())
+    }
+}
+impl From<String> for RegistryId {
+    fn from(value: String) -> Self {
+        Self(value)
+    }
+}
+impl IntoID<RegistryId> for Registry {
+    fn into_id(
+        self,
+    ) -> std::pin::Pin<Box<dyn core::future::Future<Output = Result<RegistryId, DaggerError>> + Send>>
+    {
+        Box::pin(async move { self.id().await })
+    }
+}
+impl IntoID<RegistryId> for RegistryId {
+    fn into_id(
+        self,
+    ) -> std::pin::Pin<Box<dyn core::future::Future<Output = Result<RegistryId, DaggerError>> + Send>>
+    {
+        Box::pin(async move { Ok::<RegistryId, DaggerError>(self) })
+    }
+}
+impl RegistryId {
+    fn quote(&self) -> String {
+        format!("\"{}\"", self.0.clone())
+    }
+}
+#[derive(Serialize, Deserialize, PartialEq, Debug, Clone)]
 pub struct SdkConfigId(pub String);
 impl From<&str> for SdkConfigId {
     fn from(value: &str) -> Self {
@@ -1508,6 +1749,17 @@ pub struct BuildArg {
     pub value: String,
 }
 #[derive(Serialize, Deserialize, Debug, PartialEq, Clone)]
+pub struct BuildContext {
+    pub container: ContainerId,
+    pub directory: DirectoryId,
+    pub name: String,
+}
+#[derive(Serialize, Deserialize, Debug, PartialEq, Clone)]
+pub struct HttpHeader {
+    pub name: String,
+    pub value: String,
+}
+#[derive(Serialize, Deserialize, Debug, PartialEq, Clone)]
 pub struct PipelineLabel {
     pub name: String,
     pub value: String,
@@ -1519,6 +1771,69 @@ pub struct PortForward {
     pub protocol: NetworkProtocol,
 }
 #[derive(Clone)]
+pub struct CacheMissExplanation {
+    pub proc: Option<Arc<DaggerSessionProc>>,
+    pub selection: Selection,
+    pub graphql_client: DynGraphQLClient,
+}
+impl CacheMissExplanation {
+    /// The name of the diverging argument, if the kind is ARGUMENT.
+    pub async fn argument(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("argument");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The first diverging call, e.g. host.directory(path: "./src"), empty if the calls are the same.
+    pub async fn call(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("call");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The current value, e.g. of the argument or content digest, empty if missing.
+    pub async fn current_value(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("currentValue");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The digest of the call that missed the cache.
+    pub async fn digest(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("digest");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// A unique identifier for this CacheMissExplanation.
+    pub async fn id(&self) -> Result<CacheMissExplanationId, DaggerError> {
+        let query = self.selection.select("id");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// What differs between the calls.
+    pub async fn kind(&self) -> Result<CacheMissKind, DaggerError> {
+        let query = self.selection.select("kind");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// A description of the first difference between the calls.
+    pub async fn message(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("message");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The digest of the call it was compared to.
+    pub async fn previous_digest(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("previousDigest");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The trace of the previous run the call it was compared to is from.
+    pub async fn previous_trace(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("previousTrace");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The previous value, e.g. of the argument or content digest, empty if missing.
+    pub async fn previous_value(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("previousValue");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The trace of the run the call is from.
+    pub async fn trace(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("trace");
+        query.execute(self.graphql_client.clone()).await
+    }
+}
+#[derive(Clone)]
 pub struct CacheVolume {
     pub proc: Option<Arc<DaggerSessionProc>>,
     pub selection: Selection,
@@ -1532,6 +1847,44 @@ impl CacheVolume {
     }
 }
 #[derive(Clone)]
+pub struct ComposeProject {
+    pub proc: Option<Arc<DaggerSessionProc>>,
+    pub selection: Selection,
+    pub graphql_client: DynGraphQLClient,
+}
+impl ComposeProject {
+    /// A unique identifier for this ComposeProject.
+    pub async fn id(&self) -> Result<ComposeProjectId, DaggerError> {
+        let query = self.selection.select("id");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The name of the project.
+    pub async fn name(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("name");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// Retrieves a service of the project.
+    /// The services it depends on are bound to it, reachable at their names in the project. Its named volumes are cache volumes, shared with the project's other services.
+    ///
+    /// # Arguments
+    ///
+    /// * `name` - The name of the service.
+    pub fn service(&self, name: impl Into<String>) -> Service {
+        let mut query = self.selection.select("service");
+        query = query.arg("name", name.into());
+        Service {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// The names of the services in the project.
+    pub async fn service_names(&self) -> Result<Vec<String>, DaggerError> {
+        let query = self.selection.select("serviceNames");
+        query.execute(self.graphql_client.clone()).await
+    }
+}
+#[derive(Clone)]
 pub struct Container {
     pub proc: Option<Arc<DaggerSessionProc>>,
     pub selection: Selection,
@@ -1543,6 +1896,9 @@ pub struct ContainerAsServiceOpts<'a> {
     /// If empty, the container's default command is used.
     #[builder(setter(into, strip_option), default)]
     pub args: Option<Vec<&'a str>>,
+    /// Number of CPUs the service may use (e.g., 0.5).
+    #[builder(setter(into, strip_option), default)]
+    pub cpu_limit: Option<float>,
     /// Replace "${VAR}" or "$VAR" in the args according to the current environment variables defined in the container (e.g. "/$VAR/foo").
     #[builder(setter(into, strip_option), default)]
     pub expand: Option<bool>,
@@ -1550,13 +1906,37 @@ pub struct ContainerAsServiceOpts<'a> {
     /// Do not use this option unless you trust the command being executed; the command being executed WILL BE GRANTED FULL ACCESS TO YOUR HOST FILESYSTEM.
     #[builder(setter(into, strip_option), default)]
     pub experimental_privileged_nesting: Option<bool>,
+    /// Command to run in the service container to check that it's healthy (e.g., ["pg_isready"]).
+    /// It runs once the exposed ports are reachable, and the service is healthy once it exits successfully.
+    #[builder(setter(into, strip_option), default)]
+    pub healthcheck_exec: Option<Vec<&'a str>>,
+    /// Number of seconds to wait between health check attempts.
+    /// If unset, attempts back off exponentially.
+    #[builder(setter(into, strip_option), default)]
+    pub healthcheck_interval: Option<isize>,
+    /// Number of times to retry a failing health check before the service fails to start.
+    /// If unset, health checks are retried for up to 15 minutes.
+    #[builder(setter(into, strip_option), default)]
+    pub healthcheck_retries: Option<isize>,
+    /// Number of seconds each health check attempt may take.
+    #[builder(setter(into, strip_option), default)]
+    pub healthcheck_timeout: Option<isize>,
     /// Execute the command with all root capabilities. This is similar to running a command with "sudo" or executing "docker run" with the "--privileged" flag. Containerization does not provide any security guarantees when using this option. It should only be used when absolutely necessary and only with trusted commands.
     #[builder(setter(into, strip_option), default)]
     pub insecure_root_capabilities: Option<bool>,
+    /// Memory in bytes the service may use before it's killed (e.g., 536870912 for 512 MiB).
+    #[builder(setter(into, strip_option), default)]
+    pub memory_limit: Option<isize>,
     /// If set, skip the automatic init process injected into containers by default.
     /// This should only be used if the user requires that their exec process be the pid 1 process in the container. Otherwise it may result in unexpected behavior.
     #[builder(setter(into, strip_option), default)]
     pub no_init: Option<bool>,
+    /// Number of processes the service may have running at once.
+    #[builder(setter(into, strip_option), default)]
+    pub pids_limit: Option<isize>,
+    /// Number of seconds the service may run for before it's killed.
+    #[builder(setter(into, strip_option), default)]
+    pub timeout: Option<isize>,
     /// If the container has an entrypoint, prepend it to the args.
     #[builder(setter(into, strip_option), default)]
     pub use_entrypoint: Option<bool>,
@@ -1567,6 +1947,9 @@ pub struct ContainerAsTarballOpts {
     /// If this is unset, then if a layer already has a compressed blob in the engine's cache, that will be used (this can result in a mix of compression algorithms for different layers). If this is unset and a layer has no compressed blob in the engine's cache, then it will be compressed using Gzip.
     #[builder(setter(into, strip_option), default)]
     pub forced_compression: Option<ImageLayerCompression>,
+    /// How to describe the layers of the image in its history.
+    #[builder(setter(into, strip_option), default)]
+    pub history: Option<ImageHistory>,
     /// Use the specified media types for the image's layers.
     /// Defaults to OCI, which is largely compatible with most recent container runtimes, but Docker may be needed for older runtimes without OCI support.
     #[builder(setter(into, strip_option), default)]
@@ -1575,6 +1958,23 @@ pub struct ContainerAsTarballOpts {
     /// Used for multi-platform images.
     #[builder(setter(into, strip_option), default)]
     pub platform_variants: Option<Vec<ContainerId>>,
+    /// Attach a SLSA provenance, recording the container's call ID, as an attestation.
+    #[builder(setter(into, strip_option), default)]
+    pub provenance: Option<bool>,
+    /// Attach an SPDX SBOM of the image's filesystem as an attestation.
+    #[builder(setter(into, strip_option), default)]
+    pub sbom: Option<bool>,
+    /// A private key to sign the image with, in PEM format (e.g., generated by "cosign generate-key-pair").
+    /// The signature is compatible with "cosign verify".
+    #[builder(setter(into, strip_option), default)]
+    pub signing_key: Option<SecretId>,
+    /// The password of the signing key, if it is encrypted.
+    #[builder(setter(into, strip_option), default)]
+    pub signing_key_password: Option<SecretId>,
+    /// Squash the layers of the image into one, or the layers above the image the container was created from with "from" or "import".
+    /// Squashing drops the files that were deleted by later operations.
+    #[builder(setter(into, strip_option), default)]
+    pub squash: Option<ImageLayerSquash>,
 }
 #[derive(Builder, Debug, PartialEq)]
 pub struct ContainerBuildOpts<'a> {
@@ -1608,6 +2008,9 @@ pub struct ContainerExportOpts {
     /// If this is unset, then if a layer already has a compressed blob in the engine's cache, that will be used (this can result in a mix of compression algorithms for different layers). If this is unset and a layer has no compressed blob in the engine's cache, then it will be compressed using Gzip.
     #[builder(setter(into, strip_option), default)]
     pub forced_compression: Option<ImageLayerCompression>,
+    /// How to describe the layers of the image in its history.
+    #[builder(setter(into, strip_option), default)]
+    pub history: Option<ImageHistory>,
     /// Use the specified media types for the exported image's layers.
     /// Defaults to OCI, which is largely compatible with most recent container runtimes, but Docker may be needed for older runtimes without OCI support.
     #[builder(setter(into, strip_option), default)]
@@ -1616,6 +2019,10 @@ pub struct ContainerExportOpts {
     /// Used for multi-platform image.
     #[builder(setter(into, strip_option), default)]
     pub platform_variants: Option<Vec<ContainerId>>,
+    /// Squash the layers of the image into one, or the layers above the image the container was created from with "from" or "import".
+    /// Squashing drops the files that were deleted by later operations.
+    #[builder(setter(into, strip_option), default)]
+    pub squash: Option<ImageLayerSquash>,
 }
 #[derive(Builder, Debug, PartialEq)]
 pub struct ContainerFileOpts {
@@ -1625,7 +2032,37 @@ pub struct ContainerFileOpts {
 }
 #[derive(Builder, Debug, PartialEq)]
 pub struct ContainerImportOpts<'a> {
+    /// Identifies the digest of the image manifest or index to import from the archive.
+    #[builder(setter(into, strip_option), default)]
+    pub digest: Option<&'a str>,
     /// Identifies the tag to import from the archive, if the archive bundles multiple tags.
+    /// Either the tag only (e.g., "latest") or the image reference (e.g., "alpine:latest").
+    #[builder(setter(into, strip_option), default)]
+    pub tag: Option<&'a str>,
+}
+#[derive(Builder, Debug, PartialEq)]
+pub struct ContainerImportDirectoryOpts<'a> {
+    /// Identifies the digest of the image manifest or index to import from the layout.
+    #[builder(setter(into, strip_option), default)]
+    pub digest: Option<&'a str>,
+    /// Identifies the tag to import from the layout, if the layout bundles multiple tags.
+    /// Either the tag only (e.g., "latest") or the image reference (e.g., "alpine:latest").
+    #[builder(setter(into, strip_option), default)]
+    pub tag: Option<&'a str>,
+}
+#[derive(Builder, Debug, PartialEq)]
+pub struct ContainerImportVariantsOpts<'a> {
+    /// Identifies the digest of the image index to import from the archive.
+    #[builder(setter(into, strip_option), default)]
+    pub digest: Option<&'a str>,
+    /// Directory in OCI image layout to read the variants from.
+    #[builder(setter(into, strip_option), default)]
+    pub directory: Option<DirectoryId>,
+    /// File to read the variants from. Exactly one of source or directory must be set.
+    #[builder(setter(into, strip_option), default)]
+    pub source: Option<FileId>,
+    /// Identifies the tag to import from the archive, if the archive bundles multiple tags.
+    /// Either the tag only (e.g., "latest") or the image reference (e.g., "alpine:latest").
     #[builder(setter(into, strip_option), default)]
     pub tag: Option<&'a str>,
 }
@@ -1635,6 +2072,9 @@ pub struct ContainerPublishOpts {
     /// If this is unset, then if a layer already has a compressed blob in the engine's cache, that will be used (this can result in a mix of compression algorithms for different layers). If this is unset and a layer has no compressed blob in the engine's cache, then it will be compressed using Gzip.
     #[builder(setter(into, strip_option), default)]
     pub forced_compression: Option<ImageLayerCompression>,
+    /// How to describe the layers of the image in its history.
+    #[builder(setter(into, strip_option), default)]
+    pub history: Option<ImageHistory>,
     /// Use the specified media types for the published image's layers.
     /// Defaults to OCI, which is largely compatible with most recent registries, but Docker may be needed for older registries without OCI support.
     #[builder(setter(into, strip_option), default)]
@@ -1643,6 +2083,23 @@ pub struct ContainerPublishOpts {
     /// Used for multi-platform image.
     #[builder(setter(into, strip_option), default)]
     pub platform_variants: Option<Vec<ContainerId>>,
+    /// Attach a SLSA provenance, recording the container's call ID, as an attestation.
+    #[builder(setter(into, strip_option), default)]
+    pub provenance: Option<bool>,
+    /// Attach an SPDX SBOM of the image's filesystem as an attestation.
+    #[builder(setter(into, strip_option), default)]
+    pub sbom: Option<bool>,
+    /// A private key to sign the image with, in PEM format (e.g., generated by "cosign generate-key-pair").
+    /// The signature is compatible with "cosign verify".
+    #[builder(setter(into, strip_option), default)]
+    pub signing_key: Option<SecretId>,
+    /// The password of the signing key, if it is encrypted.
+    #[builder(setter(into, strip_option), default)]
+    pub signing_key_password: Option<SecretId>,
+    /// Squash the layers of the image into one, or the layers above the image the container was created from with "from" or "import".
+    /// Squashing drops the files that were deleted by later operations.
+    #[builder(setter(into, strip_option), default)]
+    pub squash: Option<ImageLayerSquash>,
 }
 #[derive(Builder, Debug, PartialEq)]
 pub struct ContainerTerminalOpts<'a> {
@@ -1663,6 +2120,9 @@ pub struct ContainerUpOpts<'a> {
     /// If empty, the container's default command is used.
     #[builder(setter(into, strip_option), default)]
     pub args: Option<Vec<&'a str>>,
+    /// Number of CPUs the service may use (e.g., 0.5).
+    #[builder(setter(into, strip_option), default)]
+    pub cpu_limit: Option<float>,
     /// Replace "${VAR}" or "$VAR" in the args according to the current environment variables defined in the container (e.g. "/$VAR/foo").
     #[builder(setter(into, strip_option), default)]
     pub expand: Option<bool>,
@@ -1670,13 +2130,34 @@ pub struct ContainerUpOpts<'a> {
     /// Do not use this option unless you trust the command being executed; the command being executed WILL BE GRANTED FULL ACCESS TO YOUR HOST FILESYSTEM.
     #[builder(setter(into, strip_option), default)]
     pub experimental_privileged_nesting: Option<bool>,
+    /// Command to run in the service container to check that it's healthy (e.g., ["pg_isready"]).
+    /// It runs once the exposed ports are reachable, and the service is healthy once it exits successfully.
+    #[builder(setter(into, strip_option), default)]
+    pub healthcheck_exec: Option<Vec<&'a str>>,
+    /// Number of seconds to wait between health check attempts.
+    /// If unset, attempts back off exponentially.
+    #[builder(setter(into, strip_option), default)]
+    pub healthcheck_interval: Option<isize>,
+    /// Number of times to retry a failing health check before the service fails to start.
+    /// If unset, health checks are retried for up to 15 minutes.
+    #[builder(setter(into, strip_option), default)]
+    pub healthcheck_retries: Option<isize>,
+    /// Number of seconds each health check attempt may take.
+    #[builder(setter(into, strip_option), default)]
+    pub healthcheck_timeout: Option<isize>,
     /// Execute the command with all root capabilities. This is similar to running a command with "sudo" or executing "docker run" with the "--privileged" flag. Containerization does not provide any security guarantees when using this option. It should only be used when absolutely necessary and only with trusted commands.
     #[builder(setter(into, strip_option), default)]
     pub insecure_root_capabilities: Option<bool>,
+    /// Memory in bytes the service may use before it's killed (e.g., 536870912 for 512 MiB).
+    #[builder(setter(into, strip_option), default)]
+    pub memory_limit: Option<isize>,
     /// If set, skip the automatic init process injected into containers by default.
     /// This should only be used if the user requires that their exec process be the pid 1 process in the container. Otherwise it may result in unexpected behavior.
     #[builder(setter(into, strip_option), default)]
     pub no_init: Option<bool>,
+    /// Number of processes the service may have running at once.
+    #[builder(setter(into, strip_option), default)]
+    pub pids_limit: Option<isize>,
     /// List of frontend/backend port mappings to forward.
     /// Frontend is the port accepting traffic on the host, backend is the service port.
     #[builder(setter(into, strip_option), default)]
@@ -1684,6 +2165,9 @@ pub struct ContainerUpOpts<'a> {
     /// Bind each tunnel port to a random port on the host.
     #[builder(setter(into, strip_option), default)]
     pub random: Option<bool>,
+    /// Number of seconds the service may run for before it's killed.
+    #[builder(setter(into, strip_option), default)]
+    pub timeout: Option<isize>,
     /// If the container has an entrypoint, prepend it to the args.
     #[builder(setter(into, strip_option), default)]
     pub use_entrypoint: Option<bool>,
@@ -1729,6 +2213,9 @@ pub struct ContainerWithEnvVariableOpts {
 }
 #[derive(Builder, Debug, PartialEq)]
 pub struct ContainerWithExecOpts<'a> {
+    /// Number of CPUs the command may use (e.g., 0.5).
+    #[builder(setter(into, strip_option), default)]
+    pub cpu_limit: Option<float>,
     /// Replace "${VAR}" or "$VAR" in the args according to the current environment variables defined in the container (e.g. "/$VAR/foo").
     #[builder(setter(into, strip_option), default)]
     pub expand: Option<bool>,
@@ -1742,10 +2229,21 @@ pub struct ContainerWithExecOpts<'a> {
     /// Execute the command with all root capabilities. This is similar to running a command with "sudo" or executing "docker run" with the "--privileged" flag. Containerization does not provide any security guarantees when using this option. It should only be used when absolutely necessary and only with trusted commands.
     #[builder(setter(into, strip_option), default)]
     pub insecure_root_capabilities: Option<bool>,
+    /// Memory in bytes the command may use (e.g., 536870912 for 512 MiB).
+    /// A command that exceeds it is killed and fails with an ExecError whose reason is OOM_KILLED.
+    #[builder(setter(into, strip_option), default)]
+    pub memory_limit: Option<isize>,
+    /// Network access granted to the command.
+    /// With SERVICES_ONLY, connections to anything but bound services and the engine's DNS server fail immediately.
+    #[builder(setter(into, strip_option), default)]
+    pub network_mode: Option<NetworkMode>,
     /// If set, skip the automatic init process injected into containers by default.
     /// This should only be used if the user requires that their exec process be the pid 1 process in the container. Otherwise it may result in unexpected behavior.
     #[builder(setter(into, strip_option), default)]
     pub no_init: Option<bool>,
+    /// Number of processes the command may have running at once.
+    #[builder(setter(into, strip_option), default)]
+    pub pids_limit: Option<isize>,
     /// Redirect the command's standard error to a file in the container (e.g., "/tmp/stderr").
     #[builder(setter(into, strip_option), default)]
     pub redirect_stderr: Option<&'a str>,
@@ -1755,6 +2253,10 @@ pub struct ContainerWithExecOpts<'a> {
     /// Content to write to the command's standard input before closing (e.g., "Hello world").
     #[builder(setter(into, strip_option), default)]
     pub stdin: Option<&'a str>,
+    /// Number of seconds the command may run for before it's killed.
+    /// A command that times out fails with an ExecError whose reason is TIMEOUT.
+    #[builder(setter(into, strip_option), default)]
+    pub timeout: Option<isize>,
     /// If the container has an entrypoint, prepend it to the args.
     #[builder(setter(into, strip_option), default)]
     pub use_entrypoint: Option<bool>,
@@ -1767,6 +2269,17 @@ pub struct ContainerWithExposedPortOpts<'a> {
     /// Skip the health check when run as a service.
     #[builder(setter(into, strip_option), default)]
     pub experimental_skip_healthcheck: Option<bool>,
+    /// Check the port's health with an HTTP GET request for this path, rather than just connecting to it (e.g., "/healthz").
+    #[builder(setter(into, strip_option), default)]
+    pub healthcheck_path: Option<&'a str>,
+    /// Scheme of the health check request. If unset, HTTP is used.
+    /// Requires healthcheckPath.
+    #[builder(setter(into, strip_option), default)]
+    pub healthcheck_scheme: Option<HealthcheckScheme>,
+    /// Status code the health check request must respond with.
+    /// If unset, any 2xx or 3xx status is accepted. Requires healthcheckPath.
+    #[builder(setter(into, strip_option), default)]
+    pub healthcheck_status: Option<isize>,
     /// Transport layer network protocol
     #[builder(setter(into, strip_option), default)]
     pub protocol: Option<NetworkProtocol>,
@@ -1980,6 +2493,30 @@ impl Container {
         if let Some(no_init) = opts.no_init {
             query = query.arg("noInit", no_init);
         }
+        if let Some(timeout) = opts.timeout {
+            query = query.arg("timeout", timeout);
+        }
+        if let Some(cpu_limit) = opts.cpu_limit {
+            query = query.arg("cpuLimit", cpu_limit);
+        }
+        if let Some(memory_limit) = opts.memory_limit {
+            query = query.arg("memoryLimit", memory_limit);
+        }
+        if let Some(pids_limit) = opts.pids_limit {
+            query = query.arg("pidsLimit", pids_limit);
+        }
+        if let Some(healthcheck_exec) = opts.healthcheck_exec {
+            query = query.arg("healthcheckExec", healthcheck_exec);
+        }
+        if let Some(healthcheck_interval) = opts.healthcheck_interval {
+            query = query.arg("healthcheckInterval", healthcheck_interval);
+        }
+        if let Some(healthcheck_timeout) = opts.healthcheck_timeout {
+            query = query.arg("healthcheckTimeout", healthcheck_timeout);
+        }
+        if let Some(healthcheck_retries) = opts.healthcheck_retries {
+            query = query.arg("healthcheckRetries", healthcheck_retries);
+        }
         Service {
             proc: self.proc.clone(),
             selection: query,
@@ -2015,6 +2552,24 @@ impl Container {
         if let Some(media_types) = opts.media_types {
             query = query.arg("mediaTypes", media_types);
         }
+        if let Some(squash) = opts.squash {
+            query = query.arg("squash", squash);
+        }
+        if let Some(history) = opts.history {
+            query = query.arg("history", history);
+        }
+        if let Some(signing_key) = opts.signing_key {
+            query = query.arg("signingKey", signing_key);
+        }
+        if let Some(signing_key_password) = opts.signing_key_password {
+            query = query.arg("signingKeyPassword", signing_key_password);
+        }
+        if let Some(sbom) = opts.sbom {
+            query = query.arg("sbom", sbom);
+        }
+        if let Some(provenance) = opts.provenance {
+            query = query.arg("provenance", provenance);
+        }
         File {
             proc: self.proc.clone(),
             selection: query,
@@ -2228,6 +2783,12 @@ impl Container {
         if let Some(expand) = opts.expand {
             query = query.arg("expand", expand);
         }
+        if let Some(squash) = opts.squash {
+            query = query.arg("squash", squash);
+        }
+        if let Some(history) = opts.history {
+            query = query.arg("history", history);
+        }
         query.execute(self.graphql_client.clone()).await
     }
     /// Retrieves the list of exposed ports.
@@ -2302,6 +2863,7 @@ impl Container {
         query.execute(self.graphql_client.clone()).await
     }
     /// Reads the container from an OCI tarball.
+    /// Docker archives, like the ones produced by "docker save", are also supported. The image is picked for the container's platform, unless a single image manifest is selected.
     ///
     /// # Arguments
     ///
@@ -2323,6 +2885,7 @@ impl Container {
         }
     }
     /// Reads the container from an OCI tarball.
+    /// Docker archives, like the ones produced by "docker save", are also supported. The image is picked for the container's platform, unless a single image manifest is selected.
     ///
     /// # Arguments
     ///
@@ -2344,12 +2907,112 @@ impl Container {
         if let Some(tag) = opts.tag {
             query = query.arg("tag", tag);
         }
+        if let Some(digest) = opts.digest {
+            query = query.arg("digest", digest);
+        }
         Container {
             proc: self.proc.clone(),
             selection: query,
             graphql_client: self.graphql_client.clone(),
         }
     }
+    /// Reads the container from a directory in OCI image layout.
+    /// The image is picked for the container's platform, unless a single image manifest is selected.
+    ///
+    /// # Arguments
+    ///
+    /// * `source` - Directory in OCI image layout to read the container from.
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn import_directory(&self, source: impl IntoID<DirectoryId>) -> Container {
+        let mut query = self.selection.select("importDirectory");
+        query = query.arg_lazy(
+            "source",
+            Box::new(move || {
+                let source = source.clone();
+                Box::pin(async move { source.into_id().await.unwrap().quote() })
+            }),
+        );
+        Container {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// Reads the container from a directory in OCI image layout.
+    /// The image is picked for the container's platform, unless a single image manifest is selected.
+    ///
+    /// # Arguments
+    ///
+    /// * `source` - Directory in OCI image layout to read the container from.
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn import_directory_opts<'a>(
+        &self,
+        source: impl IntoID<DirectoryId>,
+        opts: ContainerImportDirectoryOpts<'a>,
+    ) -> Container {
+        let mut query = self.selection.select("importDirectory");
+        query = query.arg_lazy(
+            "source",
+            Box::new(move || {
+                let source = source.clone();
+                Box::pin(async move { source.into_id().await.unwrap().quote() })
+            }),
+        );
+        if let Some(tag) = opts.tag {
+            query = query.arg("tag", tag);
+        }
+        if let Some(digest) = opts.digest {
+            query = query.arg("digest", digest);
+        }
+        Container {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// Reads every platform variant of an image from an OCI tarball or a directory in OCI image layout.
+    /// The variants can be exported back as a multi-platform image with platformVariants.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn import_variants(&self) -> Vec<Container> {
+        let query = self.selection.select("importVariants");
+        vec![Container {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }]
+    }
+    /// Reads every platform variant of an image from an OCI tarball or a directory in OCI image layout.
+    /// The variants can be exported back as a multi-platform image with platformVariants.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn import_variants_opts<'a>(
+        &self,
+        opts: ContainerImportVariantsOpts<'a>,
+    ) -> Vec<Container> {
+        let mut query = self.selection.select("importVariants");
+        if let Some(source) = opts.source {
+            query = query.arg("source", source);
+        }
+        if let Some(directory) = opts.directory {
+            query = query.arg("directory", directory);
+        }
+        if let Some(tag) = opts.tag {
+            query = query.arg("tag", tag);
+        }
+        if let Some(digest) = opts.digest {
+            query = query.arg("digest", digest);
+        }
+        vec![Container {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }]
+    }
     /// Retrieves the value of the specified label.
     ///
     /// # Arguments
@@ -2420,6 +3083,24 @@ impl Container {
         if let Some(media_types) = opts.media_types {
             query = query.arg("mediaTypes", media_types);
         }
+        if let Some(squash) = opts.squash {
+            query = query.arg("squash", squash);
+        }
+        if let Some(history) = opts.history {
+            query = query.arg("history", history);
+        }
+        if let Some(signing_key) = opts.signing_key {
+            query = query.arg("signingKey", signing_key);
+        }
+        if let Some(signing_key_password) = opts.signing_key_password {
+            query = query.arg("signingKeyPassword", signing_key_password);
+        }
+        if let Some(sbom) = opts.sbom {
+            query = query.arg("sbom", sbom);
+        }
+        if let Some(provenance) = opts.provenance {
+            query = query.arg("provenance", provenance);
+        }
         query.execute(self.graphql_client.clone()).await
     }
     /// Retrieves this container's root filesystem. Mounts are not included.
@@ -2532,6 +3213,30 @@ impl Container {
         if let Some(no_init) = opts.no_init {
             query = query.arg("noInit", no_init);
         }
+        if let Some(timeout) = opts.timeout {
+            query = query.arg("timeout", timeout);
+        }
+        if let Some(cpu_limit) = opts.cpu_limit {
+            query = query.arg("cpuLimit", cpu_limit);
+        }
+        if let Some(memory_limit) = opts.memory_limit {
+            query = query.arg("memoryLimit", memory_limit);
+        }
+        if let Some(pids_limit) = opts.pids_limit {
+            query = query.arg("pidsLimit", pids_limit);
+        }
+        if let Some(healthcheck_exec) = opts.healthcheck_exec {
+            query = query.arg("healthcheckExec", healthcheck_exec);
+        }
+        if let Some(healthcheck_interval) = opts.healthcheck_interval {
+            query = query.arg("healthcheckInterval", healthcheck_interval);
+        }
+        if let Some(healthcheck_timeout) = opts.healthcheck_timeout {
+            query = query.arg("healthcheckTimeout", healthcheck_timeout);
+        }
+        if let Some(healthcheck_retries) = opts.healthcheck_retries {
+            query = query.arg("healthcheckRetries", healthcheck_retries);
+        }
         query.execute(self.graphql_client.clone()).await
     }
     /// Retrieves the user to be set for all commands.
@@ -2845,6 +3550,21 @@ impl Container {
         if let Some(no_init) = opts.no_init {
             query = query.arg("noInit", no_init);
         }
+        if let Some(network_mode) = opts.network_mode {
+            query = query.arg("networkMode", network_mode);
+        }
+        if let Some(timeout) = opts.timeout {
+            query = query.arg("timeout", timeout);
+        }
+        if let Some(cpu_limit) = opts.cpu_limit {
+            query = query.arg("cpuLimit", cpu_limit);
+        }
+        if let Some(memory_limit) = opts.memory_limit {
+            query = query.arg("memoryLimit", memory_limit);
+        }
+        if let Some(pids_limit) = opts.pids_limit {
+            query = query.arg("pidsLimit", pids_limit);
+        }
         Container {
             proc: self.proc.clone(),
             selection: query,
@@ -2894,6 +3614,15 @@ impl Container {
         if let Some(experimental_skip_healthcheck) = opts.experimental_skip_healthcheck {
             query = query.arg("experimentalSkipHealthcheck", experimental_skip_healthcheck);
         }
+        if let Some(healthcheck_path) = opts.healthcheck_path {
+            query = query.arg("healthcheckPath", healthcheck_path);
+        }
+        if let Some(healthcheck_scheme) = opts.healthcheck_scheme {
+            query = query.arg("healthcheckScheme", healthcheck_scheme);
+        }
+        if let Some(healthcheck_status) = opts.healthcheck_status {
+            query = query.arg("healthcheckStatus", healthcheck_status);
+        }
         Container {
             proc: self.proc.clone(),
             selection: query,
@@ -4044,6 +4773,15 @@ pub struct Directory {
     pub graphql_client: DynGraphQLClient,
 }
 #[derive(Builder, Debug, PartialEq)]
+pub struct DirectoryAsComposeOpts<'a> {
+    /// Path of the Compose file. If unset, the first of "compose.yaml", "compose.yml", "docker-compose.yaml" and "docker-compose.yml" that exists is used.
+    #[builder(setter(into, strip_option), default)]
+    pub path: Option<&'a str>,
+    /// Name of the project, which named volumes are scoped to. If unset, the name set in the Compose file is used, or "default".
+    #[builder(setter(into, strip_option), default)]
+    pub project_name: Option<&'a str>,
+}
+#[derive(Builder, Debug, PartialEq)]
 pub struct DirectoryAsModuleOpts<'a> {
     /// The engine version to upgrade to.
     #[builder(setter(into, strip_option), default)]
@@ -4059,9 +4797,19 @@ pub struct DirectoryDockerBuildOpts<'a> {
     /// Build arguments to use in the build.
     #[builder(setter(into, strip_option), default)]
     pub build_args: Option<Vec<BuildArg>>,
+    /// Named build contexts to pass to the build.
+    /// They can be referenced by name in the Dockerfile (e.g., "COPY --from=<name>" or "FROM <name>").
+    #[builder(setter(into, strip_option), default)]
+    pub build_contexts: Option<Vec<BuildContext>>,
+    /// Registry references to import the build cache from (e.g., "registry.example.com/app:cache").
+    #[builder(setter(into, strip_option), default)]
+    pub cache_from: Option<Vec<&'a str>>,
     /// Path to the Dockerfile to use (e.g., "frontend.Dockerfile").
     #[builder(setter(into, strip_option), default)]
     pub dockerfile: Option<&'a str>,
+    /// Build stages to build without using the cache.
+    #[builder(setter(into, strip_option), default)]
+    pub no_cache_filter: Option<Vec<&'a str>>,
     /// The platform to build.
     #[builder(setter(into, strip_option), default)]
     pub platform: Option<Platform>,
@@ -4069,6 +4817,10 @@ pub struct DirectoryDockerBuildOpts<'a> {
     /// They will be mounted at /run/secrets/[secret-name].
     #[builder(setter(into, strip_option), default)]
     pub secrets: Option<Vec<SecretId>>,
+    /// A socket to forward to the build as the default SSH agent.
+    /// It is available to "RUN --mount=type=ssh" instructions.
+    #[builder(setter(into, strip_option), default)]
+    pub ssh: Option<SocketId>,
     /// Target build stage to build.
     #[builder(setter(into, strip_option), default)]
     pub target: Option<&'a str>,
@@ -4135,6 +4887,40 @@ pub struct DirectoryWithNewFileOpts {
     pub permissions: Option<isize>,
 }
 impl Directory {
+    /// Load a Compose project from a Compose file in this directory.
+    /// Paths in the Compose file, such as build contexts and bind mounts, are resolved relative to the Compose file's directory. Variables are interpolated from a .env file next to the Compose file, if any.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn as_compose(&self) -> ComposeProject {
+        let query = self.selection.select("asCompose");
+        ComposeProject {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// Load a Compose project from a Compose file in this directory.
+    /// Paths in the Compose file, such as build contexts and bind mounts, are resolved relative to the Compose file's directory. Variables are interpolated from a .env file next to the Compose file, if any.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn as_compose_opts<'a>(&self, opts: DirectoryAsComposeOpts<'a>) -> ComposeProject {
+        let mut query = self.selection.select("asCompose");
+        if let Some(path) = opts.path {
+            query = query.arg("path", path);
+        }
+        if let Some(project_name) = opts.project_name {
+            query = query.arg("projectName", project_name);
+        }
+        ComposeProject {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
     /// Load the directory as a Dagger module
     ///
     /// # Arguments
@@ -4167,6 +4953,26 @@ impl Directory {
             graphql_client: self.graphql_client.clone(),
         }
     }
+    /// Returns a unified diff, in the format of "git diff", of the files changed between this directory and another directory.
+    ///
+    /// # Arguments
+    ///
+    /// * `other` - Identifier of the directory to compare.
+    pub fn as_patch(&self, other: impl IntoID<DirectoryId>) -> File {
+        let mut query = self.selection.select("asPatch");
+        query = query.arg_lazy(
+            "other",
+            Box::new(move || {
+                let other = other.clone();
+                Box::pin(async move { other.into_id().await.unwrap().quote() })
+            }),
+        );
+        File {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
     /// Gets the difference between this directory and an another directory.
     ///
     /// # Arguments
@@ -4241,6 +5047,18 @@ impl Directory {
         if let Some(secrets) = opts.secrets {
             query = query.arg("secrets", secrets);
         }
+        if let Some(build_contexts) = opts.build_contexts {
+            query = query.arg("buildContexts", build_contexts);
+        }
+        if let Some(ssh) = opts.ssh {
+            query = query.arg("ssh", ssh);
+        }
+        if let Some(no_cache_filter) = opts.no_cache_filter {
+            query = query.arg("noCacheFilter", no_cache_filter);
+        }
+        if let Some(cache_from) = opts.cache_from {
+            query = query.arg("cacheFrom", cache_from);
+        }
         Container {
             proc: self.proc.clone(),
             selection: query,
@@ -4329,6 +5147,21 @@ impl Directory {
         let query = self.selection.select("id");
         query.execute(self.graphql_client.clone()).await
     }
+    /// Retrieves this directory with the contents of the given directories merged into it.
+    /// Fails if the same path holds different contents in more than one of the directories, instead of overwriting it.
+    ///
+    /// # Arguments
+    ///
+    /// * `directories` - Identifiers of the directories to merge, in order.
+    pub fn merge(&self, directories: Vec<DirectoryId>) -> Directory {
+        let mut query = self.selection.select("merge");
+        query = query.arg("directories", directories);
+        Directory {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
     /// Force evaluation in the engine.
     pub async fn sync(&self) -> Result<DirectoryId, DaggerError> {
         let query = self.selection.select("sync");
@@ -4611,6 +5444,27 @@ impl Directory {
             graphql_client: self.graphql_client.clone(),
         }
     }
+    /// Retrieves this directory with the given unified diff applied.
+    /// Fails if any hunk of the patch does not apply cleanly.
+    ///
+    /// # Arguments
+    ///
+    /// * `patch` - File containing the patch to apply, as produced by "diff -u" or "git diff".
+    pub fn with_patch(&self, patch: impl IntoID<FileId>) -> Directory {
+        let mut query = self.selection.select("withPatch");
+        query = query.arg_lazy(
+            "patch",
+            Box::new(move || {
+                let patch = patch.clone();
+                Box::pin(async move { patch.into_id().await.unwrap().quote() })
+            }),
+        );
+        Directory {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
     /// Retrieves this directory with all file/dir timestamps set to the given time.
     ///
     /// # Arguments
@@ -5003,6 +5857,12 @@ pub struct File {
     pub graphql_client: DynGraphQLClient,
 }
 #[derive(Builder, Debug, PartialEq)]
+pub struct FileAsComposeOpts<'a> {
+    /// Name of the project, which named volumes are scoped to. If unset, the name set in the Compose file is used, or "default".
+    #[builder(setter(into, strip_option), default)]
+    pub project_name: Option<&'a str>,
+}
+#[derive(Builder, Debug, PartialEq)]
 pub struct FileDigestOpts {
     /// If true, exclude metadata from the digest.
     #[builder(setter(into, strip_option), default)]
@@ -5015,6 +5875,37 @@ pub struct FileExportOpts {
     pub allow_parent_dir_path: Option<bool>,
 }
 impl File {
+    /// Load a Compose project from this Compose file.
+    /// Services that are built or that bind mount paths can only be loaded from a directory with Directory.asCompose.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn as_compose(&self) -> ComposeProject {
+        let query = self.selection.select("asCompose");
+        ComposeProject {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// Load a Compose project from this Compose file.
+    /// Services that are built or that bind mount paths can only be loaded from a directory with Directory.asCompose.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn as_compose_opts<'a>(&self, opts: FileAsComposeOpts<'a>) -> ComposeProject {
+        let mut query = self.selection.select("asCompose");
+        if let Some(project_name) = opts.project_name {
+            query = query.arg("projectName", project_name);
+        }
+        ComposeProject {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
     /// Retrieves the contents of the file.
     pub async fn contents(&self) -> Result<String, DaggerError> {
         let query = self.selection.select("contents");
@@ -5489,6 +6380,85 @@ impl GeneratedCode {
     }
 }
 #[derive(Clone)]
+pub struct GitActor {
+    pub proc: Option<Arc<DaggerSessionProc>>,
+    pub selection: Selection,
+    pub graphql_client: DynGraphQLClient,
+}
+impl GitActor {
+    /// The date of the action, in RFC 3339 format.
+    pub async fn date(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("date");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The email address of the person.
+    pub async fn email(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("email");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// A unique identifier for this GitActor.
+    pub async fn id(&self) -> Result<GitActorId, DaggerError> {
+        let query = self.selection.select("id");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The name of the person.
+    pub async fn name(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("name");
+        query.execute(self.graphql_client.clone()).await
+    }
+}
+#[derive(Clone)]
+pub struct GitCommitInfo {
+    pub proc: Option<Arc<DaggerSessionProc>>,
+    pub selection: Selection,
+    pub graphql_client: DynGraphQLClient,
+}
+impl GitCommitInfo {
+    /// The author of the commit.
+    pub fn author(&self) -> GitActor {
+        let query = self.selection.select("author");
+        GitActor {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// The committer of the commit.
+    pub fn committer(&self) -> GitActor {
+        let query = self.selection.select("committer");
+        GitActor {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// The date the commit was authored, in RFC 3339 format.
+    pub async fn date(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("date");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// A unique identifier for this GitCommitInfo.
+    pub async fn id(&self) -> Result<GitCommitInfoId, DaggerError> {
+        let query = self.selection.select("id");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The full message of the commit.
+    pub async fn message(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("message");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The SHAs of the parents of the commit.
+    pub async fn parents(&self) -> Result<Vec<String>, DaggerError> {
+        let query = self.selection.select("parents");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The SHA of the commit.
+    pub async fn sha(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("sha");
+        query.execute(self.graphql_client.clone()).await
+    }
+}
+#[derive(Clone)]
 pub struct GitModuleSource {
     pub proc: Option<Arc<DaggerSessionProc>>,
     pub selection: Selection,
@@ -5552,10 +6522,34 @@ pub struct GitRef {
     pub graphql_client: DynGraphQLClient,
 }
 #[derive(Builder, Debug, PartialEq)]
-pub struct GitRefTreeOpts {
+pub struct GitRefTreeOpts<'a> {
+    /// The number of commits of history to fetch into the .git directory.
+    #[builder(setter(into, strip_option), default)]
+    pub depth: Option<isize>,
     /// Set to true to discard .git directory.
     #[builder(setter(into, strip_option), default)]
     pub discard_git_dir: Option<bool>,
+    /// Set to true to fetch the full history and tags into the .git directory, e.g. for "git describe". Overrides depth.
+    #[builder(setter(into, strip_option), default)]
+    pub full_history: Option<bool>,
+    /// Set to true to replace Git LFS pointer files with the content they point to. LFS objects are cached by the engine, so they're only downloaded once.
+    #[builder(setter(into, strip_option), default)]
+    pub lfs: Option<bool>,
+    /// Set to true to not check out submodules. By default, submodules are checked out recursively.
+    #[builder(setter(into, strip_option), default)]
+    pub skip_submodules: Option<bool>,
+    /// Only check out the paths matching these patterns, which use the syntax of .gitignore files (e.g., ["/docs/", "*.md"]).
+    #[builder(setter(into, strip_option), default)]
+    pub sparse_checkout: Option<Vec<&'a str>>,
+}
+#[derive(Builder, Debug, PartialEq)]
+pub struct GitRefVerifyOpts {
+    /// SSH allowed signers file trusted to sign the ref, in the format read by "ssh-keygen -Y verify".
+    #[builder(setter(into, strip_option), default)]
+    pub allowed_signers: Option<FileId>,
+    /// GPG public keys trusted to sign the ref.
+    #[builder(setter(into, strip_option), default)]
+    pub keys: Option<Vec<FileId>>,
 }
 impl GitRef {
     /// The resolved commit id at this ref.
@@ -5563,11 +6557,35 @@ impl GitRef {
         let query = self.selection.select("commit");
         query.execute(self.graphql_client.clone()).await
     }
+    /// The metadata of the resolved commit at this ref.
+    pub fn commit_info(&self) -> GitCommitInfo {
+        let query = self.selection.select("commitInfo");
+        GitCommitInfo {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// The nearest tag reachable from this ref, as returned by "git describe --tags".
+    /// If the tag doesn't point at the ref itself, it's suffixed with the number of commits since the tag and the abbreviated commit id (e.g., "v0.3.9-4-gb6315d8").
+    pub async fn describe(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("describe");
+        query.execute(self.graphql_client.clone()).await
+    }
     /// A unique identifier for this GitRef.
     pub async fn id(&self) -> Result<GitRefId, DaggerError> {
         let query = self.selection.select("id");
         query.execute(self.graphql_client.clone()).await
     }
+    /// The signature of this ref, if it was verified by "verify".
+    pub fn signature(&self) -> GitSignature {
+        let query = self.selection.select("signature");
+        GitSignature {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
     /// The filesystem tree at this ref.
     ///
     /// # Arguments
@@ -5586,17 +6604,66 @@ impl GitRef {
     /// # Arguments
     ///
     /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
-    pub fn tree_opts(&self, opts: GitRefTreeOpts) -> Directory {
+    pub fn tree_opts<'a>(&self, opts: GitRefTreeOpts<'a>) -> Directory {
         let mut query = self.selection.select("tree");
         if let Some(discard_git_dir) = opts.discard_git_dir {
             query = query.arg("discardGitDir", discard_git_dir);
         }
+        if let Some(sparse_checkout) = opts.sparse_checkout {
+            query = query.arg("sparseCheckout", sparse_checkout);
+        }
+        if let Some(depth) = opts.depth {
+            query = query.arg("depth", depth);
+        }
+        if let Some(full_history) = opts.full_history {
+            query = query.arg("fullHistory", full_history);
+        }
+        if let Some(skip_submodules) = opts.skip_submodules {
+            query = query.arg("skipSubmodules", skip_submodules);
+        }
+        if let Some(lfs) = opts.lfs {
+            query = query.arg("lfs", lfs);
+        }
         Directory {
             proc: self.proc.clone(),
             selection: query,
             graphql_client: self.graphql_client.clone(),
         }
     }
+    /// Verify the GPG or SSH signature of the annotated tag or commit at this ref, failing if it's missing or not made by one of the trusted keys.
+    /// Returns this ref pinned to the verified commit.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn verify(&self) -> GitRef {
+        let query = self.selection.select("verify");
+        GitRef {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// Verify the GPG or SSH signature of the annotated tag or commit at this ref, failing if it's missing or not made by one of the trusted keys.
+    /// Returns this ref pinned to the verified commit.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn verify_opts(&self, opts: GitRefVerifyOpts) -> GitRef {
+        let mut query = self.selection.select("verify");
+        if let Some(keys) = opts.keys {
+            query = query.arg("keys", keys);
+        }
+        if let Some(allowed_signers) = opts.allowed_signers {
+            query = query.arg("allowedSigners", allowed_signers);
+        }
+        GitRef {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
 }
 #[derive(Clone)]
 pub struct GitRepository {
@@ -5605,6 +6672,18 @@ pub struct GitRepository {
     pub graphql_client: DynGraphQLClient,
 }
 #[derive(Builder, Debug, PartialEq)]
+pub struct GitRepositoryLogOpts<'a> {
+    /// Exclude the commits reachable from this ref (e.g., "v0.3.8"). If empty, the whole history is returned.
+    #[builder(setter(into, strip_option), default)]
+    pub from: Option<&'a str>,
+    /// Only return commits that change one of these paths.
+    #[builder(setter(into, strip_option), default)]
+    pub paths: Option<Vec<&'a str>>,
+    /// Ref to list the history of (e.g., "main"). If empty, HEAD is used.
+    #[builder(setter(into, strip_option), default)]
+    pub to: Option<&'a str>,
+}
+#[derive(Builder, Debug, PartialEq)]
 pub struct GitRepositoryTagsOpts<'a> {
     /// Glob patterns (e.g., "refs/tags/v*").
     #[builder(setter(into, strip_option), default)]
@@ -5653,6 +6732,41 @@ impl GitRepository {
         let query = self.selection.select("id");
         query.execute(self.graphql_client.clone()).await
     }
+    /// Returns the commits reachable from a ref, newest first.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn log(&self) -> Vec<GitCommitInfo> {
+        let query = self.selection.select("log");
+        vec![GitCommitInfo {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }]
+    }
+    /// Returns the commits reachable from a ref, newest first.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn log_opts<'a>(&self, opts: GitRepositoryLogOpts<'a>) -> Vec<GitCommitInfo> {
+        let mut query = self.selection.select("log");
+        if let Some(from) = opts.from {
+            query = query.arg("from", from);
+        }
+        if let Some(to) = opts.to {
+            query = query.arg("to", to);
+        }
+        if let Some(paths) = opts.paths {
+            query = query.arg("paths", paths);
+        }
+        vec![GitCommitInfo {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }]
+    }
     /// Returns details of a ref.
     ///
     /// # Arguments
@@ -5747,6 +6861,34 @@ impl GitRepository {
     }
 }
 #[derive(Clone)]
+pub struct GitSignature {
+    pub proc: Option<Arc<DaggerSessionProc>>,
+    pub selection: Selection,
+    pub graphql_client: DynGraphQLClient,
+}
+impl GitSignature {
+    /// The format of the signature, "gpg" or "ssh".
+    pub async fn format(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("format");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// A unique identifier for this GitSignature.
+    pub async fn id(&self) -> Result<GitSignatureId, DaggerError> {
+        let query = self.selection.select("id");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The fingerprint of the signing key.
+    pub async fn key(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("key");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The identity of the signer: the user ID of the GPG key, or the principal of the SSH key in the allowed signers.
+    pub async fn signer(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("signer");
+        query.execute(self.graphql_client.clone()).await
+    }
+}
+#[derive(Clone)]
 pub struct Host {
     pub proc: Option<Arc<DaggerSessionProc>>,
     pub selection: Selection,
@@ -5962,6 +7104,62 @@ impl Host {
     }
 }
 #[derive(Clone)]
+pub struct ImageConfig {
+    pub proc: Option<Arc<DaggerSessionProc>>,
+    pub selection: Selection,
+    pub graphql_client: DynGraphQLClient,
+}
+impl ImageConfig {
+    /// The default arguments of the image's entrypoint.
+    pub async fn default_args(&self) -> Result<Vec<String>, DaggerError> {
+        let query = self.selection.select("defaultArgs");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The entrypoint of the image.
+    pub async fn entrypoint(&self) -> Result<Vec<String>, DaggerError> {
+        let query = self.selection.select("entrypoint");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The environment variables of the image.
+    pub fn env_variables(&self) -> Vec<EnvVariable> {
+        let query = self.selection.select("envVariables");
+        vec![EnvVariable {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }]
+    }
+    /// A unique identifier for this ImageConfig.
+    pub async fn id(&self) -> Result<ImageConfigId, DaggerError> {
+        let query = self.selection.select("id");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The labels of the image, sorted by name.
+    pub fn labels(&self) -> Vec<Label> {
+        let query = self.selection.select("labels");
+        vec![Label {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }]
+    }
+    /// The platform of the image.
+    pub async fn platform(&self) -> Result<Platform, DaggerError> {
+        let query = self.selection.select("platform");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The user of the image.
+    pub async fn user(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("user");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The working directory of the image.
+    pub async fn workdir(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("workdir");
+        query.execute(self.graphql_client.clone()).await
+    }
+}
+#[derive(Clone)]
 pub struct InputTypeDef {
     pub proc: Option<Arc<DaggerSessionProc>>,
     pub selection: Selection,
@@ -6947,6 +8145,13 @@ pub struct QueryContainerOpts {
     pub platform: Option<Platform>,
 }
 #[derive(Builder, Debug, PartialEq)]
+pub struct QueryExplainCacheMissOpts<'a> {
+    /// Digest of the call to compare to.
+    /// Defaults to the most recent call of the same fields in an earlier run.
+    #[builder(setter(into, strip_option), default)]
+    pub previous_digest: Option<&'a str>,
+}
+#[derive(Builder, Debug, PartialEq)]
 pub struct QueryGitOpts<'a> {
     /// A service which must be started before the repo is fetched.
     #[builder(setter(into, strip_option), default)]
@@ -6962,10 +8167,28 @@ pub struct QueryGitOpts<'a> {
     pub ssh_known_hosts: Option<&'a str>,
 }
 #[derive(Builder, Debug, PartialEq)]
-pub struct QueryHttpOpts {
+pub struct QueryHttpOpts<'a> {
+    /// Secret sent as the value of the Authorization header (e.g., "Basic <base64 of user:password>"). Takes precedence over authToken.
+    #[builder(setter(into, strip_option), default)]
+    pub auth_header: Option<SecretId>,
+    /// Secret sent as a bearer token in the Authorization header.
+    #[builder(setter(into, strip_option), default)]
+    pub auth_token: Option<SecretId>,
+    /// Expected digest of the content (e.g., "sha256:..."). The fetch fails if the content doesn't match, and is cached by the digest without contacting the server.
+    #[builder(setter(into, strip_option), default)]
+    pub checksum: Option<&'a str>,
     /// A service which must be started before the URL is fetched.
     #[builder(setter(into, strip_option), default)]
     pub experimental_service_host: Option<ServiceId>,
+    /// Headers to send with the request.
+    #[builder(setter(into, strip_option), default)]
+    pub headers: Option<Vec<HttpHeader>>,
+    /// Name of the file. Defaults to a digest of the URL.
+    #[builder(setter(into, strip_option), default)]
+    pub name: Option<&'a str>,
+    /// Permissions of the file (e.g., 0755). Defaults to 0600.
+    #[builder(setter(into, strip_option), default)]
+    pub permissions: Option<isize>,
 }
 #[derive(Builder, Debug, PartialEq)]
 pub struct QueryLoadSecretFromNameOpts<'a> {
@@ -7138,6 +8361,45 @@ impl Query {
             graphql_client: self.graphql_client.clone(),
         }
     }
+    /// Explains why a call missed the cache, by comparing it to the same call in a previous run.
+    /// Calls are looked up in the telemetry the engine keeps of its recent clients. The first difference is reported, in the order the calls are evaluated: the objects that calls are chained from first, then arguments, then content.
+    ///
+    /// # Arguments
+    ///
+    /// * `digest` - Digest of the call that missed the cache, as recorded in its span (e.g., "xxh3:...").
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn explain_cache_miss(&self, digest: impl Into<String>) -> CacheMissExplanation {
+        let mut query = self.selection.select("explainCacheMiss");
+        query = query.arg("digest", digest.into());
+        CacheMissExplanation {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// Explains why a call missed the cache, by comparing it to the same call in a previous run.
+    /// Calls are looked up in the telemetry the engine keeps of its recent clients. The first difference is reported, in the order the calls are evaluated: the objects that calls are chained from first, then arguments, then content.
+    ///
+    /// # Arguments
+    ///
+    /// * `digest` - Digest of the call that missed the cache, as recorded in its span (e.g., "xxh3:...").
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn explain_cache_miss_opts<'a>(
+        &self,
+        digest: impl Into<String>,
+        opts: QueryExplainCacheMissOpts<'a>,
+    ) -> CacheMissExplanation {
+        let mut query = self.selection.select("explainCacheMiss");
+        query = query.arg("digest", digest.into());
+        if let Some(previous_digest) = opts.previous_digest {
+            query = query.arg("previousDigest", previous_digest);
+        }
+        CacheMissExplanation {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
     /// Creates a function.
     ///
     /// # Arguments
@@ -7260,18 +8522,55 @@ impl Query {
     ///
     /// * `url` - HTTP url to get the content from (e.g., "https://docs.dagger.io").
     /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
-    pub fn http_opts(&self, url: impl Into<String>, opts: QueryHttpOpts) -> File {
+    pub fn http_opts<'a>(&self, url: impl Into<String>, opts: QueryHttpOpts<'a>) -> File {
         let mut query = self.selection.select("http");
         query = query.arg("url", url.into());
         if let Some(experimental_service_host) = opts.experimental_service_host {
             query = query.arg("experimentalServiceHost", experimental_service_host);
         }
+        if let Some(checksum) = opts.checksum {
+            query = query.arg("checksum", checksum);
+        }
+        if let Some(headers) = opts.headers {
+            query = query.arg("headers", headers);
+        }
+        if let Some(auth_token) = opts.auth_token {
+            query = query.arg("authToken", auth_token);
+        }
+        if let Some(auth_header) = opts.auth_header {
+            query = query.arg("authHeader", auth_header);
+        }
+        if let Some(name) = opts.name {
+            query = query.arg("name", name);
+        }
+        if let Some(permissions) = opts.permissions {
+            query = query.arg("permissions", permissions);
+        }
         File {
             proc: self.proc.clone(),
             selection: query,
             graphql_client: self.graphql_client.clone(),
         }
     }
+    /// Load a CacheMissExplanation from its ID.
+    pub fn load_cache_miss_explanation_from_id(
+        &self,
+        id: impl IntoID<CacheMissExplanationId>,
+    ) -> CacheMissExplanation {
+        let mut query = self.selection.select("loadCacheMissExplanationFromID");
+        query = query.arg_lazy(
+            "id",
+            Box::new(move || {
+                let id = id.clone();
+                Box::pin(async move { id.into_id().await.unwrap().quote() })
+            }),
+        );
+        CacheMissExplanation {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
     /// Load a CacheVolume from its ID.
     pub fn load_cache_volume_from_id(&self, id: impl IntoID<CacheVolumeId>) -> CacheVolume {
         let mut query = self.selection.select("loadCacheVolumeFromID");
@@ -7282,7 +8581,26 @@ impl Query {
                 Box::pin(async move { id.into_id().await.unwrap().quote() })
             }),
         );
-        CacheVolume {
+        CacheVolume {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// Load a ComposeProject from its ID.
+    pub fn load_compose_project_from_id(
+        &self,
+        id: impl IntoID<ComposeProjectId>,
+    ) -> ComposeProject {
+        let mut query = self.selection.select("loadComposeProjectFromID");
+        query = query.arg_lazy(
+            "id",
+            Box::new(move || {
+                let id = id.clone();
+                Box::pin(async move { id.into_id().await.unwrap().quote() })
+            }),
+        );
+        ComposeProject {
             proc: self.proc.clone(),
             selection: query,
             graphql_client: self.graphql_client.clone(),
@@ -7588,6 +8906,38 @@ impl Query {
             graphql_client: self.graphql_client.clone(),
         }
     }
+    /// Load a GitActor from its ID.
+    pub fn load_git_actor_from_id(&self, id: impl IntoID<GitActorId>) -> GitActor {
+        let mut query = self.selection.select("loadGitActorFromID");
+        query = query.arg_lazy(
+            "id",
+            Box::new(move || {
+                let id = id.clone();
+                Box::pin(async move { id.into_id().await.unwrap().quote() })
+            }),
+        );
+        GitActor {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// Load a GitCommitInfo from its ID.
+    pub fn load_git_commit_info_from_id(&self, id: impl IntoID<GitCommitInfoId>) -> GitCommitInfo {
+        let mut query = self.selection.select("loadGitCommitInfoFromID");
+        query = query.arg_lazy(
+            "id",
+            Box::new(move || {
+                let id = id.clone();
+                Box::pin(async move { id.into_id().await.unwrap().quote() })
+            }),
+        );
+        GitCommitInfo {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
     /// Load a GitModuleSource from its ID.
     pub fn load_git_module_source_from_id(
         &self,
@@ -7639,6 +8989,22 @@ impl Query {
             graphql_client: self.graphql_client.clone(),
         }
     }
+    /// Load a GitSignature from its ID.
+    pub fn load_git_signature_from_id(&self, id: impl IntoID<GitSignatureId>) -> GitSignature {
+        let mut query = self.selection.select("loadGitSignatureFromID");
+        query = query.arg_lazy(
+            "id",
+            Box::new(move || {
+                let id = id.clone();
+                Box::pin(async move { id.into_id().await.unwrap().quote() })
+            }),
+        );
+        GitSignature {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
     /// Load a Host from its ID.
     pub fn load_host_from_id(&self, id: impl IntoID<HostId>) -> Host {
         let mut query = self.selection.select("loadHostFromID");
@@ -7655,6 +9021,22 @@ impl Query {
             graphql_client: self.graphql_client.clone(),
         }
     }
+    /// Load a ImageConfig from its ID.
+    pub fn load_image_config_from_id(&self, id: impl IntoID<ImageConfigId>) -> ImageConfig {
+        let mut query = self.selection.select("loadImageConfigFromID");
+        query = query.arg_lazy(
+            "id",
+            Box::new(move || {
+                let id = id.clone();
+                Box::pin(async move { id.into_id().await.unwrap().quote() })
+            }),
+        );
+        ImageConfig {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
     /// Load a InputTypeDef from its ID.
     pub fn load_input_type_def_from_id(&self, id: impl IntoID<InputTypeDefId>) -> InputTypeDef {
         let mut query = self.selection.select("loadInputTypeDefFromID");
@@ -7843,6 +9225,22 @@ impl Query {
             graphql_client: self.graphql_client.clone(),
         }
     }
+    /// Load a Registry from its ID.
+    pub fn load_registry_from_id(&self, id: impl IntoID<RegistryId>) -> Registry {
+        let mut query = self.selection.select("loadRegistryFromID");
+        query = query.arg_lazy(
+            "id",
+            Box::new(move || {
+                let id = id.clone();
+                Box::pin(async move { id.into_id().await.unwrap().quote() })
+            }),
+        );
+        Registry {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
     /// Load a SDKConfig from its ID.
     pub fn load_sdk_config_from_id(&self, id: impl IntoID<SdkConfigId>) -> SdkConfig {
         let mut query = self.selection.select("loadSDKConfigFromID");
@@ -8107,11 +9505,32 @@ impl Query {
             graphql_client: self.graphql_client.clone(),
         }
     }
+    /// Inspects an image in a registry, without pulling its layers.
+    /// Credentials set with Container.withRegistryAuth and the client's credentials are used to authenticate to the registry.
+    ///
+    /// # Arguments
+    ///
+    /// * `address` - Address of the image (e.g., "alpine", "docker.io/library/alpine:3.20" or "alpine@sha256:...").
+    ///
+    /// The tag defaults to "latest".
+    pub fn registry(&self, address: impl Into<String>) -> Registry {
+        let mut query = self.selection.select("registry");
+        query = query.arg("address", address.into());
+        Registry {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
     /// Creates a new secret.
     ///
     /// # Arguments
     ///
     /// * `uri` - The URI of the secret store
+    ///
+    /// The scheme selects the provider that resolves the secret on the client, e.g. env://, file://, cmd://, op://, vault://, sops://, keyring:// or aws://. Other schemes are resolved by a dagger-secret-[scheme] executable on the client's PATH.
+    ///
+    /// Only the syntax of the URI is checked here: a scheme the client has no provider for fails when the secret is first used.
     pub fn secret(&self, uri: impl Into<String>) -> Secret {
         let mut query = self.selection.select("secret");
         query = query.arg("uri", uri.into());
@@ -8172,6 +9591,105 @@ impl Query {
     }
 }
 #[derive(Clone)]
+pub struct Registry {
+    pub proc: Option<Arc<DaggerSessionProc>>,
+    pub selection: Selection,
+    pub graphql_client: DynGraphQLClient,
+}
+#[derive(Builder, Debug, PartialEq)]
+pub struct RegistryConfigOpts {
+    /// Platform of the image. Defaults to the platform of the engine.
+    #[builder(setter(into, strip_option), default)]
+    pub platform: Option<Platform>,
+}
+#[derive(Builder, Debug, PartialEq)]
+pub struct RegistryManifestDigestOpts {
+    /// Platform of the manifest. Defaults to the platform of the engine.
+    #[builder(setter(into, strip_option), default)]
+    pub platform: Option<Platform>,
+}
+impl Registry {
+    /// The normalized reference of the image (e.g., "docker.io/library/alpine:latest").
+    pub async fn address(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("address");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The config of the image for a platform.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn config(&self) -> ImageConfig {
+        let query = self.selection.select("config");
+        ImageConfig {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// The config of the image for a platform.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn config_opts(&self, opts: RegistryConfigOpts) -> ImageConfig {
+        let mut query = self.selection.select("config");
+        if let Some(platform) = opts.platform {
+            query = query.arg("platform", platform);
+        }
+        ImageConfig {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// The digest of the image's manifest or index.
+    pub async fn digest(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("digest");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// A unique identifier for this Registry.
+    pub async fn id(&self) -> Result<RegistryId, DaggerError> {
+        let query = self.selection.select("id");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The digest of the image's manifest for a platform.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub async fn manifest_digest(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("manifestDigest");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The digest of the image's manifest for a platform.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub async fn manifest_digest_opts(
+        &self,
+        opts: RegistryManifestDigestOpts,
+    ) -> Result<String, DaggerError> {
+        let mut query = self.selection.select("manifestDigest");
+        if let Some(platform) = opts.platform {
+            query = query.arg("platform", platform);
+        }
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The platforms of the image, in the order of its index.
+    /// Attestations and signatures are skipped.
+    pub async fn platforms(&self) -> Result<Vec<Platform>, DaggerError> {
+        let query = self.selection.select("platforms");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The tags of the image's repository.
+    pub async fn tags(&self) -> Result<Vec<String>, DaggerError> {
+        let query = self.selection.select("tags");
+        query.execute(self.graphql_client.clone()).await
+    }
+}
+#[derive(Clone)]
 pub struct SdkConfig {
     pub proc: Option<Arc<DaggerSessionProc>>,
     pub selection: Selection,
@@ -8239,6 +9757,12 @@ impl Secret {
         let query = self.selection.select("plaintext");
         query.execute(self.graphql_client.clone()).await
     }
+    /// Resolves this secret again from its provider, replacing the value the client cached for the session, e.g. after it was rotated.
+    /// Secrets set from a plaintext have nothing to refresh.
+    pub async fn refresh(&self) -> Result<Void, DaggerError> {
+        let query = self.selection.select("refresh");
+        query.execute(self.graphql_client.clone()).await
+    }
     /// The URI of this secret.
     pub async fn uri(&self) -> Result<String, DaggerError> {
         let query = self.selection.select("uri");
@@ -8261,6 +9785,24 @@ pub struct ServiceEndpointOpts<'a> {
     pub scheme: Option<&'a str>,
 }
 #[derive(Builder, Debug, PartialEq)]
+pub struct ServiceStderrOpts<'a> {
+    /// Only return output written since this point in time, either as a duration (e.g., "10m") or as an RFC 3339 timestamp.
+    #[builder(setter(into, strip_option), default)]
+    pub since: Option<&'a str>,
+    /// Only return this many lines from the end of the output.
+    #[builder(setter(into, strip_option), default)]
+    pub tail: Option<isize>,
+}
+#[derive(Builder, Debug, PartialEq)]
+pub struct ServiceStdoutOpts<'a> {
+    /// Only return output written since this point in time, either as a duration (e.g., "10m") or as an RFC 3339 timestamp.
+    #[builder(setter(into, strip_option), default)]
+    pub since: Option<&'a str>,
+    /// Only return this many lines from the end of the output.
+    #[builder(setter(into, strip_option), default)]
+    pub tail: Option<isize>,
+}
+#[derive(Builder, Debug, PartialEq)]
 pub struct ServiceStopOpts {
     /// Immediately kill the service without waiting for a graceful exit
     #[builder(setter(into, strip_option), default)]
@@ -8276,6 +9818,21 @@ pub struct ServiceUpOpts {
     #[builder(setter(into, strip_option), default)]
     pub random: Option<bool>,
 }
+#[derive(Builder, Debug, PartialEq)]
+pub struct ServiceWithLivenessProbeOpts {
+    /// Number of consecutive failed health checks after which the service is killed.
+    #[builder(setter(into, strip_option), default)]
+    pub failure_threshold: Option<isize>,
+    /// Number of seconds between each health check.
+    #[builder(setter(into, strip_option), default)]
+    pub interval: Option<isize>,
+}
+#[derive(Builder, Debug, PartialEq)]
+pub struct ServiceWithRestartPolicyOpts {
+    /// Maximum number of times to restart the service. If unset, there is no limit.
+    #[builder(setter(into, strip_option), default)]
+    pub max_retries: Option<isize>,
+}
 impl Service {
     /// Retrieves an endpoint that clients can use to reach this container.
     /// If no port is specified, the first exposed port is used. If none exist an error is returned.
@@ -8308,6 +9865,12 @@ impl Service {
         }
         query.execute(self.graphql_client.clone()).await
     }
+    /// The exit code the service last exited with, or null if it hasn't exited yet.
+    /// If the service has been restarted, this is the exit code of the run before the current one until that one exits too. The service must have been started within the session.
+    pub async fn exit_code(&self) -> Result<isize, DaggerError> {
+        let query = self.selection.select("exitCode");
+        query.execute(self.graphql_client.clone()).await
+    }
     /// Retrieves a hostname which can be used by clients to reach this container.
     pub async fn hostname(&self) -> Result<String, DaggerError> {
         let query = self.selection.select("hostname");
@@ -8333,6 +9896,64 @@ impl Service {
         let query = self.selection.select("start");
         query.execute(self.graphql_client.clone()).await
     }
+    /// The output the service has written to stderr.
+    /// The service must have been started within the session. Its output remains available once it has stopped, and includes the output of every run if it has restarted.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub async fn stderr(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("stderr");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The output the service has written to stderr.
+    /// The service must have been started within the session. Its output remains available once it has stopped, and includes the output of every run if it has restarted.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub async fn stderr_opts<'a>(
+        &self,
+        opts: ServiceStderrOpts<'a>,
+    ) -> Result<String, DaggerError> {
+        let mut query = self.selection.select("stderr");
+        if let Some(tail) = opts.tail {
+            query = query.arg("tail", tail);
+        }
+        if let Some(since) = opts.since {
+            query = query.arg("since", since);
+        }
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The output the service has written to stdout.
+    /// The service must have been started within the session. Its output remains available once it has stopped, and includes the output of every run if it has restarted.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub async fn stdout(&self) -> Result<String, DaggerError> {
+        let query = self.selection.select("stdout");
+        query.execute(self.graphql_client.clone()).await
+    }
+    /// The output the service has written to stdout.
+    /// The service must have been started within the session. Its output remains available once it has stopped, and includes the output of every run if it has restarted.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub async fn stdout_opts<'a>(
+        &self,
+        opts: ServiceStdoutOpts<'a>,
+    ) -> Result<String, DaggerError> {
+        let mut query = self.selection.select("stdout");
+        if let Some(tail) = opts.tail {
+            query = query.arg("tail", tail);
+        }
+        if let Some(since) = opts.since {
+            query = query.arg("since", since);
+        }
+        query.execute(self.graphql_client.clone()).await
+    }
     /// Stop the service.
     ///
     /// # Arguments
@@ -8392,6 +10013,79 @@ impl Service {
             graphql_client: self.graphql_client.clone(),
         }
     }
+    /// Keeps running the service's health checks once it has started.
+    /// The service is killed once its health checks fail enough times in a row, and restarted if its restart policy allows it.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn with_liveness_probe(&self) -> Service {
+        let query = self.selection.select("withLivenessProbe");
+        Service {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// Keeps running the service's health checks once it has started.
+    /// The service is killed once its health checks fail enough times in a row, and restarted if its restart policy allows it.
+    ///
+    /// # Arguments
+    ///
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn with_liveness_probe_opts(&self, opts: ServiceWithLivenessProbeOpts) -> Service {
+        let mut query = self.selection.select("withLivenessProbe");
+        if let Some(interval) = opts.interval {
+            query = query.arg("interval", interval);
+        }
+        if let Some(failure_threshold) = opts.failure_threshold {
+            query = query.arg("failureThreshold", failure_threshold);
+        }
+        Service {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// Configures whether the service is restarted when it exits.
+    /// The service keeps the same hostname across restarts, so containers bound to it can keep reaching it.
+    ///
+    /// # Arguments
+    ///
+    /// * `policy` - When to restart the service.
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn with_restart_policy(&self, policy: ServiceRestartPolicy) -> Service {
+        let mut query = self.selection.select("withRestartPolicy");
+        query = query.arg("policy", policy);
+        Service {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
+    /// Configures whether the service is restarted when it exits.
+    /// The service keeps the same hostname across restarts, so containers bound to it can keep reaching it.
+    ///
+    /// # Arguments
+    ///
+    /// * `policy` - When to restart the service.
+    /// * `opt` - optional argument, see inner type for documentation, use <func>_opts to use
+    pub fn with_restart_policy_opts(
+        &self,
+        policy: ServiceRestartPolicy,
+        opts: ServiceWithRestartPolicyOpts,
+    ) -> Service {
+        let mut query = self.selection.select("withRestartPolicy");
+        query = query.arg("policy", policy);
+        if let Some(max_retries) = opts.max_retries {
+            query = query.arg("maxRetries", max_retries);
+        }
+        Service {
+            proc: self.proc.clone(),
+            selection: query,
+            graphql_client: self.graphql_client.clone(),
+        }
+    }
 }
 #[derive(Clone)]
 pub struct Socket {
@@ -8902,6 +10596,19 @@ impl TypeDef {
     }
 }
 #[derive(Serialize, Deserialize, Clone, PartialEq, Debug)]
+pub enum CacheMissKind {
+    #[serde(rename = "ARGUMENT")]
+    Argument,
+    #[serde(rename = "CALL")]
+    Call,
+    #[serde(rename = "CONTENT")]
+    Content,
+    #[serde(rename = "NONE")]
+    None,
+    #[serde(rename = "RECEIVER")]
+    Receiver,
+}
+#[derive(Serialize, Deserialize, Clone, PartialEq, Debug)]
 pub enum CacheSharingMode {
     #[serde(rename = "LOCKED")]
     Locked,
@@ -8911,6 +10618,20 @@ pub enum CacheSharingMode {
     Shared,
 }
 #[derive(Serialize, Deserialize, Clone, PartialEq, Debug)]
+pub enum HealthcheckScheme {
+    #[serde(rename = "HTTP")]
+    Http,
+    #[serde(rename = "HTTPS")]
+    Https,
+}
+#[derive(Serialize, Deserialize, Clone, PartialEq, Debug)]
+pub enum ImageHistory {
+    #[serde(rename = "FIELDS")]
+    Fields,
+    #[serde(rename = "OPERATIONS")]
+    Operations,
+}
+#[derive(Serialize, Deserialize, Clone, PartialEq, Debug)]
 pub enum ImageLayerCompression {
     #[serde(rename = "EStarGZ")]
     EStarGz,
@@ -8922,6 +10643,15 @@ pub enum ImageLayerCompression {
     Zstd,
 }
 #[derive(Serialize, Deserialize, Clone, PartialEq, Debug)]
+pub enum ImageLayerSquash {
+    #[serde(rename = "ABOVE_BASE")]
+    AboveBase,
+    #[serde(rename = "ALL")]
+    All,
+    #[serde(rename = "NONE")]
+    None,
+}
+#[derive(Serialize, Deserialize, Clone, PartialEq, Debug)]
 pub enum ImageMediaTypes {
     #[serde(rename = "DockerMediaTypes")]
     DockerMediaTypes,
@@ -8936,6 +10666,15 @@ pub enum ModuleSourceKind {
     LocalSource,
 }
 #[derive(Serialize, Deserialize, Clone, PartialEq, Debug)]
+pub enum NetworkMode {
+    #[serde(rename = "DEFAULT")]
+    Default,
+    #[serde(rename = "NONE")]
+    None,
+    #[serde(rename = "SERVICES_ONLY")]
+    ServicesOnly,
+}
+#[derive(Serialize, Deserialize, Clone, PartialEq, Debug)]
 pub enum NetworkProtocol {
     #[serde(rename = "TCP")]
     Tcp,
@@ -8952,6 +10691,15 @@ pub enum ReturnType {
     Success,
 }
 #[derive(Serialize, Deserialize, Clone, PartialEq, Debug)]
+pub enum ServiceRestartPolicy {
+    #[serde(rename = "ALWAYS")]
+    Always,
+    #[serde(rename = "NEVER")]
+    Never,
+    #[serde(rename = "ON_FAILURE")]
+    OnFailure,
+}
+#[derive(Serialize, Deserialize, Clone, PartialEq, Debug)]
 pub enum TypeDefKind {
     #[serde(rename = "BOOLEAN_KIND")]
     BooleanKind,
@@ -29,6 +29,59 @@ export type BuildArg = {
   value: string
 }
 
+export type BuildContext = {
+  /**
+   * The container to use as the build context, including its image config.
+   */
+  container?: Container
+
+  /**
+   * The directory to use as the build context.
+   */
+  directory?: Directory
+
+  /**
+   * The name of the build context, as referenced by the Dockerfile (e.g., in "COPY --from=<name>" or "FROM <name>").
+   */
+  name: string
+}
+
+/**
+ * The `CacheMissExplanationID` scalar type represents an identifier for an object of type CacheMissExplanation.
+ */
+export type CacheMissExplanationID = string & {
+  __CacheMissExplanationID: never
+}
+
+/**
+ * What differs between a call and the same call in a previous run.
+ */
+export enum CacheMissKind {
+  /**
+   * An argument was added, removed or changed.
+   */
+  Argument = "ARGUMENT",
+
+  /**
+   * A call selects a different field, view or module.
+   */
+  Call = "CALL",
+
+  /**
+   * A call with the same arguments has different content, e.g. the files of a host directory.
+   */
+  Content = "CONTENT",
+
+  /**
+   * The calls are the same, so the previous result was pruned from the cache or was never cached.
+   */
+  None = "NONE",
+
+  /**
+   * A call is chained from an object in only one of the runs.
+   */
+  Receiver = "RECEIVER",
+}
 /**
  * Sharing mode of the cache volume.
  */
@@ -53,6 +106,11 @@ export enum CacheSharingMode {
  */
 export type CacheVolumeID = string & { __CacheVolumeID: never }
 
+/**
+ * The `ComposeProjectID` scalar type represents an identifier for an object of type ComposeProject.
+ */
+export type ComposeProjectID = string & { __ComposeProjectID: never }
+
 export type ContainerAsServiceOpts = {
   /**
    * Command to run instead of the container's default command (e.g., ["go", "run", "main.go"]).
@@ -89,6 +147,52 @@ export type ContainerAsServiceOpts = {
    * This should only be used if the user requires that their exec process be the pid 1 process in the container. Otherwise it may result in unexpected behavior.
    */
   noInit?: boolean
+
+  /**
+   * Number of seconds the service may run for before it's killed.
+   */
+  timeout?: number
+
+  /**
+   * Number of CPUs the service may use (e.g., 0.5).
+   */
+  cpuLimit?: float
+
+  /**
+   * Memory in bytes the service may use before it's killed (e.g., 536870912 for 512 MiB).
+   */
+  memoryLimit?: number
+
+  /**
+   * Number of processes the service may have running at once.
+   */
+  pidsLimit?: number
+
+  /**
+   * Command to run in the service container to check that it's healthy (e.g., ["pg_isready"]).
+   *
+   * It runs once the exposed ports are reachable, and the service is healthy once it exits successfully.
+   */
+  healthcheckExec?: string[]
+
+  /**
+   * Number of seconds to wait between health check attempts.
+   *
+   * If unset, attempts back off exponentially.
+   */
+  healthcheckInterval?: number
+
+  /**
+   * Number of seconds each health check attempt may take.
+   */
+  healthcheckTimeout?: number
+
+  /**
+   * Number of times to retry a failing health check before the service fails to start.
+   *
+   * If unset, health checks are retried for up to 15 minutes.
+   */
+  healthcheckRetries?: number
 }
 
 export type ContainerAsTarballOpts = {
@@ -112,6 +216,40 @@ export type ContainerAsTarballOpts = {
    * Defaults to OCI, which is largely compatible with most recent container runtimes, but Docker may be needed for older runtimes without OCI support.
    */
   mediaTypes?: ImageMediaTypes
+
+  /**
+   * Squash the layers of the image into one, or the layers above the image the container was created from with "from" or "import".
+   *
+   * Squashing drops the files that were deleted by later operations.
+   */
+  squash?: ImageLayerSquash
+
+  /**
+   * How to describe the layers of the image in its history.
+   */
+  history?: ImageHistory
+
+  /**
+   * A private key to sign the image with, in PEM format (e.g., generated by "cosign generate-key-pair").
+   *
+   * The signature is compatible with "cosign verify".
+   */
+  signingKey?: Secret
+
+  /**
+   * The password of the signing key, if it is encrypted.
+   */
+  signingKeyPassword?: Secret
+
+  /**
+   * Attach an SPDX SBOM of the image's filesystem as an attestation.
+   */
+  sbom?: boolean
+
+  /**
+   * Attach a SLSA provenance, recording the container's call ID, as an attestation.
+   */
+  provenance?: boolean
 }
 
 export type ContainerBuildOpts = {
@@ -173,6 +311,18 @@ export type ContainerExportOpts = {
    * Replace "${VAR}" or "$VAR" in the value of path according to the current environment variables defined in the container (e.g. "/$VAR/foo").
    */
   expand?: boolean
+
+  /**
+   * Squash the layers of the image into one, or the layers above the image the container was created from with "from" or "import".
+   *
+   * Squashing drops the files that were deleted by later operations.
+   */
+  squash?: ImageLayerSquash
+
+  /**
+   * How to describe the layers of the image in its history.
+   */
+  history?: ImageHistory
 }
 
 export type ContainerFileOpts = {
@@ -185,8 +335,53 @@ export type ContainerFileOpts = {
 export type ContainerImportOpts = {
   /**
    * Identifies the tag to import from the archive, if the archive bundles multiple tags.
+   *
+   * Either the tag only (e.g., "latest") or the image reference (e.g., "alpine:latest").
+   */
+  tag?: string
+
+  /**
+   * Identifies the digest of the image manifest or index to import from the archive.
+   */
+  digest?: string
+}
+
+export type ContainerImportDirectoryOpts = {
+  /**
+   * Identifies the tag to import from the layout, if the layout bundles multiple tags.
+   *
+   * Either the tag only (e.g., "latest") or the image reference (e.g., "alpine:latest").
+   */
+  tag?: string
+
+  /**
+   * Identifies the digest of the image manifest or index to import from the layout.
+   */
+  digest?: string
+}
+
+export type ContainerImportVariantsOpts = {
+  /**
+   * File to read the variants from. Exactly one of source or directory must be set.
+   */
+  source?: File
+
+  /**
+   * Directory in OCI image layout to read the variants from.
+   */
+  directory?: Directory
+
+  /**
+   * Identifies the tag to import from the archive, if the archive bundles multiple tags.
+   *
+   * Either the tag only (e.g., "latest") or the image reference (e.g., "alpine:latest").
    */
   tag?: string
+
+  /**
+   * Identifies the digest of the image index to import from the archive.
+   */
+  digest?: string
 }
 
 export type ContainerPublishOpts = {
@@ -210,6 +405,40 @@ export type ContainerPublishOpts = {
    * Defaults to OCI, which is largely compatible with most recent registries, but Docker may be needed for older registries without OCI support.
    */
   mediaTypes?: ImageMediaTypes
+
+  /**
+   * Squash the layers of the image into one, or the layers above the image the container was created from with "from" or "import".
+   *
+   * Squashing drops the files that were deleted by later operations.
+   */
+  squash?: ImageLayerSquash
+
+  /**
+   * How to describe the layers of the image in its history.
+   */
+  history?: ImageHistory
+
+  /**
+   * A private key to sign the image with, in PEM format (e.g., generated by "cosign generate-key-pair").
+   *
+   * The signature is compatible with "cosign verify".
+   */
+  signingKey?: Secret
+
+  /**
+   * The password of the signing key, if it is encrypted.
+   */
+  signingKeyPassword?: Secret
+
+  /**
+   * Attach an SPDX SBOM of the image's filesystem as an attestation.
+   */
+  sbom?: boolean
+
+  /**
+   * Attach a SLSA provenance, recording the container's call ID, as an attestation.
+   */
+  provenance?: boolean
 }
 
 export type ContainerTerminalOpts = {
@@ -279,6 +508,52 @@ export type ContainerUpOpts = {
    * This should only be used if the user requires that their exec process be the pid 1 process in the container. Otherwise it may result in unexpected behavior.
    */
   noInit?: boolean
+
+  /**
+   * Number of seconds the service may run for before it's killed.
+   */
+  timeout?: number
+
+  /**
+   * Number of CPUs the service may use (e.g., 0.5).
+   */
+  cpuLimit?: float
+
+  /**
+   * Memory in bytes the service may use before it's killed (e.g., 536870912 for 512 MiB).
+   */
+  memoryLimit?: number
+
+  /**
+   * Number of processes the service may have running at once.
+   */
+  pidsLimit?: number
+
+  /**
+   * Command to run in the service container to check that it's healthy (e.g., ["pg_isready"]).
+   *
+   * It runs once the exposed ports are reachable, and the service is healthy once it exits successfully.
+   */
+  healthcheckExec?: string[]
+
+  /**
+   * Number of seconds to wait between health check attempts.
+   *
+   * If unset, attempts back off exponentially.
+   */
+  healthcheckInterval?: number
+
+  /**
+   * Number of seconds each health check attempt may take.
+   */
+  healthcheckTimeout?: number
+
+  /**
+   * Number of times to retry a failing health check before the service fails to start.
+   *
+   * If unset, health checks are retried for up to 15 minutes.
+   */
+  healthcheckRetries?: number
 }
 
 export type ContainerWithDefaultTerminalCmdOpts = {
@@ -384,6 +659,37 @@ export type ContainerWithExecOpts = {
    * This should only be used if the user requires that their exec process be the pid 1 process in the container. Otherwise it may result in unexpected behavior.
    */
   noInit?: boolean
+
+  /**
+   * Network access granted to the command.
+   *
+   * With SERVICES_ONLY, connections to anything but bound services and the engine's DNS server fail immediately.
+   */
+  networkMode?: NetworkMode
+
+  /**
+   * Number of seconds the command may run for before it's killed.
+   *
+   * A command that times out fails with an ExecError whose reason is TIMEOUT.
+   */
+  timeout?: number
+
+  /**
+   * Number of CPUs the command may use (e.g., 0.5).
+   */
+  cpuLimit?: float
+
+  /**
+   * Memory in bytes the command may use (e.g., 536870912 for 512 MiB).
+   *
+   * A command that exceeds it is killed and fails with an ExecError whose reason is OOM_KILLED.
+   */
+  memoryLimit?: number
+
+  /**
+   * Number of processes the command may have running at once.
+   */
+  pidsLimit?: number
 }
 
 export type ContainerWithExposedPortOpts = {
@@ -401,6 +707,25 @@ export type ContainerWithExposedPortOpts = {
    * Skip the health check when run as a service.
    */
   experimentalSkipHealthcheck?: boolean
+
+  /**
+   * Check the port's health with an HTTP GET request for this path, rather than just connecting to it (e.g., "/healthz").
+   */
+  healthcheckPath?: string
+
+  /**
+   * Scheme of the health check request. If unset, HTTP is used.
+   *
+   * Requires healthcheckPath.
+   */
+  healthcheckScheme?: HealthcheckScheme
+
+  /**
+   * Status code the health check request must respond with.
+   *
+   * If unset, any 2xx or 3xx status is accepted. Requires healthcheckPath.
+   */
+  healthcheckStatus?: number
 }
 
 export type ContainerWithFileOpts = {
@@ -655,6 +980,18 @@ export type CurrentModuleWorkdirOpts = {
  */
 export type CurrentModuleID = string & { __CurrentModuleID: never }
 
+export type DirectoryAsComposeOpts = {
+  /**
+   * Path of the Compose file. If unset, the first of "compose.yaml", "compose.yml", "docker-compose.yaml" and "docker-compose.yml" that exists is used.
+   */
+  path?: string
+
+  /**
+   * Name of the project, which named volumes are scoped to. If unset, the name set in the Compose file is used, or "default".
+   */
+  projectName?: string
+}
+
 export type DirectoryAsModuleOpts = {
   /**
    * An optional subpath of the directory which contains the module's configuration file.
@@ -698,6 +1035,30 @@ export type DirectoryDockerBuildOpts = {
    * They will be mounted at /run/secrets/[secret-name].
    */
   secrets?: Secret[]
+
+  /**
+   * Named build contexts to pass to the build.
+   *
+   * They can be referenced by name in the Dockerfile (e.g., "COPY --from=<name>" or "FROM <name>").
+   */
+  buildContexts?: BuildContext[]
+
+  /**
+   * A socket to forward to the build as the default SSH agent.
+   *
+   * It is available to "RUN --mount=type=ssh" instructions.
+   */
+  ssh?: Socket
+
+  /**
+   * Build stages to build without using the cache.
+   */
+  noCacheFilter?: string[]
+
+  /**
+   * Registry references to import the build cache from (e.g., "registry.example.com/app:cache").
+   */
+  cacheFrom?: string[]
 }
 
 export type DirectoryEntriesOpts = {
@@ -832,6 +1193,13 @@ export type ErrorID = string & { __ErrorID: never }
  */
 export type FieldTypeDefID = string & { __FieldTypeDefID: never }
 
+export type FileAsComposeOpts = {
+  /**
+   * Name of the project, which named volumes are scoped to. If unset, the name set in the Compose file is used, or "default".
+   */
+  projectName?: string
+}
+
 export type FileDigestOpts = {
   /**
    * If true, exclude metadata from the digest.
@@ -901,6 +1269,16 @@ export type FunctionID = string & { __FunctionID: never }
  */
 export type GeneratedCodeID = string & { __GeneratedCodeID: never }
 
+/**
+ * The `GitActorID` scalar type represents an identifier for an object of type GitActor.
+ */
+export type GitActorID = string & { __GitActorID: never }
+
+/**
+ * The `GitCommitInfoID` scalar type represents an identifier for an object of type GitCommitInfo.
+ */
+export type GitCommitInfoID = string & { __GitCommitInfoID: never }
+
 /**
  * The `GitModuleSourceID` scalar type represents an identifier for an object of type GitModuleSource.
  */
@@ -911,48 +1289,126 @@ export type GitRefTreeOpts = {
    * Set to true to discard .git directory.
    */
   discardGitDir?: boolean
-}
 
-/**
- * The `GitRefID` scalar type represents an identifier for an object of type GitRef.
- */
-export type GitRefID = string & { __GitRefID: never }
+  /**
+   * Only check out the paths matching these patterns, which use the syntax of .gitignore files (e.g., ["/docs/", "*.md"]).
+   */
+  sparseCheckout?: string[]
 
-export type GitRepositoryTagsOpts = {
   /**
-   * Glob patterns (e.g., "refs/tags/v*").
+   * The number of commits of history to fetch into the .git directory.
    */
-  patterns?: string[]
-}
+  depth?: number
 
-/**
- * The `GitRepositoryID` scalar type represents an identifier for an object of type GitRepository.
- */
-export type GitRepositoryID = string & { __GitRepositoryID: never }
+  /**
+   * Set to true to fetch the full history and tags into the .git directory, e.g. for "git describe". Overrides depth.
+   */
+  fullHistory?: boolean
 
-export type HostDirectoryOpts = {
   /**
-   * Exclude artifacts that match the given pattern (e.g., ["node_modules/", ".git*"]).
+   * Set to true to not check out submodules. By default, submodules are checked out recursively.
    */
-  exclude?: string[]
+  skipSubmodules?: boolean
 
   /**
-   * Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).
+   * Set to true to replace Git LFS pointer files with the content they point to. LFS objects are cached by the engine, so they're only downloaded once.
    */
-  include?: string[]
+  lfs?: boolean
 }
 
-export type HostServiceOpts = {
+export type GitRefVerifyOpts = {
   /**
-   * Upstream host to forward traffic to.
+   * GPG public keys trusted to sign the ref.
    */
-  host?: string
+  keys?: File[]
 
   /**
-   * Ports to expose via the service, forwarding through the host network.
-   *
-   * If a port's frontend is unspecified or 0, it defaults to the same as the backend port.
-   *
+   * SSH allowed signers file trusted to sign the ref, in the format read by "ssh-keygen -Y verify".
+   */
+  allowedSigners?: File
+}
+
+/**
+ * The `GitRefID` scalar type represents an identifier for an object of type GitRef.
+ */
+export type GitRefID = string & { __GitRefID: never }
+
+export type GitRepositoryLogOpts = {
+  /**
+   * Exclude the commits reachable from this ref (e.g., "v0.3.8"). If empty, the whole history is returned.
+   */
+  from?: string
+
+  /**
+   * Ref to list the history of (e.g., "main"). If empty, HEAD is used.
+   */
+  to?: string
+
+  /**
+   * Only return commits that change one of these paths.
+   */
+  paths?: string[]
+}
+
+export type GitRepositoryTagsOpts = {
+  /**
+   * Glob patterns (e.g., "refs/tags/v*").
+   */
+  patterns?: string[]
+}
+
+/**
+ * The `GitRepositoryID` scalar type represents an identifier for an object of type GitRepository.
+ */
+export type GitRepositoryID = string & { __GitRepositoryID: never }
+
+/**
+ * The `GitSignatureID` scalar type represents an identifier for an object of type GitSignature.
+ */
+export type GitSignatureID = string & { __GitSignatureID: never }
+
+export type HTTPHeader = {
+  /**
+   * The header name.
+   */
+  name: string
+
+  /**
+   * The header value.
+   */
+  value: string
+}
+
+/**
+ * Scheme of the HTTP request used to check a port's health.
+ */
+export enum HealthcheckScheme {
+  Http = "HTTP",
+  Https = "HTTPS",
+}
+export type HostDirectoryOpts = {
+  /**
+   * Exclude artifacts that match the given pattern (e.g., ["node_modules/", ".git*"]).
+   */
+  exclude?: string[]
+
+  /**
+   * Include only artifacts that match the given pattern (e.g., ["app/", "package.*"]).
+   */
+  include?: string[]
+}
+
+export type HostServiceOpts = {
+  /**
+   * Upstream host to forward traffic to.
+   */
+  host?: string
+
+  /**
+   * Ports to expose via the service, forwarding through the host network.
+   *
+   * If a port's frontend is unspecified or 0, it defaults to the same as the backend port.
+   *
    * An empty set of ports is not valid; an error will be returned.
    */
   ports: PortForward[]
@@ -983,6 +1439,25 @@ export type HostTunnelOpts = {
  */
 export type HostID = string & { __HostID: never }
 
+/**
+ * The `ImageConfigID` scalar type represents an identifier for an object of type ImageConfig.
+ */
+export type ImageConfigID = string & { __ImageConfigID: never }
+
+/**
+ * How to describe the layers of an exported image in its history.
+ */
+export enum ImageHistory {
+  /**
+   * Describe each layer by the API field that created it, e.g. "withExec", with the digest of its call as comment.
+   */
+  Fields = "FIELDS",
+
+  /**
+   * Describe each layer by the operation that created it.
+   */
+  Operations = "OPERATIONS",
+}
 /**
  * Compression algorithm to use for image layers.
  */
@@ -992,6 +1467,25 @@ export enum ImageLayerCompression {
   Uncompressed = "Uncompressed",
   Zstd = "Zstd",
 }
+/**
+ * How to squash the layers of an exported image.
+ */
+export enum ImageLayerSquash {
+  /**
+   * Keep the layers of the image the container was created from, and squash the layers above them into one.
+   */
+  AboveBase = "ABOVE_BASE",
+
+  /**
+   * Squash all layers into one.
+   */
+  All = "ALL",
+
+  /**
+   * Keep one layer per operation.
+   */
+  None = "NONE",
+}
 /**
  * Mediatypes to use in published or exported image metadata.
  */
@@ -1089,6 +1583,25 @@ export enum ModuleSourceKind {
  */
 export type ModuleSourceViewID = string & { __ModuleSourceViewID: never }
 
+/**
+ * Network access granted to an executed command.
+ */
+export enum NetworkMode {
+  /**
+   * Full network access, including services and the internet.
+   */
+  Default = "DEFAULT",
+
+  /**
+   * No network access beyond the loopback interface.
+   */
+  None = "NONE",
+
+  /**
+   * Only bound services and the engine's DNS server are reachable.
+   */
+  ServicesOnly = "SERVICES_ONLY",
+}
 /**
  * Transport layer network protocol associated to a port.
  */
@@ -1153,6 +1666,15 @@ export type ClientContainerOpts = {
   platform?: Platform
 }
 
+export type ClientExplainCacheMissOpts = {
+  /**
+   * Digest of the call to compare to.
+   *
+   * Defaults to the most recent call of the same fields in an earlier run.
+   */
+  previousDigest?: string
+}
+
 export type ClientGitOpts = {
   /**
    * DEPRECATED: Set to true to keep .git directory.
@@ -1180,6 +1702,36 @@ export type ClientHttpOpts = {
    * A service which must be started before the URL is fetched.
    */
   experimentalServiceHost?: Service
+
+  /**
+   * Expected digest of the content (e.g., "sha256:..."). The fetch fails if the content doesn't match, and is cached by the digest without contacting the server.
+   */
+  checksum?: string
+
+  /**
+   * Headers to send with the request.
+   */
+  headers?: HTTPHeader[]
+
+  /**
+   * Secret sent as a bearer token in the Authorization header.
+   */
+  authToken?: Secret
+
+  /**
+   * Secret sent as the value of the Authorization header (e.g., "Basic <base64 of user:password>"). Takes precedence over authToken.
+   */
+  authHeader?: Secret
+
+  /**
+   * Name of the file. Defaults to a digest of the URL.
+   */
+  name?: string
+
+  /**
+   * Permissions of the file (e.g., 0755). Defaults to 0600.
+   */
+  permissions?: number
 }
 
 export type ClientLoadSecretFromNameOpts = {
@@ -1210,6 +1762,25 @@ export type ClientModuleSourceOpts = {
   relHostPath?: string
 }
 
+export type RegistryConfigOpts = {
+  /**
+   * Platform of the image. Defaults to the platform of the engine.
+   */
+  platform?: Platform
+}
+
+export type RegistryManifestDigestOpts = {
+  /**
+   * Platform of the manifest. Defaults to the platform of the engine.
+   */
+  platform?: Platform
+}
+
+/**
+ * The `RegistryID` scalar type represents an identifier for an object of type Registry.
+ */
+export type RegistryID = string & { __RegistryID: never }
+
 /**
  * Expected return type of an execution
  */
@@ -1256,6 +1827,30 @@ export type ServiceEndpointOpts = {
   scheme?: string
 }
 
+export type ServiceStderrOpts = {
+  /**
+   * Only return this many lines from the end of the output.
+   */
+  tail?: number
+
+  /**
+   * Only return output written since this point in time, either as a duration (e.g., "10m") or as an RFC 3339 timestamp.
+   */
+  since?: string
+}
+
+export type ServiceStdoutOpts = {
+  /**
+   * Only return this many lines from the end of the output.
+   */
+  tail?: number
+
+  /**
+   * Only return output written since this point in time, either as a duration (e.g., "10m") or as an RFC 3339 timestamp.
+   */
+  since?: string
+}
+
 export type ServiceStopOpts = {
   /**
    * Immediately kill the service without waiting for a graceful exit
@@ -1277,11 +1872,49 @@ export type ServiceUpOpts = {
   random?: boolean
 }
 
+export type ServiceWithLivenessProbeOpts = {
+  /**
+   * Number of seconds between each health check.
+   */
+  interval?: number
+
+  /**
+   * Number of consecutive failed health checks after which the service is killed.
+   */
+  failureThreshold?: number
+}
+
+export type ServiceWithRestartPolicyOpts = {
+  /**
+   * Maximum number of times to restart the service. If unset, there is no limit.
+   */
+  maxRetries?: number
+}
+
 /**
  * The `ServiceID` scalar type represents an identifier for an object of type Service.
  */
 export type ServiceID = string & { __ServiceID: never }
 
+/**
+ * When to restart a service that has exited.
+ */
+export enum ServiceRestartPolicy {
+  /**
+   * Restart the service whenever it exits, unless it was stopped.
+   */
+  Always = "ALWAYS",
+
+  /**
+   * Never restart the service.
+   */
+  Never = "NEVER",
+
+  /**
+   * Restart the service when it exits with an error or fails its liveness checks.
+   */
+  OnFailure = "ON_FAILURE",
+}
 /**
  * The `SocketID` scalar type represents an identifier for an object of type Socket.
  */
@@ -1437,104 +2070,388 @@ export type __TypeFieldsOpts = {
 }
 
 /**
- * A directory whose contents persist across runs.
+ * The first difference between a call and the same call in a previous run, which explains why it missed the cache.
  */
-export class CacheVolume extends BaseClient {
-  private readonly _id?: CacheVolumeID = undefined
+export class CacheMissExplanation extends BaseClient {
+  private readonly _id?: CacheMissExplanationID = undefined
+  private readonly _argument?: string = undefined
+  private readonly _call?: string = undefined
+  private readonly _currentValue?: string = undefined
+  private readonly _digest?: string = undefined
+  private readonly _kind?: CacheMissKind = undefined
+  private readonly _message?: string = undefined
+  private readonly _previousDigest?: string = undefined
+  private readonly _previousTrace?: string = undefined
+  private readonly _previousValue?: string = undefined
+  private readonly _trace?: string = undefined
 
   /**
    * Constructor is used for internal usage only, do not create object from it.
    */
-  constructor(ctx?: Context, _id?: CacheVolumeID) {
+  constructor(
+    ctx?: Context,
+    _id?: CacheMissExplanationID,
+    _argument?: string,
+    _call?: string,
+    _currentValue?: string,
+    _digest?: string,
+    _kind?: CacheMissKind,
+    _message?: string,
+    _previousDigest?: string,
+    _previousTrace?: string,
+    _previousValue?: string,
+    _trace?: string,
+  ) {
     super(ctx)
 
     this._id = _id
+    this._argument = _argument
+    this._call = _call
+    this._currentValue = _currentValue
+    this._digest = _digest
+    this._kind = _kind
+    this._message = _message
+    this._previousDigest = _previousDigest
+    this._previousTrace = _previousTrace
+    this._previousValue = _previousValue
+    this._trace = _trace
   }
 
   /**
-   * A unique identifier for this CacheVolume.
+   * A unique identifier for this CacheMissExplanation.
    */
-  id = async (): Promise<CacheVolumeID> => {
+  id = async (): Promise<CacheMissExplanationID> => {
     if (this._id) {
       return this._id
     }
 
     const ctx = this._ctx.select("id")
 
-    const response: Awaited<CacheVolumeID> = await ctx.execute()
+    const response: Awaited<CacheMissExplanationID> = await ctx.execute()
 
     return response
   }
-}
-
-/**
- * An OCI-compatible container, also known as a Docker container.
- */
-export class Container extends BaseClient {
-  private readonly _id?: ContainerID = undefined
-  private readonly _envVariable?: string = undefined
-  private readonly _exitCode?: number = undefined
-  private readonly _export?: string = undefined
-  private readonly _imageRef?: string = undefined
-  private readonly _label?: string = undefined
-  private readonly _platform?: Platform = undefined
-  private readonly _publish?: string = undefined
-  private readonly _stderr?: string = undefined
-  private readonly _stdout?: string = undefined
-  private readonly _sync?: ContainerID = undefined
-  private readonly _up?: Void = undefined
-  private readonly _user?: string = undefined
-  private readonly _workdir?: string = undefined
 
   /**
-   * Constructor is used for internal usage only, do not create object from it.
+   * The name of the diverging argument, if the kind is ARGUMENT.
    */
-  constructor(
-    ctx?: Context,
-    _id?: ContainerID,
-    _envVariable?: string,
-    _exitCode?: number,
-    _export?: string,
-    _imageRef?: string,
-    _label?: string,
-    _platform?: Platform,
-    _publish?: string,
-    _stderr?: string,
-    _stdout?: string,
-    _sync?: ContainerID,
-    _up?: Void,
-    _user?: string,
-    _workdir?: string,
-  ) {
-    super(ctx)
+  argument = async (): Promise<string> => {
+    if (this._argument) {
+      return this._argument
+    }
 
-    this._id = _id
-    this._envVariable = _envVariable
-    this._exitCode = _exitCode
-    this._export = _export
-    this._imageRef = _imageRef
-    this._label = _label
-    this._platform = _platform
-    this._publish = _publish
-    this._stderr = _stderr
-    this._stdout = _stdout
-    this._sync = _sync
-    this._up = _up
-    this._user = _user
-    this._workdir = _workdir
+    const ctx = this._ctx.select("argument")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
   }
 
   /**
-   * A unique identifier for this Container.
+   * The first diverging call, e.g. host.directory(path: "./src"), empty if the calls are the same.
    */
-  id = async (): Promise<ContainerID> => {
-    if (this._id) {
-      return this._id
+  call = async (): Promise<string> => {
+    if (this._call) {
+      return this._call
     }
 
-    const ctx = this._ctx.select("id")
+    const ctx = this._ctx.select("call")
 
-    const response: Awaited<ContainerID> = await ctx.execute()
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The current value, e.g. of the argument or content digest, empty if missing.
+   */
+  currentValue = async (): Promise<string> => {
+    if (this._currentValue) {
+      return this._currentValue
+    }
+
+    const ctx = this._ctx.select("currentValue")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The digest of the call that missed the cache.
+   */
+  digest = async (): Promise<string> => {
+    if (this._digest) {
+      return this._digest
+    }
+
+    const ctx = this._ctx.select("digest")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * What differs between the calls.
+   */
+  kind = async (): Promise<CacheMissKind> => {
+    if (this._kind) {
+      return this._kind
+    }
+
+    const ctx = this._ctx.select("kind")
+
+    const response: Awaited<CacheMissKind> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * A description of the first difference between the calls.
+   */
+  message = async (): Promise<string> => {
+    if (this._message) {
+      return this._message
+    }
+
+    const ctx = this._ctx.select("message")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The digest of the call it was compared to.
+   */
+  previousDigest = async (): Promise<string> => {
+    if (this._previousDigest) {
+      return this._previousDigest
+    }
+
+    const ctx = this._ctx.select("previousDigest")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The trace of the previous run the call it was compared to is from.
+   */
+  previousTrace = async (): Promise<string> => {
+    if (this._previousTrace) {
+      return this._previousTrace
+    }
+
+    const ctx = this._ctx.select("previousTrace")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The previous value, e.g. of the argument or content digest, empty if missing.
+   */
+  previousValue = async (): Promise<string> => {
+    if (this._previousValue) {
+      return this._previousValue
+    }
+
+    const ctx = this._ctx.select("previousValue")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The trace of the run the call is from.
+   */
+  trace = async (): Promise<string> => {
+    if (this._trace) {
+      return this._trace
+    }
+
+    const ctx = this._ctx.select("trace")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+}
+
+/**
+ * A directory whose contents persist across runs.
+ */
+export class CacheVolume extends BaseClient {
+  private readonly _id?: CacheVolumeID = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(ctx?: Context, _id?: CacheVolumeID) {
+    super(ctx)
+
+    this._id = _id
+  }
+
+  /**
+   * A unique identifier for this CacheVolume.
+   */
+  id = async (): Promise<CacheVolumeID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<CacheVolumeID> = await ctx.execute()
+
+    return response
+  }
+}
+
+/**
+ * A set of services described by a Compose file.
+ */
+export class ComposeProject extends BaseClient {
+  private readonly _id?: ComposeProjectID = undefined
+  private readonly _name?: string = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(ctx?: Context, _id?: ComposeProjectID, _name?: string) {
+    super(ctx)
+
+    this._id = _id
+    this._name = _name
+  }
+
+  /**
+   * A unique identifier for this ComposeProject.
+   */
+  id = async (): Promise<ComposeProjectID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<ComposeProjectID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The name of the project.
+   */
+  name = async (): Promise<string> => {
+    if (this._name) {
+      return this._name
+    }
+
+    const ctx = this._ctx.select("name")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * Retrieves a service of the project.
+   *
+   * The services it depends on are bound to it, reachable at their names in the project. Its named volumes are cache volumes, shared with the project's other services.
+   * @param name The name of the service.
+   */
+  service = (name: string): Service => {
+    const ctx = this._ctx.select("service", { name })
+    return new Service(ctx)
+  }
+
+  /**
+   * The names of the services in the project.
+   */
+  serviceNames = async (): Promise<string[]> => {
+    const ctx = this._ctx.select("serviceNames")
+
+    const response: Awaited<string[]> = await ctx.execute()
+
+    return response
+  }
+}
+
+/**
+ * An OCI-compatible container, also known as a Docker container.
+ */
+export class Container extends BaseClient {
+  private readonly _id?: ContainerID = undefined
+  private readonly _envVariable?: string = undefined
+  private readonly _exitCode?: number = undefined
+  private readonly _export?: string = undefined
+  private readonly _imageRef?: string = undefined
+  private readonly _label?: string = undefined
+  private readonly _platform?: Platform = undefined
+  private readonly _publish?: string = undefined
+  private readonly _stderr?: string = undefined
+  private readonly _stdout?: string = undefined
+  private readonly _sync?: ContainerID = undefined
+  private readonly _up?: Void = undefined
+  private readonly _user?: string = undefined
+  private readonly _workdir?: string = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: ContainerID,
+    _envVariable?: string,
+    _exitCode?: number,
+    _export?: string,
+    _imageRef?: string,
+    _label?: string,
+    _platform?: Platform,
+    _publish?: string,
+    _stderr?: string,
+    _stdout?: string,
+    _sync?: ContainerID,
+    _up?: Void,
+    _user?: string,
+    _workdir?: string,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._envVariable = _envVariable
+    this._exitCode = _exitCode
+    this._export = _export
+    this._imageRef = _imageRef
+    this._label = _label
+    this._platform = _platform
+    this._publish = _publish
+    this._stderr = _stderr
+    this._stdout = _stdout
+    this._sync = _sync
+    this._up = _up
+    this._user = _user
+    this._workdir = _workdir
+  }
+
+  /**
+   * A unique identifier for this Container.
+   */
+  id = async (): Promise<ContainerID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<ContainerID> = await ctx.execute()
 
     return response
   }
@@ -1555,6 +2472,20 @@ export class Container extends BaseClient {
    * @param opts.noInit If set, skip the automatic init process injected into containers by default.
    *
    * This should only be used if the user requires that their exec process be the pid 1 process in the container. Otherwise it may result in unexpected behavior.
+   * @param opts.timeout Number of seconds the service may run for before it's killed.
+   * @param opts.cpuLimit Number of CPUs the service may use (e.g., 0.5).
+   * @param opts.memoryLimit Memory in bytes the service may use before it's killed (e.g., 536870912 for 512 MiB).
+   * @param opts.pidsLimit Number of processes the service may have running at once.
+   * @param opts.healthcheckExec Command to run in the service container to check that it's healthy (e.g., ["pg_isready"]).
+   *
+   * It runs once the exposed ports are reachable, and the service is healthy once it exits successfully.
+   * @param opts.healthcheckInterval Number of seconds to wait between health check attempts.
+   *
+   * If unset, attempts back off exponentially.
+   * @param opts.healthcheckTimeout Number of seconds each health check attempt may take.
+   * @param opts.healthcheckRetries Number of times to retry a failing health check before the service fails to start.
+   *
+   * If unset, health checks are retried for up to 15 minutes.
    */
   asService = (opts?: ContainerAsServiceOpts): Service => {
     const ctx = this._ctx.select("asService", { ...opts })
@@ -1572,11 +2503,23 @@ export class Container extends BaseClient {
    * @param opts.mediaTypes Use the specified media types for the image's layers.
    *
    * Defaults to OCI, which is largely compatible with most recent container runtimes, but Docker may be needed for older runtimes without OCI support.
+   * @param opts.squash Squash the layers of the image into one, or the layers above the image the container was created from with "from" or "import".
+   *
+   * Squashing drops the files that were deleted by later operations.
+   * @param opts.history How to describe the layers of the image in its history.
+   * @param opts.signingKey A private key to sign the image with, in PEM format (e.g., generated by "cosign generate-key-pair").
+   *
+   * The signature is compatible with "cosign verify".
+   * @param opts.signingKeyPassword The password of the signing key, if it is encrypted.
+   * @param opts.sbom Attach an SPDX SBOM of the image's filesystem as an attestation.
+   * @param opts.provenance Attach a SLSA provenance, recording the container's call ID, as an attestation.
    */
   asTarball = (opts?: ContainerAsTarballOpts): File => {
     const metadata = {
       forcedCompression: { is_enum: true },
       mediaTypes: { is_enum: true },
+      squash: { is_enum: true },
+      history: { is_enum: true },
     }
 
     const ctx = this._ctx.select("asTarball", { ...opts, __metadata: metadata })
@@ -1726,6 +2669,10 @@ export class Container extends BaseClient {
    *
    * Defaults to OCI, which is largely compatible with most recent container runtimes, but Docker may be needed for older runtimes without OCI support.
    * @param opts.expand Replace "${VAR}" or "$VAR" in the value of path according to the current environment variables defined in the container (e.g. "/$VAR/foo").
+   * @param opts.squash Squash the layers of the image into one, or the layers above the image the container was created from with "from" or "import".
+   *
+   * Squashing drops the files that were deleted by later operations.
+   * @param opts.history How to describe the layers of the image in its history.
    */
   export = async (
     path: string,
@@ -1738,6 +2685,8 @@ export class Container extends BaseClient {
     const metadata = {
       forcedCompression: { is_enum: true },
       mediaTypes: { is_enum: true },
+      squash: { is_enum: true },
+      history: { is_enum: true },
     }
 
     const ctx = this._ctx.select("export", {
@@ -1808,14 +2757,62 @@ export class Container extends BaseClient {
 
   /**
    * Reads the container from an OCI tarball.
+   *
+   * Docker archives, like the ones produced by "docker save", are also supported. The image is picked for the container's platform, unless a single image manifest is selected.
    * @param source File to read the container from.
    * @param opts.tag Identifies the tag to import from the archive, if the archive bundles multiple tags.
+   *
+   * Either the tag only (e.g., "latest") or the image reference (e.g., "alpine:latest").
+   * @param opts.digest Identifies the digest of the image manifest or index to import from the archive.
    */
   import_ = (source: File, opts?: ContainerImportOpts): Container => {
     const ctx = this._ctx.select("import", { source, ...opts })
     return new Container(ctx)
   }
 
+  /**
+   * Reads the container from a directory in OCI image layout.
+   *
+   * The image is picked for the container's platform, unless a single image manifest is selected.
+   * @param source Directory in OCI image layout to read the container from.
+   * @param opts.tag Identifies the tag to import from the layout, if the layout bundles multiple tags.
+   *
+   * Either the tag only (e.g., "latest") or the image reference (e.g., "alpine:latest").
+   * @param opts.digest Identifies the digest of the image manifest or index to import from the layout.
+   */
+  importDirectory = (
+    source: Directory,
+    opts?: ContainerImportDirectoryOpts,
+  ): Container => {
+    const ctx = this._ctx.select("importDirectory", { source, ...opts })
+    return new Container(ctx)
+  }
+
+  /**
+   * Reads every platform variant of an image from an OCI tarball or a directory in OCI image layout.
+   *
+   * The variants can be exported back as a multi-platform image with platformVariants.
+   * @param opts.source File to read the variants from. Exactly one of source or directory must be set.
+   * @param opts.directory Directory in OCI image layout to read the variants from.
+   * @param opts.tag Identifies the tag to import from the archive, if the archive bundles multiple tags.
+   *
+   * Either the tag only (e.g., "latest") or the image reference (e.g., "alpine:latest").
+   * @param opts.digest Identifies the digest of the image index to import from the archive.
+   */
+  importVariants = async (
+    opts?: ContainerImportVariantsOpts,
+  ): Promise<Container[]> => {
+    type importVariants = {
+      id: ContainerID
+    }
+
+    const ctx = this._ctx.select("importVariants", { ...opts }).select("id")
+
+    const response: Awaited<importVariants[]> = await ctx.execute()
+
+    return response.map((r) => new Client(ctx.copy()).loadContainerFromID(r.id))
+  }
+
   /**
    * Retrieves the value of the specified label.
    * @param name The name of the label (e.g., "org.opencontainers.artifact.created").
@@ -1891,6 +2888,16 @@ export class Container extends BaseClient {
    * @param opts.mediaTypes Use the specified media types for the published image's layers.
    *
    * Defaults to OCI, which is largely compatible with most recent registries, but Docker may be needed for older registries without OCI support.
+   * @param opts.squash Squash the layers of the image into one, or the layers above the image the container was created from with "from" or "import".
+   *
+   * Squashing drops the files that were deleted by later operations.
+   * @param opts.history How to describe the layers of the image in its history.
+   * @param opts.signingKey A private key to sign the image with, in PEM format (e.g., generated by "cosign generate-key-pair").
+   *
+   * The signature is compatible with "cosign verify".
+   * @param opts.signingKeyPassword The password of the signing key, if it is encrypted.
+   * @param opts.sbom Attach an SPDX SBOM of the image's filesystem as an attestation.
+   * @param opts.provenance Attach a SLSA provenance, recording the container's call ID, as an attestation.
    */
   publish = async (
     address: string,
@@ -1903,6 +2910,8 @@ export class Container extends BaseClient {
     const metadata = {
       forcedCompression: { is_enum: true },
       mediaTypes: { is_enum: true },
+      squash: { is_enum: true },
+      history: { is_enum: true },
     }
 
     const ctx = this._ctx.select("publish", {
@@ -2004,6 +3013,20 @@ export class Container extends BaseClient {
    * @param opts.noInit If set, skip the automatic init process injected into containers by default.
    *
    * This should only be used if the user requires that their exec process be the pid 1 process in the container. Otherwise it may result in unexpected behavior.
+   * @param opts.timeout Number of seconds the service may run for before it's killed.
+   * @param opts.cpuLimit Number of CPUs the service may use (e.g., 0.5).
+   * @param opts.memoryLimit Memory in bytes the service may use before it's killed (e.g., 536870912 for 512 MiB).
+   * @param opts.pidsLimit Number of processes the service may have running at once.
+   * @param opts.healthcheckExec Command to run in the service container to check that it's healthy (e.g., ["pg_isready"]).
+   *
+   * It runs once the exposed ports are reachable, and the service is healthy once it exits successfully.
+   * @param opts.healthcheckInterval Number of seconds to wait between health check attempts.
+   *
+   * If unset, attempts back off exponentially.
+   * @param opts.healthcheckTimeout Number of seconds each health check attempt may take.
+   * @param opts.healthcheckRetries Number of times to retry a failing health check before the service fails to start.
+   *
+   * If unset, health checks are retried for up to 15 minutes.
    */
   up = async (opts?: ContainerUpOpts): Promise<void> => {
     if (this._up) {
@@ -2133,10 +3156,22 @@ export class Container extends BaseClient {
    * @param opts.noInit If set, skip the automatic init process injected into containers by default.
    *
    * This should only be used if the user requires that their exec process be the pid 1 process in the container. Otherwise it may result in unexpected behavior.
+   * @param opts.networkMode Network access granted to the command.
+   *
+   * With SERVICES_ONLY, connections to anything but bound services and the engine's DNS server fail immediately.
+   * @param opts.timeout Number of seconds the command may run for before it's killed.
+   *
+   * A command that times out fails with an ExecError whose reason is TIMEOUT.
+   * @param opts.cpuLimit Number of CPUs the command may use (e.g., 0.5).
+   * @param opts.memoryLimit Memory in bytes the command may use (e.g., 536870912 for 512 MiB).
+   *
+   * A command that exceeds it is killed and fails with an ExecError whose reason is OOM_KILLED.
+   * @param opts.pidsLimit Number of processes the command may have running at once.
    */
   withExec = (args: string[], opts?: ContainerWithExecOpts): Container => {
     const metadata = {
       expect: { is_enum: true },
+      networkMode: { is_enum: true },
     }
 
     const ctx = this._ctx.select("withExec", {
@@ -2159,6 +3194,13 @@ export class Container extends BaseClient {
    * @param opts.protocol Transport layer network protocol
    * @param opts.description Optional port description
    * @param opts.experimentalSkipHealthcheck Skip the health check when run as a service.
+   * @param opts.healthcheckPath Check the port's health with an HTTP GET request for this path, rather than just connecting to it (e.g., "/healthz").
+   * @param opts.healthcheckScheme Scheme of the health check request. If unset, HTTP is used.
+   *
+   * Requires healthcheckPath.
+   * @param opts.healthcheckStatus Status code the health check request must respond with.
+   *
+   * If unset, any 2xx or 3xx status is accepted. Requires healthcheckPath.
    */
   withExposedPort = (
     port: number,
@@ -2166,6 +3208,7 @@ export class Container extends BaseClient {
   ): Container => {
     const metadata = {
       protocol: { is_enum: true },
+      healthcheckScheme: { is_enum: true },
     }
 
     const ctx = this._ctx.select("withExposedPort", {
@@ -2768,6 +3811,18 @@ export class Directory extends BaseClient {
     return response
   }
 
+  /**
+   * Load a Compose project from a Compose file in this directory.
+   *
+   * Paths in the Compose file, such as build contexts and bind mounts, are resolved relative to the Compose file's directory. Variables are interpolated from a .env file next to the Compose file, if any.
+   * @param opts.path Path of the Compose file. If unset, the first of "compose.yaml", "compose.yml", "docker-compose.yaml" and "docker-compose.yml" that exists is used.
+   * @param opts.projectName Name of the project, which named volumes are scoped to. If unset, the name set in the Compose file is used, or "default".
+   */
+  asCompose = (opts?: DirectoryAsComposeOpts): ComposeProject => {
+    const ctx = this._ctx.select("asCompose", { ...opts })
+    return new ComposeProject(ctx)
+  }
+
   /**
    * Load the directory as a Dagger module
    * @param opts.sourceRootPath An optional subpath of the directory which contains the module's configuration file.
@@ -2782,6 +3837,15 @@ export class Directory extends BaseClient {
     return new Module_(ctx)
   }
 
+  /**
+   * Returns a unified diff, in the format of "git diff", of the files changed between this directory and another directory.
+   * @param other Identifier of the directory to compare.
+   */
+  asPatch = (other: Directory): File => {
+    const ctx = this._ctx.select("asPatch", { other })
+    return new File(ctx)
+  }
+
   /**
    * Gets the difference between this directory and an another directory.
    * @param other Identifier of the directory to compare.
@@ -2824,6 +3888,14 @@ export class Directory extends BaseClient {
    * @param opts.secrets Secrets to pass to the build.
    *
    * They will be mounted at /run/secrets/[secret-name].
+   * @param opts.buildContexts Named build contexts to pass to the build.
+   *
+   * They can be referenced by name in the Dockerfile (e.g., "COPY --from=<name>" or "FROM <name>").
+   * @param opts.ssh A socket to forward to the build as the default SSH agent.
+   *
+   * It is available to "RUN --mount=type=ssh" instructions.
+   * @param opts.noCacheFilter Build stages to build without using the cache.
+   * @param opts.cacheFrom Registry references to import the build cache from (e.g., "registry.example.com/app:cache").
    */
   dockerBuild = (opts?: DirectoryDockerBuildOpts): Container => {
     const ctx = this._ctx.select("dockerBuild", { ...opts })
@@ -2883,6 +3955,17 @@ export class Directory extends BaseClient {
     return response
   }
 
+  /**
+   * Retrieves this directory with the contents of the given directories merged into it.
+   *
+   * Fails if the same path holds different contents in more than one of the directories, instead of overwriting it.
+   * @param directories Identifiers of the directories to merge, in order.
+   */
+  merge = (directories: Directory[]): Directory => {
+    const ctx = this._ctx.select("merge", { directories })
+    return new Directory(ctx)
+  }
+
   /**
    * Force evaluation in the engine.
    */
@@ -2982,6 +4065,17 @@ export class Directory extends BaseClient {
     return new Directory(ctx)
   }
 
+  /**
+   * Retrieves this directory with the given unified diff applied.
+   *
+   * Fails if any hunk of the patch does not apply cleanly.
+   * @param patch File containing the patch to apply, as produced by "diff -u" or "git diff".
+   */
+  withPatch = (patch: File): Directory => {
+    const ctx = this._ctx.select("withPatch", { patch })
+    return new Directory(ctx)
+  }
+
   /**
    * Retrieves this directory with all file/dir timestamps set to the given time.
    * @param timestamp Timestamp to set dir/files in.
@@ -3852,6 +4946,17 @@ export class File extends BaseClient {
     return response
   }
 
+  /**
+   * Load a Compose project from this Compose file.
+   *
+   * Services that are built or that bind mount paths can only be loaded from a directory with Directory.asCompose.
+   * @param opts.projectName Name of the project, which named volumes are scoped to. If unset, the name set in the Compose file is used, or "default".
+   */
+  asCompose = (opts?: FileAsComposeOpts): ComposeProject => {
+    const ctx = this._ctx.select("asCompose", { ...opts })
+    return new ComposeProject(ctx)
+  }
+
   /**
    * Retrieves the contents of the file.
    */
@@ -4542,12 +5647,215 @@ export class GeneratedCode extends BaseClient {
   }
 
   /**
-   * Call the provided function with current GeneratedCode.
-   *
-   * This is useful for reusability and readability by not breaking the calling chain.
+   * Call the provided function with current GeneratedCode.
+   *
+   * This is useful for reusability and readability by not breaking the calling chain.
+   */
+  with = (arg: (param: GeneratedCode) => GeneratedCode) => {
+    return arg(this)
+  }
+}
+
+/**
+ * The author or committer of a git commit.
+ */
+export class GitActor extends BaseClient {
+  private readonly _id?: GitActorID = undefined
+  private readonly _date?: string = undefined
+  private readonly _email?: string = undefined
+  private readonly _name?: string = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: GitActorID,
+    _date?: string,
+    _email?: string,
+    _name?: string,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._date = _date
+    this._email = _email
+    this._name = _name
+  }
+
+  /**
+   * A unique identifier for this GitActor.
+   */
+  id = async (): Promise<GitActorID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<GitActorID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The date of the action, in RFC 3339 format.
+   */
+  date = async (): Promise<string> => {
+    if (this._date) {
+      return this._date
+    }
+
+    const ctx = this._ctx.select("date")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The email address of the person.
+   */
+  email = async (): Promise<string> => {
+    if (this._email) {
+      return this._email
+    }
+
+    const ctx = this._ctx.select("email")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The name of the person.
+   */
+  name = async (): Promise<string> => {
+    if (this._name) {
+      return this._name
+    }
+
+    const ctx = this._ctx.select("name")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+}
+
+/**
+ * The metadata of a git commit.
+ */
+export class GitCommitInfo extends BaseClient {
+  private readonly _id?: GitCommitInfoID = undefined
+  private readonly _date?: string = undefined
+  private readonly _message?: string = undefined
+  private readonly _sha?: string = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: GitCommitInfoID,
+    _date?: string,
+    _message?: string,
+    _sha?: string,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._date = _date
+    this._message = _message
+    this._sha = _sha
+  }
+
+  /**
+   * A unique identifier for this GitCommitInfo.
+   */
+  id = async (): Promise<GitCommitInfoID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<GitCommitInfoID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The author of the commit.
+   */
+  author = (): GitActor => {
+    const ctx = this._ctx.select("author")
+    return new GitActor(ctx)
+  }
+
+  /**
+   * The committer of the commit.
+   */
+  committer = (): GitActor => {
+    const ctx = this._ctx.select("committer")
+    return new GitActor(ctx)
+  }
+
+  /**
+   * The date the commit was authored, in RFC 3339 format.
+   */
+  date = async (): Promise<string> => {
+    if (this._date) {
+      return this._date
+    }
+
+    const ctx = this._ctx.select("date")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The full message of the commit.
+   */
+  message = async (): Promise<string> => {
+    if (this._message) {
+      return this._message
+    }
+
+    const ctx = this._ctx.select("message")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The SHAs of the parents of the commit.
+   */
+  parents = async (): Promise<string[]> => {
+    const ctx = this._ctx.select("parents")
+
+    const response: Awaited<string[]> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The SHA of the commit.
    */
-  with = (arg: (param: GeneratedCode) => GeneratedCode) => {
-    return arg(this)
+  sha = async (): Promise<string> => {
+    if (this._sha) {
+      return this._sha
+    }
+
+    const ctx = this._ctx.select("sha")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
   }
 }
 
@@ -4725,15 +6033,22 @@ export class GitModuleSource extends BaseClient {
 export class GitRef extends BaseClient {
   private readonly _id?: GitRefID = undefined
   private readonly _commit?: string = undefined
+  private readonly _describe?: string = undefined
 
   /**
    * Constructor is used for internal usage only, do not create object from it.
    */
-  constructor(ctx?: Context, _id?: GitRefID, _commit?: string) {
+  constructor(
+    ctx?: Context,
+    _id?: GitRefID,
+    _commit?: string,
+    _describe?: string,
+  ) {
     super(ctx)
 
     this._id = _id
     this._commit = _commit
+    this._describe = _describe
   }
 
   /**
@@ -4766,14 +6081,73 @@ export class GitRef extends BaseClient {
     return response
   }
 
+  /**
+   * The metadata of the resolved commit at this ref.
+   */
+  commitInfo = (): GitCommitInfo => {
+    const ctx = this._ctx.select("commitInfo")
+    return new GitCommitInfo(ctx)
+  }
+
+  /**
+   * The nearest tag reachable from this ref, as returned by "git describe --tags".
+   *
+   * If the tag doesn't point at the ref itself, it's suffixed with the number of commits since the tag and the abbreviated commit id (e.g., "v0.3.9-4-gb6315d8").
+   */
+  describe = async (): Promise<string> => {
+    if (this._describe) {
+      return this._describe
+    }
+
+    const ctx = this._ctx.select("describe")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The signature of this ref, if it was verified by "verify".
+   */
+  signature = (): GitSignature => {
+    const ctx = this._ctx.select("signature")
+    return new GitSignature(ctx)
+  }
+
   /**
    * The filesystem tree at this ref.
    * @param opts.discardGitDir Set to true to discard .git directory.
+   * @param opts.sparseCheckout Only check out the paths matching these patterns, which use the syntax of .gitignore files (e.g., ["/docs/", "*.md"]).
+   * @param opts.depth The number of commits of history to fetch into the .git directory.
+   * @param opts.fullHistory Set to true to fetch the full history and tags into the .git directory, e.g. for "git describe". Overrides depth.
+   * @param opts.skipSubmodules Set to true to not check out submodules. By default, submodules are checked out recursively.
+   * @param opts.lfs Set to true to replace Git LFS pointer files with the content they point to. LFS objects are cached by the engine, so they're only downloaded once.
    */
   tree = (opts?: GitRefTreeOpts): Directory => {
     const ctx = this._ctx.select("tree", { ...opts })
     return new Directory(ctx)
   }
+
+  /**
+   * Verify the GPG or SSH signature of the annotated tag or commit at this ref, failing if it's missing or not made by one of the trusted keys.
+   *
+   * Returns this ref pinned to the verified commit.
+   * @param opts.keys GPG public keys trusted to sign the ref.
+   * @param opts.allowedSigners SSH allowed signers file trusted to sign the ref, in the format read by "ssh-keygen -Y verify".
+   */
+  verify = (opts?: GitRefVerifyOpts): GitRef => {
+    const ctx = this._ctx.select("verify", { ...opts })
+    return new GitRef(ctx)
+  }
+
+  /**
+   * Call the provided function with current GitRef.
+   *
+   * This is useful for reusability and readability by not breaking the calling chain.
+   */
+  with = (arg: (param: GitRef) => GitRef) => {
+    return arg(this)
+  }
 }
 
 /**
@@ -4832,6 +6206,26 @@ export class GitRepository extends BaseClient {
     return new GitRef(ctx)
   }
 
+  /**
+   * Returns the commits reachable from a ref, newest first.
+   * @param opts.from Exclude the commits reachable from this ref (e.g., "v0.3.8"). If empty, the whole history is returned.
+   * @param opts.to Ref to list the history of (e.g., "main"). If empty, HEAD is used.
+   * @param opts.paths Only return commits that change one of these paths.
+   */
+  log = async (opts?: GitRepositoryLogOpts): Promise<GitCommitInfo[]> => {
+    type log = {
+      id: GitCommitInfoID
+    }
+
+    const ctx = this._ctx.select("log", { ...opts }).select("id")
+
+    const response: Awaited<log[]> = await ctx.execute()
+
+    return response.map((r) =>
+      new Client(ctx.copy()).loadGitCommitInfoFromID(r.id),
+    )
+  }
+
   /**
    * Returns details of a ref.
    * @param name Ref's name (can be a commit identifier, a tag name, a branch name, or a fully-qualified ref).
@@ -4890,6 +6284,94 @@ export class GitRepository extends BaseClient {
   }
 }
 
+/**
+ * A verified signature of a git tag or commit.
+ */
+export class GitSignature extends BaseClient {
+  private readonly _id?: GitSignatureID = undefined
+  private readonly _format?: string = undefined
+  private readonly _key?: string = undefined
+  private readonly _signer?: string = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: GitSignatureID,
+    _format?: string,
+    _key?: string,
+    _signer?: string,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._format = _format
+    this._key = _key
+    this._signer = _signer
+  }
+
+  /**
+   * A unique identifier for this GitSignature.
+   */
+  id = async (): Promise<GitSignatureID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<GitSignatureID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The format of the signature, "gpg" or "ssh".
+   */
+  format = async (): Promise<string> => {
+    if (this._format) {
+      return this._format
+    }
+
+    const ctx = this._ctx.select("format")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The fingerprint of the signing key.
+   */
+  key = async (): Promise<string> => {
+    if (this._key) {
+      return this._key
+    }
+
+    const ctx = this._ctx.select("key")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The identity of the signer: the user ID of the GPG key, or the principal of the SSH key in the allowed signers.
+   */
+  signer = async (): Promise<string> => {
+    if (this._signer) {
+      return this._signer
+    }
+
+    const ctx = this._ctx.select("signer")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+}
+
 /**
  * Information about the host environment.
  */
@@ -4954,44 +6436,186 @@ export class Host extends BaseClient {
     return new Service(ctx)
   }
 
-  /**
-   * Sets a secret given a user-defined name and the file path on the host, and returns the secret.
-   *
-   * The file is limited to a size of 512000 bytes.
-   * @param name The user defined name for this secret.
-   * @param path Location of the file to set as a secret.
-   */
-  setSecretFile = (name: string, path: string): Secret => {
-    const ctx = this._ctx.select("setSecretFile", { name, path })
-    return new Secret(ctx)
+  /**
+   * Sets a secret given a user-defined name and the file path on the host, and returns the secret.
+   *
+   * The file is limited to a size of 512000 bytes.
+   * @param name The user defined name for this secret.
+   * @param path Location of the file to set as a secret.
+   */
+  setSecretFile = (name: string, path: string): Secret => {
+    const ctx = this._ctx.select("setSecretFile", { name, path })
+    return new Secret(ctx)
+  }
+
+  /**
+   * Creates a tunnel that forwards traffic from the host to a service.
+   * @param service Service to send traffic from the tunnel.
+   * @param opts.ports Configure explicit port forwarding rules for the tunnel.
+   *
+   * If a port's frontend is unspecified or 0, a random port will be chosen by the host.
+   *
+   * If no ports are given, all of the service's ports are forwarded. If native is true, each port maps to the same port on the host. If native is false, each port maps to a random port chosen by the host.
+   *
+   * If ports are given and native is true, the ports are additive.
+   * @param opts.native Map each service port to the same port on the host, as if the service were running natively.
+   *
+   * Note: enabling may result in port conflicts.
+   */
+  tunnel = (service: Service, opts?: HostTunnelOpts): Service => {
+    const ctx = this._ctx.select("tunnel", { service, ...opts })
+    return new Service(ctx)
+  }
+
+  /**
+   * Accesses a Unix socket on the host.
+   * @param path Location of the Unix socket (e.g., "/var/run/docker.sock").
+   */
+  unixSocket = (path: string): Socket => {
+    const ctx = this._ctx.select("unixSocket", { path })
+    return new Socket(ctx)
+  }
+}
+
+/**
+ * The config of an image in a registry.
+ */
+export class ImageConfig extends BaseClient {
+  private readonly _id?: ImageConfigID = undefined
+  private readonly _platform?: Platform = undefined
+  private readonly _user?: string = undefined
+  private readonly _workdir?: string = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: ImageConfigID,
+    _platform?: Platform,
+    _user?: string,
+    _workdir?: string,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._platform = _platform
+    this._user = _user
+    this._workdir = _workdir
+  }
+
+  /**
+   * A unique identifier for this ImageConfig.
+   */
+  id = async (): Promise<ImageConfigID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<ImageConfigID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The default arguments of the image's entrypoint.
+   */
+  defaultArgs = async (): Promise<string[]> => {
+    const ctx = this._ctx.select("defaultArgs")
+
+    const response: Awaited<string[]> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The entrypoint of the image.
+   */
+  entrypoint = async (): Promise<string[]> => {
+    const ctx = this._ctx.select("entrypoint")
+
+    const response: Awaited<string[]> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The environment variables of the image.
+   */
+  envVariables = async (): Promise<EnvVariable[]> => {
+    type envVariables = {
+      id: EnvVariableID
+    }
+
+    const ctx = this._ctx.select("envVariables").select("id")
+
+    const response: Awaited<envVariables[]> = await ctx.execute()
+
+    return response.map((r) =>
+      new Client(ctx.copy()).loadEnvVariableFromID(r.id),
+    )
+  }
+
+  /**
+   * The labels of the image, sorted by name.
+   */
+  labels = async (): Promise<Label[]> => {
+    type labels = {
+      id: LabelID
+    }
+
+    const ctx = this._ctx.select("labels").select("id")
+
+    const response: Awaited<labels[]> = await ctx.execute()
+
+    return response.map((r) => new Client(ctx.copy()).loadLabelFromID(r.id))
+  }
+
+  /**
+   * The platform of the image.
+   */
+  platform = async (): Promise<Platform> => {
+    if (this._platform) {
+      return this._platform
+    }
+
+    const ctx = this._ctx.select("platform")
+
+    const response: Awaited<Platform> = await ctx.execute()
+
+    return response
   }
 
   /**
-   * Creates a tunnel that forwards traffic from the host to a service.
-   * @param service Service to send traffic from the tunnel.
-   * @param opts.ports Configure explicit port forwarding rules for the tunnel.
-   *
-   * If a port's frontend is unspecified or 0, a random port will be chosen by the host.
-   *
-   * If no ports are given, all of the service's ports are forwarded. If native is true, each port maps to the same port on the host. If native is false, each port maps to a random port chosen by the host.
-   *
-   * If ports are given and native is true, the ports are additive.
-   * @param opts.native Map each service port to the same port on the host, as if the service were running natively.
-   *
-   * Note: enabling may result in port conflicts.
+   * The user of the image.
    */
-  tunnel = (service: Service, opts?: HostTunnelOpts): Service => {
-    const ctx = this._ctx.select("tunnel", { service, ...opts })
-    return new Service(ctx)
+  user = async (): Promise<string> => {
+    if (this._user) {
+      return this._user
+    }
+
+    const ctx = this._ctx.select("user")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
   }
 
   /**
-   * Accesses a Unix socket on the host.
-   * @param path Location of the Unix socket (e.g., "/var/run/docker.sock").
+   * The working directory of the image.
    */
-  unixSocket = (path: string): Socket => {
-    const ctx = this._ctx.select("unixSocket", { path })
-    return new Socket(ctx)
+  workdir = async (): Promise<string> => {
+    if (this._workdir) {
+      return this._workdir
+    }
+
+    const ctx = this._ctx.select("workdir")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
   }
 }
 
@@ -6526,6 +8150,23 @@ export class Client extends BaseClient {
     return new Error(ctx)
   }
 
+  /**
+   * Explains why a call missed the cache, by comparing it to the same call in a previous run.
+   *
+   * Calls are looked up in the telemetry the engine keeps of its recent clients. The first difference is reported, in the order the calls are evaluated: the objects that calls are chained from first, then arguments, then content.
+   * @param digest Digest of the call that missed the cache, as recorded in its span (e.g., "xxh3:...").
+   * @param opts.previousDigest Digest of the call to compare to.
+   *
+   * Defaults to the most recent call of the same fields in an earlier run.
+   */
+  explainCacheMiss = (
+    digest: string,
+    opts?: ClientExplainCacheMissOpts,
+  ): CacheMissExplanation => {
+    const ctx = this._ctx.select("explainCacheMiss", { digest, ...opts })
+    return new CacheMissExplanation(ctx)
+  }
+
   /**
    * Creates a function.
    * @param name Name of the function, in its original format from the implementation language.
@@ -6573,12 +8214,28 @@ export class Client extends BaseClient {
    * Returns a file containing an http remote url content.
    * @param url HTTP url to get the content from (e.g., "https://docs.dagger.io").
    * @param opts.experimentalServiceHost A service which must be started before the URL is fetched.
+   * @param opts.checksum Expected digest of the content (e.g., "sha256:..."). The fetch fails if the content doesn't match, and is cached by the digest without contacting the server.
+   * @param opts.headers Headers to send with the request.
+   * @param opts.authToken Secret sent as a bearer token in the Authorization header.
+   * @param opts.authHeader Secret sent as the value of the Authorization header (e.g., "Basic <base64 of user:password>"). Takes precedence over authToken.
+   * @param opts.name Name of the file. Defaults to a digest of the URL.
+   * @param opts.permissions Permissions of the file (e.g., 0755). Defaults to 0600.
    */
   http = (url: string, opts?: ClientHttpOpts): File => {
     const ctx = this._ctx.select("http", { url, ...opts })
     return new File(ctx)
   }
 
+  /**
+   * Load a CacheMissExplanation from its ID.
+   */
+  loadCacheMissExplanationFromID = (
+    id: CacheMissExplanationID,
+  ): CacheMissExplanation => {
+    const ctx = this._ctx.select("loadCacheMissExplanationFromID", { id })
+    return new CacheMissExplanation(ctx)
+  }
+
   /**
    * Load a CacheVolume from its ID.
    */
@@ -6587,6 +8244,14 @@ export class Client extends BaseClient {
     return new CacheVolume(ctx)
   }
 
+  /**
+   * Load a ComposeProject from its ID.
+   */
+  loadComposeProjectFromID = (id: ComposeProjectID): ComposeProject => {
+    const ctx = this._ctx.select("loadComposeProjectFromID", { id })
+    return new ComposeProject(ctx)
+  }
+
   /**
    * Load a Container from its ID.
    */
@@ -6735,6 +8400,22 @@ export class Client extends BaseClient {
     return new GeneratedCode(ctx)
   }
 
+  /**
+   * Load a GitActor from its ID.
+   */
+  loadGitActorFromID = (id: GitActorID): GitActor => {
+    const ctx = this._ctx.select("loadGitActorFromID", { id })
+    return new GitActor(ctx)
+  }
+
+  /**
+   * Load a GitCommitInfo from its ID.
+   */
+  loadGitCommitInfoFromID = (id: GitCommitInfoID): GitCommitInfo => {
+    const ctx = this._ctx.select("loadGitCommitInfoFromID", { id })
+    return new GitCommitInfo(ctx)
+  }
+
   /**
    * Load a GitModuleSource from its ID.
    */
@@ -6759,6 +8440,14 @@ export class Client extends BaseClient {
     return new GitRepository(ctx)
   }
 
+  /**
+   * Load a GitSignature from its ID.
+   */
+  loadGitSignatureFromID = (id: GitSignatureID): GitSignature => {
+    const ctx = this._ctx.select("loadGitSignatureFromID", { id })
+    return new GitSignature(ctx)
+  }
+
   /**
    * Load a Host from its ID.
    */
@@ -6767,6 +8456,14 @@ export class Client extends BaseClient {
     return new Host(ctx)
   }
 
+  /**
+   * Load a ImageConfig from its ID.
+   */
+  loadImageConfigFromID = (id: ImageConfigID): ImageConfig => {
+    const ctx = this._ctx.select("loadImageConfigFromID", { id })
+    return new ImageConfig(ctx)
+  }
+
   /**
    * Load a InputTypeDef from its ID.
    */
@@ -6857,6 +8554,14 @@ export class Client extends BaseClient {
     return new Port(ctx)
   }
 
+  /**
+   * Load a Registry from its ID.
+   */
+  loadRegistryFromID = (id: RegistryID): Registry => {
+    const ctx = this._ctx.select("loadRegistryFromID", { id })
+    return new Registry(ctx)
+  }
+
   /**
    * Load a SDKConfig from its ID.
    */
@@ -6968,9 +8673,26 @@ export class Client extends BaseClient {
     return new ModuleSource(ctx)
   }
 
+  /**
+   * Inspects an image in a registry, without pulling its layers.
+   *
+   * Credentials set with Container.withRegistryAuth and the client's credentials are used to authenticate to the registry.
+   * @param address Address of the image (e.g., "alpine", "docker.io/library/alpine:3.20" or "alpine@sha256:...").
+   *
+   * The tag defaults to "latest".
+   */
+  registry = (address: string): Registry => {
+    const ctx = this._ctx.select("registry", { address })
+    return new Registry(ctx)
+  }
+
   /**
    * Creates a new secret.
    * @param uri The URI of the secret store
+   *
+   * The scheme selects the provider that resolves the secret on the client, e.g. env://, file://, cmd://, op://, vault://, sops://, keyring:// or aws://. Other schemes are resolved by a dagger-secret-[scheme] executable on the client's PATH.
+   *
+   * Only the syntax of the URI is checked here: a scheme the client has no provider for fails when the secret is first used.
    */
   secret = (uri: string): Secret => {
     const ctx = this._ctx.select("secret", { uri })
@@ -7020,6 +8742,130 @@ export class Client extends BaseClient {
   }
 }
 
+/**
+ * An image in a registry, inspected without pulling its layers.
+ */
+export class Registry extends BaseClient {
+  private readonly _id?: RegistryID = undefined
+  private readonly _address?: string = undefined
+  private readonly _digest?: string = undefined
+  private readonly _manifestDigest?: string = undefined
+
+  /**
+   * Constructor is used for internal usage only, do not create object from it.
+   */
+  constructor(
+    ctx?: Context,
+    _id?: RegistryID,
+    _address?: string,
+    _digest?: string,
+    _manifestDigest?: string,
+  ) {
+    super(ctx)
+
+    this._id = _id
+    this._address = _address
+    this._digest = _digest
+    this._manifestDigest = _manifestDigest
+  }
+
+  /**
+   * A unique identifier for this Registry.
+   */
+  id = async (): Promise<RegistryID> => {
+    if (this._id) {
+      return this._id
+    }
+
+    const ctx = this._ctx.select("id")
+
+    const response: Awaited<RegistryID> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The normalized reference of the image (e.g., "docker.io/library/alpine:latest").
+   */
+  address = async (): Promise<string> => {
+    if (this._address) {
+      return this._address
+    }
+
+    const ctx = this._ctx.select("address")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The config of the image for a platform.
+   * @param opts.platform Platform of the image. Defaults to the platform of the engine.
+   */
+  config = (opts?: RegistryConfigOpts): ImageConfig => {
+    const ctx = this._ctx.select("config", { ...opts })
+    return new ImageConfig(ctx)
+  }
+
+  /**
+   * The digest of the image's manifest or index.
+   */
+  digest = async (): Promise<string> => {
+    if (this._digest) {
+      return this._digest
+    }
+
+    const ctx = this._ctx.select("digest")
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The digest of the image's manifest for a platform.
+   * @param opts.platform Platform of the manifest. Defaults to the platform of the engine.
+   */
+  manifestDigest = async (
+    opts?: RegistryManifestDigestOpts,
+  ): Promise<string> => {
+    if (this._manifestDigest) {
+      return this._manifestDigest
+    }
+
+    const ctx = this._ctx.select("manifestDigest", { ...opts })
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The platforms of the image, in the order of its index.
+   *
+   * Attestations and signatures are skipped.
+   */
+  platforms = async (): Promise<Platform[]> => {
+    const ctx = this._ctx.select("platforms")
+
+    const response: Awaited<Platform[]> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The tags of the image's repository.
+   */
+  tags = async (): Promise<string[]> => {
+    const ctx = this._ctx.select("tags")
+
+    const response: Awaited<string[]> = await ctx.execute()
+
+    return response
+  }
+}
+
 /**
  * The SDK config of the module.
  */
@@ -7163,6 +9009,7 @@ export class Secret extends BaseClient {
   private readonly _id?: SecretID = undefined
   private readonly _name?: string = undefined
   private readonly _plaintext?: string = undefined
+  private readonly _refresh?: Void = undefined
   private readonly _uri?: string = undefined
 
   /**
@@ -7173,6 +9020,7 @@ export class Secret extends BaseClient {
     _id?: SecretID,
     _name?: string,
     _plaintext?: string,
+    _refresh?: Void,
     _uri?: string,
   ) {
     super(ctx)
@@ -7180,6 +9028,7 @@ export class Secret extends BaseClient {
     this._id = _id
     this._name = _name
     this._plaintext = _plaintext
+    this._refresh = _refresh
     this._uri = _uri
   }
 
@@ -7228,6 +9077,21 @@ export class Secret extends BaseClient {
     return response
   }
 
+  /**
+   * Resolves this secret again from its provider, replacing the value the client cached for the session, e.g. after it was rotated.
+   *
+   * Secrets set from a plaintext have nothing to refresh.
+   */
+  refresh = async (): Promise<void> => {
+    if (this._refresh) {
+      return
+    }
+
+    const ctx = this._ctx.select("refresh")
+
+    await ctx.execute()
+  }
+
   /**
    * The URI of this secret.
    */
@@ -7250,8 +9114,11 @@ export class Secret extends BaseClient {
 export class Service extends BaseClient {
   private readonly _id?: ServiceID = undefined
   private readonly _endpoint?: string = undefined
+  private readonly _exitCode?: number = undefined
   private readonly _hostname?: string = undefined
   private readonly _start?: ServiceID = undefined
+  private readonly _stderr?: string = undefined
+  private readonly _stdout?: string = undefined
   private readonly _stop?: ServiceID = undefined
   private readonly _up?: Void = undefined
 
@@ -7262,8 +9129,11 @@ export class Service extends BaseClient {
     ctx?: Context,
     _id?: ServiceID,
     _endpoint?: string,
+    _exitCode?: number,
     _hostname?: string,
     _start?: ServiceID,
+    _stderr?: string,
+    _stdout?: string,
     _stop?: ServiceID,
     _up?: Void,
   ) {
@@ -7271,8 +9141,11 @@ export class Service extends BaseClient {
 
     this._id = _id
     this._endpoint = _endpoint
+    this._exitCode = _exitCode
     this._hostname = _hostname
     this._start = _start
+    this._stderr = _stderr
+    this._stdout = _stdout
     this._stop = _stop
     this._up = _up
   }
@@ -7313,6 +9186,23 @@ export class Service extends BaseClient {
     return response
   }
 
+  /**
+   * The exit code the service last exited with, or null if it hasn't exited yet.
+   *
+   * If the service has been restarted, this is the exit code of the run before the current one until that one exits too. The service must have been started within the session.
+   */
+  exitCode = async (): Promise<number> => {
+    if (this._exitCode) {
+      return this._exitCode
+    }
+
+    const ctx = this._ctx.select("exitCode")
+
+    const response: Awaited<number> = await ctx.execute()
+
+    return response
+  }
+
   /**
    * Retrieves a hostname which can be used by clients to reach this container.
    */
@@ -7356,6 +9246,44 @@ export class Service extends BaseClient {
     return new Client(ctx.copy()).loadServiceFromID(response)
   }
 
+  /**
+   * The output the service has written to stderr.
+   *
+   * The service must have been started within the session. Its output remains available once it has stopped, and includes the output of every run if it has restarted.
+   * @param opts.tail Only return this many lines from the end of the output.
+   * @param opts.since Only return output written since this point in time, either as a duration (e.g., "10m") or as an RFC 3339 timestamp.
+   */
+  stderr = async (opts?: ServiceStderrOpts): Promise<string> => {
+    if (this._stderr) {
+      return this._stderr
+    }
+
+    const ctx = this._ctx.select("stderr", { ...opts })
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
+  /**
+   * The output the service has written to stdout.
+   *
+   * The service must have been started within the session. Its output remains available once it has stopped, and includes the output of every run if it has restarted.
+   * @param opts.tail Only return this many lines from the end of the output.
+   * @param opts.since Only return output written since this point in time, either as a duration (e.g., "10m") or as an RFC 3339 timestamp.
+   */
+  stdout = async (opts?: ServiceStdoutOpts): Promise<string> => {
+    if (this._stdout) {
+      return this._stdout
+    }
+
+    const ctx = this._ctx.select("stdout", { ...opts })
+
+    const response: Awaited<string> = await ctx.execute()
+
+    return response
+  }
+
   /**
    * Stop the service.
    * @param opts.kill Immediately kill the service without waiting for a graceful exit
@@ -7394,6 +9322,41 @@ export class Service extends BaseClient {
     return new Service(ctx)
   }
 
+  /**
+   * Keeps running the service's health checks once it has started.
+   *
+   * The service is killed once its health checks fail enough times in a row, and restarted if its restart policy allows it.
+   * @param opts.interval Number of seconds between each health check.
+   * @param opts.failureThreshold Number of consecutive failed health checks after which the service is killed.
+   */
+  withLivenessProbe = (opts?: ServiceWithLivenessProbeOpts): Service => {
+    const ctx = this._ctx.select("withLivenessProbe", { ...opts })
+    return new Service(ctx)
+  }
+
+  /**
+   * Configures whether the service is restarted when it exits.
+   *
+   * The service keeps the same hostname across restarts, so containers bound to it can keep reaching it.
+   * @param policy When to restart the service.
+   * @param opts.maxRetries Maximum number of times to restart the service. If unset, there is no limit.
+   */
+  withRestartPolicy = (
+    policy: ServiceRestartPolicy,
+    opts?: ServiceWithRestartPolicyOpts,
+  ): Service => {
+    const metadata = {
+      policy: { is_enum: true },
+    }
+
+    const ctx = this._ctx.select("withRestartPolicy", {
+      policy,
+      ...opts,
+      __metadata: metadata,
+    })
+    return new Service(ctx)
+  }
+
   /**
    * Call the provided function with current Service.
    *
@@ -6,6 +6,7 @@ interface ExecErrorOptions extends DaggerSDKErrorOptions {
   exitCode: number
   stdout: string
   stderr: string
+  reason?: string
 }
 
 /**
@@ -35,6 +36,12 @@ export class ExecError extends DaggerSDKError {
    */
   stderr: string
 
+  /**
+   * Why the engine terminated the command (e.g., TIMEOUT or OOM_KILLED), if
+   * it did.
+   */
+  reason?: string
+
   /**
    *  @hidden
    */
@@ -44,5 +51,6 @@ export class ExecError extends DaggerSDKError {
     this.exitCode = options.exitCode
     this.stdout = options.stdout
     this.stderr = options.stderr
+    this.reason = options.reason
   }
 }